@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// scopeWarningUnreachable is the warning code for a scope that could not be
+// reached by an AggregatedList call.
+const scopeWarningUnreachable = "UNREACHABLE"
+
+// ScopeError is the error for a scope (e.g. "zones/us-central1-b") that could
+// not be listed by AggregatedList.
+type ScopeError struct {
+	// Scope that failed, as returned by the API.
+	Scope string
+	// Code of the warning returned by the API, e.g. "UNREACHABLE".
+	Code    string
+	Message string
+}
+
+func (e *ScopeError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: %s", e.Scope, e.Code)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.Scope, e.Code, e.Message)
+}
+
+// AggregatedListError is returned by AggregatedList called with the
+// PartialSuccess option when some of the scopes could not be listed. The
+// objects from the other scopes are returned along with the error:
+//
+//	objs, err := gce.NetworkEndpointGroups().AggregatedList(ctx, filter.None, PartialSuccess())
+//	var aggErr *AggregatedListError
+//	if errors.As(err, &aggErr) {
+//		// objs has the results for all scopes except aggErr.Scopes().
+//	}
+type AggregatedListError struct {
+	Errors []*ScopeError
+}
+
+func (e *AggregatedListError) Error() string {
+	var msgs []string
+	for _, se := range e.Errors {
+		msgs = append(msgs, se.Error())
+	}
+	return fmt.Sprintf("AggregatedList: %d scopes failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the ScopeErrors for errors.Is() and errors.As().
+func (e *AggregatedListError) Unwrap() []error {
+	var ret []error
+	for _, se := range e.Errors {
+		ret = append(ret, se)
+	}
+	return ret
+}
+
+// Scopes that failed.
+func (e *AggregatedListError) Scopes() []string {
+	var ret []string
+	for _, se := range e.Errors {
+		ret = append(ret, se.Scope)
+	}
+	return ret
+}
+
+// scopeErrors collects the ScopeErrors of an AggregatedList call.
+type scopeErrors struct {
+	errs []*ScopeError
+	seen map[string]bool
+}
+
+// add a ScopeError. Only the first error for a scope is kept.
+func (s *scopeErrors) add(scope, code, message string) {
+	if s.seen == nil {
+		s.seen = map[string]bool{}
+	}
+	if s.seen[scope] {
+		return
+	}
+	s.seen[scope] = true
+	s.errs = append(s.errs, &ScopeError{Scope: scope, Code: code, Message: message})
+}
+
+// err returns an AggregatedListError or nil if there are no errors.
+func (s *scopeErrors) err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return &AggregatedListError{Errors: s.errs}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestAggregatedListPartialSuccess(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name       string
+		options    []Option
+		wantParam  string
+		wantScopes []string
+	}{
+		{
+			name: "no option",
+		},
+		{
+			name:       "PartialSuccess",
+			options:    []Option{PartialSuccess()},
+			wantParam:  "true",
+			wantScopes: []string{"regions/us-east1", "regions/europe-west1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotParam string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/projects/proj/aggregated/addresses" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				gotParam = r.URL.Query().Get("returnPartialSuccess")
+				fmt.Fprint(w, `{
+					"items": {
+						"regions/us-central1": {"addresses": [{"name": "a"}]},
+						"regions/us-west1": {"warning": {"code": "NO_RESULTS_ON_PAGE"}},
+						"regions/us-east1": {"warning": {"code": "UNREACHABLE", "message": "down"}}
+					},
+					"unreachables": ["regions/europe-west1", "regions/us-east1"]
+				}`)
+			}))
+			defer srv.Close()
+
+			s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+			if err != nil {
+				t.Fatalf("NewService() = %v", err)
+			}
+			s.GA.BasePath = srv.URL + "/"
+			g := NewGCE(s)
+
+			objs, err := g.Addresses().AggregatedList(context.Background(), filter.None, tc.options...)
+			if gotParam != tc.wantParam {
+				t.Errorf("returnPartialSuccess = %q, want %q", gotParam, tc.wantParam)
+			}
+			if l := objs["regions/us-central1"]; len(l) != 1 || l[0].Name != "a" {
+				t.Errorf("objs[regions/us-central1] = %v, want [a]", l)
+			}
+
+			if tc.wantScopes == nil {
+				if err != nil {
+					t.Errorf("AggregatedList() = %v, want nil", err)
+				}
+				return
+			}
+			var aggErr *AggregatedListError
+			if !errors.As(err, &aggErr) {
+				t.Fatalf("AggregatedList() = %v, want AggregatedListError", err)
+			}
+			// The order of the items in the map is not deterministic.
+			if diff := cmp.Diff(aggErr.Scopes(), tc.wantScopes, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("Scopes(): diff -got,+want: %s", diff)
+			}
+			var scopeErr *ScopeError
+			if !errors.As(err, &scopeErr) {
+				t.Errorf("errors.As(%v, *ScopeError) = false, want true", err)
+			}
+		})
+	}
+}
@@ -25,6 +25,18 @@ import (
 // below for the properties being checked.
 func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
 	acc := newAcceptorFuncs()
+	acc.onBasicF = func(p Path, v reflect.Value) (bool, error) {
+		values, ok := traits.enumValues(p)
+		if !ok || v.Kind() != reflect.String || v.String() == "" {
+			return true, nil
+		}
+		for _, ev := range values {
+			if v.String() == ev {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("%s has value %q, which is not one of %v", p, v.String(), values)
+	}
 	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
 		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
 			return false, nil
@@ -32,11 +44,16 @@ func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
 
 		acc, err := newMetafieldAccessor(v)
 		if err != nil {
-			return false, fmt.Errorf("checkPostAccess %v: %w", p, err)
+			// Proto messages do not have metafields, a field that is set
+			// to a zero value is a non-nil pointer.
+			if !isProtoMessageT(v.Type()) {
+				return false, fmt.Errorf("checkPostAccess %v: %w", p, err)
+			}
+			acc = &metafieldAccessor{}
 		}
 		for i := 0; i < v.NumField(); i++ {
 			ft := v.Type().Field(i)
-			if ft.Name == "NullFields" || ft.Name == "ForceSendFields" {
+			if ft.Name == "NullFields" || ft.Name == "ForceSendFields" || skipField(v.Type(), ft) {
 				continue
 			}
 			fType := traits.fieldType(p.Field(ft.Name))
@@ -86,12 +103,15 @@ func checkNoCycles(p Path, t reflect.Type, seen []string) error {
 		typeName := fmt.Sprintf("%s/%s", t.PkgPath(), t.Name())
 		for _, seenTypeName := range seen {
 			if typeName == seenTypeName {
-				return fmt.Errorf("recursive type found at %s: %s", p, typeName)
+				return newError(ErrorCodeUnsupportedKind, p, "recursive type found at %s: %s", p, typeName)
 			}
 		}
 		// Add this struct type to the list of types seen on this path.
 		seen = append(seen, fmt.Sprintf("%s/%s", t.PkgPath(), t.Name()))
 		for i := 0; i < t.NumField(); i++ {
+			if skipField(t, t.Field(i)) {
+				continue
+			}
 			if err := checkNoCycles(p.Field(t.Field(i).Name), t.Field(i).Type, seen); err != nil {
 				return err
 			}
@@ -109,13 +129,16 @@ func checkResourceTypes(p Path, t reflect.Type) error {
 	}
 	switch t.Kind() {
 	case reflect.Pointer:
-		if err := checkResourceTypes(p, t.Elem()); err != nil {
+		if err := checkResourceTypes(p.Pointer(), t.Elem()); err != nil {
 			return err
 		}
 	case reflect.Struct:
 		// struct => {all fields are valid_type}
 		for i := 0; i < t.NumField(); i++ {
 			tf := t.Field(i)
+			if skipField(t, tf) {
+				continue
+			}
 			if err := checkResourceTypes(p.Field(tf.Name), tf.Type); err != nil {
 				return err
 			}
@@ -128,14 +151,15 @@ func checkResourceTypes(p Path, t reflect.Type) error {
 	case reflect.Map:
 		// map => key is basic type; value is valid_type
 		if !isBasicT(t.Key()) {
-			return fmt.Errorf("map key must be basic type %s: %v", p.Pointer(), t)
+			return newError(ErrorCodeUnsupportedKind, p, "map key must be basic type %s: %v", p, t)
 		}
 		// Supported value types.
 		if !isBasicT(t.Elem()) {
-			switch t.Elem().Kind() {
-			case reflect.Slice, reflect.Struct:
+			switch vt := t.Elem(); {
+			case vt.Kind() == reflect.Slice, vt.Kind() == reflect.Struct:
+			case vt.Kind() == reflect.Pointer && vt.Elem().Kind() == reflect.Struct:
 			default:
-				return fmt.Errorf("unsupported value type %s: %v", p, t)
+				return newError(ErrorCodeUnsupportedKind, p, "unsupported value type %s: %v", p, t)
 			}
 			// Use "x" as the placeholder for the map key in the Path for debugging
 			// output purposes.
@@ -144,7 +168,7 @@ func checkResourceTypes(p Path, t reflect.Type) error {
 			}
 		}
 	default:
-		return fmt.Errorf("unsupported type %s: %v", p, t)
+		return newError(ErrorCodeUnsupportedKind, p, "unsupported type %s: %v", p, t)
 	}
 	return nil
 }
@@ -159,13 +183,13 @@ func checkSchema(t reflect.Type) error {
 	}
 	// Check that common fields are present.
 	if t.Kind() != reflect.Pointer {
-		return fmt.Errorf("object is not a pointer (%s)", t)
+		return newError(ErrorCodeTypeMismatch, Path{}, "object is not a pointer (%s)", t)
 	}
 	st := t.Elem()
 	for _, fn := range []string{"Name", "SelfLink"} {
 		f, ok := st.FieldByName(fn)
-		if !ok || f.Type.Kind() != reflect.String {
-			return fmt.Errorf("object has missing or invalid type for the %s field", fn)
+		if !ok || !isStringOrPtrToStringT(f.Type) {
+			return newError(ErrorCodeMissingField, Path{}.Pointer().Field(fn), "object has missing or invalid type for the %s field", fn)
 		}
 	}
 
@@ -183,7 +207,7 @@ func CheckStructuralSubset(from, to reflect.Type) error {
 // a subset of To type. Path parameter is used for better error reporting.
 func checkStructuralSubsetImpl(p Path, from, to reflect.Type) error {
 	if from.Kind() != to.Kind() {
-		return fmt.Errorf("%s has different type: %v != %v", p, from.Kind(), to.Kind())
+		return newError(ErrorCodeTypeMismatch, p, "%s has different type: %v != %v", p, from.Kind(), to.Kind())
 	}
 	if isBasicT(from) {
 		return nil
@@ -195,9 +219,12 @@ func checkStructuralSubsetImpl(p Path, from, to reflect.Type) error {
 	case reflect.Struct:
 		for i := 0; i < from.NumField(); i++ {
 			af := from.Field(i)
+			if skipField(from, af) {
+				continue
+			}
 			bf, exist := to.FieldByName(af.Name)
 			if !exist {
-				return fmt.Errorf("%s: type %T does not have field %v", p.String(), to, af.Name)
+				return newError(ErrorCodeMissingField, p.Field(af.Name), "%s: type %T does not have field %v", p.String(), to, af.Name)
 			}
 			if err := checkStructuralSubsetImpl(p.Field(af.Name), af.Type, bf.Type); err != nil {
 				return err
@@ -216,5 +243,5 @@ func checkStructuralSubsetImpl(p Path, from, to reflect.Type) error {
 		}
 		return checkStructuralSubsetImpl(path, from.Elem(), to.Elem())
 	}
-	return fmt.Errorf("%s Unsupported type %v", p.String(), from.Kind())
+	return newError(ErrorCodeUnsupportedKind, p, "%s Unsupported type %v", p.String(), from.Kind())
 }
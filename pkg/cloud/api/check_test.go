@@ -35,6 +35,8 @@ func TestCheckFieldsAreSet(t *testing.T) {
 		A               int
 		B               int
 		S               *sti
+		E               string
+		LE              []string
 		NullFields      []string
 		ForceSendFields []string
 	}
@@ -42,6 +44,10 @@ func TestCheckFieldsAreSet(t *testing.T) {
 	ft := NewFieldTraits()
 	ft.NonZeroValue(Path{}.Pointer().Field("A"))
 
+	ftEnum := ft.Clone()
+	ftEnum.Enum(Path{}.Pointer().Field("E"), "X", "Y")
+	ftEnum.Enum(Path{}.Pointer().Field("LE").AnySliceIndex(), "X")
+
 	ftSystemField := ft.Clone()
 	ftSystemField.System(Path{}.Pointer().Field("B"))
 
@@ -130,6 +136,28 @@ func TestCheckFieldsAreSet(t *testing.T) {
 			},
 			ft:      ft,
 			wantErr: true},
+		{
+			name: "enum",
+			in:   &st{A: 1, E: "Y", LE: []string{"X", "X"}},
+			ft:   ftEnum,
+		},
+		{
+			name: "enum empty value",
+			in:   &st{A: 1},
+			ft:   ftEnum,
+		},
+		{
+			name:    "enum invalid value",
+			in:      &st{A: 1, E: "Z"},
+			ft:      ftEnum,
+			wantErr: true,
+		},
+		{
+			name:    "enum invalid value in slice",
+			in:      &st{A: 1, LE: []string{"X", "Y"}},
+			ft:      ftEnum,
+			wantErr: true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := checkPostAccess(tc.ft, reflect.ValueOf(tc.in))
@@ -190,6 +218,8 @@ func TestCheckResourceTypes(t *testing.T) {
 		M   map[string]int
 		ST  innerSt
 		PST *innerSt
+		MST map[string]innerSt
+		MPS map[int]*innerSt
 	}
 	type invalidSt1 struct {
 		M map[innerSt]int
@@ -198,7 +228,7 @@ func TestCheckResourceTypes(t *testing.T) {
 		C chan int
 	}
 	type invalidSt3 struct {
-		M map[int]*innerSt
+		M map[int]*int
 	}
 
 	for _, tc := range []struct {
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"go/token"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// CmpOptions returns go-cmp options that compare resource structs using the
+// same semantics as Equal():
+//
+//   - OutputOnly and System fields are ignored.
+//   - The metafields (NullFields, ForceSendFields) are ignored.
+//   - nil and empty slices and maps are equal.
+//
+// In addition, slices marked with FieldTraits.Unordered() are compared
+// without regard to the order of their elements.
+//
+// traits may be nil. Version-specific traits are skipped; use
+// CmpOptionsFor() or FieldTraits.ForVersion() to resolve these first.
+//
+// Example:
+//
+//	if diff := cmp.Diff(want, got, api.CmpOptions(traits)); diff != "" { ... }
+func CmpOptions(traits *FieldTraits) cmp.Options {
+	if traits == nil {
+		traits = &FieldTraits{}
+	}
+	ignore := func(cp cmp.Path) bool {
+		sf, ok := cp.Last().(cmp.StructField)
+		if !ok {
+			return false
+		}
+		switch name := sf.Name(); {
+		case !token.IsExported(name), name == "NullFields", name == "ForceSendFields":
+			return true
+		}
+		switch traits.fieldType(cmpPathToPath(cp)) {
+		case FieldTypeOutputOnly, FieldTypeSystem:
+			return true
+		}
+		return false
+	}
+	unordered := func(cp cmp.Path) bool {
+		if len(traits.unordered) == 0 {
+			return false
+		}
+		if _, ok := cp.Last().(cmp.Transform); ok {
+			return false
+		}
+		return cmpPathToPath(cp).MatchAny(traits.unordered...)
+	}
+
+	return cmp.Options{
+		cmp.FilterPath(ignore, cmp.Ignore()),
+		cmp.FilterPath(unordered, cmpopts.SortSlices(lessJSON)),
+		cmpopts.EquateEmpty(),
+	}
+}
+
+// CmpOptionsFor returns the CmpOptions() for version v of the TypeTrait.
+func CmpOptionsFor[GA any, Alpha any, Beta any](tt TypeTrait[GA, Alpha, Beta], v meta.Version) cmp.Options {
+	return CmpOptions(fieldTraits(tt, v))
+}
+
+// cmpPathToPath converts the cmp.Path to a Path. Transforms (e.g. the sorting
+// of an unordered slice) are skipped.
+func cmpPathToPath(cp cmp.Path) Path {
+	var p Path
+	// The first step is the root value.
+	for _, step := range cp[1:] {
+		switch s := step.(type) {
+		case cmp.StructField:
+			p = p.Field(s.Name())
+		case cmp.Indirect:
+			p = p.Pointer()
+		case cmp.SliceIndex:
+			i := s.Key()
+			if i < 0 {
+				// The element only exists on one side.
+				ix, iy := s.SplitKeys()
+				i = max(ix, iy)
+			}
+			p = p.Index(i)
+		case cmp.MapIndex:
+			p = p.MapIndex(s.Key().Interface())
+		}
+	}
+	return p
+}
+
+// lessJSON orders the elements of an unordered slice by their JSON encoding.
+// This gives a stable order for any element type.
+func lessJSON(a, b any) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return string(aj) < string(bj)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCmpOptions(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		A  string
+		ID int
+	}
+	type st struct {
+		S               string
+		Out             string
+		L               []string
+		Set             []string
+		SetSt           []*inner
+		M               map[string]string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	traits := NewFieldTraits()
+	traits.OutputOnly(Path{}.Pointer().Field("Out"))
+	traits.OutputOnly(Path{}.Pointer().Field("SetSt").AnySliceIndex().Pointer().Field("ID"))
+	traits.Unordered(Path{}.Pointer().Field("Set"))
+	traits.Unordered(Path{}.Pointer().Field("SetSt"))
+
+	for _, tc := range []struct {
+		name string
+		a, b *st
+		want bool
+		// orderOnly is set if the values only differ in the order of an
+		// Unordered field, which Equal() does not take into account.
+		orderOnly bool
+	}{
+		{
+			name: "equal",
+			a:    &st{S: "a"},
+			b:    &st{S: "a"},
+			want: true,
+		},
+		{
+			name: "different",
+			a:    &st{S: "a"},
+			b:    &st{S: "b"},
+		},
+		{
+			name: "output only ignored",
+			a:    &st{Out: "a"},
+			b:    &st{Out: "b"},
+			want: true,
+		},
+		{
+			name: "metafields ignored",
+			a:    &st{NullFields: []string{"S"}},
+			b:    &st{ForceSendFields: []string{"S"}},
+			want: true,
+		},
+		{
+			name: "nil and empty",
+			a:    &st{L: []string{}, M: map[string]string{}},
+			b:    &st{},
+			want: true,
+		},
+		{
+			name: "ordered slice",
+			a:    &st{L: []string{"a", "b"}},
+			b:    &st{L: []string{"b", "a"}},
+		},
+		{
+			name:      "unordered slice",
+			a:         &st{Set: []string{"a", "b"}},
+			b:         &st{Set: []string{"b", "a"}},
+			want:      true,
+			orderOnly: true,
+		},
+		{
+			name: "unordered slice different",
+			a:    &st{Set: []string{"a", "b"}},
+			b:    &st{Set: []string{"b", "c"}},
+		},
+		{
+			name:      "unordered slice of structs",
+			a:         &st{SetSt: []*inner{{A: "x"}, {A: "y"}}},
+			b:         &st{SetSt: []*inner{{A: "y"}, {A: "x"}}},
+			want:      true,
+			orderOnly: true,
+		},
+		{
+			name: "output only in unordered slice",
+			a:    &st{SetSt: []*inner{{A: "x", ID: 1}}},
+			b:    &st{SetSt: []*inner{{A: "x", ID: 2}}},
+			want: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := cmp.Equal(tc.a, tc.b, CmpOptions(traits))
+			if got != tc.want {
+				t.Errorf("cmp.Equal() = %t, want %t; diff = %s", got, tc.want, cmp.Diff(tc.a, tc.b, CmpOptions(traits)))
+			}
+			// CmpOptions() should agree with Equal() for ordered fields.
+			if !tc.orderOnly {
+				eq, err := Equal(tc.a, tc.b, traits)
+				if err != nil {
+					t.Fatalf("Equal() = %v", err)
+				}
+				if eq != got {
+					t.Errorf("Equal() = %t, cmp.Equal() = %t", eq, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCmpOptionsFor(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		S   string
+		Out string
+	}
+	traits := NewFieldTraits()
+	traits.VersionTrait(Path{}.Pointer().Field("Out"), FieldTypeOutputOnly, meta.VersionAlpha)
+	tt := &TypeTraitFuncs[st, st, st]{
+		FieldTraitsF: func(meta.Version) *FieldTraits { return traits },
+	}
+
+	a, b := &st{Out: "a"}, &st{Out: "b"}
+	if cmp.Equal(a, b, CmpOptionsFor[st, st, st](tt, meta.VersionGA)) {
+		t.Errorf("cmp.Equal(GA) = true, want false")
+	}
+	if !cmp.Equal(a, b, CmpOptionsFor[st, st, st](tt, meta.VersionAlpha)) {
+		t.Errorf("cmp.Equal(Alpha) = false, want true")
+	}
+}
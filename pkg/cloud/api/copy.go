@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 )
 
 type missingFieldOnCopy struct {
@@ -35,6 +36,18 @@ func copierLogS(f func(msg string, kv ...any)) copierOption {
 	return func(c *copier) { c.logSFn = f }
 }
 
+// copierFieldConversions configures the copier to use the conversions for the
+// matching fields instead of copying them by value.
+func copierFieldConversions(convs []FieldConversion) copierOption {
+	return func(c *copier) { c.conversions = append(c.conversions, convs...) }
+}
+
+// copierAllowMissing configures the copier to not report the fields matching
+// the paths (or their children) as missing.
+func copierAllowMissing(paths []Path) copierOption {
+	return func(c *copier) { c.allowMissing = append(c.allowMissing, paths...) }
+}
+
 func newCopier(opts ...copierOption) *copier {
 	c := &copier{}
 	for _, o := range opts {
@@ -47,6 +60,10 @@ type copier struct {
 	// logSFn is an optional structured log function, matching the
 	// signature from klog/v2.
 	logSFn func(msg string, kv ...any)
+	// conversions for specific fields.
+	conversions []FieldConversion
+	// allowMissing are the fields that are not reported as missing.
+	allowMissing []Path
 
 	missing []missingFieldOnCopy
 }
@@ -58,6 +75,32 @@ func (c *copier) logS(msg string, kv ...any) {
 	c.logSFn(msg, kv...)
 }
 
+// addMissing records the field at p as missing unless it is allowed by
+// allowMissing. field is the path used to match allowMissing.
+func (c *copier) addMissing(p, field Path, v any) {
+	for _, allowed := range c.allowMissing {
+		if field.HasPrefix(allowed) {
+			c.logS("copier allowed missing field", "path", p)
+			return
+		}
+	}
+	c.missing = append(c.missing, missingFieldOnCopy{
+		Path:  append(Path{}, p...),
+		Value: v,
+	})
+}
+
+// conversion returns the FieldConversion for the field at p, nil if there is
+// none.
+func (c *copier) conversion(p Path) *FieldConversion {
+	for i := range c.conversions {
+		if c.conversions[i].Path.Match(p) {
+			return &c.conversions[i]
+		}
+	}
+	return nil
+}
+
 func (c *copier) do(dest, src reflect.Value) error {
 	return c.doValues(Path{}, dest, src)
 }
@@ -75,12 +118,12 @@ func (c *copier) doValues(p Path, dest, src reflect.Value) error {
 	case src.Type().Kind() == reflect.Map && dest.Type().Kind() == reflect.Map:
 		return c.doMap(p, dest, src)
 	}
-	return fmt.Errorf("copyValues: incompatible types: src %T, dest %T", src.Interface(), dest.Interface())
+	return newError(ErrorCodeTypeMismatch, p, "copyValues: incompatible types: src %T, dest %T", src.Interface(), dest.Interface())
 }
 
 func (c *copier) doBasic(p Path, dest, src reflect.Value) error {
 	if !isBasicV(dest) || !isBasicV(src) || dest.Type().Kind() != src.Type().Kind() {
-		return fmt.Errorf("copyBasic: mismatched types: src %s, dest %s", src.Type(), dest.Type())
+		return newError(ErrorCodeTypeMismatch, p, "copyBasic: mismatched types: src %s, dest %s", src.Type(), dest.Type())
 	}
 	if !dest.CanSet() {
 		return fmt.Errorf("cannot set dest (%s)", p)
@@ -92,7 +135,7 @@ func (c *copier) doBasic(p Path, dest, src reflect.Value) error {
 
 func (c *copier) doPointer(p Path, dest, src reflect.Value) error {
 	if dest.Type().Kind() != reflect.Pointer || src.Type().Kind() != reflect.Pointer {
-		return fmt.Errorf("copyPointer: invalid types: src %T, dest %T", src.Interface(), dest.Interface())
+		return newError(ErrorCodeTypeMismatch, p, "copyPointer: invalid types: src %T, dest %T", src.Interface(), dest.Interface())
 	}
 	if src.IsZero() {
 		c.logS("copyPointer zero", "path", p)
@@ -114,7 +157,7 @@ func (c *copier) doPointer(p Path, dest, src reflect.Value) error {
 
 func (c *copier) doSlice(p Path, dest, src reflect.Value) error {
 	if dest.Type().Kind() != reflect.Slice || src.Type().Kind() != reflect.Slice {
-		return fmt.Errorf("copySlice: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
+		return newError(ErrorCodeTypeMismatch, p, "copySlice: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
 	if src.IsZero() {
 		dest.Set(reflect.Zero(dest.Type()))
@@ -140,15 +183,27 @@ func (c *copier) doSlice(p Path, dest, src reflect.Value) error {
 
 func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 	if dest.Kind() != reflect.Struct || src.Kind() != reflect.Struct {
-		return fmt.Errorf("copyStruct: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
+		return newError(ErrorCodeTypeMismatch, p, "copyStruct: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
 	// Copy over fields that are present in both src and dest. Fields in dest
 	// that don't exist in src are left alone.
-	for i := 0; i < src.Type().NumField(); i++ {
-		srcFieldT := src.Type().Field(i)
-		fieldName := srcFieldT.Name
-		destField := dest.FieldByName(fieldName)
-		_, ok := dest.Type().FieldByName(fieldName)
+	for _, fm := range structFieldMappings(dest.Type(), src.Type()) {
+		i := fm.srcIndex
+		fieldName := fm.name
+		ok := fm.destIndex != nil
+		var destField reflect.Value
+		if ok {
+			destField = dest.FieldByIndex(fm.destIndex)
+		}
+		fp := p.Field(fieldName)
+
+		if conv := c.conversion(fp); conv != nil {
+			c.logS("copyStruct convert", "path", p, "fieldName", fieldName)
+			if err := conv.Convert(dest, src.Field(i)); err != nil {
+				return fmt.Errorf("copyStruct: convert %s: %w", fp, err)
+			}
+			continue
+		}
 
 		if !ok {
 			// Only non-zero fields are counted towards
@@ -156,10 +211,7 @@ func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 			// in NullFields or ForceSendFields are
 			// handled by copyMetaFields() below.
 			if !src.Field(i).IsZero() {
-				c.missing = append(c.missing, missingFieldOnCopy{
-					Path:  p.Field(fieldName),
-					Value: src.Field(i).Interface(),
-				})
+				c.addMissing(fp, fp, src.Field(i).Interface())
 				c.logS("copyStruct missing field", "path", p, "fieldName", fieldName)
 			}
 			continue
@@ -171,7 +223,7 @@ func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 		}
 
 		if fieldName == "NullFields" || fieldName == "ForceSendFields" {
-			err := c.doMetaFields(p.Field(fieldName), destField, src.Field(i), dest, src)
+			err := c.doMetaFields(fp, destField, src.Field(i), dest, src)
 			if err != nil {
 				return err
 			}
@@ -179,16 +231,59 @@ func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 		}
 
 		c.logS("copyStruct", "path", p, "fieldName", fieldName)
-		if err := c.doValues(p.Field(fieldName), destField, src.Field(i)); err != nil {
+		if err := c.doValues(fp, destField, src.Field(i)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// fieldMapping maps a field in the src struct to the field with the same name
+// in the dest struct.
+type fieldMapping struct {
+	name     string
+	srcIndex int
+	// destIndex is the index of the field in dest (see
+	// reflect.Value.FieldByIndex). This is nil if the field does not exist
+	// in dest.
+	destIndex []int
+}
+
+type structTypePair struct {
+	dest, src reflect.Type
+}
+
+// fieldMappingCache caches the []fieldMapping for a structTypePair. Looking up
+// fields by name is expensive for the large API structs (e.g.
+// BackendService) and the mapping only depends on the types.
+var fieldMappingCache sync.Map
+
+// structFieldMappings returns the mapping of the fields in src to the fields
+// in dest. Fields that should not be copied (see skipField()) are omitted.
+func structFieldMappings(dest, src reflect.Type) []fieldMapping {
+	key := structTypePair{dest: dest, src: src}
+	if ret, ok := fieldMappingCache.Load(key); ok {
+		return ret.([]fieldMapping)
+	}
+	var ret []fieldMapping
+	for i := 0; i < src.NumField(); i++ {
+		srcFieldT := src.Field(i)
+		if skipField(src, srcFieldT) {
+			continue
+		}
+		fm := fieldMapping{name: srcFieldT.Name, srcIndex: i}
+		if destFieldT, ok := dest.FieldByName(srcFieldT.Name); ok {
+			fm.destIndex = destFieldT.Index
+		}
+		ret = append(ret, fm)
+	}
+	fieldMappingCache.Store(key, ret)
+	return ret
+}
+
 func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 	if dest.Type().Kind() != reflect.Map || src.Type().Kind() != reflect.Map {
-		return fmt.Errorf("copyMap: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
+		return newError(ErrorCodeTypeMismatch, p, "copyMap: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
 
 	if src.IsZero() {
@@ -206,13 +301,13 @@ func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 	svt := src.Type().Elem()
 
 	if !basicT(dkt) || !basicT(skt) {
-		return fmt.Errorf("copyMap: keys are not basic types (dest: %T, src: %T)", dest.Interface(), src.Interface())
+		return newError(ErrorCodeUnsupportedKind, p, "copyMap: keys are not basic types (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
 	if dkt.Kind() != skt.Kind() {
-		return fmt.Errorf("copyMap: keys do not match (dest: %T, src: %T)", dest.Interface(), src.Interface())
+		return newError(ErrorCodeTypeMismatch, p, "copyMap: keys do not match (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
 	if dvt.Kind() != svt.Kind() {
-		return fmt.Errorf("copyMap: values type must match (dest: %T, src: %T)", dest.Interface(), src.Interface())
+		return newError(ErrorCodeTypeMismatch, p, "copyMap: values type must match (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
 
 	newMap := reflect.MakeMapWithSize(dest.Type(), src.Len())
@@ -229,6 +324,12 @@ func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 				return err
 			}
 			newMap.SetMapIndex(sk, pdv.Elem())
+		case svt.Kind() == reflect.Pointer && svt.Elem().Kind() == reflect.Struct:
+			dv := reflect.New(dvt).Elem()
+			if err := c.doValues(p.MapIndex(sk.Interface()), dv, sv); err != nil {
+				return err
+			}
+			newMap.SetMapIndex(sk, dv)
 		case svt.Kind() == reflect.Slice:
 			dv := reflect.New(dvt).Elem()
 			dv.Grow(sv.Len())
@@ -237,7 +338,7 @@ func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 			}
 			newMap.SetMapIndex(sk, dv)
 		default:
-			return fmt.Errorf("unsupported map types (dest: %T, src: %T)", dest.Interface(), src.Interface())
+			return newError(ErrorCodeUnsupportedKind, p, "unsupported map types (dest: %T, src: %T)", dest.Interface(), src.Interface())
 		}
 	}
 
@@ -268,40 +369,63 @@ func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 //	// BetaField is marked as "missing" if the user uses ToGA().
 //	Obj.ForceSendFields == []string{"Field"}
 //	ObjBeta.ForceSendFields == []string{"Field", "BetaField"}
+//
+// The entries in the src are authoritative for fields that exist in both
+// versions: entries in dest for these fields that are no longer present in src
+// are removed. This keeps an intentionally zero field (e.g. "Field" above)
+// from being dropped or left stale after a round trip between versions.
+//
+// NullFields entries that refer to a map key ("Labels.key") are handled the
+// same way as an entry for the map field itself.
+//
+// Fields that have a FieldConversion are not copied; the conversion is
+// responsible for setting the metafields in the destination.
 func (c *copier) doMetaFields(p Path, destField, srcField, destStruct, srcStruct reflect.Value) error {
 	if !isSliceOfStringV(destField) || !isSliceOfStringV(srcField) {
-		return fmt.Errorf("copyMetaFields: invalid type (destField: %T, srcField: %T)", destField.Interface(), srcField.Interface())
+		return newError(ErrorCodeTypeMismatch, p, "copyMetaFields: invalid type (destField: %T, srcField: %T)", destField.Interface(), srcField.Interface())
 	}
 
-	destMetaFields := destField.Interface().([]string)
-
-	// MetaFields already present in the destination list.
+	// Keep the entries in dest that refer to fields that are not in src, or
+	// were set by a FieldConversion.
+	var destMetaFields []string
 	exists := map[string]bool{}
-	for _, v := range destMetaFields {
+	for _, v := range destField.Interface().([]string) {
+		fn, _ := metafieldName(v)
+		_, srcHasField := srcStruct.Type().FieldByName(fn)
+		if srcHasField && c.conversion(p.parent().Field(fn)) == nil {
+			continue
+		}
+		destMetaFields = append(destMetaFields, v)
 		exists[v] = true
 	}
 
 	c.logS("copyMetaFields dest", "path", p, "destFields", exists)
 
-	for _, fn := range srcField.Interface().([]string) {
-		if _, ok := srcStruct.Type().FieldByName(fn); !ok {
-			return fmt.Errorf("copyMetaFields: %s refers to field %q that doesn't exist (type %T)", p, fn, srcStruct.Interface())
+	for _, v := range srcField.Interface().([]string) {
+		fn, hasKey := metafieldName(v)
+		srcFieldT, ok := srcStruct.Type().FieldByName(fn)
+		if !ok {
+			return newError(ErrorCodeMissingField, p.parent().Field(fn), "copyMetaFields: %s refers to field %q that doesn't exist (type %T)", p, fn, srcStruct.Interface())
+		}
+		if hasKey && srcFieldT.Type.Kind() != reflect.Map {
+			return newError(ErrorCodeTypeMismatch, p.parent().Field(fn), "copyMetaFields: %s refers to key in field %q that is not a map (type %T)", p, fn, srcStruct.Interface())
+		}
+		if c.conversion(p.parent().Field(fn)) != nil {
+			continue
 		}
 		_, destHasField := destStruct.Type().FieldByName(fn)
 		// We only need to add to destMetaFields if it exists
 		// in the dest struct and hasn't already been added to
 		// the list.
-		if destHasField && !exists[fn] {
-			destMetaFields = append(destMetaFields, fn)
-			c.logS("copyMetaFields add", "path", p, "fieldName", fn)
+		if destHasField && !exists[v] {
+			destMetaFields = append(destMetaFields, v)
+			exists[v] = true
+			c.logS("copyMetaFields add", "path", p, "fieldName", v)
 		} else if !destHasField {
 			// Record that the metafield referenced a
 			// field that didn't exist on the dest
 			// version.
-			c.missing = append(c.missing, missingFieldOnCopy{
-				Path:  p.Field(fn),
-				Value: srcField.Interface(),
-			})
+			c.addMissing(p.Field(fn), p.parent().Field(fn), srcField.Interface())
 			c.logS("copyMetaFields missing field", "path", p, "fieldName", fn)
 		}
 	}
@@ -311,6 +435,13 @@ func (c *copier) doMetaFields(p Path, destField, srcField, destStruct, srcStruct
 
 	}
 
+	if len(destMetaFields) == 0 {
+		// Preserve nil vs empty list in dest if nothing changed.
+		if destField.Len() > 0 {
+			destField.Set(reflect.Zero(destField.Type()))
+		}
+		return nil
+	}
 	sort.Strings(destMetaFields)
 	destField.Set(reflect.ValueOf(destMetaFields))
 
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	"google.golang.org/api/compute/v1"
 )
 
 func testCopier(t *testing.T) *copier {
@@ -414,6 +416,12 @@ func TestCopyMap(t *testing.T) {
 			src:  v(map[int]st{1: {I: 20}}),
 			want: map[int]st{1: {I: 20}},
 		},
+		{
+			name: "copy pointer to struct",
+			dest: v(&map[string]*st{}).Elem(),
+			src:  v(map[string]*st{"a": {I: 20}, "b": nil}),
+			want: map[string]*st{"a": {I: 20}, "b": nil},
+		},
 		{
 			name: "nil map",
 			dest: v(&map[string]int{}).Elem(),
@@ -462,12 +470,58 @@ func TestCopyMap(t *testing.T) {
 	}
 }
 
+func TestCopyMapOfStructMissingFields(t *testing.T) {
+	t.Parallel()
+
+	type s1 struct {
+		A int
+		B int
+	}
+	type s2 struct {
+		A int
+	}
+	type st1 struct {
+		M  map[string]s1
+		MP map[string]*s1
+	}
+	type st2 struct {
+		M  map[string]s2
+		MP map[string]*s2
+	}
+
+	src := &st1{
+		M:  map[string]s1{"x": {A: 1}, "y": {A: 2, B: 3}},
+		MP: map[string]*s1{"z": {B: 4}},
+	}
+	var dest st2
+	c := testCopier(t)
+	if err := c.do(reflect.ValueOf(&dest), reflect.ValueOf(src)); err != nil {
+		t.Fatalf("c.do() = %v, want nil", err)
+	}
+	want := st2{
+		M:  map[string]s2{"x": {A: 1}, "y": {A: 2}},
+		MP: map[string]*s2{"z": {}},
+	}
+	if diff := cmp.Diff(dest, want); diff != "" {
+		t.Errorf("c.do(): -got,+want: %s", diff)
+	}
+	var gotMissing []string
+	for _, m := range c.missing {
+		gotMissing = append(gotMissing, m.Path.String())
+	}
+	wantMissing := []string{"*.M:y.B", "*.MP:z*.B"}
+	if diff := cmp.Diff(gotMissing, wantMissing); diff != "" {
+		t.Errorf("c.missing: -got,+want: %s", diff)
+	}
+}
+
 func TestCopyMetaFields(t *testing.T) {
 	t.Parallel()
 
 	type st1 struct {
 		SomeField    string
 		St1OnlyField string
+		Labels       map[string]string
 
 		NullFields      []string
 		ForceSendFields []string
@@ -475,6 +529,7 @@ func TestCopyMetaFields(t *testing.T) {
 	type st2 struct {
 		SomeField    string
 		St2OnlyField string
+		Labels       map[string]string
 
 		NullFields      []string
 		ForceSendFields []string
@@ -532,6 +587,33 @@ func TestCopyMetaFields(t *testing.T) {
 			dest: st2{ForceSendFields: []string{"St2OnlyField"}},
 			want: st2{ForceSendFields: []string{"SomeField", "St2OnlyField"}},
 		},
+		{
+			name: "remove fields no longer in src",
+			fn:   "ForceSendFields",
+			src:  st1{},
+			dest: st2{ForceSendFields: []string{"SomeField", "St2OnlyField"}},
+			want: st2{ForceSendFields: []string{"St2OnlyField"}},
+		},
+		{
+			name: "remove all fields",
+			fn:   "NullFields",
+			src:  st1{},
+			dest: st2{NullFields: []string{"SomeField"}},
+			want: st2{},
+		},
+		{
+			name: "map key",
+			fn:   "NullFields",
+			src:  st1{NullFields: []string{"Labels.a"}},
+			dest: st2{NullFields: []string{"Labels.b", "St2OnlyField"}},
+			want: st2{NullFields: []string{"Labels.a", "St2OnlyField"}},
+		},
+		{
+			name:    "key for field that is not a map",
+			fn:      "NullFields",
+			src:     st1{NullFields: []string{"SomeField.a"}},
+			wantErr: true,
+		},
 	} {
 		t.Run(tc.name, func(*testing.T) {
 			srcV := reflect.ValueOf(&tc.src).Elem()
@@ -554,3 +636,104 @@ func TestCopyMetaFields(t *testing.T) {
 		})
 	}
 }
+
+func TestCopyFieldConversions(t *testing.T) {
+	t.Parallel()
+
+	type elem1 struct {
+		Old             string
+		ForceSendFields []string
+	}
+	type elem2 struct {
+		New             string
+		ForceSendFields []string
+	}
+	type st1 struct {
+		L []elem1
+	}
+	type st2 struct {
+		L []elem2
+	}
+
+	rename := FieldConversion{
+		Path: Path{}.Pointer().Field("L").AnySliceIndex().Field("Old"),
+		Convert: func(dest, src reflect.Value) error {
+			dest.FieldByName("New").Set(src)
+			if src.IsZero() {
+				fsf := dest.FieldByName("ForceSendFields")
+				fsf.Set(reflect.Append(fsf, reflect.ValueOf("New")))
+			}
+			return nil
+		},
+	}
+
+	src := &st1{L: []elem1{{Old: "a"}, {ForceSendFields: []string{"Old"}}}}
+	var dest st2
+	c := newCopier(copierFieldConversions([]FieldConversion{rename}))
+	if err := c.do(reflect.ValueOf(&dest), reflect.ValueOf(src)); err != nil {
+		t.Fatalf("c.do() = %v, want nil", err)
+	}
+	want := st2{L: []elem2{{New: "a"}, {ForceSendFields: []string{"New"}}}}
+	if diff := cmp.Diff(dest, want); diff != "" {
+		t.Errorf("c.do(): -got,+want: %s", diff)
+	}
+	if len(c.missing) != 0 {
+		t.Errorf("c.missing = %v, want none", c.missing)
+	}
+}
+
+func TestStructFieldMappings(t *testing.T) {
+	t.Parallel()
+
+	type s1 struct {
+		A int
+		B string
+		c int
+	}
+	type s2 struct {
+		B string
+		A int
+		C int
+	}
+
+	got := structFieldMappings(reflect.TypeOf(s2{}), reflect.TypeOf(s1{}))
+	want := []fieldMapping{
+		{name: "A", srcIndex: 0, destIndex: []int{1}},
+		{name: "B", srcIndex: 1, destIndex: []int{0}},
+		{name: "c", srcIndex: 2},
+	}
+	if diff := cmp.Diff(got, want, cmp.AllowUnexported(fieldMapping{})); diff != "" {
+		t.Errorf("structFieldMappings(): -got,+want: %s", diff)
+	}
+	// The second call should return the cached mapping.
+	got2 := structFieldMappings(reflect.TypeOf(s2{}), reflect.TypeOf(s1{}))
+	if &got2[0] != &got[0] {
+		t.Errorf("structFieldMappings() was not cached")
+	}
+}
+
+func BenchmarkCopyBackendService(b *testing.B) {
+	src := &compute.BackendService{
+		Name:     "bs",
+		Protocol: "HTTP",
+		Backends: []*compute.Backend{
+			{Group: "ig1", BalancingMode: "UTILIZATION", MaxUtilization: 0.8},
+			{Group: "ig2", BalancingMode: "RATE", MaxRatePerInstance: 10},
+		},
+		HealthChecks: []string{"hc"},
+		CdnPolicy: &compute.BackendServiceCdnPolicy{
+			CacheMode: "CACHE_ALL_STATIC",
+			CacheKeyPolicy: &compute.CacheKeyPolicy{
+				IncludeHost: true,
+			},
+		},
+		LogConfig:       &compute.BackendServiceLogConfig{Enable: true},
+		ForceSendFields: []string{"Description"},
+	}
+	for i := 0; i < b.N; i++ {
+		var dest alpha.BackendService
+		if err := newCopier().do(reflect.ValueOf(&dest), reflect.ValueOf(src)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -41,6 +41,24 @@ func diff[T any](a, b *T, trait *FieldTraits) (*DiffResult, error) {
 	return d.result, nil
 }
 
+// Equal returns true if a and b are semantically equal. OutputOnly and System
+// fields (as given by traits) are skipped, as are the metafields. Unlike Diff,
+// nil and empty slices and maps are considered equal. traits may be nil.
+func Equal[T any](a, b *T, traits *FieldTraits) (bool, error) {
+	if traits == nil {
+		traits = &FieldTraits{}
+	}
+	d := &differ[T]{
+		traits:      traits,
+		result:      &DiffResult{},
+		emptyIsZero: true,
+	}
+	if err := d.do(Path{}, reflect.ValueOf(a), reflect.ValueOf(b)); err != nil {
+		return false, err
+	}
+	return !d.result.HasDiff(), nil
+}
+
 func diffStructs[A any, B any](a *A, b *B) (*DiffResult, error) {
 	d := &differ[A]{
 		traits: &FieldTraits{},
@@ -114,19 +132,33 @@ type DiffItem struct {
 type differ[T any] struct {
 	traits *FieldTraits
 	result *DiffResult
+	// emptyIsZero treats empty slices and maps the same as nil.
+	emptyIsZero bool
+}
+
+// isZero returns true if v is the zero value.
+func (d *differ[T]) isZero(v reflect.Value) bool {
+	if d.emptyIsZero {
+		switch v.Kind() {
+		case reflect.Slice, reflect.Map:
+			return v.Len() == 0
+		}
+	}
+	return v.IsZero()
 }
 
 func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 	// cmpZero applies to pointer, slice and map values. Returns true if no
 	// further diff'ing is required for the values.
 	cmpZero := func() bool {
+		aZero, bZero := d.isZero(av), d.isZero(bv)
 		switch {
-		case av.IsZero() && bv.IsZero():
+		case aZero && bZero:
 			return true
-		case !av.IsZero() && bv.IsZero():
+		case !aZero && bZero:
 			d.result.add(DiffItemOnlyInA, p, av, bv)
 			return true
-		case av.IsZero() && !bv.IsZero():
+		case aZero && !bZero:
 			d.result.add(DiffItemOnlyInB, p, av, bv)
 			return true
 		}
@@ -151,7 +183,7 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 			afv := av.Field(i)
 			aft := av.Type().Field(i)
 
-			if aft.Name == "NullFields" || aft.Name == "ForceSendFields" {
+			if aft.Name == "NullFields" || aft.Name == "ForceSendFields" || skipField(av.Type(), aft) {
 				continue
 			}
 
@@ -197,26 +229,26 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 		if cmpZero() {
 			return nil
 		}
-		if av.Len() != bv.Len() {
-			d.result.add(DiffItemDifferent, p, av, bv)
-			return nil
-		}
-		// For maps of the same size, for the maps to be equal, all keys in A
-		// must be present in B for these to be equal. This means we don't have
-		// to check  in the opposite direction from B to A. However, this makes
-		// the Diff function non-symmetric.
+		// Compare the maps key by key so that the differences are reported
+		// at the path of each key (e.g. for maps with struct values).
 		for _, amk := range av.MapKeys() {
 			amv := av.MapIndex(amk)
 			bmv := bv.MapIndex(amk)
 			mp := p.MapIndex(amk)
 
 			if !bmv.IsValid() {
-				d.result.add(DiffItemDifferent, mp, amv, bmv)
+				d.result.add(DiffItemOnlyInA, mp, amv, bmv)
+				continue
 			}
 			if err := d.do(mp, amv, bmv); err != nil {
 				return fmt.Errorf("differ map %p: %w", mp, err)
 			}
 		}
+		for _, bmk := range bv.MapKeys() {
+			if !av.MapIndex(bmk).IsValid() {
+				d.result.add(DiffItemOnlyInB, p.MapIndex(bmk), reflect.Value{}, bv.MapIndex(bmk))
+			}
+		}
 		return nil
 	}
 
@@ -19,6 +19,7 @@ package api
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/kr/pretty"
 )
 
@@ -191,6 +192,46 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestDiffMapOfStruct(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I int
+		S string
+	}
+	type st struct {
+		M  map[string]sti
+		MP map[string]*sti
+	}
+
+	a := &st{
+		M:  map[string]sti{"x": {I: 1}, "y": {I: 2}},
+		MP: map[string]*sti{"p": {S: "a"}, "q": {}},
+	}
+	b := &st{
+		M:  map[string]sti{"x": {I: 1, S: "b"}, "z": {}},
+		MP: map[string]*sti{"p": {S: "b"}, "q": nil},
+	}
+	r, err := diff(a, b, nil)
+	if err != nil {
+		t.Fatalf("diff() = %v, want nil", err)
+	}
+	got := map[string]DiffItemState{}
+	for _, item := range r.Items {
+		got[item.Path.String()] = item.State
+	}
+	want := map[string]DiffItemState{
+		"*.M:x.S":   DiffItemDifferent,
+		"*.M:y":     DiffItemOnlyInA,
+		"*.M:z":     DiffItemOnlyInB,
+		"*.MP:p*.S": DiffItemDifferent,
+		"*.MP:q":    DiffItemOnlyInA,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("diff(): -got,+want: %s", diff)
+	}
+}
+
 func TestDiffForStructWithUnexportedFields(t *testing.T) {
 	t.Parallel()
 
@@ -292,3 +333,91 @@ func TestDiffForStructWithUnexportedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I  int
+		LS []string
+	}
+	type st struct {
+		I   int
+		O   string
+		PSt *sti
+		LS  []string
+		M   map[string]string
+		LSt []sti
+	}
+
+	traits := &FieldTraits{}
+	traits.OutputOnly(Path{}.Pointer().Field("O"))
+
+	for _, tc := range []struct {
+		name string
+		a    st
+		b    st
+		want bool
+	}{
+		{
+			name: "empty",
+			want: true,
+		},
+		{
+			name: "basic eq",
+			a:    st{I: 1, LS: []string{"a"}},
+			b:    st{I: 1, LS: []string{"a"}},
+			want: true,
+		},
+		{
+			name: "basic diff",
+			a:    st{I: 1},
+			b:    st{I: 2},
+		},
+		{
+			name: "output only field is ignored",
+			a:    st{O: "a"},
+			b:    st{O: "b"},
+			want: true,
+		},
+		{
+			name: "nil and empty slice",
+			a:    st{LS: nil, PSt: &sti{LS: []string{}}},
+			b:    st{LS: []string{}, PSt: &sti{}},
+			want: true,
+		},
+		{
+			name: "nil and empty map",
+			a:    st{M: map[string]string{}},
+			want: true,
+		},
+		{
+			name: "nil and non-empty slice",
+			a:    st{LS: []string{"a"}},
+		},
+		{
+			name: "nil and zero struct pointer",
+			a:    st{PSt: &sti{}},
+		},
+		{
+			name: "slice of structs",
+			a:    st{LSt: []sti{{I: 1, LS: []string{}}}},
+			b:    st{LSt: []sti{{I: 1}}},
+			want: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Equal(&tc.a, &tc.b, traits)
+			if err != nil {
+				t.Fatalf("Equal() = %v, want nil", err)
+			}
+			if got != tc.want {
+				t.Errorf("Equal() = %t, want %t", got, tc.want)
+			}
+			// Equal should be symmetric.
+			if got, _ := Equal(&tc.b, &tc.a, traits); got != tc.want {
+				t.Errorf("Equal(b, a) = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// FieldProvenance records which version of the resource last wrote a field.
+// This can be used to explain why a field is set in a version, e.g. a value in
+// the GA object that was propagated from an AccessAlpha() call.
+type FieldProvenance struct {
+	// Path of the field that was written.
+	Path Path `json:"path"`
+	// Version that was modified by Access*() or Set*(). This will be empty
+	// for a resource that was deserialized without provenance.
+	Version meta.Version `json:"version,omitempty"`
+	// Time of the write.
+	Time time.Time `json:"time"`
+}
+
+// dirtyFields is the set of field paths that were explicitly set via
+// Access*() or Set*().
+type dirtyFields struct {
+	paths map[string]FieldProvenance
+}
+
+// add p as written by ver.
+func (d *dirtyFields) add(p Path, ver meta.Version) {
+	d.set(FieldProvenance{Path: p, Version: ver, Time: time.Now()})
+}
+
+func (d *dirtyFields) set(fp FieldProvenance) {
+	if d.paths == nil {
+		d.paths = map[string]FieldProvenance{}
+	}
+	fp.Path = append(Path{}, fp.Path...)
+	d.paths[fp.Path.String()] = fp
+}
+
+// list returns the dirty paths, sorted.
+func (d *dirtyFields) list() []Path {
+	var ret []Path
+	for _, fp := range d.provenance() {
+		ret = append(ret, fp.Path)
+	}
+	return ret
+}
+
+// provenance returns the provenance of the dirty paths, sorted by path.
+func (d *dirtyFields) provenance() []FieldProvenance {
+	var keys []string
+	for k := range d.paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var ret []FieldProvenance
+	for _, k := range keys {
+		ret = append(ret, d.paths[k])
+	}
+	return ret
+}
+
+// provenanceOf returns the most recent write to p. Writes to a parent of p or
+// to a field contained in p are included (see has()).
+func (d *dirtyFields) provenanceOf(p Path) (FieldProvenance, bool) {
+	var (
+		ret FieldProvenance
+		ok  bool
+	)
+	for _, fp := range d.provenance() {
+		if !p.HasPrefix(fp.Path) && !fp.Path.HasPrefix(p) {
+			continue
+		}
+		if !ok || !fp.Time.Before(ret.Time) {
+			ret, ok = fp, true
+		}
+	}
+	return ret, ok
+}
+
+// has returns true if p was set. This is true if p or one of its parents was
+// set or if a field contained in p was set.
+func (d *dirtyFields) has(p Path) bool {
+	for _, dp := range d.paths {
+		if p.HasPrefix(dp.Path) || dp.Path.HasPrefix(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// trackDirty calls f(obj), recording the fields that were changed by f in d as
+// written by ver.
+// Fields that were added to the NullFields or ForceSendFields are also
+// recorded as these are explicitly set to a zero value.
+func trackDirty[T any](d *dirtyFields, ver meta.Version, obj *T, f func(*T) error) error {
+	var before T
+	if err := newCopier().do(reflect.ValueOf(&before), reflect.ValueOf(obj)); err != nil {
+		return fmt.Errorf("trackDirty: %w", err)
+	}
+	beforeMeta, err := metafieldPaths(reflect.ValueOf(&before))
+	if err != nil {
+		return err
+	}
+
+	if err := f(obj); err != nil {
+		return err
+	}
+
+	r, err := diff(&before, obj, nil)
+	if err != nil {
+		return fmt.Errorf("trackDirty: %w", err)
+	}
+	for _, item := range r.Items {
+		d.add(item.Path, ver)
+	}
+	afterMeta, err := metafieldPaths(reflect.ValueOf(obj))
+	if err != nil {
+		return err
+	}
+	for k, p := range afterMeta {
+		if _, ok := beforeMeta[k]; !ok {
+			d.add(p, ver)
+		}
+	}
+	return nil
+}
+
+// metafieldPaths returns the paths of the fields referenced by the
+// NullFields and ForceSendFields in v.
+func metafieldPaths(v reflect.Value) (map[string]Path, error) {
+	ret := map[string]Path{}
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		ma, err := newMetafieldAccessor(v)
+		if err != nil {
+			// Structs without metafields (e.g. PlaceholderType).
+			return true, nil
+		}
+		for _, m := range []map[string]bool{ma.null(), ma.forceSend()} {
+			for v := range m {
+				fn, _ := metafieldName(v)
+				fp := append(Path{}, p.Field(fn)...)
+				ret[fp.String()] = fp
+			}
+		}
+		return true, nil
+	}
+	if err := visit(v, acc); err != nil {
+		return nil, fmt.Errorf("metafieldPaths: %w", err)
+	}
+	return ret, nil
+}
@@ -58,6 +58,28 @@ limitations under the License.
 //	    if errors.As(err, &objErrors) { /* handle MissingFields, etc. */ }
 //	}
 //
+// Errors from the schema checks and type conversions are *Error values with an
+// ErrorCode (e.g. ErrorCodeMissingField) and the Path of the offending field.
+// Use ErrorCodeOf() to decide how to handle them.
+//
+// The fields changed by Access*() and Set*() (including fields listed in the
+// meta-fields) are tracked. Use DirtyFields() and IsDirty() to distinguish a
+// field that was intentionally set to zero from one that was never set:
+//
+//	addr.IsDirty(api.Path{}.Pointer().Field("Region")) // true
+//	addr.IsDirty(api.Path{}.Pointer().Field("Address")) // false
+//
+// ProvenanceOf() returns the version whose Access*() or Set*() last wrote a
+// field, which explains e.g. why the GA object contains a value that was set
+// via AccessAlpha():
+//
+//	fp, _ := addr.ProvenanceOf(api.Path{}.Pointer().Field("Labels"))
+//	fp.Version // meta.VersionBeta
+//
+// A MutableResource is not safe for concurrent use. Wrap it with
+// Synchronized() if it is shared between goroutines; see Synchronized() for
+// the cost of doing so.
+//
 // # Checking type assumptions with unit tests
 //
 // Resource.CheckSchema() can be used to check if the types referenced meet the
@@ -78,4 +100,28 @@ limitations under the License.
 //	// finished. This allows for any additional fixup of the fields after
 //	// conversion.
 //	func (*myTypeTrait) CopyHelperGAtoAlpha(...) { ... }
+//
+//	// FieldConversions convert fields that have a different name or type
+//	// between versions. These fields are not reported as MissingFields.
+//	func (*myTypeTrait) FieldConversions(src, dest meta.Version) []FieldConversion { ... }
+//
+//	// ValidateGA (and ValidateAlpha, ValidateBeta) check the resource on
+//	// Access*() and Freeze(). See ResourceValidator.
+//	func (*myTypeTrait) ValidateGA(obj *myTypeGA) []InvalidField { ... }
+//
+// FieldTraits can be given declaratively with a schema (see
+// FieldTraitsFromSchema and SchemaTypeTrait):
+//
+//	var myTypeTrait = MustNewSchemaTypeTrait[myTypeGA, myTypeAlpha, myTypeBeta](`
+//	  OutputOnly    CreationTimestamp
+//	  NonZeroValue  Backends[].Group
+//	`)
+//
+// # Proto resources
+//
+// Resources can also wrap structs generated by protoc-gen-go (e.g. the
+// computepb types). The unexported fields of the messages are ignored and
+// presence is given by the optional (pointer) fields instead of
+// NullFields/ForceSendFields. Name and SelfLink may be *string. Oneof fields
+// are not supported.
 package api
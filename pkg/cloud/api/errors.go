@@ -0,0 +1,75 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode classifies the errors returned by the type checks (e.g.
+// CheckSchema, CheckStructuralSubset) and by conversions between versions.
+type ErrorCode string
+
+const (
+	// ErrorCodeMissingField is returned when a field does not exist in the
+	// type or version.
+	ErrorCodeMissingField ErrorCode = "MissingField"
+	// ErrorCodeTypeMismatch is returned when the type of a field is not the
+	// type that was expected.
+	ErrorCodeTypeMismatch ErrorCode = "TypeMismatch"
+	// ErrorCodeUnsupportedKind is returned when the type contains a kind
+	// (e.g. chan, func, recursive structs) that is not supported.
+	ErrorCodeUnsupportedKind ErrorCode = "UnsupportedKind"
+)
+
+// Error is the typed error returned by the checks and conversions in this
+// package. Use errors.As or ErrorCodeOf() to get the details.
+type Error struct {
+	// Code classifies the error.
+	Code ErrorCode
+	// Path of the offending field. This may be empty if the error applies to
+	// the entire type.
+	Path Path
+
+	msg string
+}
+
+// Error implements error.
+func (e *Error) Error() string { return e.msg }
+
+func newError(code ErrorCode, p Path, format string, args ...any) *Error {
+	return &Error{
+		Code: code,
+		Path: append(Path{}, p...),
+		msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+// ErrorCodeOf returns the ErrorCode of err if err is (or wraps) an Error or
+// a ConversionError. A ConversionError is reported as ErrorCodeMissingField.
+func ErrorCodeOf(err error) (ErrorCode, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code, true
+	}
+	var ce *ConversionError
+	if errors.As(err, &ce) {
+		return ErrorCodeMissingField, true
+	}
+	return "", false
+}
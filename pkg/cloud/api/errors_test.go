@@ -0,0 +1,172 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	type inner struct{ A int }
+	type st struct {
+		Name     string
+		SelfLink string
+		I        inner
+		M        map[string]int
+	}
+	type noSelfLink struct {
+		Name string
+	}
+	type withChan struct {
+		Name     string
+		SelfLink string
+		C        chan int
+	}
+	type otherSt struct {
+		Name     string
+		SelfLink string
+		I        string
+	}
+
+	for _, tc := range []struct {
+		name     string
+		f        func() error
+		wantCode ErrorCode
+		wantPath Path
+	}{
+		{
+			name:     "checkSchema missing field",
+			f:        func() error { return checkSchema(reflect.TypeOf(&noSelfLink{})) },
+			wantCode: ErrorCodeMissingField,
+			wantPath: Path{}.Pointer().Field("SelfLink"),
+		},
+		{
+			name:     "checkSchema unsupported kind",
+			f:        func() error { return checkSchema(reflect.TypeOf(&withChan{})) },
+			wantCode: ErrorCodeUnsupportedKind,
+			wantPath: Path{}.Pointer().Field("C"),
+		},
+		{
+			name: "CheckStructuralSubset missing field",
+			f: func() error {
+				return CheckStructuralSubset(reflect.TypeOf(&st{}), reflect.TypeOf(&noSelfLink{}))
+			},
+			wantCode: ErrorCodeMissingField,
+			wantPath: Path{}.Pointer().Field("SelfLink"),
+		},
+		{
+			name: "CheckStructuralSubset type mismatch",
+			f: func() error {
+				return CheckStructuralSubset(reflect.TypeOf(&st{}), reflect.TypeOf(&otherSt{}))
+			},
+			wantCode: ErrorCodeTypeMismatch,
+			wantPath: Path{}.Pointer().Field("I"),
+		},
+		{
+			name: "ResolveType missing field",
+			f: func() error {
+				_, err := Path{}.Pointer().Field("I").Field("B").ResolveType(reflect.TypeOf(&st{}))
+				return err
+			},
+			wantCode: ErrorCodeMissingField,
+			wantPath: Path{}.Pointer().Field("I").Field("B"),
+		},
+		{
+			name: "ResolveType type mismatch",
+			f: func() error {
+				_, err := Path{}.Pointer().Field("M").Index(0).ResolveType(reflect.TypeOf(&st{}))
+				return err
+			},
+			wantCode: ErrorCodeTypeMismatch,
+			wantPath: Path{}.Pointer().Field("M").Index(0),
+		},
+		{
+			name: "copier type mismatch",
+			f: func() error {
+				return newCopier().do(reflect.ValueOf(&otherSt{}), reflect.ValueOf(&st{}))
+			},
+			wantCode: ErrorCodeTypeMismatch,
+			wantPath: Path{}.Pointer().Field("I"),
+		},
+		{
+			name: "wrapped",
+			f: func() error {
+				return fmt.Errorf("wrapped: %w", checkSchema(reflect.TypeOf(&noSelfLink{})))
+			},
+			wantCode: ErrorCodeMissingField,
+			wantPath: Path{}.Pointer().Field("SelfLink"),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.f()
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("f() = %v, want *Error", err)
+			}
+			if apiErr.Code != tc.wantCode {
+				t.Errorf("Code = %q, want %q (err = %v)", apiErr.Code, tc.wantCode, err)
+			}
+			if !apiErr.Path.Equal(tc.wantPath) {
+				t.Errorf("Path = %v, want %v (err = %v)", apiErr.Path, tc.wantPath, err)
+			}
+			if code, ok := ErrorCodeOf(err); !ok || code != tc.wantCode {
+				t.Errorf("ErrorCodeOf() = %q, %t; want %q, true", code, ok, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		wantCode ErrorCode
+		wantOk   bool
+	}{
+		{name: "nil"},
+		{name: "other error", err: errors.New("x")},
+		{
+			name:     "Error",
+			err:      newError(ErrorCodeUnsupportedKind, Path{}, "x"),
+			wantCode: ErrorCodeUnsupportedKind,
+			wantOk:   true,
+		},
+		{
+			name:     "ConversionError",
+			err:      fmt.Errorf("x: %w", &ConversionError{MissingFields: []MissingField{{}}}),
+			wantCode: ErrorCodeMissingField,
+			wantOk:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			code, ok := ErrorCodeOf(tc.err)
+			if code != tc.wantCode || ok != tc.wantOk {
+				t.Errorf("ErrorCodeOf(%v) = %q, %t; want %q, %t", tc.err, code, ok, tc.wantCode, tc.wantOk)
+			}
+		})
+	}
+}
@@ -163,6 +163,11 @@ func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
 		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
 			return false, nil
 		}
+		// Proto messages use pointer fields for presence and do not
+		// have metafields to fill.
+		if isProtoMessageT(v.Type()) {
+			return true, nil
+		}
 		acc, err := newMetafieldAccessor(v)
 		if err != nil {
 			return false, fmt.Errorf("fillNullAndForceSend: %w", err)
@@ -206,3 +211,56 @@ func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
 
 	return visit(v, acc)
 }
+
+// forceSendZeroValues adds the basic fields in v that are dirty and have a
+// zero value to ForceSendFields. See ZeroValueIsSet().
+func forceSendZeroValues(traits *FieldTraits, dirty *dirtyFields, v reflect.Value) error {
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
+			return false, nil
+		}
+		if isProtoMessageT(v.Type()) {
+			return true, nil
+		}
+		acc, err := newMetafieldAccessor(v)
+		if err != nil {
+			return false, fmt.Errorf("forceSendZeroValues: %w", err)
+		}
+
+		nullFields := acc.null()
+		forceSendFields := acc.forceSend()
+		var added bool
+
+		for i := 0; i < v.NumField(); i++ {
+			ft := v.Type().Field(i)
+			if ft.Name == "NullFields" || ft.Name == "ForceSendFields" || !isBasicT(ft.Type) {
+				continue
+			}
+			fp := p.Field(ft.Name)
+			switch traits.fieldType(fp) {
+			case FieldTypeAllowZeroValue, FieldTypeOutputOnly, FieldTypeSystem:
+				continue
+			}
+			if !v.Field(i).IsZero() || nullFields[ft.Name] || forceSendFields[ft.Name] || !dirty.has(fp) {
+				continue
+			}
+			forceSendFields[ft.Name] = true
+			added = true
+		}
+		if !added {
+			return true, nil
+		}
+
+		var sl []string
+		for k := range forceSendFields {
+			sl = append(sl, k)
+		}
+		sort.Strings(sl)
+		v.FieldByName(forceSendFieldsName).Set(reflect.ValueOf(sl))
+
+		return true, nil
+	}
+
+	return visit(v, acc)
+}
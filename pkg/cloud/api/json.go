@@ -0,0 +1,225 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// mutableResourceJSON is the serialized form of a mutableResource.
+type mutableResourceJSON struct {
+	ResourceID *cloud.ResourceID `json:"resourceID,omitempty"`
+	// Version that was last modified.
+	Version     meta.Version `json:"version"`
+	GA          *versionJSON `json:"ga,omitempty"`
+	Alpha       *versionJSON `json:"alpha,omitempty"`
+	Beta        *versionJSON `json:"beta,omitempty"`
+	DirtyFields []Path       `json:"dirtyFields,omitempty"`
+	// Provenance of the DirtyFields. This may be missing for resources
+	// serialized by an older version of this package.
+	Provenance []FieldProvenance `json:"provenance,omitempty"`
+}
+
+// versionJSON is the serialized form of one version of the resource.
+type versionJSON struct {
+	Object json.RawMessage `json:"object"`
+	// MetaFields are the metafields of the structs in Object. These are not
+	// serialized by the API types.
+	MetaFields []metaFieldsJSON `json:"metaFields,omitempty"`
+}
+
+type metaFieldsJSON struct {
+	Path            Path     `json:"path"`
+	NullFields      []string `json:"nullFields,omitempty"`
+	ForceSendFields []string `json:"forceSendFields,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u *mutableResource[GA, Alpha, Beta]) MarshalJSON() ([]byte, error) {
+	ret := mutableResourceJSON{
+		ResourceID:  u.resourceID,
+		Version:     u.authored,
+		DirtyFields: u.dirty.list(),
+		Provenance:  u.dirty.provenance(),
+	}
+	if ret.Version == "" {
+		ret.Version = meta.VersionGA
+	}
+
+	var err error
+	if ret.GA, err = marshalVersion(&u.ga); err != nil {
+		return nil, fmt.Errorf("MarshalJSON %s: %w", meta.VersionGA, err)
+	}
+	if !isPlaceholderType(u.alpha) {
+		if ret.Alpha, err = marshalVersion(&u.alpha); err != nil {
+			return nil, fmt.Errorf("MarshalJSON %s: %w", meta.VersionAlpha, err)
+		}
+	}
+	if !isPlaceholderType(u.beta) {
+		if ret.Beta, err = marshalVersion(&u.beta); err != nil {
+			return nil, fmt.Errorf("MarshalJSON %s: %w", meta.VersionBeta, err)
+		}
+	}
+	return json.Marshal(ret)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The conversion errors are
+// recomputed from the restored versions.
+func (u *mutableResource[GA, Alpha, Beta]) UnmarshalJSON(b []byte) error {
+	var in mutableResourceJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	switch in.Version {
+	case meta.VersionGA, meta.VersionAlpha, meta.VersionBeta:
+	default:
+		return fmt.Errorf("UnmarshalJSON: invalid version %q", in.Version)
+	}
+	if u.typeTrait == nil {
+		u.typeTrait = &BaseTypeTrait[GA, Alpha, Beta]{}
+	}
+
+	var (
+		ga    GA
+		alpha Alpha
+		beta  Beta
+	)
+	if err := unmarshalVersion(in.GA, &ga); err != nil {
+		return fmt.Errorf("UnmarshalJSON %s: %w", meta.VersionGA, err)
+	}
+	if !isPlaceholderType(alpha) {
+		if err := unmarshalVersion(in.Alpha, &alpha); err != nil {
+			return fmt.Errorf("UnmarshalJSON %s: %w", meta.VersionAlpha, err)
+		}
+	}
+	if !isPlaceholderType(beta) {
+		if err := unmarshalVersion(in.Beta, &beta); err != nil {
+			return fmt.Errorf("UnmarshalJSON %s: %w", meta.VersionBeta, err)
+		}
+	}
+
+	u.ga, u.alpha, u.beta = ga, alpha, beta
+	if in.ResourceID != nil {
+		u.resourceID = in.ResourceID
+	}
+	u.authored = in.Version
+	u.dirty = dirtyFields{}
+	for _, p := range in.DirtyFields {
+		u.dirty.set(FieldProvenance{Path: p})
+	}
+	for _, fp := range in.Provenance {
+		u.dirty.set(fp)
+	}
+	return u.recomputeErrors()
+}
+
+// recomputeErrors recomputes the conversion errors between all of the
+// versions.
+func (u *mutableResource[GA, Alpha, Beta]) recomputeErrors() error {
+	for _, ver := range []meta.Version{meta.VersionGA, meta.VersionAlpha, meta.VersionBeta} {
+		src, conversions := u.versionConversions(ver)
+		if isPlaceholderType(src.Interface()) {
+			continue
+		}
+		for _, conv := range conversions {
+			c := u.newVersionCopier(ver, conv.ver)
+			if err := c.do(reflect.New(conv.dest.Type().Elem()), src); err != nil {
+				return err
+			}
+			conv.errors.missingFields = c.missing
+		}
+	}
+	return nil
+}
+
+func marshalVersion[T any](obj *T) (*versionJSON, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	ret := &versionJSON{Object: b}
+
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		ma, err := newMetafieldAccessor(v)
+		if err != nil {
+			// Structs without metafields.
+			return true, nil
+		}
+		mf := metaFieldsJSON{
+			Path:            append(Path{}, p...),
+			NullFields:      ma.nullFields.Interface().([]string),
+			ForceSendFields: ma.forceSendFields.Interface().([]string),
+		}
+		if len(mf.NullFields) > 0 || len(mf.ForceSendFields) > 0 {
+			ret.MetaFields = append(ret.MetaFields, mf)
+		}
+		return true, nil
+	}
+	if err := visit(reflect.ValueOf(obj), acc); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func unmarshalVersion[T any](in *versionJSON, obj *T) error {
+	if in == nil {
+		return nil
+	}
+	if err := json.Unmarshal(in.Object, obj); err != nil {
+		return err
+	}
+	if len(in.MetaFields) == 0 {
+		return nil
+	}
+
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		for _, mf := range in.MetaFields {
+			if !p.Equal(mf.Path) {
+				continue
+			}
+			ma, err := newMetafieldAccessor(v)
+			if err != nil {
+				return false, fmt.Errorf("%s: %w", p, err)
+			}
+			ma.nullFields.Set(reflect.ValueOf(mf.NullFields))
+			ma.forceSendFields.Set(reflect.ValueOf(mf.ForceSendFields))
+			// NullFields for a map key are serialized as {"key": null},
+			// which is decoded as a zero value entry in the map.
+			for _, nf := range mf.NullFields {
+				fn, key, ok := strings.Cut(nf, ".")
+				if !ok {
+					continue
+				}
+				fv := v.FieldByName(fn)
+				if fv.Kind() != reflect.Map || fv.IsNil() || fv.Type().Key().Kind() != reflect.String {
+					continue
+				}
+				fv.SetMapIndex(reflect.ValueOf(key).Convert(fv.Type().Key()), reflect.Value{})
+			}
+		}
+		return true, nil
+	}
+	return visit(reflect.ValueOf(obj), acc)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceJSON(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int      `json:"i,omitempty"`
+		NullFields      []string `json:"-"`
+		ForceSendFields []string `json:"-"`
+	}
+	type ga struct {
+		Name            string            `json:"name,omitempty"`
+		P               *sti              `json:"p,omitempty"`
+		L               []sti             `json:"l,omitempty"`
+		M               map[string]string `json:"m,omitempty"`
+		NullFields      []string          `json:"-"`
+		ForceSendFields []string          `json:"-"`
+	}
+	type alph struct {
+		Name            string            `json:"name,omitempty"`
+		P               *sti              `json:"p,omitempty"`
+		L               []sti             `json:"l,omitempty"`
+		M               map[string]string `json:"m,omitempty"`
+		B               int               `json:"b,omitempty"`
+		NullFields      []string          `json:"-"`
+		ForceSendFields []string          `json:"-"`
+	}
+
+	res := newTestResource[ga, alph, PlaceholderType](nil)
+	if err := res.Access(func(x *ga) {
+		x.P = &sti{ForceSendFields: []string{"I"}}
+		x.L = []sti{{I: 1}, {NullFields: []string{"I"}}}
+		x.M = map[string]string{"a": "b"}
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	// B does not exist in GA, so this is a conversion error.
+	if err := res.AccessAlpha(func(x *alph) {
+		x.B = 10
+		x.ForceSendFields = []string{"Name"}
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	got := NewResource[ga, alph, PlaceholderType](&cloud.ResourceID{Key: meta.GlobalKey("other")}, nil)
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("json.Unmarshal() = %v, want nil", err)
+	}
+
+	if diff := cmp.Diff(got.ResourceID(), res.ResourceID()); diff != "" {
+		t.Errorf("ResourceID() -got,+want: %s", diff)
+	}
+	if got.authored != meta.VersionAlpha {
+		t.Errorf("authored = %q, want %q", got.authored, meta.VersionAlpha)
+	}
+	gotGA, gotErr := got.ToGA()
+	wantGA, wantErr := res.ToGA()
+	if diff := cmp.Diff(gotGA, wantGA); diff != "" {
+		t.Errorf("ToGA() -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(gotErr, wantErr); diff != "" {
+		t.Errorf("ToGA() error -got,+want: %s", diff)
+	}
+	if wantErr == nil {
+		t.Errorf("ToGA() = nil, want error")
+	}
+	gotAlpha, gotErr := got.ToAlpha()
+	wantAlpha, wantErr := res.ToAlpha()
+	if diff := cmp.Diff(gotAlpha, wantAlpha); diff != "" {
+		t.Errorf("ToAlpha() -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(gotErr, wantErr); diff != "" {
+		t.Errorf("ToAlpha() error -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(got.DirtyFields(), res.DirtyFields()); diff != "" {
+		t.Errorf("DirtyFields() -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(got.Provenance(), res.Provenance()); diff != "" {
+		t.Errorf("Provenance() -got,+want: %s", diff)
+	}
+}
+
+func TestResourceJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		NullFields      []string `json:"-"`
+		ForceSendFields []string `json:"-"`
+	}
+
+	for _, tc := range []struct {
+		name string
+		in   string
+	}{
+		{name: "invalid JSON", in: `{`},
+		{name: "invalid version", in: `{"version": "v2"}`},
+		{name: "invalid object", in: `{"version": "ga", "ga": {"object": {"I": "abc"}}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestResource[st, st, st](nil)
+			if err := json.Unmarshal([]byte(tc.in), r); err == nil {
+				t.Error("json.Unmarshal() = nil, want error")
+			}
+		})
+	}
+}
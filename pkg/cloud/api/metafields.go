@@ -19,6 +19,7 @@ package api
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 const (
@@ -26,6 +27,14 @@ const (
 	forceSendFieldsName = "ForceSendFields"
 )
 
+// metafieldName returns the name of the field referenced by the metafield
+// entry v. NullFields may contain "Field.key" to send a null value for a key in
+// the map "Field"; hasKey is true in this case.
+func metafieldName(v string) (name string, hasKey bool) {
+	name, _, hasKey = strings.Cut(v, ".")
+	return name, hasKey
+}
+
 func newMetafieldAccessor(v reflect.Value) (*metafieldAccessor, error) {
 	if v.Type().Kind() != reflect.Struct {
 		return nil, fmt.Errorf("invalid type: %s", v.Type())
@@ -64,6 +73,9 @@ func (a *metafieldAccessor) forceSend() map[string]bool {
 }
 
 func (a *metafieldAccessor) inNull(f string) bool {
+	if !a.nullFields.IsValid() {
+		return false
+	}
 	for _, x := range a.nullFields.Interface().([]string) {
 		if f == x {
 			return true
@@ -73,6 +85,9 @@ func (a *metafieldAccessor) inNull(f string) bool {
 }
 
 func (a *metafieldAccessor) inForceSend(f string) bool {
+	if !a.forceSendFields.IsValid() {
+		return false
+	}
 	for _, x := range a.forceSendFields.Interface().([]string) {
 		if f == x {
 			return true
@@ -17,6 +17,7 @@ limitations under the License.
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -90,6 +91,7 @@ type conversionErrors struct {
 func NewResource[GA any, Alpha any, Beta any](
 	resourceID *cloud.ResourceID,
 	typeTrait TypeTrait[GA, Alpha, Beta],
+	opts ...ResourceOption,
 ) *mutableResource[GA, Alpha, Beta] {
 	if typeTrait == nil {
 		typeTrait = &BaseTypeTrait[GA, Alpha, Beta]{}
@@ -99,17 +101,20 @@ func NewResource[GA any, Alpha any, Beta any](
 		typeTrait:  typeTrait,
 		resourceID: resourceID,
 	}
+	for _, o := range opts {
+		o(&obj.options)
+	}
 
 	// Set .Name from the ResourceID.
 	setName := func(v reflect.Value) {
-		if ft, ok := v.Type().FieldByName("Name"); !ok || ft.Type.Kind() != reflect.String {
+		if ft, ok := v.Type().FieldByName("Name"); !ok || !isStringOrPtrToStringT(ft.Type) {
 			return
 		}
 		f := v.FieldByName("Name")
 		if !f.IsValid() {
 			panic(fmt.Sprintf("type does not have .Name (%T)", v.Type()))
 		}
-		f.Set(reflect.ValueOf(resourceID.Key.Name))
+		setStringOrPtr(f, resourceID.Key.Name)
 	}
 	setName(reflect.ValueOf(&obj.ga).Elem())
 	setName(reflect.ValueOf(&obj.alpha).Elem())
@@ -118,6 +123,26 @@ func NewResource[GA any, Alpha any, Beta any](
 	return obj
 }
 
+// ResourceOption are options for NewResource.
+type ResourceOption func(*resourceOptions)
+
+type resourceOptions struct {
+	zeroValueIsSet bool
+}
+
+// ZeroValueIsSet configures the Resource to treat basic (e.g. int, string)
+// fields that were set to a zero value by Access*() as set. These fields are
+// added to ForceSendFields so that they are sent to the API (e.g. port 0 or a
+// timeout of 0) instead of being dropped. Fields that were never set
+// (see DirtyFields()) are left as is.
+//
+// Fields with the AllowZeroValue, OutputOnly or System trait are not affected.
+// Without this option, a zero value must be listed in ForceSendFields (or the
+// field given the NonZeroValue trait) to be sent.
+func ZeroValueIsSet() ResourceOption {
+	return func(o *resourceOptions) { o.zeroValueIsSet = true }
+}
+
 // MutableResource wraps the multi-versioned concrete resources.
 type MutableResource[GA any, Alpha any, Beta any] interface {
 	// CheckSchema should be called in init() to ensure that the resource being
@@ -151,6 +176,14 @@ type MutableResource[GA any, Alpha any, Beta any] interface {
 	// error.As ConversionError to get the specific details.
 	ToBeta() (*Beta, error)
 
+	// LossyFields reports the fields that are set but would be dropped
+	// when converting the resource to each version. This is the same
+	// information as the ConversionError returned by To*() but does not
+	// require converting the resource. There is an entry for every version
+	// that is not a PlaceholderType; a nil list means the conversion to
+	// that version is lossless.
+	LossyFields() map[meta.Version][]MissingField
+
 	// Set the value to src. This skips some of the field
 	// validation in Access* so should only be used with a valid
 	// object returned from GCE.
@@ -164,10 +197,40 @@ type MutableResource[GA any, Alpha any, Beta any] interface {
 	// object returned from GCE.
 	SetBeta(src *Beta) error
 
+	// DirtyFields returns the paths of the fields that were explicitly set
+	// via Access*() and Set*(). This includes fields that were set to a
+	// zero value via NullFields or ForceSendFields.
+	DirtyFields() []Path
+	// IsDirty returns true if the field at p (or a parent or child of p)
+	// was explicitly set. This distinguishes fields that are intentionally
+	// zero from fields that were never set.
+	IsDirty(p Path) bool
+	// Provenance returns the version that last wrote each of the
+	// DirtyFields() and when. Fields that are propagated to the other
+	// versions are attributed to the version that was accessed, e.g. a
+	// field set with AccessAlpha() is reported as written by Alpha.
+	Provenance() []FieldProvenance
+	// ProvenanceOf returns the most recent write to the field at p (or a
+	// parent or child of p). ok is false if the field was never set.
+	ProvenanceOf(p Path) (fp FieldProvenance, ok bool)
+
 	// Freeze the resource to a read-only copy. It is an error if it is ambiguous
 	// which version is the correct one i.e. not all fields can be represented in a
 	// single version of the resource.
 	Freeze() (Resource[GA, Alpha, Beta], error)
+
+	// Clone returns a deep copy of the resource. The copy does not share
+	// any slices, maps or pointers with the original, so it can be modified
+	// independently.
+	Clone() (MutableResource[GA, Alpha, Beta], error)
+
+	// MarshalJSON serializes all versions of the resource together with the
+	// version that was last modified. UnmarshalJSON restores the resource,
+	// including the metafields, conversion errors and DirtyFields(). The
+	// TypeTrait is not serialized; unmarshal into a resource created with
+	// NewResource().
+	json.Marshaler
+	json.Unmarshaler
 }
 
 type mutableResource[GA any, Alpha any, Beta any] struct {
@@ -179,7 +242,12 @@ type mutableResource[GA any, Alpha any, Beta any] struct {
 	beta  Beta
 
 	resourceID *cloud.ResourceID
+	options    resourceOptions
 	errors     [conversionContextCount]conversionErrors
+	dirty      dirtyFields
+	// authored is the version that was last modified via Access*() or
+	// Set*().
+	authored meta.Version
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) CheckSchema() error {
@@ -201,7 +269,7 @@ func (u *mutableResource[GA, Alpha, Beta]) CheckSchema() error {
 		alpha, _ := u.ToAlpha()
 		err = checkSubsetOf(ga, alpha)
 		if err != nil {
-			return fmt.Errorf("checkSubsetOf(%T, %T) = %v, want nil", ga, alpha, err)
+			return fmt.Errorf("checkSubsetOf(%T, %T) = %w, want nil", ga, alpha, err)
 		}
 	}
 	if !isPlaceholderType(u.beta) {
@@ -213,7 +281,7 @@ func (u *mutableResource[GA, Alpha, Beta]) CheckSchema() error {
 		err = checkSubsetOf(ga, beta)
 
 		if err != nil {
-			return fmt.Errorf("checkSubsetOf(%T, %T) = %v, want nil", ga, beta, err)
+			return fmt.Errorf("checkSubsetOf(%T, %T) = %w, want nil", ga, beta, err)
 		}
 	}
 
@@ -231,28 +299,34 @@ const (
 	postAccessSkipValidation = 1 << iota
 )
 
-func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags int) error {
-	type convert struct {
-		dest       reflect.Value
-		copyHelper func() error
-		errors     *conversionErrors
-	}
+// versionConversion is a conversion from a source version to ver.
+type versionConversion struct {
+	ver        meta.Version
+	dest       reflect.Value
+	copyHelper func() error
+	errors     *conversionErrors
+}
 
+// versionConversions returns the source value and the conversions to the other
+// (non-placeholder) versions for srcVer.
+func (u *mutableResource[GA, Alpha, Beta]) versionConversions(srcVer meta.Version) (reflect.Value, []versionConversion) {
 	var src reflect.Value
-	var conversions []convert
+	var conversions []versionConversion
 
 	switch srcVer {
 	case meta.VersionGA:
 		src = reflect.ValueOf(&u.ga)
 		if !isPlaceholderType(u.alpha) {
-			conversions = append(conversions, convert{
+			conversions = append(conversions, versionConversion{
+				ver:        meta.VersionAlpha,
 				dest:       reflect.ValueOf(&u.alpha),
 				copyHelper: func() error { return u.typeTrait.CopyHelperGAtoAlpha(&u.alpha, &u.ga) },
 				errors:     &u.errors[GAToAlphaConversion],
 			})
 		}
 		if !isPlaceholderType(u.beta) {
-			conversions = append(conversions, convert{
+			conversions = append(conversions, versionConversion{
+				ver:        meta.VersionBeta,
 				dest:       reflect.ValueOf(&u.beta),
 				copyHelper: func() error { return u.typeTrait.CopyHelperGAtoBeta(&u.beta, &u.ga) },
 				errors:     &u.errors[GAToBetaConversion],
@@ -261,14 +335,16 @@ func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags
 	case meta.VersionAlpha:
 		src = reflect.ValueOf(&u.alpha)
 		if !isPlaceholderType(u.ga) {
-			conversions = append(conversions, convert{
+			conversions = append(conversions, versionConversion{
+				ver:        meta.VersionGA,
 				dest:       reflect.ValueOf(&u.ga),
 				copyHelper: func() error { return u.typeTrait.CopyHelperAlphaToGA(&u.ga, &u.alpha) },
 				errors:     &u.errors[AlphaToGAConversion],
 			})
 		}
 		if !isPlaceholderType(u.beta) {
-			conversions = append(conversions, convert{
+			conversions = append(conversions, versionConversion{
+				ver:        meta.VersionBeta,
 				dest:       reflect.ValueOf(&u.beta),
 				copyHelper: func() error { return u.typeTrait.CopyHelperAlphaToBeta(&u.beta, &u.alpha) },
 				errors:     &u.errors[AlphaToBetaConversion],
@@ -277,14 +353,16 @@ func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags
 	case meta.VersionBeta:
 		src = reflect.ValueOf(&u.beta)
 		if !isPlaceholderType(u.ga) {
-			conversions = append(conversions, convert{
+			conversions = append(conversions, versionConversion{
+				ver:        meta.VersionGA,
 				dest:       reflect.ValueOf(&u.ga),
 				copyHelper: func() error { return u.typeTrait.CopyHelperBetaToGA(&u.ga, &u.beta) },
 				errors:     &u.errors[BetaToGAConversion],
 			})
 		}
 		if !isPlaceholderType(u.alpha) {
-			conversions = append(conversions, convert{
+			conversions = append(conversions, versionConversion{
+				ver:        meta.VersionAlpha,
 				dest:       reflect.ValueOf(&u.alpha),
 				copyHelper: func() error { return u.typeTrait.CopyHelperBetaToAlpha(&u.alpha, &u.beta) },
 				errors:     &u.errors[BetaToAlphaConversion],
@@ -292,13 +370,36 @@ func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags
 		}
 	}
 
+	return src, conversions
+}
+
+// newVersionCopier returns a copier for the conversion src => dest.
+func (u *mutableResource[GA, Alpha, Beta]) newVersionCopier(src, dest meta.Version) *copier {
+	opts := append([]copierOption{}, u.copierOptions...)
+	opts = append(opts,
+		copierFieldConversions(fieldConversions(u.typeTrait, src, dest)),
+		copierAllowMissing(fieldTraits(u.typeTrait, src).allowMissing),
+	)
+	return newCopier(opts...)
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags int) error {
+	u.authored = srcVer
+	src, conversions := u.versionConversions(srcVer)
+
 	if flags&postAccessSkipValidation == 0 {
-		if err := checkPostAccess(u.typeTrait.FieldTraits(srcVer), src); err != nil {
+		traits := fieldTraits(u.typeTrait, srcVer)
+		if u.options.zeroValueIsSet {
+			if err := forceSendZeroValues(traits, &u.dirty, src); err != nil {
+				return err
+			}
+		}
+		if err := checkPostAccess(traits, src); err != nil {
 			return err
 		}
 	}
 	for _, conv := range conversions {
-		c := newCopier(u.copierOptions...)
+		c := u.newVersionCopier(srcVer, conv.ver)
 		if err := c.do(conv.dest, src); err != nil {
 			return err
 		}
@@ -308,24 +409,44 @@ func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags
 		conv.errors.missingFields = c.missing
 	}
 
+	if flags&postAccessSkipValidation == 0 {
+		return u.validate(srcVer)
+	}
 	return nil
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) Access(f func(x *GA)) error {
-	f(&u.ga)
+	if err := trackDirty(&u.dirty, meta.VersionGA, &u.ga, func(x *GA) error { f(x); return nil }); err != nil {
+		return err
+	}
 	return u.postAccess(meta.VersionGA, 0)
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) AccessAlpha(f func(x *Alpha)) error {
-	f(&u.alpha)
+	if err := trackDirty(&u.dirty, meta.VersionAlpha, &u.alpha, func(x *Alpha) error { f(x); return nil }); err != nil {
+		return err
+	}
 	return u.postAccess(meta.VersionAlpha, 0)
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) AccessBeta(f func(x *Beta)) error {
-	f(&u.beta)
+	if err := trackDirty(&u.dirty, meta.VersionBeta, &u.beta, func(x *Beta) error { f(x); return nil }); err != nil {
+		return err
+	}
 	return u.postAccess(meta.VersionBeta, 0)
 }
 
+func (u *mutableResource[GA, Alpha, Beta]) DirtyFields() []Path { return u.dirty.list() }
+func (u *mutableResource[GA, Alpha, Beta]) IsDirty(p Path) bool { return u.dirty.has(p) }
+
+func (u *mutableResource[GA, Alpha, Beta]) Provenance() []FieldProvenance {
+	return u.dirty.provenance()
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) ProvenanceOf(p Path) (FieldProvenance, bool) {
+	return u.dirty.provenanceOf(p)
+}
+
 // ImpliedVersion returns the implied version of the underlying resource.
 // This is determined by the convertibility of the resource.
 //
@@ -383,17 +504,44 @@ func (u *mutableResource[GA, Alpha, Beta]) ImpliedVersion() (meta.Version, error
 	return meta.VersionGA, fmt.Errorf("indeterminant version (ga=%v, alpha=%v, beta=%v)", gaErr, alphaErr, betaErr)
 }
 
-func (u *mutableResource[GA, Alpha, Beta]) ToGA() (*GA, error) {
-	var errs ConversionError
-	for _, cc := range []ConversionContext{AlphaToGAConversion, BetaToGAConversion} {
+// conversionsTo are the ConversionContexts that convert to the version.
+var conversionsTo = map[meta.Version][]ConversionContext{
+	meta.VersionGA:    {AlphaToGAConversion, BetaToGAConversion},
+	meta.VersionAlpha: {GAToAlphaConversion, BetaToAlphaConversion},
+	meta.VersionBeta:  {GAToBetaConversion, AlphaToBetaConversion},
+}
+
+// missingFields returns the fields that did not convert to ver.
+func (u *mutableResource[GA, Alpha, Beta]) missingFields(ver meta.Version) []MissingField {
+	var ret []MissingField
+	for _, cc := range conversionsTo[ver] {
 		for _, mf := range u.errors[cc].missingFields {
-			errs.MissingFields = append(errs.MissingFields, MissingField{
+			ret = append(ret, MissingField{
 				Context: cc,
 				Path:    mf.Path,
 				Value:   mf.Value,
 			})
 		}
 	}
+	return ret
+}
+
+// LossyFields implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) LossyFields() map[meta.Version][]MissingField {
+	ret := map[meta.Version][]MissingField{
+		meta.VersionGA: u.missingFields(meta.VersionGA),
+	}
+	if !isPlaceholderType(u.alpha) {
+		ret[meta.VersionAlpha] = u.missingFields(meta.VersionAlpha)
+	}
+	if !isPlaceholderType(u.beta) {
+		ret[meta.VersionBeta] = u.missingFields(meta.VersionBeta)
+	}
+	return ret
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) ToGA() (*GA, error) {
+	errs := ConversionError{MissingFields: u.missingFields(meta.VersionGA)}
 	if errs.hasErr() {
 		return &u.ga, &errs
 	}
@@ -404,16 +552,7 @@ func (u *mutableResource[GA, Alpha, Beta]) ToAlpha() (*Alpha, error) {
 	if isPlaceholderType(u.alpha) {
 		return nil, useOfPlaceholderTypeError{msg: u.resourceID.String()}
 	}
-	var errs ConversionError
-	for _, cc := range []ConversionContext{GAToAlphaConversion, BetaToAlphaConversion} {
-		for _, mf := range u.errors[cc].missingFields {
-			errs.MissingFields = append(errs.MissingFields, MissingField{
-				Context: cc,
-				Path:    mf.Path,
-				Value:   mf.Value,
-			})
-		}
-	}
+	errs := ConversionError{MissingFields: u.missingFields(meta.VersionAlpha)}
 	if errs.hasErr() {
 		return &u.alpha, &errs
 	}
@@ -424,16 +563,7 @@ func (u *mutableResource[GA, Alpha, Beta]) ToBeta() (*Beta, error) {
 	if isPlaceholderType(u.beta) {
 		return nil, useOfPlaceholderTypeError{msg: u.resourceID.String()}
 	}
-	var errs ConversionError
-	for _, cc := range []ConversionContext{GAToBetaConversion, AlphaToBetaConversion} {
-		for _, mf := range u.errors[cc].missingFields {
-			errs.MissingFields = append(errs.MissingFields, MissingField{
-				Context: cc,
-				Path:    mf.Path,
-				Value:   mf.Value,
-			})
-		}
-	}
+	errs := ConversionError{MissingFields: u.missingFields(meta.VersionBeta)}
 	if errs.hasErr() {
 		return &u.beta, &errs
 	}
@@ -445,7 +575,8 @@ func (u *mutableResource[GA, Alpha, Beta]) ToBeta() (*Beta, error) {
 
 func (u *mutableResource[GA, Alpha, Beta]) Set(src *GA) error {
 	c := newCopier(u.copierOptions...)
-	if err := c.do(reflect.ValueOf(&u.ga), reflect.ValueOf(src)); err != nil {
+	set := func(x *GA) error { return c.do(reflect.ValueOf(x), reflect.ValueOf(src)) }
+	if err := trackDirty(&u.dirty, meta.VersionGA, &u.ga, set); err != nil {
 		return err
 	}
 	return u.postAccess(meta.VersionGA, postAccessSkipValidation)
@@ -453,7 +584,8 @@ func (u *mutableResource[GA, Alpha, Beta]) Set(src *GA) error {
 
 func (u *mutableResource[GA, Alpha, Beta]) SetAlpha(src *Alpha) error {
 	c := newCopier(u.copierOptions...)
-	if err := c.do(reflect.ValueOf(&u.alpha), reflect.ValueOf(src)); err != nil {
+	set := func(x *Alpha) error { return c.do(reflect.ValueOf(x), reflect.ValueOf(src)) }
+	if err := trackDirty(&u.dirty, meta.VersionAlpha, &u.alpha, set); err != nil {
 		return err
 	}
 	return u.postAccess(meta.VersionAlpha, postAccessSkipValidation)
@@ -461,7 +593,8 @@ func (u *mutableResource[GA, Alpha, Beta]) SetAlpha(src *Alpha) error {
 
 func (u *mutableResource[GA, Alpha, Beta]) SetBeta(src *Beta) error {
 	c := newCopier(u.copierOptions...)
-	if err := c.do(reflect.ValueOf(&u.beta), reflect.ValueOf(src)); err != nil {
+	set := func(x *Beta) error { return c.do(reflect.ValueOf(x), reflect.ValueOf(src)) }
+	if err := trackDirty(&u.dirty, meta.VersionBeta, &u.beta, set); err != nil {
 		return err
 	}
 	return u.postAccess(meta.VersionBeta, postAccessSkipValidation)
@@ -472,6 +605,9 @@ func (u *mutableResource[GA, Alpha, Beta]) Freeze() (Resource[GA, Alpha, Beta],
 	if err != nil {
 		return nil, err
 	}
+	if err := u.validate(ver); err != nil {
+		return nil, err
+	}
 	// For the structures in the other versions, fill in
 	// zero-valued fields in the metafields. This ensures that if
 	// the resource can be diff'd and sync'd correctly in all
@@ -486,20 +622,78 @@ func (u *mutableResource[GA, Alpha, Beta]) Freeze() (Resource[GA, Alpha, Beta],
 	// - At this point, we need to set NullFields = ["Feature1"],
 	//   otherwise the update will ignore the field.
 	if ver != meta.VersionGA {
-		if err := fillNullAndForceSend(u.typeTrait.FieldTraits(meta.VersionGA), reflect.ValueOf(&u.ga)); err != nil {
+		if err := fillNullAndForceSend(fieldTraits(u.typeTrait, meta.VersionGA), reflect.ValueOf(&u.ga)); err != nil {
 			return nil, err
 		}
 	}
 	if ver != meta.VersionAlpha {
-		if err := fillNullAndForceSend(u.typeTrait.FieldTraits(meta.VersionAlpha), reflect.ValueOf(&u.alpha)); err != nil {
+		if err := fillNullAndForceSend(fieldTraits(u.typeTrait, meta.VersionAlpha), reflect.ValueOf(&u.alpha)); err != nil {
 			return nil, err
 		}
 	}
 	if ver != meta.VersionBeta {
-		if err := fillNullAndForceSend(u.typeTrait.FieldTraits(meta.VersionBeta), reflect.ValueOf(&u.beta)); err != nil {
+		if err := fillNullAndForceSend(fieldTraits(u.typeTrait, meta.VersionBeta), reflect.ValueOf(&u.beta)); err != nil {
 			return nil, err
 		}
 	}
 
 	return &resource[GA, Alpha, Beta]{x: u, ver: ver}, nil
 }
+
+func (u *mutableResource[GA, Alpha, Beta]) Clone() (MutableResource[GA, Alpha, Beta], error) {
+	return u.clone()
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) clone() (*mutableResource[GA, Alpha, Beta], error) {
+	ret := &mutableResource[GA, Alpha, Beta]{
+		copierOptions: append([]copierOption{}, u.copierOptions...),
+		typeTrait:     u.typeTrait,
+		options:       u.options,
+		authored:      u.authored,
+	}
+	if u.resourceID != nil {
+		id := *u.resourceID
+		if id.Key != nil {
+			key := *id.Key
+			id.Key = &key
+		}
+		ret.resourceID = &id
+	}
+	if err := deepCopy(&ret.ga, &u.ga); err != nil {
+		return nil, fmt.Errorf("Clone: %w", err)
+	}
+	if err := deepCopy(&ret.alpha, &u.alpha); err != nil {
+		return nil, fmt.Errorf("Clone: %w", err)
+	}
+	if err := deepCopy(&ret.beta, &u.beta); err != nil {
+		return nil, fmt.Errorf("Clone: %w", err)
+	}
+	for i := range u.errors {
+		for _, mf := range u.errors[i].missingFields {
+			ret.errors[i].missingFields = append(ret.errors[i].missingFields, missingFieldOnCopy{
+				Path:  append(Path{}, mf.Path...),
+				Value: mf.Value,
+			})
+		}
+	}
+	for _, fp := range u.dirty.provenance() {
+		ret.dirty.set(fp)
+	}
+	return ret, nil
+}
+
+// deepCopy src to dest, which must be a zero value. The top-level
+// ServerResponse is not copied by the copier and is assigned directly.
+func deepCopy[T any](dest, src *T) error {
+	if err := newCopier().do(reflect.ValueOf(dest), reflect.ValueOf(src)); err != nil {
+		return err
+	}
+	dv := reflect.ValueOf(dest).Elem()
+	if dv.Kind() != reflect.Struct {
+		return nil
+	}
+	if f := dv.FieldByName("ServerResponse"); f.IsValid() && f.CanSet() {
+		f.Set(reflect.ValueOf(src).Elem().FieldByName("ServerResponse"))
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PatchFields are the fields to send in a PATCH request to make the changes
+// described by a DiffResult.
+type PatchFields struct {
+	// Mask are the JSON paths of the fields that changed, e.g.
+	// "logConfig.enable". This is the format of the updateMask parameter.
+	// Lists and maps are replaced as a whole so the path stops at the field
+	// containing the list or map.
+	Mask []string
+	// ForceSendFields are the top-level fields that changed to a zero
+	// value. These must be sent explicitly as zero values are omitted from
+	// the request.
+	ForceSendFields []string
+	// NullFields are the top-level fields that changed to nil. These must
+	// be sent as null to clear the value.
+	NullFields []string
+}
+
+// PatchFieldsFromDiff returns the PatchFields for d, a diff of a resource
+// against want, the resource with the desired values. Only the fields in the
+// diff are included, resulting in a minimal PATCH request.
+//
+// Note: ForceSendFields and NullFields are computed for the top-level fields
+// only. Zero-values in nested structs must be handled by the caller.
+func PatchFieldsFromDiff[T any](d *DiffResult, want *T) (*PatchFields, error) {
+	if want == nil {
+		return nil, fmt.Errorf("PatchFieldsFromDiff: want is nil")
+	}
+	wantV := reflect.ValueOf(want).Elem()
+	if wantV.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("PatchFieldsFromDiff: %T is not a struct", want)
+	}
+
+	ret := &PatchFields{}
+	seenMask := map[string]bool{}
+	seenField := map[string]bool{}
+	for _, item := range d.Items {
+		mask, field, err := jsonPath(wantV.Type(), item.Path)
+		if err != nil {
+			return nil, fmt.Errorf("PatchFieldsFromDiff: %w", err)
+		}
+		if !seenMask[mask] {
+			seenMask[mask] = true
+			ret.Mask = append(ret.Mask, mask)
+		}
+		if seenField[field] {
+			continue
+		}
+		seenField[field] = true
+		fv := wantV.FieldByName(field)
+		if !fv.IsZero() {
+			continue
+		}
+		switch fv.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			ret.NullFields = append(ret.NullFields, field)
+		default:
+			ret.ForceSendFields = append(ret.ForceSendFields, field)
+		}
+	}
+	sort.Strings(ret.Mask)
+	sort.Strings(ret.ForceSendFields)
+	sort.Strings(ret.NullFields)
+	return ret, nil
+}
+
+// Apply adds the ForceSendFields and NullFields to obj, which must be a
+// pointer to a struct with the standard metafields.
+func (pf *PatchFields) Apply(obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("PatchFields.Apply: %T is not a pointer to a struct", obj)
+	}
+	for _, mf := range []struct {
+		name   string
+		fields []string
+	}{
+		{"ForceSendFields", pf.ForceSendFields},
+		{"NullFields", pf.NullFields},
+	} {
+		fv := v.Elem().FieldByName(mf.name)
+		if !isSliceOfStringV(fv) {
+			return fmt.Errorf("PatchFields.Apply: %T does not have %s", obj, mf.name)
+		}
+		l := fv.Interface().([]string)
+		for _, f := range mf.fields {
+			if !containsString(l, f) {
+				l = append(l, f)
+			}
+		}
+		fv.Set(reflect.ValueOf(l))
+	}
+	return nil
+}
+
+func containsString(l []string, s string) bool {
+	for _, x := range l {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonPath converts p to the dotted JSON path for the type t. Returns the
+// path and the name of the top-level field.
+func jsonPath(t reflect.Type, p Path) (string, string, error) {
+	var (
+		names    []string
+		topLevel string
+	)
+loop:
+	for _, elem := range p {
+		switch elem[0] {
+		case pathPointer:
+			continue
+		case pathSliceIndex, pathMapIndex:
+			break loop
+		case pathField:
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct {
+				return "", "", fmt.Errorf("path %s: %s is not a struct", p, t)
+			}
+			sf, ok := t.FieldByName(elem[1:])
+			if !ok {
+				return "", "", fmt.Errorf("path %s: no field %q in %s", p, elem[1:], t)
+			}
+			if topLevel == "" {
+				topLevel = sf.Name
+			}
+			names = append(names, jsonName(sf))
+			t = sf.Type
+		default:
+			return "", "", fmt.Errorf("path %s: invalid element %q", p, elem)
+		}
+	}
+	if len(names) == 0 {
+		return "", "", fmt.Errorf("path %s does not refer to a field", p)
+	}
+	return strings.Join(names, "."), topLevel, nil
+}
+
+// jsonName returns the name of the field in JSON from the struct tag.
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
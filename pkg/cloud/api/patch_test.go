@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPatchFieldsFromDiff(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I  int      `json:"i,omitempty"`
+		LS []string `json:"ls,omitempty"`
+	}
+	type st struct {
+		I               int               `json:"i,omitempty"`
+		S               string            `json:"s,omitempty"`
+		St              sti               `json:"st,omitempty"`
+		PSt             *sti              `json:"pst,omitempty"`
+		LS              []string          `json:"ls,omitempty"`
+		M               map[string]string `json:"m,omitempty"`
+		NoTag           int
+		ForceSendFields []string `json:"-"`
+		NullFields      []string `json:"-"`
+	}
+
+	for _, tc := range []struct {
+		name string
+		a    st
+		b    st
+		want PatchFields
+	}{
+		{
+			name: "no diff",
+			a:    st{I: 1},
+			b:    st{I: 1},
+		},
+		{
+			name: "basic",
+			a:    st{I: 1, S: "a"},
+			b:    st{I: 2, S: "b"},
+			want: PatchFields{Mask: []string{"i", "s"}},
+		},
+		{
+			name: "zero value",
+			a:    st{I: 1},
+			b:    st{},
+			want: PatchFields{Mask: []string{"i"}, ForceSendFields: []string{"I"}},
+		},
+		{
+			name: "nested",
+			a:    st{St: sti{I: 1}, PSt: &sti{I: 1}},
+			b:    st{St: sti{I: 2}, PSt: &sti{I: 2}},
+			want: PatchFields{Mask: []string{"pst.i", "st.i"}},
+		},
+		{
+			name: "nil pointer",
+			a:    st{PSt: &sti{I: 1}},
+			b:    st{},
+			want: PatchFields{Mask: []string{"pst"}, NullFields: []string{"PSt"}},
+		},
+		{
+			name: "list",
+			a:    st{LS: []string{"a", "b"}, St: sti{LS: []string{"a"}}},
+			b:    st{LS: []string{"a", "c"}, St: sti{LS: []string{"b"}}},
+			want: PatchFields{Mask: []string{"ls", "st.ls"}},
+		},
+		{
+			name: "nil list",
+			a:    st{LS: []string{"a"}},
+			b:    st{},
+			want: PatchFields{Mask: []string{"ls"}, NullFields: []string{"LS"}},
+		},
+		{
+			name: "map",
+			a:    st{M: map[string]string{"a": "1", "b": "2"}},
+			b:    st{M: map[string]string{"a": "2", "b": "3"}},
+			want: PatchFields{Mask: []string{"m"}},
+		},
+		{
+			name: "no json tag",
+			a:    st{NoTag: 1},
+			b:    st{NoTag: 2},
+			want: PatchFields{Mask: []string{"NoTag"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := diff(&tc.a, &tc.b, nil)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			got, err := PatchFieldsFromDiff(d, &tc.b)
+			if err != nil {
+				t.Fatalf("PatchFieldsFromDiff() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(got, &tc.want); diff != "" {
+				t.Errorf("PatchFieldsFromDiff() diff (-got,+want): %s", diff)
+			}
+
+			obj := tc.b
+			if err := got.Apply(&obj); err != nil {
+				t.Fatalf("Apply() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(obj.ForceSendFields, tc.want.ForceSendFields); diff != "" {
+				t.Errorf("ForceSendFields diff (-got,+want): %s", diff)
+			}
+			if diff := cmp.Diff(obj.NullFields, tc.want.NullFields); diff != "" {
+				t.Errorf("NullFields diff (-got,+want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestPatchFieldsFromDiffErrors(t *testing.T) {
+	t.Parallel()
+
+	type st struct{ I int }
+	for _, tc := range []struct {
+		name string
+		d    *DiffResult
+	}{
+		{
+			name: "no such field",
+			d:    &DiffResult{Items: []DiffItem{{Path: Path{}.Pointer().Field("X")}}},
+		},
+		{
+			name: "not a field",
+			d:    &DiffResult{Items: []DiffItem{{Path: Path{}.Pointer()}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := PatchFieldsFromDiff(tc.d, &st{}); err == nil {
+				t.Error("PatchFieldsFromDiff() = nil, want error")
+			}
+		})
+	}
+}
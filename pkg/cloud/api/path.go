@@ -31,6 +31,10 @@ import (
 // - ":" is a map key
 // - "*" is a pointer deref.
 // - "#" is all array elements reference
+//
+// Wildcard elements (see AnySliceIndex() and AnyMapIndex()) are interpreted by
+// Match(), MatchAny() and HasPrefix(). FieldPath() constructs a Path from a
+// field name such as "Backends[*].Group".
 type Path []string
 
 const (
@@ -70,6 +74,14 @@ func (p Path) MapIndex(k any) Path {
 	return append(p, fmt.Sprintf("%c%v", pathMapIndex, k))
 }
 
+// parent returns the path without the last element.
+func (p Path) parent() Path {
+	if len(p) == 0 {
+		return p
+	}
+	return append(Path{}, p[:len(p)-1]...)
+}
+
 // Pointer returns the path extended with a pointer dereference.
 func (p Path) Pointer() Path {
 	return append(p, string(pathPointer))
@@ -165,6 +177,119 @@ func isArrayIndex(path string) bool {
 	return path[0] == pathSliceIndex
 }
 
+// MatchAny returns true if p matches any of the patterns (see Match()). This
+// can be used with wildcard patterns to check a Path against a list of fields
+// to ignore.
+func (p Path) MatchAny(patterns ...Path) bool {
+	for _, pattern := range patterns {
+		if p.Match(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldPath returns the Path for a field of type T given in dotted notation,
+// e.g. "Backends[*].Group" for compute.BackendService. The Path starts with a
+// pointer dereference (i.e. it refers to a field of *T) and the pointer
+// dereferences along the way are added as needed. Indices are given in
+// brackets after a field name:
+//
+//   - "[*]" (or "[]") matches any element of a slice or any key of a map.
+//   - "[N]" is the slice index N.
+//   - "[key]" is the map key "key".
+func FieldPath[T any](field string) (Path, error) {
+	return resolveFieldPath(reflect.TypeOf((*T)(nil)), field)
+}
+
+// resolveFieldPath converts the dotted field name to a Path for the type t,
+// adding the pointer dereferences and indices.
+func resolveFieldPath(t reflect.Type, field string) (Path, error) {
+	var p Path
+	deref := func() {
+		for t.Kind() == reflect.Pointer {
+			p = p.Pointer()
+			t = t.Elem()
+		}
+	}
+	elements, err := splitFieldPath(field)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range elements {
+		deref()
+		if t.Kind() != reflect.Struct {
+			return nil, newError(ErrorCodeTypeMismatch, p, "%s: %s is not a struct (%s)", field, p, t)
+		}
+		sf, ok := t.FieldByName(e.name)
+		if !ok || e.name == "" {
+			return nil, newError(ErrorCodeMissingField, p.Field(e.name), "%s: no field %q in %s", field, e.name, t)
+		}
+		p = p.Field(e.name)
+		t = sf.Type
+		if !e.hasIndex {
+			continue
+		}
+		deref()
+		switch {
+		case t.Kind() == reflect.Slice && (e.index == "" || e.index == "*"):
+			p = p.AnySliceIndex()
+		case t.Kind() == reflect.Slice:
+			i, err := strconv.Atoi(e.index)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("%s: invalid slice index %q for %s", field, e.index, p)
+			}
+			p = p.Index(i)
+		case t.Kind() == reflect.Map && (e.index == "" || e.index == "*"):
+			p = p.AnyMapIndex()
+		case t.Kind() == reflect.Map:
+			p = p.MapIndex(e.index)
+		default:
+			return nil, newError(ErrorCodeTypeMismatch, p, "%s: %s is not a slice or map (%s)", field, p, t)
+		}
+		t = t.Elem()
+	}
+	return p, nil
+}
+
+type fieldPathElement struct {
+	name     string
+	hasIndex bool
+	index    string
+}
+
+// splitFieldPath splits "A.B[x].C" into its elements. Map keys in brackets may
+// contain '.'.
+func splitFieldPath(field string) ([]fieldPathElement, error) {
+	var ret []fieldPathElement
+	for rest := field; ; {
+		var e fieldPathElement
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			e.name = rest
+			return append(ret, e), nil
+		}
+		e.name = rest[:end]
+		if rest[end] == '[' {
+			closing := strings.IndexByte(rest[end:], ']')
+			if closing < 0 {
+				return nil, fmt.Errorf("%s: missing ']'", field)
+			}
+			e.hasIndex = true
+			e.index = rest[end+1 : end+closing]
+			end += closing + 1
+		}
+		ret = append(ret, e)
+		if end == len(rest) {
+			return ret, nil
+		}
+		if rest[end] != '.' {
+			return nil, fmt.Errorf("%s: expected '.' after index", field)
+		}
+		rest = rest[end+1:]
+	}
+}
+
 // String implements Stringer.
 func (p Path) String() string {
 	return strings.Join(p, "")
@@ -177,27 +302,27 @@ func (p Path) ResolveType(t reflect.Type) (reflect.Type, error) {
 		switch x[0] {
 		case pathField:
 			if t.Kind() != reflect.Struct {
-				return nil, fmt.Errorf("at %s element %d, expected struct, got %s", p, i, t)
+				return nil, newError(ErrorCodeTypeMismatch, p[:i+1], "at %s element %d, expected struct, got %s", p, i, t)
 			}
 			fieldName := x[1:]
 			sf, ok := t.FieldByName(fieldName)
 			if !ok {
-				return nil, fmt.Errorf("at %s element %d, no field named %q", p, i, fieldName)
+				return nil, newError(ErrorCodeMissingField, p[:i+1], "at %s element %d, no field named %q", p, i, fieldName)
 			}
 			t = sf.Type
 		case pathSliceIndex:
 			if t.Kind() != reflect.Slice {
-				return nil, fmt.Errorf("at %s element %d, expected slice, got %s", p, i, t)
+				return nil, newError(ErrorCodeTypeMismatch, p[:i+1], "at %s element %d, expected slice, got %s", p, i, t)
 			}
 			t = t.Elem()
 		case pathMapIndex:
 			if t.Kind() != reflect.Map {
-				return nil, fmt.Errorf("at %s element %d, expected map, got %s", p, i, t)
+				return nil, newError(ErrorCodeTypeMismatch, p[:i+1], "at %s element %d, expected map, got %s", p, i, t)
 			}
 			t = t.Elem()
 		case pathPointer:
 			if t.Kind() != reflect.Pointer {
-				return nil, fmt.Errorf("at %s element %d, expected pointer, got %s", p, i, t)
+				return nil, newError(ErrorCodeTypeMismatch, p[:i+1], "at %s element %d, expected pointer, got %s", p, i, t)
 			}
 			t = t.Elem()
 		default:
@@ -142,6 +142,79 @@ func TestPathHasPrefix(t *testing.T) {
 	}
 }
 
+func TestPathMatchAny(t *testing.T) {
+	t.Parallel()
+
+	patterns := []Path{
+		Path{}.Pointer().Field("Backends").AnySliceIndex().Pointer().Field("Group"),
+		Path{}.Pointer().Field("Labels").AnyMapIndex(),
+	}
+	for _, tc := range []struct {
+		p    Path
+		want bool
+	}{
+		{p: Path{}.Pointer().Field("Backends").Index(3).Pointer().Field("Group"), want: true},
+		{p: Path{}.Pointer().Field("Backends").Index(3).Pointer().Field("Mode"), want: false},
+		{p: Path{}.Pointer().Field("Labels").MapIndex("k"), want: true},
+		{p: Path{}.Pointer().Field("Labels"), want: false},
+	} {
+		if got := tc.p.MatchAny(patterns...); got != tc.want {
+			t.Errorf("%s.MatchAny(%v) = %t, want %t", tc.p, patterns, got, tc.want)
+		}
+	}
+	if (Path{}).MatchAny() {
+		t.Errorf("MatchAny() = true, want false")
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Group string
+	}
+	type st struct {
+		Name     string
+		Backends []*inner
+		Labels   map[string]string
+		M        map[string]inner
+		P        *inner
+	}
+
+	for _, tc := range []struct {
+		field   string
+		want    Path
+		wantErr bool
+	}{
+		{field: "Name", want: Path{}.Pointer().Field("Name")},
+		{field: "P.Group", want: Path{}.Pointer().Field("P").Pointer().Field("Group")},
+		{field: "Backends", want: Path{}.Pointer().Field("Backends")},
+		{field: "Backends[*].Group", want: Path{}.Pointer().Field("Backends").AnySliceIndex().Pointer().Field("Group")},
+		{field: "Backends[].Group", want: Path{}.Pointer().Field("Backends").AnySliceIndex().Pointer().Field("Group")},
+		{field: "Backends[2].Group", want: Path{}.Pointer().Field("Backends").Index(2).Pointer().Field("Group")},
+		{field: "Labels[*]", want: Path{}.Pointer().Field("Labels").AnyMapIndex()},
+		{field: "Labels[a.b]", want: Path{}.Pointer().Field("Labels").MapIndex("a.b")},
+		{field: "M[k].Group", want: Path{}.Pointer().Field("M").MapIndex("k").Field("Group")},
+		{field: "Invalid", wantErr: true},
+		{field: "Name.X", wantErr: true},
+		{field: "Name[*]", wantErr: true},
+		{field: "Backends[x]", wantErr: true},
+		{field: "Backends[-1]", wantErr: true},
+		{field: "Backends[*", wantErr: true},
+		{field: "Backends[*]Group", wantErr: true},
+		{field: "P.", wantErr: true},
+	} {
+		got, err := FieldPath[st](tc.field)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("FieldPath(%q) = %v, %v; gotErr = %t, want %t", tc.field, got, err, gotErr, tc.wantErr)
+			continue
+		}
+		if err == nil && !got.Equal(tc.want) {
+			t.Errorf("FieldPath(%q) = %s, want %s", tc.field, got, tc.want)
+		}
+	}
+}
+
 func TestResolveType(t *testing.T) {
 	t.Parallel()
 
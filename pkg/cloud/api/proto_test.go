@@ -0,0 +1,168 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/runtime/protoimpl"
+)
+
+// The proto* types have the same shape as structs generated by
+// protoc-gen-go.
+
+type protoTestElem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port *int32
+}
+
+func (*protoTestElem) ProtoReflect() protoreflect.Message { return nil }
+
+type protoTestGA struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     *string
+	SelfLink *string
+	Port     *int32
+	Elem     *protoTestElem
+	Labels   map[string]string
+}
+
+func (*protoTestGA) ProtoReflect() protoreflect.Message { return nil }
+
+type protoTestAlpha struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      *string
+	SelfLink  *string
+	Port      *int32
+	Elem      *protoTestElem
+	Labels    map[string]string
+	AlphaOnly *string
+}
+
+func (*protoTestAlpha) ProtoReflect() protoreflect.Message { return nil }
+
+func ptrTo[T any](x T) *T { return &x }
+
+func TestResourceProto(t *testing.T) {
+	t.Parallel()
+
+	tt := &TypeTraitFuncs[protoTestGA, protoTestAlpha, PlaceholderType]{
+		FieldTraitsF: func(meta.Version) *FieldTraits {
+			dt := NewFieldTraits()
+			dt.NonZeroValue(Path{}.Pointer().Field("Port"))
+			return dt
+		},
+	}
+	newRes := func() MutableResource[protoTestGA, protoTestAlpha, PlaceholderType] {
+		return NewResource[protoTestGA, protoTestAlpha, PlaceholderType](&cloud.ResourceID{
+			ProjectID: "proj-1",
+			Resource:  "st",
+			Key:       meta.GlobalKey("obj-1"),
+		}, tt)
+	}
+	ignore := cmpopts.IgnoreUnexported(protoTestGA{}, protoTestAlpha{}, protoTestElem{})
+
+	res := newRes()
+	if err := res.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+	// Port is required; presence is given by a non-nil pointer.
+	if err := res.Access(func(x *protoTestGA) {}); err == nil {
+		t.Errorf("Access() = nil, want error (Port is not set)")
+	}
+	if err := res.Access(func(x *protoTestGA) {
+		x.Port = ptrTo[int32](0)
+		x.Elem = &protoTestElem{Port: ptrTo[int32](80)}
+		x.Labels = map[string]string{"a": "b"}
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if err := res.AccessAlpha(func(x *protoTestAlpha) { x.AlphaOnly = ptrTo("x") }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+
+	wantGA := &protoTestGA{
+		Name:   ptrTo("obj-1"),
+		Port:   ptrTo[int32](0),
+		Elem:   &protoTestElem{Port: ptrTo[int32](80)},
+		Labels: map[string]string{"a": "b"},
+	}
+	ga, err := res.ToGA()
+	if diff := cmp.Diff(ga, wantGA, ignore); diff != "" {
+		t.Errorf("ToGA() -got,+want: %s", diff)
+	}
+	var convErr *ConversionError
+	if !errors.As(err, &convErr) || len(convErr.MissingFields) != 1 {
+		t.Errorf("ToGA() = %v, want ConversionError for AlphaOnly", err)
+	}
+	alpha, err := res.ToAlpha()
+	if err != nil {
+		t.Fatalf("ToAlpha() = %v, want nil", err)
+	}
+	wantAlpha := &protoTestAlpha{
+		Name:      ptrTo("obj-1"),
+		Port:      ptrTo[int32](0),
+		Elem:      &protoTestElem{Port: ptrTo[int32](80)},
+		Labels:    map[string]string{"a": "b"},
+		AlphaOnly: ptrTo("x"),
+	}
+	if diff := cmp.Diff(alpha, wantAlpha, ignore); diff != "" {
+		t.Errorf("ToAlpha() -got,+want: %s", diff)
+	}
+
+	// Diff between frozen resources.
+	r1, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	res2 := newRes()
+	if err := res2.AccessAlpha(func(x *protoTestAlpha) {
+		x.Port = ptrTo[int32](0)
+		x.Elem = &protoTestElem{Port: ptrTo[int32](81)}
+		x.Labels = map[string]string{"a": "b"}
+		x.AlphaOnly = ptrTo("x")
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	r2, err := res2.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	d, err := r1.Diff(r2)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	wantPath := Path{}.Pointer().Field("Elem").Pointer().Field("Port").Pointer()
+	if len(d.Items) != 1 || !d.Items[0].Path.Equal(wantPath) {
+		t.Errorf("Diff() = %+v, want one item at %s", d.Items, wantPath)
+	}
+}
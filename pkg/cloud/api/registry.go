@@ -0,0 +1,120 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// SchemaChecker is a resource that can check its schema (see
+// MutableResource.CheckSchema()).
+type SchemaChecker interface {
+	CheckSchema() error
+}
+
+var schemaRegistry = struct {
+	lock    sync.Mutex
+	entries map[string]SchemaChecker
+}{entries: map[string]SchemaChecker{}}
+
+// RegisterSchema adds the resource to the set of resources validated by
+// ValidateAll(). name identifies the resource type in errors (e.g.
+// "compute/backendServices"). This is meant to be called from init() with an
+// instance of the MutableResource for the type:
+//
+//	func init() {
+//		api.RegisterSchema("compute/addresses", NewMutableAddress("p", meta.GlobalKey("k")))
+//	}
+//
+// This panics if the name has already been registered.
+func RegisterSchema(name string, r SchemaChecker) {
+	schemaRegistry.lock.Lock()
+	defer schemaRegistry.lock.Unlock()
+
+	if _, ok := schemaRegistry.entries[name]; ok {
+		panic(fmt.Sprintf("api.RegisterSchema: %q registered twice", name))
+	}
+	schemaRegistry.entries[name] = r
+}
+
+// RegisteredSchemas returns the sorted list of names in the schema registry.
+func RegisteredSchemas() []string {
+	schemaRegistry.lock.Lock()
+	defer schemaRegistry.lock.Unlock()
+
+	var ret []string
+	for name := range schemaRegistry.entries {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// ValidateAll checks the schema of all of the resources registered with
+// RegisterSchema(). For resources created by NewResource(), this also checks
+// the FieldTraits for each version. All violations are returned (see
+// errors.Join); the result is nil if there are none.
+//
+// This should be called from a unit test (or from the program init).
+func ValidateAll() error {
+	var errs []error
+	for _, name := range RegisteredSchemas() {
+		schemaRegistry.lock.Lock()
+		r := schemaRegistry.entries[name]
+		schemaRegistry.lock.Unlock()
+
+		if err := r.CheckSchema(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		if tc, ok := r.(interface{ checkTraits() []error }); ok {
+			for _, err := range tc.checkTraits() {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkTraits checks the FieldTraits of each version against the type for
+// that version.
+func (u *mutableResource[GA, Alpha, Beta]) checkTraits() []error {
+	types := map[meta.Version]reflect.Type{
+		meta.VersionGA: reflect.TypeOf(&u.ga),
+	}
+	if !isPlaceholderType(u.alpha) {
+		types[meta.VersionAlpha] = reflect.TypeOf(&u.alpha)
+	}
+	if !isPlaceholderType(u.beta) {
+		types[meta.VersionBeta] = reflect.TypeOf(&u.beta)
+	}
+	var errs []error
+	for _, ver := range meta.AllVersions {
+		if _, ok := types[ver]; !ok {
+			continue
+		}
+		if err := fieldTraits(u.typeTrait, ver).CheckSchema(types[ver]); err != nil {
+			errs = append(errs, fmt.Errorf("FieldTraits(%s): %w", ver, err))
+		}
+	}
+	return errs
+}
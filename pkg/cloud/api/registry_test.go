@@ -0,0 +1,98 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+)
+
+type registryTestTrait struct {
+	BaseTypeTrait[registryTestSt, registryTestSt, registryTestSt]
+	path Path
+}
+
+func (tt *registryTestTrait) FieldTraits(meta.Version) *FieldTraits {
+	dt := NewFieldTraits()
+	dt.OutputOnly(tt.path)
+	return dt
+}
+
+type registryTestSt struct {
+	Name, SelfLink              string
+	I                           int
+	NullFields, ForceSendFields []string
+	ServerResponse              googleapi.ServerResponse
+}
+
+type registryTestInvalidSt struct {
+	C chan int
+}
+
+func TestValidateAll(t *testing.T) {
+	// Not parallel: this modifies the global registry.
+	registryTestReset := func() {
+		schemaRegistry.lock.Lock()
+		schemaRegistry.entries = map[string]SchemaChecker{}
+		schemaRegistry.lock.Unlock()
+	}
+	registryTestReset()
+	defer registryTestReset()
+
+	RegisterSchema("test/ok", newTestResource[registryTestSt, registryTestSt, registryTestSt](
+		&registryTestTrait{path: Path{}.Pointer().Field("I")}))
+	if err := ValidateAll(); err != nil {
+		t.Fatalf("ValidateAll() = %v, want nil", err)
+	}
+
+	RegisterSchema("test/badSchema", newTestResource[registryTestInvalidSt, PlaceholderType, PlaceholderType](nil))
+	RegisterSchema("test/badTraits", newTestResource[registryTestSt, registryTestSt, registryTestSt](
+		&registryTestTrait{path: Path{}.Pointer().Field("Invalid")}))
+
+	err := ValidateAll()
+	if err == nil {
+		t.Fatal("ValidateAll() = nil, want error")
+	}
+	for _, s := range []string{
+		"test/badSchema:",
+		"test/badTraits: FieldTraits(ga)",
+		"test/badTraits: FieldTraits(alpha)",
+		"test/badTraits: FieldTraits(beta)",
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("ValidateAll() = %v, want error containing %q", err, s)
+		}
+	}
+	if strings.Contains(err.Error(), "test/ok") {
+		t.Errorf("ValidateAll() = %v, want no errors for test/ok", err)
+	}
+
+	want := []string{"test/badSchema", "test/badTraits", "test/ok"}
+	if got := RegisteredSchemas(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("RegisteredSchemas() = %v, want %v", got, want)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("RegisterSchema() twice did not panic")
+		}
+	}()
+	RegisterSchema("test/ok", newTestResource[registryTestSt, registryTestSt, registryTestSt](nil))
+}
@@ -18,6 +18,7 @@ package api
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -36,14 +37,33 @@ type Resource[GA any, Alpha any, Beta any] interface {
 	ToAlpha() (*Alpha, error)
 	ToBeta() (*Beta, error)
 
+	// LossyFields reports the fields that would be dropped when
+	// converting to each version. See MutableResource.LossyFields().
+	LossyFields() map[meta.Version][]MissingField
+
 	// Diff obtains the difference between this resource and
 	// other, taking into account the versions of the resources
 	// being compared. Cross Alpha and Beta comparisons are not
 	// currently supported.
 	Diff(other Resource[GA, Alpha, Beta]) (*DiffResult, error)
 
-	// Clone returns an exact structural copy of this resource.
-	// Clone() Resource[GA, Alpha, Beta] XXX
+	// DirtyFields returns the paths of the fields that were explicitly set
+	// on the MutableResource before it was frozen. See
+	// MutableResource.DirtyFields().
+	DirtyFields() []Path
+	// IsDirty returns true if the field at p was explicitly set. See
+	// MutableResource.IsDirty().
+	IsDirty(p Path) bool
+	// Provenance returns the version that last wrote each of the
+	// DirtyFields(). See MutableResource.Provenance().
+	Provenance() []FieldProvenance
+	// ProvenanceOf returns the most recent write to the field at p. See
+	// MutableResource.ProvenanceOf().
+	ProvenanceOf(p Path) (fp FieldProvenance, ok bool)
+
+	// Clone returns an exact structural copy of this resource. The copy
+	// does not share any slices, maps or pointers with the original.
+	Clone() (Resource[GA, Alpha, Beta], error)
 }
 
 type resource[GA any, Alpha any, Beta any] struct {
@@ -57,6 +77,24 @@ func (obj *resource[GA, Alpha, Beta]) ResourceID() *cloud.ResourceID { return ob
 func (obj *resource[GA, Alpha, Beta]) ToGA() (*GA, error)            { return obj.x.ToGA() }
 func (obj *resource[GA, Alpha, Beta]) ToAlpha() (*Alpha, error)      { return obj.x.ToAlpha() }
 func (obj *resource[GA, Alpha, Beta]) ToBeta() (*Beta, error)        { return obj.x.ToBeta() }
+func (obj *resource[GA, Alpha, Beta]) DirtyFields() []Path           { return obj.x.DirtyFields() }
+func (obj *resource[GA, Alpha, Beta]) IsDirty(p Path) bool           { return obj.x.IsDirty(p) }
+func (obj *resource[GA, Alpha, Beta]) Provenance() []FieldProvenance { return obj.x.Provenance() }
+func (obj *resource[GA, Alpha, Beta]) ProvenanceOf(p Path) (FieldProvenance, bool) {
+	return obj.x.ProvenanceOf(p)
+}
+func (obj *resource[GA, Alpha, Beta]) LossyFields() map[meta.Version][]MissingField {
+	return obj.x.LossyFields()
+}
+
+// Clone implements Resource.
+func (obj *resource[GA, Alpha, Beta]) Clone() (Resource[GA, Alpha, Beta], error) {
+	x, err := obj.x.clone()
+	if err != nil {
+		return nil, err
+	}
+	return &resource[GA, Alpha, Beta]{x: x, ver: obj.ver}, nil
+}
 
 // Diff implements Resource.
 func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*DiffResult, error) {
@@ -67,17 +105,17 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 	case obj.Version() == meta.VersionGA && other.Version() == meta.VersionGA:
 		aObj, _ := obj.ToGA()
 		bObj, _ := other.ToGA()
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionGA))
+		return diff(aObj, bObj, fieldTraits(obj.x.typeTrait, meta.VersionGA))
 	// cmp(Alpha, Alpha)
 	case obj.Version() == meta.VersionAlpha && other.Version() == meta.VersionAlpha:
 		aObj, _ := obj.ToAlpha()
 		bObj, _ := other.ToAlpha()
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionAlpha))
+		return diff(aObj, bObj, fieldTraits(obj.x.typeTrait, meta.VersionAlpha))
 	// cmp(Beta, Beta)
 	case obj.Version() == meta.VersionBeta && other.Version() == meta.VersionBeta:
 		aObj, _ := obj.ToBeta()
 		bObj, _ := other.ToBeta()
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionBeta))
+		return diff(aObj, bObj, fieldTraits(obj.x.typeTrait, meta.VersionBeta))
 
 	// GA => Alpha, GA => Beta should be safe and supported with a conversion.
 	//
@@ -87,26 +125,26 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 	case obj.Version() == meta.VersionAlpha && other.Version() == meta.VersionGA:
 		aObj, err := obj.ToAlpha()
 		if err != nil {
-			return nil, fmt.Errorf("Resource.Diff: %s", err)
+			return nil, fmt.Errorf("Resource.Diff: %w", err)
 		}
 		bObj, err := other.ToAlpha()
 		if err != nil {
-			return nil, fmt.Errorf("Resource.Diff: %s", err)
+			return nil, fmt.Errorf("Resource.Diff: %w", err)
 		}
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionAlpha))
+		return diff(aObj, bObj, fieldTraits(obj.x.typeTrait, meta.VersionAlpha))
 	// cmp(GA, Beta), cmp(Beta, GA): convert to Beta, then compare.
 	case obj.Version() == meta.VersionGA && other.Version() == meta.VersionBeta:
 		fallthrough
 	case obj.Version() == meta.VersionBeta && other.Version() == meta.VersionGA:
 		aObj, err := obj.ToBeta()
 		if err != nil {
-			return nil, fmt.Errorf("Resource.Diff: %s", err)
+			return nil, fmt.Errorf("Resource.Diff: %w", err)
 		}
 		bObj, err := other.ToBeta()
 		if err != nil {
-			return nil, fmt.Errorf("Resource.Diff: %s", err)
+			return nil, fmt.Errorf("Resource.Diff: %w", err)
 		}
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionBeta))
+		return diff(aObj, bObj, fieldTraits(obj.x.typeTrait, meta.VersionBeta))
 
 	// Comparison between Alpha/Beta is not supported right now. This probably
 	// can work with some manual conversion logic.
@@ -119,6 +157,161 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 	return nil, fmt.Errorf("invalid versions (got a.Version=%s, b.Version=%s)", obj.Version(), other.Version())
 }
 
+// CopyWithID returns a copy of r with the ResourceID id. The .Name field of
+// the copy is set from id. If rewrite is non-nil, it is called for each string
+// value in the resource (struct fields, slice elements and map values) and the
+// value is replaced with the result. The copy has the same Version as r.
+func CopyWithID[GA any, Alpha any, Beta any](
+	r Resource[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	rewrite func(p Path, s string) string,
+) (Resource[GA, Alpha, Beta], error) {
+	obj, ok := r.(*resource[GA, Alpha, Beta])
+	if !ok {
+		return nil, fmt.Errorf("CopyWithID: unsupported Resource type %T", r)
+	}
+	ret := NewResource(id, obj.x.typeTrait)
+
+	// Copy r into ret. dest is the native version of the copy that will be
+	// rewritten.
+	var (
+		dest any
+		err  error
+	)
+	switch r.Version() {
+	case meta.VersionGA:
+		var raw *GA
+		if raw, err = r.ToGA(); err == nil {
+			err = ret.Set(raw)
+		}
+		dest = &ret.ga
+	case meta.VersionAlpha:
+		var raw *Alpha
+		if raw, err = r.ToAlpha(); err == nil {
+			err = ret.SetAlpha(raw)
+		}
+		dest = &ret.alpha
+	case meta.VersionBeta:
+		var raw *Beta
+		if raw, err = r.ToBeta(); err == nil {
+			err = ret.SetBeta(raw)
+		}
+		dest = &ret.beta
+	default:
+		return nil, fmt.Errorf("CopyWithID: invalid version %q", r.Version())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CopyWithID: %w", err)
+	}
+
+	v := reflect.ValueOf(dest)
+	if nf := v.Elem().FieldByName("Name"); nf.IsValid() && isStringOrPtrToStringT(nf.Type()) {
+		setStringOrPtr(nf, id.Key.Name)
+	}
+	if rewrite != nil {
+		acc := newAcceptorFuncs()
+		acc.onBasicF = func(p Path, v reflect.Value) (bool, error) {
+			if v.Kind() == reflect.String && v.CanSet() {
+				v.SetString(rewrite(p, v.String()))
+			}
+			return true, nil
+		}
+		if err := visit(v, acc); err != nil {
+			return nil, fmt.Errorf("CopyWithID: %w", err)
+		}
+	}
+	// Propagate the changes to the other versions.
+	if err := ret.postAccess(r.Version(), postAccessSkipValidation); err != nil {
+		return nil, fmt.Errorf("CopyWithID: %w", err)
+	}
+
+	return ret.Freeze()
+}
+
+// ClearOutputOnly returns a copy of r with the OutputOnly fields (as given by
+// the FieldTraits of the type) set to the zero value. Use this to send a
+// resource that was returned by the Cloud (e.g. to create it again). The copy
+// has the same Version as r.
+func ClearOutputOnly[GA any, Alpha any, Beta any](r Resource[GA, Alpha, Beta]) (Resource[GA, Alpha, Beta], error) {
+	obj, ok := r.(*resource[GA, Alpha, Beta])
+	if !ok {
+		return nil, fmt.Errorf("ClearOutputOnly: unsupported Resource type %T", r)
+	}
+	ret := NewResource(obj.x.resourceID, obj.x.typeTrait)
+
+	var (
+		dest any
+		err  error
+	)
+	switch r.Version() {
+	case meta.VersionGA:
+		var raw *GA
+		if raw, err = r.ToGA(); err == nil {
+			err = ret.Set(raw)
+		}
+		dest = &ret.ga
+	case meta.VersionAlpha:
+		var raw *Alpha
+		if raw, err = r.ToAlpha(); err == nil {
+			err = ret.SetAlpha(raw)
+		}
+		dest = &ret.alpha
+	case meta.VersionBeta:
+		var raw *Beta
+		if raw, err = r.ToBeta(); err == nil {
+			err = ret.SetBeta(raw)
+		}
+		dest = &ret.beta
+	default:
+		return nil, fmt.Errorf("ClearOutputOnly: invalid version %q", r.Version())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ClearOutputOnly: %w", err)
+	}
+
+	traits := fieldTraits(obj.x.typeTrait, r.Version())
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
+			return false, nil
+		}
+		for i := 0; i < v.NumField(); i++ {
+			ft := v.Type().Field(i)
+			if skipField(v.Type(), ft) || traits.fieldType(p.Field(ft.Name)) != FieldTypeOutputOnly {
+				continue
+			}
+			v.Field(i).Set(reflect.Zero(ft.Type))
+			// The field must not be sent as a null or zero value either.
+			for _, name := range []string{nullFieldsName, forceSendFieldsName} {
+				mf := v.FieldByName(name)
+				if !mf.IsValid() || mf.Kind() != reflect.Slice || mf.Type().Elem().Kind() != reflect.String {
+					continue
+				}
+				kept := reflect.MakeSlice(mf.Type(), 0, mf.Len())
+				for j := 0; j < mf.Len(); j++ {
+					if fn, _ := metafieldName(mf.Index(j).String()); fn != ft.Name {
+						kept = reflect.Append(kept, mf.Index(j))
+					}
+				}
+				if kept.Len() == 0 {
+					kept = reflect.Zero(mf.Type())
+				}
+				mf.Set(kept)
+			}
+		}
+		return true, nil
+	}
+	if err := visit(reflect.ValueOf(dest), acc); err != nil {
+		return nil, fmt.Errorf("ClearOutputOnly: %w", err)
+	}
+	// Propagate the changes to the other versions.
+	if err := ret.postAccess(r.Version(), postAccessSkipValidation); err != nil {
+		return nil, fmt.Errorf("ClearOutputOnly: %w", err)
+	}
+
+	return ret.Freeze()
+}
+
 /*
 func (obj *Resource[GA, Alpha, Beta]) Clone() Resource[GA, Alpha, Beta] {
 	return &Resource[GA, Alpha, Beta]{
@@ -17,6 +17,10 @@ limitations under the License.
 package api
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -545,6 +549,254 @@ func TestResourceMissingFields(t *testing.T) {
 	}
 }
 
+func TestResourceLossyFields(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		A, B            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[ga, alph, PlaceholderType](nil)
+	if err := res.Access(func(x *ga) { x.A = 10 }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	want := map[meta.Version][]MissingField{
+		meta.VersionGA:    nil,
+		meta.VersionAlpha: nil,
+	}
+	if diff := cmp.Diff(res.LossyFields(), want); diff != "" {
+		t.Errorf("LossyFields(); -got,+want: %s", diff)
+	}
+
+	if err := res.AccessAlpha(func(x *alph) { x.B = 20 }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	want = map[meta.Version][]MissingField{
+		meta.VersionGA: {
+			{Context: AlphaToGAConversion, Path: Path{}.Pointer().Field("B"), Value: 20},
+		},
+		meta.VersionAlpha: nil,
+	}
+	if diff := cmp.Diff(res.LossyFields(), want); diff != "" {
+		t.Errorf("LossyFields(); -got,+want: %s", diff)
+	}
+
+	// LossyFields() should match the error from ToGA().
+	_, err := res.ToGA()
+	var cerr *ConversionError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("ToGA() = %v, want ConversionError", err)
+	}
+	if diff := cmp.Diff(cerr.MissingFields, want[meta.VersionGA]); diff != "" {
+		t.Errorf("ToGA() MissingFields; -got,+want: %s", diff)
+	}
+
+	r, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(r.LossyFields(), res.LossyFields()); diff != "" {
+		t.Errorf("Resource.LossyFields(); -got,+want: %s", diff)
+	}
+}
+
+func TestResourceZeroValueIsSet(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Timeout         int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		Port            int
+		Desc            string
+		Id              int
+		In              *inner
+		NullFields      []string
+		ForceSendFields []string
+	}
+	tt := &TypeTraitFuncs[st, st, PlaceholderType]{
+		FieldTraitsF: func(meta.Version) *FieldTraits {
+			dt := NewFieldTraits()
+			dt.AllowZeroValue(Path{}.Pointer().Field("Desc"))
+			return dt
+		},
+	}
+	id := &cloud.ResourceID{ProjectID: "proj-1", Resource: "st", Key: meta.GlobalKey("obj-1")}
+
+	for _, tc := range []struct {
+		name string
+		opts []ResourceOption
+		want *st
+	}{
+		{
+			name: "default",
+			want: &st{Name: "obj-1", In: &inner{}},
+		},
+		{
+			name: "ZeroValueIsSet",
+			opts: []ResourceOption{ZeroValueIsSet()},
+			want: &st{
+				Name:            "obj-1",
+				In:              &inner{ForceSendFields: []string{"Timeout"}},
+				ForceSendFields: []string{"Port"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NewResource[st, st, PlaceholderType](id, tt, tc.opts...)
+			if err := res.Access(func(x *st) {
+				x.Port = 80
+				x.Desc = "abc"
+				x.In = &inner{Timeout: 10}
+			}); err != nil {
+				t.Fatalf("Access() = %v, want nil", err)
+			}
+			// Port and Timeout are intentionally set to zero. Desc has the
+			// AllowZeroValue trait. Id was never set.
+			if err := res.Access(func(x *st) {
+				x.Port = 0
+				x.Desc = ""
+				x.In.Timeout = 0
+			}); err != nil {
+				t.Fatalf("Access() = %v, want nil", err)
+			}
+			got, err := res.ToGA()
+			if err != nil {
+				t.Fatalf("ToGA() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("ToGA(); -got,+want: %s", diff)
+			}
+			// The ForceSendFields are copied to the other versions.
+			gotAlpha, _ := res.ToAlpha()
+			if diff := cmp.Diff(gotAlpha, tc.want); diff != "" {
+				t.Errorf("ToAlpha(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestResourceValidator(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		Type            string
+		Port            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	// TCP requires Port to be set.
+	validate := func(obj *st) []InvalidField {
+		if obj.Type == "TCP" && obj.Port == 0 {
+			return []InvalidField{{Path: Path{}.Pointer().Field("Port"), Msg: "required for TCP"}}
+		}
+		return nil
+	}
+	tt := &TypeTraitFuncs[st, st, st]{
+		ValidateGAF:    validate,
+		ValidateAlphaF: validate,
+	}
+
+	res := newTestResource[st, st, st](tt)
+	err := res.Access(func(x *st) { x.Type = "TCP" })
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Access() = %v, want ValidationError", err)
+	}
+	want := &ValidationError{
+		Version:       meta.VersionGA,
+		InvalidFields: []InvalidField{{Path: Path{}.Pointer().Field("Port"), Msg: "required for TCP"}},
+	}
+	if diff := cmp.Diff(verr, want); diff != "" {
+		t.Errorf("Access(); -got,+want: %s", diff)
+	}
+	if _, err := res.Freeze(); !errors.As(err, &verr) {
+		t.Errorf("Freeze() = %v, want ValidationError", err)
+	}
+
+	// Beta does not have a validator.
+	if err := res.AccessBeta(func(x *st) {}); err != nil {
+		t.Errorf("AccessBeta() = %v, want nil", err)
+	}
+	// Set*() skips validation.
+	if err := res.Set(&st{Type: "TCP"}); err != nil {
+		t.Errorf("Set() = %v, want nil", err)
+	}
+
+	if err := res.Access(func(x *st) { x.Port = 80 }); err != nil {
+		t.Errorf("Access() = %v, want nil", err)
+	}
+	if _, err := res.Freeze(); err != nil {
+		t.Errorf("Freeze() = %v, want nil", err)
+	}
+}
+
+func TestResourceAllowMissing(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		A, B, C         int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	tt := &TypeTraitFuncs[ga, alph, PlaceholderType]{
+		FieldTraitsF: func(v meta.Version) *FieldTraits {
+			dt := NewFieldTraits()
+			if v == meta.VersionAlpha {
+				dt.AllowMissing(Path{}.Pointer().Field("B"))
+			}
+			return dt
+		},
+	}
+
+	res := newTestResource[ga, alph, PlaceholderType](tt)
+	if err := res.AccessAlpha(func(x *alph) {
+		x.A = 1
+		x.B = 2
+		x.ForceSendFields = []string{"B"}
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	got, err := res.ToGA()
+	if err != nil {
+		t.Errorf("ToGA() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(got, &ga{A: 1}); diff != "" {
+		t.Errorf("ToGA(); -got,+want: %s", diff)
+	}
+
+	// C is not in the allow list.
+	if err := res.AccessAlpha(func(x *alph) { x.C = 3 }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	_, err = res.ToGA()
+	var cerr *ConversionError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("ToGA() = %v, want ConversionError", err)
+	}
+	want := []MissingField{
+		{Context: AlphaToGAConversion, Path: Path{}.Pointer().Field("C"), Value: 3},
+	}
+	if diff := cmp.Diff(cerr.MissingFields, want); diff != "" {
+		t.Errorf("ToGA() MissingFields; -got,+want: %s", diff)
+	}
+}
+
 func TestResourceMissingMetaFields(t *testing.T) {
 	t.Parallel()
 
@@ -603,6 +855,185 @@ func TestResourceMissingMetaFields(t *testing.T) {
 	}
 }
 
+func TestResourceMetaFieldsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		A, B            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	res := newTestResource[ga, alph, PlaceholderType](nil)
+
+	// A is intentionally zero in GA and must be sent in Alpha as well.
+	if err := res.Access(func(x *ga) { x.ForceSendFields = []string{"A"} }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	aResult, err := res.ToAlpha()
+	if err != nil {
+		t.Fatalf("ToAlpha() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(aResult, &alph{ForceSendFields: []string{"A"}}); diff != "" {
+		t.Errorf("ToAlpha(); -got,+want: %s", diff)
+	}
+
+	// Setting A in Alpha removes the entry from GA. The Alpha-only B is
+	// kept in Alpha.
+	if err := res.AccessAlpha(func(x *alph) {
+		x.A = 10
+		x.ForceSendFields = []string{"B"}
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	if err := res.Access(func(x *ga) { x.A = 11 }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	gaResult, _ := res.ToGA()
+	if diff := cmp.Diff(gaResult, &ga{A: 11}); diff != "" {
+		t.Errorf("ToGA(); -got,+want: %s", diff)
+	}
+	aResult, err = res.ToAlpha()
+	if err != nil {
+		t.Fatalf("ToAlpha() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(aResult, &alph{A: 11, ForceSendFields: []string{"B"}}); diff != "" {
+		t.Errorf("ToAlpha(); -got,+want: %s", diff)
+	}
+}
+
+func TestResourceVersionTraits(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		A, B            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	tt := &TypeTraitFuncs[ga, alph, PlaceholderType]{
+		FieldTraitsF: func(meta.Version) *FieldTraits {
+			dt := NewFieldTraits()
+			dt.NonZeroValue(Path{}.Pointer().Field("A"))
+			// B only exists (and is required) in Alpha.
+			dt.VersionTrait(Path{}.Pointer().Field("B"), FieldTypeNonZeroValue, meta.VersionAlpha)
+			return dt
+		},
+	}
+
+	res := newTestResource[ga, alph, PlaceholderType](tt)
+	if err := res.Access(func(x *ga) { x.A = 1 }); err != nil {
+		t.Errorf("Access() = %v, want nil", err)
+	}
+	if err := res.AccessAlpha(func(x *alph) { x.A = 1 }); err == nil {
+		t.Error("AccessAlpha() = nil, want error (B is not set)")
+	}
+	if err := res.AccessAlpha(func(x *alph) { x.B = 2 }); err != nil {
+		t.Errorf("AccessAlpha() = %v, want nil", err)
+	}
+}
+
+func TestResourceClone(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type ga struct {
+		P               *sti
+		LS              []string
+		M               map[string]string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		P               *sti
+		LS              []string
+		M               map[string]string
+		B               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[ga, alph, PlaceholderType](nil)
+	if err := res.Access(func(x *ga) {
+		x.P = &sti{I: 1}
+		x.LS = []string{"a"}
+		x.M = map[string]string{"a": "b"}
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	// B does not exist in GA and will be a conversion error.
+	if err := res.AccessAlpha(func(x *alph) { x.B = 2 }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	frozen, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+
+	mutClone, err := res.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v, want nil", err)
+	}
+	frozenClone, err := frozen.Clone()
+	if err != nil {
+		t.Fatalf("frozen.Clone() = %v, want nil", err)
+	}
+
+	wantGA := &ga{P: &sti{I: 1}, LS: []string{"a"}, M: map[string]string{"a": "b"}}
+	_, gaErr := res.ToGA()
+	wantDirty := res.DirtyFields()
+
+	// Modify the contents of the original in place.
+	if err := res.AccessAlpha(func(x *alph) {
+		x.P.I = 10
+		x.LS[0] = "z"
+		x.M["a"] = "z"
+		x.B = 0
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		r    interface {
+			ToGA() (*ga, error)
+			DirtyFields() []Path
+		}
+	}{
+		{name: "mutable", r: mutClone},
+		{name: "frozen", r: frozenClone},
+	} {
+		got, err := tc.r.ToGA()
+		if diff := cmp.Diff(got, wantGA); diff != "" {
+			t.Errorf("%s: ToGA() -got,+want: %s", tc.name, diff)
+		}
+		if diff := cmp.Diff(err, gaErr); diff != "" {
+			t.Errorf("%s: ToGA() error -got,+want: %s", tc.name, diff)
+		}
+		if diff := cmp.Diff(tc.r.DirtyFields(), wantDirty); diff != "" {
+			t.Errorf("%s: DirtyFields() -got,+want: %s", tc.name, diff)
+		}
+	}
+	if frozenClone.Version() != frozen.Version() {
+		t.Errorf("frozen.Clone().Version() = %s, want %s", frozenClone.Version(), frozen.Version())
+	}
+	if !frozenClone.ResourceID().Equal(frozen.ResourceID()) {
+		t.Errorf("frozen.Clone().ResourceID() = %v, want %v", frozenClone.ResourceID(), frozen.ResourceID())
+	}
+}
+
 func TestResourceSetX(t *testing.T) {
 	t.Parallel()
 
@@ -707,6 +1138,207 @@ func TestResourceSetX(t *testing.T) {
 	}
 }
 
+func TestResourceDirtyFields(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		I               int
+		S               string
+		StP             *sti
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	for _, tc := range []struct {
+		name      string
+		f         func(r *mutableResource[st, st, st]) error
+		want      []Path
+		wantDirty []Path
+		wantClean []Path
+	}{
+		{
+			name:      "no access",
+			f:         func(*mutableResource[st, st, st]) error { return nil },
+			wantClean: []Path{Path{}.Pointer().Field("I")},
+		},
+		{
+			name: "Access",
+			f: func(r *mutableResource[st, st, st]) error {
+				return r.Access(func(x *st) { x.I = 5 })
+			},
+			want:      []Path{Path{}.Pointer().Field("I")},
+			wantClean: []Path{Path{}.Pointer().Field("S")},
+		},
+		{
+			name: "zero value in ForceSendFields",
+			f: func(r *mutableResource[st, st, st]) error {
+				return r.Access(func(x *st) { x.ForceSendFields = []string{"I"} })
+			},
+			want:      []Path{Path{}.Pointer().Field("I")},
+			wantClean: []Path{Path{}.Pointer().Field("S")},
+		},
+		{
+			name: "nested struct",
+			f: func(r *mutableResource[st, st, st]) error {
+				return r.Access(func(x *st) { x.StP = &sti{I: 1} })
+			},
+			want: []Path{Path{}.Pointer().Field("StP")},
+			wantDirty: []Path{
+				Path{}.Pointer().Field("StP").Pointer().Field("I"),
+			},
+		},
+		{
+			name: "nested NullFields",
+			f: func(r *mutableResource[st, st, st]) error {
+				return r.Access(func(x *st) { x.StP = &sti{NullFields: []string{"I"}} })
+			},
+			want: []Path{
+				Path{}.Pointer().Field("StP"),
+				Path{}.Pointer().Field("StP").Pointer().Field("I"),
+			},
+		},
+		{
+			name: "multiple Access",
+			f: func(r *mutableResource[st, st, st]) error {
+				if err := r.Access(func(x *st) { x.I = 5 }); err != nil {
+					return err
+				}
+				return r.AccessBeta(func(x *st) { x.S = "abc" })
+			},
+			want: []Path{
+				Path{}.Pointer().Field("I"),
+				Path{}.Pointer().Field("S"),
+			},
+		},
+		{
+			name: "unchanged value",
+			f: func(r *mutableResource[st, st, st]) error {
+				if err := r.Access(func(x *st) { x.I = 5 }); err != nil {
+					return err
+				}
+				return r.AccessAlpha(func(x *st) { x.I = 5 })
+			},
+			want: []Path{Path{}.Pointer().Field("I")},
+		},
+		{
+			name: "Set",
+			f: func(r *mutableResource[st, st, st]) error {
+				return r.Set(&st{S: "abc"})
+			},
+			want:      []Path{Path{}.Pointer().Field("S")},
+			wantClean: []Path{Path{}.Pointer().Field("I")},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestResource[st, st, st](&testTrait[st, st, st]{})
+			if err := tc.f(r); err != nil {
+				t.Fatalf("f() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(r.DirtyFields(), tc.want); diff != "" {
+				t.Errorf("DirtyFields() diff -got,+want: %s", diff)
+			}
+			for _, p := range append(tc.want, tc.wantDirty...) {
+				if !r.IsDirty(p) {
+					t.Errorf("IsDirty(%v) = false, want true", p)
+				}
+			}
+			for _, p := range tc.wantClean {
+				if r.IsDirty(p) {
+					t.Errorf("IsDirty(%v) = true, want false", p)
+				}
+			}
+
+			fr, err := r.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(fr.DirtyFields(), tc.want); diff != "" {
+				t.Errorf("Freeze().DirtyFields() diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestResourceProvenance(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	pI := Path{}.Pointer().Field("I")
+	pS := Path{}.Pointer().Field("S")
+
+	r := newTestResource[st, st, st](&testTrait[st, st, st]{})
+	if _, ok := r.ProvenanceOf(pI); ok {
+		t.Errorf("ProvenanceOf(%v) = _, true, want false", pI)
+	}
+	if err := r.Access(func(x *st) { x.I = 5 }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if err := r.AccessAlpha(func(x *st) { x.S = "abc" }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	if err := r.SetBeta(&st{I: 10, S: "abc"}); err != nil {
+		t.Fatalf("SetBeta() = %v, want nil", err)
+	}
+
+	type result struct {
+		Path    Path
+		Version meta.Version
+	}
+	var got []result
+	for _, fp := range r.Provenance() {
+		if fp.Time.IsZero() {
+			t.Errorf("Provenance(): %v has zero Time", fp.Path)
+		}
+		got = append(got, result{fp.Path, fp.Version})
+	}
+	want := []result{
+		{pI, meta.VersionBeta},
+		{pS, meta.VersionAlpha},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Provenance() diff -got,+want: %s", diff)
+	}
+
+	for _, tc := range []struct {
+		p    Path
+		want meta.Version
+	}{
+		{p: pI, want: meta.VersionBeta},
+		{p: pS, want: meta.VersionAlpha},
+		{p: Path{}.Pointer(), want: meta.VersionBeta},
+	} {
+		fp, ok := r.ProvenanceOf(tc.p)
+		if !ok || fp.Version != tc.want {
+			t.Errorf("ProvenanceOf(%v) = %+v, %t; want Version %q", tc.p, fp, ok, tc.want)
+		}
+	}
+
+	cr, err := r.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(cr.Provenance(), r.Provenance()); diff != "" {
+		t.Errorf("Clone().Provenance() diff -got,+want: %s", diff)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(fr.Provenance(), r.Provenance()); diff != "" {
+		t.Errorf("Freeze().Provenance() diff -got,+want: %s", diff)
+	}
+}
+
 func TestResourceCheckSchema(t *testing.T) {
 	t.Parallel()
 	type sti struct {
@@ -1102,3 +1734,327 @@ func TestResourceTypeTrait(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceFieldConversions(t *testing.T) {
+	t.Parallel()
+
+	// Kind in GA was renamed to Type in Alpha. The values of Mode are
+	// different in Beta.
+	type st struct {
+		Kind            string
+		Mode            string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type stA struct {
+		Type            string
+		Mode            string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type stB struct {
+		Kind            string
+		Mode            string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	rename := func(to string) func(dest, src reflect.Value) error {
+		return func(dest, src reflect.Value) error {
+			dest.FieldByName(to).Set(src)
+			return nil
+		}
+	}
+	mapValue := func(m map[string]string) func(dest, src reflect.Value) error {
+		return func(dest, src reflect.Value) error {
+			v, ok := m[src.String()]
+			if !ok && src.String() != "" {
+				return fmt.Errorf("invalid value %q", src.String())
+			}
+			dest.FieldByName("Mode").SetString(v)
+			return nil
+		}
+	}
+	toBeta := map[string]string{"ENABLED": "ON", "DISABLED": "OFF"}
+	fromBeta := map[string]string{"ON": "ENABLED", "OFF": "DISABLED"}
+
+	conversions := func(src, dest meta.Version) []FieldConversion {
+		var ret []FieldConversion
+		switch {
+		case src == meta.VersionAlpha:
+			ret = append(ret, FieldConversion{Path: Path{}.Pointer().Field("Type"), Convert: rename("Kind")})
+		case dest == meta.VersionAlpha:
+			ret = append(ret, FieldConversion{Path: Path{}.Pointer().Field("Kind"), Convert: rename("Type")})
+		}
+		switch {
+		case src == meta.VersionBeta:
+			ret = append(ret, FieldConversion{Path: Path{}.Pointer().Field("Mode"), Convert: mapValue(fromBeta)})
+		case dest == meta.VersionBeta:
+			ret = append(ret, FieldConversion{Path: Path{}.Pointer().Field("Mode"), Convert: mapValue(toBeta)})
+		}
+		return ret
+	}
+
+	for _, tc := range []struct {
+		name        string
+		conversions func(src, dest meta.Version) []FieldConversion
+		f           func(r MutableResource[st, stA, stB]) error
+		want        st
+		wantA       stA
+		wantB       stB
+		wantErr     bool
+		wantMissing bool
+	}{
+		{
+			name:        "set GA",
+			conversions: conversions,
+			f: func(r MutableResource[st, stA, stB]) error {
+				return r.Access(func(x *st) { x.Kind = "k"; x.Mode = "ENABLED" })
+			},
+			want:  st{Kind: "k", Mode: "ENABLED"},
+			wantA: stA{Type: "k", Mode: "ENABLED"},
+			wantB: stB{Kind: "k", Mode: "ON"},
+		},
+		{
+			name:        "set Alpha",
+			conversions: conversions,
+			f: func(r MutableResource[st, stA, stB]) error {
+				return r.AccessAlpha(func(x *stA) { x.Type = "k"; x.Mode = "DISABLED" })
+			},
+			want:  st{Kind: "k", Mode: "DISABLED"},
+			wantA: stA{Type: "k", Mode: "DISABLED"},
+			wantB: stB{Kind: "k", Mode: "OFF"},
+		},
+		{
+			name:        "set Beta",
+			conversions: conversions,
+			f: func(r MutableResource[st, stA, stB]) error {
+				return r.AccessBeta(func(x *stB) { x.Kind = "k"; x.Mode = "ON" })
+			},
+			want:  st{Kind: "k", Mode: "ENABLED"},
+			wantA: stA{Type: "k", Mode: "ENABLED"},
+			wantB: stB{Kind: "k", Mode: "ON"},
+		},
+		{
+			name:        "conversion error",
+			conversions: conversions,
+			f: func(r MutableResource[st, stA, stB]) error {
+				return r.Access(func(x *st) { x.Mode = "INVALID" })
+			},
+			wantErr: true,
+		},
+		{
+			name: "no conversions",
+			f: func(r MutableResource[st, stA, stB]) error {
+				return r.Access(func(x *st) { x.Kind = "k" })
+			},
+			want:        st{Kind: "k"},
+			wantB:       stB{Kind: "k"},
+			wantMissing: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tt := &TypeTraitFuncs[st, stA, stB]{
+				FieldTraitsF: func(meta.Version) *FieldTraits {
+					ret := &FieldTraits{}
+					ret.AllowZeroValue(Path{}.Pointer().Field("Kind"))
+					ret.AllowZeroValue(Path{}.Pointer().Field("Type"))
+					ret.AllowZeroValue(Path{}.Pointer().Field("Mode"))
+					return ret
+				},
+				FieldConversionsF: tc.conversions,
+			}
+			r := newTestResource[st, stA, stB](tt)
+			err := tc.f(r)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Access() = %v, want err = %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			g, _ := r.ToGA()
+			if diff := cmp.Diff(g, &tc.want); diff != "" {
+				t.Errorf("ToGA() -got,+want: %s", diff)
+			}
+			a, err := r.ToAlpha()
+			if gotMissing := err != nil; gotMissing != tc.wantMissing {
+				t.Errorf("ToAlpha() = %v, want missing fields = %t", err, tc.wantMissing)
+			}
+			if diff := cmp.Diff(a, &tc.wantA); diff != "" {
+				t.Errorf("ToAlpha() -got,+want: %s", diff)
+			}
+			b, _ := r.ToBeta()
+			if diff := cmp.Diff(b, &tc.wantB); diff != "" {
+				t.Errorf("ToBeta() -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCopyWithID(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		S               string
+		LS              []string
+		M               map[string]string
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	tt := &testTrait[st, st, st]{}
+
+	newID := &cloud.ResourceID{
+		ProjectID: "proj-1",
+		Resource:  "st",
+		Key:       meta.GlobalKey("obj-2"),
+	}
+	upper := func(_ Path, s string) string { return strings.ToUpper(s) }
+
+	for _, tc := range []struct {
+		name    string
+		ver     meta.Version
+		rewrite func(Path, string) string
+		want    st
+	}{
+		{
+			name: "no rewrite",
+			ver:  meta.VersionGA,
+			want: st{S: "abc", LS: []string{"x"}, M: map[string]string{"k": "v"}, Name: "obj-2"},
+		},
+		{
+			name:    "rewrite",
+			ver:     meta.VersionGA,
+			rewrite: upper,
+			want:    st{S: "ABC", LS: []string{"X"}, M: map[string]string{"k": "V"}, Name: "OBJ-2"},
+		},
+		{
+			name:    "rewrite alpha",
+			ver:     meta.VersionAlpha,
+			rewrite: upper,
+			want:    st{S: "ABC", LS: []string{"X"}, M: map[string]string{"k": "V"}, Name: "OBJ-2"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestResource[st, st, st](tt)
+			set := func(x *st) {
+				x.S = "abc"
+				x.LS = []string{"x"}
+				x.M = map[string]string{"k": "v"}
+			}
+			switch tc.ver {
+			case meta.VersionGA:
+				r.Access(set)
+			case meta.VersionAlpha:
+				r.AccessAlpha(set)
+			}
+			fr, err := r.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v, want nil", err)
+			}
+
+			got, err := CopyWithID[st, st, st](fr, newID, tc.rewrite)
+			if err != nil {
+				t.Fatalf("CopyWithID() = %v, want nil", err)
+			}
+			if !got.ResourceID().Equal(newID) {
+				t.Errorf("ResourceID() = %v, want %v", got.ResourceID(), newID)
+			}
+			if got.Version() != fr.Version() {
+				t.Errorf("Version() = %v, want %v", got.Version(), fr.Version())
+			}
+			gotGA, err := got.ToGA()
+			if err != nil {
+				t.Fatalf("ToGA() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(gotGA, &tc.want); diff != "" {
+				t.Errorf("ToGA() -got,+want: %s", diff)
+			}
+			// The original is unchanged.
+			orig, _ := fr.ToGA()
+			if orig.Name != "obj-1" || orig.S != "abc" {
+				t.Errorf("original resource changed: %+v", orig)
+			}
+		})
+	}
+}
+
+type outputOnlyTrait[G any, A any, B any] struct {
+	BaseTypeTrait[G, A, B]
+}
+
+func (outputOnlyTrait[G, A, B]) FieldTraits(meta.Version) *FieldTraits {
+	ret := &FieldTraits{}
+	ret.OutputOnly(Path{}.Pointer().Field("Id"))
+	ret.OutputOnly(Path{}.Pointer().Field("SelfLink"))
+	ret.OutputOnly(Path{}.Pointer().Field("Sub").Pointer().Field("Id"))
+	return ret
+}
+
+func TestClearOutputOnly(t *testing.T) {
+	t.Parallel()
+
+	type sub struct {
+		Id              uint64
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		S               string
+		Id              uint64
+		SelfLink        string
+		Sub             *sub
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	r := newTestResource[st, st, st](&outputOnlyTrait[st, st, st]{})
+	// Set() skips the validation, similar to a resource returned by the
+	// Cloud.
+	err := r.Set(&st{
+		Name:            "obj-1",
+		S:               "abc",
+		Id:              123,
+		SelfLink:        "https://selflink",
+		Sub:             &sub{Id: 456, S: "def"},
+		ForceSendFields: []string{"Id", "S"},
+	})
+	if err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+
+	got, err := ClearOutputOnly[st, st, st](fr)
+	if err != nil {
+		t.Fatalf("ClearOutputOnly() = %v, want nil", err)
+	}
+	if got.Version() != fr.Version() {
+		t.Errorf("Version() = %v, want %v", got.Version(), fr.Version())
+	}
+	if !got.ResourceID().Equal(fr.ResourceID()) {
+		t.Errorf("ResourceID() = %v, want %v", got.ResourceID(), fr.ResourceID())
+	}
+	gotGA, err := got.ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v, want nil", err)
+	}
+	want := &st{
+		Name:            "obj-1",
+		S:               "abc",
+		Sub:             &sub{S: "def"},
+		ForceSendFields: []string{"S"},
+	}
+	if diff := cmp.Diff(gotGA, want); diff != "" {
+		t.Errorf("ToGA() -got,+want: %s", diff)
+	}
+	// The original is unchanged.
+	if orig, _ := fr.ToGA(); orig.Id != 123 || orig.Sub.Id != 456 {
+		t.Errorf("original resource changed: %+v", orig)
+	}
+}
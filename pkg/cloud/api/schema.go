@@ -0,0 +1,202 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// FieldTraitsFromSchema returns the FieldTraits for the version v of the type
+// T from a declarative schema. This keeps the traits of a resource in one
+// place instead of a sequence of calls to FieldTraits.OutputOnly(), etc.
+//
+// Each line of the schema has the form:
+//
+//	<FieldType> <field> [<versions>]
+//
+// FieldType is one of the FieldType values (e.g. "OutputOnly"). field is a
+// dotted list of field names; pointers are dereferenced automatically and
+// "[]" matches any element of a slice or map. versions is an optional comma
+// separated list of the versions the line applies to (e.g. "alpha,beta"). It
+// defaults to all versions. "#" starts a comment. Example:
+//
+//	# [Output Only]
+//	OutputOnly     CreationTimestamp
+//	OutputOnly     HttpHealthCheck.PortName
+//	OutputOnly     SelfLinkWithId             alpha
+//	NonZeroValue   Backends[].Group
+//
+// Allowed values for a string field (see FieldTraits.Enum) are given with
+// "Enum", followed by a comma separated list of values:
+//
+//	Enum           Type          HTTP,HTTPS,TCP
+//	Enum           Type          UDP                alpha
+//
+// The result includes the default traits from NewFieldTraits().
+func FieldTraitsFromSchema[T any](schema string, v meta.Version) (*FieldTraits, error) {
+	lines, err := parseSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	ret := NewFieldTraits()
+	t := reflect.TypeOf((*T)(nil))
+	for _, l := range lines {
+		if !l.hasVersion(v) {
+			continue
+		}
+		p, err := resolveFieldPath(t, l.field)
+		if err != nil {
+			return nil, fmt.Errorf("FieldTraitsFromSchema: line %d: %w", l.lineNo, err)
+		}
+		// Enum values can be given for the elements of a slice, the other
+		// traits must refer to a field.
+		if l.enum != nil {
+			if ft, err := p.ResolveType(t); err != nil || ft.Kind() != reflect.String {
+				return nil, fmt.Errorf("FieldTraitsFromSchema: line %d: %s: enum is not a string", l.lineNo, l.field)
+			}
+			ret.Enum(p, l.enum...)
+			continue
+		}
+		if p[len(p)-1][0] != pathField {
+			return nil, fmt.Errorf("FieldTraitsFromSchema: line %d: %s: %s is not a field reference", l.lineNo, l.field, p)
+		}
+		ret.add(p, l.fType)
+	}
+	return ret, nil
+}
+
+// SchemaTypeTrait is a TypeTrait with the FieldTraits given by a declarative
+// schema. See FieldTraitsFromSchema for the format. Embed SchemaTypeTrait to
+// add CopyHelpers.
+type SchemaTypeTrait[GA any, Alpha any, Beta any] struct {
+	BaseTypeTrait[GA, Alpha, Beta]
+	traits map[meta.Version]*FieldTraits
+}
+
+// NewSchemaTypeTrait returns a SchemaTypeTrait for the schema. Versions that
+// are a PlaceholderType are skipped.
+func NewSchemaTypeTrait[GA any, Alpha any, Beta any](schema string) (*SchemaTypeTrait[GA, Alpha, Beta], error) {
+	ret := &SchemaTypeTrait[GA, Alpha, Beta]{traits: map[meta.Version]*FieldTraits{}}
+
+	var err error
+	if ret.traits[meta.VersionGA], err = FieldTraitsFromSchema[GA](schema, meta.VersionGA); err != nil {
+		return nil, fmt.Errorf("%s: %w", meta.VersionGA, err)
+	}
+	var alpha Alpha
+	if !isPlaceholderType(alpha) {
+		if ret.traits[meta.VersionAlpha], err = FieldTraitsFromSchema[Alpha](schema, meta.VersionAlpha); err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.VersionAlpha, err)
+		}
+	}
+	var beta Beta
+	if !isPlaceholderType(beta) {
+		if ret.traits[meta.VersionBeta], err = FieldTraitsFromSchema[Beta](schema, meta.VersionBeta); err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.VersionBeta, err)
+		}
+	}
+	return ret, nil
+}
+
+// MustNewSchemaTypeTrait is NewSchemaTypeTrait that panics on error. This is
+// intended for package-level variables.
+func MustNewSchemaTypeTrait[GA any, Alpha any, Beta any](schema string) *SchemaTypeTrait[GA, Alpha, Beta] {
+	ret, err := NewSchemaTypeTrait[GA, Alpha, Beta](schema)
+	if err != nil {
+		panic(fmt.Sprintf("MustNewSchemaTypeTrait: %v", err))
+	}
+	return ret
+}
+
+// FieldTraits implements TypeTrait.
+func (tt *SchemaTypeTrait[GA, Alpha, Beta]) FieldTraits(v meta.Version) *FieldTraits {
+	if dt, ok := tt.traits[v]; ok {
+		return dt.Clone()
+	}
+	return NewFieldTraits()
+}
+
+// schemaEnum is the keyword for a line with the allowed values of a field.
+const schemaEnum = "Enum"
+
+type schemaLine struct {
+	lineNo   int
+	fType    FieldType
+	field    string
+	enum     []string
+	versions []meta.Version
+}
+
+func (l *schemaLine) hasVersion(v meta.Version) bool {
+	if len(l.versions) == 0 {
+		return true
+	}
+	for _, lv := range l.versions {
+		if lv == v {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSchema(schema string) ([]schemaLine, error) {
+	var ret []schemaLine
+	for i, line := range strings.Split(schema, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		parts := strings.Fields(line)
+		if len(parts) == 0 {
+			continue
+		}
+		// Enum lines have an additional column with the values.
+		maxParts := 3
+		if parts[0] == schemaEnum {
+			maxParts = 4
+		}
+		if len(parts) < maxParts-1 || len(parts) > maxParts {
+			return nil, fmt.Errorf("schema line %d: invalid line %q", i+1, line)
+		}
+		l := schemaLine{lineNo: i + 1, fType: FieldType(parts[0]), field: parts[1]}
+		if parts[0] == schemaEnum {
+			l.fType = ""
+			l.enum = strings.Split(parts[2], ",")
+			parts = append(parts[:2], parts[3:]...)
+		} else {
+			switch l.fType {
+			case FieldTypeOrdinary, FieldTypeSystem, FieldTypeOutputOnly, FieldTypeAllowZeroValue, FieldTypeNonZeroValue:
+			default:
+				return nil, fmt.Errorf("schema line %d: invalid FieldType %q", i+1, parts[0])
+			}
+		}
+		if len(parts) == 3 {
+			for _, v := range strings.Split(parts[2], ",") {
+				switch meta.Version(v) {
+				case meta.VersionGA, meta.VersionAlpha, meta.VersionBeta:
+					l.versions = append(l.versions, meta.Version(v))
+				default:
+					return nil, fmt.Errorf("schema line %d: invalid version %q", i+1, v)
+				}
+			}
+		}
+		ret = append(ret, l)
+	}
+	return ret, nil
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+type schemaTestElem struct {
+	A string
+}
+
+type schemaTestType struct {
+	I    int
+	P    *schemaTestElem
+	L    []*schemaTestElem
+	M    map[string]schemaTestElem
+	LS   []string
+	Name string
+
+	NullFields      []string
+	ForceSendFields []string
+}
+
+type schemaTestTypeAlpha struct {
+	schemaTestType
+	AlphaOnly string
+}
+
+func TestFieldTraitsFromSchema(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		schema  string
+		ver     meta.Version
+		want    []fieldTrait
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			ver:  meta.VersionGA,
+		},
+		{
+			name: "fields",
+			schema: `
+				# Comment.
+				OutputOnly    I
+				NonZeroValue  P.A   # Comment.
+				System        L[].A
+				AllowZeroValue M[].A
+				OutputOnly    LS
+			`,
+			ver: meta.VersionGA,
+			want: []fieldTrait{
+				{path: Path{}.Pointer().Field("I"), fType: FieldTypeOutputOnly},
+				{path: Path{}.Pointer().Field("P").Pointer().Field("A"), fType: FieldTypeNonZeroValue},
+				{path: Path{}.Pointer().Field("L").AnySliceIndex().Pointer().Field("A"), fType: FieldTypeSystem},
+				{path: Path{}.Pointer().Field("M").AnyMapIndex().Field("A"), fType: FieldTypeAllowZeroValue},
+				{path: Path{}.Pointer().Field("LS"), fType: FieldTypeOutputOnly},
+			},
+		},
+		{
+			name: "versions",
+			schema: `
+				OutputOnly I
+				OutputOnly Name alpha,beta
+			`,
+			ver: meta.VersionGA,
+			want: []fieldTrait{
+				{path: Path{}.Pointer().Field("I"), fType: FieldTypeOutputOnly},
+			},
+		},
+		{
+			name: "versions alpha",
+			schema: `
+				OutputOnly I
+				OutputOnly Name alpha,beta
+			`,
+			ver: meta.VersionAlpha,
+			want: []fieldTrait{
+				{path: Path{}.Pointer().Field("I"), fType: FieldTypeOutputOnly},
+				{path: Path{}.Pointer().Field("Name"), fType: FieldTypeOutputOnly},
+			},
+		},
+		{
+			name:    "invalid field type",
+			schema:  "ReadOnly I",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			schema:  "OutputOnly I v2",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "invalid line",
+			schema:  "OutputOnly",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "no such field",
+			schema:  "OutputOnly P.B",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "not a struct",
+			schema:  "OutputOnly I.A",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "not a slice",
+			schema:  "OutputOnly P[].A",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "not a field reference",
+			schema:  "OutputOnly LS[]",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dt, err := FieldTraitsFromSchema[schemaTestType](tc.schema, tc.ver)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("FieldTraitsFromSchema() = %v, want err = %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			want := append(NewFieldTraits().fields, tc.want...)
+			if diff := cmp.Diff(dt.fields, want, cmp.AllowUnexported(fieldTrait{})); diff != "" {
+				t.Errorf("FieldTraitsFromSchema(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFieldTraitsFromSchemaEnum(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		schema  string
+		ver     meta.Version
+		want    []enumTrait
+		wantErr bool
+	}{
+		{
+			name: "enum",
+			schema: `
+				Enum  Name   X,Y
+				Enum  LS[]   Z
+				Enum  P.A    X      beta
+			`,
+			ver: meta.VersionGA,
+			want: []enumTrait{
+				{path: Path{}.Pointer().Field("Name"), values: []string{"X", "Y"}},
+				{path: Path{}.Pointer().Field("LS").AnySliceIndex(), values: []string{"Z"}},
+			},
+		},
+		{
+			name: "values by version",
+			schema: `
+				Enum  Name   X,Y
+				Enum  Name   Z      alpha
+			`,
+			ver: meta.VersionAlpha,
+			want: []enumTrait{
+				{path: Path{}.Pointer().Field("Name"), values: []string{"X", "Y", "Z"}},
+			},
+		},
+		{
+			name:    "not a string",
+			schema:  "Enum I X",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "missing values",
+			schema:  "Enum Name",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			schema:  "Enum Name X v2",
+			ver:     meta.VersionGA,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dt, err := FieldTraitsFromSchema[schemaTestType](tc.schema, tc.ver)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("FieldTraitsFromSchema() = %v, want err = %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(dt.enums, tc.want, cmp.AllowUnexported(enumTrait{})); diff != "" {
+				t.Errorf("FieldTraitsFromSchema(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaTypeTrait(t *testing.T) {
+	t.Parallel()
+
+	const schema = `
+		OutputOnly I
+		OutputOnly AlphaOnly alpha
+	`
+	tt, err := NewSchemaTypeTrait[schemaTestType, schemaTestTypeAlpha, PlaceholderType](schema)
+	if err != nil {
+		t.Fatalf("NewSchemaTypeTrait() = %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		ver  meta.Version
+		path Path
+		want FieldType
+	}{
+		{ver: meta.VersionGA, path: Path{}.Pointer().Field("I"), want: FieldTypeOutputOnly},
+		{ver: meta.VersionGA, path: Path{}.Pointer().Field("Name"), want: FieldTypeOrdinary},
+		{ver: meta.VersionAlpha, path: Path{}.Pointer().Field("AlphaOnly"), want: FieldTypeOutputOnly},
+		{ver: meta.VersionBeta, path: Path{}.Pointer().Field("I"), want: FieldTypeOrdinary},
+	} {
+		if got := tt.FieldTraits(tc.ver).fieldType(tc.path); got != tc.want {
+			t.Errorf("FieldTraits(%s).fieldType(%s) = %s, want %s", tc.ver, tc.path, got, tc.want)
+		}
+	}
+
+	// The GA type does not have the field.
+	if _, err := NewSchemaTypeTrait[schemaTestType, schemaTestTypeAlpha, PlaceholderType]("OutputOnly AlphaOnly"); err == nil {
+		t.Errorf("NewSchemaTypeTrait() = nil, want error")
+	}
+
+	// The returned FieldTraits can be modified without changing the
+	// SchemaTypeTrait.
+	tt.FieldTraits(meta.VersionGA).OutputOnly(Path{}.Pointer().Field("Name"))
+	if got := tt.FieldTraits(meta.VersionGA).fieldType(Path{}.Pointer().Field("Name")); got != FieldTypeOrdinary {
+		t.Errorf("fieldType(Name) = %s, want %s", got, FieldTypeOrdinary)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Synchronized wraps r so that it is safe for concurrent use. MutableResource
+// is not safe for concurrent use by itself; use this when the resource is
+// shared between goroutines (e.g. a Builder used by multiple workers).
+//
+// The cost of the serialization:
+//
+//   - All methods take a lock. Access*() and Set*() hold the lock while the
+//     callback runs and the change is propagated to the other versions.
+//     Calling methods of the resource from within the Access*() callback
+//     will deadlock.
+//   - To*() return a deep copy of the object instead of the object stored in
+//     the resource, as the latter could be modified by a concurrent Access*().
+//   - Freeze() returns a Resource backed by a copy of r.
+//
+// r must not be used directly after it is wrapped.
+func Synchronized[GA any, Alpha any, Beta any](r MutableResource[GA, Alpha, Beta]) MutableResource[GA, Alpha, Beta] {
+	if sr, ok := r.(*syncResource[GA, Alpha, Beta]); ok {
+		return sr
+	}
+	return &syncResource[GA, Alpha, Beta]{r: r}
+}
+
+type syncResource[GA any, Alpha any, Beta any] struct {
+	lock sync.RWMutex
+	r    MutableResource[GA, Alpha, Beta]
+}
+
+// syncResource implements MutableResource.
+var _ MutableResource[PlaceholderType, PlaceholderType, PlaceholderType] = (*syncResource[PlaceholderType, PlaceholderType, PlaceholderType])(nil)
+
+func (s *syncResource[GA, Alpha, Beta]) CheckSchema() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.CheckSchema()
+}
+
+func (s *syncResource[GA, Alpha, Beta]) ResourceID() *cloud.ResourceID {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.ResourceID()
+}
+
+func (s *syncResource[GA, Alpha, Beta]) ImpliedVersion() (meta.Version, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.ImpliedVersion()
+}
+
+func (s *syncResource[GA, Alpha, Beta]) Access(f func(x *GA)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.r.Access(f)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) AccessAlpha(f func(x *Alpha)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.r.AccessAlpha(f)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) AccessBeta(f func(x *Beta)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.r.AccessBeta(f)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) ToGA() (*GA, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return syncCopy(s.r.ToGA())
+}
+
+func (s *syncResource[GA, Alpha, Beta]) ToAlpha() (*Alpha, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return syncCopy(s.r.ToAlpha())
+}
+
+func (s *syncResource[GA, Alpha, Beta]) ToBeta() (*Beta, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return syncCopy(s.r.ToBeta())
+}
+
+// syncCopy returns a deep copy of the obj returned from To*(). The original
+// error is returned unless the copy fails.
+func syncCopy[T any](obj *T, err error) (*T, error) {
+	if obj == nil {
+		return nil, err
+	}
+	ret := new(T)
+	if cerr := deepCopy(ret, obj); cerr != nil {
+		return nil, cerr
+	}
+	return ret, err
+}
+
+func (s *syncResource[GA, Alpha, Beta]) LossyFields() map[meta.Version][]MissingField {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.LossyFields()
+}
+
+func (s *syncResource[GA, Alpha, Beta]) Set(src *GA) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.r.Set(src)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) SetAlpha(src *Alpha) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.r.SetAlpha(src)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) SetBeta(src *Beta) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.r.SetBeta(src)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) DirtyFields() []Path {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.DirtyFields()
+}
+
+func (s *syncResource[GA, Alpha, Beta]) IsDirty(p Path) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.IsDirty(p)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) Provenance() []FieldProvenance {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.Provenance()
+}
+
+func (s *syncResource[GA, Alpha, Beta]) ProvenanceOf(p Path) (FieldProvenance, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.ProvenanceOf(p)
+}
+
+func (s *syncResource[GA, Alpha, Beta]) Freeze() (Resource[GA, Alpha, Beta], error) {
+	s.lock.RLock()
+	c, err := s.r.Clone()
+	s.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	// c is not shared, so it can be frozen without holding the lock.
+	return c.Freeze()
+}
+
+// Clone returns a copy that is also synchronized.
+func (s *syncResource[GA, Alpha, Beta]) Clone() (MutableResource[GA, Alpha, Beta], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	c, err := s.r.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return Synchronized(c), nil
+}
+
+func (s *syncResource[GA, Alpha, Beta]) MarshalJSON() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.r.MarshalJSON()
+}
+
+func (s *syncResource[GA, Alpha, Beta]) UnmarshalJSON(b []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.r.UnmarshalJSON(b)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSynchronized(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		L               []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	r := Synchronized[st, st, st](newTestResource[st, st, st](&testTrait[st, st, st]{}))
+	if Synchronized(r) != r {
+		t.Errorf("Synchronized(Synchronized(r)) != Synchronized(r)")
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := r.AccessAlpha(func(x *st) { x.I++; x.L = append(x.L, "x") }); err != nil {
+				t.Errorf("AccessAlpha() = %v, want nil", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := r.ToGA(); err != nil {
+				t.Errorf("ToGA() = %v, want nil", err)
+			}
+			r.DirtyFields()
+		}()
+	}
+	wg.Wait()
+
+	ga, err := r.ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = %v, want nil", err)
+	}
+	if ga.I != n || len(ga.L) != n {
+		t.Errorf("ToGA() = %+v, want I = %d and len(L) = %d", ga, n, n)
+	}
+
+	// To*() returns a copy.
+	ga.L[0] = "modified"
+	ga.I = 0
+	ga2, _ := r.ToGA()
+	if ga2.I != n || ga2.L[0] != "x" {
+		t.Errorf("ToGA() = %+v after modifying a previous result, want unchanged", ga2)
+	}
+
+	// The frozen resource does not change with later Access() calls.
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	if err := r.Access(func(x *st) { x.I = 100 }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if fga, _ := fr.ToGA(); fga.I != n {
+		t.Errorf("Freeze().ToGA().I = %d, want %d", fga.I, n)
+	}
+
+	c, err := r.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v, want nil", err)
+	}
+	if _, ok := c.(*syncResource[st, st, st]); !ok {
+		t.Errorf("Clone() = %T, want *syncResource", c)
+	}
+}
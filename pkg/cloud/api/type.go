@@ -106,3 +106,40 @@ func isPtrToBasic(x any) bool               { return isPtrToBasicV(reflect.Value
 func isSliceOfStringT(x reflect.Type) bool  { return typeIs(x, sliceT, stringT) }
 func isSliceOfStringV(x reflect.Value) bool { return isSliceOfStringT(x.Type()) }
 func isSliceOfString(x any) bool            { return isSliceOfStringV(reflect.ValueOf(x)) }
+
+// isProtoMessageT returns true if t is a struct generated by protoc-gen-go
+// (i.e. *t implements proto.Message). Presence for these types is given by
+// pointer (optional) fields instead of the NullFields and ForceSendFields
+// metafields.
+func isProtoMessageT(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := reflect.PointerTo(t).MethodByName("ProtoReflect")
+	return ok
+}
+
+// skipField returns true if the field f of struct type t is internal state
+// that is not part of the resource and should be ignored. These are the
+// unexported fields of a proto message (e.g. "state", "sizeCache").
+func skipField(t reflect.Type, f reflect.StructField) bool {
+	return !f.IsExported() && isProtoMessageT(t)
+}
+
+// isStringOrPtrToStringT returns true if t is a string or *string. Proto
+// messages use *string for optional string fields.
+func isStringOrPtrToStringT(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+// setStringOrPtr sets v, which is a string or *string, to s.
+func setStringOrPtr(v reflect.Value, s string) {
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	v.SetString(s)
+}
@@ -57,6 +57,48 @@ func (*BaseTypeTrait[GA, Alpha, Beta]) CopyHelperBetaToAlpha(dest *Alpha, src *B
 }
 func (*BaseTypeTrait[GA, Alpha, Beta]) FieldTraits(meta.Version) *FieldTraits { return &FieldTraits{} }
 
+// Implements FieldConverter.
+func (*BaseTypeTrait[GA, Alpha, Beta]) FieldConversions(src, dest meta.Version) []FieldConversion {
+	return nil
+}
+
+// FieldConverter is an optional interface that can be implemented by a
+// TypeTrait to convert fields that have a different name or type between
+// versions (e.g. an enum that was renamed). Without a FieldConversion, these
+// fields are reported as MissingFields by the To*() methods.
+type FieldConverter interface {
+	// FieldConversions returns the conversions to use when copying from the
+	// src version to the dest version.
+	FieldConversions(src, dest meta.Version) []FieldConversion
+}
+
+// FieldConversion converts a field from one version to another.
+type FieldConversion struct {
+	// Path of the field in the source version, e.g.
+	// Path{}.Pointer().Field("Type"). Wildcards (e.g. AnySliceIndex) can be
+	// used to match fields in slices and maps.
+	Path Path
+	// Convert the srcField. destStruct is the struct in the destination
+	// version that corresponds to the struct containing srcField. Convert
+	// should set the field(s) in destStruct.
+	Convert func(destStruct, srcField reflect.Value) error
+}
+
+// fieldConversions returns the FieldConversions for the TypeTrait if it
+// implements FieldConverter.
+func fieldConversions[GA any, Alpha any, Beta any](tt TypeTrait[GA, Alpha, Beta], src, dest meta.Version) []FieldConversion {
+	fc, ok := tt.(FieldConverter)
+	if !ok {
+		return nil
+	}
+	return fc.FieldConversions(src, dest)
+}
+
+// fieldTraits returns the FieldTraits from tt that apply to version v.
+func fieldTraits[GA any, Alpha any, Beta any](tt TypeTrait[GA, Alpha, Beta], v meta.Version) *FieldTraits {
+	return tt.FieldTraits(v).ForVersion(v)
+}
+
 // NewFieldTraits creates a default traits.
 func NewFieldTraits() *FieldTraits {
 	return &FieldTraits{
@@ -78,6 +120,10 @@ type TypeTraitFuncs[GA any, Alpha any, Beta any] struct {
 	CopyHelperBetaToGAF    func(dest *GA, src *Beta) error
 	CopyHelperBetaToAlphaF func(dest *Alpha, src *Beta) error
 	FieldTraitsF           func(meta.Version) *FieldTraits
+	FieldConversionsF      func(src, dest meta.Version) []FieldConversion
+	ValidateGAF            func(obj *GA) []InvalidField
+	ValidateAlphaF         func(obj *Alpha) []InvalidField
+	ValidateBetaF          func(obj *Beta) []InvalidField
 }
 
 // Implements TypeTrait.
@@ -124,14 +170,58 @@ func (f *TypeTraitFuncs[GA, Alpha, Beta]) FieldTraits(v meta.Version) *FieldTrai
 	return f.FieldTraitsF(v)
 }
 
+// Implements FieldConverter.
+func (f *TypeTraitFuncs[GA, Alpha, Beta]) FieldConversions(src, dest meta.Version) []FieldConversion {
+	if f.FieldConversionsF == nil {
+		return nil
+	}
+	return f.FieldConversionsF(src, dest)
+}
+
+// Implements ResourceValidator.
+func (f *TypeTraitFuncs[GA, Alpha, Beta]) ValidateGA(obj *GA) []InvalidField {
+	if f.ValidateGAF == nil {
+		return nil
+	}
+	return f.ValidateGAF(obj)
+}
+func (f *TypeTraitFuncs[GA, Alpha, Beta]) ValidateAlpha(obj *Alpha) []InvalidField {
+	if f.ValidateAlphaF == nil {
+		return nil
+	}
+	return f.ValidateAlphaF(obj)
+}
+func (f *TypeTraitFuncs[GA, Alpha, Beta]) ValidateBeta(obj *Beta) []InvalidField {
+	if f.ValidateBetaF == nil {
+		return nil
+	}
+	return f.ValidateBetaF(obj)
+}
+
 // FieldTraits are the features and behavior for fields in the resource.
 type FieldTraits struct {
 	fields []fieldTrait
+	enums  []enumTrait
+	// allowMissing are the fields that may be lost when converting to
+	// another version.
+	allowMissing []Path
+	// unordered are the slice fields where the order of the elements is not
+	// significant.
+	unordered []Path
 }
 
 type fieldTrait struct {
 	path  Path
 	fType FieldType
+	// versions the trait applies to. If empty, the trait applies to all
+	// versions.
+	versions []meta.Version
+}
+
+// enumTrait restricts the values of a string field.
+type enumTrait struct {
+	path   Path
+	values []string
 }
 
 // FieldType of the field.
@@ -158,9 +248,13 @@ const (
 )
 
 // CheckSchema validates that the traits are valid and match the schema of the
-// given type.
+// given type. Version-specific traits (see VersionTrait) are skipped; use
+// ForVersion(v).CheckSchema(t) to check the traits for a version.
 func (dt *FieldTraits) CheckSchema(t reflect.Type) error {
 	for _, f := range dt.fields {
+		if len(f.versions) > 0 {
+			continue
+		}
 		if f.path[len(f.path)-1][0] != pathField {
 			return fmt.Errorf("CheckSchema: path %s is not a field reference", f.path)
 		}
@@ -169,6 +263,15 @@ func (dt *FieldTraits) CheckSchema(t reflect.Type) error {
 			return fmt.Errorf("CheckSchema: %w", err)
 		}
 	}
+	for _, e := range dt.enums {
+		ft, err := e.path.ResolveType(t)
+		if err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if ft.Kind() != reflect.String {
+			return fmt.Errorf("CheckSchema: enum %s is not a string (%s)", e.path, ft)
+		}
+	}
 	return nil
 }
 
@@ -188,19 +291,99 @@ func (dt *FieldTraits) AllowZeroValue(p Path) { dt.add(p, FieldTypeAllowZeroValu
 // NonZeroValue specifies the type of the given path.
 func (dt *FieldTraits) NonZeroValue(p Path) { dt.add(p, FieldTypeNonZeroValue) }
 
+// VersionTrait sets the type of the given path for the versions given only.
+// This is used when the fields differ between versions, e.g. a field that is
+// required in Alpha but does not exist in GA:
+//
+//	dt.VersionTrait(p, FieldTypeNonZeroValue, meta.VersionAlpha)
+//
+// The trait only takes effect for the traits returned by ForVersion(). The
+// Resource resolves the traits this way when validating and diffing each
+// version, so Alpha-only requirements do not reject GA configurations (and
+// vice versa).
+func (dt *FieldTraits) VersionTrait(p Path, t FieldType, versions ...meta.Version) {
+	dt.fields = append(dt.fields, fieldTrait{path: p, fType: t, versions: versions})
+}
+
+// ForVersion returns a copy of the traits that apply to version v.
+func (dt *FieldTraits) ForVersion(v meta.Version) *FieldTraits {
+	ret := dt.Clone()
+	ret.fields = nil
+	for _, f := range dt.fields {
+		if !f.hasVersion(v) {
+			continue
+		}
+		f.versions = nil
+		ret.fields = append(ret.fields, f)
+	}
+	return ret
+}
+
+// Enum restricts the string field at p to the given values. The empty string
+// is always allowed; use NonZeroValue() to require the field to be set.
+// Calling Enum() again for the same path adds to the allowed values. Access()
+// returns an error if the field is set to a value that is not allowed.
+func (dt *FieldTraits) Enum(p Path, values ...string) {
+	for i := range dt.enums {
+		if dt.enums[i].path.Equal(p) {
+			dt.enums[i].values = append(dt.enums[i].values, values...)
+			return
+		}
+	}
+	dt.enums = append(dt.enums, enumTrait{path: p, values: append([]string{}, values...)})
+}
+
+// AllowMissing declares that the loss of the field at p (and its children)
+// when converting to another version is acceptable, e.g. an Alpha-only field
+// that the caller does not need to be sent in GA. The field is not reported
+// in the ConversionError from To*() or in LossyFields(). p may contain
+// wildcards. The traits of the version being converted from are used.
+func (dt *FieldTraits) AllowMissing(p Path) {
+	dt.allowMissing = append(dt.allowMissing, p)
+}
+
+// Unordered declares that the order of the elements in the slice at p is not
+// significant (the slice is a set). This is used by CmpOptions(). p may
+// contain wildcards.
+func (dt *FieldTraits) Unordered(p Path) {
+	dt.unordered = append(dt.unordered, p)
+}
+
 // Clone create an exact copy of the traits.
 func (dt *FieldTraits) Clone() *FieldTraits {
-	return &FieldTraits{
-		fields: append([]fieldTrait{}, dt.fields...),
+	ret := &FieldTraits{
+		fields:       append([]fieldTrait{}, dt.fields...),
+		allowMissing: append([]Path(nil), dt.allowMissing...),
+		unordered:    append([]Path(nil), dt.unordered...),
 	}
+	for _, e := range dt.enums {
+		ret.enums = append(ret.enums, enumTrait{path: e.path, values: append([]string{}, e.values...)})
+	}
+	return ret
+}
+
+// enumValues returns the allowed values for the field at p. ok is false if
+// the field is not an enum.
+func (dt *FieldTraits) enumValues(p Path) (values []string, ok bool) {
+	for _, e := range dt.enums {
+		if p.Match(e.path) {
+			return e.values, true
+		}
+	}
+	return nil, false
 }
 
 func (dt *FieldTraits) fieldType(p Path) FieldType { return dt.fieldTrait(p).fType }
 
+// fieldTrait returns the trait for the path. Version-specific traits are
+// skipped; use ForVersion() to resolve these first.
 func (dt *FieldTraits) fieldTrait(p Path) fieldTrait {
 	// TODO(bowei): this can be made very efficient with a tree, early bailout
 	// etc.. We will go with a very inefficient implimentation for now.
 	for _, f := range dt.fields {
+		if len(f.versions) > 0 {
+			continue
+		}
 		if p.HasPrefix(f.path) {
 			return f
 		}
@@ -210,3 +393,15 @@ func (dt *FieldTraits) fieldTrait(p Path) fieldTrait {
 		fType: FieldTypeOrdinary,
 	}
 }
+
+func (f *fieldTrait) hasVersion(v meta.Version) bool {
+	if len(f.versions) == 0 {
+		return true
+	}
+	for _, fv := range f.versions {
+		if fv == v {
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/kr/pretty"
 )
 
@@ -124,11 +125,68 @@ func TestFieldTraitsClone(t *testing.T) {
 
 	dt := &FieldTraits{}
 	dt.OutputOnly(Path{}.Pointer().Field("A"))
+	dt.Enum(Path{}.Pointer().Field("B"), "X", "Y")
 
 	dtc := dt.Clone()
 	if !reflect.DeepEqual(dt, dtc) {
 		t.Errorf("Clone() differs: dt = %+v, dt.Clone = %+v", dt, dtc)
 	}
+	dtc.Enum(Path{}.Pointer().Field("B"), "Z")
+	if reflect.DeepEqual(dt, dtc) {
+		t.Errorf("Clone() shares enum values with the original")
+	}
+}
+
+func TestFieldTraitsForVersion(t *testing.T) {
+	t.Parallel()
+
+	pa := Path{}.Pointer().Field("A")
+	pb := Path{}.Pointer().Field("B")
+
+	dt := &FieldTraits{}
+	dt.OutputOnly(pa)
+	dt.VersionTrait(pb, FieldTypeNonZeroValue, meta.VersionAlpha, meta.VersionBeta)
+
+	for _, tc := range []struct {
+		name  string
+		dt    *FieldTraits
+		wantA FieldType
+		wantB FieldType
+	}{
+		{
+			name:  "unresolved",
+			dt:    dt,
+			wantA: FieldTypeOutputOnly,
+			wantB: FieldTypeOrdinary,
+		},
+		{
+			name:  "ga",
+			dt:    dt.ForVersion(meta.VersionGA),
+			wantA: FieldTypeOutputOnly,
+			wantB: FieldTypeOrdinary,
+		},
+		{
+			name:  "alpha",
+			dt:    dt.ForVersion(meta.VersionAlpha),
+			wantA: FieldTypeOutputOnly,
+			wantB: FieldTypeNonZeroValue,
+		},
+		{
+			name:  "beta",
+			dt:    dt.ForVersion(meta.VersionBeta),
+			wantA: FieldTypeOutputOnly,
+			wantB: FieldTypeNonZeroValue,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.dt.fieldType(pa); got != tc.wantA {
+				t.Errorf("fieldType(%v) = %s, want %s", pa, got, tc.wantA)
+			}
+			if got := tc.dt.fieldType(pb); got != tc.wantB {
+				t.Errorf("fieldType(%v) = %s, want %s", pb, got, tc.wantB)
+			}
+		})
+	}
 }
 
 func TestFieldTraitsCheckSchema(t *testing.T) {
@@ -171,6 +229,35 @@ func TestFieldTraitsCheckSchema(t *testing.T) {
 			ty:      reflect.TypeOf(&st{}),
 			wantErr: true,
 		},
+		{
+			name: "enum",
+			ft: func() *FieldTraits {
+				var ret FieldTraits
+				ret.Enum(Path{}.Pointer().Field("S").Field("L").AnySliceIndex(), "X")
+				ret.Enum(Path{}.Pointer().Field("P").Pointer(), "X")
+				return &ret
+			}(),
+			ty: reflect.TypeOf(&st{}),
+		},
+		{
+			name: "version-specific path is skipped",
+			ft: func() *FieldTraits {
+				var ret FieldTraits
+				ret.VersionTrait(Path{}.Pointer().Field("X"), FieldTypeNonZeroValue, meta.VersionAlpha)
+				return &ret
+			}(),
+			ty: reflect.TypeOf(&st{}),
+		},
+		{
+			name: "enum is not a string",
+			ft: func() *FieldTraits {
+				var ret FieldTraits
+				ret.Enum(Path{}.Pointer().Field("A"), "X")
+				return &ret
+			}(),
+			ty:      reflect.TypeOf(&st{}),
+			wantErr: true,
+		},
 		{
 			name: "path references fields that don't exist",
 			ft: func() *FieldTraits {
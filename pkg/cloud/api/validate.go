@@ -0,0 +1,97 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// ResourceValidator is an optional interface that can be implemented by a
+// TypeTrait to check constraints on the resource as a whole that cannot be
+// expressed with FieldTraits, e.g. "a TCP HealthCheck must have
+// TcpHealthCheck set". The validators are called by Access*() for the version
+// that was modified and by Freeze() for the version of the frozen resource.
+// Set*() does not call the validators as the value is expected to come from
+// GCE.
+//
+// Return the list of fields that are invalid; an empty list means the object
+// is valid.
+type ResourceValidator[GA any, Alpha any, Beta any] interface {
+	ValidateGA(obj *GA) []InvalidField
+	ValidateAlpha(obj *Alpha) []InvalidField
+	ValidateBeta(obj *Beta) []InvalidField
+}
+
+// InvalidField describes a validation failure.
+type InvalidField struct {
+	// Path of the field that is invalid. This can be empty if the error is
+	// for the resource as a whole.
+	Path Path
+	// Msg describing the problem.
+	Msg string
+}
+
+// String implements Stringer.
+func (f InvalidField) String() string {
+	if len(f.Path) == 0 {
+		return f.Msg
+	}
+	return fmt.Sprintf("%s: %s", f.Path, f.Msg)
+}
+
+// ValidationError is returned from Access*() and Freeze() when the resource
+// fails a ResourceValidator. Use errors.As to get the specific details.
+type ValidationError struct {
+	// Version of the resource that was validated.
+	Version meta.Version
+	// InvalidFields is the list of failures returned by the validator.
+	InvalidFields []InvalidField
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	var msgs []string
+	for _, f := range e.InvalidFields {
+		msgs = append(msgs, f.String())
+	}
+	return fmt.Sprintf("ValidationError (%s): %s", e.Version, strings.Join(msgs, "; "))
+}
+
+// validate calls the ResourceValidator (if implemented by the TypeTrait) for
+// version ver.
+func (u *mutableResource[GA, Alpha, Beta]) validate(ver meta.Version) error {
+	rv, ok := u.typeTrait.(ResourceValidator[GA, Alpha, Beta])
+	if !ok {
+		return nil
+	}
+	var invalid []InvalidField
+	switch ver {
+	case meta.VersionGA:
+		invalid = rv.ValidateGA(&u.ga)
+	case meta.VersionAlpha:
+		invalid = rv.ValidateAlpha(&u.alpha)
+	case meta.VersionBeta:
+		invalid = rv.ValidateBeta(&u.beta)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return &ValidationError{Version: ver, InvalidFields: invalid}
+}
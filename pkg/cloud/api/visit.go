@@ -109,6 +109,9 @@ func visitImpl(p Path, v reflect.Value, a acceptor) error {
 			for i := 0; i < v.NumField(); i++ {
 				fv := v.Field(i)
 				ft := v.Type().Field(i)
+				if skipField(v.Type(), ft) {
+					continue
+				}
 				if err := visitImpl(p.Field(ft.Name), fv, a); err != nil {
 					return err
 				}
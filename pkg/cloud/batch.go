@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchParallelism is the number of concurrent calls made by Batch and
+// BatchDo if parallelism <= 0.
+const DefaultBatchParallelism = 10
+
+// BatchResult is the result of the call for Key in a Batch.
+type BatchResult[K, V any] struct {
+	Key   K
+	Value V
+	Err   error
+}
+
+// Batch calls f for each of the keys, with at most parallelism concurrent
+// calls, and returns the results in the same order as the keys. This is used
+// to issue a set of homogeneous calls to the API, e.g. to get a list of
+// NetworkEndpointGroups:
+//
+//	res := Batch(ctx, 10, keys, func(ctx context.Context, k *meta.Key) (*ga.NetworkEndpointGroup, error) {
+//		return gce.NetworkEndpointGroups().Get(ctx, k)
+//	})
+//
+// The calls still go through the RateLimiter of the Service, which bounds
+// the rate of the calls. Calls that have not started when ctx is done are not
+// made and their result is the error of the context.
+func Batch[K, V any](ctx context.Context, parallelism int, keys []K, f func(context.Context, K) (V, error)) []BatchResult[K, V] {
+	if parallelism <= 0 {
+		parallelism = DefaultBatchParallelism
+	}
+	ret := make([]BatchResult[K, V], len(keys))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, k := range keys {
+		ret[i].Key = k
+		if err := ctx.Err(); err != nil {
+			ret[i].Err = err
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			ret[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int, k K) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			ret[i].Value, ret[i].Err = f(ctx, k)
+		}(i, k)
+	}
+	wg.Wait()
+
+	return ret
+}
+
+// BatchDo calls f for each of the keys, with at most parallelism concurrent
+// calls (see Batch). This is used for mutations, e.g. to delete a list of
+// NetworkEndpointGroups:
+//
+//	err := BatchDo(ctx, 10, keys, func(ctx context.Context, k *meta.Key) error {
+//		return gce.NetworkEndpointGroups().Delete(ctx, k)
+//	})
+//
+// The returned error combines the errors of all of the failed calls (see
+// BatchError).
+func BatchDo[K any](ctx context.Context, parallelism int, keys []K, f func(context.Context, K) error) error {
+	res := Batch(ctx, parallelism, keys, func(ctx context.Context, k K) (struct{}, error) {
+		return struct{}{}, f(ctx, k)
+	})
+	return BatchError(res)
+}
+
+// BatchError returns an error combining the errors in res or nil if all of
+// the calls succeeded. The individual errors can be checked with errors.Is()
+// and errors.As().
+func BatchError[K, V any](res []BatchResult[K, V]) error {
+	var errs []error
+	for _, r := range res {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", r.Key, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	errInjected := errors.New("injected")
+
+	for _, tc := range []struct {
+		name        string
+		parallelism int
+		n           int
+		failAt      map[int]bool
+	}{
+		{name: "empty", parallelism: 2},
+		{name: "sequential", parallelism: 1, n: 5},
+		{name: "parallel", parallelism: 3, n: 20},
+		{name: "default parallelism", n: 20},
+		{name: "errors", parallelism: 4, n: 10, failAt: map[int]bool{3: true, 7: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				lock    sync.Mutex
+				running int
+				maxRun  int
+			)
+			keys := make([]int, tc.n)
+			for i := range keys {
+				keys[i] = i
+			}
+			res := Batch(context.Background(), tc.parallelism, keys, func(_ context.Context, k int) (string, error) {
+				lock.Lock()
+				running++
+				if running > maxRun {
+					maxRun = running
+				}
+				lock.Unlock()
+				defer func() {
+					lock.Lock()
+					running--
+					lock.Unlock()
+				}()
+				if tc.failAt[k] {
+					return "", errInjected
+				}
+				return fmt.Sprint(k), nil
+			})
+
+			wantMax := tc.parallelism
+			if wantMax <= 0 {
+				wantMax = DefaultBatchParallelism
+			}
+			if maxRun > wantMax {
+				t.Errorf("max concurrent calls = %d, want <= %d", maxRun, wantMax)
+			}
+			if len(res) != tc.n {
+				t.Fatalf("len(res) = %d, want %d", len(res), tc.n)
+			}
+			for i, r := range res {
+				if r.Key != i {
+					t.Errorf("res[%d].Key = %d, want %d", i, r.Key, i)
+				}
+				if tc.failAt[i] {
+					if !errors.Is(r.Err, errInjected) {
+						t.Errorf("res[%d].Err = %v, want %v", i, r.Err, errInjected)
+					}
+				} else if r.Err != nil || r.Value != fmt.Sprint(i) {
+					t.Errorf("res[%d] = %q, %v; want %q, nil", i, r.Value, r.Err, fmt.Sprint(i))
+				}
+			}
+			err := BatchError(res)
+			if gotErr, wantErr := err != nil, len(tc.failAt) > 0; gotErr != wantErr {
+				t.Errorf("BatchError() = %v; gotErr = %t, want %t", err, gotErr, wantErr)
+			}
+		})
+	}
+}
+
+func TestBatchCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := BatchDo(ctx, 2, []int{1, 2, 3}, func(context.Context, int) error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Errorf("f was called, want no calls")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("BatchDo() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestBatchDoMock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	var keys []*meta.Key
+	for i := 0; i < 50; i++ {
+		k := meta.ZonalKey(fmt.Sprintf("neg-%d", i), "us-central1-b")
+		if err := mock.NetworkEndpointGroups().Insert(ctx, k, &ga.NetworkEndpointGroup{}); err != nil {
+			t.Fatalf("Insert(%v) = %v", k, err)
+		}
+		keys = append(keys, k)
+	}
+	missing := meta.ZonalKey("missing", "us-central1-b")
+	keys = append(keys, missing)
+
+	err := BatchDo(ctx, 10, keys, func(ctx context.Context, k *meta.Key) error {
+		return mock.NetworkEndpointGroups().Delete(ctx, k)
+	})
+	if err == nil {
+		t.Fatalf("BatchDo() = nil, want error for %v", missing)
+	}
+	negs, err := mock.NetworkEndpointGroups().List(ctx, "us-central1-b", nil)
+	if err != nil || len(negs) != 0 {
+		t.Errorf("List() = %d items, %v; want 0 items, nil", len(negs), err)
+	}
+}
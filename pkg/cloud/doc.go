@@ -35,10 +35,25 @@ limitations under the License.
 //  // Run foo with a mock.
 //  foo(NewMockGCE())
 //
+// List returns all of the objects at once. Use ListPages to process the
+// objects page by page as they are received and to stop early by returning
+// ErrStopPages.
+//
 // Rate limiting and routing
 //
 // The generated code allows for custom policies for operation rate limiting
 // and GCE project routing. See RateLimiter and ProjectRouter for more details.
+// Transient errors can be retried by setting Service.RetryPolicy (see
+// RetryPolicy) and default timeouts can be set per service and operation with
+// Service.CallTimeouts (see CallTimeouts). Each call emits an OpenTelemetry
+// span (see Service.TracerProvider) and call counts, latencies and error
+// codes can be exported by setting Service.Metrics (see Metrics). The HTTP
+// requests can be logged with Service.Interceptor (see Interceptor). Other
+// instrumentation can be added with Service.Observers (see ServiceObserver).
+// The API usage can be billed to another project with Service.UserProject or
+// per call with the UserProject option.
+// Batch and BatchDo issue a set of homogeneous calls in parallel, e.g. to
+// delete many NetworkEndpointGroups.
 //
 // Mocks
 //
@@ -52,6 +67,14 @@ limitations under the License.
 // objects, i.e. an alpha object will be visible with beta and GA methods.
 // Note that translation is done with JSON serialization between the API versions.
 //
+// The mocks are safe for concurrent use. Errors can be scripted per method with
+// ErrorSequences, latency added with Latencies and MockGCE.RandomizeInterleavings
+// shuffles the order of concurrent calls. The objects in a MockGCE can be saved
+// and restored with DumpState and LoadState (see MockState). Setting Validator
+// rejects invalid objects on Insert and Update with a 400 error (see
+// MockValidator). MockGCE.AsyncOperations makes Insert and Delete wait for
+// simulated long running operations (see MockOperations).
+//
 // Changing service code generation
 //
 // The list of services to generate is contained in "meta/meta.go". To add a
@@ -70,6 +93,10 @@ limitations under the License.
 //    options: <options>              // Or'd ("|") together.
 //  }
 //
+// Services and API groups can also be added without changing these lists
+// with meta.RegisterAPIGroup and meta.RegisterServices (see
+// meta.ServiceSpec), e.g. from an init() function in a fork.
+//
 // Read-only objects
 //
 // Services such as Regions and Zones do not allow for mutations. Specify
@@ -0,0 +1,224 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Builder builds a filter on the fields of a resource type. The field names
+// and the types of the values are checked against the type when the filter
+// is built instead of failing (or silently matching nothing) at the API:
+//
+//	f, err := filter.For(&compute.ForwardingRule{}).
+//		Eq("LoadBalancingScheme", "INTERNAL").
+//		Ne("Network", network).
+//		Filter()
+//
+// Fields are named by their Go name (e.g. "LoadBalancingScheme") or their
+// API name (e.g. "loadBalancingScheme"). Nested fields are separated by "."
+// (e.g. "Scheduling.AutomaticRestart"). Only string, integer and boolean
+// fields can be compared.
+type Builder struct {
+	t    reflect.Type
+	f    *F
+	errs []error
+}
+
+// For returns a Builder for filters on the type of obj, which must be a
+// struct or a pointer to a struct, e.g. &compute.ForwardingRule{}.
+func For(obj interface{}) *Builder {
+	b := &Builder{f: &F{}}
+	t := reflect.TypeOf(obj)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		b.errs = append(b.errs, fmt.Errorf("filter.For(%T): not a struct", obj))
+		return b
+	}
+	b.t = t
+	return b
+}
+
+// Eq adds a predicate for fieldName equal to v. String values are compared
+// literally (see EqualString).
+func (b *Builder) Eq(fieldName string, v interface{}) *Builder {
+	return b.add(fieldName, v, false)
+}
+
+// Ne adds a predicate for fieldName not equal to v.
+func (b *Builder) Ne(fieldName string, v interface{}) *Builder {
+	return b.add(fieldName, v, true)
+}
+
+// Regexp adds a predicate for the string fieldName matching the regular
+// expression v (see Regexp).
+func (b *Builder) Regexp(fieldName, v string) *Builder {
+	if path, ok := b.field(fieldName, v); ok {
+		b.f.AndRegexp(path, v)
+	}
+	return b
+}
+
+// NotRegexp adds a predicate for the string fieldName not matching the
+// regular expression v (see NotRegexp).
+func (b *Builder) NotRegexp(fieldName, v string) *Builder {
+	if path, ok := b.field(fieldName, v); ok {
+		b.f.AndNotRegexp(path, v)
+	}
+	return b
+}
+
+// In adds a predicate for the string fieldName equal to one of values (see
+// In).
+func (b *Builder) In(fieldName string, values ...string) *Builder {
+	if path, ok := b.field(fieldName, ""); ok {
+		b.f.AndIn(path, values...)
+	}
+	return b
+}
+
+// NotIn adds a predicate for the string fieldName not equal to any of
+// values (see NotIn).
+func (b *Builder) NotIn(fieldName string, values ...string) *Builder {
+	if path, ok := b.field(fieldName, ""); ok {
+		b.f.AndNotIn(path, values...)
+	}
+	return b
+}
+
+// Filter returns the filter or the errors for the invalid fields and
+// values.
+func (b *Builder) Filter() (*F, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	return b.f, nil
+}
+
+func (b *Builder) add(fieldName string, v interface{}, not bool) *Builder {
+	path, ok := b.field(fieldName, v)
+	if !ok {
+		return b
+	}
+	switch x := reflect.ValueOf(v); x.Kind() {
+	case reflect.String:
+		if not {
+			b.f.AndNotEqualString(path, x.String())
+		} else {
+			b.f.AndEqualString(path, x.String())
+		}
+	case reflect.Bool:
+		if not {
+			b.f.AndNotEqualBool(path, x.Bool())
+		} else {
+			b.f.AndEqualBool(path, x.Bool())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if not {
+			b.f.AndNotEqualInt(path, int(x.Int()))
+		} else {
+			b.f.AndEqualInt(path, int(x.Int()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if not {
+			b.f.AndNotEqualInt(path, int(x.Uint()))
+		} else {
+			b.f.AndEqualInt(path, int(x.Uint()))
+		}
+	}
+	return b
+}
+
+// field checks that fieldName is a field of b.t that can be compared with v
+// and returns the path of the field with the API names.
+func (b *Builder) field(fieldName string, v interface{}) (string, bool) {
+	if b.t == nil {
+		return "", false
+	}
+	errorf := func(format string, args ...interface{}) (string, bool) {
+		b.errs = append(b.errs, fmt.Errorf("filter.For(%v): field %q: %s", b.t, fieldName, fmt.Sprintf(format, args...)))
+		return "", false
+	}
+
+	t := b.t
+	var path []string
+	for _, part := range strings.Split(fieldName, ".") {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return errorf("%v is not a struct", t)
+		}
+		sf, name, ok := lookupField(t, part)
+		if !ok {
+			return errorf("no field %q in %v", part, t)
+		}
+		path = append(path, name)
+		t = sf.Type
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	vt := reflect.TypeOf(v)
+	if vt == nil {
+		return errorf("nil value")
+	}
+	switch {
+	case t.Kind() == reflect.String && vt.Kind() == reflect.String:
+	case t.Kind() == reflect.Bool && vt.Kind() == reflect.Bool:
+	case isInt(t.Kind()) && isInt(vt.Kind()):
+	case t.Kind() == reflect.String || t.Kind() == reflect.Bool || isInt(t.Kind()):
+		return errorf("cannot compare a field of type %v with %T", t, v)
+	default:
+		return errorf("cannot filter on a field of type %v", t)
+	}
+	return strings.Join(path, "."), true
+}
+
+// lookupField returns the field of t with the Go or the API name name, and
+// its API name.
+func lookupField(t reflect.Type, name string) (reflect.StructField, string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		apiName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if apiName == "" || apiName == "-" {
+			apiName = strings.ToLower(sf.Name[:1]) + sf.Name[1:]
+		}
+		if name == sf.Name || name == apiName {
+			return sf, apiName, true
+		}
+	}
+	return reflect.StructField{}, "", false
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
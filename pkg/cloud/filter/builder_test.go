@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc string
+		b    *Builder
+		want string
+	}{
+		{
+			desc: "go name",
+			b:    For(&compute.ForwardingRule{}).Eq("LoadBalancingScheme", "INTERNAL"),
+			want: "loadBalancingScheme eq INTERNAL",
+		},
+		{
+			desc: "api name",
+			b:    For(compute.ForwardingRule{}).Ne("loadBalancingScheme", "INTERNAL"),
+			want: "loadBalancingScheme ne INTERNAL",
+		},
+		{
+			desc: "literal string",
+			b:    For(&compute.ForwardingRule{}).Eq("IPAddress", "10.0.0.1"),
+			want: `IPAddress eq 10\.0\.0\.1`,
+		},
+		{
+			desc: "regexp",
+			b:    For(&compute.ForwardingRule{}).Regexp("Name", "k8s-.*").NotRegexp("name", "k8s-a"),
+			want: "(name eq k8s-.*) (name ne k8s-a)",
+		},
+		{
+			desc: "in",
+			b:    For(&compute.ForwardingRule{}).In("Name", "a", "b").NotIn("IPProtocol", "UDP"),
+			want: `((name = "a") OR (name = "b")) AND (IPProtocol != "UDP")`,
+		},
+		{
+			desc: "int64 field",
+			b:    For(&compute.Route{}).Eq("Priority", 1000),
+			want: "priority eq 1000",
+		},
+		{
+			desc: "nested pointer to bool",
+			b:    For(&compute.Instance{}).Eq("Scheduling.AutomaticRestart", true),
+			want: "scheduling.automaticRestart eq true",
+		},
+	} {
+		f, err := tc.b.Filter()
+		if err != nil {
+			t.Errorf("%s: Filter() = _, %v; want nil", tc.desc, err)
+			continue
+		}
+		if got := f.String(); got != tc.want {
+			t.Errorf("%s: String() = %q, want %q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc string
+		b    *Builder
+	}{
+		{desc: "not a struct", b: For("abc").Eq("Name", "abc")},
+		{desc: "nil", b: For(nil)},
+		{desc: "no such field", b: For(&compute.ForwardingRule{}).Eq("LoadBalancerScheme", "INTERNAL")},
+		{desc: "no such nested field", b: For(&compute.Instance{}).Eq("Scheduling.Restart", true)},
+		{desc: "not a struct field", b: For(&compute.Instance{}).Eq("Name.Foo", "abc")},
+		{desc: "wrong type", b: For(&compute.Route{}).Eq("Priority", "1000")},
+		{desc: "wrong type for regexp", b: For(&compute.Route{}).Regexp("Priority", "1.*")},
+		{desc: "nil value", b: For(&compute.Route{}).Eq("Name", nil)},
+		{desc: "in on int field", b: For(&compute.Route{}).In("Priority", "1")},
+		{desc: "unsupported field", b: For(&compute.Route{}).Eq("Tags", "abc")},
+		{desc: "one invalid field", b: For(&compute.Route{}).Eq("Name", "abc").Eq("Nme", "abc")},
+	} {
+		if f, err := tc.b.Filter(); err == nil {
+			t.Errorf("%s: Filter() = %v, nil; want error", tc.desc, f)
+		}
+	}
+}
+
+func TestBuilderMatch(t *testing.T) {
+	t.Parallel()
+
+	f, err := For(&compute.Instance{}).
+		Eq("Name", "vm.1").
+		Eq("Scheduling.AutomaticRestart", true).
+		Ne("Id", 10).
+		Filter()
+	if err != nil {
+		t.Fatalf("Filter() = _, %v; want nil", err)
+	}
+	restart := true
+	for _, tc := range []struct {
+		obj  *compute.Instance
+		want bool
+	}{
+		{obj: &compute.Instance{Name: "vm.1", Id: 11, Scheduling: &compute.Scheduling{AutomaticRestart: &restart}}, want: true},
+		{obj: &compute.Instance{Name: "vm-1", Id: 11, Scheduling: &compute.Scheduling{AutomaticRestart: &restart}}},
+		{obj: &compute.Instance{Name: "vm.1", Id: 10, Scheduling: &compute.Scheduling{AutomaticRestart: &restart}}},
+		{obj: &compute.Instance{Name: "vm.1", Id: 11}},
+	} {
+		if got := f.Match(tc.obj); got != tc.want {
+			t.Errorf("%v: Match(%+v) = %t, want %t", f, tc.obj, got, tc.want)
+		}
+	}
+}
@@ -25,6 +25,13 @@ limitations under the License.
 //  // List on multiple conditions.
 //  f := filter.Regexp("name", "homer.*").AndNotRegexp("name", "homers")
 //  c.GlobalAddresses().List(ctx, f)
+//
+//  // List with OR and NOT.
+//  f := filter.Or(filter.Regexp("name", "marge"), filter.Regexp("name", "lisa")).AndNot(filter.EqualInt("priority", 1))
+//  c.Routes().List(ctx, f)
+//
+//  // Check the fields against the resource type (see Builder).
+//  f, err := filter.For(&compute.ForwardingRule{}).Eq("LoadBalancingScheme", "INTERNAL").Filter()
 package filter
 
 import (
@@ -53,6 +60,17 @@ func NotRegexp(fieldName, v string) *F {
 	return (&F{}).AndNotRegexp(fieldName, v)
 }
 
+// EqualString returns a filter for fieldName equal to the string v. Unlike
+// Regexp, v is not a regular expression.
+func EqualString(fieldName, v string) *F {
+	return (&F{}).AndEqualString(fieldName, v)
+}
+
+// NotEqualString returns a filter for fieldName not equal to the string v.
+func NotEqualString(fieldName, v string) *F {
+	return (&F{}).AndNotEqualString(fieldName, v)
+}
+
 // EqualInt returns a filter for fieldName eq v.
 func EqualInt(fieldName string, v int) *F {
 	return (&F{}).AndEqualInt(fieldName, v)
@@ -73,6 +91,38 @@ func NotEqualBool(fieldName string, v bool) *F {
 	return (&F{}).AndNotEqualBool(fieldName, v)
 }
 
+// HasPrefix returns a filter for the string fieldName starting with prefix.
+func HasPrefix(fieldName, prefix string) *F {
+	return (&F{}).AndHasPrefix(fieldName, prefix)
+}
+
+// In returns a filter for the string fieldName equal to one of values. It
+// is expanded to (fieldName = values[0]) OR (fieldName = values[1]) ... If
+// values is empty, no resource matches the filter.
+func In(fieldName string, values ...string) *F {
+	return (&F{}).AndIn(fieldName, values...)
+}
+
+// NotIn returns a filter for the string fieldName not equal to any of
+// values.
+func NotIn(fieldName string, values ...string) *F {
+	return (&F{}).AndNotIn(fieldName, values...)
+}
+
+// Or returns a filter matching the resources that match any of filters. A
+// nil or empty filter matches all of the resources, so does the returned
+// filter if one of filters is empty.
+func Or(filters ...*F) *F {
+	return (&F{}).AndOr(filters...)
+}
+
+// Not returns a filter matching the resources that do not match f. If f is
+// nil or empty, it is ignored and the returned filter matches all of the
+// resources.
+func Not(f *F) *F {
+	return (&F{}).AndNot(f)
+}
+
 // F is a filter to be used with List() operations.
 //
 // From the compute API description:
@@ -100,18 +150,52 @@ func NotEqualBool(fieldName string, v bool) *F {
 // parentheses. For example, (scheduling.automaticRestart eq true)
 // (zone eq us-central1-f). Multiple expressions are treated as AND expressions,
 // meaning that resources must match all expressions to pass the filters.
+//
+// The eq and ne comparisons cannot be used with the OR and NOT operators. A
+// filter that contains an Or or a Not group is written with the = and !=
+// comparisons instead, e.g. (name = "abc") OR (NOT (priority = 10)). In
+// that form, string values are quoted and compared literally, not as
+// regular expressions.
 type F struct {
 	predicates []filterPredicate
 }
 
-// TODO(rramkumar): Support logical OR
-
 // And joins two filters together.
 func (fl *F) And(rest *F) *F {
 	fl.predicates = append(fl.predicates, rest.predicates...)
 	return fl
 }
 
+// Or replaces fl with the group (fl) OR (rest[0]) OR ... (see Or).
+func (fl *F) Or(rest ...*F) *F {
+	filters := append([]*F{{predicates: fl.predicates}}, rest...)
+	fl.predicates = nil
+	return fl.AndOr(filters...)
+}
+
+// AndOr adds the group (filters[0]) OR (filters[1]) OR ... (see Or).
+func (fl *F) AndOr(filters ...*F) *F {
+	var group []*F
+	for _, f := range filters {
+		if f.empty() {
+			return fl
+		}
+		group = append(group, f.clone())
+	}
+	if len(group) > 0 {
+		fl.predicates = append(fl.predicates, filterPredicate{or: group})
+	}
+	return fl
+}
+
+// AndNot adds the group NOT (rest) (see Not).
+func (fl *F) AndNot(rest *F) *F {
+	if !rest.empty() {
+		fl.predicates = append(fl.predicates, filterPredicate{not: rest.clone()})
+	}
+	return fl
+}
+
 // AndRegexp adds a field ~ string predicate.
 func (fl *F) AndRegexp(fieldName, v string) *F {
 	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: regexpEquals, s: &v})
@@ -124,6 +208,49 @@ func (fl *F) AndNotRegexp(fieldName, v string) *F {
 	return fl
 }
 
+// AndEqualString adds a field = string predicate.
+func (fl *F) AndEqualString(fieldName, v string) *F {
+	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: equals, s: &v})
+	return fl
+}
+
+// AndNotEqualString adds a field != string predicate.
+func (fl *F) AndNotEqualString(fieldName, v string) *F {
+	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: notEquals, s: &v})
+	return fl
+}
+
+// AndHasPrefix adds a field ~ prefix.* predicate.
+func (fl *F) AndHasPrefix(fieldName, prefix string) *F {
+	return fl.AndRegexp(fieldName, regexp.QuoteMeta(prefix)+".*")
+}
+
+// AndIn adds the group (field = values[0]) OR (field = values[1]) ... (see
+// In).
+func (fl *F) AndIn(fieldName string, values ...string) *F {
+	switch len(values) {
+	case 0:
+		// Nothing is in the empty set. A contradiction is used as there
+		// is no false literal.
+		return fl.AndEqualString(fieldName, "").AndNotEqualString(fieldName, "")
+	case 1:
+		return fl.AndEqualString(fieldName, values[0])
+	}
+	var filters []*F
+	for _, v := range values {
+		filters = append(filters, EqualString(fieldName, v))
+	}
+	return fl.AndOr(filters...)
+}
+
+// AndNotIn adds a field != v predicate for each of values.
+func (fl *F) AndNotIn(fieldName string, values ...string) *F {
+	for _, v := range values {
+		fl.AndNotEqualString(fieldName, v)
+	}
+	return fl
+}
+
 // AndEqualInt adds a field = int predicate.
 func (fl *F) AndEqualInt(fieldName string, v int) *F {
 	fl.predicates = append(fl.predicates, filterPredicate{fieldName: fieldName, op: equals, i: &v})
@@ -149,6 +276,9 @@ func (fl *F) AndNotEqualBool(fieldName string, v bool) *F {
 }
 
 func (fl *F) String() string {
+	if fl.hasGroups() {
+		return fl.expression()
+	}
 	if len(fl.predicates) == 1 {
 		return fl.predicates[0].String()
 	}
@@ -160,16 +290,59 @@ func (fl *F) String() string {
 	return strings.Join(pl, " ")
 }
 
+// expression returns fl with the = and != comparisons and explicit AND
+// operators.
+func (fl *F) expression() string {
+	if len(fl.predicates) == 1 {
+		return fl.predicates[0].expression()
+	}
+
+	var pl []string
+	for _, p := range fl.predicates {
+		pl = append(pl, "("+p.expression()+")")
+	}
+	return strings.Join(pl, " AND ")
+}
+
+// hasGroups returns true if fl contains an Or or a Not group.
+func (fl *F) hasGroups() bool {
+	if fl == nil {
+		return false
+	}
+	for _, p := range fl.predicates {
+		if p.or != nil || p.not != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (fl *F) empty() bool {
+	return fl == nil || len(fl.predicates) == 0
+}
+
+// clone returns a copy of fl that is not changed by further calls to the
+// And methods of fl.
+func (fl *F) clone() *F {
+	return &F{predicates: append([]filterPredicate(nil), fl.predicates...)}
+}
+
 // Match returns true if the F as specifies matches the given object. This
 // is used by the Mock implementations to perform filtering and SHOULD NOT be
 // used in production code as it is not well-tested to be equivalent to the
 // actual compute API.
 func (fl *F) Match(obj interface{}) bool {
+	return fl.match(obj, fl.hasGroups())
+}
+
+// match returns true if obj matches fl. If literal is true, strings are
+// compared literally as with the = and != comparisons.
+func (fl *F) match(obj interface{}, literal bool) bool {
 	if fl == nil {
 		return true
 	}
 	for _, p := range fl.predicates {
-		if !p.match(obj) {
+		if !p.match(obj, literal) {
 			return false
 		}
 	}
@@ -185,7 +358,8 @@ const (
 	notEquals       filterOp = iota
 )
 
-// filterPredicate is an individual predicate for a fieldName and value.
+// filterPredicate is an individual predicate for a fieldName and value, or a
+// group of filters if or or not is set.
 type filterPredicate struct {
 	fieldName string
 
@@ -193,6 +367,11 @@ type filterPredicate struct {
 	s  *string
 	i  *int
 	b  *bool
+
+	// or matches if any of the filters match.
+	or []*F
+	// not matches if the filter does not match.
+	not *F
 }
 
 func (fp *filterPredicate) String() string {
@@ -215,10 +394,11 @@ func (fp *filterPredicate) String() string {
 
 	var value string
 	switch {
+	case fp.s != nil && (fp.op == equals || fp.op == notEquals):
+		// eq and ne compare with a regular expression.
+		value = regexpLiteral(regexp.QuoteMeta(*fp.s))
 	case fp.s != nil:
-		// There does not seem to be any sort of escaping as specified in the
-		// document. This means it's possible to create malformed expressions.
-		value = *fp.s
+		value = regexpLiteral(*fp.s)
 	case fp.i != nil:
 		value = fmt.Sprintf("%d", *fp.i)
 	case fp.b != nil:
@@ -230,7 +410,71 @@ func (fp *filterPredicate) String() string {
 	return fmt.Sprintf("%s %s %s", fp.fieldName, op, value)
 }
 
-func (fp *filterPredicate) match(o interface{}) bool {
+// expression returns the predicate with the = and != comparisons.
+func (fp *filterPredicate) expression() string {
+	switch {
+	case fp.or != nil:
+		var pl []string
+		for _, f := range fp.or {
+			pl = append(pl, "("+f.expression()+")")
+		}
+		return strings.Join(pl, " OR ")
+	case fp.not != nil:
+		return "NOT (" + fp.not.expression() + ")"
+	}
+
+	op := "="
+	if fp.op == regexpNotEquals || fp.op == notEquals {
+		op = "!="
+	}
+
+	var value string
+	switch {
+	case fp.s != nil:
+		value = quote(*fp.s)
+	case fp.i != nil:
+		value = fmt.Sprintf("%d", *fp.i)
+	case fp.b != nil:
+		value = fmt.Sprintf("%t", *fp.b)
+	default:
+		value = "invalidValue"
+	}
+
+	return fmt.Sprintf("%s %s %s", fp.fieldName, op, value)
+}
+
+// regexpLiteral returns the regular expression re as a literal for the eq
+// and ne comparisons. The API does not define any escaping for the
+// literals, so re is quoted if it is empty or contains spaces, quotes or
+// parentheses and the double quotes are written as \x22, which is the same
+// character for RE2.
+func regexpLiteral(re string) string {
+	if re != "" && !strings.ContainsAny(re, " \t\r\n\"'()") {
+		return re
+	}
+	return `"` + strings.ReplaceAll(re, `"`, `\x22`) + `"`
+}
+
+// quote returns s as a double quoted string literal.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func (fp *filterPredicate) match(o interface{}, literal bool) bool {
+	switch {
+	case fp.or != nil:
+		for _, f := range fp.or {
+			if f.match(o, literal) {
+				return true
+			}
+		}
+		return false
+	case fp.not != nil:
+		return !fp.not.match(o, literal)
+	}
+
 	v, err := extractValue(fp.fieldName, o)
 	klog.V(6).Infof("extractValue(%q, %#v) = %v, %v", fp.fieldName, o, v, err)
 	if err != nil {
@@ -243,15 +487,17 @@ func (fp *filterPredicate) match(o interface{}) bool {
 		if fp.s == nil {
 			return false
 		}
-		re, err := regexp.Compile(*fp.s)
+		match = x == *fp.s
+		if literal || fp.op == equals || fp.op == notEquals {
+			break
+		}
+		// The regexp must match the entire field.
+		re, err := regexp.Compile("^(?:" + *fp.s + ")$")
 		if err != nil {
 			klog.Errorf("Match regexp %q is invalid: %v", *fp.s, err)
 			return false
 		}
-		match = x == *fp.s
-		if fp.op < regexpNotEquals {
-			match = re.Match([]byte(x))
-		}
+		match = re.MatchString(x)
 	case int:
 		if fp.i == nil {
 			return false
@@ -313,9 +559,19 @@ func extractValue(path string, o interface{}) (interface{}, error) {
 		}
 		o = v.Interface()
 	}
-	switch o.(type) {
-	case string, int, bool:
-		return o, nil
+	v := reflect.ValueOf(o)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), nil
+	case reflect.Bool:
+		return v.Bool(), nil
 	}
 	return nil, fmt.Errorf("unhandled object of type %T", o)
 }
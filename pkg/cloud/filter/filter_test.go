@@ -34,6 +34,8 @@ func TestFilterToString(t *testing.T) {
 		{NotEqualInt("field1", 13), "field1 ne 13"},
 		{EqualBool("field1", true), "field1 eq true"},
 		{NotEqualBool("field1", true), "field1 ne true"},
+		{EqualString("field1", "a.b"), `field1 eq a\.b`},
+		{NotEqualString("field1", "abc"), "field1 ne abc"},
 		{Regexp("field1", "abc").AndRegexp("field2", "def"), `(field1 eq abc) (field2 eq def)`},
 		{Regexp("field1", "abc").AndNotEqualInt("field2", 17), `(field1 eq abc) (field2 ne 17)`},
 		{Regexp("field1", "abc").And(EqualInt("field2", 17)), `(field1 eq abc) (field2 eq 17)`},
@@ -81,6 +83,10 @@ func TestFilterMatch(t *testing.T) {
 		{f: Regexp("s", "abcd").AndEqualBool("b", true), o: &S{S: "abc"}},
 		{f: Regexp("s", "abc").AndEqualBool("b", true), o: &S{S: "abc", B: true}, want: true},
 		{f: Regexp("s", "abc").And(EqualBool("b", true)), o: &S{S: "abc", B: true}, want: true},
+		{f: EqualString("s", "a.c"), o: &S{S: "abc"}},
+		{f: EqualString("s", "a.c"), o: &S{S: "a.c"}, want: true},
+		{f: NotEqualString("s", "a.c"), o: &S{S: "abc"}, want: true},
+		{f: EqualString("s", "a((("), o: &S{S: "a((("}, want: true},
 		{f: Regexp("unhandled", "xyz"), o: &S{}},
 		{f: Regexp("nested_field.x", "xyz"), o: &S{}},
 		{f: Regexp("nested_field.x", "xyz"), o: &S{NestedField: &inner{"xyz"}}, want: true},
@@ -95,6 +101,142 @@ func TestFilterMatch(t *testing.T) {
 	}
 }
 
+func TestFilterGroupsToString(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		f    *F
+		want string
+	}{
+		{Or(Regexp("field1", "abc"), EqualInt("field2", 13)), `(field1 = "abc") OR (field2 = 13)`},
+		{Not(EqualBool("field1", true)), `NOT (field1 = true)`},
+		{Not(NotRegexp("field1", "abc")), `NOT (field1 != "abc")`},
+		{Regexp("field1", "abc").Or(Regexp("field1", "def")), `(field1 = "abc") OR (field1 = "def")`},
+		{
+			Regexp("field1", "abc").AndOr(EqualInt("field2", 1), EqualInt("field2", 2)),
+			`(field1 = "abc") AND ((field2 = 1) OR (field2 = 2))`,
+		},
+		{
+			Or(Regexp("field1", "abc").AndEqualBool("field2", true), Not(EqualInt("field3", 3))),
+			`((field1 = "abc") AND (field2 = true)) OR (NOT (field3 = 3))`,
+		},
+		{EqualInt("field1", 1).AndNot(Or(EqualInt("field2", 2), EqualInt("field2", 3))), `(field1 = 1) AND (NOT ((field2 = 2) OR (field2 = 3)))`},
+		{Or(Regexp("field1", `a"b\c`)), `(field1 = "a\"b\\c")`},
+		// Empty filters match everything.
+		{Regexp("field1", "abc").AndOr(EqualInt("field2", 1), None), `field1 eq abc`},
+		{Regexp("field1", "abc").AndNot(&F{}), `field1 eq abc`},
+		{Or(), ``},
+	} {
+		if got := tc.f.String(); got != tc.want {
+			t.Errorf("filter %#v String() = %q, want %q", tc.f, got, tc.want)
+		}
+	}
+
+	// The groups are not changed by the filters they were created from.
+	f1 := EqualInt("field1", 1)
+	f := Not(f1)
+	f1.AndEqualInt("field2", 2)
+	if got, want := f.String(), `NOT (field1 = 1)`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFilterGroupsMatch(t *testing.T) {
+	t.Parallel()
+
+	type S struct {
+		S string
+		I int
+		B bool
+	}
+
+	for _, tc := range []struct {
+		f    *F
+		o    interface{}
+		want bool
+	}{
+		{f: Or(EqualInt("i", 1), EqualInt("i", 2)), o: &S{I: 1}, want: true},
+		{f: Or(EqualInt("i", 1), EqualInt("i", 2)), o: &S{I: 2}, want: true},
+		{f: Or(EqualInt("i", 1), EqualInt("i", 2)), o: &S{I: 3}},
+		{f: Not(EqualInt("i", 1)), o: &S{I: 1}},
+		{f: Not(EqualInt("i", 1)), o: &S{I: 2}, want: true},
+		{f: EqualBool("b", true).AndOr(EqualInt("i", 1), EqualInt("i", 2)), o: &S{I: 1}},
+		{f: EqualBool("b", true).AndOr(EqualInt("i", 1), EqualInt("i", 2)), o: &S{I: 1, B: true}, want: true},
+		{f: EqualInt("i", 1).Or(EqualBool("b", true)), o: &S{B: true}, want: true},
+		{f: Not(Or(EqualInt("i", 1), EqualBool("b", true))), o: &S{I: 2}, want: true},
+		{f: Not(Or(EqualInt("i", 1), EqualBool("b", true))), o: &S{I: 2, B: true}},
+		// Strings are compared literally with groups.
+		{f: Or(Regexp("s", "a.*")), o: &S{S: "abc"}},
+		{f: Or(Regexp("s", "a.*")), o: &S{S: "a.*"}, want: true},
+		{f: Not(Regexp("s", "a(((")), o: &S{S: "abc"}, want: true},
+		{f: Or(EqualInt("i", 1), None), o: &S{I: 2}, want: true},
+	} {
+		if got := tc.f.Match(tc.o); got != tc.want {
+			t.Errorf("%v: Match(%+v) = %v, want %v", tc.f, tc.o, got, tc.want)
+		}
+	}
+}
+
+func TestFilterHelpersToString(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		f    *F
+		want string
+	}{
+		{Regexp("field1", "a b"), `field1 eq "a b"`},
+		{Regexp("field1", `a"b`), `field1 eq "a\x22b"`},
+		{Regexp("field1", "(a|b)"), `field1 eq "(a|b)"`},
+		{EqualString("field1", ""), `field1 eq ""`},
+		{HasPrefix("field1", "k8s-"), `field1 eq k8s-.*`},
+		{HasPrefix("field1", "k8s.io/"), `field1 eq k8s\.io/.*`},
+		{In("field1", "a"), `field1 eq a`},
+		{In("field1", "a", "b.c"), `(field1 = "a") OR (field1 = "b.c")`},
+		{In("field1"), `(field1 eq "") (field1 ne "")`},
+		{NotIn("field1", "a", "b.c"), `(field1 ne a) (field1 ne b\.c)`},
+		{NotIn("field1"), ``},
+		{EqualBool("field1", true).AndIn("field2", "a", "b"), `(field1 = true) AND ((field2 = "a") OR (field2 = "b"))`},
+	} {
+		if got := tc.f.String(); got != tc.want {
+			t.Errorf("filter %#v String() = %q, want %q", tc.f, got, tc.want)
+		}
+	}
+}
+
+func TestFilterHelpersMatch(t *testing.T) {
+	t.Parallel()
+
+	type S struct {
+		S string
+	}
+
+	for _, tc := range []struct {
+		f    *F
+		o    interface{}
+		want bool
+	}{
+		{f: HasPrefix("s", "k8s."), o: &S{S: "k8s.abc"}, want: true},
+		{f: HasPrefix("s", "k8s."), o: &S{S: "k8s-abc"}},
+		{f: HasPrefix("s", "k8s."), o: &S{S: "a-k8s.abc"}},
+		// The regexp must match the entire field.
+		{f: Regexp("s", "b"), o: &S{S: "abc"}},
+		{f: NotRegexp("s", "a.*"), o: &S{S: "abc"}},
+		{f: NotRegexp("s", "b"), o: &S{S: "abc"}, want: true},
+		{f: In("s", "a", "b"), o: &S{S: "b"}, want: true},
+		{f: In("s", "a", "b"), o: &S{S: "c"}},
+		{f: In("s", "a.*"), o: &S{S: "abc"}},
+		{f: In("s"), o: &S{S: ""}},
+		{f: In("s"), o: &S{S: "abc"}},
+		{f: NotIn("s", "a", "b"), o: &S{S: "b"}},
+		{f: NotIn("s", "a", "b"), o: &S{S: "c"}, want: true},
+		{f: NotIn("s"), o: &S{S: "c"}, want: true},
+	} {
+		if got := tc.f.Match(tc.o); got != tc.want {
+			t.Errorf("%v: Match(%+v) = %v, want %v", tc.f, tc.o, got, tc.want)
+		}
+	}
+}
+
 func TestFilterSnakeToCamelCase(t *testing.T) {
 	t.Parallel()
 
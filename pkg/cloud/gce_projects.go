@@ -59,18 +59,29 @@ func (g *GCEProjects) Get(ctx context.Context, projectID string) (*compute.Proje
 		Version:   meta.Version("ga"),
 		Service:   "Projects",
 	}
+	ctx, cancel := g.s.callContext(ctx, rk, allOptions{})
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, rk)
+	defer span.End()
+	ctx = g.s.startCall(ctx, rk)
 	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+		recordSpanError(ctx, err)
 		return nil, err
 	}
 	call := g.s.GA.Projects.Get(projectID)
 	call.Context(ctx)
 	v, err := call.Do()
+	recordSpanError(ctx, err)
+	endCall(ctx, rk, err)
 	g.s.RateLimiter.Observe(ctx, err, rk)
 	return v, err
 }
 
 // SetCommonInstanceMetadata for a given project.
 func (m *MockProjects) SetCommonInstanceMetadata(ctx context.Context, projectID string, meta *compute.Metadata) error {
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
 	if m.X == nil {
 		m.X = &MockProjectOpsState{metadata: map[string]*compute.Metadata{}}
 	}
@@ -87,16 +98,26 @@ func (g *GCEProjects) SetCommonInstanceMetadata(ctx context.Context, projectID s
 		Version:   meta.Version("ga"),
 		Service:   "Projects",
 	}
+	ctx, cancel := g.s.callContext(ctx, rk, allOptions{})
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, rk)
+	defer span.End()
+	ctx = g.s.startCall(ctx, rk)
 	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
+		recordSpanError(ctx, err)
 		return err
 	}
 	call := g.s.GA.Projects.SetCommonInstanceMetadata(projectID, m)
 	call.Context(ctx)
 
 	op, err := call.Do()
+	recordSpanError(ctx, err)
+	endCall(ctx, rk, err)
 	g.s.RateLimiter.Observe(ctx, err, rk)
 	if err != nil {
 		return err
 	}
-	return g.s.WaitForCompletion(ctx, op)
+	err = g.s.WaitForCompletion(ctx, op)
+	recordSpanError(ctx, err)
+	return err
 }
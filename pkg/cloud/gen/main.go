@@ -35,16 +35,11 @@ import (
 )
 
 const (
-	gofmt                      = "gofmt"
-	packageRoot                = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
-	googleAPIPackage           = "google.golang.org/api/googleapi"
-	kLogPackage                = "k8s.io/klog/v2"
-	alphaComputePackage        = "google.golang.org/api/compute/v0.alpha"
-	betaComputePackage         = "google.golang.org/api/compute/v0.beta"
-	gaComputePackage           = "google.golang.org/api/compute/v1"
-	betaNetworkServicesPackage = "google.golang.org/api/networkservices/v1beta1"
-	gaNetworkServicesPackage   = "google.golang.org/api/networkservices/v1"
-	kLogEnabled                = ".Enabled()"
+	gofmt            = "gofmt"
+	packageRoot      = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	googleAPIPackage = "google.golang.org/api/googleapi"
+	kLogPackage      = "k8s.io/klog/v2"
+	kLogEnabled      = ".Enabled()"
 
 	filterPackage = packageRoot + "/filter"
 	metaPackage   = packageRoot + "/meta"
@@ -105,6 +100,7 @@ package cloud
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
@@ -128,39 +124,7 @@ import (
 		panic(err)
 	}
 
-	var hasComputeGA, hasComputeAlpha, hasComputeBeta bool
-	var hasNetworkServicesGA, hasNetworkServicesBeta bool
-	for _, s := range meta.AllServices {
-		switch {
-		case s.APIGroup == meta.APIGroupCompute && s.Version() == meta.VersionAlpha:
-			hasComputeAlpha = true
-		case s.APIGroup == meta.APIGroupCompute && s.Version() == meta.VersionBeta:
-			hasComputeBeta = true
-		case s.APIGroup == meta.APIGroupCompute && s.Version() == meta.VersionGA:
-			hasComputeGA = true
-		case s.APIGroup == meta.APIGroupNetworkServices && s.Version() == meta.VersionBeta:
-			hasNetworkServicesBeta = true
-		case s.APIGroup == meta.APIGroupNetworkServices && s.Version() == meta.VersionGA:
-			hasNetworkServicesGA = true
-		}
-	}
-
-	if hasComputeAlpha {
-		fmt.Fprintf(wr, "	computealpha \"%s\"\n", alphaComputePackage)
-	}
-	if hasComputeBeta {
-		fmt.Fprintf(wr, "	computebeta \"%s\"\n", betaComputePackage)
-	}
-	if hasComputeGA {
-		fmt.Fprintf(wr, "	computega \"%s\"\n", gaComputePackage)
-	}
-	if hasNetworkServicesBeta {
-		fmt.Fprintf(wr, "	networkservicesbeta \"%s\"\n", betaNetworkServicesPackage)
-	}
-	if hasNetworkServicesGA {
-		fmt.Fprintf(wr, "	networkservicesga \"%s\"\n", gaNetworkServicesPackage)
-	}
-
+	genAPIGroupImports(wr)
 	fmt.Fprintf(wr, ")\n\n")
 
 	const kLogAdapter = `
@@ -180,6 +144,25 @@ func kLogEnabled(level klog.Level) bool {
 	}
 }
 
+// genAPIGroupImports imports the API client packages of the registered API
+// groups that are used by AllServices.
+func genAPIGroupImports(wr io.Writer) {
+	used := map[meta.APIGroup]map[meta.Version]bool{}
+	for _, s := range meta.AllServices {
+		if used[s.APIGroup] == nil {
+			used[s.APIGroup] = map[meta.Version]bool{}
+		}
+		used[s.APIGroup][s.Version()] = true
+	}
+	for _, group := range meta.APIGroups() {
+		for _, v := range meta.AllVersions {
+			if pkg, ok := group.Packages[v]; ok && used[group.Group][v] {
+				fmt.Fprintf(wr, "	%s \"%s\"\n", group.Alias(v), pkg)
+			}
+		}
+	}
+}
+
 // genStubs generates the interface and wrapper stubs.
 func genStubs(wr io.Writer) {
 	const text = `// Cloud is an interface for the GCE compute API.
@@ -221,15 +204,43 @@ func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
 	{{- range .Groups}}
 	mock{{.Service}}Objs := map[meta.Key]*Mock{{.Service}}Obj{}
 	{{- end}}
+	{{- range .Groups}}
+	mock{{.Service}}Lock := &sync.Mutex{}
+	{{- end}}
 
 	mock := &MockGCE{
 	{{- range .All}}
 		{{.MockField}}: New{{.MockWrapType}}(projectRouter, mock{{.Service}}Objs),
 	{{- end}}
 	}
+	// The versions of a service share the Objects, so they must share the
+	// Lock.
+	{{- range .All}}
+	mock.{{.MockField}}.Lock = mock{{.Service}}Lock
+	{{- end}}
 	return mock
 }
 
+// setDefaultLatency sets the latency for the methods of all of the mocks
+// that do not have a latency.
+func (mock *MockGCE) setDefaultLatency(l *MockLatency) {
+	{{- range .All}}
+	if mock.{{.MockField}}.Latencies == nil {
+		mock.{{.MockField}}.Latencies = map[string]*MockLatency{}
+	}
+	mock.{{.MockField}}.Latencies[""] = l
+	{{- end}}
+}
+
+// setOperations sets the MockOperations of the mocks with Insert or Delete.
+func (mock *MockGCE) setOperations(ops *MockOperations) {
+	{{- range .All}}
+	{{- if or .GenerateInsert .GenerateDelete}}
+	mock.{{.MockField}}.Operations = ops
+	{{- end}}
+	{{- end}}
+}
+
 // MockGCE implements Cloud.
 var _ Cloud = (*MockGCE)(nil)
 
@@ -296,6 +307,63 @@ func (m *Mock{{.Service}}Obj) ToGA() *{{.GA.FQObjectType}} {
 }
 {{- end}}
 {{- end}}
+
+// dumpObjects adds the objects in the mocks to s.
+func (mock *MockGCE) dumpObjects(s *MockState) error {
+{{- range .Groups}}
+	{
+		m := mock.{{.ServiceInfo.MockField}}
+		m.Lock.Lock()
+		for k, obj := range m.Objects {
+			if err := s.add("{{.Service}}", k, obj.Obj); err != nil {
+				m.Lock.Unlock()
+				return err
+			}
+		}
+		m.Lock.Unlock()
+	}
+{{- end}}
+	return nil
+}
+
+// loadObject adds the object o to the mocks.
+func (mock *MockGCE) loadObject(o *MockStateObject) error {
+	switch o.Service {
+{{- range .Groups}}
+	case "{{.Service}}":
+		var obj any
+		switch o.Version {
+		{{- if .HasGA}}
+		case meta.VersionGA:
+			obj = &{{.GA.FQObjectType}}{}
+		{{- end}}
+		{{- if .HasAlpha}}
+		case meta.VersionAlpha:
+			obj = &{{.Alpha.FQObjectType}}{}
+		{{- end}}
+		{{- if .HasBeta}}
+		case meta.VersionBeta:
+			obj = &{{.Beta.FQObjectType}}{}
+		{{- end}}
+		default:
+			return fmt.Errorf("{{.Service}}: unsupported version %q", o.Version)
+		}
+		if err := json.Unmarshal(o.Object, obj); err != nil {
+			return fmt.Errorf("{{.Service}}: %w", err)
+		}
+		key, err := o.key()
+		if err != nil {
+			return fmt.Errorf("{{.Service}}: %w", err)
+		}
+		m := mock.{{.ServiceInfo.MockField}}
+		m.Lock.Lock()
+		m.Objects[*key] = &Mock{{.Service}}Obj{obj}
+		m.Lock.Unlock()
+		return nil
+{{- end}}
+	}
+	return fmt.Errorf("unknown service %q", o.Service)
+}
 `
 	data := struct {
 		All    []*meta.ServiceInfo
@@ -342,6 +410,15 @@ type {{.WrapType}} interface {
 {{- if .KeyIsZonal}}
 	List(ctx context.Context, zone string, fl *filter.F, options... Option) ([]*{{.FQObjectType}}, error)
 {{- end -}}
+{{- if .KeyIsGlobal}}
+	ListPages(ctx context.Context, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error
+{{- end -}}
+{{- if .KeyIsRegional}}
+	ListPages(ctx context.Context, region string, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error
+{{- end -}}
+{{- if .KeyIsZonal}}
+	ListPages(ctx context.Context, zone string, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error
+{{- end -}}
 {{- end -}}
 {{- if .GenerateInsert}}
 	Insert(ctx context.Context, key *meta.Key, obj *{{.FQObjectType}}, options... Option) error
@@ -365,6 +442,7 @@ type {{.WrapType}} interface {
 // New{{.MockWrapType}} returns a new mock for {{.Service}}.
 func New{{.MockWrapType}}(pr ProjectRouter, objs map[meta.Key]*Mock{{.Service}}Obj) *{{.MockWrapType}} {
 	mock := &{{.MockWrapType}}{
+		Lock:          &sync.Mutex{},
 		ProjectRouter: pr,
 
 		Objects: objs,
@@ -383,7 +461,10 @@ func New{{.MockWrapType}}(pr ProjectRouter, objs map[meta.Key]*Mock{{.Service}}O
 
 // {{.MockWrapType}} is the mock for {{.Service}}.
 type {{.MockWrapType}} struct {
-	Lock sync.Mutex
+	// Lock protects the state of the mock. NewMockGCE sets the same Lock
+	// for the mocks of all versions of {{.Service}} as they share the
+	// Objects.
+	Lock *sync.Mutex
 
 	ProjectRouter ProjectRouter
 
@@ -411,6 +492,27 @@ type {{.MockWrapType}} struct {
 	ListUsableError *error
 	{{- end}}
 
+	{{- if .GenerateInsert}}
+	// Validator is called with the object for Insert and the methods that
+	// update the object (e.g. Update, Patch). If it returns an error, the
+	// call fails with an HTTP 400 error, like the API does for invalid
+	// objects. See MockValidator.
+	Validator func(obj *{{.FQObjectType}}) error
+	{{- end}}
+
+	// ErrorSequences script the errors returned by the methods, keyed by
+	// the name of the method (e.g. "Get", "SetLabels"). See ErrorSequence.
+	ErrorSequences map[string]*ErrorSequence
+	// Latencies add artificial latency to the methods, keyed by the name of
+	// the method. The entry for "" applies to the methods without an entry.
+	// See MockLatency.
+	Latencies map[string]*MockLatency
+	{{- if or .GenerateInsert .GenerateDelete}}
+	// Operations, if set, makes Insert and Delete wait for a
+	// MockOperation. See MockGCE.AsyncOperations.
+	Operations *MockOperations
+	{{- end}}
+
 	// xxxHook allow you to intercept the standard processing of the mock in
 	// order to add your own logic. Return (true, _, _) to prevent the normal
 	// execution flow of the mock. Return (false, nil, nil) to continue with
@@ -456,6 +558,13 @@ type {{.MockWrapType}} struct {
 {{- if .GenerateGet}}
 // Get returns the object from the mock.
 func (m *{{.MockWrapType}}) Get(ctx context.Context, key *meta.Key, options... Option) (*{{.FQObjectType}}, error) {
+	if err := mockDelay(ctx, m.Latencies, "Get"); err != nil {
+		return nil, err
+	}
+	if err := nextMockError(m.ErrorSequences, "Get"); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Get(%v, ...) = %v (ErrorSequences)", ctx, err)
+		return nil, err
+	}
 	if m.GetHook != nil {
 		if intercept, obj, err := m.GetHook(ctx, key, m, options...);  intercept {
 			klog.V(5).Infof("{{.MockWrapType}}.Get(%v, %s) = %+v, %v", ctx, key, obj ,err)
@@ -501,6 +610,13 @@ func (m *{{.MockWrapType}}) List(ctx context.Context, region string, fl *filter.
 // List all of the objects in the mock in the given zone.
 func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F, options... Option) ([]*{{.FQObjectType}}, error) {
 {{- end}}
+	if err := mockDelay(ctx, m.Latencies, "List"); err != nil {
+		return nil, err
+	}
+	if err := nextMockError(m.ErrorSequences, "List"); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.List(%v, ...) = %v (ErrorSequences)", ctx, err)
+		return nil, err
+	}
 	if m.ListHook != nil {
 		{{if .KeyIsGlobal -}}
 		if intercept, objs, err := m.ListHook(ctx, fl, m, options...);  intercept {
@@ -569,21 +685,61 @@ func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 	{{- end}}
 	return objs, nil
 }
+
+{{if .KeyIsGlobal -}}
+// ListPages calls f with the objects returned by List as a single page.
+func (m *{{.MockWrapType}}) ListPages(ctx context.Context, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error {
+	objs, err := m.List(ctx, fl, options...)
+{{- end -}}
+{{- if .KeyIsRegional -}}
+// ListPages calls f with the objects returned by List as a single page.
+func (m *{{.MockWrapType}}) ListPages(ctx context.Context, region string, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error {
+	objs, err := m.List(ctx, region, fl, options...)
+{{- end -}}
+{{- if .KeyIsZonal -}}
+// ListPages calls f with the objects returned by List as a single page.
+func (m *{{.MockWrapType}}) ListPages(ctx context.Context, zone string, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error {
+	objs, err := m.List(ctx, zone, fl, options...)
+{{- end}}
+	if err != nil {
+		return err
+	}
+	return pagesError(f(objs))
+}
 {{- end}}
 
 {{- if .GenerateInsert}}
 // Insert is a mock for inserting/creating a new object.
 func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQObjectType}}, options... Option) error {
+	if err := mockDelay(ctx, m.Latencies, "Insert"); err != nil {
+		return err
+	}
+	if err := nextMockError(m.ErrorSequences, "Insert"); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, ...) = %v (ErrorSequences)", ctx, err)
+		return err
+	}
+	if m.Validator != nil {
+		if err := mockValidationError(m.Validator(obj)); err != nil {
+			klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v (Validator)", ctx, key, obj, err)
+			return err
+		}
+	}
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(ctx, key, obj, m, options...);  intercept {
 			klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
 			return err
 		}
 	}
+{{- if .HasSelfLink}}
         opts := mergeOptions(options)
+{{- end}}
 	if !key.Valid() {
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
+	if err := mockOperation(ctx, m.Operations, "{{.Service}}", meta.Version{{.VersionTitle}}, "Insert", key); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v (Operations)", ctx, key, obj, err)
+		return err
+	}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
@@ -602,8 +758,10 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 	}
 
 	obj.Name = key.Name
-	projectID := getProjectID(ctx, m.ProjectRouter, opts, "{{.Version}}", "{{.Resource}}")
+{{- if .HasSelfLink}}
+	projectID := getProjectID(ctx, m.ProjectRouter, opts, "{{.Version}}", "{{.Resource}}", key)
 	obj.SelfLink = SelfLinkWithGroup("{{.APIGroup}}", meta.Version{{.VersionTitle}}, projectID, "{{.Resource}}", key)
+{{- end}}
 
 	m.Objects[*key] = &Mock{{.Service}}Obj{obj}
 	klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = nil", ctx, key, obj)
@@ -614,6 +772,13 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 {{- if .GenerateDelete}}
 // Delete is a mock for deleting the object.
 func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key, options... Option) error {
+	if err := mockDelay(ctx, m.Latencies, "Delete"); err != nil {
+		return err
+	}
+	if err := nextMockError(m.ErrorSequences, "Delete"); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, ...) = %v (ErrorSequences)", ctx, err)
+		return err
+	}
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(ctx, key, m, options...);  intercept {
 			klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
@@ -623,6 +788,10 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key, options..
 	if !key.Valid() {
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
+	if err := mockOperation(ctx, m.Operations, "{{.Service}}", meta.Version{{.VersionTitle}}, "Delete", key); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v (Operations)", ctx, key, err)
+		return err
+	}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
@@ -649,6 +818,13 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key, options..
 {{- if .AggregatedList}}
 // AggregatedList is a mock for AggregatedList.
 func (m *{{.MockWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, options... Option) (map[string][]*{{.FQObjectType}}, error) {
+	if err := mockDelay(ctx, m.Latencies, "AggregatedList"); err != nil {
+		return nil, err
+	}
+	if err := nextMockError(m.ErrorSequences, "AggregatedList"); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.AggregatedList(%v, ...) = %v (ErrorSequences)", ctx, err)
+		return nil, err
+	}
 	if m.AggregatedListHook != nil {
 		if intercept, objs, err := m.AggregatedListHook(ctx, fl, m, options...); intercept {
 			klog.V(5).Infof("{{.MockWrapType}}.AggregatedList(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -686,6 +862,13 @@ func (m *{{.MockWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, op
 {{- if .ListUsable}}
 // List all of the objects in the mock.
 func (m *{{.MockWrapType}}) ListUsable(ctx context.Context, fl *filter.F, options... Option) ([]*{{.FQListUsableObjectType}}, error) {
+	if err := mockDelay(ctx, m.Latencies, "ListUsable"); err != nil {
+		return nil, err
+	}
+	if err := nextMockError(m.ErrorSequences, "ListUsable"); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.ListUsable(%v, ...) = %v (ErrorSequences)", ctx, err)
+		return nil, err
+	}
 	if m.ListUsableHook != nil {
 		if intercept, objs, err := m.ListUsableHook(ctx, fl, m, options...);  intercept {
 			klog.V(5).Infof("{{.MockWrapType}}.ListUsable(%v, %v) = [%v items], %v", ctx, fl, len(objs), err)
@@ -731,16 +914,41 @@ func (m *{{.MockWrapType}}) Obj(o *{{.FQObjectType}}) *Mock{{.Service}}Obj {
 // {{.Name}} is a mock for the corresponding method.
 func (m *{{.MockWrapType}}) {{.FcnArgs}} {
 {{- if .IsOperation }}
+	if err := mockDelay(ctx, m.Latencies, "{{.Name}}"); err != nil {
+		return err
+	}
+	if err := nextMockError(m.ErrorSequences, "{{.Name}}"); err != nil {
+		return err
+	}
+{{- if and .UpdatesObject .GenerateInsert}}
+	if m.Validator != nil {
+		if err := mockValidationError(m.Validator(arg0)); err != nil {
+			return err
+		}
+	}
+{{- end}}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, m)
 	}
 	return nil
 {{- else if .IsGet}}
+	if err := mockDelay(ctx, m.Latencies, "{{.Name}}"); err != nil {
+		return nil, err
+	}
+	if err := nextMockError(m.ErrorSequences, "{{.Name}}"); err != nil {
+		return nil, err
+	}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, m)
 	}
 	return nil, fmt.Errorf("{{.MockHookName}} must be set")
 {{- else if .IsPaged}}
+	if err := mockDelay(ctx, m.Latencies, "{{.Name}}"); err != nil {
+		return nil, err
+	}
+	if err := nextMockError(m.ErrorSequences, "{{.Name}}"); err != nil {
+		return nil, err
+	}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, fl, m)
 	}
@@ -764,7 +972,7 @@ func (g *{{.GCPWrapType}}) Get(ctx context.Context, key *meta.Key, options... Op
 		klog.V(2).Infof("{{.GCPWrapType}}.Get(%v, %v): key is invalid (%#v)", ctx, key, key)
 		return nil, fmt.Errorf("invalid GCE key (%#v)", key)
 	}
-	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}")
+	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", key)
 
 	ck:= &CallContextKey{
 		ProjectID: projectID,
@@ -772,15 +980,21 @@ func (g *{{.GCPWrapType}}) Get(ctx context.Context, key *meta.Key, options... Op
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
 
 	klog.V(5).Infof("{{.GCPWrapType}}.Get(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
 		klog.V(4).Infof("{{.GCPWrapType}}.Get(%v, %v): RateLimiter error: %v", ctx, key, err)
 		return nil, err
 	}
 {{- if .IsNetworkServices}}
-    name := fmt.Sprintf("{{.NetworkServicesFmt}}", projectID, key.Name)
+    name := locationResourceName("{{.NetworkServicesFmt}}", projectID, key)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.Get(name)
 {{- else}}
 	{{- if .KeyIsGlobal}}
@@ -794,7 +1008,10 @@ func (g *{{.GCPWrapType}}) Get(ctx context.Context, key *meta.Key, options... Op
 	{{- end}}
 {{- end}}
 	call.Context(ctx)
-	v, err := call.Do()
+	if len(opts.fields) > 0 {
+		call.Fields(opts.fields...)
+	}
+	v, err := callWithRetry(ctx, g.s, ck, call.Do)
 	klog.V(4).Infof("{{.GCPWrapType}}.Get(%v, %v) = %+v, %v", ctx, key, v, err)
 
 	callObserverEnd(ctx, ck, err)
@@ -821,7 +1038,7 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
         opts := mergeOptions(options)
 	klog.V(5).Infof("{{.GCPWrapType}}.List(%v, %v, %v, %v) called", ctx, zone, fl, opts)
 {{- end}}
-        projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}")
+        projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", nil)
 
 	ck:= &CallContextKey{
 		ProjectID: projectID,
@@ -829,9 +1046,15 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
 
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
 		return nil, err
 	}
 
@@ -852,6 +1075,9 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 		call.Filter(fl.String())
 	}
 {{- end}}
+	if len(opts.fields) > 0 {
+		call.Fields(opts.fields...)
+	}
 
 	var all []*{{.FQObjectType}}
 	f := func(l *{{.ObjectListType}}) error {
@@ -859,7 +1085,12 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 		all = append(all, l.{{.ListItemName}}...)
 		return nil
 	}
-	if err := call.Pages(ctx, f); err != nil {
+	pages := func() error {
+		// Start over if the call is retried.
+		all = nil
+		return call.Pages(ctx, f)
+	}
+	if err := g.s.retry(ctx, ck, pages); err != nil {
 		callObserverEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
@@ -882,6 +1113,77 @@ func (g *{{.GCPWrapType}}) List(ctx context.Context, zone string, fl *filter.F,
 
 	return all, nil
 }
+
+// ListPages calls f with each page of {{.Object}} objects as they are
+// received. Return ErrStopPages from f to stop early. ListPages is not
+// retried as pages may already have been processed by f.
+{{- if .KeyIsGlobal}}
+func (g *{{.GCPWrapType}}) ListPages(ctx context.Context, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error {
+        opts := mergeOptions(options)
+	klog.V(5).Infof("{{.GCPWrapType}}.ListPages(%v, %v, %v) called", ctx, fl, opts)
+{{- end -}}
+{{- if .KeyIsRegional}}
+func (g *{{.GCPWrapType}}) ListPages(ctx context.Context, region string, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error {
+        opts := mergeOptions(options)
+	klog.V(5).Infof("{{.GCPWrapType}}.ListPages(%v, %v, %v, %v) called", ctx, region, fl, opts)
+{{- end -}}
+{{- if .KeyIsZonal}}
+func (g *{{.GCPWrapType}}) ListPages(ctx context.Context, zone string, fl *filter.F, f func([]*{{.FQObjectType}}) error, options... Option) error {
+        opts := mergeOptions(options)
+	klog.V(5).Infof("{{.GCPWrapType}}.ListPages(%v, %v, %v, %v) called", ctx, zone, fl, opts)
+{{- end}}
+        projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", nil)
+
+	ck:= &CallContextKey{
+		ProjectID: projectID,
+		Operation: "List",
+		Version: meta.Version("{{.Version}}"),
+		Service: "{{.Service}}",
+	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
+
+	callObserverStart(ctx, ck)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
+		return err
+	}
+
+{{- if .KeyIsGlobal}}
+	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.List(projectID)
+{{- end -}}
+{{- if .KeyIsRegional}}
+	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.List(projectID, region)
+{{- end -}}
+{{- if .KeyIsZonal}}
+	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.List(projectID, zone)
+{{- end}}
+{{- if not .IsNetworkServices }}
+	if fl != filter.None {
+		call.Filter(fl.String())
+	}
+{{- end}}
+	if len(opts.fields) > 0 {
+		call.Fields(opts.fields...)
+	}
+
+	var n int
+	pf := func(l *{{.ObjectListType}}) error {
+		klog.V(5).Infof("{{.GCPWrapType}}.ListPages(%v, ..., %v): page %+v", ctx, fl, l)
+		n += len(l.{{.ListItemName}})
+		return f(l.{{.ListItemName}})
+	}
+	err := pagesError(call.Pages(ctx, pf))
+
+	callObserverEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	klog.V(4).Infof("{{.GCPWrapType}}.ListPages(%v, ..., %v) = [%v items], %v", ctx, fl, n, err)
+	return err
+}
 {{- end}}
 
 {{- if .GenerateInsert}}
@@ -894,7 +1196,7 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
 
-    projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}")
+    projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", key)
 
 	ck:= &CallContextKey{
 		ProjectID: projectID,
@@ -902,6 +1204,11 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
 	{{- if .IsNetworkServices}}
 	klog.V(5).Infof("{{.GCPWrapType}}.Create(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
 	{{- else}}
@@ -909,13 +1216,14 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 	{{- end}}
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
 		klog.V(4).Infof("{{.GCPWrapType}}.Insert(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
 		return err
 	}
 	obj.Name = key.Name
 
 {{- if .IsNetworkServices}}
-	parent := fmt.Sprintf("projects/%s/locations/global", projectID)
+	parent := locationParent(projectID, key)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.Create(parent, obj)
 	{{- if callOperationRequiresID .Object }}
 	  call.{{.Object}}Id(obj.Name)
@@ -933,7 +1241,7 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 {{- end}}
 	call.Context(ctx)
 
-	op, err := call.Do()
+	op, err := callWithRetry(ctx, g.s, ck, call.Do)
 
 	callObserverEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
@@ -943,7 +1251,8 @@ func (g *{{.GCPWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 		return err
 	}
 
-	err = g.s.WaitForCompletion(ctx, op)
+	err = g.s.waitForCompletion(ctx, op, opts)
+	recordSpanError(ctx, err)
 	klog.V(4).Infof("{{.GCPWrapType}}.Insert(%v, %v, %+v) = %+v", ctx, key, obj, err)
 	return err
 }
@@ -959,21 +1268,27 @@ func (g *{{.GCPWrapType}}) Delete(ctx context.Context, key *meta.Key, options...
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
 
-	projectID := getProjectID(ctx, g.s.ProjectRouter, opts,  "{{.Version}}", "{{.Service}}")
+	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", key)
 	ck:= &CallContextKey{
 		ProjectID: projectID,
 		Operation: "Delete",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
 	klog.V(5).Infof("{{.GCPWrapType}}.Delete(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
 		klog.V(4).Infof("{{.GCPWrapType}}.Delete(%v, %v): RateLimiter error: %v", ctx, key, err)
 		return err
 	}
 {{- if .IsNetworkServices}}
-	name := fmt.Sprintf("{{.NetworkServicesFmt}}", projectID, key.Name)
+	name := locationResourceName("{{.NetworkServicesFmt}}", projectID, key)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.Delete(name)
 {{- else}}
 	{{- if .KeyIsGlobal}}
@@ -989,7 +1304,7 @@ func (g *{{.GCPWrapType}}) Delete(ctx context.Context, key *meta.Key, options...
 
 	call.Context(ctx)
 
-	op, err := call.Do()
+	op, err := callWithRetry(ctx, g.s, ck, call.Do)
 
 	callObserverEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
@@ -999,7 +1314,8 @@ func (g *{{.GCPWrapType}}) Delete(ctx context.Context, key *meta.Key, options...
 		return err
 	}
 
-	err = g.s.WaitForCompletion(ctx, op)
+	err = g.s.waitForCompletion(ctx, op, opts)
+	recordSpanError(ctx, err)
 	klog.V(4).Infof("{{.GCPWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
 	return err
 }
@@ -1011,17 +1327,23 @@ func (g *{{.GCPWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, opt
     opts := mergeOptions(options)
 	klog.V(5).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v) called", ctx, fl)
 
-	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}")
+	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", nil)
 	ck:= &CallContextKey{
 		ProjectID: projectID,
 		Operation: "AggregatedList",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
 
 	klog.V(5).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v): projectID = %v, ck = %+v", ctx, fl, projectID, ck)
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
 		klog.V(5).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v): RateLimiter error: %v", ctx, fl, err)
 		return nil, err
 	}
@@ -1031,16 +1353,36 @@ func (g *{{.GCPWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, opt
 	if fl != filter.None {
 		call.Filter(fl.String())
 	}
+	if len(opts.fields) > 0 {
+		call.Fields(opts.fields...)
+	}
+
+	if opts.partialSuccess {
+		call.ReturnPartialSuccess(true)
+	}
 
 	all := map[string][]*{{.FQObjectType}}{}
+	var scopeErrs scopeErrors
 	f := func(l *{{.ObjectAggregatedListType}}) error {
 		for k, v := range l.Items {
 			klog.V(5).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v): page[%v]%+v", ctx, fl, k, v)
 			all[k] = append(all[k], v.{{.AggregatedListField}}...)
+			if v.Warning != nil && v.Warning.Code == scopeWarningUnreachable {
+				scopeErrs.add(k, v.Warning.Code, v.Warning.Message)
+			}
+		}
+		for _, u := range l.Unreachables {
+			scopeErrs.add(u, scopeWarningUnreachable, "")
 		}
 		return nil
 	}
-	if err := call.Pages(ctx, f); err != nil {
+	pages := func() error {
+		// Start over if the call is retried.
+		clear(all)
+		scopeErrs = scopeErrors{}
+		return call.Pages(ctx, f)
+	}
+	if err := g.s.retry(ctx, ck, pages); err != nil {
 		callObserverEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
@@ -1059,6 +1401,12 @@ func (g *{{.GCPWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, opt
 		}
 		klog.V(5).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v) = %v, %v", ctx, fl, asStr, nil)
 	}
+	if opts.partialSuccess {
+		if err := scopeErrs.err(); err != nil {
+			klog.V(4).Infof("{{.GCPWrapType}}.AggregatedList(%v, %v): %v", ctx, fl, err)
+			return all, err
+		}
+	}
 	return all, nil
 }
 {{- end}}
@@ -1068,15 +1416,21 @@ func (g *{{.GCPWrapType}}) AggregatedList(ctx context.Context, fl *filter.F, opt
 func (g *{{.GCPWrapType}}) ListUsable(ctx context.Context, fl *filter.F, options... Option) ([]*{{.FQListUsableObjectType}}, error) {
     opts := mergeOptions(options)
 	klog.V(5).Infof("{{.GCPWrapType}}.ListUsable(%v, %v, %v) called", ctx, fl, opts)
-	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}")
+	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", nil)
 	ck:= &CallContextKey{
 		ProjectID: projectID,
 		Operation: "ListUsable",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
         callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
 		return nil, err
 	}
 
@@ -1085,13 +1439,21 @@ func (g *{{.GCPWrapType}}) ListUsable(ctx context.Context, fl *filter.F, options
 	if fl != filter.None {
 		call.Filter(fl.String())
 	}
+	if len(opts.fields) > 0 {
+		call.Fields(opts.fields...)
+	}
 	var all []*{{.FQListUsableObjectType}}
 	f := func(l *{{.ObjectListUsableType}}) error {
 		klog.V(5).Infof("{{.GCPWrapType}}.ListUsable(%v, ..., %v): page %+v", ctx, fl, l)
 		all = append(all, l.Items...)
 		return nil
 	}
-	if err := call.Pages(ctx, f); err != nil {
+	pages := func() error {
+		// Start over if the call is retried.
+		all = nil
+		return call.Pages(ctx, f)
+	}
+	if err := g.s.retry(ctx, ck, pages); err != nil {
 		callObserverEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
@@ -1132,16 +1494,22 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 		return nil, fmt.Errorf("invalid GCE key (%+v)", key)
 {{- end}}
 	}
-	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}")
+	projectID := getProjectID(ctx, g.s.ProjectRouter, opts, "{{.Version}}", "{{.Service}}", key)
 	ck:= &CallContextKey{
 		ProjectID: projectID,
 		Operation: "{{.Name}}",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
+	ctx, cancel := g.s.callContext(ctx, ck, opts)
+	defer cancel()
+	ctx, span := g.s.startCallSpan(ctx, ck)
+	defer span.End()
+	ctx = g.s.startCall(ctx, ck)
 	klog.V(5).Infof("{{.GCPWrapType}}.{{.Name}}(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		recordSpanError(ctx, err)
 		klog.V(4).Infof("{{.GCPWrapType}}.{{.Name}}(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
 	{{- if .IsOperation}}
 		return err
@@ -1151,7 +1519,7 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 	}
 
 {{- if .IsNetworkServices}}
-    name := fmt.Sprintf("{{.NetworkServicesFmt}}", projectID, key.Name)
+    name := locationResourceName("{{.NetworkServicesFmt}}", projectID, key)
 	call := g.s.{{.GroupVersionTitle}}.{{.Service}}.{{.Name}}(name {{.CallArgs}})
 {{- else}}
 	{{- if .KeyIsGlobal}}
@@ -1166,7 +1534,7 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 {{- end}}
 {{- if .IsOperation}}
 	call.Context(ctx)
-	op, err := call.Do()
+	op, err := callWithRetry(ctx, g.s, ck, call.Do)
 
 	if err != nil {
 		callObserverEnd(ctx, ck, err)
@@ -1176,7 +1544,7 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 		return err
 	}
 
-	err = g.s.WaitForCompletion(ctx, op)
+	err = g.s.waitForCompletion(ctx, op, opts)
         callObserverEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck) // XXX
 
@@ -1184,7 +1552,7 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 	return err
 {{- else if .IsGet}}
 	call.Context(ctx)
-	v, err := call.Do()
+	v, err := callWithRetry(ctx, g.s, ck, call.Do)
 
         callObserverEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
@@ -1198,7 +1566,12 @@ func (g *{{.GCPWrapType}}) {{.FcnArgs}} {
 		all = append(all, l.Items...)
 		return nil
 	}
-	if err := call.Pages(ctx, f); err != nil {
+	pages := func() error {
+		// Start over if the call is retried.
+		all = nil
+		return call.Pages(ctx, f)
+	}
+	if err := g.s.retry(ctx, ck, pages); err != nil {
 		callObserverEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
@@ -1293,29 +1666,28 @@ import (
 	"reflect"
 	"testing"
 
-	computealpha "{{.AlphaComputePackage}}"
-	computebeta "{{.BetaComputePackage}}"
-	computega "{{.GaComputePackage}}"
+`
+	tmpl := template.Must(template.New("header").Parse(text))
+	values := map[string]string{
+		"Year": fmt.Sprintf("%v", time.Now().Year()),
+	}
+	if err := tmpl.Execute(wr, values); err != nil {
+		panic(err)
+	}
 
-	networkservicesga "{{.GaNetworkservicesPackage}}"
-	networkservicesbeta "{{.BetaNetworkservicesPackage}}"
+	genAPIGroupImports(wr)
 
+	const footer = `
 	"{{.FilterPackage}}"
 	"{{.MetaPackage}}"
 )
 
 const location = "location"
 `
-	tmpl := template.Must(template.New("header").Parse(text))
-	values := map[string]string{
-		"Year":                       fmt.Sprintf("%v", time.Now().Year()),
-		"FilterPackage":              filterPackage,
-		"MetaPackage":                metaPackage,
-		"AlphaComputePackage":        alphaComputePackage,
-		"BetaComputePackage":         betaComputePackage,
-		"GaComputePackage":           gaComputePackage,
-		"BetaNetworkservicesPackage": betaNetworkServicesPackage,
-		"GaNetworkservicesPackage":   gaNetworkServicesPackage,
+	tmpl = template.Must(template.New("footer").Parse(footer))
+	values = map[string]string{
+		"FilterPackage": filterPackage,
+		"MetaPackage":   metaPackage,
 	}
 	if err := tmpl.Execute(wr, values); err != nil {
 		panic(err)
@@ -27,7 +27,8 @@ import (
 	computealpha "google.golang.org/api/compute/v0.alpha"
 	computebeta "google.golang.org/api/compute/v0.beta"
 	computega "google.golang.org/api/compute/v1"
-
+	networksecurityga "google.golang.org/api/networksecurity/v1"
+	networksecuritybeta "google.golang.org/api/networksecurity/v1beta1"
 	networkservicesga "google.golang.org/api/networkservices/v1"
 	networkservicesbeta "google.golang.org/api/networkservices/v1beta1"
 
@@ -172,6 +173,105 @@ func TestAddressesGroup(t *testing.T) {
 	}
 }
 
+func TestAuthorizationPoliciesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaAuthorizationPolicies().Get(ctx, key); err == nil {
+		t.Errorf("BetaAuthorizationPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.AuthorizationPolicies().Get(ctx, key); err == nil {
+		t.Errorf("AuthorizationPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networksecuritybeta.AuthorizationPolicy{}
+		if err := mock.BetaAuthorizationPolicies().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaAuthorizationPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networksecurityga.AuthorizationPolicy{}
+		if err := mock.AuthorizationPolicies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("AuthorizationPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaAuthorizationPolicies().Get(ctx, key); err != nil {
+		t.Errorf("BetaAuthorizationPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.AuthorizationPolicies().Get(ctx, key); err != nil {
+		t.Errorf("AuthorizationPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaAuthorizationPolicies.Objects[*keyBeta] = mock.MockBetaAuthorizationPolicies.Obj(&networksecuritybeta.AuthorizationPolicy{Name: keyBeta.Name})
+	mock.MockAuthorizationPolicies.Objects[*keyGA] = mock.MockAuthorizationPolicies.Obj(&networksecurityga.AuthorizationPolicy{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaAuthorizationPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaAuthorizationPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaAuthorizationPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.AuthorizationPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("AuthorizationPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AuthorizationPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaAuthorizationPolicies().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaAuthorizationPolicies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.AuthorizationPolicies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("AuthorizationPolicies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaAuthorizationPolicies().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaAuthorizationPolicies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.AuthorizationPolicies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("AuthorizationPolicies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestBackendServicesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -307,6 +407,105 @@ func TestBackendServicesGroup(t *testing.T) {
 	}
 }
 
+func TestClientTlsPoliciesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaClientTlsPolicies().Get(ctx, key); err == nil {
+		t.Errorf("BetaClientTlsPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.ClientTlsPolicies().Get(ctx, key); err == nil {
+		t.Errorf("ClientTlsPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networksecuritybeta.ClientTlsPolicy{}
+		if err := mock.BetaClientTlsPolicies().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaClientTlsPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networksecurityga.ClientTlsPolicy{}
+		if err := mock.ClientTlsPolicies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("ClientTlsPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaClientTlsPolicies().Get(ctx, key); err != nil {
+		t.Errorf("BetaClientTlsPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.ClientTlsPolicies().Get(ctx, key); err != nil {
+		t.Errorf("ClientTlsPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaClientTlsPolicies.Objects[*keyBeta] = mock.MockBetaClientTlsPolicies.Obj(&networksecuritybeta.ClientTlsPolicy{Name: keyBeta.Name})
+	mock.MockClientTlsPolicies.Objects[*keyGA] = mock.MockClientTlsPolicies.Obj(&networksecurityga.ClientTlsPolicy{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaClientTlsPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaClientTlsPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaClientTlsPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.ClientTlsPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("ClientTlsPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ClientTlsPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaClientTlsPolicies().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaClientTlsPolicies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.ClientTlsPolicies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("ClientTlsPolicies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaClientTlsPolicies().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaClientTlsPolicies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.ClientTlsPolicies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("ClientTlsPolicies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestDisksGroup(t *testing.T) {
 	t.Parallel()
 
@@ -640,6 +839,105 @@ func TestForwardingRulesGroup(t *testing.T) {
 	}
 }
 
+func TestGatewaysGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaGateways().Get(ctx, key); err == nil {
+		t.Errorf("BetaGateways().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.Gateways().Get(ctx, key); err == nil {
+		t.Errorf("Gateways().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networkservicesbeta.Gateway{}
+		if err := mock.BetaGateways().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaGateways().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networkservicesga.Gateway{}
+		if err := mock.Gateways().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("Gateways().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaGateways().Get(ctx, key); err != nil {
+		t.Errorf("BetaGateways().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.Gateways().Get(ctx, key); err != nil {
+		t.Errorf("Gateways().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaGateways.Objects[*keyBeta] = mock.MockBetaGateways.Obj(&networkservicesbeta.Gateway{Name: keyBeta.Name})
+	mock.MockGateways.Objects[*keyGA] = mock.MockGateways.Obj(&networkservicesga.Gateway{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaGateways().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaGateways().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaGateways().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.Gateways().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("Gateways().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Gateways().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaGateways().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaGateways().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.Gateways().Delete(ctx, keyGA); err != nil {
+		t.Errorf("Gateways().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaGateways().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaGateways().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.Gateways().Delete(ctx, keyGA); err == nil {
+		t.Errorf("Gateways().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestGlobalAddressesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -1045,6 +1343,153 @@ func TestGlobalNetworkEndpointGroupsGroup(t *testing.T) {
 	}
 }
 
+func TestGlobalOperationsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.GlobalOperations().Get(ctx, key); err == nil {
+		t.Errorf("GlobalOperations().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+
+	// Get across versions.
+
+	// List.
+	mock.MockGlobalOperations.Objects[*keyGA] = mock.MockGlobalOperations.Obj(&computega.Operation{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-ga": true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.GlobalOperations().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("GlobalOperations().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GlobalOperations().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+
+	// Delete not found.
+}
+
+func TestGrpcRoutesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaGrpcRoutes().Get(ctx, key); err == nil {
+		t.Errorf("BetaGrpcRoutes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.GrpcRoutes().Get(ctx, key); err == nil {
+		t.Errorf("GrpcRoutes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networkservicesbeta.GrpcRoute{}
+		if err := mock.BetaGrpcRoutes().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaGrpcRoutes().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networkservicesga.GrpcRoute{}
+		if err := mock.GrpcRoutes().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("GrpcRoutes().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaGrpcRoutes().Get(ctx, key); err != nil {
+		t.Errorf("BetaGrpcRoutes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.GrpcRoutes().Get(ctx, key); err != nil {
+		t.Errorf("GrpcRoutes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaGrpcRoutes.Objects[*keyBeta] = mock.MockBetaGrpcRoutes.Obj(&networkservicesbeta.GrpcRoute{Name: keyBeta.Name})
+	mock.MockGrpcRoutes.Objects[*keyGA] = mock.MockGrpcRoutes.Obj(&networkservicesga.GrpcRoute{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaGrpcRoutes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaGrpcRoutes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaGrpcRoutes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.GrpcRoutes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("GrpcRoutes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GrpcRoutes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaGrpcRoutes().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaGrpcRoutes().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.GrpcRoutes().Delete(ctx, keyGA); err != nil {
+		t.Errorf("GrpcRoutes().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaGrpcRoutes().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaGrpcRoutes().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.GrpcRoutes().Delete(ctx, keyGA); err == nil {
+		t.Errorf("GrpcRoutes().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestHealthChecksGroup(t *testing.T) {
 	t.Parallel()
 
@@ -1194,52 +1639,151 @@ func TestHttpHealthChecksGroup(t *testing.T) {
 	_, _, _ = ctx, mock, key
 
 	// Get not found.
-	if _, err := mock.HttpHealthChecks().Get(ctx, key); err == nil {
-		t.Errorf("HttpHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	if _, err := mock.HttpHealthChecks().Get(ctx, key); err == nil {
+		t.Errorf("HttpHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &computega.HttpHealthCheck{}
+		if err := mock.HttpHealthChecks().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("HttpHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.HttpHealthChecks().Get(ctx, key); err != nil {
+		t.Errorf("HttpHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockHttpHealthChecks.Objects[*keyGA] = mock.MockHttpHealthChecks.Obj(&computega.HttpHealthCheck{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-ga": true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.HttpHealthChecks().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("HttpHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("HttpHealthChecks().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.HttpHealthChecks().Delete(ctx, keyGA); err != nil {
+		t.Errorf("HttpHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.HttpHealthChecks().Delete(ctx, keyGA); err == nil {
+		t.Errorf("HttpHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
+func TestHttpRoutesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaHttpRoutes().Get(ctx, key); err == nil {
+		t.Errorf("BetaHttpRoutes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.HttpRoutes().Get(ctx, key); err == nil {
+		t.Errorf("HttpRoutes().Get(%v, %v) = _, nil; want error", ctx, key)
 	}
 
 	// Insert.
 	{
-		obj := &computega.HttpHealthCheck{}
-		if err := mock.HttpHealthChecks().Insert(ctx, keyGA, obj); err != nil {
-			t.Errorf("HttpHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		obj := &networkservicesbeta.HttpRoute{}
+		if err := mock.BetaHttpRoutes().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaHttpRoutes().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networkservicesga.HttpRoute{}
+		if err := mock.HttpRoutes().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("HttpRoutes().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
 		}
 	}
 
 	// Get across versions.
-	if obj, err := mock.HttpHealthChecks().Get(ctx, key); err != nil {
-		t.Errorf("HttpHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	if obj, err := mock.BetaHttpRoutes().Get(ctx, key); err != nil {
+		t.Errorf("BetaHttpRoutes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.HttpRoutes().Get(ctx, key); err != nil {
+		t.Errorf("HttpRoutes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
 	}
 
 	// List.
-	mock.MockHttpHealthChecks.Objects[*keyGA] = mock.MockHttpHealthChecks.Obj(&computega.HttpHealthCheck{Name: keyGA.Name})
+	mock.MockBetaHttpRoutes.Objects[*keyBeta] = mock.MockBetaHttpRoutes.Obj(&networkservicesbeta.HttpRoute{Name: keyBeta.Name})
+	mock.MockHttpRoutes.Objects[*keyGA] = mock.MockHttpRoutes.Obj(&networkservicesga.HttpRoute{Name: keyGA.Name})
 	want := map[string]bool{
-		"key-ga": true,
+		"key-beta": true,
+		"key-ga":   true,
 	}
 	_ = want // ignore unused variables.
 	{
-		objs, err := mock.HttpHealthChecks().List(ctx, filter.None)
+		objs, err := mock.BetaHttpRoutes().List(ctx, filter.None)
 		if err != nil {
-			t.Errorf("HttpHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+			t.Errorf("BetaHttpRoutes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
 		} else {
 			got := map[string]bool{}
 			for _, obj := range objs {
 				got[obj.Name] = true
 			}
 			if !reflect.DeepEqual(got, want) {
-				t.Errorf("HttpHealthChecks().List(); got %+v, want %+v", got, want)
+				t.Errorf("BetaHttpRoutes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.HttpRoutes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("HttpRoutes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("HttpRoutes().List(); got %+v, want %+v", got, want)
 			}
 		}
 	}
 
 	// Delete across versions.
-	if err := mock.HttpHealthChecks().Delete(ctx, keyGA); err != nil {
-		t.Errorf("HttpHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	if err := mock.BetaHttpRoutes().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaHttpRoutes().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.HttpRoutes().Delete(ctx, keyGA); err != nil {
+		t.Errorf("HttpRoutes().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
 	}
 
 	// Delete not found.
-	if err := mock.HttpHealthChecks().Delete(ctx, keyGA); err == nil {
-		t.Errorf("HttpHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	if err := mock.BetaHttpRoutes().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaHttpRoutes().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.HttpRoutes().Delete(ctx, keyGA); err == nil {
+		t.Errorf("HttpRoutes().Delete(%v, %v) = nil; want error", ctx, keyGA)
 	}
 }
 
@@ -2759,6 +3303,54 @@ func TestRegionNetworkFirewallPoliciesGroup(t *testing.T) {
 	}
 }
 
+func TestRegionOperationsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyGA := meta.RegionalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.RegionOperations().Get(ctx, key); err == nil {
+		t.Errorf("RegionOperations().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+
+	// Get across versions.
+
+	// List.
+	mock.MockRegionOperations.Objects[*keyGA] = mock.MockRegionOperations.Obj(&computega.Operation{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-ga": true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.RegionOperations().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("RegionOperations().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("RegionOperations().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+
+	// Delete not found.
+}
+
 func TestRegionSslCertificatesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -3657,6 +4249,105 @@ func TestSecurityPoliciesGroup(t *testing.T) {
 	}
 }
 
+func TestServerTlsPoliciesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaServerTlsPolicies().Get(ctx, key); err == nil {
+		t.Errorf("BetaServerTlsPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.ServerTlsPolicies().Get(ctx, key); err == nil {
+		t.Errorf("ServerTlsPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networksecuritybeta.ServerTlsPolicy{}
+		if err := mock.BetaServerTlsPolicies().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaServerTlsPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networksecurityga.ServerTlsPolicy{}
+		if err := mock.ServerTlsPolicies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("ServerTlsPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaServerTlsPolicies().Get(ctx, key); err != nil {
+		t.Errorf("BetaServerTlsPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.ServerTlsPolicies().Get(ctx, key); err != nil {
+		t.Errorf("ServerTlsPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaServerTlsPolicies.Objects[*keyBeta] = mock.MockBetaServerTlsPolicies.Obj(&networksecuritybeta.ServerTlsPolicy{Name: keyBeta.Name})
+	mock.MockServerTlsPolicies.Objects[*keyGA] = mock.MockServerTlsPolicies.Obj(&networksecurityga.ServerTlsPolicy{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaServerTlsPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaServerTlsPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaServerTlsPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.ServerTlsPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("ServerTlsPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ServerTlsPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaServerTlsPolicies().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaServerTlsPolicies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.ServerTlsPolicies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("ServerTlsPolicies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaServerTlsPolicies().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaServerTlsPolicies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.ServerTlsPolicies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("ServerTlsPolicies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestServiceAttachmentsGroup(t *testing.T) {
 	t.Parallel()
 
@@ -4678,6 +5369,105 @@ func TestTcpRoutesGroup(t *testing.T) {
 	}
 }
 
+func TestTlsRoutesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.BetaTlsRoutes().Get(ctx, key); err == nil {
+		t.Errorf("BetaTlsRoutes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.TlsRoutes().Get(ctx, key); err == nil {
+		t.Errorf("TlsRoutes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &networkservicesbeta.TlsRoute{}
+		if err := mock.BetaTlsRoutes().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaTlsRoutes().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &networkservicesga.TlsRoute{}
+		if err := mock.TlsRoutes().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("TlsRoutes().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.BetaTlsRoutes().Get(ctx, key); err != nil {
+		t.Errorf("BetaTlsRoutes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.TlsRoutes().Get(ctx, key); err != nil {
+		t.Errorf("TlsRoutes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockBetaTlsRoutes.Objects[*keyBeta] = mock.MockBetaTlsRoutes.Obj(&networkservicesbeta.TlsRoute{Name: keyBeta.Name})
+	mock.MockTlsRoutes.Objects[*keyGA] = mock.MockTlsRoutes.Obj(&networkservicesga.TlsRoute{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-beta": true,
+		"key-ga":   true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.BetaTlsRoutes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaTlsRoutes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaTlsRoutes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.TlsRoutes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("TlsRoutes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("TlsRoutes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.BetaTlsRoutes().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaTlsRoutes().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.TlsRoutes().Delete(ctx, keyGA); err != nil {
+		t.Errorf("TlsRoutes().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.BetaTlsRoutes().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaTlsRoutes().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.TlsRoutes().Delete(ctx, keyGA); err == nil {
+		t.Errorf("TlsRoutes().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestUrlMapsGroup(t *testing.T) {
 	t.Parallel()
 
@@ -4813,6 +5603,54 @@ func TestUrlMapsGroup(t *testing.T) {
 	}
 }
 
+func TestZoneOperationsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyGA := meta.ZonalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.ZoneOperations().Get(ctx, key); err == nil {
+		t.Errorf("ZoneOperations().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+
+	// Get across versions.
+
+	// List.
+	mock.MockZoneOperations.Objects[*keyGA] = mock.MockZoneOperations.Obj(&computega.Operation{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-ga": true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.ZoneOperations().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("ZoneOperations().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ZoneOperations().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+
+	// Delete not found.
+}
+
 func TestZonesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -4866,15 +5704,21 @@ func TestResourceIDConversion(t *testing.T) {
 
 	for _, id := range []*ResourceID{
 		NewAddressesResourceID("some-project", "us-central1", "my-addresses-resource"),
+		NewAuthorizationPoliciesResourceID("some-project", "my-authorizationPolicies-resource"),
 		NewBackendServicesResourceID("some-project", "my-backendServices-resource"),
+		NewClientTlsPoliciesResourceID("some-project", "my-clientTlsPolicies-resource"),
 		NewDisksResourceID("some-project", "us-east1-b", "my-disks-resource"),
 		NewFirewallsResourceID("some-project", "my-firewalls-resource"),
 		NewForwardingRulesResourceID("some-project", "us-central1", "my-forwardingRules-resource"),
+		NewGatewaysResourceID("some-project", "my-gateways-resource"),
 		NewGlobalAddressesResourceID("some-project", "my-addresses-resource"),
 		NewGlobalForwardingRulesResourceID("some-project", "my-forwardingRules-resource"),
 		NewGlobalNetworkEndpointGroupsResourceID("some-project", "my-networkEndpointGroups-resource"),
+		NewGlobalOperationsResourceID("some-project", "my-operations-resource"),
+		NewGrpcRoutesResourceID("some-project", "my-grpcRoutes-resource"),
 		NewHealthChecksResourceID("some-project", "my-healthChecks-resource"),
 		NewHttpHealthChecksResourceID("some-project", "my-httpHealthChecks-resource"),
+		NewHttpRoutesResourceID("some-project", "my-httpRoutes-resource"),
 		NewHttpsHealthChecksResourceID("some-project", "my-httpsHealthChecks-resource"),
 		NewImagesResourceID("some-project", "my-Images-resource"),
 		NewInstanceGroupManagersResourceID("some-project", "us-east1-b", "my-instanceGroupManagers-resource"),
@@ -4891,6 +5735,7 @@ func TestResourceIDConversion(t *testing.T) {
 		NewRegionHealthChecksResourceID("some-project", "us-central1", "my-healthChecks-resource"),
 		NewRegionNetworkEndpointGroupsResourceID("some-project", "us-central1", "my-networkEndpointGroups-resource"),
 		NewRegionNetworkFirewallPoliciesResourceID("some-project", "us-central1", "my-regionNetworkFirewallPolicies-resource"),
+		NewRegionOperationsResourceID("some-project", "us-central1", "my-operations-resource"),
 		NewRegionSslCertificatesResourceID("some-project", "us-central1", "my-sslCertificates-resource"),
 		NewRegionSslPoliciesResourceID("some-project", "us-central1", "my-sslPolicies-resource"),
 		NewRegionTargetHttpProxiesResourceID("some-project", "us-central1", "my-targetHttpProxies-resource"),
@@ -4900,6 +5745,7 @@ func TestResourceIDConversion(t *testing.T) {
 		NewRoutersResourceID("some-project", "us-central1", "my-routers-resource"),
 		NewRoutesResourceID("some-project", "my-routes-resource"),
 		NewSecurityPoliciesResourceID("some-project", "my-securityPolicies-resource"),
+		NewServerTlsPoliciesResourceID("some-project", "my-serverTlsPolicies-resource"),
 		NewServiceAttachmentsResourceID("some-project", "us-central1", "my-serviceAttachments-resource"),
 		NewSslCertificatesResourceID("some-project", "my-sslCertificates-resource"),
 		NewSslPoliciesResourceID("some-project", "my-sslPolicies-resource"),
@@ -4909,7 +5755,9 @@ func TestResourceIDConversion(t *testing.T) {
 		NewTargetPoolsResourceID("some-project", "us-central1", "my-targetPools-resource"),
 		NewTargetTcpProxiesResourceID("some-project", "my-targetTcpProxies-resource"),
 		NewTcpRoutesResourceID("some-project", "my-tcpRoutes-resource"),
+		NewTlsRoutesResourceID("some-project", "my-tlsRoutes-resource"),
 		NewUrlMapsResourceID("some-project", "my-urlMaps-resource"),
+		NewZoneOperationsResourceID("some-project", "us-east1-b", "my-operations-resource"),
 		NewZonesResourceID("some-project", "my-zones-resource"),
 	} {
 		t.Run(id.Resource, func(t *testing.T) {
@@ -0,0 +1,219 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Interceptor is called before and after each HTTP request to the API. Set
+// Service.Interceptor to e.g. audit log the calls made by the generated code.
+//
+// A single call can make several requests: one per attempt if the call is
+// retried (see RetryPolicy), one per page for List calls and the polling of
+// long running operations.
+//
+// The summaries passed to the Interceptor do not contain credentials (see
+// RequestSummary and ResponseSummary). Implementations must be safe for
+// concurrent use.
+type Interceptor interface {
+	// BeforeRequest is called before req is sent. key identifies the call
+	// making the request; it is nil if the request was not made by one of
+	// the generated methods.
+	BeforeRequest(ctx context.Context, key *CallContextKey, req *RequestSummary)
+	// AfterResponse is called when the response for req has been received
+	// or the request failed with err. resp is nil if there was no response.
+	AfterResponse(ctx context.Context, key *CallContextKey, req *RequestSummary, resp *ResponseSummary, err error)
+}
+
+// RequestSummary is a sanitized summary of an HTTP request to the API.
+type RequestSummary struct {
+	Method string
+	// URL of the request with the API key and access token query parameters
+	// and the user info removed.
+	URL string
+	// Header of the request without the Authorization, Cookie and API key
+	// headers.
+	Header        http.Header
+	ContentLength int64
+}
+
+// ResponseSummary is a sanitized summary of an HTTP response from the API.
+type ResponseSummary struct {
+	StatusCode int
+	// Header of the response without the Set-Cookie headers.
+	Header        http.Header
+	ContentLength int64
+	// Latency of the request, until the response headers were received.
+	Latency time.Duration
+}
+
+// sensitiveHeaders are removed from the summaries.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Goog-Api-Key",
+}
+
+// sensitiveParams are query parameters removed from the summaries.
+var sensitiveParams = []string{
+	"key",
+	"access_token",
+}
+
+func sanitizeHeader(h http.Header) http.Header {
+	ret := h.Clone()
+	for _, k := range sensitiveHeaders {
+		ret.Del(k)
+	}
+	return ret
+}
+
+func sanitizeURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	ret := *u
+	ret.User = nil
+	q := ret.Query()
+	for _, p := range sensitiveParams {
+		q.Del(p)
+	}
+	ret.RawQuery = q.Encode()
+	return ret.String()
+}
+
+var callKeyContextKey = contextKey("call key")
+
+// callKeyFromContext returns the CallContextKey of the call in ctx or nil if
+// there is none.
+func callKeyFromContext(ctx context.Context) *CallContextKey {
+	ck, _ := ctx.Value(callKeyContextKey).(*CallContextKey)
+	return ck
+}
+
+var userProjectContextKey = contextKey("user project")
+
+// userProjectFromContext returns the user project set with the UserProject
+// option of the call in ctx or "" if there is none.
+func userProjectFromContext(ctx context.Context) string {
+	p, _ := ctx.Value(userProjectContextKey).(string)
+	return p
+}
+
+// interceptTransport serves the request from the ReadCache, routes the
+// request to its regional endpoint, sets the X-Goog-User-Project header and
+// calls the Interceptor of the Service for each request.
+type interceptTransport struct {
+	s    *Service
+	base http.RoundTripper
+}
+
+func (t *interceptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.s.ReadCache != nil {
+		return t.s.ReadCache.roundTrip(req, t.roundTrip)
+	}
+	return t.roundTrip(req)
+}
+
+func (t *interceptTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.s.RegionalEndpoints != nil {
+		req = t.s.RegionalEndpoints.route(req)
+	}
+	userProject := userProjectFromContext(req.Context())
+	if userProject == "" {
+		userProject = t.s.UserProject
+	}
+	if userProject != "" {
+		// RoundTrippers must not modify the request.
+		req = req.Clone(req.Context())
+		req.Header.Set("X-Goog-User-Project", userProject)
+	}
+	ic := t.s.Interceptor
+	if ic == nil {
+		return base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	key := callKeyFromContext(ctx)
+	rs := &RequestSummary{
+		Method:        req.Method,
+		URL:           sanitizeURL(req.URL),
+		Header:        sanitizeHeader(req.Header),
+		ContentLength: req.ContentLength,
+	}
+	ic.BeforeRequest(ctx, key, rs)
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	var ps *ResponseSummary
+	if resp != nil {
+		ps = &ResponseSummary{
+			StatusCode:    resp.StatusCode,
+			Header:        sanitizeHeader(resp.Header),
+			ContentLength: resp.ContentLength,
+			Latency:       time.Since(start),
+		}
+	}
+	ic.AfterResponse(ctx, key, rs, ps, err)
+
+	return resp, err
+}
+
+// interceptClient returns a copy of client that calls the Interceptor of s.
+func interceptClient(s *Service, client *http.Client) *http.Client {
+	if client == nil {
+		return nil
+	}
+	ret := *client
+	ret.Transport = &interceptTransport{s: s, base: client.Transport}
+	return &ret
+}
+
+// LogInterceptor is an Interceptor that logs the requests with klog at
+// the given verbosity.
+type LogInterceptor struct {
+	Verbosity klog.Level
+}
+
+// LogInterceptor implements Interceptor.
+var _ Interceptor = (*LogInterceptor)(nil)
+
+// BeforeRequest implements Interceptor.
+func (l *LogInterceptor) BeforeRequest(ctx context.Context, key *CallContextKey, req *RequestSummary) {
+	klog.V(l.Verbosity).Infof("%+v: %s %s (%d bytes)", key, req.Method, req.URL, req.ContentLength)
+}
+
+// AfterResponse implements Interceptor.
+func (l *LogInterceptor) AfterResponse(ctx context.Context, key *CallContextKey, req *RequestSummary, resp *ResponseSummary, err error) {
+	if resp == nil {
+		klog.V(l.Verbosity).Infof("%+v: %s %s = %v", key, req.Method, req.URL, err)
+		return
+	}
+	klog.V(l.Verbosity).Infof("%+v: %s %s = %d (%v), %v", key, req.Method, req.URL, resp.StatusCode, resp.Latency, err)
+}
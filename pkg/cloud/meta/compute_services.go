@@ -277,9 +277,11 @@ var ComputeServices = []*ServiceInfo{
 		Resource:    "forwardingRules",
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&ga.ForwardingRulesService{}),
+		options:     AggregatedList,
 		additionalMethods: []string{
 			"SetTarget",
 			"SetLabels",
+			"Patch",
 		},
 	},
 	{
@@ -289,9 +291,11 @@ var ComputeServices = []*ServiceInfo{
 		version:     VersionAlpha,
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&alpha.ForwardingRulesService{}),
+		options:     AggregatedList,
 		additionalMethods: []string{
 			"SetTarget",
 			"SetLabels",
+			"Patch",
 		},
 	},
 	{
@@ -301,9 +305,11 @@ var ComputeServices = []*ServiceInfo{
 		version:     VersionBeta,
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&beta.ForwardingRulesService{}),
+		options:     AggregatedList,
 		additionalMethods: []string{
 			"SetTarget",
 			"SetLabels",
+			"Patch",
 		},
 	},
 	{
@@ -316,6 +322,7 @@ var ComputeServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetTarget",
 			"SetLabels",
+			"Patch",
 		},
 	},
 	{
@@ -328,6 +335,7 @@ var ComputeServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetTarget",
 			"SetLabels",
+			"Patch",
 		},
 	},
 	{
@@ -339,6 +347,7 @@ var ComputeServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetTarget",
 			"SetLabels",
+			"Patch",
 		},
 	},
 	{
@@ -349,6 +358,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.HealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -360,6 +370,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&alpha.HealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -371,6 +382,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&beta.HealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -382,6 +394,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&alpha.RegionHealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -393,6 +406,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&beta.RegionHealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -404,6 +418,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.RegionHealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -414,6 +429,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.HttpHealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -424,6 +440,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.HttpsHealthChecksService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -432,6 +449,7 @@ var ComputeServices = []*ServiceInfo{
 		Resource:    "instanceGroups",
 		keyType:     Zonal,
 		serviceType: reflect.TypeOf(&ga.InstanceGroupsService{}),
+		options:     AggregatedList,
 		additionalMethods: []string{
 			"AddInstances",
 			"ListInstances",
@@ -687,6 +705,30 @@ var ComputeServices = []*ServiceInfo{
 			"ListNetworkEndpoints",
 		},
 	},
+	{
+		Object:      "Operation",
+		Service:     "GlobalOperations",
+		Resource:    "operations",
+		keyType:     Global,
+		options:     ReadOnly,
+		serviceType: reflect.TypeOf(&ga.GlobalOperationsService{}),
+	},
+	{
+		Object:      "Operation",
+		Service:     "RegionOperations",
+		Resource:    "operations",
+		keyType:     Regional,
+		options:     ReadOnly,
+		serviceType: reflect.TypeOf(&ga.RegionOperationsService{}),
+	},
+	{
+		Object:      "Operation",
+		Service:     "ZoneOperations",
+		Resource:    "operations",
+		keyType:     Zonal,
+		options:     ReadOnly,
+		serviceType: reflect.TypeOf(&ga.ZoneOperationsService{}),
+	},
 	{
 		Object:   "Project",
 		Service:  "Projects",
@@ -857,6 +899,9 @@ var ComputeServices = []*ServiceInfo{
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.SslPoliciesService{}),
 		options:     NoList, // List() naming convention is different in GCE API for this resource
+		additionalMethods: []string{
+			"Patch",
+		},
 	},
 	{
 		Object:      "SslPolicy",
@@ -865,6 +910,9 @@ var ComputeServices = []*ServiceInfo{
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&ga.RegionSslPoliciesService{}),
 		options:     NoList, // List() naming convention is different in GCE API for this resource
+		additionalMethods: []string{
+			"Patch",
+		},
 	},
 	{
 		Object:      "Subnetwork",
@@ -911,6 +959,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&alpha.TargetHttpProxiesService{}),
 		additionalMethods: []string{
 			"SetUrlMap",
+			"Patch",
 		},
 	},
 	{
@@ -922,6 +971,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&beta.TargetHttpProxiesService{}),
 		additionalMethods: []string{
 			"SetUrlMap",
+			"Patch",
 		},
 	},
 	{
@@ -932,6 +982,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.TargetHttpProxiesService{}),
 		additionalMethods: []string{
 			"SetUrlMap",
+			"Patch",
 		},
 	},
 	{
@@ -978,6 +1029,7 @@ var ComputeServices = []*ServiceInfo{
 			"SetSslCertificates",
 			"SetSslPolicy",
 			"SetUrlMap",
+			"Patch",
 		},
 	},
 	{
@@ -992,6 +1044,7 @@ var ComputeServices = []*ServiceInfo{
 			"SetSslCertificates",
 			"SetSslPolicy",
 			"SetUrlMap",
+			"Patch",
 		},
 	},
 	{
@@ -1006,6 +1059,7 @@ var ComputeServices = []*ServiceInfo{
 			"SetSslCertificates",
 			"SetSslPolicy",
 			"SetUrlMap",
+			"Patch",
 		},
 	},
 	{
@@ -1100,6 +1154,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&alpha.UrlMapsService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -1111,6 +1166,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&beta.UrlMapsService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -1121,6 +1177,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.UrlMapsService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -1132,6 +1189,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&alpha.RegionUrlMapsService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -1143,6 +1201,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&beta.RegionUrlMapsService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -1154,6 +1213,7 @@ var ComputeServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.RegionUrlMapsService{}),
 		additionalMethods: []string{
 			"Update",
+			"Patch",
 		},
 	},
 	{
@@ -22,10 +22,17 @@ import (
 )
 
 // Key for a GCP resource.
+//
+// Fields may be added to Key. Use the constructors (e.g. GlobalKey) or name
+// the fields in composite literals.
 type Key struct {
 	Name   string
 	Zone   string
 	Region string
+	// Parent is the parent of a resource that does not belong to a project,
+	// e.g. "organizations/123" or "folders/456" (see InParent). It is empty
+	// for resources in a project.
+	Parent string
 }
 
 // KeyType is the type of the key.
@@ -43,21 +50,33 @@ const (
 var (
 	// locationRegexp is the format of regions/zone names in GCE.
 	locationRegexp = regexp.MustCompile("^[a-z](?:[-a-z0-9]+)?$")
+	// parentRegexp is the format of the parents of resources that are not
+	// in a project.
+	parentRegexp = regexp.MustCompile("^(organizations|folders)/[0-9]+$")
 )
 
 // ZonalKey returns the key for a zonal resource.
 func ZonalKey(name, zone string) *Key {
-	return &Key{name, zone, ""}
+	return &Key{Name: name, Zone: zone}
 }
 
 // RegionalKey returns the key for a regional resource.
 func RegionalKey(name, region string) *Key {
-	return &Key{name, "", region}
+	return &Key{Name: name, Region: region}
 }
 
 // GlobalKey returns the key for a global resource.
 func GlobalKey(name string) *Key {
-	return &Key{name, "", ""}
+	return &Key{Name: name}
+}
+
+// InParent returns a copy of the key for a resource under parent instead of
+// a project, e.g. GlobalKey("ag").InParent("organizations/123") for
+// "organizations/123/locations/global/addressGroups/ag".
+func (k *Key) InParent(parent string) *Key {
+	ret := *k
+	ret.Parent = parent
+	return &ret
 }
 
 // Type returns the type of the key.
@@ -74,13 +93,17 @@ func (k *Key) Type() KeyType {
 
 // String returns a string representation of the key.
 func (k Key) String() string {
+	var parent string
+	if k.Parent != "" {
+		parent = fmt.Sprintf(", parent: %q", k.Parent)
+	}
 	switch k.Type() {
 	case Zonal:
-		return fmt.Sprintf("Key{%q, zone: %q}", k.Name, k.Zone)
+		return fmt.Sprintf("Key{%q, zone: %q%s}", k.Name, k.Zone, parent)
 	case Regional:
-		return fmt.Sprintf("Key{%q, region: %q}", k.Name, k.Region)
+		return fmt.Sprintf("Key{%q, region: %q%s}", k.Name, k.Region, parent)
 	default:
-		return fmt.Sprintf("Key{%q}", k.Name)
+		return fmt.Sprintf("Key{%q%s}", k.Name, parent)
 	}
 }
 
@@ -89,6 +112,9 @@ func (k *Key) Valid() bool {
 	if k.Zone != "" && k.Region != "" {
 		return false
 	}
+	if k.Parent != "" && !parentRegexp.MatchString(k.Parent) {
+		return false
+	}
 	switch {
 	case k.Region != "":
 		return locationRegexp.Match([]byte(k.Region))
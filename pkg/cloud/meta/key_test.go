@@ -44,6 +44,7 @@ func TestKeyString(t *testing.T) {
 		GlobalKey("abc"),
 		RegionalKey("abc", "us-central1"),
 		ZonalKey("abc", "us-central1-b"),
+		GlobalKey("abc").InParent("organizations/123"),
 	} {
 		if k.String() == "" {
 			t.Errorf(`k.String() = "", want non-empty`)
@@ -66,7 +67,11 @@ func TestKeyValid(t *testing.T) {
 		{ZonalKey("abc", zone), true},
 		{RegionalKey("abc", "/invalid/"), false},
 		{ZonalKey("abc", "/invalid/"), false},
-		{&Key{"abc", zone, region}, false},
+		{&Key{Name: "abc", Zone: zone, Region: region}, false},
+		{GlobalKey("abc").InParent("organizations/123"), true},
+		{RegionalKey("abc", region).InParent("folders/456"), true},
+		{GlobalKey("abc").InParent("projects/p"), false},
+		{GlobalKey("abc").InParent("organizations/abc"), false},
 	} {
 		got := tc.key.Valid()
 		if got != tc.want {
@@ -60,6 +60,9 @@ const (
 
 	// APIGroupNetworkServices is the networkservices API group.
 	APIGroupNetworkServices APIGroup = "networkservices"
+
+	// APIGroupNetworkSecurity is the networksecurity API group.
+	APIGroupNetworkSecurity APIGroup = "networksecurity"
 )
 
 // AllVersions is a list of all versions of the GCP APIs.
@@ -71,6 +71,10 @@ func (a *arg) normalizedPkg() string {
 		return "networkservicesga."
 	case "google.golang.org/api/networkservices/v1beta1":
 		return "networkservicesbeta."
+	case "google.golang.org/api/networksecurity/v1":
+		return "networksecurityga."
+	case "google.golang.org/api/networksecurity/v1beta1":
+		return "networksecuritybeta."
 	default:
 		panic(fmt.Errorf("unhandled package %q", a.pkg))
 	}
@@ -141,7 +145,7 @@ func (m *Method) IsGet() bool {
 // argsSkip is the number of arguments to skip when generating the
 // synthesized method.
 func (m *Method) argsSkip() int {
-	if m.ServiceInfo.APIGroup == APIGroupNetworkServices {
+	if m.ServiceInfo.IsNetworkServices() {
 		return 2
 	}
 	switch m.keyType {
@@ -249,6 +253,17 @@ func (m *Method) init() {
 	}
 }
 
+// UpdatesObject is true if the method is an operation that takes the object
+// as its first argument, e.g. Update(key, obj) or Patch(key, obj).
+func (m *Method) UpdatesObject() bool {
+	fType := m.m.Func.Type()
+	if m.kind != MethodOperation || fType.NumIn() <= m.argsSkip() {
+		return false
+	}
+	t := fType.In(m.argsSkip())
+	return t.Kind() == reflect.Pointer && t.Elem().Name() == m.Object
+}
+
 // Name is the name of the method.
 func (m *Method) Name() string {
 	return m.m.Name
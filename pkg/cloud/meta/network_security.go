@@ -0,0 +1,100 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"reflect"
+
+	ga "google.golang.org/api/networksecurity/v1"
+	beta "google.golang.org/api/networksecurity/v1beta1"
+)
+
+func init() {
+	for _, s := range NetworkSecurity {
+		s.APIGroup = APIGroupNetworkSecurity
+	}
+	AllServices = append(AllServices, NetworkSecurity...)
+}
+
+var NetworkSecurity = []*ServiceInfo{
+	{
+		Object:      "ServerTlsPolicy",
+		Service:     "ServerTlsPolicies",
+		Resource:    "serverTlsPolicies",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsServerTlsPoliciesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "ServerTlsPolicy",
+		Service:     "ServerTlsPolicies",
+		Resource:    "serverTlsPolicies",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsServerTlsPoliciesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "ClientTlsPolicy",
+		Service:     "ClientTlsPolicies",
+		Resource:    "clientTlsPolicies",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsClientTlsPoliciesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "ClientTlsPolicy",
+		Service:     "ClientTlsPolicies",
+		Resource:    "clientTlsPolicies",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsClientTlsPoliciesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "AuthorizationPolicy",
+		Service:     "AuthorizationPolicies",
+		Resource:    "authorizationPolicies",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsAuthorizationPoliciesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "AuthorizationPolicy",
+		Service:     "AuthorizationPolicies",
+		Resource:    "authorizationPolicies",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsAuthorizationPoliciesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+}
@@ -75,4 +75,92 @@ var NetworkServices = []*ServiceInfo{
 			"Patch",
 		},
 	},
+	{
+		Object:      "Gateway",
+		Service:     "Gateways",
+		Resource:    "gateways",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsGatewaysService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "Gateway",
+		Service:     "Gateways",
+		Resource:    "gateways",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsGatewaysService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "HttpRoute",
+		Service:     "HttpRoutes",
+		Resource:    "httpRoutes",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsHttpRoutesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "HttpRoute",
+		Service:     "HttpRoutes",
+		Resource:    "httpRoutes",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsHttpRoutesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "GrpcRoute",
+		Service:     "GrpcRoutes",
+		Resource:    "grpcRoutes",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsGrpcRoutesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "GrpcRoute",
+		Service:     "GrpcRoutes",
+		Resource:    "grpcRoutes",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsGrpcRoutesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "TlsRoute",
+		Service:     "TlsRoutes",
+		Resource:    "tlsRoutes",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.ProjectsLocationsTlsRoutesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "TlsRoute",
+		Service:     "TlsRoutes",
+		Resource:    "tlsRoutes",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.ProjectsLocationsTlsRoutesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
 }
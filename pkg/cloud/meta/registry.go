@@ -0,0 +1,212 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// APIGroupInfo describes an API group to generate code for.
+type APIGroupInfo struct {
+	// Group is the name of the API group, e.g. "networkservices".
+	Group APIGroup
+	// Title is the prefix of the fields of cloud.Service holding the API
+	// clients of the group, e.g. "NetworkServices" for
+	// cloud.Service.NetworkServicesGA. It is empty for compute.
+	Title string
+	// Locations is true if the resources of the group are named
+	// "projects/<project>/locations/<location>/<resource>/<name>", as for
+	// networkservices, instead of using the compute style of URLs.
+	Locations bool
+	// Packages are the import paths of the Go API clients of the group,
+	// by version.
+	Packages map[Version]string
+}
+
+// Alias returns the name the generated code uses to import the package of
+// the API client for version v, e.g. "computega".
+func (g *APIGroupInfo) Alias(v Version) string {
+	return string(g.Group) + string(v)
+}
+
+var apiGroups = map[APIGroup]*APIGroupInfo{
+	APIGroupCompute: {
+		Group: APIGroupCompute,
+		Packages: map[Version]string{
+			VersionGA:    "google.golang.org/api/compute/v1",
+			VersionAlpha: "google.golang.org/api/compute/v0.alpha",
+			VersionBeta:  "google.golang.org/api/compute/v0.beta",
+		},
+	},
+	APIGroupNetworkServices: {
+		Group:     APIGroupNetworkServices,
+		Title:     "NetworkServices",
+		Locations: true,
+		Packages: map[Version]string{
+			VersionGA:   "google.golang.org/api/networkservices/v1",
+			VersionBeta: "google.golang.org/api/networkservices/v1beta1",
+		},
+	},
+	APIGroupNetworkSecurity: {
+		Group:     APIGroupNetworkSecurity,
+		Title:     "NetworkSecurity",
+		Locations: true,
+		Packages: map[Version]string{
+			VersionGA:   "google.golang.org/api/networksecurity/v1",
+			VersionBeta: "google.golang.org/api/networksecurity/v1beta1",
+		},
+	},
+}
+
+// RegisterAPIGroup adds an API group or additional versions of an existing
+// API group. For an existing group, the Packages are added to the ones
+// already registered and the other fields are ignored.
+//
+// The generated code for a new group or version expects a matching field
+// in cloud.Service (see APIGroupInfo.Title), which must be added by hand.
+func RegisterAPIGroup(info *APIGroupInfo) error {
+	if info.Group == "" {
+		return fmt.Errorf("RegisterAPIGroup: empty Group")
+	}
+	for v := range info.Packages {
+		if !isValidVersion(v) {
+			return fmt.Errorf("RegisterAPIGroup(%q): invalid version %q", info.Group, v)
+		}
+	}
+	existing, ok := apiGroups[info.Group]
+	if !ok {
+		ret := *info
+		ret.Packages = map[Version]string{}
+		for v, pkg := range info.Packages {
+			ret.Packages[v] = pkg
+		}
+		apiGroups[info.Group] = &ret
+		return nil
+	}
+	for v, pkg := range info.Packages {
+		if old, ok := existing.Packages[v]; ok && old != pkg {
+			return fmt.Errorf("RegisterAPIGroup(%q): version %q is already registered with package %q", info.Group, v, old)
+		}
+		existing.Packages[v] = pkg
+	}
+	return nil
+}
+
+// LookupAPIGroup returns the registered API group.
+func LookupAPIGroup(group APIGroup) (*APIGroupInfo, bool) {
+	info, ok := apiGroups[group]
+	return info, ok
+}
+
+// APIGroups returns the registered API groups sorted by name.
+func APIGroups() []*APIGroupInfo {
+	var ret []*APIGroupInfo
+	for _, info := range apiGroups {
+		ret = append(ret, info)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Group < ret[j].Group })
+	return ret
+}
+
+func isValidVersion(v Version) bool {
+	for _, valid := range AllVersions {
+		if v == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceSpec describes a service to generate code for with
+// RegisterServices. The fields have the same meaning as the ones of
+// ServiceInfo.
+type ServiceSpec struct {
+	Object   string
+	Service  string
+	Resource string
+	APIGroup APIGroup
+	// Version defaults to VersionGA.
+	Version Version
+	KeyType KeyType
+	// ServiceType is the type of the API client service, e.g.
+	// reflect.TypeOf(&ga.ProjectsLocationsGatewaysService{}).
+	ServiceType reflect.Type
+	// AdditionalMethods to generate code for, e.g. "Patch".
+	AdditionalMethods []string
+	// Options is a combination of NoGet, NoList, ..., ReadOnly.
+	Options int
+	// AggregatedListField overrides the name of the field of the
+	// AggregatedList items (see ServiceInfo.AggregatedListField).
+	AggregatedListField string
+}
+
+// NewServiceInfo returns the ServiceInfo for spec.
+func NewServiceInfo(spec *ServiceSpec) *ServiceInfo {
+	return &ServiceInfo{
+		Object:              spec.Object,
+		Service:             spec.Service,
+		Resource:            spec.Resource,
+		APIGroup:            spec.APIGroup,
+		version:             spec.Version,
+		keyType:             spec.KeyType,
+		serviceType:         spec.ServiceType,
+		additionalMethods:   spec.AdditionalMethods,
+		options:             spec.Options,
+		aggregatedListField: spec.AggregatedListField,
+	}
+}
+
+// RegisterServices adds services to AllServices, so that code is generated
+// for them without changing the lists of this package. This is intended to
+// be called from an init() function, e.g. in a fork adding the services of a
+// new API group:
+//
+//	func init() {
+//		meta.RegisterAPIGroup(&meta.APIGroupInfo{...})
+//		meta.RegisterServices(meta.NewServiceInfo(&meta.ServiceSpec{...}))
+//	}
+//
+// The API group and version of the services must have been registered (see
+// RegisterAPIGroup).
+func RegisterServices(services ...*ServiceInfo) error {
+	existing := map[string]bool{}
+	for _, s := range AllServices {
+		existing[string(s.Version())+"/"+s.Service] = true
+	}
+	for _, s := range services {
+		group, ok := apiGroups[s.APIGroup]
+		if !ok {
+			return fmt.Errorf("RegisterServices(%q): API group %q is not registered", s.Service, s.APIGroup)
+		}
+		if _, ok := group.Packages[s.Version()]; !ok {
+			return fmt.Errorf("RegisterServices(%q): version %q of API group %q is not registered", s.Service, s.Version(), s.APIGroup)
+		}
+		if s.Object == "" || s.Service == "" || s.serviceType == nil {
+			return fmt.Errorf("RegisterServices(%q): Object, Service and ServiceType must be set", s.Service)
+		}
+		k := string(s.Version()) + "/" + s.Service
+		if existing[k] {
+			return fmt.Errorf("RegisterServices(%q): version %q is already registered", s.Service, s.Version())
+		}
+		existing[k] = true
+	}
+	AllServices = append(AllServices, services...)
+	groupAllServices()
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/networkservices/v1"
+)
+
+// saveRegistry saves the global registry and restores it at the end of the
+// test.
+func saveRegistry(t *testing.T) {
+	services := AllServices
+	groups := map[APIGroup]*APIGroupInfo{}
+	for k, v := range apiGroups {
+		g := *v
+		g.Packages = map[Version]string{}
+		for ver, pkg := range v.Packages {
+			g.Packages[ver] = pkg
+		}
+		groups[k] = &g
+	}
+	t.Cleanup(func() {
+		AllServices = services
+		apiGroups = groups
+		groupAllServices()
+	})
+}
+
+func TestRegisterAPIGroup(t *testing.T) {
+	saveRegistry(t)
+
+	if err := RegisterAPIGroup(&APIGroupInfo{
+		Group:     "networksecurity",
+		Title:     "NetworkSecurity",
+		Locations: true,
+		Packages:  map[Version]string{VersionGA: "google.golang.org/api/networksecurity/v1"},
+	}); err != nil {
+		t.Fatalf("RegisterAPIGroup() = %v, want nil", err)
+	}
+	// Add a version to an existing group.
+	if err := RegisterAPIGroup(&APIGroupInfo{
+		Group:    "networksecurity",
+		Packages: map[Version]string{VersionBeta: "google.golang.org/api/networksecurity/v1beta1"},
+	}); err != nil {
+		t.Fatalf("RegisterAPIGroup() = %v, want nil", err)
+	}
+	group, ok := LookupAPIGroup("networksecurity")
+	if !ok {
+		t.Fatalf("LookupAPIGroup() = _, false, want true")
+	}
+	if group.Title != "NetworkSecurity" || len(group.Packages) != 2 {
+		t.Errorf("LookupAPIGroup() = %+v, want Title NetworkSecurity and 2 packages", group)
+	}
+	if got := group.Alias(VersionBeta); got != "networksecuritybeta" {
+		t.Errorf("Alias(beta) = %q, want %q", got, "networksecuritybeta")
+	}
+
+	for _, tc := range []struct {
+		name string
+		info *APIGroupInfo
+	}{
+		{name: "no group", info: &APIGroupInfo{}},
+		{name: "invalid version", info: &APIGroupInfo{Group: "x", Packages: map[Version]string{"v2": "pkg"}}},
+		{name: "conflicting package", info: &APIGroupInfo{Group: APIGroupCompute, Packages: map[Version]string{VersionGA: "other"}}},
+	} {
+		if err := RegisterAPIGroup(tc.info); err == nil {
+			t.Errorf("%s: RegisterAPIGroup() = nil, want error", tc.name)
+		}
+	}
+}
+
+func TestRegisterServices(t *testing.T) {
+	saveRegistry(t)
+
+	spec := &ServiceSpec{
+		Object:      "Gateway",
+		Service:     "TestGateways",
+		Resource:    "gateways",
+		APIGroup:    APIGroupNetworkServices,
+		KeyType:     Global,
+		ServiceType: reflect.TypeOf(&ga.ProjectsLocationsGatewaysService{}),
+	}
+	n := len(AllServices)
+	if err := RegisterServices(NewServiceInfo(spec)); err != nil {
+		t.Fatalf("RegisterServices() = %v, want nil", err)
+	}
+	if len(AllServices) != n+1 {
+		t.Errorf("len(AllServices) = %d, want %d", len(AllServices), n+1)
+	}
+	sg, ok := AllServicesByGroup["TestGateways"]
+	if !ok || !sg.HasGA() {
+		t.Fatalf("AllServicesByGroup[TestGateways] = %+v, want GA service", sg)
+	}
+	if got := sg.GA.GroupVersionTitle(); got != "NetworkServicesGA" {
+		t.Errorf("GroupVersionTitle() = %q, want %q", got, "NetworkServicesGA")
+	}
+	if !sg.GA.IsNetworkServices() {
+		t.Errorf("IsNetworkServices() = false, want true")
+	}
+	var found bool
+	for _, g := range SortedServicesGroups {
+		if g == sg {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("TestGateways not in SortedServicesGroups")
+	}
+
+	for _, tc := range []struct {
+		name string
+		spec ServiceSpec
+	}{
+		{name: "duplicate", spec: *spec},
+		{name: "unknown group", spec: ServiceSpec{Object: "X", Service: "Xs", APIGroup: "unknown", ServiceType: spec.ServiceType}},
+		{name: "unknown version", spec: ServiceSpec{Object: "X", Service: "Xs", APIGroup: APIGroupNetworkServices, Version: VersionAlpha, ServiceType: spec.ServiceType}},
+		{name: "no service type", spec: ServiceSpec{Object: "X", Service: "Xs", APIGroup: APIGroupNetworkServices}},
+	} {
+		if err := RegisterServices(NewServiceInfo(&tc.spec)); err == nil {
+			t.Errorf("%s: RegisterServices() = nil, want error", tc.name)
+		}
+	}
+	if len(AllServices) != n+1 {
+		t.Errorf("len(AllServices) = %d after failed registrations, want %d", len(AllServices), n+1)
+	}
+}
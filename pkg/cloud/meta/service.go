@@ -72,8 +72,8 @@ func (i *ServiceInfo) VersionTitle() string {
 // GroupVersionTitle returns the capitalized golang CamelCase name for the API Group version.
 func (i *ServiceInfo) GroupVersionTitle() string {
 	prefix := ""
-	if i.APIGroup == APIGroupNetworkServices {
-		prefix = "NetworkServices"
+	if group, ok := LookupAPIGroup(i.APIGroup); ok {
+		prefix = group.Title
 	}
 	return prefix + i.VersionTitle()
 }
@@ -212,9 +212,26 @@ func (i *ServiceInfo) KeyIsZonal() bool {
 	return i.keyType == Zonal
 }
 
-// NetworkServices is true if the APIGroup is networkservices.
+// IsNetworkServices is true if the APIGroup uses the networkservices style
+// of resource names (see APIGroupInfo.Locations).
 func (i *ServiceInfo) IsNetworkServices() bool {
-	return i.APIGroup == APIGroupNetworkServices
+	group, ok := LookupAPIGroup(i.APIGroup)
+	return ok && group.Locations
+}
+
+// HasSelfLink is true if the object has a SelfLink field. The objects of
+// some API groups (e.g. networksecurity) do not have one.
+func (i *ServiceInfo) HasSelfLink() bool {
+	get, ok := i.serviceType.MethodByName("Get")
+	if !ok {
+		return true
+	}
+	do, ok := get.Type.Out(0).MethodByName("Do")
+	if !ok {
+		return true
+	}
+	_, ok = do.Type.Out(0).Elem().FieldByName("SelfLink")
+	return ok
 }
 
 // KeyIsProject is true if the key represents the project resource.
@@ -350,9 +367,12 @@ var AllServicesByGroup map[string]*ServiceGroup
 // SortedServicesGroups is a slice of Servicegroup sorted by Service name.
 var SortedServicesGroups []*ServiceGroup
 
-func init() {
+// groupAllServices sets AllServicesByGroup and SortedServicesGroups from
+// AllServices.
+func groupAllServices() {
 	AllServicesByGroup = groupServices(AllServices)
 
+	SortedServicesGroups = nil
 	for _, sg := range AllServicesByGroup {
 		SortedServicesGroups = append(SortedServicesGroups, sg)
 	}
@@ -360,3 +380,7 @@ func init() {
 		return SortedServicesGroups[i].Service() < SortedServicesGroups[j].Service()
 	})
 }
+
+func init() {
+	groupAllServices()
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Metrics receives measurements for the calls to the API. Set
+// Service.Metrics to export them, e.g. as Prometheus metrics:
+//
+//	type promMetrics struct{ calls *prometheus.CounterVec; ... }
+//
+//	func (m *promMetrics) ObserveCall(key *CallContextKey, latency time.Duration, err error) {
+//		m.calls.WithLabelValues(key.Service, key.Operation, string(key.Version), ErrorCode(err)).Inc()
+//		...
+//	}
+//
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveCall is called when the call to the API identified by key
+	// returns. latency includes the time spent in the RateLimiter and in
+	// retries. For Insert and Delete, it does not include waiting for the
+	// operation to complete; the other methods returning an operation
+	// report after the operation is done.
+	ObserveCall(key *CallContextKey, latency time.Duration, err error)
+	// ObserveOperationWait is called when waiting for a long running
+	// operation (WaitForCompletion) returns. key is the call that started
+	// the operation; it is nil if WaitForCompletion was not called from one
+	// of the generated methods.
+	ObserveOperationWait(key *CallContextKey, latency time.Duration, err error)
+}
+
+// Error codes returned by ErrorCode for errors that are not
+// googleapi.Errors.
+const (
+	ErrorCodeOK               = "OK"
+	ErrorCodeCanceled         = "Canceled"
+	ErrorCodeDeadlineExceeded = "DeadlineExceeded"
+	ErrorCodeUnknown          = "Unknown"
+)
+
+// ErrorCode returns a low cardinality code for err, suitable for labelling
+// metrics. This is the HTTP status code (e.g. "404") for googleapi.Errors
+// and one of the ErrorCode* constants otherwise.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ErrorCodeOK
+	}
+	var apiErr *googleapi.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.Code)
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeDeadlineExceeded
+	}
+	return ErrorCodeUnknown
+}
+
+// metricsObserver reports the calls to Metrics.
+type metricsObserver struct {
+	m Metrics
+}
+
+// metricsObserver implements ServiceObserver.
+var _ ServiceObserver = (*metricsObserver)(nil)
+
+func (o *metricsObserver) PreCall(ctx context.Context, _ *CallContextKey) context.Context {
+	return ctx
+}
+
+func (o *metricsObserver) PostCall(_ context.Context, key *CallContextKey, latency time.Duration, err error) {
+	o.m.ObserveCall(key, latency, err)
+}
+
+// observeOperationWait reports waiting for an operation that started at
+// start.
+func (s *Service) observeOperationWait(ctx context.Context, start time.Time, err error) {
+	if s.Metrics == nil {
+		return
+	}
+	var key *CallContextKey
+	if cs, ok := ctx.Value(callStateContextKey).(*callState); ok {
+		key = cs.key
+	}
+	s.Metrics.ObserveOperationWait(key, time.Since(start), err)
+}
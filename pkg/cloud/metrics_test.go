@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+type metricsRecord struct {
+	Key  string
+	Code string
+}
+
+type recordingMetrics struct {
+	lock  sync.Mutex
+	calls []metricsRecord
+	waits []metricsRecord
+}
+
+func keyString(key *CallContextKey) string {
+	if key == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s/%s.%s", key.Version, key.Service, key.Operation)
+}
+
+func (m *recordingMetrics) ObserveCall(key *CallContextKey, _ time.Duration, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.calls = append(m.calls, metricsRecord{Key: keyString(key), Code: ErrorCode(err)})
+}
+
+func (m *recordingMetrics) ObserveOperationWait(key *CallContextKey, _ time.Duration, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.waits = append(m.waits, metricsRecord{Key: keyString(key), Code: ErrorCode(err)})
+}
+
+func TestErrorCode(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil", want: ErrorCodeOK},
+		{name: "googleapi", err: &googleapi.Error{Code: http.StatusNotFound}, want: "404"},
+		{name: "wrapped googleapi", err: fmt.Errorf("x: %w", &googleapi.Error{Code: http.StatusTooManyRequests}), want: "429"},
+		{name: "canceled", err: context.Canceled, want: ErrorCodeCanceled},
+		{name: "deadline", err: fmt.Errorf("x: %w", context.DeadlineExceeded), want: ErrorCodeDeadlineExceeded},
+		{name: "other", err: fmt.Errorf("x"), want: ErrorCodeUnknown},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ErrorCode(tc.err); got != tc.want {
+				t.Errorf("ErrorCode(%v) = %q, want %q", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedCallsMetrics(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/proj/global/addresses/addr":
+			fmt.Fprint(w, `{"name": "addr"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/proj/global/addresses":
+			fmt.Fprint(w, `{"name": "op", "status": "RUNNING", "selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/proj/global/operations/op/wait":
+			fmt.Fprint(w, `{"name": "op", "status": "DONE"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	m := &recordingMetrics{}
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	s.Metrics = m
+	g := NewGCE(s)
+	ctx := context.Background()
+
+	g.GlobalAddresses().Get(ctx, meta.GlobalKey("addr"))
+	g.GlobalAddresses().Get(ctx, meta.GlobalKey("missing"))
+	g.GlobalAddresses().Insert(ctx, meta.GlobalKey("addr"), &ga.Address{})
+
+	wantCalls := []metricsRecord{
+		{Key: "ga/GlobalAddresses.Get", Code: "OK"},
+		{Key: "ga/GlobalAddresses.Get", Code: "404"},
+		{Key: "ga/GlobalAddresses.Insert", Code: "OK"},
+	}
+	if diff := cmp.Diff(m.calls, wantCalls); diff != "" {
+		t.Errorf("calls: diff -got,+want: %s", diff)
+	}
+	wantWaits := []metricsRecord{{Key: "ga/GlobalAddresses.Insert", Code: "OK"}}
+	if diff := cmp.Diff(m.waits, wantWaits); diff != "" {
+		t.Errorf("waits: diff -got,+want: %s", diff)
+	}
+}
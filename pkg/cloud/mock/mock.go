@@ -420,10 +420,10 @@ func AddInstancesHook(ctx context.Context, key *meta.Key, req *ga.InstanceGroups
 		return err
 	}
 
-	var attrs InstanceGroupAttributes
-	attrs = m.X.(InstanceGroupAttributes)
+	m.Lock.Lock()
+	attrs := m.X.(InstanceGroupAttributes)
+	m.Lock.Unlock()
 	attrs.AddInstances(key, req.Instances)
-	m.X = attrs
 	return nil
 }
 
@@ -439,8 +439,9 @@ func ListInstancesHook(ctx context.Context, key *meta.Key, req *ga.InstanceGroup
 		return nil, err
 	}
 
-	var attrs InstanceGroupAttributes
-	attrs = m.X.(InstanceGroupAttributes)
+	m.Lock.Lock()
+	attrs := m.X.(InstanceGroupAttributes)
+	m.Lock.Unlock()
 	instances := attrs.List(key)
 
 	return instances, nil
@@ -458,10 +459,10 @@ func RemoveInstancesHook(ctx context.Context, key *meta.Key, req *ga.InstanceGro
 		return err
 	}
 
-	var attrs InstanceGroupAttributes
-	attrs = m.X.(InstanceGroupAttributes)
+	m.Lock.Lock()
+	attrs := m.X.(InstanceGroupAttributes)
+	m.Lock.Unlock()
 	attrs.RemoveInstances(key, req.Instances)
-	m.X = attrs
 	return nil
 }
 
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import "sync"
+
+// ErrorSequence is a scripted sequence of errors for a method of the mocks
+// (see the ErrorSequences field of the Mock types). Each call to the method
+// returns the next error in the sequence. A nil error or the end of the
+// sequence continues with the normal behavior of the mock (including the
+// hooks). This can be used to test the retry behavior of the callers:
+//
+//	seq := NewErrorSequence(
+//		&googleapi.Error{Code: http.StatusInternalServerError},
+//		&googleapi.Error{Code: http.StatusTooManyRequests},
+//	)
+//	mock.MockAddresses.ErrorSequences = map[string]*ErrorSequence{"Get": seq}
+//	// Third Get() succeeds.
+//	...
+//	if seq.Calls() != 3 { ... }
+//
+// ErrorSequence is safe for concurrent use.
+type ErrorSequence struct {
+	lock  sync.Mutex
+	errs  []error
+	calls int
+}
+
+// NewErrorSequence returns an ErrorSequence returning errs in order.
+func NewErrorSequence(errs ...error) *ErrorSequence {
+	return &ErrorSequence{errs: errs}
+}
+
+// Next returns the error for the next call. It returns nil once the sequence
+// is exhausted.
+func (s *ErrorSequence) Next() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.calls++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+// Calls returns the number of calls to Next().
+func (s *ErrorSequence) Calls() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.calls
+}
+
+// Remaining returns the number of errors remaining in the sequence.
+func (s *ErrorSequence) Remaining() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.errs)
+}
+
+// nextMockError returns the next error in seqs for method or nil if there is
+// none.
+func nextMockError(seqs map[string]*ErrorSequence, method string) error {
+	seq, ok := seqs[method]
+	if !ok {
+		return nil
+	}
+	return seq.Next()
+}
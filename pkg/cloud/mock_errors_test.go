@@ -0,0 +1,106 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestErrorSequence(t *testing.T) {
+	t.Parallel()
+
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	seq := NewErrorSequence(err1, nil, err2)
+
+	for i, want := range []error{err1, nil, err2, nil, nil} {
+		if got := seq.Next(); got != want {
+			t.Errorf("Next() #%d = %v, want %v", i, got, want)
+		}
+	}
+	if seq.Calls() != 5 || seq.Remaining() != 0 {
+		t.Errorf("Calls() = %d, Remaining() = %d; want 5, 0", seq.Calls(), seq.Remaining())
+	}
+}
+
+func TestMockErrorSequences(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	key := meta.RegionalKey("addr", "us-central1")
+	if err := mock.Addresses().Insert(ctx, key, &ga.Address{}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	getSeq := NewErrorSequence(
+		&googleapi.Error{Code: http.StatusInternalServerError},
+		&googleapi.Error{Code: http.StatusTooManyRequests},
+	)
+	listSeq := NewErrorSequence(&googleapi.Error{Code: http.StatusServiceUnavailable})
+	labelsSeq := NewErrorSequence(&googleapi.Error{Code: http.StatusConflict})
+	mock.MockAddresses.ErrorSequences = map[string]*ErrorSequence{
+		"Get":  getSeq,
+		"List": listSeq,
+	}
+	mock.MockForwardingRules.ErrorSequences = map[string]*ErrorSequence{
+		"SetLabels": labelsSeq,
+	}
+
+	// A caller retrying the Get until it succeeds.
+	var codes []int
+	for {
+		_, err := mock.Addresses().Get(ctx, key)
+		if err == nil {
+			break
+		}
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Get() = %v, want googleapi.Error", err)
+		}
+		codes = append(codes, apiErr.Code)
+	}
+	if len(codes) != 2 || codes[0] != http.StatusInternalServerError || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("Get() error codes = %v, want [500 429]", codes)
+	}
+	if getSeq.Calls() != 3 {
+		t.Errorf("getSeq.Calls() = %d, want 3", getSeq.Calls())
+	}
+
+	if _, err := mock.Addresses().List(ctx, "us-central1", filter.None); err == nil {
+		t.Errorf("List() = nil, want error")
+	}
+	if objs, err := mock.Addresses().List(ctx, "us-central1", filter.None); err != nil || len(objs) != 1 {
+		t.Errorf("List() = %d items, %v; want 1 item, nil", len(objs), err)
+	}
+
+	frKey := meta.RegionalKey("fr", "us-central1")
+	if err := mock.ForwardingRules().SetLabels(ctx, frKey, &ga.RegionSetLabelsRequest{}); err == nil {
+		t.Errorf("SetLabels() = nil, want error")
+	}
+	if err := mock.ForwardingRules().SetLabels(ctx, frKey, &ga.RegionSetLabelsRequest{}); err != nil {
+		t.Errorf("SetLabels() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// MockLatency is an artificial latency for a method of the mocks (see the
+// Latencies field of the Mock types). This can be used to test the handling
+// of timeouts and to detect calls being made serially that should be made in
+// parallel:
+//
+//	mock.MockNetworkEndpointGroups.Latencies = map[string]*MockLatency{
+//		"":       {Latency: 10 * time.Millisecond},
+//		"Delete": {Latency: 100 * time.Millisecond, Jitter: 50 * time.Millisecond},
+//	}
+//
+// The delay happens before any other processing of the mock and does not hold
+// the lock of the mock. The call returns the error of the context if it is
+// done before the end of the delay.
+type MockLatency struct {
+	// Latency is the minimum delay of the call.
+	Latency time.Duration
+	// Jitter is the maximum random delay added to Latency.
+	Jitter time.Duration
+}
+
+// Delay returns a delay in [Latency, Latency+Jitter).
+func (l *MockLatency) Delay() time.Duration {
+	d := l.Latency
+	if l.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(l.Jitter)))
+	}
+	return d
+}
+
+// mockDelay waits for the latency of method in latencies.
+func mockDelay(ctx context.Context, latencies map[string]*MockLatency, method string) error {
+	l, ok := latencies[method]
+	if !ok {
+		l, ok = latencies[""]
+	}
+	if !ok || l == nil {
+		return nil
+	}
+	d := l.Delay()
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// RandomizeInterleavings adds a random delay of up to maxDelay to all of the
+// calls to the mocks without a latency for the method. This shuffles the
+// order of concurrent calls to the mock to find ordering and concurrency bugs
+// in the callers (use with -race). It must be called before the mock is used.
+func (mock *MockGCE) RandomizeInterleavings(maxDelay time.Duration) {
+	mock.setDefaultLatency(&MockLatency{Jitter: maxDelay})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestMockLatencyDelay(t *testing.T) {
+	t.Parallel()
+
+	l := &MockLatency{Latency: 10 * time.Millisecond, Jitter: 5 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		if d := l.Delay(); d < l.Latency || d >= l.Latency+l.Jitter {
+			t.Fatalf("Delay() = %v, want in [%v, %v)", d, l.Latency, l.Latency+l.Jitter)
+		}
+	}
+}
+
+func TestMockLatency(t *testing.T) {
+	t.Parallel()
+
+	const latency = 50 * time.Millisecond
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	var keys []*meta.Key
+	for i := 0; i < 10; i++ {
+		k := meta.GlobalKey(fmt.Sprintf("addr-%d", i))
+		if err := mock.GlobalAddresses().Insert(ctx, k, &ga.Address{}); err != nil {
+			t.Fatalf("Insert(%v) = %v", k, err)
+		}
+		keys = append(keys, k)
+	}
+	mock.MockGlobalAddresses.Latencies = map[string]*MockLatency{
+		"":    {Latency: latency},
+		"Get": {Latency: time.Hour},
+	}
+
+	// The calls are not serialized by the mock.
+	start := time.Now()
+	err := BatchDo(ctx, len(keys), keys, func(ctx context.Context, k *meta.Key) error {
+		return mock.GlobalAddresses().Delete(ctx, k)
+	})
+	if err != nil {
+		t.Fatalf("BatchDo() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < latency || elapsed >= time.Duration(len(keys))*latency {
+		t.Errorf("elapsed = %v, want in [%v, %v)", elapsed, latency, time.Duration(len(keys))*latency)
+	}
+
+	// The latency is interrupted by the deadline of the context.
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := mock.GlobalAddresses().Get(tctx, keys[0]); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// MockOperations simulates the long running operations of the API in the
+// mocks. By default, Insert and Delete of the mocks complete synchronously.
+// With MockOperations (see MockGCE.AsyncOperations), they start a pending
+// MockOperation and wait for it to complete before the change is made, the
+// same way that the GCE methods wait for the Operation returned by the API:
+//
+//	ops := mock.AsyncOperations(0)
+//	go func() { errc <- mock.BackendServices().Insert(ctx, key, obj) }()
+//	// ... check the behavior while the Insert is pending ...
+//	ops.CompleteAll(nil)
+//
+// An operation completes after Delay or when Complete is called. If it
+// completes with an error, the call returns the error and the change is not
+// made. If the context of the call is done first, the call returns the error
+// of the context and the change is not made.
+type MockOperations struct {
+	// Delay after which the operations complete. If zero, the operations
+	// only complete when Complete or CompleteAll is called.
+	Delay time.Duration
+
+	lock    sync.Mutex
+	count   int
+	pending []*MockOperation
+}
+
+// MockOperation is an operation started by a mock.
+type MockOperation struct {
+	// Name of the operation, e.g. "operation-1".
+	Name string
+	// Service of the mock, e.g. "BackendServices".
+	Service string
+	// Version of the mock.
+	Version meta.Version
+	// Method that started the operation, e.g. "Insert".
+	Method string
+	// Key of the object.
+	Key *meta.Key
+
+	done chan struct{}
+	err  error
+}
+
+// String implements fmt.Stringer.
+func (op *MockOperation) String() string {
+	return fmt.Sprintf("MockOperation{%s, %s/%s.%s(%v)}", op.Name, op.Version, op.Service, op.Method, op.Key)
+}
+
+// Pending returns the operations that have not completed, in the order they
+// were started.
+func (o *MockOperations) Pending() []*MockOperation {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return append([]*MockOperation(nil), o.pending...)
+}
+
+// Complete completes op with err (nil for success). It returns false if op
+// was already completed.
+func (o *MockOperations) Complete(op *MockOperation, err error) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for i, p := range o.pending {
+		if p == op {
+			o.pending = append(o.pending[:i], o.pending[i+1:]...)
+			op.err = err
+			close(op.done)
+			return true
+		}
+	}
+	return false
+}
+
+// CompleteAll completes all of the pending operations with err and returns
+// the number of operations completed.
+func (o *MockOperations) CompleteAll(err error) int {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	n := len(o.pending)
+	for _, op := range o.pending {
+		op.err = err
+		close(op.done)
+	}
+	o.pending = nil
+	return n
+}
+
+// start a pending operation.
+func (o *MockOperations) start(service string, version meta.Version, method string, key *meta.Key) *MockOperation {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.count++
+	op := &MockOperation{
+		Name:    fmt.Sprintf("operation-%d", o.count),
+		Service: service,
+		Version: version,
+		Method:  method,
+		Key:     key,
+		done:    make(chan struct{}),
+	}
+	o.pending = append(o.pending, op)
+	if o.Delay > 0 {
+		time.AfterFunc(o.Delay, func() { o.Complete(op, nil) })
+	}
+	return op
+}
+
+// mockOperation starts an operation for the call and waits for it to
+// complete. It returns nil immediately if ops is nil.
+func mockOperation(ctx context.Context, ops *MockOperations, service string, version meta.Version, method string, key *meta.Key) error {
+	if ops == nil {
+		return nil
+	}
+	op := ops.start(service, version, method, key)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-op.done:
+		return op.err
+	}
+}
+
+// AsyncOperations makes Insert and Delete of all of the mocks wait for a
+// MockOperation that completes after delay (or when completed with the
+// returned MockOperations if delay is zero). It must be called before the
+// mock is used.
+func (mock *MockGCE) AsyncOperations(delay time.Duration) *MockOperations {
+	ops := &MockOperations{Delay: delay}
+	mock.setOperations(ops)
+	return ops
+}
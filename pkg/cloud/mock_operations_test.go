@@ -0,0 +1,140 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+// waitPending waits for n operations to be pending.
+func waitPending(t *testing.T, ops *MockOperations, n int) []*MockOperation {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(time.Millisecond) {
+		if p := ops.Pending(); len(p) == n {
+			return p
+		}
+	}
+	t.Fatalf("timed out waiting for %d pending operations (got %v)", n, ops.Pending())
+	return nil
+}
+
+func TestMockOperations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := meta.GlobalKey("addr")
+	opErr := errors.New("operation failed")
+
+	for _, tc := range []struct {
+		name    string
+		method  string
+		err     error
+		wantErr bool
+		wantObj bool
+	}{
+		{name: "insert", method: "Insert", wantObj: true},
+		{name: "insert error", method: "Insert", err: opErr, wantErr: true},
+		{name: "delete", method: "Delete"},
+		{name: "delete error", method: "Delete", err: opErr, wantErr: true, wantObj: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+			if tc.method == "Delete" {
+				if err := mock.GlobalAddresses().Insert(ctx, key, &ga.Address{}); err != nil {
+					t.Fatalf("Insert() = %v", err)
+				}
+			}
+			ops := mock.AsyncOperations(0)
+
+			errc := make(chan error)
+			go func() {
+				if tc.method == "Insert" {
+					errc <- mock.GlobalAddresses().Insert(ctx, key, &ga.Address{})
+				} else {
+					errc <- mock.GlobalAddresses().Delete(ctx, key)
+				}
+			}()
+			pending := waitPending(t, ops, 1)
+			op := pending[0]
+			if op.Service != "GlobalAddresses" || op.Version != meta.VersionGA || op.Method != tc.method || *op.Key != *key {
+				t.Errorf("op = %v, want GlobalAddresses %s %v", op, tc.method, key)
+			}
+			// The change is not visible while the operation is pending.
+			if _, err := mock.GlobalAddresses().Get(ctx, key); (err == nil) != (tc.method == "Delete") {
+				t.Errorf("Get() = %v while the operation is pending", err)
+			}
+
+			if !ops.Complete(op, tc.err) {
+				t.Fatalf("Complete(%v) = false, want true", op)
+			}
+			if ops.Complete(op, nil) {
+				t.Errorf("second Complete(%v) = true, want false", op)
+			}
+			if err := <-errc; (err != nil) != tc.wantErr {
+				t.Errorf("%s() = %v; gotErr = %t, want %t", tc.method, err, err != nil, tc.wantErr)
+			}
+			if _, err := mock.GlobalAddresses().Get(ctx, key); (err == nil) != tc.wantObj {
+				t.Errorf("Get() = %v; want object = %t", err, tc.wantObj)
+			}
+		})
+	}
+}
+
+func TestMockOperationsDelay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	mock.AsyncOperations(10 * time.Millisecond)
+
+	start := time.Now()
+	if err := mock.Firewalls().Insert(ctx, meta.GlobalKey("fw"), &ga.Firewall{}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Errorf("Insert() took %v, want >= 10ms", d)
+	}
+}
+
+func TestMockOperationsContext(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	ops := mock.AsyncOperations(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	key := meta.GlobalKey("fw")
+	if err := mock.Firewalls().Insert(ctx, key, &ga.Firewall{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Insert() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := ops.CompleteAll(nil); n != 1 {
+		t.Errorf("CompleteAll() = %d, want 1", n)
+	}
+	if _, err := mock.Firewalls().Get(context.Background(), key); err == nil {
+		t.Errorf("Get() = nil, want error")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+// TestMockConcurrentAccess calls the mocks for all of the versions of a
+// service concurrently. Run with -race.
+func TestMockConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	const (
+		workers = 8
+		n       = 20
+		region  = "us-central1"
+	)
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	mock.RandomizeInterleavings(time.Millisecond)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				key := meta.RegionalKey(fmt.Sprintf("addr-%d-%d", w, i), region)
+				var err error
+				switch i % 3 {
+				case 0:
+					err = mock.Addresses().Insert(ctx, key, &ga.Address{})
+				case 1:
+					err = mock.AlphaAddresses().Insert(ctx, key, &alpha.Address{})
+				case 2:
+					err = mock.BetaAddresses().Insert(ctx, key, &beta.Address{})
+				}
+				if err != nil {
+					t.Errorf("Insert(%v) = %v", key, err)
+					return
+				}
+				if _, err := mock.BetaAddresses().Get(ctx, key); err != nil {
+					t.Errorf("BetaAddresses().Get(%v) = %v", key, err)
+				}
+				if _, err := mock.AlphaAddresses().List(ctx, region, filter.None); err != nil {
+					t.Errorf("AlphaAddresses().List() = %v", err)
+				}
+				if _, err := mock.Addresses().AggregatedList(ctx, filter.None); err != nil {
+					t.Errorf("Addresses().AggregatedList() = %v", err)
+				}
+				if i%2 == 0 {
+					if err := mock.Addresses().Delete(ctx, key); err != nil {
+						t.Errorf("Delete(%v) = %v", key, err)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	objs, err := mock.Addresses().List(ctx, region, filter.None)
+	if err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+	if want := workers * n / 2; len(objs) != want {
+		t.Errorf("len(List()) = %d, want %d", len(objs), want)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// MockState is a snapshot of the objects in a MockGCE. It can be serialized
+// to JSON to save the state of a mock and load it later (e.g. as a fixture
+// for tests):
+//
+//	state, err := mock.DumpState()
+//	data, err := json.Marshal(state)
+//	...
+//	var state MockState
+//	err := json.Unmarshal(data, &state)
+//	err = NewMockGCE(pr).LoadState(&state)
+//
+// A fixture can be captured from a real project by adding the objects
+// returned by the API with their version (see MockStateObject).
+type MockState struct {
+	Objects []*MockStateObject `json:"objects"`
+}
+
+// MockStateObject is an object in a MockState.
+type MockStateObject struct {
+	// Service of the object, e.g. "Addresses", "BackendServices".
+	Service string `json:"service"`
+	// Version of the API of Object.
+	Version meta.Version `json:"version"`
+	// Key of the object. If Key is nil, the key is parsed from the
+	// selfLink of the Object.
+	Key *meta.Key `json:"key,omitempty"`
+	// Object is the JSON of the API object.
+	Object json.RawMessage `json:"object"`
+}
+
+// key returns the Key of the object.
+func (o *MockStateObject) key() (*meta.Key, error) {
+	if o.Key != nil {
+		if !o.Key.Valid() {
+			return nil, fmt.Errorf("invalid key %v", o.Key)
+		}
+		return o.Key, nil
+	}
+	var obj struct {
+		SelfLink string `json:"selfLink"`
+	}
+	if err := json.Unmarshal(o.Object, &obj); err != nil {
+		return nil, err
+	}
+	if obj.SelfLink == "" {
+		return nil, fmt.Errorf("object has no key and no selfLink")
+	}
+	id, err := ParseResourceURL(obj.SelfLink)
+	if err != nil {
+		return nil, err
+	}
+	return id.Key, nil
+}
+
+// add the object to the state.
+func (s *MockState) add(service string, key meta.Key, obj any) error {
+	ver, err := objectVersion(obj)
+	if err != nil {
+		return fmt.Errorf("%s %v: %w", service, key, err)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("%s %v: %w", service, key, err)
+	}
+	s.Objects = append(s.Objects, &MockStateObject{
+		Service: service,
+		Version: ver,
+		Key:     &key,
+		Object:  data,
+	})
+	return nil
+}
+
+// objectVersion returns the API version of obj from the package of its type.
+func objectVersion(obj any) (meta.Version, error) {
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	pkg := t.PkgPath()
+	switch {
+	case strings.HasSuffix(pkg, "alpha"):
+		return meta.VersionAlpha, nil
+	case strings.HasSuffix(pkg, "beta"), strings.HasSuffix(pkg, "beta1"):
+		return meta.VersionBeta, nil
+	case strings.HasSuffix(pkg, "/v1"):
+		return meta.VersionGA, nil
+	}
+	return "", fmt.Errorf("unknown API version for %T", obj)
+}
+
+// DumpState returns a snapshot of the objects in the mock. The objects are
+// sorted by service and key.
+func (mock *MockGCE) DumpState() (*MockState, error) {
+	s := &MockState{}
+	if err := mock.dumpObjects(s); err != nil {
+		return nil, err
+	}
+	sort.Slice(s.Objects, func(i, j int) bool {
+		a, b := s.Objects[i], s.Objects[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Key.String() < b.Key.String()
+	})
+	return s, nil
+}
+
+// LoadState adds the objects in s to the mock. Objects with the same key
+// replace the objects already in the mock.
+func (mock *MockGCE) LoadState(s *MockState) error {
+	for _, o := range s.Objects {
+		if err := mock.loadObject(o); err != nil {
+			return fmt.Errorf("LoadState: %w", err)
+		}
+	}
+	return nil
+}
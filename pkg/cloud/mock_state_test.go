@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestMockState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{ID: "proj"}
+	mock := NewMockGCE(pr)
+
+	if err := mock.Addresses().Insert(ctx, meta.RegionalKey("addr", "us-central1"), &ga.Address{Address: "1.2.3.4"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if err := mock.AlphaBackendServices().Insert(ctx, meta.GlobalKey("bs"), &alpha.BackendService{Description: "alpha"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if err := mock.NetworkEndpointGroups().Insert(ctx, meta.ZonalKey("neg", "us-central1-b"), &ga.NetworkEndpointGroup{}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	state, err := mock.DumpState()
+	if err != nil {
+		t.Fatalf("DumpState() = %v", err)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	var state2 MockState
+	if err := json.Unmarshal(data, &state2); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	mock2 := NewMockGCE(pr)
+	if err := mock2.LoadState(&state2); err != nil {
+		t.Fatalf("LoadState() = %v", err)
+	}
+
+	addr, err := mock2.Addresses().Get(ctx, meta.RegionalKey("addr", "us-central1"))
+	if err != nil || addr.Address != "1.2.3.4" {
+		t.Errorf("Addresses().Get() = %+v, %v; want Address 1.2.3.4", addr, err)
+	}
+	bs, err := mock2.AlphaBackendServices().Get(ctx, meta.GlobalKey("bs"))
+	if err != nil || bs.Description != "alpha" {
+		t.Errorf("AlphaBackendServices().Get() = %+v, %v; want Description alpha", bs, err)
+	}
+	negs, err := mock2.NetworkEndpointGroups().List(ctx, "us-central1-b", filter.None)
+	if err != nil || len(negs) != 1 {
+		t.Errorf("NetworkEndpointGroups().List() = %d items, %v; want 1 item", len(negs), err)
+	}
+
+	// Dumping the loaded state gives the same result.
+	state3, err := mock2.DumpState()
+	if err != nil {
+		t.Fatalf("DumpState() = %v", err)
+	}
+	data3, err := json.Marshal(state3)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	if string(data3) != string(data) {
+		t.Errorf("DumpState() after LoadState() = %s, want %s", data3, data)
+	}
+}
+
+func TestMockStateFixture(t *testing.T) {
+	t.Parallel()
+
+	const fixture = `{"objects": [
+		{
+			"service": "Firewalls",
+			"version": "ga",
+			"object": {"name": "fw", "selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/firewalls/fw"}
+		}
+	]}`
+
+	for _, tc := range []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "selfLink", data: fixture},
+		{name: "unknown service", data: `{"objects": [{"service": "Foos", "version": "ga", "object": {}}]}`, wantErr: true},
+		{name: "bad version", data: `{"objects": [{"service": "Firewalls", "version": "v2", "object": {}}]}`, wantErr: true},
+		{name: "no key", data: `{"objects": [{"service": "Firewalls", "version": "ga", "object": {"name": "fw"}}]}`, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var state MockState
+			if err := json.Unmarshal([]byte(tc.data), &state); err != nil {
+				t.Fatalf("json.Unmarshal() = %v", err)
+			}
+			mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+			err := mock.LoadState(&state)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("LoadState() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if _, err := mock.Firewalls().Get(context.Background(), meta.GlobalKey("fw")); err != nil {
+				t.Errorf("Firewalls().Get() = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -149,3 +149,83 @@ func TestMocks(t *testing.T) {
 		t.Errorf("Addresses().Delete(%v, %v) = nil; want error", ctx, key)
 	}
 }
+
+func TestMockAggregatedList(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+
+	for _, k := range []*meta.Key{
+		meta.RegionalKey("fr-1", "us-central1"),
+		meta.RegionalKey("fr-2", "us-central1"),
+		meta.RegionalKey("fr-3", "us-east1"),
+	} {
+		if err := mock.ForwardingRules().Insert(ctx, k, &ga.ForwardingRule{}); err != nil {
+			t.Fatalf("ForwardingRules().Insert(%v) = %v", k, err)
+		}
+	}
+	for _, k := range []*meta.Key{
+		meta.ZonalKey("ig-1", "us-central1-b"),
+		meta.ZonalKey("ig-2", "us-central1-c"),
+	} {
+		if err := mock.InstanceGroups().Insert(ctx, k, &ga.InstanceGroup{}); err != nil {
+			t.Fatalf("InstanceGroups().Insert(%v) = %v", k, err)
+		}
+	}
+
+	names := func(m map[string][]string) map[string]map[string]bool {
+		ret := map[string]map[string]bool{}
+		for loc, l := range m {
+			ret[loc] = map[string]bool{}
+			for _, n := range l {
+				ret[loc][n] = true
+			}
+		}
+		return ret
+	}
+
+	frs, err := mock.ForwardingRules().AggregatedList(ctx, filter.None)
+	if err != nil {
+		t.Fatalf("ForwardingRules().AggregatedList() = %v", err)
+	}
+	got := map[string][]string{}
+	for loc, l := range frs {
+		for _, o := range l {
+			got[loc] = append(got[loc], o.Name)
+		}
+	}
+	want := map[string][]string{
+		"regions/us-central1": {"fr-1", "fr-2"},
+		"regions/us-east1":    {"fr-3"},
+	}
+	if !reflect.DeepEqual(names(got), names(want)) {
+		t.Errorf("ForwardingRules().AggregatedList() = %v, want %v", got, want)
+	}
+
+	alphaFRs, err := mock.AlphaForwardingRules().AggregatedList(ctx, filter.Regexp("name", "fr-[12]"))
+	if err != nil {
+		t.Fatalf("AlphaForwardingRules().AggregatedList() = %v", err)
+	}
+	if n := len(alphaFRs["regions/us-central1"]); len(alphaFRs) != 1 || n != 2 {
+		t.Errorf("AlphaForwardingRules().AggregatedList(name ~ fr-[12]) = %v, want 2 items in regions/us-central1", alphaFRs)
+	}
+
+	igs, err := mock.InstanceGroups().AggregatedList(ctx, filter.None)
+	if err != nil {
+		t.Fatalf("InstanceGroups().AggregatedList() = %v", err)
+	}
+	got = map[string][]string{}
+	for loc, l := range igs {
+		for _, o := range l {
+			got[loc] = append(got[loc], o.Name)
+		}
+	}
+	want = map[string][]string{
+		"zones/us-central1-b": {"ig-1"},
+		"zones/us-central1-c": {"ig-2"},
+	}
+	if !reflect.DeepEqual(names(got), names(want)) {
+		t.Errorf("InstanceGroups().AggregatedList() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// MockValidator returns a validator for the Validator field of the Mock types
+// that runs the validators in order and returns the first error:
+//
+//	mock.MockBackendServices.Validator = MockValidator(
+//		RequireFields[ga.BackendService]("LoadBalancingScheme", "Protocol"),
+//		EnumField[ga.BackendService]("Protocol", "HTTP", "HTTPS", "HTTP2", "TCP", "SSL", "UDP", "GRPC"),
+//	)
+func MockValidator[T any](validators ...func(*T) error) func(*T) error {
+	return func(obj *T) error {
+		for _, v := range validators {
+			if err := v(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// RequireFields returns a validator that rejects objects where any of the
+// fields is the zero value. Fields are Go field names; nested fields are
+// separated with ".", e.g. "CdnPolicy.CacheMode".
+func RequireFields[T any](fields ...string) func(*T) error {
+	return func(obj *T) error {
+		for _, f := range fields {
+			v, err := fieldByPath(obj, f)
+			if err != nil {
+				return err
+			}
+			if !v.IsValid() || v.IsZero() {
+				return fmt.Errorf("required field %s is missing", f)
+			}
+		}
+		return nil
+	}
+}
+
+// EnumField returns a validator that rejects objects where the string field
+// is set to a value not in values. An empty field is accepted (use
+// RequireFields to reject empty fields).
+func EnumField[T any](field string, values ...string) func(*T) error {
+	return func(obj *T) error {
+		v, err := fieldByPath(obj, field)
+		if err != nil {
+			return err
+		}
+		if !v.IsValid() || v.IsZero() {
+			return nil
+		}
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("field %s is not a string (%v)", field, v.Kind())
+		}
+		for _, val := range values {
+			if v.String() == val {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q for field %s, must be one of [%s]", v.String(), field, strings.Join(values, ", "))
+	}
+}
+
+// fieldByPath returns the field of obj named by the "."-separated path. It
+// returns an invalid Value if a pointer along the path is nil.
+func fieldByPath(obj any, path string) (reflect.Value, error) {
+	v := reflect.ValueOf(obj)
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, nil
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("invalid field path %q: %v is not a struct", path, v.Type())
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return reflect.Value{}, fmt.Errorf("invalid field path %q: no field %q", path, name)
+		}
+	}
+	return v, nil
+}
+
+// mockValidationError converts the error of a validator into the HTTP 400
+// error returned by the API for invalid objects. googleapi.Errors are
+// returned as is.
+func mockValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	return &googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("Invalid value for field 'resource': %v", err),
+		Errors:  []googleapi.ErrorItem{{Reason: "invalid", Message: err.Error()}},
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestMockValidatorHelpers(t *testing.T) {
+	t.Parallel()
+
+	v := MockValidator(
+		RequireFields[ga.BackendService]("Protocol", "CdnPolicy.CacheMode"),
+		EnumField[ga.BackendService]("Protocol", "HTTP", "HTTPS"),
+	)
+	for _, tc := range []struct {
+		name    string
+		obj     *ga.BackendService
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			obj:  &ga.BackendService{Protocol: "HTTP", CdnPolicy: &ga.BackendServiceCdnPolicy{CacheMode: "CACHE_ALL_STATIC"}},
+		},
+		{
+			name:    "missing field",
+			obj:     &ga.BackendService{CdnPolicy: &ga.BackendServiceCdnPolicy{CacheMode: "CACHE_ALL_STATIC"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing nested field with nil parent",
+			obj:     &ga.BackendService{Protocol: "HTTP"},
+			wantErr: true,
+		},
+		{
+			name:    "bad enum",
+			obj:     &ga.BackendService{Protocol: "FTP", CdnPolicy: &ga.BackendServiceCdnPolicy{CacheMode: "CACHE_ALL_STATIC"}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := v(tc.obj)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validator() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+		})
+	}
+
+	if err := RequireFields[ga.BackendService]("NoSuchField")(&ga.BackendService{}); err == nil {
+		t.Errorf("RequireFields(NoSuchField) = nil, want error")
+	}
+}
+
+func TestMockValidator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	mock.MockBackendServices.Validator = MockValidator(
+		RequireFields[ga.BackendService]("Protocol"),
+		EnumField[ga.BackendService]("Protocol", "HTTP", "HTTPS"),
+	)
+	updated := false
+	mock.MockBackendServices.UpdateHook = func(context.Context, *meta.Key, *ga.BackendService, *MockBackendServices, ...Option) error {
+		updated = true
+		return nil
+	}
+	key := meta.GlobalKey("bs")
+
+	checkBadRequest := func(name string, err error) {
+		t.Helper()
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+			t.Errorf("%s() = %v, want HTTP %d", name, err, http.StatusBadRequest)
+		}
+	}
+
+	checkBadRequest("Insert", mock.BackendServices().Insert(ctx, key, &ga.BackendService{}))
+	if _, err := mock.BackendServices().Get(ctx, key); err == nil {
+		t.Errorf("Get() = nil, want error (invalid object was inserted)")
+	}
+	if err := mock.BackendServices().Insert(ctx, key, &ga.BackendService{Protocol: "HTTP"}); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+	checkBadRequest("Update", mock.BackendServices().Update(ctx, key, &ga.BackendService{Protocol: "FTP"}))
+	if updated {
+		t.Errorf("UpdateHook was called for an invalid object")
+	}
+	if err := mock.BackendServices().Update(ctx, key, &ga.BackendService{Protocol: "HTTPS"}); err != nil || !updated {
+		t.Errorf("Update() = %v, updated = %t; want nil, true", err, updated)
+	}
+}
@@ -19,8 +19,43 @@ package cloud
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
+// ServiceObserver is notified of all of the calls to the API made by the
+// generated code with a Service (see Service.Observers). Unlike
+// CallObserver, which is set per call in the context, ServiceObservers apply
+// to every call and can be chained, e.g. to add logging or metrics:
+//
+//	type logObserver struct{}
+//
+//	func (*logObserver) PreCall(ctx context.Context, key *CallContextKey) context.Context {
+//		return ctx
+//	}
+//
+//	func (*logObserver) PostCall(ctx context.Context, key *CallContextKey, latency time.Duration, err error) {
+//		klog.Infof("%s.%s: %v (%v)", key.Service, key.Operation, err, latency)
+//	}
+//
+//	svc.Observers = append(svc.Observers, &logObserver{})
+//
+// Implementations must be safe for concurrent use.
+type ServiceObserver interface {
+	// PreCall is called before the call identified by key. This happens
+	// before the RateLimiter.Accept(). The returned context is used for
+	// the call and is passed to the next ServiceObserver and to PostCall.
+	PreCall(ctx context.Context, key *CallContextKey) context.Context
+	// PostCall is called when the call returns with err. latency is the time
+	// since PreCall. PostCall is called in the reverse order of the
+	// ServiceObservers.
+	//
+	// For Insert and Delete, this happens before waiting for the operation
+	// to complete; the other methods returning an operation call PostCall
+	// after the operation is done. PostCall is not called if the
+	// RateLimiter rejects the call.
+	PostCall(ctx context.Context, key *CallContextKey, latency time.Duration, err error)
+}
+
 // CallObserver is called between the start and end of the operation.
 type CallObserver interface {
 	// Start event for a call to the API. This happens before the
@@ -61,7 +96,12 @@ func callObserverStart(ctx context.Context, key *CallContextKey) {
 	co.Start(ctx, key)
 }
 
+// callObserverEnd also records err on the span of the call and ends the
+// call for the ServiceObservers.
 func callObserverEnd(ctx context.Context, key *CallContextKey, err error) {
+	recordSpanError(ctx, err)
+	endCall(ctx, key, err)
+
 	obj := ctx.Value(callObserverContextKey)
 	if obj == nil {
 		return
@@ -72,3 +112,83 @@ func callObserverEnd(ctx context.Context, key *CallContextKey, err error) {
 	}
 	co.End(ctx, key, err)
 }
+
+// OperationObserver is notified when a call to the API has started a long
+// running operation. This can be used to record the operation (e.g. for
+// recovery after a crash) before waiting for it to complete.
+type OperationObserver interface {
+	// OperationStarted is called with the URL of the operation. For the
+	// Compute APIs this is the SelfLink of the Operation; for the Network
+	// Services APIs this is the Operation name.
+	OperationStarted(ctx context.Context, url string)
+}
+
+var operationObserverContextKey = contextKey("operation observer")
+
+// WithOperationObserver adds an OperationObserver that will be called when a
+// long running operation is started.
+func WithOperationObserver(ctx context.Context, obs OperationObserver) context.Context {
+	return context.WithValue(ctx, operationObserverContextKey, obs)
+}
+
+func operationObserverStarted(ctx context.Context, url string) {
+	obj := ctx.Value(operationObserverContextKey)
+	if obj == nil {
+		return
+	}
+	oo, ok := obj.(OperationObserver)
+	if !ok {
+		panic(fmt.Sprintf("expected OperationObserver, got %T", obj))
+	}
+	oo.OperationStarted(ctx, url)
+}
+
+var callStateContextKey = contextKey("call state")
+
+// callState is the state of a call for the ServiceObservers.
+type callState struct {
+	key   *CallContextKey
+	start time.Time
+	// observers that were called with PreCall and the context each one
+	// returned.
+	observers []ServiceObserver
+	ctxs      []context.Context
+}
+
+// observers returns the ServiceObservers for the calls made with s.
+func (s *Service) observers() []ServiceObserver {
+	if s.Metrics == nil {
+		return s.Observers
+	}
+	return append(s.Observers[:len(s.Observers):len(s.Observers)], &metricsObserver{m: s.Metrics})
+}
+
+// startCall calls PreCall on the ServiceObservers for the call identified by
+// ck. The call must be ended with endCall.
+func (s *Service) startCall(ctx context.Context, ck *CallContextKey) context.Context {
+	obs := s.observers()
+	if len(obs) == 0 {
+		return ctx
+	}
+	cs := &callState{key: ck, start: time.Now()}
+	ctx = context.WithValue(ctx, callStateContextKey, cs)
+	for _, o := range obs {
+		ctx = o.PreCall(ctx, ck)
+		cs.observers = append(cs.observers, o)
+		cs.ctxs = append(cs.ctxs, ctx)
+	}
+	return ctx
+}
+
+// endCall calls PostCall on the ServiceObservers of the call ck started by
+// startCall in ctx.
+func endCall(ctx context.Context, ck *CallContextKey, err error) {
+	cs, ok := ctx.Value(callStateContextKey).(*callState)
+	if !ok || cs.key != ck {
+		return
+	}
+	latency := time.Since(cs.start)
+	for i := len(cs.observers) - 1; i >= 0; i-- {
+		cs.observers[i].PostCall(cs.ctxs[i], cs.key, latency, err)
+	}
+}
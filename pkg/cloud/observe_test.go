@@ -19,7 +19,13 @@ package cloud
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
 )
 
 type fakeCO struct {
@@ -62,3 +68,82 @@ func TestCallObserver(t *testing.T) {
 		})
 	}
 }
+
+type fakeOO struct {
+	urls []string
+}
+
+func (f *fakeOO) OperationStarted(ctx context.Context, url string) { f.urls = append(f.urls, url) }
+
+func TestOperationObserver(t *testing.T) {
+	const url = "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"
+
+	obs := &fakeOO{}
+	ctx, cancel := context.WithCancel(WithOperationObserver(context.Background(), obs))
+	// Cancel the context so that WaitForCompletion does not poll.
+	cancel()
+
+	s := &Service{}
+	if err := s.WaitForCompletion(ctx, &ga.Operation{SelfLink: url}); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForCompletion() = %v, want %v", err, context.Canceled)
+	}
+	if len(obs.urls) != 1 || obs.urls[0] != url {
+		t.Errorf("obs.urls = %v, want [%s]", obs.urls, url)
+	}
+}
+
+type obsContextKey string
+
+// fakeSO records the calls in events and adds its name to the context.
+type fakeSO struct {
+	name   string
+	events *[]string
+	errs   []error
+}
+
+func (f *fakeSO) PreCall(ctx context.Context, key *CallContextKey) context.Context {
+	*f.events = append(*f.events, f.name+".PreCall("+key.Operation+")")
+	return context.WithValue(ctx, obsContextKey(f.name), true)
+}
+
+func (f *fakeSO) PostCall(ctx context.Context, key *CallContextKey, _ time.Duration, err error) {
+	var ctxNames []string
+	for _, n := range []string{"a", "b"} {
+		if ctx.Value(obsContextKey(n)) != nil {
+			ctxNames = append(ctxNames, n)
+		}
+	}
+	*f.events = append(*f.events, f.name+".PostCall("+key.Operation+")"+fmt.Sprint(ctxNames))
+	f.errs = append(f.errs, err)
+}
+
+func TestServiceObservers(t *testing.T) {
+	var events []string
+	a := &fakeSO{name: "a", events: &events}
+	b := &fakeSO{name: "b", events: &events}
+
+	s := &Service{
+		RateLimiter: &NopRateLimiter{},
+		Observers:   []ServiceObserver{a, b},
+	}
+	ctx := context.Background()
+
+	ck := &CallContextKey{Service: "Addresses", Operation: "Get", Version: meta.VersionGA}
+	ctx2 := s.startCall(ctx, ck)
+	callObserverEnd(ctx2, ck, errors.New("injected"))
+	// Ending a different call is a no-op.
+	callObserverEnd(ctx2, &CallContextKey{Operation: "Other"}, nil)
+
+	want := []string{
+		"a.PreCall(Get)",
+		"b.PreCall(Get)",
+		"b.PostCall(Get)[a b]",
+		"a.PostCall(Get)[a]",
+	}
+	if diff := cmp.Diff(events, want); diff != "" {
+		t.Errorf("events: diff -got,+want: %s", diff)
+	}
+	if len(a.errs) != 1 || a.errs[0] == nil {
+		t.Errorf("a.errs = %v, want [injected]", a.errs)
+	}
+}
@@ -52,6 +52,9 @@ type operation interface {
 	// This rate limit will govern how fast the server will be polled for
 	// operation completion status.
 	rateLimitKey() *RateLimitKey
+	// progress returns the status and the progress percent of the operation
+	// from the last isDone.
+	progress() (status string, percent int)
 }
 
 type gaOperation struct {
@@ -59,6 +62,8 @@ type gaOperation struct {
 	projectID string
 	key       *meta.Key
 	err       error
+	status    string
+	percent   int
 }
 
 func (o *gaOperation) String() string {
@@ -104,6 +109,9 @@ func (o *gaOperation) isDone(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if op != nil {
+		o.status, o.percent = op.Status, int(op.Progress)
+	}
 	if op == nil || op.Status != operationStatusDone {
 		return false, nil
 	}
@@ -128,11 +136,17 @@ func (o *gaOperation) error() error {
 	return o.err
 }
 
+func (o *gaOperation) progress() (string, int) {
+	return o.status, o.percent
+}
+
 type alphaOperation struct {
 	s         *Service
 	projectID string
 	key       *meta.Key
 	err       error
+	status    string
+	percent   int
 }
 
 func (o *alphaOperation) String() string {
@@ -178,6 +192,9 @@ func (o *alphaOperation) isDone(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if op != nil {
+		o.status, o.percent = op.Status, int(op.Progress)
+	}
 	if op == nil || op.Status != operationStatusDone {
 		return false, nil
 	}
@@ -202,11 +219,17 @@ func (o *alphaOperation) error() error {
 	return o.err
 }
 
+func (o *alphaOperation) progress() (string, int) {
+	return o.status, o.percent
+}
+
 type betaOperation struct {
 	s         *Service
 	projectID string
 	key       *meta.Key
 	err       error
+	status    string
+	percent   int
 }
 
 func (o *betaOperation) String() string {
@@ -251,6 +274,9 @@ func (o *betaOperation) isDone(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if op != nil {
+		o.status, o.percent = op.Status, int(op.Progress)
+	}
 	if op == nil || op.Status != operationStatusDone {
 		return false, nil
 	}
@@ -274,3 +300,7 @@ func (o *betaOperation) rateLimitKey() *RateLimitKey {
 func (o *betaOperation) error() error {
 	return o.err
 }
+
+func (o *betaOperation) progress() (string, int) {
+	return o.status, o.percent
+}
@@ -29,11 +29,16 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
+// networkServicesOperation is an operation of the networkservices API, or of
+// the networksecurity API if apiGroup is meta.APIGroupNetworkSecurity. Both
+// APIs name their operations in the same way.
 type networkServicesOperation struct {
 	s         *Service
+	apiGroup  meta.APIGroup
 	projectID string
 	key       *meta.Key
 	err       error
+	done      bool
 }
 
 func (o *networkServicesOperation) String() string {
@@ -49,8 +54,11 @@ func (o *networkServicesOperation) isDone(ctx context.Context) (bool, error) {
 	fqname := fmt.Sprintf("projects/%s/locations/global/operations/%s", o.projectID, o.key.Name)
 	klog.V(5).Infof("isDone %q", fqname)
 
-	switch o.key.Type() {
-	case meta.Global:
+	switch {
+	case o.key.Type() == meta.Global && o.apiGroup == meta.APIGroupNetworkSecurity:
+		op, err = o.networkSecurityOperation(ctx, fqname)
+		klog.V(5).Infof("NetworkSecurityGA.Operations.Get(%v, %v) = %+v, %v; ctx = %v", o.projectID, o.key.Name, op, err, ctx)
+	case o.key.Type() == meta.Global:
 		op, err = o.s.NetworkServicesGA.Operations.Get(fqname).Context(ctx).Do()
 		klog.V(5).Infof("GA.GlobalOperations.Get(%v, %v) = %+v, %v; ctx = %v", o.projectID, o.key.Name, op, err, ctx)
 	default:
@@ -64,6 +72,7 @@ func (o *networkServicesOperation) isDone(ctx context.Context) (bool, error) {
 	if op == nil || !op.Done {
 		return false, nil
 	}
+	o.done = true
 
 	if op.Error != nil {
 		o.err = &googleapi.Error{
@@ -74,6 +83,20 @@ func (o *networkServicesOperation) isDone(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// networkSecurityOperation gets the networksecurity operation fqname and
+// returns its status as a networkservices operation.
+func (o *networkServicesOperation) networkSecurityOperation(ctx context.Context, fqname string) (*networkservices.Operation, error) {
+	op, err := o.s.NetworkSecurityGA.Operations.Get(fqname).Context(ctx).Do()
+	if err != nil || op == nil {
+		return nil, err
+	}
+	ret := &networkservices.Operation{Name: op.Name, Done: op.Done}
+	if op.Error != nil {
+		ret.Error = &networkservices.Status{Code: op.Error.Code, Message: op.Error.Message}
+	}
+	return ret, nil
+}
+
 func (o *networkServicesOperation) rateLimitKey() *RateLimitKey {
 	return &RateLimitKey{
 		ProjectID: o.projectID,
@@ -87,6 +110,15 @@ func (o *networkServicesOperation) error() error {
 	return o.err
 }
 
+// progress of the operation. The Network Services operations do not report a
+// progress percent.
+func (o *networkServicesOperation) progress() (string, int) {
+	if o.done {
+		return operationStatusDone, 100
+	}
+	return "RUNNING", 0
+}
+
 type networkServiceOpURLParseResult struct {
 	projectID string
 	key       *meta.Key
@@ -1,5 +1,7 @@
 package cloud
 
+import "google.golang.org/api/googleapi"
+
 // Option are optional parameters to the generated methods.
 type Option interface {
 	mergeInto(all *allOptions)
@@ -8,6 +10,13 @@ type Option interface {
 // allOptions that can be configured for the generated methods.
 type allOptions struct {
 	projectID string
+	fields    []googleapi.Field
+	// partialSuccess for AggregatedList.
+	partialSuccess bool
+	userProject    string
+	// detachedURL receives the URL of the operation if the call must not
+	// wait for it.
+	detachedURL *string
 }
 
 // ForceProjectID forces the projectID to be used in the call to be the one
@@ -18,6 +27,23 @@ type projectIDOption string
 
 func (opt projectIDOption) mergeInto(all *allOptions) { all.projectID = string(opt) }
 
+// Fields sets the partial response fields to return from the API for Get,
+// List, ListPages, AggregatedList and ListUsable calls. This reduces the size
+// of the response for large resources. For the list calls, the fields apply
+// to the list response so they must include "nextPageToken" for pagination,
+// e.g.:
+//
+//	gce.UrlMaps().Get(ctx, key, Fields("name", "fingerprint"))
+//	gce.Instances().List(ctx, zone, filter.None, Fields("items(name,status)", "nextPageToken"))
+//
+// The Mock implementations ignore the Fields option. Fields are appended if
+// the option is given multiple times.
+func Fields(fields ...googleapi.Field) Option { return fieldsOption(fields) }
+
+type fieldsOption []googleapi.Field
+
+func (opt fieldsOption) mergeInto(all *allOptions) { all.fields = append(all.fields, opt...) }
+
 func mergeOptions(options []Option) allOptions {
 	var ret allOptions
 	for _, opt := range options {
@@ -25,3 +51,43 @@ func mergeOptions(options []Option) allOptions {
 	}
 	return ret
 }
+
+// PartialSuccess makes AggregatedList return the objects from the scopes
+// (zones, regions) that could be listed when other scopes fail, along with an
+// AggregatedListError for the failed scopes. Without this option, a single
+// failing scope may fail the entire call.
+func PartialSuccess() Option { return partialSuccessOption{} }
+
+type partialSuccessOption struct{}
+
+func (partialSuccessOption) mergeInto(all *allOptions) { all.partialSuccess = true }
+
+// UserProject sets the X-Goog-User-Project header of the call (and of the
+// polling of its operation) so the API usage is billed and counted against
+// the quota of the given project. This overrides Service.UserProject. The
+// header is only set on requests made with the HTTP client given to
+// NewService. The Mock implementations ignore the UserProject option.
+func UserProject(projectID string) Option { return userProjectOption(projectID) }
+
+type userProjectOption string
+
+func (opt userProjectOption) mergeInto(all *allOptions) { all.userProject = string(opt) }
+
+// DetachOperation makes the calls that start a long running operation (Insert,
+// Delete and the other mutations) return as soon as the operation is started
+// instead of waiting for its completion. The URL of the operation is stored
+// in url and the operation can be waited on later with
+// Service.ResumeOperation, e.g. after a restart of the controller:
+//
+//	var url string
+//	err := gce.BackendServices().Delete(ctx, key, DetachOperation(&url))
+//	// ... persist url ...
+//	err = svc.ResumeOperation(ctx, url)
+//
+// The Mock implementations ignore the DetachOperation option and complete the
+// call before returning.
+func DetachOperation(url *string) Option { return detachOption{url} }
+
+type detachOption struct{ url *string }
+
+func (opt detachOption) mergeInto(all *allOptions) { all.detachedURL = opt.url }
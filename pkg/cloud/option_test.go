@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/googleapi"
+)
+
+func TestMergeOptions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		options []Option
+		want    allOptions
+	}{
+		{name: "empty"},
+		{
+			name:    "projectID",
+			options: []Option{ForceProjectID("p1"), ForceProjectID("p2")},
+			want:    allOptions{projectID: "p2"},
+		},
+		{
+			name:    "fields",
+			options: []Option{Fields("name", "selfLink"), ForceProjectID("p"), Fields("fingerprint")},
+			want:    allOptions{projectID: "p", fields: []googleapi.Field{"name", "selfLink", "fingerprint"}},
+		},
+		{
+			name:    "partialSuccess",
+			options: []Option{PartialSuccess()},
+			want:    allOptions{partialSuccess: true},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := mergeOptions(tc.options)
+			if diff := cmp.Diff(got, tc.want, cmp.AllowUnexported(allOptions{})); diff != "" {
+				t.Errorf("mergeOptions() diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFieldsOption(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock   sync.Mutex
+		fields []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		fields = append(fields, r.URL.Query().Get("fields"))
+		lock.Unlock()
+		switch r.URL.Path {
+		case "/projects/proj/global/addresses/addr":
+			fmt.Fprint(w, `{"name": "addr"}`)
+		case "/projects/proj/global/addresses":
+			fmt.Fprint(w, `{"items": [{"name": "addr"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	g := NewGCE(s)
+	ctx := context.Background()
+
+	if _, err := g.GlobalAddresses().Get(ctx, meta.GlobalKey("addr"), Fields("name", "address")); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if _, err := g.GlobalAddresses().List(ctx, filter.None, Fields("items(name)", "nextPageToken")); err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+	if _, err := g.GlobalAddresses().Get(ctx, meta.GlobalKey("addr")); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+
+	want := []string{"name,address", "items(name),nextPageToken", ""}
+	if diff := cmp.Diff(fields, want); diff != "" {
+		t.Errorf("fields: diff -got,+want: %s", diff)
+	}
+}
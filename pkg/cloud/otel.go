@@ -0,0 +1,68 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+
+// Attribute keys for the OpenTelemetry spans.
+const (
+	AttrProject   = attribute.Key("cloud.project")
+	AttrResource  = attribute.Key("cloud.resource")
+	AttrVersion   = attribute.Key("cloud.version")
+	AttrOperation = attribute.Key("cloud.operation")
+	AttrOpURL     = attribute.Key("cloud.operation_url")
+)
+
+func (s *Service) tracer() trace.Tracer {
+	tp := s.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(instrumentationName)
+}
+
+// startCallSpan starts the span for the call identified by ck. The span is
+// named "cloud.<Service>.<Operation>", e.g. "cloud.Addresses.Get".
+func (s *Service) startCallSpan(ctx context.Context, ck *CallContextKey) (context.Context, trace.Span) {
+	return s.tracer().Start(ctx, "cloud."+ck.Service+"."+ck.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttrProject.String(ck.ProjectID),
+			AttrResource.String(ck.Service),
+			AttrVersion.String(string(ck.Version)),
+			AttrOperation.String(ck.Operation),
+		))
+}
+
+// recordSpanError records err (if non-nil) on the span in ctx.
+func recordSpanError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
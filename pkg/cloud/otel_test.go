@@ -0,0 +1,114 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/tracerec"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestGeneratedCallsTracing(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/proj/global/addresses/addr":
+			fmt.Fprint(w, `{"name": "addr"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/proj/global/addresses":
+			fmt.Fprint(w, `{"name": "op", "status": "RUNNING", "selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/proj/global/operations/op":
+			fmt.Fprint(w, `{"name": "op", "status": "DONE", "error": {"errors": [{"code": "X"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tp := tracerec.New()
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	s.TracerProvider = tp
+	g := NewGCE(s)
+	ctx := context.Background()
+
+	if _, err := g.GlobalAddresses().Get(ctx, meta.GlobalKey("addr")); err != nil {
+		t.Fatalf("Get() = %v, want nil", err)
+	}
+	if _, err := g.GlobalAddresses().Get(ctx, meta.GlobalKey("missing")); err == nil {
+		t.Fatalf("Get() = nil, want error")
+	}
+	if err := g.GlobalAddresses().Insert(ctx, meta.GlobalKey("addr"), &ga.Address{}); err == nil {
+		t.Fatalf("Insert() = nil, want error")
+	}
+
+	gets := tp.Find("cloud.GlobalAddresses.Get")
+	if len(gets) != 2 {
+		t.Fatalf("len(Find(cloud.GlobalAddresses.Get)) = %d, want 2", len(gets))
+	}
+	for _, tc := range []struct {
+		span       *tracerec.Span
+		wantStatus codes.Code
+	}{
+		{span: gets[0], wantStatus: codes.Unset},
+		{span: gets[1], wantStatus: codes.Error},
+	} {
+		if !tc.span.Ended() || tc.span.Status() != tc.wantStatus {
+			t.Errorf("span Ended() = %t, Status() = %v; want true, %v", tc.span.Ended(), tc.span.Status(), tc.wantStatus)
+		}
+		for k, want := range map[attribute.Key]string{
+			AttrProject:   "proj",
+			AttrResource:  "GlobalAddresses",
+			AttrVersion:   "ga",
+			AttrOperation: "Get",
+		} {
+			if v, ok := tc.span.Attr(k); !ok || v.AsString() != want {
+				t.Errorf("span Attr(%s) = %v, %t; want %q", k, v, ok, want)
+			}
+		}
+	}
+
+	inserts := tp.Find("cloud.GlobalAddresses.Insert")
+	if len(inserts) != 1 {
+		t.Fatalf("len(Find(cloud.GlobalAddresses.Insert)) = %d, want 1", len(inserts))
+	}
+	if inserts[0].Status() != codes.Error {
+		t.Errorf("Insert span Status() = %v, want %v", inserts[0].Status(), codes.Error)
+	}
+	// The polling of the operation is a child of the call.
+	waits := tp.Find("cloud.WaitForCompletion")
+	if len(waits) != 1 {
+		t.Fatalf("len(Find(cloud.WaitForCompletion)) = %d, want 1", len(waits))
+	}
+	if !waits[0].Parent().Equal(inserts[0].SpanContext()) {
+		t.Errorf("WaitForCompletion span Parent() = %v, want %v", waits[0].Parent(), inserts[0].SpanContext())
+	}
+	if waits[0].Status() != codes.Error || !waits[0].Ended() {
+		t.Errorf("WaitForCompletion span Status() = %v, Ended() = %t; want Error, true", waits[0].Status(), waits[0].Ended())
+	}
+}
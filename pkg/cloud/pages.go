@@ -0,0 +1,31 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import "errors"
+
+// ErrStopPages can be returned by the function passed to ListPages to stop
+// listing early. ListPages returns nil in this case.
+var ErrStopPages = errors.New("stop pages")
+
+// pagesError returns the error of ListPages for err.
+func pagesError(err error) error {
+	if errors.Is(err, ErrStopPages) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestListPages(t *testing.T) {
+	t.Parallel()
+
+	errInjected := errors.New("injected")
+
+	for _, tc := range []struct {
+		name         string
+		stopAt       int
+		errAt        int
+		wantPages    [][]string
+		wantRequests int32
+		wantErr      error
+	}{
+		{
+			name:         "all pages",
+			wantPages:    [][]string{{"a-0", "a-1"}, {"b-0", "b-1"}, {"c-0", "c-1"}},
+			wantRequests: 3,
+		},
+		{
+			name:         "stop early",
+			stopAt:       2,
+			wantPages:    [][]string{{"a-0", "a-1"}, {"b-0", "b-1"}},
+			wantRequests: 2,
+		},
+		{
+			name:         "error",
+			errAt:        1,
+			wantPages:    [][]string{{"a-0", "a-1"}},
+			wantRequests: 1,
+			wantErr:      errInjected,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if r.URL.Path != "/projects/proj/global/addresses" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				switch r.URL.Query().Get("pageToken") {
+				case "":
+					fmt.Fprint(w, `{"items": [{"name": "a-0"}, {"name": "a-1"}], "nextPageToken": "b"}`)
+				case "b":
+					fmt.Fprint(w, `{"items": [{"name": "b-0"}, {"name": "b-1"}], "nextPageToken": "c"}`)
+				default:
+					fmt.Fprint(w, `{"items": [{"name": "c-0"}, {"name": "c-1"}]}`)
+				}
+			}))
+			defer srv.Close()
+
+			s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+			if err != nil {
+				t.Fatalf("NewService() = %v", err)
+			}
+			s.GA.BasePath = srv.URL + "/"
+			g := NewGCE(s)
+
+			var pages [][]string
+			err = g.GlobalAddresses().ListPages(context.Background(), filter.None, func(l []*ga.Address) error {
+				var names []string
+				for _, o := range l {
+					names = append(names, o.Name)
+				}
+				pages = append(pages, names)
+				switch len(pages) {
+				case tc.stopAt:
+					return ErrStopPages
+				case tc.errAt:
+					return errInjected
+				}
+				return nil
+			})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("ListPages() = %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(pages, tc.wantPages); diff != "" {
+				t.Errorf("pages: diff -got,+want: %s", diff)
+			}
+			if got := requests.Load(); got != tc.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tc.wantRequests)
+			}
+		})
+	}
+}
+
+func TestMockListPages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj"})
+	for _, name := range []string{"a", "b"} {
+		if err := mock.Addresses().Insert(ctx, meta.RegionalKey(name, "us-central1"), &ga.Address{}); err != nil {
+			t.Fatalf("Insert(%s) = %v", name, err)
+		}
+	}
+
+	var n int
+	err := mock.Addresses().ListPages(ctx, "us-central1", filter.None, func(l []*ga.Address) error {
+		n += len(l)
+		return ErrStopPages
+	})
+	if err != nil || n != 2 {
+		t.Errorf("ListPages() = %v, %d items; want nil, 2 items", err, n)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MinimalPatch returns the body of a Patch call that changes current into
+// desired, e.g.:
+//
+//	patch, err := MinimalPatch(current, desired)
+//	...
+//	err = gce.HealthChecks().Patch(ctx, key, patch)
+//
+// The Patch methods of the API have JSON merge patch semantics: only the
+// fields that are set in the body are changed. The patch contains the top
+// level fields of desired that are different from current; fields that are
+// set in current and not in desired are cleared (see NullFields). Nested
+// objects and lists are replaced as a whole. The fingerprint of current is
+// sent with the patch so the Patch fails if the resource was changed
+// concurrently.
+//
+// T must be one of the resource types of the API, e.g. compute.HealthCheck.
+func MinimalPatch[T any](current, desired *T) (*T, error) {
+	cur, err := toJSONMap(current)
+	if err != nil {
+		return nil, fmt.Errorf("MinimalPatch: %w", err)
+	}
+	des, err := toJSONMap(desired)
+	if err != nil {
+		return nil, fmt.Errorf("MinimalPatch: %w", err)
+	}
+
+	patch := map[string]json.RawMessage{}
+	for k, v := range des {
+		if cv, ok := cur[k]; !ok || !jsonEqual(cv, v) {
+			patch[k] = v
+		}
+	}
+	if fp, ok := cur["fingerprint"]; ok {
+		patch["fingerprint"] = fp
+	}
+	var removed []string
+	for k := range cur {
+		if _, ok := des[k]; !ok && k != "fingerprint" {
+			removed = append(removed, k)
+		}
+	}
+
+	ret := new(T)
+	if err := copyViaJSON(ret, patch); err != nil {
+		return nil, fmt.Errorf("MinimalPatch: %w", err)
+	}
+	if err := setNullFields(ret, removed); err != nil {
+		return nil, fmt.Errorf("MinimalPatch: %w", err)
+	}
+	return ret, nil
+}
+
+// toJSONMap returns the top level fields of the JSON serialization of obj.
+func toJSONMap(obj any) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// setNullFields sets the NullFields of obj to the Go names of the fields with
+// the given JSON names. This makes the API client send them as null.
+func setNullFields(obj any, jsonNames []string) error {
+	if len(jsonNames) == 0 {
+		return nil
+	}
+	v := reflect.ValueOf(obj).Elem()
+	nf := v.FieldByName("NullFields")
+	if !nf.IsValid() || nf.Type() != reflect.TypeOf([]string{}) {
+		return fmt.Errorf("type %T has no NullFields", obj)
+	}
+	goNames := map[string]string{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			goNames[name] = f.Name
+		}
+	}
+	var fields []string
+	for _, n := range jsonNames {
+		if gn, ok := goNames[n]; ok {
+			fields = append(fields, gn)
+		}
+	}
+	sort.Strings(fields)
+	nf.Set(reflect.ValueOf(fields))
+	return nil
+}
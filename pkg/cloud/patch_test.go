@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestMinimalPatch(t *testing.T) {
+	t.Parallel()
+
+	current := &ga.UrlMap{
+		Name:           "um",
+		Description:    "old",
+		DefaultService: "bs1",
+		Fingerprint:    "abc",
+		HostRules:      []*ga.HostRule{{Hosts: []string{"a.com"}, PathMatcher: "pm"}},
+	}
+	desired := &ga.UrlMap{
+		Name:           "um",
+		DefaultService: "bs2",
+		HostRules:      []*ga.HostRule{{Hosts: []string{"a.com", "b.com"}, PathMatcher: "pm"}},
+	}
+	got, err := MinimalPatch(current, desired)
+	if err != nil {
+		t.Fatalf("MinimalPatch() = %v", err)
+	}
+	want := &ga.UrlMap{
+		DefaultService: "bs2",
+		Fingerprint:    "abc",
+		HostRules:      []*ga.HostRule{{Hosts: []string{"a.com", "b.com"}, PathMatcher: "pm"}},
+		NullFields:     []string{"Description"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("MinimalPatch() diff -got,+want: %s", diff)
+	}
+
+	// No changes.
+	got, err = MinimalPatch(current, current)
+	if err != nil {
+		t.Fatalf("MinimalPatch() = %v", err)
+	}
+	if diff := cmp.Diff(got, &ga.UrlMap{Fingerprint: "abc"}); diff != "" {
+		t.Errorf("MinimalPatch(current, current) diff -got,+want: %s", diff)
+	}
+}
+
+func TestPatchMock(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockGCE(&SingleProjectRouter{"proj"})
+	var patched *ga.HealthCheck
+	mock.MockHealthChecks.PatchHook = func(_ context.Context, _ *meta.Key, obj *ga.HealthCheck, _ *MockHealthChecks, _ ...Option) error {
+		patched = obj
+		return nil
+	}
+	obj := &ga.HealthCheck{CheckIntervalSec: 10}
+	if err := mock.HealthChecks().Patch(context.Background(), meta.GlobalKey("hc"), obj); err != nil {
+		t.Fatalf("Patch() = %v", err)
+	}
+	if patched != obj {
+		t.Errorf("PatchHook got %+v, want %+v", patched, obj)
+	}
+}
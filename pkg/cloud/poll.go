@@ -0,0 +1,71 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"math"
+	"time"
+)
+
+// OperationPollPolicy configures the delays between the polls of a long
+// running operation by WaitForCompletion. Set Service.OperationPollPolicy to
+// apply the policy to all of the operations. Longer delays use less quota at
+// the cost of noticing the completion of the operations later.
+//
+// The polls still go through the RateLimiter of the Service. Note that with
+// OperationsUseWait, each poll blocks on the server until the operation is
+// done or a server side timeout expires.
+type OperationPollPolicy struct {
+	// InitialDelay is the delay before the first poll.
+	InitialDelay time.Duration
+	// Interval is the delay after the first poll.
+	Interval time.Duration
+	// MaxInterval is the maximum delay between polls. If 0, the delay is
+	// not bounded.
+	MaxInterval time.Duration
+	// Multiplier is applied to the delay after each poll. Values < 1 are
+	// treated as 1.
+	Multiplier float64
+}
+
+// DefaultOperationPollPolicy returns an OperationPollPolicy with reasonable
+// defaults.
+func DefaultOperationPollPolicy() *OperationPollPolicy {
+	return &OperationPollPolicy{
+		InitialDelay: 0,
+		Interval:     1 * time.Second,
+		MaxInterval:  10 * time.Second,
+		Multiplier:   1.5,
+	}
+}
+
+// delay returns the delay before the next poll. pollCount is the number of
+// polls made so far.
+func (p *OperationPollPolicy) delay(pollCount int) time.Duration {
+	if p == nil {
+		return 0
+	}
+	if pollCount == 0 {
+		return p.InitialDelay
+	}
+	mult := math.Max(p.Multiplier, 1)
+	d := float64(p.Interval) * math.Pow(mult, float64(pollCount-1))
+	if p.MaxInterval > 0 {
+		d = math.Min(d, float64(p.MaxInterval))
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOperationPollPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	p := &OperationPollPolicy{
+		InitialDelay: 500 * time.Millisecond,
+		Interval:     time.Second,
+		MaxInterval:  5 * time.Second,
+		Multiplier:   2,
+	}
+	for _, tc := range []struct {
+		pollCount int
+		want      time.Duration
+	}{
+		{0, 500 * time.Millisecond},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	} {
+		if got := p.delay(tc.pollCount); got != tc.want {
+			t.Errorf("delay(%d) = %v, want %v", tc.pollCount, got, tc.want)
+		}
+	}
+
+	var nilPolicy *OperationPollPolicy
+	if got := nilPolicy.delay(3); got != 0 {
+		t.Errorf("nil.delay(3) = %v, want 0", got)
+	}
+	p = &OperationPollPolicy{Interval: time.Second, Multiplier: 0.5}
+	if got := p.delay(3); got != time.Second {
+		t.Errorf("delay(3) with Multiplier < 1 = %v, want %v", got, time.Second)
+	}
+}
+
+func TestPollOperationWithPolicy(t *testing.T) {
+	t.Parallel()
+
+	s := Service{
+		RateLimiter: &NopRateLimiter{},
+		OperationPollPolicy: &OperationPollPolicy{
+			InitialDelay: 10 * time.Millisecond,
+			Interval:     10 * time.Millisecond,
+			Multiplier:   1,
+		},
+	}
+	start := time.Now()
+	if err := s.pollOperation(context.Background(), &fakeOperation{attemptsRemaining: 3}, ""); err != nil {
+		t.Fatalf("pollOperation() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("pollOperation() took %v, want >= 30ms for 3 polls", elapsed)
+	}
+
+	// The delay is interrupted when the context is done.
+	s.OperationPollPolicy = &OperationPollPolicy{InitialDelay: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	op := &fakeOperation{attemptsRemaining: 1}
+	if err := s.pollOperation(ctx, op, ""); err != context.DeadlineExceeded {
+		t.Errorf("pollOperation() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if op.attemptsRemaining != 1 {
+		t.Errorf("op was polled %d times, want 0", 1-op.attemptsRemaining)
+	}
+}
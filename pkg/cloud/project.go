@@ -18,6 +18,8 @@ package cloud
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
@@ -44,9 +46,119 @@ func (r *SingleProjectRouter) ProjectID(ctx context.Context, version meta.Versio
 	return r.ID
 }
 
-func getProjectID(ctx context.Context, pr ProjectRouter, opt allOptions, version meta.Version, service string) string {
+// KeyedProjectRouter is a ProjectRouter that also routes by the key of the
+// resource, e.g. to route the calls for the subnetworks of a shared VPC to the
+// host project. The generated code calls ProjectIDForKey instead of ProjectID
+// when the ProjectRouter implements KeyedProjectRouter.
+type KeyedProjectRouter interface {
+	ProjectRouter
+	// ProjectIDForKey returns the project ID (non-numeric) to be used for a
+	// call to an API (version,service) for the resource identified by key.
+	// key is nil for calls that are not for a single resource (e.g. List
+	// and AggregatedList).
+	ProjectIDForKey(ctx context.Context, version meta.Version, service string, key *meta.Key) string
+}
+
+// ProjectRouteFunc returns the project ID for a call to an API
+// (version,service) for the resource identified by key (nil if the call is
+// not for a single resource).
+type ProjectRouteFunc func(ctx context.Context, version meta.Version, service string, key *meta.Key) string
+
+// DynamicProjectRouter routes the calls with a ProjectRouteFunc. The routes
+// are cached by (version, service, key) for the TTL given to
+// NewDynamicProjectRouter.
+//
+// # Example
+//
+//	pr := NewDynamicProjectRouter(func(ctx context.Context, version meta.Version, service string, key *meta.Key) string {
+//		if service == "Subnetworks" {
+//			return hostProject
+//		}
+//		return serviceProject
+//	}, 10*time.Minute)
+//
+// DynamicProjectRouter is safe for concurrent use.
+type DynamicProjectRouter struct {
+	route ProjectRouteFunc
+	ttl   time.Duration
+
+	lock  sync.Mutex
+	cache map[projectRouteKey]projectRouteEntry
+}
+
+type projectRouteKey struct {
+	version meta.Version
+	service string
+	hasKey  bool
+	key     meta.Key
+}
+
+type projectRouteEntry struct {
+	projectID string
+	expires   time.Time
+}
+
+// DynamicProjectRouter implements KeyedProjectRouter.
+var _ KeyedProjectRouter = (*DynamicProjectRouter)(nil)
+
+// NewDynamicProjectRouter returns a DynamicProjectRouter that routes the calls
+// with route. The result of route is cached for ttl. A ttl <= 0 means the
+// routes are cached until Invalidate is called.
+func NewDynamicProjectRouter(route ProjectRouteFunc, ttl time.Duration) *DynamicProjectRouter {
+	return &DynamicProjectRouter{
+		route: route,
+		ttl:   ttl,
+		cache: map[projectRouteKey]projectRouteEntry{},
+	}
+}
+
+// ProjectID implements ProjectRouter.
+func (r *DynamicProjectRouter) ProjectID(ctx context.Context, version meta.Version, service string) string {
+	return r.ProjectIDForKey(ctx, version, service, nil)
+}
+
+// ProjectIDForKey implements KeyedProjectRouter.
+func (r *DynamicProjectRouter) ProjectIDForKey(ctx context.Context, version meta.Version, service string, key *meta.Key) string {
+	rk := projectRouteKey{version: version, service: service}
+	if key != nil {
+		rk.hasKey = true
+		rk.key = *key
+	}
+	now := time.Now()
+
+	r.lock.Lock()
+	e, ok := r.cache[rk]
+	r.lock.Unlock()
+	if ok && (e.expires.IsZero() || now.Before(e.expires)) {
+		return e.projectID
+	}
+
+	// route is called without the lock held as it may be slow (e.g. read
+	// the configuration of the shared VPC).
+	e = projectRouteEntry{projectID: r.route(ctx, version, service, key)}
+	if r.ttl > 0 {
+		e.expires = now.Add(r.ttl)
+	}
+	r.lock.Lock()
+	r.cache[rk] = e
+	r.lock.Unlock()
+
+	return e.projectID
+}
+
+// Invalidate removes all of the cached routes.
+func (r *DynamicProjectRouter) Invalidate() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cache = map[projectRouteKey]projectRouteEntry{}
+}
+
+func getProjectID(ctx context.Context, pr ProjectRouter, opt allOptions, version meta.Version, service string, key *meta.Key) string {
 	if opt.projectID != "" {
 		return opt.projectID
 	}
+	if kpr, ok := pr.(KeyedProjectRouter); ok {
+		return kpr.ProjectIDForKey(ctx, version, service, key)
+	}
 	return pr.ProjectID(ctx, version, service)
 }
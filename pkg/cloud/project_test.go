@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestDynamicProjectRouter(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	pr := NewDynamicProjectRouter(func(ctx context.Context, version meta.Version, service string, key *meta.Key) string {
+		calls++
+		if service == "subnetworks" && key != nil && key.Region == "us-central1" {
+			return "host-project"
+		}
+		return "service-project"
+	}, 0)
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		service string
+		key     *meta.Key
+		want    string
+	}{
+		{"subnetworks", meta.RegionalKey("s", "us-central1"), "host-project"},
+		{"subnetworks", meta.RegionalKey("s", "europe-west1"), "service-project"},
+		{"subnetworks", nil, "service-project"},
+		{"addresses", meta.RegionalKey("s", "us-central1"), "service-project"},
+	} {
+		if got := pr.ProjectIDForKey(ctx, meta.VersionGA, tc.service, tc.key); got != tc.want {
+			t.Errorf("ProjectIDForKey(_, ga, %q, %v) = %q, want %q", tc.service, tc.key, got, tc.want)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
+	}
+
+	// Cached.
+	pr.ProjectIDForKey(ctx, meta.VersionGA, "subnetworks", meta.RegionalKey("s", "us-central1"))
+	pr.ProjectID(ctx, meta.VersionGA, "subnetworks")
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4 (cached)", calls)
+	}
+	pr.Invalidate()
+	pr.ProjectID(ctx, meta.VersionGA, "subnetworks")
+	if calls != 5 {
+		t.Errorf("calls = %d, want 5 after Invalidate()", calls)
+	}
+
+	pr = NewDynamicProjectRouter(func(context.Context, meta.Version, string, *meta.Key) string {
+		calls++
+		return "p"
+	}, time.Millisecond)
+	pr.ProjectID(ctx, meta.VersionGA, "addresses")
+	time.Sleep(10 * time.Millisecond)
+	pr.ProjectID(ctx, meta.VersionGA, "addresses")
+	if calls != 7 {
+		t.Errorf("calls = %d, want 7 after the TTL", calls)
+	}
+}
+
+func TestKeyedProjectRouterMock(t *testing.T) {
+	t.Parallel()
+
+	pr := NewDynamicProjectRouter(func(ctx context.Context, version meta.Version, service string, key *meta.Key) string {
+		if key != nil && key.Name == "shared" {
+			return "host-project"
+		}
+		return "service-project"
+	}, 0)
+	mock := NewMockGCE(pr)
+	ctx := context.Background()
+
+	for _, name := range []string{"shared", "local"} {
+		key := meta.RegionalKey(name, "us-central1")
+		if err := mock.Subnetworks().Insert(ctx, key, &ga.Subnetwork{}); err != nil {
+			t.Fatalf("Insert(%v) = %v", key, err)
+		}
+	}
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"shared", "/projects/host-project/"},
+		{"local", "/projects/service-project/"},
+	} {
+		obj, err := mock.Subnetworks().Get(ctx, meta.RegionalKey(tc.name, "us-central1"))
+		if err != nil {
+			t.Fatalf("Get(%q) = %v", tc.name, err)
+		}
+		if !strings.Contains(obj.SelfLink, tc.want) {
+			t.Errorf("SelfLink = %q, want to contain %q", obj.SelfLink, tc.want)
+		}
+	}
+}
@@ -18,7 +18,15 @@ package cloud
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/api/googleapi"
 )
 
 // RateLimitKey is a key identifying the operation to be rate limited. The rate limit
@@ -94,13 +102,28 @@ type MinimumRateLimiter struct {
 	// RateLimiter is the underlying ratelimiter which is called after the mininum time is reacehd.
 	RateLimiter RateLimiter
 	// Minimum is the minimum wait time before the underlying ratelimiter is called.
+	// Use SetMinimum to change it once the rate limiter is in use.
 	Minimum time.Duration
+
+	lock sync.Mutex
+}
+
+// SetMinimum changes the minimum wait time. It is safe to call while Accept
+// is being called concurrently; calls already waiting are not affected.
+func (m *MinimumRateLimiter) SetMinimum(minimum time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.Minimum = minimum
 }
 
 // Accept blocks on the minimum duration and context. Once the minimum duration is met,
 // the func is blocked on the underlying ratelimiter.
 func (m *MinimumRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
-	t := time.NewTimer(m.Minimum)
+	m.lock.Lock()
+	minimum := m.Minimum
+	m.lock.Unlock()
+
+	t := time.NewTimer(minimum)
 	select {
 	case <-t.C:
 		return m.RateLimiter.Accept(ctx, key)
@@ -134,6 +157,12 @@ func NewTickerRateLimiter(limit int, interval time.Duration) *TickerRateLimiter
 	}
 }
 
+// SetLimit changes the rate of the TickerRateLimiter to limit calls per
+// interval. It is safe to call while Accept is being called concurrently.
+func (t *TickerRateLimiter) SetLimit(limit int, interval time.Duration) {
+	t.ticker.Reset(interval / time.Duration(limit))
+}
+
 // Accept will block until a time, specified when creating TickerRateLimiter,
 // passes since the last call to Accept.
 func (t *TickerRateLimiter) Accept(ctx context.Context, rlk *RateLimitKey) error {
@@ -152,17 +181,63 @@ func (*TickerRateLimiter) Observe(context.Context, error, *RateLimitKey) {
 // Make sure that TickerRateLimiter implements RateLimiter.
 var _ RateLimiter = new(TickerRateLimiter)
 
+// TokenBucketRateLimiter allows calls at a sustained rate with bursts of up
+// to a given number of calls (see golang.org/x/time/rate). Unlike
+// TickerRateLimiter, a short burst of calls after an idle period is not
+// spaced out.
+//
+// TokenBucketRateLimiter does not distinguish between keys; use
+// CompositeRateLimiter to have separate limits per service or operation.
+type TokenBucketRateLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewTokenBucketRateLimiter creates a TokenBucketRateLimiter allowing qps
+// calls per second on average with bursts of up to burst calls.
+//
+// For example, qps=5 burst=20 allows 20 calls at once after an idle period
+// of 4 seconds, then 5 calls per second.
+func NewTokenBucketRateLimiter(qps float64, burst int) *TokenBucketRateLimiter {
+	return &TokenBucketRateLimiter{limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+}
+
+// SetLimit changes the average rate and the burst size of the
+// TokenBucketRateLimiter. It is safe to call while Accept is being called
+// concurrently.
+func (rl *TokenBucketRateLimiter) SetLimit(qps float64, burst int) {
+	now := time.Now()
+	rl.limiter.SetLimitAt(now, rate.Limit(qps))
+	rl.limiter.SetBurstAt(now, burst)
+}
+
+// Accept blocks until a token is available or the context is done.
+func (rl *TokenBucketRateLimiter) Accept(ctx context.Context, _ *RateLimitKey) error {
+	return rl.limiter.Wait(ctx)
+}
+
+// Observe does nothing.
+func (*TokenBucketRateLimiter) Observe(context.Context, error, *RateLimitKey) {
+}
+
+// Make sure that TokenBucketRateLimiter implements RateLimiter.
+var _ RateLimiter = new(TokenBucketRateLimiter)
+
 // CompositeRateLimiter combines rate limiters based on RateLimitKey.
 type CompositeRateLimiter struct {
+	// lock guards the maps below so that rate limiters can be registered
+	// while the CompositeRateLimiter is in use.
+	lock sync.RWMutex
 	// map[resource name]map[operation name]RateLimiter
 	rateLimiters map[string]map[string]RateLimiter
+	// projects contains rate limiters registered for a specific project.
+	// map[project]map[resource name]map[operation name]RateLimiter
+	projects map[string]map[string]map[string]RateLimiter
 	// defaultRL is used when no matching RateLimiter was found.
 	defaultRL RateLimiter
 }
 
 // NewCompositeRateLimiter creates a new CompositeRateLimiter that will use
-// provided default rate limiter if no better match is found. It is intended to
-// be used for a single project.
+// provided default rate limiter if no better match is found.
 //
 // # Example
 //
@@ -179,6 +254,15 @@ type CompositeRateLimiter struct {
 // limiter registered at ("BackendServices", "") won't be applied to operation
 // ("BackendServices", "Get"), because a more specific rate limiter was
 // registered.
+//
+// Rate limiters registered with Register are shared by all projects. Use
+// RegisterProject to give a project its own rate limiters.
+//
+// Register and RegisterProject can be called at any time, including while
+// the CompositeRateLimiter is used by a Cloud. This allows limits to be
+// changed at runtime by registering a new rate limiter in place of the old
+// one, or by changing the existing rate limiter (e.g.
+// TokenBucketRateLimiter.SetLimit).
 func NewCompositeRateLimiter(defaultRL RateLimiter) *CompositeRateLimiter {
 	m := map[string]map[string]RateLimiter{
 		"": {
@@ -187,6 +271,7 @@ func NewCompositeRateLimiter(defaultRL RateLimiter) *CompositeRateLimiter {
 	}
 	return &CompositeRateLimiter{
 		rateLimiters: m,
+		projects:     map[string]map[string]map[string]RateLimiter{},
 		defaultRL:    defaultRL,
 	}
 }
@@ -214,32 +299,358 @@ func (c *CompositeRateLimiter) fillMissing() {
 // particular resource, or operation.
 //
 // It replaces previous rate limiter provided for the same service, operation
-// combination. Once a rate limiter is added, it can't be removed.
+// combination. Calls already waiting in the previous rate limiter are not
+// affected. Once a rate limiter is added, it can't be removed.
 //
 // Same rate limiter can be used for multiple Register calls.
 func (c *CompositeRateLimiter) Register(service, operation string, rl RateLimiter) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	c.ensureExists(service)
 	c.rateLimiters[service][operation] = rl
 	c.fillMissing()
 }
 
+// RegisterProject adds provided rl to the composite rate limiter for calls
+// to the given project (RateLimitKey.ProjectID). Service and operation are
+// matched in the same way as for Register.
+//
+// Rate limiters registered for a project take precedence over those added
+// with Register. If none of the rate limiters registered for the project
+// match a call, the call falls back to the rate limiters shared by all
+// projects.
+//
+// To keep one project from starving another, register a separate rate
+// limiter instance for each project:
+//
+//	for _, p := range projects {
+//		rl.RegisterProject(p, "", "", NewTokenBucketRateLimiter(10, 20))
+//	}
+func (c *CompositeRateLimiter) RegisterProject(project, service, operation string, rl RateLimiter) {
+	if project == "" {
+		c.Register(service, operation, rl)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.projects[project]; !ok {
+		c.projects[project] = map[string]map[string]RateLimiter{}
+	}
+	if _, ok := c.projects[project][service]; !ok {
+		c.projects[project][service] = map[string]RateLimiter{}
+	}
+	c.projects[project][service][operation] = rl
+}
+
+// matchRateLimiter returns the rate limiter in m matching service and
+// operation or nil if there is none.
+func matchRateLimiter(m map[string]map[string]RateLimiter, service, operation string) RateLimiter {
+	if _, ok := m[service]; !ok {
+		service = ""
+	}
+	if _, ok := m[service][operation]; !ok {
+		operation = ""
+	}
+	return m[service][operation]
+}
+
+// rateLimiter returns the rate limiter matching rlk.
+func (c *CompositeRateLimiter) rateLimiter(rlk *RateLimitKey) RateLimiter {
+	if rlk == nil {
+		return c.defaultRL
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if m, ok := c.projects[rlk.ProjectID]; ok {
+		if rl := matchRateLimiter(m, rlk.Service, rlk.Operation); rl != nil {
+			return rl
+		}
+	}
+	return matchRateLimiter(c.rateLimiters, rlk.Service, rlk.Operation)
+}
+
 // Accept either calls underlying rate limiter matching rlk or a default rate
 // limiter when none is found.
 func (c *CompositeRateLimiter) Accept(ctx context.Context, rlk *RateLimitKey) error {
-	if rlk == nil {
-		return c.defaultRL.Accept(ctx, rlk)
+	return c.rateLimiter(rlk).Accept(ctx, rlk)
+}
+
+// Observe passes the result to the same rate limiter that was used by
+// Accept.
+func (c *CompositeRateLimiter) Observe(ctx context.Context, err error, rlk *RateLimitKey) {
+	c.rateLimiter(rlk).Observe(ctx, err, rlk)
+}
+
+// RateLimitLane is a class of calls that is given its own budget by
+// LaneRateLimiter.
+type RateLimitLane int
+
+const (
+	// LaneRead is for calls that do not modify resources, e.g. Get and
+	// List.
+	LaneRead RateLimitLane = iota
+	// LaneWrite is for calls that modify resources, e.g. Insert, Delete and
+	// custom methods such as SetTarget.
+	LaneWrite
+	// LaneOperationPoll is for polls of long running operations started by
+	// write calls.
+	LaneOperationPoll
+)
+
+// String implements fmt.Stringer.
+func (l RateLimitLane) String() string {
+	switch l {
+	case LaneRead:
+		return "read"
+	case LaneWrite:
+		return "write"
+	case LaneOperationPoll:
+		return "operationPoll"
 	}
-	service := rlk.Service
-	if _, ok := c.rateLimiters[service]; !ok {
-		service = ""
+	return fmt.Sprintf("RateLimitLane(%d)", int(l))
+}
+
+// LaneForKey returns the lane the call identified by key belongs to.
+// Operations starting with "Get", "List" or "AggregatedList" are reads;
+// everything else is a write. A nil key is treated as a write.
+func LaneForKey(key *RateLimitKey) RateLimitLane {
+	if key == nil {
+		return LaneWrite
 	}
-	operation := rlk.Operation
-	if _, ok := c.rateLimiters[service][operation]; !ok {
-		operation = ""
+	if key.Service == "Operations" {
+		return LaneOperationPoll
+	}
+	for _, prefix := range []string{"Get", "List", "AggregatedList"} {
+		if strings.HasPrefix(key.Operation, prefix) {
+			return LaneRead
+		}
 	}
-	return c.rateLimiters[service][operation].Accept(ctx, rlk)
+	return LaneWrite
 }
 
-// Observe does nothing.
-func (*CompositeRateLimiter) Observe(context.Context, error, *RateLimitKey) {
+// LaneRateLimiter gives reads, writes and operation polls separate rate
+// limiters so that a heavy read loop does not delay writes (or the other
+// way around). A nil RateLimiter for a lane means that calls in the lane are
+// not rate limited.
+//
+// # Example
+//
+//	rl := &LaneRateLimiter{
+//		Read:          NewTokenBucketRateLimiter(20, 50),
+//		Write:         NewTokenBucketRateLimiter(5, 10),
+//		OperationPoll: NewTokenBucketRateLimiter(10, 10),
+//	}
+//
+// Lanes can be combined with other rate limiters, e.g. a
+// CompositeRateLimiter per lane or per project.
+type LaneRateLimiter struct {
+	// Read is used for calls in LaneRead.
+	Read RateLimiter
+	// Write is used for calls in LaneWrite.
+	Write RateLimiter
+	// OperationPoll is used for calls in LaneOperationPoll.
+	OperationPoll RateLimiter
+	// Classify assigns calls to lanes. If nil, LaneForKey is used.
+	Classify func(key *RateLimitKey) RateLimitLane
+}
+
+// rateLimiter returns the rate limiter for the lane of key.
+func (rl *LaneRateLimiter) rateLimiter(key *RateLimitKey) RateLimiter {
+	classify := rl.Classify
+	if classify == nil {
+		classify = LaneForKey
+	}
+	var r RateLimiter
+	switch classify(key) {
+	case LaneRead:
+		r = rl.Read
+	case LaneWrite:
+		r = rl.Write
+	case LaneOperationPoll:
+		r = rl.OperationPoll
+	}
+	if r == nil {
+		return &NopRateLimiter{}
+	}
+	return r
+}
+
+// Accept calls the rate limiter of the lane of key.
+func (rl *LaneRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	return rl.rateLimiter(key).Accept(ctx, key)
+}
+
+// Observe passes the result to the rate limiter of the lane of key.
+func (rl *LaneRateLimiter) Observe(ctx context.Context, err error, key *RateLimitKey) {
+	rl.rateLimiter(key).Observe(ctx, err, key)
+}
+
+// Make sure that LaneRateLimiter implements RateLimiter.
+var _ RateLimiter = new(LaneRateLimiter)
+
+// InstrumentedRateLimiter wraps a RateLimiter and reports how long each
+// Accept blocked. This shows when calls are limited by the client side
+// quota rather than by the latency of the API:
+//
+//	svc.RateLimiter = &InstrumentedRateLimiter{
+//		RateLimiter: rl,
+//		ObserveWait: func(_ context.Context, key *RateLimitKey, wait time.Duration, _ error) {
+//			waitHistogram.WithLabelValues(key.Service, key.Operation).Observe(wait.Seconds())
+//		},
+//	}
+type InstrumentedRateLimiter struct {
+	// RateLimiter is the underlying rate limiter.
+	RateLimiter RateLimiter
+	// ObserveWait is called after each Accept of the underlying rate
+	// limiter with the time Accept blocked and the error it returned. key
+	// may be nil. ObserveWait must be safe for concurrent use.
+	ObserveWait func(ctx context.Context, key *RateLimitKey, wait time.Duration, err error)
+}
+
+// Accept calls the underlying rate limiter and reports the time spent
+// waiting to ObserveWait.
+func (rl *InstrumentedRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	start := time.Now()
+	err := rl.RateLimiter.Accept(ctx, key)
+	if rl.ObserveWait != nil {
+		rl.ObserveWait(ctx, key, time.Since(start), err)
+	}
+	return err
+}
+
+// Observe passes the result to the underlying rate limiter.
+func (rl *InstrumentedRateLimiter) Observe(ctx context.Context, err error, key *RateLimitKey) {
+	rl.RateLimiter.Observe(ctx, err, key)
+}
+
+// Make sure that InstrumentedRateLimiter implements RateLimiter.
+var _ RateLimiter = new(InstrumentedRateLimiter)
+
+// AdaptiveRateLimiter spaces calls by an interval that adapts to feedback
+// from the API. The interval is increased (multiplicatively) when Observe()
+// sees a rate limit or quota error (see IsRateLimitError) and is gradually
+// decreased after successful calls until it reaches MinInterval.
+//
+// AdaptiveRateLimiter does not distinguish between keys; use
+// CompositeRateLimiter to have separate limits per service or operation.
+type AdaptiveRateLimiter struct {
+	// MinInterval is the interval between calls when there is no throttling
+	// by the API. 0 means no limit.
+	MinInterval time.Duration
+	// MaxInterval is the maximum interval between calls.
+	MaxInterval time.Duration
+	// InitialBackoff is the interval used when a rate limit error is seen
+	// and the current interval is smaller.
+	InitialBackoff time.Duration
+	// BackoffFactor multiplies the interval on each rate limit error.
+	BackoffFactor float64
+	// RecoveryFactor multiplies the interval on each successful call. This
+	// should be in the range (0, 1); larger values recover more slowly.
+	RecoveryFactor float64
+
+	lock     sync.Mutex
+	interval time.Duration
+	// next is the earliest time the next call can be accepted.
+	next time.Time
+}
+
+// NewAdaptiveRateLimiter creates an AdaptiveRateLimiter with the given
+// bounds for the interval between calls and the default backoff and recovery
+// factors.
+func NewAdaptiveRateLimiter(minInterval, maxInterval time.Duration) *AdaptiveRateLimiter {
+	initial := minInterval
+	if initial < 100*time.Millisecond {
+		initial = 100 * time.Millisecond
+	}
+	return &AdaptiveRateLimiter{
+		MinInterval:    minInterval,
+		MaxInterval:    maxInterval,
+		InitialBackoff: initial,
+		BackoffFactor:  2,
+		RecoveryFactor: 0.9,
+		interval:       minInterval,
+	}
+}
+
+// Accept blocks until the current interval has passed since the last
+// accepted call or the context is done.
+func (rl *AdaptiveRateLimiter) Accept(ctx context.Context, _ *RateLimitKey) error {
+	rl.lock.Lock()
+	now := time.Now()
+	start := rl.next
+	if start.Before(now) {
+		start = now
+	}
+	rl.next = start.Add(rl.interval)
+	rl.lock.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
+}
+
+// Observe adjusts the interval based on the result of the call.
+func (rl *AdaptiveRateLimiter) Observe(_ context.Context, err error, _ *RateLimitKey) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	switch {
+	case IsRateLimitError(err):
+		next := time.Duration(float64(rl.interval) * rl.BackoffFactor)
+		if next < rl.InitialBackoff {
+			next = rl.InitialBackoff
+		}
+		if rl.MaxInterval > 0 && next > rl.MaxInterval {
+			next = rl.MaxInterval
+		}
+		rl.interval = next
+	case err == nil:
+		next := time.Duration(float64(rl.interval) * rl.RecoveryFactor)
+		// Snap to the minimum once the interval is close enough, otherwise
+		// the interval will only approach it asymptotically.
+		if next < rl.MinInterval || next-rl.MinInterval < time.Millisecond {
+			next = rl.MinInterval
+		}
+		rl.interval = next
+	}
+}
+
+// Interval returns the current interval between calls.
+func (rl *AdaptiveRateLimiter) Interval() time.Duration {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	return rl.interval
+}
+
+// IsRateLimitError returns true if err is an error from the API indicating
+// that a rate limit or quota was exceeded.
+func IsRateLimitError(err error) bool {
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return false
+	}
+	if gerr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	for _, item := range gerr.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+			return true
+		}
+	}
+	return false
 }
@@ -18,8 +18,12 @@ package cloud
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
+
+	"google.golang.org/api/googleapi"
 )
 
 type FakeAcceptor struct{ accept func() }
@@ -103,6 +107,72 @@ func TestTickerRateLimiter(t *testing.T) {
 	}
 }
 
+func TestTokenBucketRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	rl := NewTokenBucketRateLimiter(10, 5)
+	ctx := context.Background()
+	start := time.Now()
+	// The burst is accepted immediately.
+	for i := 0; i < 5; i++ {
+		if err := rl.Accept(ctx, nil); err != nil {
+			t.Fatalf("Accept() = %v, want nil", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst of 5 Accept() took %v, want immediate", elapsed)
+	}
+	// The next call waits for a token (100ms at 10 qps).
+	if err := rl.Accept(ctx, nil); err != nil {
+		t.Fatalf("Accept() = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Accept() after the burst returned after %v, want >= 50ms", elapsed)
+	}
+
+	ctxCancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rl.Accept(ctxCancelled, nil); err == nil {
+		t.Errorf("Accept(cancelled) = nil, want error")
+	}
+}
+
+func TestTokenBucketRateLimiterSetLimit(t *testing.T) {
+	t.Parallel()
+
+	// Start with a limit that would block for a long time after the burst.
+	rl := NewTokenBucketRateLimiter(0.001, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := rl.Accept(ctx, nil); err != nil {
+		t.Fatalf("Accept() = %v, want nil", err)
+	}
+
+	rl.SetLimit(1000, 10)
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if err := rl.Accept(ctx, nil); err != nil {
+			t.Fatalf("Accept() = %v, want nil", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("10 Accept() after SetLimit(1000, 10) took %v, want < 1s", elapsed)
+	}
+}
+
+func TestMinimumRateLimiterSetMinimum(t *testing.T) {
+	t.Parallel()
+
+	m := &MinimumRateLimiter{RateLimiter: &NopRateLimiter{}, Minimum: time.Hour}
+	m.SetMinimum(time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.Accept(ctx, nil); err != nil {
+		t.Errorf("MinimumRateLimiter.Accept() = %v, want nil", err)
+	}
+}
+
 func TestCompositeRateLimiter(t *testing.T) {
 	t.Parallel()
 
@@ -218,3 +288,298 @@ func TestCompositeRateLimiter_Table(t *testing.T) {
 		t.Errorf("getNetRL served %d calls, want = 3", *getNetRL)
 	}
 }
+
+func TestCompositeRateLimiterProject(t *testing.T) {
+	t.Parallel()
+
+	def := new(CountingRateLimiter)
+	rl := NewCompositeRateLimiter(def)
+	defNetRL := new(CountingRateLimiter)
+	rl.Register("networks", "", defNetRL)
+	projARL := new(CountingRateLimiter)
+	rl.RegisterProject("projectA", "", "", projARL)
+	projBGetNetRL := new(CountingRateLimiter)
+	rl.RegisterProject("projectB", "networks", "get", projBGetNetRL)
+
+	for _, tc := range []struct {
+		key  *CallContextKey
+		want *CountingRateLimiter
+	}{
+		{key: &CallContextKey{ProjectID: "projectA", Service: "networks", Operation: "get"}, want: projARL},
+		{key: &CallContextKey{ProjectID: "projectA", Service: "firewalls"}, want: projARL},
+		{key: &CallContextKey{ProjectID: "projectB", Service: "networks", Operation: "get"}, want: projBGetNetRL},
+		{key: &CallContextKey{ProjectID: "projectB", Service: "networks", Operation: "list"}, want: defNetRL},
+		{key: &CallContextKey{ProjectID: "projectB", Service: "firewalls", Operation: "get"}, want: def},
+		{key: &CallContextKey{ProjectID: "projectC", Service: "networks", Operation: "get"}, want: defNetRL},
+		{key: &CallContextKey{ProjectID: "projectC"}, want: def},
+	} {
+		before := *tc.want
+		if err := rl.Accept(context.Background(), tc.key); err != nil {
+			t.Errorf("CompositeRateLimiter.Accept(%+v) = %v, want nil", tc.key, err)
+		}
+		if *tc.want != before+1 {
+			t.Errorf("CompositeRateLimiter.Accept(%+v) did not use the expected rate limiter", tc.key)
+		}
+	}
+}
+
+func TestCompositeRateLimiterConcurrentRegister(t *testing.T) {
+	t.Parallel()
+
+	rl := NewCompositeRateLimiter(&NopRateLimiter{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			rl.Register("networks", "get", &NopRateLimiter{})
+			rl.RegisterProject("projectA", "networks", "", &NopRateLimiter{})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		key := &CallContextKey{ProjectID: "projectA", Service: "networks", Operation: "get"}
+		if err := rl.Accept(context.Background(), key); err != nil {
+			t.Errorf("CompositeRateLimiter.Accept() = %v, want nil", err)
+		}
+	}
+	<-done
+
+	// A rate limiter registered later replaces the previous one.
+	counting := new(CountingRateLimiter)
+	rl.RegisterProject("projectA", "networks", "", counting)
+	rl.Accept(context.Background(), &CallContextKey{ProjectID: "projectA", Service: "networks"})
+	if *counting != 1 {
+		t.Errorf("replaced rate limiter served %d calls, want 1", *counting)
+	}
+}
+
+// observedRateLimiter records the errors passed to Observe.
+type observedRateLimiter struct {
+	NopRateLimiter
+	observed []error
+}
+
+func (rl *observedRateLimiter) Observe(_ context.Context, err error, _ *RateLimitKey) {
+	rl.observed = append(rl.observed, err)
+}
+
+func TestCompositeRateLimiterObserve(t *testing.T) {
+	t.Parallel()
+
+	def := &observedRateLimiter{}
+	net := &observedRateLimiter{}
+	rl := NewCompositeRateLimiter(def)
+	rl.Register("networks", "", net)
+
+	errA, errB, errC := errors.New("a"), errors.New("b"), errors.New("c")
+	rl.Observe(context.Background(), errA, &CallContextKey{Service: "networks", Operation: "get"})
+	rl.Observe(context.Background(), errB, &CallContextKey{Service: "firewalls"})
+	rl.Observe(context.Background(), errC, nil)
+
+	if len(net.observed) != 1 || net.observed[0] != errA {
+		t.Errorf("networks observed %v, want [%v]", net.observed, errA)
+	}
+	if len(def.observed) != 2 || def.observed[0] != errB || def.observed[1] != errC {
+		t.Errorf("default observed %v, want [%v %v]", def.observed, errB, errC)
+	}
+}
+
+func TestLaneForKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		key  *CallContextKey
+		want RateLimitLane
+	}{
+		{key: nil, want: LaneWrite},
+		{key: &CallContextKey{Service: "Firewalls", Operation: "Get"}, want: LaneRead},
+		{key: &CallContextKey{Service: "Firewalls", Operation: "List"}, want: LaneRead},
+		{key: &CallContextKey{Service: "Addresses", Operation: "AggregatedList"}, want: LaneRead},
+		{key: &CallContextKey{Service: "BackendServices", Operation: "GetHealth"}, want: LaneRead},
+		{key: &CallContextKey{Service: "Firewalls", Operation: "Insert"}, want: LaneWrite},
+		{key: &CallContextKey{Service: "TargetHttpProxies", Operation: "SetUrlMap"}, want: LaneWrite},
+		{key: &CallContextKey{Service: "Operations", Operation: "Get"}, want: LaneOperationPoll},
+	} {
+		if got := LaneForKey(tc.key); got != tc.want {
+			t.Errorf("LaneForKey(%+v) = %v, want %v", tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestLaneRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	read := &observedRateLimiter{}
+	write := new(CountingRateLimiter)
+	rl := &LaneRateLimiter{Read: read, Write: write}
+
+	ctx := context.Background()
+	for _, key := range []*CallContextKey{
+		{Service: "Firewalls", Operation: "Insert"},
+		{Service: "Firewalls", Operation: "Delete"},
+		{Service: "Firewalls", Operation: "Get"},
+		// No rate limiter for LaneOperationPoll.
+		{Service: "Operations", Operation: "Get"},
+	} {
+		if err := rl.Accept(ctx, key); err != nil {
+			t.Errorf("LaneRateLimiter.Accept(%+v) = %v, want nil", key, err)
+		}
+	}
+	if *write != 2 {
+		t.Errorf("write served %d calls, want 2", *write)
+	}
+
+	errA := errors.New("a")
+	rl.Observe(ctx, errA, &CallContextKey{Service: "Firewalls", Operation: "List"})
+	if len(read.observed) != 1 || read.observed[0] != errA {
+		t.Errorf("read observed %v, want [%v]", read.observed, errA)
+	}
+
+	// Classify overrides the default lanes.
+	rl.Classify = func(*RateLimitKey) RateLimitLane { return LaneWrite }
+	rl.Accept(ctx, &CallContextKey{Service: "Firewalls", Operation: "Get"})
+	if *write != 3 {
+		t.Errorf("write served %d calls, want 3", *write)
+	}
+}
+
+func TestInstrumentedRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	type waitRecord struct {
+		key  *RateLimitKey
+		wait time.Duration
+		err  error
+	}
+	var got []waitRecord
+	rl := &InstrumentedRateLimiter{
+		RateLimiter: &MinimumRateLimiter{RateLimiter: &NopRateLimiter{}, Minimum: 20 * time.Millisecond},
+		ObserveWait: func(_ context.Context, key *RateLimitKey, wait time.Duration, err error) {
+			got = append(got, waitRecord{key: key, wait: wait, err: err})
+		},
+	}
+
+	key := &CallContextKey{Service: "Firewalls", Operation: "Get"}
+	if err := rl.Accept(context.Background(), key); err != nil {
+		t.Errorf("InstrumentedRateLimiter.Accept() = %v, want nil", err)
+	}
+	ctxCancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rl.Accept(ctxCancelled, nil); err == nil {
+		t.Errorf("InstrumentedRateLimiter.Accept(cancelled) = nil, want error")
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("ObserveWait called %d times, want 2", len(got))
+	}
+	if got[0].key != key || got[0].wait < 20*time.Millisecond || got[0].err != nil {
+		t.Errorf("got[0] = %+v, want key %+v, wait >= 20ms, nil error", got[0], key)
+	}
+	if got[1].key != nil || got[1].err == nil {
+		t.Errorf("got[1] = %+v, want nil key and an error", got[1])
+	}
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil"},
+		{name: "other", err: errors.New("x")},
+		{name: "429", err: &googleapi.Error{Code: 429}, want: true},
+		{name: "404", err: &googleapi.Error{Code: 404}},
+		{
+			name: "403 rateLimitExceeded",
+			err:  &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}},
+			want: true,
+		},
+		{
+			name: "403 quotaExceeded",
+			err:  fmt.Errorf("wrapped: %w", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}),
+			want: true,
+		},
+		{
+			name: "403 forbidden",
+			err:  &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}},
+		},
+	} {
+		if got := IsRateLimitError(tc.err); got != tc.want {
+			t.Errorf("%s: IsRateLimitError(%v) = %t, want %t", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestAdaptiveRateLimiterObserve(t *testing.T) {
+	t.Parallel()
+
+	rl := NewAdaptiveRateLimiter(10*time.Millisecond, 100*time.Millisecond)
+	rl.InitialBackoff = 20 * time.Millisecond
+	rl.RecoveryFactor = 0.5
+
+	ctx := context.Background()
+	errRateLimit := &googleapi.Error{Code: 429}
+	for _, tc := range []struct {
+		err  error
+		want time.Duration
+	}{
+		{err: nil, want: 10 * time.Millisecond},
+		{err: errRateLimit, want: 20 * time.Millisecond},
+		{err: errRateLimit, want: 40 * time.Millisecond},
+		{err: errRateLimit, want: 80 * time.Millisecond},
+		{err: errRateLimit, want: 100 * time.Millisecond},
+		// Other errors do not change the interval.
+		{err: errors.New("other"), want: 100 * time.Millisecond},
+		{err: nil, want: 50 * time.Millisecond},
+		{err: nil, want: 25 * time.Millisecond},
+		{err: nil, want: 12500 * time.Microsecond},
+		{err: nil, want: 10 * time.Millisecond},
+	} {
+		rl.Observe(ctx, tc.err, nil)
+		if got := rl.Interval(); got != tc.want {
+			t.Errorf("Observe(%v); Interval() = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestAdaptiveRateLimiterAccept(t *testing.T) {
+	t.Parallel()
+
+	rl := NewAdaptiveRateLimiter(0, time.Second)
+	ctx := context.Background()
+
+	// No throttling.
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if err := rl.Accept(ctx, nil); err != nil {
+			t.Fatalf("Accept() = %v, want nil", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("10 Accept() took %v without throttling, want < 50ms", elapsed)
+	}
+
+	// Throttled: calls are spaced by the interval.
+	rl.InitialBackoff = 20 * time.Millisecond
+	rl.Observe(ctx, &googleapi.Error{Code: 429}, nil)
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		if err := rl.Accept(ctx, nil); err != nil {
+			t.Fatalf("Accept() = %v, want nil", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("3 Accept() took %v with a 20ms interval, want >= 40ms", elapsed)
+	}
+
+	// Canceled context.
+	rl.Observe(ctx, &googleapi.Error{Code: 429}, nil)
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	rl.Accept(ctx, nil)
+	if err := rl.Accept(cctx, nil); err != context.Canceled {
+		t.Errorf("Accept() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReadCache caches the results of the read calls (e.g. Get, List and
+// AggregatedList) made with a Service for a TTL. Set Service.ReadCache to
+// use it with all of the calls made by the Cloud created with NewGCE:
+//
+//	s.ReadCache = NewReadCache(10 * time.Second)
+//	g := NewGCE(s)
+//
+// Results are cached per request URL, i.e. per project, API version,
+// resource key and List filter. Every call returns a new copy of the
+// result, so callers are free to modify the objects returned.
+//
+// A call that modifies a resource (e.g. Insert, Delete, Patch or a custom
+// method such as SetTarget) made with the same Service invalidates the
+// cached results for all of the resources of the same type in the project,
+// whether the call succeeds or not. Changes made by other clients, and side
+// effects on other resource types (e.g. the Users of an Address), are only
+// seen once the TTL has expired.
+//
+// Operations are never cached, so waiting for operations is not affected.
+type ReadCache struct {
+	ttl time.Duration
+
+	lock    sync.Mutex
+	entries map[string]*readCacheEntry
+	// generations is incremented for a scope each time it is invalidated.
+	// This prevents a read that was in flight during the invalidation from
+	// adding a stale entry.
+	generations map[string]uint64
+	lastSweep   time.Time
+}
+
+type readCacheEntry struct {
+	scope      string
+	expires    time.Time
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// NewReadCache returns an empty ReadCache that keeps results for ttl.
+func NewReadCache(ttl time.Duration) *ReadCache {
+	return &ReadCache{
+		ttl:         ttl,
+		entries:     map[string]*readCacheEntry{},
+		generations: map[string]uint64{},
+		lastSweep:   time.Now(),
+	}
+}
+
+// Invalidate removes all of the cached results.
+func (c *ReadCache) Invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key, e := range c.entries {
+		c.generations[e.scope]++
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of cached results, including the results that have
+// expired but were not removed yet.
+func (c *ReadCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries)
+}
+
+// readCacheScope returns the scope used to invalidate the results for the
+// request path, "<project>/<resource type>", or false if the results for
+// path must not be cached.
+//
+// For example, the scope of
+// "/compute/v1/projects/p/regions/us-central1/addresses/a" and
+// "/compute/v1/projects/p/aggregated/addresses" is "p/addresses".
+func readCacheScope(path string) (string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	i := 0
+	for i < len(parts) && parts[i] != "projects" {
+		i++
+	}
+	if i+1 >= len(parts) {
+		return "", false
+	}
+	project, rest := parts[i+1], parts[i+2:]
+
+	var resource string
+	switch {
+	case len(rest) == 0:
+		// The project itself.
+	case rest[0] == "global" || rest[0] == "aggregated":
+		if len(rest) > 1 {
+			resource = rest[1]
+		}
+	case rest[0] == "regions" || rest[0] == "zones" || rest[0] == "locations":
+		if len(rest) > 2 {
+			resource = rest[2]
+		} else {
+			resource = rest[0]
+		}
+	default:
+		resource = rest[0]
+	}
+	if resource == "operations" {
+		return "", false
+	}
+	return project + "/" + resource, true
+}
+
+// roundTrip serves req from the cache or with next.
+func (c *ReadCache) roundTrip(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	scope, ok := readCacheScope(req.URL.Path)
+	if !ok {
+		return next(req)
+	}
+	if req.Method != http.MethodGet {
+		// Invalidate before the call, so that concurrent reads do not use
+		// the cache while the resource is changing, and after the call
+		// for the reads that were sent before the change was done.
+		c.invalidate(scope)
+		resp, err := next(req)
+		c.invalidate(scope)
+		return resp, err
+	}
+
+	key := req.URL.String()
+	now := time.Now()
+	c.lock.Lock()
+	e, ok := c.entries[key]
+	if ok && now.After(e.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	generation := c.generations[scope]
+	c.lock.Unlock()
+	if ok {
+		return e.response(req), nil
+	}
+
+	resp, err := next(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	e = &readCacheEntry{
+		scope:      scope,
+		expires:    now.Add(c.ttl),
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+	}
+	c.add(key, e, generation)
+
+	return e.response(req), nil
+}
+
+// add adds e to the cache unless its scope was invalidated after
+// generation.
+func (c *ReadCache) add(key string, e *readCacheEntry, generation uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.generations[e.scope] != generation {
+		return
+	}
+	c.entries[key] = e
+
+	// Remove the expired entries from time to time so that results that
+	// are not read again do not accumulate.
+	if now := time.Now(); now.Sub(c.lastSweep) > c.ttl {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// invalidate removes the cached results in scope.
+func (c *ReadCache) invalidate(scope string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.generations[scope]++
+	for key, e := range c.entries {
+		if e.scope == scope {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// response returns a new http.Response for req with the cached result.
+func (e *readCacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(e.statusCode),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestReadCacheScope(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"/compute/v1/projects/p/global/addresses/a", "p/addresses", true},
+		{"/compute/v1/projects/p/regions/us-central1/addresses/a", "p/addresses", true},
+		{"/compute/v1/projects/p/regions/us-central1/addresses", "p/addresses", true},
+		{"/compute/v1/projects/p/aggregated/addresses", "p/addresses", true},
+		{"/compute/v1/projects/p/zones/us-central1-b/instances/i/setTags", "p/instances", true},
+		{"/compute/v1/projects/p/regions/us-central1", "p/regions", true},
+		{"/compute/v1/projects/p/regions", "p/regions", true},
+		{"/compute/v1/projects/p", "p/", true},
+		{"/v1/projects/p/locations/global/tcpRoutes/r", "p/tcpRoutes", true},
+		{"/compute/v1/projects/p/global/operations/op", "", false},
+		{"/compute/v1/projects/p/zones/us-central1-b/operations/op", "", false},
+		{"/v1/projects/p/locations/global/operations/op", "", false},
+		{"/compute/v1/other", "", false},
+	} {
+		got, ok := readCacheScope(tc.path)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("readCacheScope(%q) = %q, %t; want %q, %t", tc.path, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestReadCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock  sync.Mutex
+		calls []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		lock.Unlock()
+		switch {
+		case r.URL.Path == "/projects/proj/global/addresses/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": 404}}`)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"name": "addr", "description": "d"}`)
+		default:
+			fmt.Fprintf(w, `{"name": "op", "status": "DONE", "selfLink": "http://%s/projects/proj/global/operations/op"}`, r.Host)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	s.ReadCache = NewReadCache(time.Hour)
+	g := NewGCE(s)
+	ctx := context.Background()
+	key := meta.GlobalKey("addr")
+
+	// The second Get is served from the cache and returns a new copy.
+	addr, err := g.GlobalAddresses().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	addr.Description = "modified"
+	addr, err = g.GlobalAddresses().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if addr.Description != "d" {
+		t.Errorf("Get().Description = %q, want %q", addr.Description, "d")
+	}
+	// Errors are not cached.
+	g.GlobalAddresses().Get(ctx, meta.GlobalKey("missing"))
+	g.GlobalAddresses().Get(ctx, meta.GlobalKey("missing"))
+	// Other resource types are cached separately.
+	g.BackendServices().Get(ctx, meta.GlobalKey("addr"))
+	g.BackendServices().Get(ctx, meta.GlobalKey("addr"))
+	// A mutation invalidates the results for the resource type.
+	g.GlobalAddresses().Insert(ctx, meta.GlobalKey("addr2"), &ga.Address{})
+	g.GlobalAddresses().Get(ctx, key)
+	g.BackendServices().Get(ctx, meta.GlobalKey("addr"))
+	// Invalidate removes all of the results.
+	s.ReadCache.Invalidate()
+	g.GlobalAddresses().Get(ctx, key)
+
+	want := []string{
+		"GET /projects/proj/global/addresses/addr",
+		"GET /projects/proj/global/addresses/missing",
+		"GET /projects/proj/global/addresses/missing",
+		"GET /projects/proj/global/backendServices/addr",
+		"POST /projects/proj/global/addresses",
+		"POST /projects/proj/global/operations/op/wait",
+		"GET /projects/proj/global/addresses/addr",
+		"GET /projects/proj/global/addresses/addr",
+	}
+	if diff := cmp.Diff(calls, want); diff != "" {
+		t.Errorf("calls: diff -got,+want: %s", diff)
+	}
+}
+
+func TestReadCacheExpiry(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"items": [{"name": "addr"}]}`)
+	}))
+	defer srv.Close()
+
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	s.ReadCache = NewReadCache(50 * time.Millisecond)
+	g := NewGCE(s)
+	ctx := context.Background()
+
+	g.GlobalAddresses().List(ctx, filter.None)
+	g.GlobalAddresses().List(ctx, filter.None)
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	time.Sleep(100 * time.Millisecond)
+	g.GlobalAddresses().List(ctx, filter.None)
+	if calls != 2 {
+		t.Errorf("calls = %d after the TTL, want 2", calls)
+	}
+	if got := s.ReadCache.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RegionalEndpoints routes the requests to the compute API for regional and
+// zonal resources to the endpoint of the region of the resource, e.g. to
+// reduce latency or to meet data residency requirements. Set
+// Service.RegionalEndpoints to route the requests made with the HTTP client
+// given to NewService.
+//
+// The region is taken from the path of the request
+// (".../projects/<proj>/regions/<region>/..." or
+// ".../projects/<proj>/zones/<zone>/..."), so the polling of regional and
+// zonal operations is routed as well. Requests for global resources and
+// aggregated lists are sent to the endpoint of the Service.
+//
+// # Example
+//
+//	s.RegionalEndpoints = &RegionalEndpoints{
+//		Host:    "{region}-compute.googleapis.com",
+//		Regions: []string{"us-central1", "europe-west1"},
+//	}
+type RegionalEndpoints struct {
+	// Host of the regional endpoints, "{region}" is replaced by the region
+	// of the resource.
+	Host string
+	// Regions that are routed to their regional endpoint. If empty, all
+	// regions are routed.
+	Regions []string
+}
+
+// regionalPathRegexp matches the location of a regional or zonal resource in
+// a compute API path.
+var regionalPathRegexp = regexp.MustCompile(`/compute/[^/]+/projects/[^/]+/(regions|zones)/([^/]+)`)
+
+// regionFromPath returns the region of the resource in the compute API path
+// or "" if the resource is not regional or zonal.
+func regionFromPath(path string) string {
+	m := regionalPathRegexp.FindStringSubmatch(path)
+	if m == nil {
+		return ""
+	}
+	if m[1] == "regions" {
+		return m[2]
+	}
+	// The region of a zone is the zone name without the last part, e.g.
+	// "us-central1-b" is in "us-central1".
+	i := strings.LastIndex(m[2], "-")
+	if i <= 0 {
+		return ""
+	}
+	return m[2][:i]
+}
+
+// host returns the regional host for the region or "" if the region is not
+// routed.
+func (e *RegionalEndpoints) host(region string) string {
+	if region == "" || e.Host == "" {
+		return ""
+	}
+	if len(e.Regions) > 0 {
+		found := false
+		for _, r := range e.Regions {
+			if r == region {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ""
+		}
+	}
+	return strings.ReplaceAll(e.Host, "{region}", region)
+}
+
+// route returns req sent to its regional endpoint or req if it is not routed.
+// req is not modified.
+func (e *RegionalEndpoints) route(req *http.Request) *http.Request {
+	host := e.host(regionFromPath(req.URL.Path))
+	if host == "" || host == req.URL.Host {
+		return req
+	}
+	req = req.Clone(req.Context())
+	req.URL.Host = host
+	req.Host = host
+	return req
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegionFromPath(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{"/compute/v1/projects/p/regions/us-central1/addresses/a", "us-central1"},
+		{"/compute/beta/projects/p/zones/us-central1-b/instances/i", "us-central1"},
+		{"/compute/v1/projects/p/regions/us-central1", "us-central1"},
+		{"/compute/v1/projects/p/global/addresses/a", ""},
+		{"/compute/v1/projects/p/aggregated/addresses", ""},
+		{"/v1/projects/p/locations/global/tcpRoutes/r", ""},
+		{"/compute/v1/projects/p/zones/invalid/instances/i", ""},
+	} {
+		if got := regionFromPath(tc.path); got != tc.want {
+			t.Errorf("regionFromPath(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+type hostRecorder struct {
+	lock  sync.Mutex
+	hosts []string
+}
+
+func (r *hostRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.lock.Lock()
+	r.hosts = append(r.hosts, req.URL.Host)
+	r.lock.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRegionalEndpoints(t *testing.T) {
+	t.Parallel()
+
+	rec := &hostRecorder{}
+	ctx := context.Background()
+	s, err := NewService(ctx, &http.Client{Transport: rec}, &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.RegionalEndpoints = &RegionalEndpoints{
+		Host:    "{region}-compute.googleapis.com",
+		Regions: []string{"us-central1"},
+	}
+	g := NewGCE(s)
+
+	g.RegionBackendServices().Get(ctx, meta.RegionalKey("bs", "us-central1"))
+	g.Instances().Get(ctx, meta.ZonalKey("i", "us-central1-b"))
+	g.RegionBackendServices().Get(ctx, meta.RegionalKey("bs", "europe-west1"))
+	g.BackendServices().Get(ctx, meta.GlobalKey("bs"))
+	g.Addresses().AggregatedList(ctx, filter.None)
+
+	want := []string{
+		"us-central1-compute.googleapis.com",
+		"us-central1-compute.googleapis.com",
+		"compute.googleapis.com",
+		"compute.googleapis.com",
+		"compute.googleapis.com",
+	}
+	if diff := cmp.Diff(rec.hosts, want); diff != "" {
+		t.Errorf("hosts: diff -got,+want: %s", diff)
+	}
+}
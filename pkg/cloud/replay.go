@@ -0,0 +1,296 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Recording is a sequence of HTTP interactions with the API recorded by
+// RecordTransport and replayed by ReplayTransport. This can be used to turn
+// an e2e scenario run against the real API into a hermetic regression test:
+//
+//	// Record (e2e).
+//	rt := &RecordTransport{Transport: client.Transport}
+//	client.Transport = rt
+//	... run the scenario with NewService(ctx, client, ...) ...
+//	rt.Recording().Save("testdata/scenario.json")
+//
+//	// Replay (unit test).
+//	rec, err := LoadRecording("testdata/scenario.json")
+//	client := &http.Client{Transport: NewReplayTransport(rec)}
+//	... run the scenario with NewService(ctx, client, ...) ...
+//
+// The recorded requests and responses do not contain credentials (see
+// RequestSummary) nor the values of the JSON fields in sensitiveFields
+// (e.g. the private keys of SslCertificates).
+type Recording struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded HTTP request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded HTTP request.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded HTTP response.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// LoadRecording reads a Recording saved with Recording.Save.
+func LoadRecording(path string) (*Recording, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadRecording: %w", err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("LoadRecording %s: %w", path, err)
+	}
+	return &rec, nil
+}
+
+// Save the Recording to path as JSON.
+func (r *Recording) Save(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Recording.Save: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("Recording.Save: %w", err)
+	}
+	return nil
+}
+
+// sensitiveFields are JSON fields with secrets in the API objects. Their
+// values are replaced with redactedValue in the recorded bodies.
+var sensitiveFields = []string{
+	"privateKey",
+}
+
+const redactedValue = "REDACTED"
+
+// scrubBody replaces the values of the sensitiveFields in a JSON body. Bodies
+// that are not JSON objects are returned unchanged.
+func scrubBody(body []byte) string {
+	var v map[string]any
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return string(body)
+	}
+	if !scrubValue(v) {
+		return string(body)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(b)
+}
+
+// scrubValue scrubs v in place. It returns true if v was changed.
+func scrubValue(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, fv := range v {
+			for _, f := range sensitiveFields {
+				if k == f {
+					v[k] = redactedValue
+					changed = true
+				}
+			}
+			if scrubValue(fv) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if scrubValue(e) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// readBody reads and replaces *body so it can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(b))
+	return b, err
+}
+
+// RecordTransport is an http.RoundTripper that records the interactions made
+// with Transport. Requests that fail without a response are not recorded.
+type RecordTransport struct {
+	// Transport makes the requests. http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+	// Scrub, if set, is called on each Interaction before it is recorded,
+	// e.g. to replace the project ID.
+	Scrub func(*Interaction)
+
+	lock sync.Mutex
+	rec  Recording
+}
+
+// RecordTransport implements http.RoundTripper.
+var _ http.RoundTripper = (*RecordTransport)(nil)
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    sanitizeURL(req.URL),
+			Header: sanitizeHeader(req.Header),
+			Body:   scrubBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     sanitizeHeader(resp.Header),
+			Body:       scrubBody(respBody),
+		},
+	}
+	if t.Scrub != nil {
+		t.Scrub(in)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.rec.Interactions = append(t.rec.Interactions, in)
+
+	return resp, nil
+}
+
+// Recording returns a copy of the interactions recorded so far.
+func (t *RecordTransport) Recording() *Recording {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return &Recording{Interactions: append([]*Interaction(nil), t.rec.Interactions...)}
+}
+
+// ReplayTransport is an http.RoundTripper that replays a Recording. Each
+// request is answered with the response of the first interaction that was
+// not replayed yet with the same method, URL and body (after scrubbing), so
+// repeated requests (e.g. the polling of an operation) are replayed in the
+// order they were recorded. Requests that do not match an interaction fail.
+type ReplayTransport struct {
+	lock     sync.Mutex
+	rec      *Recording
+	replayed []bool
+}
+
+// ReplayTransport implements http.RoundTripper.
+var _ http.RoundTripper = (*ReplayTransport)(nil)
+
+// NewReplayTransport returns a ReplayTransport for rec.
+func NewReplayTransport(rec *Recording) *ReplayTransport {
+	return &ReplayTransport{
+		rec:      rec,
+		replayed: make([]bool, len(rec.Interactions)),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rb := req.Body
+	reqBody, err := readBody(&rb)
+	if err != nil {
+		return nil, err
+	}
+	url := sanitizeURL(req.URL)
+	body := scrubBody(reqBody)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for i, in := range t.rec.Interactions {
+		if t.replayed[i] || in.Request.Method != req.Method || in.Request.URL != url || in.Request.Body != body {
+			continue
+		}
+		t.replayed[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("ReplayTransport: no recorded interaction for %s %s", req.Method, url)
+}
+
+// Unreplayed returns the interactions that have not been replayed. This can
+// be used to check that a test made all of the recorded requests.
+func (t *ReplayTransport) Unreplayed() []*Interaction {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var ret []*Interaction
+	for i, in := range t.rec.Interactions {
+		if !t.replayed[i] {
+			ret = append(ret, in)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestRecordTransportScrubs(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+		fmt.Fprint(w, `{"name": "cert", "privateKey": "secret", "certificate": "cert"}`)
+	}))
+	defer srv.Close()
+
+	rt := &RecordTransport{Transport: srv.Client().Transport}
+	client := &http.Client{Transport: rt}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/cert?key=secret&alt=json", strings.NewReader(`{"privateKey": "secret"}`))
+	if err != nil {
+		t.Fatalf("NewRequest() = %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Goog-Api-Key", "secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `"privateKey": "secret"`) {
+		t.Errorf("response body = %q, want the unscrubbed body", body)
+	}
+
+	rec := rt.Recording()
+	if len(rec.Interactions) != 1 {
+		t.Fatalf("len(Interactions) = %d, want 1", len(rec.Interactions))
+	}
+	in := rec.Interactions[0]
+	for _, s := range []string{
+		in.Request.URL,
+		in.Request.Body,
+		in.Response.Body,
+		fmt.Sprint(in.Request.Header),
+		fmt.Sprint(in.Response.Header),
+	} {
+		if strings.Contains(s, "secret") {
+			t.Errorf("recorded %q contains a secret", s)
+		}
+	}
+	if !strings.Contains(in.Response.Body, `"certificate":"cert"`) {
+		t.Errorf("Response.Body = %q, want the certificate", in.Response.Body)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	var opPolls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/proj/global/addresses":
+			fmt.Fprint(w, `{"name": "op", "status": "RUNNING", "selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/proj/global/operations/op/wait":
+			opPolls++
+			status := "RUNNING"
+			if opPolls > 1 {
+				status = "DONE"
+			}
+			fmt.Fprintf(w, `{"name": "op", "status": %q}`, status)
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/proj/global/addresses/addr":
+			fmt.Fprint(w, `{"name": "addr", "address": "1.2.3.4"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	basePath := srv.URL + "/"
+
+	scenario := func(client *http.Client) (*ga.Address, error) {
+		ctx := context.Background()
+		s, err := NewService(ctx, client, &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+		if err != nil {
+			return nil, err
+		}
+		s.GA.BasePath = basePath
+		g := NewGCE(s)
+		key := meta.GlobalKey("addr")
+		if err := g.GlobalAddresses().Insert(ctx, key, &ga.Address{Name: "addr"}); err != nil {
+			return nil, err
+		}
+		return g.GlobalAddresses().Get(ctx, key)
+	}
+
+	rt := &RecordTransport{Transport: srv.Client().Transport}
+	want, err := scenario(&http.Client{Transport: rt})
+	if err != nil {
+		t.Fatalf("scenario() = %v (record)", err)
+	}
+	srv.Close()
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+	if err := rt.Recording().Save(path); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	rec, err := LoadRecording(path)
+	if err != nil {
+		t.Fatalf("LoadRecording() = %v", err)
+	}
+	if len(rec.Interactions) != 4 {
+		t.Errorf("len(Interactions) = %d, want 4", len(rec.Interactions))
+	}
+
+	replay := NewReplayTransport(rec)
+	got, err := scenario(&http.Client{Transport: replay})
+	if err != nil {
+		t.Fatalf("scenario() = %v (replay)", err)
+	}
+	if got.Address != want.Address {
+		t.Errorf("Address = %q, want %q", got.Address, want.Address)
+	}
+	if u := replay.Unreplayed(); len(u) != 0 {
+		t.Errorf("Unreplayed() = %v, want none", u)
+	}
+
+	// The interactions are replayed once.
+	if _, err := scenario(&http.Client{Transport: replay}); err == nil {
+		t.Errorf("scenario() = nil, want error (recording exhausted)")
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"k8s.io/klog/v2"
+)
+
+// RetryPolicy configures the retry of failed calls to the API. Set
+// Service.RetryPolicy to enable retries for all of the calls made by the
+// generated code. Each attempt goes through the RateLimiter (Accept() and
+// Observe()) so retries are rate limited like any other call.
+//
+// Retries stop when the context is done or if the context deadline would
+// expire before the next attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// call. Values < 1 are treated as 1 (no retries).
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between attempts.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the delay after each attempt. Values < 1 are
+	// treated as 1.
+	Multiplier float64
+	// Jitter randomizes each delay by +/- Jitter * delay. Jitter should be
+	// in the range [0, 1].
+	Jitter float64
+	// ShouldRetry returns true if the call identified by key should be
+	// retried after err. If nil, DefaultShouldRetry is used.
+	ShouldRetry func(key *CallContextKey, err error) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with reasonable defaults.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 1 * time.Second,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// DefaultShouldRetry retries errors that are likely to be transient:
+//
+//   - HTTP 429 (Too Many Requests) is retried for all operations as the
+//     request was rejected by the server.
+//   - HTTP 5xx and connection resets are only retried for read-only
+//     operations (Get*, List*, Aggregated*). A mutation may have been
+//     applied by the server before the error, so it is not safe to retry
+//     these blindly.
+func DefaultShouldRetry(key *CallContextKey, err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		switch {
+		case gerr.Code == http.StatusTooManyRequests:
+			return true
+		case gerr.Code >= 500 && gerr.Code <= 599:
+			return isReadOnlyOperation(key)
+		}
+		return false
+	}
+	return isConnectionReset(err) && isReadOnlyOperation(key)
+}
+
+// isReadOnlyOperation returns true if the operation does not modify
+// resources.
+func isReadOnlyOperation(key *CallContextKey) bool {
+	if key == nil {
+		return false
+	}
+	for _, prefix := range []string{"Get", "List", "Aggregated"} {
+		if strings.HasPrefix(key.Operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isConnectionReset(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// Some transports do not wrap the syscall error.
+	return err != nil && strings.Contains(err.Error(), "connection reset by peer")
+}
+
+func (p *RetryPolicy) shouldRetry(key *CallContextKey, err error) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(key, err)
+	}
+	return DefaultShouldRetry(key, err)
+}
+
+// backoff returns the delay before the next attempt. attempt is the number of
+// attempts made so far (starting at 1).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	mult := math.Max(p.Multiplier, 1)
+	d := float64(p.InitialBackoff) * math.Pow(mult, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		d = math.Min(d, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// retry calls f until it succeeds or the RetryPolicy gives up. The error from
+// the last attempt is returned.
+func (s *Service) retry(ctx context.Context, ck *CallContextKey, f func() error) error {
+	p := s.RetryPolicy
+	if p == nil {
+		return f()
+	}
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= p.MaxAttempts || !p.shouldRetry(ck, err) {
+			return err
+		}
+		d := p.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+			klog.V(4).Infof("retry(%v, %+v): not retrying after attempt %d, deadline would expire: %v", ctx, ck, attempt, err)
+			return err
+		}
+		klog.V(4).Infof("retry(%v, %+v): attempt %d failed, retrying in %v: %v", ctx, ck, attempt, d, err)
+		s.RateLimiter.Observe(ctx, err, ck)
+
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		if aerr := s.RateLimiter.Accept(ctx, ck); aerr != nil {
+			klog.V(4).Infof("retry(%v, %+v): RateLimiter error: %v", ctx, ck, aerr)
+			return err
+		}
+	}
+}
+
+// callWithRetry calls do (the Do() method of an API call) with the Service
+// RetryPolicy.
+func callWithRetry[T any](ctx context.Context, s *Service, ck *CallContextKey, do func(...googleapi.CallOption) (T, error)) (T, error) {
+	var ret T
+	err := s.retry(ctx, ck, func() error {
+		var err error
+		ret, err = do()
+		return err
+	})
+	return ret, err
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+)
+
+// recordingRateLimiter records the calls to Accept and Observe.
+type recordingRateLimiter struct {
+	lock     sync.Mutex
+	accepts  int
+	observed []error
+}
+
+func (rl *recordingRateLimiter) Accept(context.Context, *RateLimitKey) error {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.accepts++
+	return nil
+}
+
+func (rl *recordingRateLimiter) Observe(_ context.Context, err error, _ *RateLimitKey) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.observed = append(rl.observed, err)
+}
+
+func TestDefaultShouldRetry(t *testing.T) {
+	t.Parallel()
+
+	get := &CallContextKey{Operation: "Get"}
+	aggList := &CallContextKey{Operation: "AggregatedList"}
+	insert := &CallContextKey{Operation: "Insert"}
+
+	for _, tc := range []struct {
+		name string
+		key  *CallContextKey
+		err  error
+		want bool
+	}{
+		{name: "429 Get", key: get, err: &googleapi.Error{Code: 429}, want: true},
+		{name: "429 Insert", key: insert, err: &googleapi.Error{Code: 429}, want: true},
+		{name: "503 Get", key: get, err: &googleapi.Error{Code: 503}, want: true},
+		{name: "500 AggregatedList", key: aggList, err: &googleapi.Error{Code: 500}, want: true},
+		{name: "503 Insert", key: insert, err: &googleapi.Error{Code: 503}},
+		{name: "404 Get", key: get, err: &googleapi.Error{Code: 404}},
+		{name: "wrapped 429", key: get, err: fmt.Errorf("x: %w", &googleapi.Error{Code: 429}), want: true},
+		{name: "connection reset Get", key: get, err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "connection reset string", key: get, err: errors.New("read tcp: connection reset by peer"), want: true},
+		{name: "connection reset Insert", key: insert, err: syscall.ECONNRESET},
+		{name: "other error", key: get, err: errors.New("x")},
+		{name: "nil key", err: &googleapi.Error{Code: 503}},
+	} {
+		if got := DefaultShouldRetry(tc.key, tc.err); got != tc.want {
+			t.Errorf("%s: DefaultShouldRetry(%+v, %v) = %t, want %t", tc.name, tc.key, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	p := &RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := p.backoff(i + 1); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(2); got < time.Second || got > 3*time.Second {
+			t.Fatalf("backoff(2) = %v with jitter, want in [1s, 3s]", got)
+		}
+	}
+}
+
+func TestServiceRetry(t *testing.T) {
+	t.Parallel()
+
+	errRetry := &googleapi.Error{Code: 503}
+	errFatal := &googleapi.Error{Code: 400}
+
+	for _, tc := range []struct {
+		name         string
+		policy       *RetryPolicy
+		timeout      time.Duration
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "no policy",
+			errs:         []error{errRetry, nil},
+			wantAttempts: 1,
+			wantErr:      errRetry,
+		},
+		{
+			name:         "success",
+			policy:       &RetryPolicy{MaxAttempts: 3},
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry then success",
+			policy:       &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			errs:         []error{errRetry, errRetry, nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "max attempts",
+			policy:       &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			errs:         []error{errRetry, errRetry, nil},
+			wantAttempts: 2,
+			wantErr:      errRetry,
+		},
+		{
+			name:         "not retryable",
+			policy:       &RetryPolicy{MaxAttempts: 3},
+			errs:         []error{errFatal, nil},
+			wantAttempts: 1,
+			wantErr:      errFatal,
+		},
+		{
+			name: "custom ShouldRetry",
+			policy: &RetryPolicy{
+				MaxAttempts: 3,
+				ShouldRetry: func(_ *CallContextKey, err error) bool { return err == errFatal },
+			},
+			errs:         []error{errFatal, nil},
+			wantAttempts: 2,
+		},
+		{
+			name:         "deadline before backoff",
+			policy:       &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour},
+			timeout:      time.Minute,
+			errs:         []error{errRetry, nil},
+			wantAttempts: 1,
+			wantErr:      errRetry,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rl := &recordingRateLimiter{}
+			s := &Service{RateLimiter: rl, RetryPolicy: tc.policy}
+			ctx := context.Background()
+			if tc.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			var attempts int
+			err := s.retry(ctx, &CallContextKey{Operation: "Get"}, func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			})
+			if err != tc.wantErr {
+				t.Errorf("retry() = %v, want %v", err, tc.wantErr)
+			}
+			if attempts != tc.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tc.wantAttempts)
+			}
+			// Each retry is Observed and Accepted by the RateLimiter.
+			if rl.accepts != attempts-1 || len(rl.observed) != attempts-1 {
+				t.Errorf("RateLimiter accepts = %d, observed = %d; want %d", rl.accepts, len(rl.observed), attempts-1)
+			}
+		})
+	}
+}
+
+func TestServiceRetryCanceled(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{
+		RateLimiter: &NopRateLimiter{},
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errRetry := &googleapi.Error{Code: 429}
+	var attempts int
+	err := s.retry(ctx, &CallContextKey{Operation: "Get"}, func() error {
+		attempts++
+		cancel()
+		return errRetry
+	})
+	if err != errRetry || attempts != 1 {
+		t.Errorf("retry() = %v, attempts = %d; want %v, 1", err, attempts, errRetry)
+	}
+}
+
+func TestGeneratedCallsRetry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock  sync.Mutex
+		calls = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		calls[r.URL.Path]++
+		n := calls[r.URL.Path]
+		lock.Unlock()
+
+		// Fail the first attempt for every path.
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/projects/proj/global/addresses/addr":
+			fmt.Fprint(w, `{"name": "addr"}`)
+		case "/projects/proj/global/addresses":
+			if r.URL.Query().Get("pageToken") == "" {
+				fmt.Fprint(w, `{"items": [{"name": "a"}], "nextPageToken": "next"}`)
+				return
+			}
+			// Fail the second page once to check that the List starts over.
+			if n == 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"items": [{"name": "b"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	s.RetryPolicy = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	g := NewGCE(s)
+	ctx := context.Background()
+
+	addr, err := g.GlobalAddresses().Get(ctx, meta.GlobalKey("addr"))
+	if err != nil || addr.Name != "addr" {
+		t.Errorf("Get() = %+v, %v; want addr, nil", addr, err)
+	}
+
+	l, err := g.GlobalAddresses().List(ctx, filter.None)
+	if err != nil {
+		t.Fatalf("List() = %v, want nil", err)
+	}
+	var names []string
+	for _, a := range l {
+		names = append(names, a.Name)
+	}
+	if fmt.Sprint(names) != "[a b]" {
+		t.Errorf("List() = %v, want [a b]", names)
+	}
+}
@@ -85,6 +85,8 @@ func (*viznode) opColor(op rnode.Operation) string {
 		return "yellow"
 	case rnode.OpUpdate:
 		return "khaki1"
+	case rnode.OpAdopt:
+		return "lightblue"
 	case rnode.OpNothing:
 		return "gray90"
 	case rnode.OpUnknown:
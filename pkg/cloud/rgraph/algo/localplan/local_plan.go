@@ -17,10 +17,14 @@ limitations under the License.
 package localplan
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PlanWantGraph computes a plan local to each Node in the graph and puts the
@@ -28,13 +32,28 @@ import (
 // same set of Nodes; Nodes that don't exist need to be marked as with
 // NodeStateDoesNotExist.
 func PlanWantGraph(got, want *rgraph.Graph) error {
-	p := planner{got: got, want: want}
+	return PlanWantGraphContext(context.Background(), got, want)
+}
+
+// PlanWantGraphContext is PlanWantGraph that emits a span for the diff of each
+// Node if ctx contains an OpenTelemetry span.
+func PlanWantGraphContext(ctx context.Context, got, want *rgraph.Graph) error {
+	p := planner{
+		got:    got,
+		want:   want,
+		tracer: trace.SpanFromContext(ctx).TracerProvider().Tracer(instrumentationName),
+		ctx:    ctx,
+	}
 	return p.do()
 }
 
+const instrumentationName = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/localplan"
+
 type planner struct {
-	got  *rgraph.Graph
-	want *rgraph.Graph
+	got    *rgraph.Graph
+	want   *rgraph.Graph
+	tracer trace.Tracer
+	ctx    context.Context
 }
 
 func (p *planner) do() error {
@@ -44,7 +63,7 @@ func (p *planner) do() error {
 	for _, gotNode := range p.got.All() {
 		wantNode := p.want.Get(gotNode.ID())
 		// Preconditions check that wantNode is not nil.
-		if err := p.planWantGraph(gotNode, wantNode); err != nil {
+		if err := p.tracedPlanWantGraph(gotNode, wantNode); err != nil {
 			return err
 		}
 	}
@@ -67,6 +86,20 @@ func (p *planner) preconditions() error {
 	return nil
 }
 
+func (p *planner) tracedPlanWantGraph(gotNode, wantNode rnode.Node) error {
+	_, span := p.tracer.Start(p.ctx, "rgraph.Diff", trace.WithAttributes(exec.AttrResource.String(wantNode.ID().String())))
+	defer span.End()
+
+	err := p.planWantGraph(gotNode, wantNode)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	span.SetAttributes(exec.AttrOp.String(string(wantNode.Plan().Op())))
+	return nil
+}
+
 func (p *planner) planWantGraph(gotNode, wantNode rnode.Node) error {
 	if wantNode.Ownership() != rnode.OwnershipManaged {
 		wantNode.Plan().Set(rnode.PlanDetails{
@@ -79,7 +79,7 @@ type ParallelQueue[T fmt.Stringer] struct {
 	// processed with op.
 	pending []queueElement[T]
 	// in channel is signaled whenever a new element has been
-	// Add()ed. This is 1-1 with the number of Add() calls.
+	// Add()ed. There is at most one wakeup per Add() call.
 	in chan struct{}
 	// done channel is signaled when an element is done i.e. op()
 	// completes.
@@ -130,13 +130,20 @@ func (q *ParallelQueue[T]) Add(item T) bool {
 	q.pending = append(q.pending, qe)
 	if q.state == stateRunning {
 		// Add() will always result in a call to launch() as
-		// we enqueue one element for each call to Add().
-		// This means the Run() loop will wake up AT LEAST
-		// once per `item`.
+		// there is a wakeup in q.in for every Add() that has
+		// not yet been observed by the Run() loop.
 		//
 		// `item` will in q.pending during launch() because
 		// the <-q.in will happen AFTER append(q.pending).
-		q.in <- struct{}{}
+		//
+		// If q.in is full, there is already a wakeup queued that
+		// will observe `item` in q.pending. Blocking here while
+		// holding q.lock would deadlock the Run() loop, which
+		// happens for large graphs with a high fan-out.
+		select {
+		case q.in <- struct{}{}:
+		default:
+		}
 	}
 	return true
 }
@@ -253,6 +253,18 @@ func TestParallelQueue(t *testing.T) {
 			},
 			waitForOrphans: true,
 		},
+		{
+			name: "large fan-out from a task",
+			setup: func(ctx context.Context, q *ParallelQueue[*task], c *taskControl) context.Context {
+				var steps []step
+				for i := 0; i < 500; i++ {
+					steps = append(steps, step{add: fmt.Sprintf("task_%d", i)})
+				}
+				q.Add(c.newTask("a", steps))
+				return ctx
+			},
+			waitForOrphans: true,
+		},
 		{
 			name: "a spawns b; b must execute before a completes",
 			setup: func(ctx context.Context, q *ParallelQueue[*task], c *taskControl) context.Context {
@@ -0,0 +1,206 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota implements a pre-flight check of a planned Graph against the
+// project quotas.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// Option for Check.
+type Option func(*config)
+
+// MetricFunc overrides the function used to map a resource to the name of the
+// quota metric (e.g. "FORWARDING_RULES") that it is counted against. The
+// function should return "" if the resource is not subject to a quota check.
+func MetricFunc(f func(id *cloud.ResourceID) string) Option {
+	return func(c *config) { c.metric = f }
+}
+
+type config struct {
+	metric func(id *cloud.ResourceID) string
+}
+
+// DefaultMetric maps forwarding rules, backend services and addresses to their
+// corresponding quota metric. Other resources are not checked.
+func DefaultMetric(id *cloud.ResourceID) string {
+	switch id.Resource {
+	case "forwardingRules":
+		return "FORWARDING_RULES"
+	case "backendServices":
+		return "BACKEND_SERVICES"
+	case "addresses":
+		return "STATIC_ADDRESSES"
+	}
+	return ""
+}
+
+// Shortfall is a quota metric that does not have enough remaining quota for
+// the plan to succeed.
+type Shortfall struct {
+	// ProjectID of the quota.
+	ProjectID string
+	// Region of the quota. This is "" for project-wide (global) quotas.
+	Region string
+	// Metric name, e.g. "FORWARDING_RULES".
+	Metric string
+	// Limit and Usage as reported by the API.
+	Limit float64
+	Usage float64
+	// Requested is the number of resources the plan will create.
+	Requested int
+}
+
+func (s Shortfall) String() string {
+	scope := "global"
+	if s.Region != "" {
+		scope = s.Region
+	}
+	return fmt.Sprintf("%s/%s %s: limit=%v usage=%v requested=%d", s.ProjectID, scope, s.Metric, s.Limit, s.Usage, s.Requested)
+}
+
+// ExceededError is returned by Check if the plan will exceed one or more
+// quotas.
+type ExceededError struct {
+	Shortfalls []Shortfall
+}
+
+func (e *ExceededError) Error() string {
+	var s []string
+	for _, sf := range e.Shortfalls {
+		s = append(s, sf.String())
+	}
+	return fmt.Sprintf("quota: plan exceeds quota: [%s]", strings.Join(s, ", "))
+}
+
+// bucket is the (project, region, metric) that a resource is counted against.
+// region is "" for global resources.
+type bucket struct {
+	project string
+	region  string
+	metric  string
+}
+
+// Check the planned Operations in the want Graph against the project quota.
+// Only Nodes that will be created (OpCreate) are counted; resources that are
+// recreated or deleted are assumed to not change the usage. This is a
+// conservative estimate as deletions may run after creations.
+//
+// Returns an *ExceededError if the plan would exceed the quota. Quota metrics
+// that are not reported by the API are ignored.
+func Check(ctx context.Context, c cloud.Cloud, want *rgraph.Graph, opts ...Option) error {
+	config := config{metric: DefaultMetric}
+	for _, o := range opts {
+		o(&config)
+	}
+
+	demand := map[bucket]int{}
+	for _, n := range want.All() {
+		if n.Plan().Op() != rnode.OpCreate {
+			continue
+		}
+		metric := config.metric(n.ID())
+		if metric == "" {
+			continue
+		}
+		b := bucket{project: n.ID().ProjectID, metric: metric}
+		switch n.ID().Key.Type() {
+		case meta.Global:
+		case meta.Regional:
+			b.region = n.ID().Key.Region
+		default:
+			klog.V(2).Infof("quota.Check: skipping %v, scope is not supported", n.ID())
+			continue
+		}
+		demand[b]++
+	}
+
+	quotas := map[bucket]*quotaValue{}
+	fetched := map[bucket]bool{}
+	for b := range demand {
+		scope := bucket{project: b.project, region: b.region}
+		if fetched[scope] {
+			continue
+		}
+		fetched[scope] = true
+		if err := fetchQuotas(ctx, c, scope, quotas); err != nil {
+			return err
+		}
+	}
+
+	var shortfalls []Shortfall
+	for b, requested := range demand {
+		q, ok := quotas[b]
+		if !ok {
+			klog.V(2).Infof("quota.Check: metric %+v not reported, skipping", b)
+			continue
+		}
+		if q.usage+float64(requested) > q.limit {
+			shortfalls = append(shortfalls, Shortfall{
+				ProjectID: b.project,
+				Region:    b.region,
+				Metric:    b.metric,
+				Limit:     q.limit,
+				Usage:     q.usage,
+				Requested: requested,
+			})
+		}
+	}
+	if len(shortfalls) == 0 {
+		return nil
+	}
+	sort.Slice(shortfalls, func(i, j int) bool { return shortfalls[i].String() < shortfalls[j].String() })
+
+	return &ExceededError{Shortfalls: shortfalls}
+}
+
+type quotaValue struct{ limit, usage float64 }
+
+// fetchQuotas for the scope (project and optional region) into out.
+func fetchQuotas(ctx context.Context, c cloud.Cloud, scope bucket, out map[bucket]*quotaValue) error {
+	add := func(metric string, limit, usage float64) {
+		out[bucket{project: scope.project, region: scope.region, metric: metric}] = &quotaValue{limit: limit, usage: usage}
+	}
+	if scope.region == "" {
+		p, err := c.Projects().Get(ctx, scope.project)
+		if err != nil {
+			return fmt.Errorf("quota: get project %q: %w", scope.project, err)
+		}
+		for _, q := range p.Quotas {
+			add(q.Metric, q.Limit, q.Usage)
+		}
+		return nil
+	}
+	r, err := c.Regions().Get(ctx, meta.GlobalKey(scope.region), cloud.ForceProjectID(scope.project))
+	if err != nil {
+		return fmt.Errorf("quota: get region %q (project %q): %w", scope.region, scope.project, err)
+	}
+	for _, q := range r.Quotas {
+		add(q.Metric, q.Limit, q.Usage)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const project = "proj-1"
+
+func fakeMetric(id *cloud.ResourceID) string {
+	if id.Resource == "fakes" {
+		return "FAKES"
+	}
+	return ""
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		key *meta.Key
+		op  rnode.Operation
+	}
+	for _, tc := range []struct {
+		name          string
+		nodes         []node
+		projectQuota  *compute.Quota
+		regionQuota   *compute.Quota
+		wantErr       bool
+		wantShortfall []Shortfall
+	}{
+		{
+			name: "empty graph",
+		},
+		{
+			name: "global within quota",
+			nodes: []node{
+				{meta.GlobalKey("a"), rnode.OpCreate},
+				{meta.GlobalKey("b"), rnode.OpCreate},
+			},
+			projectQuota: &compute.Quota{Metric: "FAKES", Limit: 10, Usage: 8},
+		},
+		{
+			name: "global exceeds quota",
+			nodes: []node{
+				{meta.GlobalKey("a"), rnode.OpCreate},
+				{meta.GlobalKey("b"), rnode.OpCreate},
+				{meta.GlobalKey("c"), rnode.OpUpdate},
+			},
+			projectQuota: &compute.Quota{Metric: "FAKES", Limit: 10, Usage: 9},
+			wantErr:      true,
+			wantShortfall: []Shortfall{
+				{ProjectID: project, Metric: "FAKES", Limit: 10, Usage: 9, Requested: 2},
+			},
+		},
+		{
+			name: "only creates are counted",
+			nodes: []node{
+				{meta.GlobalKey("a"), rnode.OpRecreate},
+				{meta.GlobalKey("b"), rnode.OpDelete},
+				{meta.GlobalKey("c"), rnode.OpNothing},
+			},
+			projectQuota: &compute.Quota{Metric: "FAKES", Limit: 0, Usage: 0},
+		},
+		{
+			name: "regional exceeds quota",
+			nodes: []node{
+				{meta.RegionalKey("a", "us-central1"), rnode.OpCreate},
+			},
+			projectQuota: &compute.Quota{Metric: "FAKES", Limit: 10, Usage: 0},
+			regionQuota:  &compute.Quota{Metric: "FAKES", Limit: 1, Usage: 1},
+			wantErr:      true,
+			wantShortfall: []Shortfall{
+				{ProjectID: project, Region: "us-central1", Metric: "FAKES", Limit: 1, Usage: 1, Requested: 1},
+			},
+		},
+		{
+			name: "metric not reported is ignored",
+			nodes: []node{
+				{meta.GlobalKey("a"), rnode.OpCreate},
+			},
+		},
+		{
+			name: "region does not exist",
+			nodes: []node{
+				{meta.RegionalKey("a", "us-central1"), rnode.OpCreate},
+			},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			p := &compute.Project{Name: project}
+			if tc.projectQuota != nil {
+				p.Quotas = append(p.Quotas, tc.projectQuota)
+			}
+			mock.MockProjects.Objects[*meta.GlobalKey(project)] = &cloud.MockProjectsObj{Obj: p}
+			if tc.regionQuota != nil {
+				mock.MockRegions.Objects[*meta.GlobalKey("us-central1")] = &cloud.MockRegionsObj{
+					Obj: &compute.Region{Name: "us-central1", Quotas: []*compute.Quota{tc.regionQuota}},
+				}
+			}
+
+			b := rgraph.NewBuilder()
+			for _, n := range tc.nodes {
+				nb := fake.NewBuilder(fake.ID(project, n.key))
+				nb.SetOwnership(rnode.OwnershipManaged)
+				nb.SetState(rnode.NodeExists)
+				b.Add(nb)
+			}
+			g := b.MustBuild()
+			for _, n := range tc.nodes {
+				g.Get(fake.ID(project, n.key)).Plan().Set(rnode.PlanDetails{Operation: n.op})
+			}
+
+			err := Check(context.Background(), mock, g, MetricFunc(fakeMetric))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Check() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantShortfall == nil {
+				return
+			}
+			var exceeded *ExceededError
+			if !errors.As(err, &exceeded) {
+				t.Fatalf("Check() = %v, want ExceededError", err)
+			}
+			if diff := cmp.Diff(exceeded.Shortfalls, tc.wantShortfall); diff != "" {
+				t.Errorf("Shortfalls: -got,+want: %s", diff)
+			}
+		})
+	}
+}
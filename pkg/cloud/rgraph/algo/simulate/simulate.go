@@ -0,0 +1,226 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulate estimates the wall-clock time of executing a plan using the
+// historical latencies of the Actions. Compare the estimates for different
+// numbers of Workers to choose between the serial and parallel Executors:
+//
+//	stats := simulate.NewStats(10 * time.Second)
+//	// Record the latencies of previous executions.
+//	ex, err := exec.NewParallelExecutor(c, actions, exec.TracerOption(stats))
+//	...
+//	serial, err := simulate.Do(result.Actions, stats, simulate.Workers(1))
+//	parallel, err := simulate.Do(result.Actions, stats, simulate.Workers(4))
+//
+// Simulating the execution calls DryRun() on the Actions to determine the
+// Events they signal. The Actions are not modified otherwise and can still be
+// executed afterwards.
+package simulate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+)
+
+// Option for Do.
+type Option func(*config)
+
+// Workers is the number of Actions that can run concurrently. Use 1 (the
+// default) to simulate the serial Executor.
+func Workers(n int) Option {
+	return func(c *config) { c.workers = n }
+}
+
+type config struct {
+	workers int
+}
+
+// Entry is the simulated execution of an Action.
+type Entry struct {
+	Action exec.Action
+	// Start and End of the Action, relative to the start of the execution.
+	Start time.Duration
+	End   time.Duration
+}
+
+// Result of the simulation.
+type Result struct {
+	// Duration is the estimated wall-clock time of the execution.
+	Duration time.Duration
+	// Timeline of the Actions, in the order they are started.
+	Timeline []Entry
+	// CriticalPath is the longest chain of Actions that depend on each
+	// other. The execution cannot be faster than the CriticalPath regardless
+	// of the number of Workers.
+	CriticalPath []exec.Action
+	// CriticalPathDuration is the estimated time to execute the
+	// CriticalPath.
+	CriticalPathDuration time.Duration
+	// Pending are the Actions that will not run because they are waiting
+	// for Events that are not signalled by any Action.
+	Pending []exec.Action
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("duration %v (critical path %v, %d actions), %d actions, %d pending",
+		r.Duration, r.CriticalPathDuration, len(r.CriticalPath), len(r.Timeline), len(r.Pending))
+}
+
+// Do simulates the execution of the actions using the latencies in stats.
+// Runnable Actions are started in order of exec.ActionPriority, as done by
+// the Executors.
+func Do(actions []exec.Action, stats *Stats, opts ...Option) (*Result, error) {
+	c := config{workers: 1}
+	for _, o := range opts {
+		o(&c)
+	}
+	if c.workers < 1 {
+		return nil, fmt.Errorf("simulate: invalid Workers %d", c.workers)
+	}
+
+	s := newSimulation(actions, stats)
+	timeline, _, pending := s.run(c.workers)
+
+	ret := &Result{Timeline: timeline, Pending: pending}
+	for _, e := range timeline {
+		if e.End > ret.Duration {
+			ret.Duration = e.End
+		}
+	}
+
+	// With unlimited Workers, an Action starts as soon as the last Event
+	// it is waiting for is signalled. The critical path is the chain of
+	// Actions that signalled the last Event, starting from the Action that
+	// finishes last.
+	unbounded, blockers, _ := s.run(len(actions))
+	var last *Entry
+	for i := range unbounded {
+		if last == nil || unbounded[i].End > last.End {
+			last = &unbounded[i]
+		}
+	}
+	if last != nil {
+		ret.CriticalPathDuration = last.End
+		for i := s.index[last.Action]; i >= 0; i = blockers[i] {
+			ret.CriticalPath = append([]exec.Action{s.actions[i]}, ret.CriticalPath...)
+		}
+	}
+	return ret, nil
+}
+
+type simulation struct {
+	actions []exec.Action
+	index   map[exec.Action]int
+	latency []time.Duration
+	// signals are the Events signalled by each Action.
+	signals []exec.EventList
+}
+
+func newSimulation(actions []exec.Action, stats *Stats) *simulation {
+	s := &simulation{
+		actions: actions,
+		index:   map[exec.Action]int{},
+	}
+	for i, a := range actions {
+		s.index[a] = i
+		s.latency = append(s.latency, stats.Latency(a))
+		s.signals = append(s.signals, a.DryRun())
+	}
+	return s
+}
+
+// run the simulation with the given number of workers. Returns the timeline,
+// the index of the Action that signalled the last Event each Action was
+// waiting for (-1 if none) and the Actions that never ran.
+func (s *simulation) run(workers int) ([]Entry, []int, []exec.Action) {
+	type running struct {
+		idx int
+		end time.Duration
+	}
+	var (
+		now       time.Duration
+		timeline  []Entry
+		ready     []int
+		active    []running
+		started   = make([]bool, len(s.actions))
+		blockers  = make([]int, len(s.actions))
+		remaining = make([]exec.EventList, len(s.actions))
+	)
+	for i, a := range s.actions {
+		blockers[i] = -1
+		remaining[i] = append(exec.EventList{}, a.PendingEvents()...)
+		if len(remaining[i]) == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	for {
+		// Start the highest priority runnable Actions.
+		sort.SliceStable(ready, func(i, j int) bool {
+			return exec.ActionPriority(s.actions[ready[i]]) > exec.ActionPriority(s.actions[ready[j]])
+		})
+		for len(ready) > 0 && len(active) < workers {
+			i := ready[0]
+			ready = ready[1:]
+			started[i] = true
+			active = append(active, running{idx: i, end: now + s.latency[i]})
+			timeline = append(timeline, Entry{Action: s.actions[i], Start: now, End: now + s.latency[i]})
+		}
+		if len(active) == 0 {
+			break
+		}
+
+		// Finish the Action that ends first and signal its Events.
+		next := 0
+		for k := range active {
+			if active[k].end < active[next].end {
+				next = k
+			}
+		}
+		done := active[next]
+		active = append(active[:next], active[next+1:]...)
+		now = done.end
+
+		for i := range s.actions {
+			if started[i] || len(remaining[i]) == 0 {
+				continue
+			}
+			for _, ev := range s.signals[done.idx] {
+				for k, want := range remaining[i] {
+					if want.Equal(ev) {
+						remaining[i] = append(remaining[i][:k], remaining[i][k+1:]...)
+						break
+					}
+				}
+			}
+			if len(remaining[i]) == 0 {
+				blockers[i] = done.idx
+				ready = append(ready, i)
+			}
+		}
+	}
+
+	var pending []exec.Action
+	for i, a := range s.actions {
+		if !started[i] {
+			pending = append(pending, a)
+		}
+	}
+	return timeline, blockers, pending
+}
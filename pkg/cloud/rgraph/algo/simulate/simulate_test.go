@@ -0,0 +1,247 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeAction signals StringEvent(name).
+type fakeAction struct {
+	exec.ActionBase
+	name     string
+	typ      exec.ActionType
+	resource *cloud.ResourceID
+}
+
+func (a *fakeAction) Run(context.Context, cloud.Cloud) (exec.EventList, error) {
+	return a.DryRun(), nil
+}
+func (a *fakeAction) DryRun() exec.EventList { return exec.EventList{exec.StringEvent(a.name)} }
+func (a *fakeAction) String() string         { return a.name }
+
+func (a *fakeAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{Name: a.name, Type: a.typ, Resource: a.resource}
+}
+
+func newAction(name string, typ exec.ActionType, resource string, want ...string) *fakeAction {
+	a := &fakeAction{
+		name: name,
+		typ:  typ,
+		resource: &cloud.ResourceID{
+			ProjectID: "proj-1",
+			Resource:  resource,
+			Key:       meta.GlobalKey(name),
+		},
+	}
+	for _, w := range want {
+		a.Want = append(a.Want, exec.StringEvent(w))
+	}
+	return a
+}
+
+func names(actions []exec.Action) []string {
+	var ret []string
+	for _, a := range actions {
+		ret = append(ret, a.String())
+	}
+	return ret
+}
+
+func TestDo(t *testing.T) {
+	stats := NewStats(time.Second)
+	stats.Observe(exec.ActionTypeCreate, "healthChecks", 10*time.Second)
+	stats.Observe(exec.ActionTypeCreate, "backendServices", 10*time.Second)
+	stats.Observe(exec.ActionTypeCreate, "urlMaps", 10*time.Second)
+	stats.Observe(exec.ActionTypeUpdate, "firewalls", 5*time.Second)
+
+	// A -> B -> C; D; E
+	newActions := func() []exec.Action {
+		return []exec.Action{
+			newAction("A", exec.ActionTypeCreate, "healthChecks"),
+			newAction("B", exec.ActionTypeCreate, "backendServices", "A"),
+			newAction("C", exec.ActionTypeCreate, "urlMaps", "B"),
+			newAction("D", exec.ActionTypeUpdate, "firewalls"),
+			// No stats for addresses, uses the latency for creates.
+			newAction("E", exec.ActionTypeCreate, "addresses"),
+		}
+	}
+
+	for _, tc := range []struct {
+		name         string
+		actions      []exec.Action
+		opts         []Option
+		wantDuration time.Duration
+		wantOrder    []string
+		wantCritical []string
+		wantCPDur    time.Duration
+		wantPending  []string
+		wantErr      bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:         "serial",
+			actions:      newActions(),
+			wantDuration: 45 * time.Second,
+			wantOrder:    []string{"A", "D", "E", "B", "C"},
+			wantCritical: []string{"A", "B", "C"},
+			wantCPDur:    30 * time.Second,
+		},
+		{
+			name:         "2 workers",
+			actions:      newActions(),
+			opts:         []Option{Workers(2)},
+			wantDuration: 30 * time.Second,
+			wantOrder:    []string{"A", "D", "E", "B", "C"},
+			wantCritical: []string{"A", "B", "C"},
+			wantCPDur:    30 * time.Second,
+		},
+		{
+			name: "priority",
+			actions: []exec.Action{
+				newAction("A", exec.ActionTypeCreate, "healthChecks"),
+				exec.NewPrioritizedAction(newAction("B", exec.ActionTypeCreate, "backendServices"), 1),
+			},
+			wantDuration: 20 * time.Second,
+			wantOrder:    []string{"B", "A"},
+			wantCritical: []string{"B"},
+			wantCPDur:    10 * time.Second,
+		},
+		{
+			name: "meta actions have no latency",
+			actions: []exec.Action{
+				exec.NewExistsAction(&cloud.ResourceID{ProjectID: "proj-1", Resource: "healthChecks", Key: meta.GlobalKey("hc")}),
+				newAction("A", exec.ActionTypeDelete, "healthChecks"),
+			},
+			wantDuration: time.Second,
+			wantOrder:    []string{"EventAction([Exists(healthChecks:proj-1/hc)])", "A"},
+			wantCritical: []string{"A"},
+			wantCPDur:    time.Second,
+		},
+		{
+			name: "pending",
+			actions: []exec.Action{
+				newAction("A", exec.ActionTypeCreate, "healthChecks"),
+				newAction("B", exec.ActionTypeCreate, "backendServices", "A", "X"),
+			},
+			wantDuration: 10 * time.Second,
+			wantOrder:    []string{"A"},
+			wantCritical: []string{"A"},
+			wantCPDur:    10 * time.Second,
+			wantPending:  []string{"B"},
+		},
+		{
+			name:    "invalid workers",
+			opts:    []Option{Workers(0)},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := Do(tc.actions, stats, tc.opts...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v, want err = %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if res.Duration != tc.wantDuration {
+				t.Errorf("Duration = %v, want %v", res.Duration, tc.wantDuration)
+			}
+			var order []string
+			for _, e := range res.Timeline {
+				order = append(order, e.Action.String())
+			}
+			if diff := cmp.Diff(order, tc.wantOrder); diff != "" {
+				t.Errorf("Timeline: diff -got,+want: %s", diff)
+			}
+			if diff := cmp.Diff(names(res.CriticalPath), tc.wantCritical); diff != "" {
+				t.Errorf("CriticalPath: diff -got,+want: %s", diff)
+			}
+			if res.CriticalPathDuration != tc.wantCPDur {
+				t.Errorf("CriticalPathDuration = %v, want %v", res.CriticalPathDuration, tc.wantCPDur)
+			}
+			if diff := cmp.Diff(names(res.Pending), tc.wantPending); diff != "" {
+				t.Errorf("Pending: diff -got,+want: %s", diff)
+			}
+			// The Actions must not have been signalled by the simulation.
+			for _, a := range tc.actions {
+				if fa, ok := a.(*fakeAction); ok && len(fa.PendingEvents()) != len(fa.Want) {
+					t.Errorf("Action %s was modified", fa.name)
+				}
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	stats := NewStats(time.Second)
+	start := time.Now()
+	stats.Record(&exec.TraceEntry{
+		Action: newAction("A", exec.ActionTypeCreate, "healthChecks"),
+		Start:  start,
+		End:    start.Add(4 * time.Second),
+	}, nil)
+	stats.Observe(exec.ActionTypeCreate, "healthChecks", 6*time.Second)
+	stats.Observe(exec.ActionTypeCreate, "backendServices", 20*time.Second)
+	// Errors are not recorded.
+	stats.Record(&exec.TraceEntry{
+		Action: newAction("B", exec.ActionTypeDelete, "healthChecks"),
+		Start:  start,
+		End:    start.Add(time.Hour),
+	}, context.DeadlineExceeded)
+
+	for _, tc := range []struct {
+		name   string
+		action exec.Action
+		want   time.Duration
+	}{
+		{
+			name:   "by resource",
+			action: newAction("X", exec.ActionTypeCreate, "healthChecks"),
+			want:   5 * time.Second,
+		},
+		{
+			name:   "by type",
+			action: newAction("X", exec.ActionTypeCreate, "urlMaps"),
+			want:   10 * time.Second,
+		},
+		{
+			name:   "default",
+			action: newAction("X", exec.ActionTypeDelete, "healthChecks"),
+			want:   time.Second,
+		},
+		{
+			name:   "meta",
+			action: exec.NewExistsAction(&cloud.ResourceID{ProjectID: "proj-1", Resource: "healthChecks", Key: meta.GlobalKey("hc")}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := stats.Latency(tc.action); got != tc.want {
+				t.Errorf("Latency(%v) = %v, want %v", tc.action, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+)
+
+// Stats are the historical latencies of Actions, aggregated by the type of
+// Action and the type of resource (e.g. "Create" "backendServices"). Stats
+// implements exec.Tracer so it can be used to record the latencies of an
+// execution with exec.TracerOption.
+type Stats struct {
+	// Default latency for Actions without stats.
+	Default time.Duration
+
+	lock sync.Mutex
+	// byKey are the latencies by ActionType and resource.
+	byKey map[statsKey]*latency
+	// byType are the latencies by ActionType for all resources.
+	byType map[exec.ActionType]*latency
+}
+
+type statsKey struct {
+	typ      exec.ActionType
+	resource string
+}
+
+type latency struct {
+	total time.Duration
+	count int
+}
+
+func (l *latency) mean() time.Duration { return l.total / time.Duration(l.count) }
+
+// Stats implements exec.Tracer.
+var _ exec.Tracer = (*Stats)(nil)
+
+// NewStats returns empty Stats. def is the latency used for Actions that have
+// no stats.
+func NewStats(def time.Duration) *Stats {
+	return &Stats{
+		Default: def,
+		byKey:   map[statsKey]*latency{},
+		byType:  map[exec.ActionType]*latency{},
+	}
+}
+
+// Observe adds a latency for an Action of the given type operating on the
+// given resource type (e.g. "backendServices").
+func (s *Stats) Observe(typ exec.ActionType, resource string, d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	add := func(l *latency) *latency {
+		if l == nil {
+			l = &latency{}
+		}
+		l.total += d
+		l.count++
+		return l
+	}
+	key := statsKey{typ: typ, resource: resource}
+	s.byKey[key] = add(s.byKey[key])
+	s.byType[typ] = add(s.byType[typ])
+}
+
+// Latency returns the expected latency of the Action. This is the mean of the
+// observed latencies for the same type of Action and resource. If there are
+// none, the mean of the latencies for the type of Action is used, followed by
+// s.Default. Actions that only signal Events (ActionTypeMeta) have zero
+// latency.
+func (s *Stats) Latency(a exec.Action) time.Duration {
+	md := a.Metadata()
+	if md.Type == exec.ActionTypeMeta {
+		return 0
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if l, ok := s.byKey[statsKey{typ: md.Type, resource: resourceType(md)}]; ok {
+		return l.mean()
+	}
+	if l, ok := s.byType[md.Type]; ok {
+		return l.mean()
+	}
+	return s.Default
+}
+
+// Record implements exec.Tracer. Actions that returned an error are not
+// recorded.
+func (s *Stats) Record(entry *exec.TraceEntry, err error) {
+	if err != nil || entry.Action == nil {
+		return
+	}
+	md := entry.Action.Metadata()
+	if md.Type == exec.ActionTypeMeta {
+		return
+	}
+	s.Observe(md.Type, resourceType(md), entry.End.Sub(entry.Start))
+}
+
+// Finish implements exec.Tracer.
+func (s *Stats) Finish([]exec.Action) {}
+
+func resourceType(md *exec.ActionMetadata) string {
+	if md.Resource == nil {
+		return ""
+	}
+	return md.Resource.Resource
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package summary computes an aggregate, machine-readable summary of a planned
+// Graph. This is suitable for emitting metrics and CLI output.
+package summary
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Counts of the planned operations.
+type Counts struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Recreate  int `json:"recreate"`
+	Delete    int `json:"delete"`
+	Adopt     int `json:"adopt"`
+	Unchanged int `json:"unchanged"`
+}
+
+// Changed returns the number of operations that will mutate a resource.
+func (c Counts) Changed() int {
+	return c.Create + c.Update + c.Recreate + c.Delete + c.Adopt
+}
+
+func (c *Counts) add(op rnode.Operation) {
+	switch op {
+	case rnode.OpCreate:
+		c.Create++
+	case rnode.OpUpdate:
+		c.Update++
+	case rnode.OpRecreate:
+		c.Recreate++
+	case rnode.OpDelete:
+		c.Delete++
+	case rnode.OpAdopt:
+		c.Adopt++
+	case rnode.OpNothing:
+		c.Unchanged++
+	}
+}
+
+// Node is the summary for a single changed Node.
+type Node struct {
+	// Resource is the ResourceID.String() of the Node.
+	Resource string `json:"resource"`
+	// Op planned for the Node.
+	Op rnode.Operation `json:"op"`
+	// Fields are the paths (e.g. "*.Description") that differ between got and
+	// want. This is only filled in for Update, Recreate and Adopt.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Summary of a planned Graph.
+type Summary struct {
+	Counts
+	// ByResource breaks down the Counts by the resource type (e.g.
+	// "backendServices").
+	ByResource map[string]*Counts `json:"byResource,omitempty"`
+	// Nodes that will be changed by the plan, sorted by Resource. Unchanged
+	// Nodes are not listed.
+	Nodes []Node `json:"nodes,omitempty"`
+}
+
+// String returns a one line summary, e.g. "2 to create, 1 to update, 0 to
+// recreate, 0 to delete, 0 to adopt, 5 unchanged".
+func (s *Summary) String() string {
+	return fmt.Sprintf("%d to create, %d to update, %d to recreate, %d to delete, %d to adopt, %d unchanged",
+		s.Create, s.Update, s.Recreate, s.Delete, s.Adopt, s.Unchanged)
+}
+
+// Do summarizes the plan for want. want must have been planned against got
+// (e.g. with localplan.PlanWantGraph). If a Node plan does not carry a diff,
+// the field list is computed by diffing the Node against the corresponding
+// Node in got.
+func Do(got, want *rgraph.Graph) (*Summary, error) {
+	s := &Summary{ByResource: map[string]*Counts{}}
+
+	for _, wantNode := range want.All() {
+		op := wantNode.Plan().Op()
+		if op == rnode.OpUnknown {
+			return nil, fmt.Errorf("summary: node %v has not been planned", wantNode.ID())
+		}
+
+		s.Counts.add(op)
+		resource := wantNode.ID().Resource
+		if s.ByResource[resource] == nil {
+			s.ByResource[resource] = &Counts{}
+		}
+		s.ByResource[resource].add(op)
+
+		if op == rnode.OpNothing {
+			continue
+		}
+		n := Node{Resource: wantNode.ID().String(), Op: op}
+		switch op {
+		case rnode.OpUpdate, rnode.OpRecreate, rnode.OpAdopt:
+			fields, err := diffFields(got, wantNode)
+			if err != nil {
+				return nil, err
+			}
+			n.Fields = fields
+		}
+		s.Nodes = append(s.Nodes, n)
+	}
+
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].Resource < s.Nodes[j].Resource })
+
+	return s, nil
+}
+
+func diffFields(got *rgraph.Graph, wantNode rnode.Node) ([]string, error) {
+	details := wantNode.Plan().Details()
+	if details.Diff == nil {
+		gotNode := got.Get(wantNode.ID())
+		if gotNode == nil || gotNode.State() != rnode.NodeExists || wantNode.State() != rnode.NodeExists {
+			return nil, nil
+		}
+		var err error
+		details, err = wantNode.Diff(gotNode)
+		if err != nil {
+			return nil, fmt.Errorf("summary: diff %v: %w", wantNode.ID(), err)
+		}
+		if details == nil || details.Diff == nil {
+			return nil, nil
+		}
+	}
+
+	var fields []string
+	for _, item := range details.Diff.Items {
+		fields = append(fields, item.Path.String())
+	}
+	sort.Strings(fields)
+
+	return fields, nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/localplan"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	const project = "project-1"
+	makeID := func(i int) *cloud.ResourceID {
+		return fake.ID(project, meta.GlobalKey(fmt.Sprintf("fake-%d", i)))
+	}
+	newNode := func(i int, v string, state rnode.NodeState) rnode.Builder {
+		id := makeID(i)
+		nb := fake.NewBuilder(id)
+		mr := fake.NewMutableFake(project, id.Key)
+		mr.Access(func(x *fake.FakeResource) { x.Value = v })
+		r, _ := mr.Freeze()
+		nb.SetResource(r)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(state)
+		return nb
+	}
+
+	for _, tc := range []struct {
+		name         string
+		setupBuilder func(gotb, wantb *rgraph.Builder)
+		// setupGraph is called after planning.
+		setupGraph func(got, want *rgraph.Graph)
+		skipPlan   bool
+		wantErr    bool
+		want       *Summary
+	}{
+		{
+			name: "empty graph",
+			want: &Summary{ByResource: map[string]*Counts{}},
+		},
+		{
+			name: "mixed plan",
+			setupBuilder: func(gotb, wantb *rgraph.Builder) {
+				gotb.Add(newNode(0, "abc", rnode.NodeExists))
+				wantb.Add(newNode(0, "abc", rnode.NodeExists))
+				gotb.Add(newNode(1, "abc", rnode.NodeExists))
+				wantb.Add(newNode(1, "def", rnode.NodeExists))
+				gotb.Add(newNode(2, "", rnode.NodeDoesNotExist))
+				wantb.Add(newNode(2, "", rnode.NodeExists))
+				gotb.Add(newNode(3, "", rnode.NodeExists))
+				wantb.Add(newNode(3, "", rnode.NodeDoesNotExist))
+			},
+			want: &Summary{
+				Counts: Counts{Create: 1, Update: 1, Delete: 1, Unchanged: 1},
+				ByResource: map[string]*Counts{
+					"fakes": {Create: 1, Update: 1, Delete: 1, Unchanged: 1},
+				},
+				Nodes: []Node{
+					{Resource: makeID(1).String(), Op: rnode.OpUpdate, Fields: []string{"*.Value"}},
+					{Resource: makeID(2).String(), Op: rnode.OpCreate},
+					{Resource: makeID(3).String(), Op: rnode.OpDelete},
+				},
+			},
+		},
+		{
+			name: "recreate without diff in plan",
+			setupBuilder: func(gotb, wantb *rgraph.Builder) {
+				gotb.Add(newNode(0, "abc", rnode.NodeExists))
+				wantb.Add(newNode(0, "def", rnode.NodeExists))
+			},
+			setupGraph: func(got, want *rgraph.Graph) {
+				want.Get(makeID(0)).Plan().Set(rnode.PlanDetails{Operation: rnode.OpRecreate})
+			},
+			want: &Summary{
+				Counts:     Counts{Recreate: 1},
+				ByResource: map[string]*Counts{"fakes": {Recreate: 1}},
+				Nodes: []Node{
+					{Resource: makeID(0).String(), Op: rnode.OpRecreate, Fields: []string{"*.Value"}},
+				},
+			},
+		},
+		{
+			name: "unplanned node",
+			setupBuilder: func(gotb, wantb *rgraph.Builder) {
+				gotb.Add(newNode(0, "abc", rnode.NodeExists))
+				wantb.Add(newNode(0, "abc", rnode.NodeExists))
+			},
+			skipPlan: true,
+			wantErr:  true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotb := rgraph.NewBuilder()
+			wantb := rgraph.NewBuilder()
+			if tc.setupBuilder != nil {
+				tc.setupBuilder(gotb, wantb)
+			}
+			got := gotb.MustBuild()
+			want := wantb.MustBuild()
+			if !tc.skipPlan {
+				if err := localplan.PlanWantGraph(got, want); err != nil {
+					t.Fatalf("PlanWantGraph() = %v, want nil", err)
+				}
+			}
+			if tc.setupGraph != nil {
+				tc.setupGraph(got, want)
+			}
+
+			s, err := Do(got, want)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(s, tc.want); diff != "" {
+				t.Errorf("Do(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSummaryString(t *testing.T) {
+	s := &Summary{Counts: Counts{Create: 2, Update: 1, Unchanged: 5}}
+	const want = "2 to create, 1 to update, 0 to recreate, 0 to delete, 0 to adopt, 5 unchanged"
+	if got := s.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := s.Changed(); got != 3 {
+		t.Errorf("Changed() = %d, want 3", got)
+	}
+}
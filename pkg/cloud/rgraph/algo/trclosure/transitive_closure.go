@@ -40,14 +40,43 @@ func OnGetFunc(f func(n rnode.Builder) error) Option {
 	return func(c *Config) { c.onGet = f }
 }
 
+// SkipFetchFunc is called before fetching each Node from the Cloud. If f
+// returns true, the Node is not fetched and the current value of the Node in
+// the graph is used as-is; OnGetFunc is not called for the Node.
+func SkipFetchFunc(f func(n rnode.Builder) bool) Option {
+	return func(c *Config) { c.skipFetch = f }
+}
+
+// WorkerCount sets the maximum number of Nodes that will be fetched from the
+// Cloud concurrently. n must be > 0.
+func WorkerCount(n int) Option {
+	return func(c *Config) { c.workerCount = n }
+}
+
+// RateLimiterOption sets a RateLimiter that is shared by the fetch. Accept() is
+// called before each Node is fetched from the Cloud. This can be used to share
+// a single RateLimiter between multiple concurrent graph fetches so that a
+// large graph does not exhaust the API quota.
+func RateLimiterOption(rl cloud.RateLimiter) Option {
+	return func(c *Config) { c.rateLimiter = rl }
+}
+
 // Config for the algorithm.
 type Config struct {
-	onGet func(n rnode.Builder) error
+	onGet       func(n rnode.Builder) error
+	skipFetch   func(n rnode.Builder) bool
+	workerCount int
+	rateLimiter cloud.RateLimiter
 }
 
+const defaultWorkerCount = 2
+
 func makeConfig(opts ...Option) Config {
 	config := Config{
-		onGet: func(rnode.Builder) error { return nil },
+		onGet:       func(rnode.Builder) error { return nil },
+		skipFetch:   func(rnode.Builder) bool { return false },
+		workerCount: defaultWorkerCount,
+		rateLimiter: &cloud.NopRateLimiter{},
 	}
 	for _, o := range opts {
 		o(&config)
@@ -65,10 +94,15 @@ func makeErr(s string, args ...any) error { return fmt.Errorf("TransitiveClosure
 // Do traverses and fetches the graph, adding all the dependencies into
 // the graph, pulling the resource from Cloud as needed.
 func Do(ctx context.Context, cl cloud.Cloud, gr *rgraph.Builder, opts ...Option) error {
+	config := makeConfig(opts...)
+	if config.workerCount <= 0 {
+		return makeErr("invalid WorkerCount %d", config.workerCount)
+	}
+
 	subctx, cancel := context.WithCancel(ctx)
-	pq := algo.NewParallelQueue[work]()
+	pq := algo.NewParallelQueue[work](algo.WorkerCount(config.workerCount))
 
-	err := doInternal(subctx, cl, gr, pq, opts...)
+	err := doInternal(subctx, cl, gr, pq, config)
 	cancel()
 
 	// Cancel pending traverse operations if we get an error.
@@ -91,10 +125,8 @@ func doInternal(
 	cl cloud.Cloud,
 	gr *rgraph.Builder,
 	pq *algo.ParallelQueue[work],
-	opts ...Option,
+	config Config,
 ) error {
-	config := makeConfig(opts...)
-
 	for _, nb := range gr.All() {
 		if ok := pq.Add(work{b: nb}); !ok {
 			return fmt.Errorf("parallel queue is done")
@@ -149,8 +181,23 @@ func doInternal(
 // syncNode loads the resource from the Cloud. This func MUST be threadsafe with
 // respect to the Node it is syncing.
 func syncNode(ctx context.Context, cl cloud.Cloud, config Config, b rnode.Builder) ([]rnode.ResourceRef, error) {
+	if config.skipFetch(b) {
+		klog.V(2).Infof("Node %s skipped, not fetching from Cloud", b.ID())
+		return outRefs(b)
+	}
+
+	rlk := &cloud.RateLimitKey{
+		ProjectID: b.ID().ProjectID,
+		Operation: "Get",
+		Version:   b.Version(),
+		Service:   b.ID().Resource,
+	}
+	if err := config.rateLimiter.Accept(ctx, rlk); err != nil {
+		return nil, makeErr("rate limiter: %w", err)
+	}
 	// TODO: SyncFromCloud needs to be threadsafe.
 	err := b.SyncFromCloud(ctx, cl)
+	config.rateLimiter.Observe(ctx, err, rlk)
 	klog.V(2).Infof("node.SyncFromCloud(%s) = %v (%s)", b.ID(), err, pretty.Sprint(b))
 
 	if err != nil {
@@ -161,6 +208,11 @@ func syncNode(ctx context.Context, cl cloud.Cloud, config Config, b rnode.Builde
 		return nil, makeErr("%w", err)
 	}
 
+	return outRefs(b)
+}
+
+// outRefs returns the references to traverse from the Node.
+func outRefs(b rnode.Builder) ([]rnode.ResourceRef, error) {
 	if b.State() != rnode.NodeExists {
 		klog.V(2).Infof("Node %s resource state = %s, no outRefs", b.ID(), b.State())
 		return nil, nil
@@ -172,10 +224,10 @@ func syncNode(ctx context.Context, cl cloud.Cloud, config Config, b rnode.Builde
 		return nil, nil
 	}
 
-	outRefs, err := b.OutRefs()
+	refs, err := b.OutRefs()
 	if err != nil {
 		return nil, makeErr("%w", err)
 	}
 
-	return outRefs, nil
+	return refs, nil
 }
@@ -3,6 +3,7 @@ package trclosure
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -302,3 +303,72 @@ func TestTransitiveClosure(t *testing.T) {
 		})
 	}
 }
+
+type countingRateLimiter struct {
+	lock     sync.Mutex
+	accepted map[string]int
+}
+
+func (rl *countingRateLimiter) Accept(ctx context.Context, key *cloud.RateLimitKey) error {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.accepted[key.Service+"/"+key.Operation]++
+	return nil
+}
+
+func (*countingRateLimiter) Observe(context.Context, error, *cloud.RateLimitKey) {}
+
+func TestTransitiveClosureOptions(t *testing.T) {
+	// No t.Parallel() due to use of fake.Mocks.Add().
+	const project = "proj1"
+	mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+
+	fake.Mocks.Clear()
+	g := rgraph.NewBuilder()
+	const fanOut = 200
+
+	root := fake.NewBuilder(fake.ID(project, meta.GlobalKey("root")))
+	root.SetOwnership(rnode.OwnershipManaged)
+	root.SetState(rnode.NodeExists)
+	for i := 0; i < fanOut; i++ {
+		child := fake.NewBuilder(fake.ID(project, meta.GlobalKey(fmt.Sprintf("child-%d", i))))
+		child.SetOwnership(rnode.OwnershipManaged)
+		child.SetState(rnode.NodeExists)
+		fake.Mocks.Add(child)
+		root.FakeOutRefs = append(root.FakeOutRefs, rnode.ResourceRef{From: root.ID(), To: child.ID()})
+	}
+	fake.Mocks.Add(root)
+	g.Add(root)
+
+	rl := &countingRateLimiter{accepted: map[string]int{}}
+	err := Do(context.Background(), mockCloud, g, WorkerCount(20), RateLimiterOption(rl))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if got, want := len(g.All()), fanOut+1; got != want {
+		t.Errorf("len(g.All()) = %d, want %d", got, want)
+	}
+	if diff := cmp.Diff(rl.accepted, map[string]int{"fakes/Get": fanOut + 1}); diff != "" {
+		t.Errorf("rate limiter Accept() calls: -got,+want: %s", diff)
+	}
+
+	// Skipped Nodes are not fetched, but their references are traversed.
+	g = rgraph.NewBuilder()
+	g.Add(root)
+	rl = &countingRateLimiter{accepted: map[string]int{}}
+	skip := func(n rnode.Builder) bool { return n.ID().Equal(root.ID()) }
+	err = Do(context.Background(), mockCloud, g, RateLimiterOption(rl), SkipFetchFunc(skip))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if got, want := len(g.All()), fanOut+1; got != want {
+		t.Errorf("len(g.All()) = %d, want %d", got, want)
+	}
+	if diff := cmp.Diff(rl.accepted, map[string]int{"fakes/Get": fanOut}); diff != "" {
+		t.Errorf("rate limiter Accept() calls with SkipFetchFunc: -got,+want: %s", diff)
+	}
+
+	if err := Do(context.Background(), mockCloud, rgraph.NewBuilder(), WorkerCount(0)); err == nil {
+		t.Errorf("Do(WorkerCount(0)) = nil, want error")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
 // Action is an operation that updates external resources. An Action depends on
@@ -65,6 +66,12 @@ type ActionMetadata struct {
 	Type ActionType
 	// Summary is a human readable description of this action.
 	Summary string
+	// Resource the action operates on. This is nil if the action is not
+	// specific to a resource.
+	Resource *cloud.ResourceID
+	// Version of the API used for Resource. This is "" if the version is
+	// not known.
+	Version meta.Version
 }
 
 // ActionBase is a helper that implements some standard behaviors of common
@@ -119,6 +126,9 @@ func (*eventAction) Signal(Event) bool        { return false }
 func (a *eventAction) String() string         { return fmt.Sprintf("EventAction(%v)", a.events) }
 func (*eventAction) PendingEvents() EventList { return nil }
 
+// Compensation implements Compensator. eventActions have no side effects.
+func (*eventAction) Compensation() (Action, error) { return nil, nil }
+
 func (a *eventAction) DryRun() EventList {
 	return a.events
 }
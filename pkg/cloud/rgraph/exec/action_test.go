@@ -33,6 +33,13 @@ type testAction struct {
 	events  EventList
 	err     error
 	runHook func(context.Context) error
+	// resource is returned in the Metadata.
+	resource *cloud.ResourceID
+	// version is returned in the Metadata.
+	version meta.Version
+	// actionType is returned in the Metadata. The default is
+	// ActionTypeCustom.
+	actionType ActionType
 }
 
 func (a *testAction) String() string {
@@ -53,10 +60,16 @@ func (a *testAction) Run(ctx context.Context, _ cloud.Cloud) (EventList, error)
 }
 
 func (a *testAction) Metadata() *ActionMetadata {
+	actionType := a.actionType
+	if actionType == "" {
+		actionType = ActionTypeCustom
+	}
 	return &ActionMetadata{
-		Name:    fmt.Sprintf("%s(%v)", a.name, a.events),
-		Type:    ActionTypeCustom,
-		Summary: "Action used for testing",
+		Name:     fmt.Sprintf("%s(%v)", a.name, a.events),
+		Type:     actionType,
+		Summary:  "Action used for testing",
+		Resource: a.resource,
+		Version:  a.version,
 	}
 }
 
@@ -20,6 +20,10 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
 )
 
 type Result struct {
@@ -30,20 +34,42 @@ type Result struct {
 	// Pending are Actions that could not be executed due to missing
 	// preconditions.
 	Pending []Action
+	// Compensated are the Actions that were run to revert Completed Actions
+	// after an error (see NewTransactionalExecutor).
+	Compensated []Action
+	// CompensationErrors are the compensations that failed. The changes made
+	// by the Completed Actions were not fully reverted if this is non-empty.
+	CompensationErrors []ActionWithErr
 }
 
 func (r *Result) DeepCopy() *Result {
 	resultCopy := Result{
-		Completed: make([]Action, len(r.Completed)),
-		Pending:   make([]Action, len(r.Pending)),
-		Errors:    make([]ActionWithErr, len(r.Errors)),
+		Completed:          make([]Action, len(r.Completed)),
+		Pending:            make([]Action, len(r.Pending)),
+		Errors:             make([]ActionWithErr, len(r.Errors)),
+		Compensated:        make([]Action, len(r.Compensated)),
+		CompensationErrors: make([]ActionWithErr, len(r.CompensationErrors)),
 	}
 	copy(resultCopy.Completed, r.Completed)
 	copy(resultCopy.Errors, r.Errors)
 	copy(resultCopy.Pending, r.Pending)
+	copy(resultCopy.Compensated, r.Compensated)
+	copy(resultCopy.CompensationErrors, r.CompensationErrors)
 	return &resultCopy
 }
 
+// Remaining returns the Actions that did not complete: the Pending Actions
+// and the Actions that returned an error. An execution that was interrupted
+// (e.g. the context was canceled) can be resumed by passing the Remaining
+// Actions to a new Executor.
+func (r *Result) Remaining() []Action {
+	ret := append([]Action{}, r.Pending...)
+	for _, ae := range r.Errors {
+		ret = append(ret, ae.Action)
+	}
+	return ret
+}
+
 type ActionWithErr struct {
 	Action Action
 	Err    error
@@ -100,10 +126,47 @@ func ErrorStrategyOption(s ErrorStrategy) Option {
 	return func(c *ExecutorConfig) { c.ErrorStrategy = s }
 }
 
+// CancelStrategy determines what happens to the in-flight Actions when the
+// context given to Run() is canceled or the TimeoutOption expires. In all
+// cases, no new Actions will be started.
+type CancelStrategy string
+
+var (
+	// CancelInFlight cancels the context of the in-flight Actions.
+	CancelInFlight CancelStrategy = "CancelInFlight"
+	// WaitForInFlight lets the in-flight Actions run to completion. The
+	// parallel Executor will wait for at most WaitForOrphansTimeoutOption;
+	// Actions that are still running after the timeout are detached and
+	// are not reported in the Result.
+	WaitForInFlight CancelStrategy = "WaitForInFlight"
+)
+
+// CancelStrategyOption sets the handling of in-flight Actions on cancellation.
+func CancelStrategyOption(s CancelStrategy) Option {
+	return func(c *ExecutorConfig) { c.CancelStrategy = s }
+}
+
+// PriorityOption sets the function used to get the priority of an Action.
+// When more than one Action can run, Actions with a higher priority are started
+// first. The default is ActionPriority.
+//
+// The priority only orders the Actions that are ready to run at the same time;
+// it is not a global ordering of the plan. The priority of an Action is not
+// propagated to the Actions it waits for, so a high priority Action does not
+// make its dependencies run earlier. The parallel Executor also does not
+// reorder Actions that were queued before a higher priority Action became
+// ready.
+func PriorityOption(f func(Action) int) Option {
+	return func(c *ExecutorConfig) { c.Priority = f }
+}
+
 func defaultExecutorConfig() *ExecutorConfig {
 	return &ExecutorConfig{
-		DryRun:        false,
-		ErrorStrategy: StopOnError,
+		DryRun:         false,
+		ErrorStrategy:  StopOnError,
+		CancelStrategy: CancelInFlight,
+		Priority:       ActionPriority,
+		LogVerbosity:   DefaultLogVerbosity,
 	}
 }
 
@@ -112,6 +175,15 @@ type ExecutorConfig struct {
 	Tracer                Tracer
 	DryRun                bool
 	ErrorStrategy         ErrorStrategy
+	CancelStrategy        CancelStrategy
+	Priority              func(Action) int
+	Logger                *klog.Logger
+	LogVerbosity          LogVerbosity
+	TracerProvider        trace.TracerProvider
+	TraceParent           trace.SpanContext
+	RateLimiter           cloud.RateLimiter
+	RetryBudget           *RetryBudget
+	Journal               Journal
 	Timeout               time.Duration
 	WaitForOrphansTimeout time.Duration
 }
@@ -122,5 +194,24 @@ func (c *ExecutorConfig) validate() error {
 	default:
 		return fmt.Errorf("invalid ErrorStrategy: %q", c.ErrorStrategy)
 	}
+	switch c.CancelStrategy {
+	case CancelInFlight, WaitForInFlight:
+	default:
+		return fmt.Errorf("invalid CancelStrategy: %q", c.CancelStrategy)
+	}
+	if c.Priority == nil {
+		return fmt.Errorf("Priority func must not be nil")
+	}
 	return nil
 }
+
+// actionContext returns the context to pass to Action.Run().
+func (c *ExecutorConfig) actionContext(ctx context.Context) context.Context {
+	if c.RetryBudget != nil {
+		ctx = withRetryBudget(ctx, c.RetryBudget)
+	}
+	if c.CancelStrategy == WaitForInFlight {
+		return context.WithoutCancel(ctx)
+	}
+	return ctx
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -34,8 +35,11 @@ var (
 
 func defaultParallelExecutorConfig() *ExecutorConfig {
 	return &ExecutorConfig{
-		DryRun:        false,
-		ErrorStrategy: ContinueOnError,
+		DryRun:         false,
+		ErrorStrategy:  ContinueOnError,
+		CancelStrategy: CancelInFlight,
+		Priority:       ActionPriority,
+		LogVerbosity:   DefaultLogVerbosity,
 	}
 }
 
@@ -54,6 +58,9 @@ func NewParallelExecutor(c cloud.Cloud, pending []Action, opts ...Option) (*para
 	if err := ret.config.validate(); err != nil {
 		return nil, err
 	}
+	if ret.config.RateLimiter != nil {
+		ret.pacer = newPacer(ret.config.RateLimiter)
+	}
 	return ret, nil
 }
 
@@ -61,12 +68,16 @@ type parallelExecutor struct {
 	config *ExecutorConfig
 	cloud  cloud.Cloud
 
-	// lock guards results
+	// lock guards results and queued.
 	lock   sync.Mutex
 	result *Result
+	// queued are the Actions that were added to pq but have not started.
+	queued []Action
 
 	pq   *algo.ParallelQueue[Action]
 	done chan *TraceEntry
+	// pacer is nil if RateLimiterOption is not set.
+	pacer *pacer
 }
 
 // parallelExecutor implements Executor.
@@ -79,13 +90,27 @@ var _ Executor = (*parallelExecutor)(nil)
 // routines that are currently executing.
 //
 // To handle timeout properly use TimeoutOption for canceling running actions
-// and WaitForOrphansTimeoutOption for canceling post error cleanup.
+// and WaitForOrphansTimeoutOption for canceling post error cleanup. Use
+// CancelStrategyOption(WaitForInFlight) to let the running actions finish
+// instead. Actions that were not started are returned in Result.Pending.
 func (ex *parallelExecutor) Run(ctx context.Context) (*Result, error) {
+	ctx, span := ex.config.startRunSpan(ctx, "rgraph.ParallelExecutor", len(ex.result.Pending))
+	result, err := ex.runInternal(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (ex *parallelExecutor) runInternal(ctx context.Context) (*Result, error) {
 	ex.queueRunnableActions()
 
 	queueErr := ex.runActionQueue(ctx)
 	if queueErr != nil {
-		waitErr := ex.waitForQueueOrphans(ctx)
+		waitCtx := ctx
+		if ex.config.CancelStrategy == WaitForInFlight {
+			waitCtx = context.WithoutCancel(ctx)
+		}
+		waitErr := ex.waitForQueueOrphans(waitCtx)
+		ex.requeueNotStarted()
 		if waitErr != nil {
 			// Actions might still run and modify the results. Because result is
 			// returned as a pointer we need to deep copy it.
@@ -96,10 +121,21 @@ func (ex *parallelExecutor) Run(ctx context.Context) (*Result, error) {
 		}
 	}
 	if len(ex.result.Errors) > 0 || len(ex.result.Pending) != 0 {
+		if queueErr != nil {
+			return ex.result, fmt.Errorf("%w: %w", ErrPendingActions, queueErr)
+		}
 		return ex.result, ErrPendingActions
 	}
 	return ex.result, nil
+}
 
+// requeueNotStarted returns the Actions that were queued but not started to
+// Pending.
+func (ex *parallelExecutor) requeueNotStarted() {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+	ex.result.Pending = append(ex.result.Pending, ex.queued...)
+	ex.queued = nil
 }
 
 func (ex *parallelExecutor) runActionQueue(ctx context.Context) error {
@@ -127,19 +163,50 @@ func (ex *parallelExecutor) waitForQueueOrphans(ctx context.Context) error {
 }
 
 func (ex *parallelExecutor) runAction(ctx context.Context, a Action) error {
+	ex.lock.Lock()
+	for i, q := range ex.queued {
+		if q == a {
+			ex.queued = append(ex.queued[:i], ex.queued[i+1:]...)
+			break
+		}
+	}
+	ex.lock.Unlock()
+
+	if ex.pacer != nil {
+		if err := ex.pacer.accept(ctx, a); err != nil {
+			ex.lock.Lock()
+			ex.result.Pending = append(ex.result.Pending, a)
+			ex.lock.Unlock()
+			return fmt.Errorf("parallelExecutor: %w", err)
+		}
+	}
+
 	te := &TraceEntry{
 		Action: a,
 		Start:  time.Now(),
 	}
-	klog.V(4).Infof("Run action %s", a)
-	events, runErr := a.Run(ctx, ex.cloud)
+	logger, actx := ex.config.actionLogger(ex.config.actionContext(ctx), a)
+	actx, span := ex.config.startActionSpan(actx, a)
+	ex.config.logActionStart(logger)
+	events, runErr := ex.config.runJournaled(actx, a, func(ctx context.Context) (EventList, error) {
+		return runChecked(ctx, ex.cloud, a)
+	})
 	te.End = time.Now()
-	klog.V(4).Infof("Finish action %s, err: %v", a, runErr)
+	if ex.pacer != nil {
+		ex.pacer.observe(actx, a, runErr)
+	}
+	ex.config.logActionEnd(logger, te, runErr)
+	endSpan(span, runErr)
 
 	ex.addActionResult(a, runErr)
 
 	if runErr != nil {
-		klog.V(2).Infof("Got error  %v, from action %s error_strategy: %s", runErr, a, ex.config.ErrorStrategy)
+		if ex.config.RetryBudget != nil && errors.Is(runErr, ErrRetryBudgetExhausted) {
+			if ex.config.Tracer != nil {
+				ex.config.Tracer.Record(te, runErr)
+			}
+			return fmt.Errorf("parallelExecutor: %w", ex.config.RetryBudget.err())
+		}
 		// check error strategy and decide if new actions should be executed.
 		if ex.config.ErrorStrategy == StopOnError {
 			if ex.config.Tracer != nil {
@@ -167,25 +234,34 @@ func (ex *parallelExecutor) queueRunnableActions() {
 
 	klog.V(4).Infof("queueRunnableActions: %d actions pending", len(ex.result.Pending))
 
-	taskWasRun := false
-	var notRunnable []Action
+	var runnable, notRunnable []Action
 	for _, a := range ex.result.Pending {
 		if a.CanRun() {
-			klog.V(4).Infof("Run task: %s", a)
-			if ok := ex.pq.Add(a); !ok {
-				klog.Errorf("error scheduling task %s: parallel queue is done", a)
-				break
-			}
-			taskWasRun = true
+			runnable = append(runnable, a)
 		} else {
 			notRunnable = append(notRunnable, a)
 		}
 	}
-	klog.V(4).Infof("queueRunnableActions: remaining %d pending actions", len(notRunnable))
-	// update Pending array only if actions were run
-	if taskWasRun {
-		ex.result.Pending = notRunnable
+	// Queue the higher priority actions first.
+	sort.SliceStable(runnable, func(i, j int) bool {
+		return ex.config.Priority(runnable[i]) > ex.config.Priority(runnable[j])
+	})
+	if ex.pacer != nil {
+		runnable = ex.pacer.interleave(runnable, ex.config.Priority)
 	}
+	for i, a := range runnable {
+		klog.V(4).Infof("Run task: %s", a)
+		if ok := ex.pq.Add(a); !ok {
+			// The queue is done (e.g. it was canceled). Keep the
+			// remaining actions as Pending.
+			klog.V(2).Infof("not scheduling task %s: parallel queue is done", a)
+			notRunnable = append(notRunnable, runnable[i:]...)
+			break
+		}
+		ex.queued = append(ex.queued, a)
+	}
+	klog.V(4).Infof("queueRunnableActions: remaining %d pending actions", len(notRunnable))
+	ex.result.Pending = notRunnable
 }
 
 // signal notifies parents that action finished
@@ -19,6 +19,7 @@ package exec
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -110,27 +111,31 @@ func TestParallelExecutorErrorStrategy(t *testing.T) {
 		graph string
 		// pending should be sorted alphabetically for comparison.
 		pending []string
-		errs    []string
+		// completed should be sorted alphabetically for comparison.
+		completed []string
+		errs      []string
 	}{
 		{
-			name:    "linear graph",
-			graph:   "A -> !B -> C -> D -> E",
-			pending: []string{"C", "D", "E"},
-			errs:    []string{"B"},
+			name:      "linear graph",
+			graph:     "A -> !B -> C -> D -> E",
+			pending:   []string{"C", "D", "E"},
+			completed: []string{"A"},
+			errs:      []string{"B"},
 		},
 		{
-			name:    "branched graph",
-			graph:   "A -> !B -> C; A -> D; A -> E; A -> F",
-			pending: []string{"C"},
-			errs:    []string{"B"},
+			name:      "branched graph",
+			graph:     "A -> !B -> C; A -> D; A -> E; A -> F",
+			pending:   []string{"C"},
+			completed: []string{"A", "D", "E", "F"},
+			errs:      []string{"B"},
 		},
 	} {
-		mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj1"})
-		actions := actionsFromGraphStr(tc.graph)
-
 		for _, strategy := range []ErrorStrategy{StopOnError, ContinueOnError} {
 			name := tc.name + " " + string(strategy)
 			t.Run(name, func(t *testing.T) {
+				mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj1"})
+				actions := actionsFromGraphStr(tc.graph)
+
 				ex, err := NewParallelExecutor(mockCloud,
 					actions,
 					ErrorStrategyOption(strategy),
@@ -147,9 +152,33 @@ func TestParallelExecutorErrorStrategy(t *testing.T) {
 				if diff := cmp.Diff(gotErrs, tc.errs); diff != "" {
 					t.Errorf("errors: diff -got,+want: %s", diff)
 				}
-				got := sortedStrings(result.Pending, func(a Action) string { return a.(*testAction).name })
-				if diff := cmp.Diff(got, tc.pending); diff != "" {
-					t.Errorf("pending: diff -got,+want: %s", diff)
+				actionName := func(a Action) string { return a.(*testAction).name }
+				gotPending := sortedStrings(result.Pending, actionName)
+				gotCompleted := sortedStrings(result.Completed, actionName)
+
+				if strategy == ContinueOnError {
+					if diff := cmp.Diff(gotPending, tc.pending); diff != "" {
+						t.Errorf("pending: diff -got,+want: %s", diff)
+					}
+					if diff := cmp.Diff(gotCompleted, tc.completed); diff != "" {
+						t.Errorf("completed: diff -got,+want: %s", diff)
+					}
+					return
+				}
+				// With StopOnError, the Actions running concurrently with
+				// the failing Action are either Completed or Pending
+				// depending on whether they were started before the error.
+				// The Actions depending on the failing Action are always
+				// Pending.
+				gotAll := sortedStrings(append(slices.Clone(result.Pending), result.Completed...), actionName)
+				wantAll := sortedStrings(append(slices.Clone(tc.pending), tc.completed...), func(s string) string { return s })
+				if diff := cmp.Diff(gotAll, wantAll); diff != "" {
+					t.Errorf("pending and completed: diff -got,+want: %s", diff)
+				}
+				for _, p := range tc.pending {
+					if !slices.Contains(gotPending, p) {
+						t.Errorf("pending = %v, want %q in pending", gotPending, p)
+					}
 				}
 			})
 		}
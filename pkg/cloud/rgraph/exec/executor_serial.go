@@ -18,6 +18,7 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -39,15 +40,16 @@ func NewSerialExecutor(c cloud.Cloud, pending []Action, opts ...Option) (*serial
 	if err := ret.config.validate(); err != nil {
 		return nil, err
 	}
+	if ret.config.RateLimiter != nil {
+		ret.pacer = newPacer(ret.config.RateLimiter)
+	}
 
 	if ret.config.DryRun {
 		ret.runFunc = func(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
 			return a.DryRun(), nil
 		}
 	} else {
-		ret.runFunc = func(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
-			return a.Run(ctx, c)
-		}
+		ret.runFunc = runChecked
 	}
 
 	return ret, nil
@@ -59,6 +61,8 @@ type serialExecutor struct {
 	cloud   cloud.Cloud
 	runFunc func(context.Context, cloud.Cloud, Action) (EventList, error)
 	result  *Result
+	// pacer is nil if RateLimiterOption is not set.
+	pacer *pacer
 }
 
 var _ Executor = (*serialExecutor)(nil)
@@ -67,7 +71,8 @@ var _ Executor = (*serialExecutor)(nil)
 //
 // Use TimeoutOption to define timeout for executor to launch new actions.
 // Note that when timeout occurs the executor will block until active action
-// has returned.
+// has returned. The active action's context is canceled unless
+// CancelStrategyOption(WaitForInFlight) is set.
 func (ex *serialExecutor) Run(ctx context.Context) (*Result, error) {
 	if ex.config.Timeout != 0 {
 		var cancel context.CancelFunc
@@ -75,13 +80,30 @@ func (ex *serialExecutor) Run(ctx context.Context) (*Result, error) {
 		ctx, cancel = context.WithTimeout(ctx, ex.config.Timeout)
 		defer cancel()
 	}
-	return ex.runInternal(ctx)
+	ctx, span := ex.config.startRunSpan(ctx, "rgraph.SerialExecutor", len(ex.result.Pending))
+	result, err := ex.runInternal(ctx)
+	endSpan(span, err)
+	return result, err
 }
 
 func (ex *serialExecutor) runInternal(ctx context.Context) (*Result, error) {
-	for a := ex.next(); a != nil; a = ex.next() {
-		err := ex.runAction(ctx, a)
-		if err != nil {
+	for {
+		// Do not start new actions if the context is done. The
+		// remaining actions stay in Pending.
+		if err := ctx.Err(); err != nil {
+			return ex.result, fmt.Errorf("serialExecutor: %w", err)
+		}
+		a := ex.next()
+		if a == nil {
+			break
+		}
+		if ex.pacer != nil {
+			if err := ex.pacer.accept(ctx, a); err != nil {
+				ex.result.Pending = append(ex.result.Pending, a)
+				return ex.result, fmt.Errorf("serialExecutor: %w", err)
+			}
+		}
+		if err := ex.runAction(ctx, a); err != nil {
 			return ex.result, err
 		}
 	}
@@ -96,19 +118,30 @@ func (ex *serialExecutor) runInternal(ctx context.Context) (*Result, error) {
 }
 
 func (ex *serialExecutor) runAction(ctx context.Context, a Action) error {
-	klog.V(4).Infof("runAction %s", a)
-
 	te := &TraceEntry{
 		Action: a,
 		Start:  time.Now(),
 	}
-	events, runErr := ex.runFunc(ctx, ex.cloud, a)
+	logger, actx := ex.config.actionLogger(ex.config.actionContext(ctx), a)
+	actx, span := ex.config.startActionSpan(actx, a)
+	ex.config.logActionStart(logger)
+	events, runErr := ex.config.runJournaled(actx, a, func(ctx context.Context) (EventList, error) {
+		return ex.runFunc(ctx, ex.cloud, a)
+	})
 	te.End = time.Now()
+	if ex.pacer != nil {
+		ex.pacer.observe(actx, a, runErr)
+	}
+	ex.config.logActionEnd(logger, te, runErr)
+	endSpan(span, runErr)
 
 	if runErr == nil {
 		ex.result.Completed = append(ex.result.Completed, a)
 	} else {
 		ex.result.Errors = append(ex.result.Errors, ActionWithErr{Action: a, Err: runErr})
+		if ex.config.RetryBudget != nil && errors.Is(runErr, ErrRetryBudgetExhausted) {
+			return fmt.Errorf("serialExecutor: %w", ex.config.RetryBudget.err())
+		}
 		switch ex.config.ErrorStrategy {
 		case ContinueOnError:
 		case StopOnError:
@@ -125,17 +158,29 @@ func (ex *serialExecutor) runAction(ctx context.Context, a Action) error {
 		ex.config.Tracer.Record(te, runErr)
 	}
 
-	return ctx.Err()
+	return nil
 }
 
+// next returns the runnable Action with the highest priority. Ties are broken
+// by the pacer (if set), then by the order in Pending.
 func (ex *serialExecutor) next() Action {
+	best := -1
+	var bestPriority int
 	for i, a := range ex.result.Pending {
-		if a.CanRun() {
-			ex.result.Pending = append(ex.result.Pending[0:i], ex.result.Pending[i+1:]...)
-			return a
+		if !a.CanRun() {
+			continue
+		}
+		p := ex.config.Priority(a)
+		if best == -1 || p > bestPriority || (p == bestPriority && ex.pacer != nil && ex.pacer.less(a, ex.result.Pending[best])) {
+			best, bestPriority = i, p
 		}
 	}
-	return nil
+	if best == -1 {
+		return nil
+	}
+	a := ex.result.Pending[best]
+	ex.result.Pending = append(ex.result.Pending[0:best], ex.result.Pending[best+1:]...)
+	return a
 }
 
 func (ex *serialExecutor) signal(ev Event) []TraceSignal {
@@ -274,3 +274,69 @@ func TestSerialExecutorTimeoutOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestSerialExecutorPriority(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		graph    string
+		priority map[string]int
+		opts     []Option
+		want     []string
+	}{
+		{
+			name:  "no priorities",
+			graph: "A -> B; A -> C; A -> D",
+			want:  []string{"A", "B", "C", "D"},
+		},
+		{
+			name:     "priorities",
+			graph:    "A -> B; A -> C; A -> D",
+			priority: map[string]int{"C": 10, "D": 5},
+			want:     []string{"A", "C", "D", "B"},
+		},
+		{
+			name:     "dependencies take precedence",
+			graph:    "A -> B -> C",
+			priority: map[string]int{"C": 10},
+			want:     []string{"A", "B", "C"},
+		},
+		{
+			name:  "PriorityOption",
+			graph: "A -> B; A -> C; A -> D",
+			opts: []Option{PriorityOption(func(a Action) int {
+				return int(a.Metadata().Name[0])
+			})},
+			want: []string{"A", "D", "C", "B"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actions []Action
+			// Sort the actions so the order of ties is deterministic.
+			graphActions := actionsFromGraphStr(tc.graph)
+			sort.Slice(graphActions, func(i, j int) bool {
+				return graphActions[i].(*testAction).name < graphActions[j].(*testAction).name
+			})
+			for _, a := range graphActions {
+				if p, ok := tc.priority[a.(*testAction).name]; ok {
+					a = NewPrioritizedAction(a, p)
+				}
+				actions = append(actions, a)
+			}
+			ex, err := NewSerialExecutor(nil, actions, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			var got []string
+			for _, a := range result.Completed {
+				got = append(got, a.Metadata().Name[:1])
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("order: diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package exec
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 // actionsFromGraphStr parses a graph in the form of "A -> B -> C; B -> D" to a
@@ -67,3 +71,85 @@ func actionsFromGraphStr(graphStr string) []Action {
 
 	return actions
 }
+
+func TestExecutorCancel(t *testing.T) {
+	executors := map[string]func([]Action, ...Option) (Executor, error){
+		"serial": func(a []Action, opts ...Option) (Executor, error) {
+			return NewSerialExecutor(nil, a, opts...)
+		},
+		"parallel": func(a []Action, opts ...Option) (Executor, error) {
+			return NewParallelExecutor(nil, a, opts...)
+		},
+	}
+	for exName, newExecutor := range executors {
+		for _, tc := range []struct {
+			name          string
+			strategy      CancelStrategy
+			wantCompleted []string
+			wantRemaining []string
+		}{
+			{
+				name:          "cancel in-flight",
+				strategy:      CancelInFlight,
+				wantRemaining: []string{"A", "B", "C"},
+			},
+			{
+				name:          "wait for in-flight",
+				strategy:      WaitForInFlight,
+				wantCompleted: []string{"A"},
+				wantRemaining: []string{"B", "C"},
+			},
+		} {
+			t.Run(exName+"/"+tc.name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				actions := actionsFromGraphStr("A -> B -> C")
+				for _, a := range actions {
+					if ta := a.(*testAction); ta.name == "A" {
+						// A is in-flight when the context is canceled.
+						ta.runHook = func(ctx context.Context) error {
+							cancel()
+							return ctx.Err()
+						}
+					}
+				}
+				ex, err := newExecutor(actions, CancelStrategyOption(tc.strategy))
+				if err != nil {
+					t.Fatalf("newExecutor() = %v, want nil", err)
+				}
+				result, err := ex.Run(ctx)
+				if err == nil {
+					t.Fatalf("Run() = nil, want error")
+				}
+				names := func(l []Action) []string {
+					return sortedStrings(l, func(a Action) string { return a.(*testAction).name })
+				}
+				if diff := cmp.Diff(names(result.Completed), tc.wantCompleted); diff != "" {
+					t.Errorf("Completed: diff -got,+want: %s", diff)
+				}
+				remaining := result.Remaining()
+				if diff := cmp.Diff(names(remaining), tc.wantRemaining); diff != "" {
+					t.Errorf("Remaining(): diff -got,+want: %s", diff)
+				}
+
+				// Resume the execution with the remaining Actions.
+				for _, a := range remaining {
+					a.(*testAction).runHook = nil
+					a.(*testAction).err = nil
+				}
+				ex, err = newExecutor(remaining)
+				if err != nil {
+					t.Fatalf("newExecutor() = %v, want nil", err)
+				}
+				result, err = ex.Run(context.Background())
+				if err != nil {
+					t.Fatalf("Run() = %v, want nil (result: %+v)", err, result)
+				}
+				if diff := cmp.Diff(names(result.Completed), tc.wantRemaining); diff != "" {
+					t.Errorf("resumed Completed: diff -got,+want: %s", diff)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
+)
+
+// Compensator is implemented by Actions that can be reverted.
+type Compensator interface {
+	// Compensation returns an Action that reverts the changes made by a
+	// successful Run(). Returns nil if the Action does not have side effects
+	// that need to be reverted.
+	Compensation() (Action, error)
+}
+
+// NewTransactionalExecutor returns a new Executor that either completes all of
+// the Actions or reverts the changes that were made. If an Action fails, the
+// compensating Actions for the Completed Actions are run in reverse order. The
+// compensations that were run are reported in Result.Compensated and
+// Result.CompensationErrors.
+//
+// All Actions must implement Compensator. The Actions are executed serially
+// with the StopOnError strategy.
+func NewTransactionalExecutor(c cloud.Cloud, pending []Action, opts ...Option) (*transactionalExecutor, error) {
+	if err := CheckCompensations(pending); err != nil {
+		return nil, fmt.Errorf("transactionalExecutor: %w", err)
+	}
+	opts = append(opts, ErrorStrategyOption(StopOnError))
+	inner, err := NewSerialExecutor(c, pending, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionalExecutor{inner: inner}, nil
+}
+
+type transactionalExecutor struct {
+	inner *serialExecutor
+}
+
+var _ Executor = (*transactionalExecutor)(nil)
+
+// Run the Actions. If there was an error, the returned Result will contain
+// the compensations that were run. An error is returned in both cases; it will
+// wrap the compensation errors if the original state could not be restored.
+func (ex *transactionalExecutor) Run(ctx context.Context) (*Result, error) {
+	ctx, span := ex.inner.config.startRunSpan(ctx, "rgraph.TransactionalExecutor", len(ex.inner.result.Pending))
+	result, err := ex.runInternal(ctx)
+	endSpan(span, err)
+	return result, err
+}
+
+func (ex *transactionalExecutor) runInternal(ctx context.Context) (*Result, error) {
+	result, runErr := ex.inner.Run(ctx)
+	if runErr == nil {
+		return result, nil
+	}
+
+	// The compensations must run even if ctx was the cause of the error.
+	compCtx := context.WithoutCancel(ctx)
+	for i := len(result.Completed) - 1; i >= 0; i-- {
+		a := result.Completed[i]
+		comp, err := compensation(a)
+		if err != nil {
+			result.CompensationErrors = append(result.CompensationErrors, ActionWithErr{Action: a, Err: err})
+			continue
+		}
+		if comp == nil {
+			continue
+		}
+		logger, actx := ex.inner.config.actionLogger(compCtx, comp)
+		logger = logger.WithValues("compensates", a.Metadata().Name)
+		if ex.inner.config.DryRun {
+			comp.DryRun()
+		} else {
+			te := &TraceEntry{Action: comp, Start: time.Now()}
+			actx, span := ex.inner.config.startActionSpan(klog.NewContext(actx, logger), comp)
+			ex.inner.config.logActionStart(logger)
+			_, err := comp.Run(actx, ex.inner.cloud)
+			te.End = time.Now()
+			ex.inner.config.logActionEnd(logger, te, err)
+			endSpan(span, err)
+			if err != nil {
+				result.CompensationErrors = append(result.CompensationErrors, ActionWithErr{Action: comp, Err: err})
+				continue
+			}
+		}
+		result.Compensated = append(result.Compensated, comp)
+	}
+
+	if len(result.CompensationErrors) > 0 {
+		return result, fmt.Errorf("transactionalExecutor: %w; could not revert changes: %v", runErr, result.CompensationErrors)
+	}
+	return result, fmt.Errorf("transactionalExecutor: changes reverted: %w", runErr)
+}
+
+var errNoCompensation = errors.New("action cannot be compensated")
+
+// CheckCompensations returns an error if any of the Actions cannot be
+// compensated, i.e. the Actions cannot be run with NewTransactionalExecutor.
+func CheckCompensations(actions []Action) error {
+	for _, a := range actions {
+		if _, err := compensation(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compensation returns the compensating Action for a.
+func compensation(a Action) (Action, error) {
+	c, ok := a.(Compensator)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", a, errNoCompensation)
+	}
+	return c.Compensation()
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// compensatingAction is a testAction that can be compensated. The
+// compensations that are run are appended to log.
+type compensatingAction struct {
+	*testAction
+	log     *[]string
+	compErr error
+}
+
+func (a *compensatingAction) Compensation() (Action, error) {
+	name := "undo-" + a.name
+	return &testAction{
+		name: name,
+		runHook: func(context.Context) error {
+			*a.log = append(*a.log, name)
+			return a.compErr
+		},
+	}, nil
+}
+
+func TestTransactionalExecutor(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// graph is given to actionsFromGraphStr().
+		graph string
+		// noComp is the set of Actions that cannot be compensated.
+		noComp map[string]bool
+		// compErr is the set of Actions with compensations that fail.
+		compErr map[string]bool
+
+		wantNewErr          bool
+		wantErr             bool
+		wantLog             []string
+		wantCompErrs        []string
+		wantCompletedSorted []string
+	}{
+		{
+			name:                "no errors",
+			graph:               "A -> B -> C",
+			wantCompletedSorted: []string{"A", "B", "C"},
+		},
+		{
+			name:                "error reverts in reverse order",
+			graph:               "A -> B -> !C -> D",
+			wantErr:             true,
+			wantLog:             []string{"undo-B", "undo-A"},
+			wantCompletedSorted: []string{"A", "B"},
+		},
+		{
+			name:                "error in first action",
+			graph:               "!A -> B",
+			wantErr:             true,
+			wantCompletedSorted: nil,
+		},
+		{
+			name:                "compensation error",
+			graph:               "A -> B -> !C",
+			compErr:             map[string]bool{"B": true},
+			wantErr:             true,
+			wantLog:             []string{"undo-B", "undo-A"},
+			wantCompErrs:        []string{"undo-B"},
+			wantCompletedSorted: []string{"A", "B"},
+		},
+		{
+			name:       "action cannot be compensated",
+			graph:      "A -> B",
+			noComp:     map[string]bool{"B": true},
+			wantNewErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var log []string
+			var actions []Action
+			for _, a := range actionsFromGraphStr(tc.graph) {
+				ta := a.(*testAction)
+				if tc.noComp[ta.name] {
+					actions = append(actions, ta)
+					continue
+				}
+				ca := &compensatingAction{testAction: ta, log: &log}
+				if tc.compErr[ta.name] {
+					ca.compErr = errors.New("injected")
+				}
+				actions = append(actions, ca)
+			}
+
+			ex, err := NewTransactionalExecutor(nil, actions)
+			if gotErr := err != nil; gotErr != tc.wantNewErr {
+				t.Fatalf("NewTransactionalExecutor() = %v; gotErr = %t, want %t", err, gotErr, tc.wantNewErr)
+			}
+			if tc.wantNewErr {
+				return
+			}
+			result, err := ex.Run(context.Background())
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if diff := cmp.Diff(log, tc.wantLog); diff != "" {
+				t.Errorf("compensation log: diff -got,+want: %s", diff)
+			}
+			var compErrs []string
+			for _, ae := range result.CompensationErrors {
+				compErrs = append(compErrs, ae.Action.(*testAction).name)
+			}
+			if diff := cmp.Diff(compErrs, tc.wantCompErrs); diff != "" {
+				t.Errorf("CompensationErrors: diff -got,+want: %s", diff)
+			}
+			if got, want := len(result.Compensated)+len(result.CompensationErrors), len(tc.wantLog); got != want {
+				t.Errorf("len(Compensated)+len(CompensationErrors) = %d, want %d", got, want)
+			}
+			var completed []string
+			for _, a := range result.Completed {
+				completed = append(completed, a.(*compensatingAction).name)
+			}
+			sort.Strings(completed)
+			if diff := cmp.Diff(completed, tc.wantCompletedSorted); diff != "" {
+				t.Errorf("Completed: diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
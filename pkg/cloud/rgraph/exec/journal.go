@@ -0,0 +1,273 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
+)
+
+// Journal persists the progress of an execution. The Executor appends an
+// entry when an Action starts, when the Action starts a long running GCE
+// operation and when the Action ends. A controller that restarts after a crash
+// can use InFlight() to find the Actions (and operations) that were started
+// but not finished.
+//
+// Implementations must be safe for concurrent use. Entries should be durable
+// by the time Append returns.
+type Journal interface {
+	// Append the entry to the Journal.
+	Append(ctx context.Context, e *JournalEntry) error
+	// Entries returns all of the entries in the order they were appended.
+	Entries(ctx context.Context) ([]JournalEntry, error)
+}
+
+// JournalEntryType is the type of the JournalEntry.
+type JournalEntryType string
+
+var (
+	// JournalStart is appended before the Action is run.
+	JournalStart JournalEntryType = "Start"
+	// JournalOperation is appended when the Action has started a long
+	// running operation.
+	JournalOperation JournalEntryType = "Operation"
+	// JournalEnd is appended when the Action returns.
+	JournalEnd JournalEntryType = "End"
+)
+
+// JournalEntry is a single entry in the Journal.
+type JournalEntry struct {
+	Type JournalEntryType `json:"type"`
+	Time time.Time        `json:"time"`
+	// Action is the ActionMetadata.Name.
+	Action     string     `json:"action"`
+	ActionType ActionType `json:"actionType"`
+	// Resource is the ResourceID.String() of the resource the Action
+	// operates on. This is empty if there is no resource.
+	Resource string `json:"resource,omitempty"`
+	// Operation is the URL of the operation (JournalOperation only).
+	Operation string `json:"operation,omitempty"`
+	// Error returned by the Action (JournalEnd only).
+	Error string `json:"error,omitempty"`
+}
+
+// JournalOption sets the Journal used to record the execution. Actions are
+// not journaled in dry run mode.
+func JournalOption(j Journal) Option {
+	return func(c *ExecutorConfig) { c.Journal = j }
+}
+
+// InFlightAction is an Action that was started but did not end according to
+// the Journal.
+type InFlightAction struct {
+	Action     string
+	ActionType ActionType
+	Resource   string
+	Start      time.Time
+	// Operations started by the Action. These should be checked for
+	// completion before the resource is planned again.
+	Operations []string
+}
+
+// InFlight returns the Actions in the entries that started but did not end
+// (e.g. the process crashed during the execution). The Actions are returned in
+// the order that they were started.
+func InFlight(entries []JournalEntry) []InFlightAction {
+	var ret []*InFlightAction
+	started := map[string]*InFlightAction{}
+	for _, e := range entries {
+		switch e.Type {
+		case JournalStart:
+			ifa := &InFlightAction{Action: e.Action, ActionType: e.ActionType, Resource: e.Resource, Start: e.Time}
+			started[e.Action] = ifa
+			ret = append(ret, ifa)
+		case JournalOperation:
+			if ifa, ok := started[e.Action]; ok {
+				ifa.Operations = append(ifa.Operations, e.Operation)
+			}
+		case JournalEnd:
+			delete(started, e.Action)
+		}
+	}
+	var out []InFlightAction
+	for _, ifa := range ret {
+		// Only the Actions that are still in started did not end. An Action
+		// that is retried will have a new entry in started.
+		if started[ifa.Action] == ifa {
+			out = append(out, *ifa)
+		}
+	}
+	return out
+}
+
+// runJournaled calls run() for Action a, recording the start, operations and
+// end in the Journal (if set). An error appending the start entry is returned
+// without running the Action.
+func (c *ExecutorConfig) runJournaled(ctx context.Context, a Action, run func(context.Context) (EventList, error)) (EventList, error) {
+	if c.Journal == nil || c.DryRun {
+		return run(ctx)
+	}
+
+	md := a.Metadata()
+	newEntry := func(t JournalEntryType) *JournalEntry {
+		e := &JournalEntry{Type: t, Time: time.Now(), Action: md.Name, ActionType: md.Type}
+		if md.Resource != nil {
+			e.Resource = md.Resource.String()
+		}
+		return e
+	}
+
+	if err := c.Journal.Append(ctx, newEntry(JournalStart)); err != nil {
+		return nil, fmt.Errorf("journal start of %s: %w", md.Name, err)
+	}
+	obs := &journalObserver{newEntry: newEntry, journal: c.Journal}
+	events, runErr := run(cloud.WithOperationObserver(ctx, obs))
+
+	end := newEntry(JournalEnd)
+	if runErr != nil {
+		end.Error = runErr.Error()
+	}
+	if err := c.Journal.Append(ctx, end); err != nil {
+		// The Action has already run, the result must be reported to the
+		// caller regardless.
+		klog.FromContext(ctx).Error(err, "Journal end of Action")
+	}
+	return events, runErr
+}
+
+// journalObserver appends the operations started by an Action to the Journal.
+type journalObserver struct {
+	newEntry func(JournalEntryType) *JournalEntry
+	journal  Journal
+}
+
+func (o *journalObserver) OperationStarted(ctx context.Context, url string) {
+	e := o.newEntry(JournalOperation)
+	e.Operation = url
+	if err := o.journal.Append(ctx, e); err != nil {
+		klog.FromContext(ctx).Error(err, "Journal operation", "operation", url)
+	}
+}
+
+// MemoryJournal is a Journal that is kept in memory. This is useful for
+// testing and for keeping the execution history of a single process.
+type MemoryJournal struct {
+	lock    sync.Mutex
+	entries []JournalEntry
+}
+
+// Append implements Journal.
+func (j *MemoryJournal) Append(_ context.Context, e *JournalEntry) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.entries = append(j.entries, *e)
+	return nil
+}
+
+// Entries implements Journal.
+func (j *MemoryJournal) Entries(context.Context) ([]JournalEntry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return append([]JournalEntry{}, j.entries...), nil
+}
+
+// NewFileJournal returns a Journal that appends the entries as JSON lines to
+// the file at path. The file is created if it does not exist.
+func NewFileJournal(path string) *FileJournal {
+	return &FileJournal{path: path}
+}
+
+// FileJournal stores the entries in a local file.
+type FileJournal struct {
+	lock sync.Mutex
+	path string
+}
+
+// Append implements Journal. The file is synced before returning.
+func (j *FileJournal) Append(_ context.Context, e *JournalEntry) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("FileJournal: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("FileJournal: %w", err)
+	}
+	_, err = f.Write(append(b, '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("FileJournal: %w", err)
+	}
+	return nil
+}
+
+// Entries implements Journal. Returns no entries if the file does not exist. An
+// incomplete last line (e.g. due to a crash during Append) is ignored.
+func (j *FileJournal) Entries(context.Context) ([]JournalEntry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("FileJournal: %w", err)
+	}
+	defer f.Close()
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte{}, scanner.Bytes()...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("FileJournal: %w", err)
+	}
+
+	var ret []JournalEntry
+	for i, line := range lines {
+		var e JournalEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			if i == len(lines)-1 {
+				// The last write may have been interrupted by a crash.
+				klog.Warningf("FileJournal: %s: ignoring incomplete last entry: %v", j.path, err)
+				break
+			}
+			return nil, fmt.Errorf("FileJournal: %s line %d: %w", j.path, i+1, err)
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
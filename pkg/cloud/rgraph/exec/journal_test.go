@@ -0,0 +1,231 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/api/compute/v1"
+)
+
+const testOpURL = "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"
+
+// startOperation simulates an Action that starts a long running operation.
+func startOperation(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// Cancel so that WaitForCompletion does not poll the operation.
+	cancel()
+	(&cloud.Service{}).WaitForCompletion(ctx, &compute.Operation{SelfLink: testOpURL})
+	return nil
+}
+
+func TestExecutorJournal(t *testing.T) {
+	id := &cloud.ResourceID{Resource: "addresses", ProjectID: "proj", Key: meta.GlobalKey("a")}
+
+	for _, tc := range []struct {
+		name        string
+		newExecutor func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			newExecutor: func(acts []Action, opts ...Option) (Executor, error) {
+				return NewSerialExecutor(nil, acts, append(opts, ErrorStrategyOption(ContinueOnError))...)
+			},
+		},
+		{
+			name: "parallel",
+			newExecutor: func(acts []Action, opts ...Option) (Executor, error) {
+				return NewParallelExecutor(nil, acts, opts...)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &testAction{name: "A", runHook: startOperation, resource: id}
+			b := &testAction{name: "B", err: errors.New("injected")}
+			j := &MemoryJournal{}
+			ex, err := tc.newExecutor([]Action{a, b}, JournalOption(j))
+			if err != nil {
+				t.Fatalf("newExecutor() = %v, want nil", err)
+			}
+			if _, err := ex.Run(context.Background()); err == nil {
+				t.Fatalf("Run() = nil, want error")
+			}
+
+			entries, err := j.Entries(context.Background())
+			if err != nil {
+				t.Fatalf("Entries() = %v, want nil", err)
+			}
+			got := map[string][]JournalEntry{}
+			for _, e := range entries {
+				if e.Time.IsZero() {
+					t.Errorf("entry %+v has no Time", e)
+				}
+				got[e.Action] = append(got[e.Action], e)
+			}
+			want := map[string][]JournalEntry{
+				"A([])": {
+					{Type: JournalStart, Action: "A([])", ActionType: ActionTypeCustom, Resource: id.String()},
+					{Type: JournalOperation, Action: "A([])", ActionType: ActionTypeCustom, Resource: id.String(), Operation: testOpURL},
+					{Type: JournalEnd, Action: "A([])", ActionType: ActionTypeCustom, Resource: id.String()},
+				},
+				"B([])": {
+					{Type: JournalStart, Action: "B([])", ActionType: ActionTypeCustom},
+					{Type: JournalEnd, Action: "B([])", ActionType: ActionTypeCustom, Error: "injected"},
+				},
+			}
+			if diff := cmp.Diff(got, want, cmpopts.IgnoreFields(JournalEntry{}, "Time")); diff != "" {
+				t.Errorf("entries: -got,+want: %s", diff)
+			}
+			if ifa := InFlight(entries); len(ifa) != 0 {
+				t.Errorf("InFlight() = %+v, want none", ifa)
+			}
+		})
+	}
+}
+
+type errJournal struct{ MemoryJournal }
+
+func (*errJournal) Append(context.Context, *JournalEntry) error { return errors.New("injected") }
+
+func TestExecutorJournalError(t *testing.T) {
+	ran := false
+	a := &testAction{name: "A", runHook: func(context.Context) error { ran = true; return nil }}
+	ex, err := NewSerialExecutor(nil, []Action{a}, JournalOption(&errJournal{}))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background())
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if ran {
+		t.Errorf("Action was run, but the start could not be journaled")
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("len(result.Errors) = %d, want 1", len(result.Errors))
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	for _, tc := range []struct {
+		name    string
+		entries []JournalEntry
+		want    []InFlightAction
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "all ended",
+			entries: []JournalEntry{
+				{Type: JournalStart, Action: "A"},
+				{Type: JournalStart, Action: "B"},
+				{Type: JournalEnd, Action: "B"},
+				{Type: JournalEnd, Action: "A"},
+			},
+		},
+		{
+			name: "in flight with operation",
+			entries: []JournalEntry{
+				{Type: JournalStart, Action: "A"},
+				{Type: JournalEnd, Action: "A"},
+				{Type: JournalStart, Action: "B", ActionType: ActionTypeCreate, Resource: "r", Time: t0},
+				{Type: JournalOperation, Action: "B", Operation: "op-1"},
+				{Type: JournalStart, Action: "C"},
+			},
+			want: []InFlightAction{
+				{Action: "B", ActionType: ActionTypeCreate, Resource: "r", Start: t0, Operations: []string{"op-1"}},
+				{Action: "C"},
+			},
+		},
+		{
+			name: "restarted Action",
+			entries: []JournalEntry{
+				{Type: JournalStart, Action: "A", Time: t0},
+				{Type: JournalOperation, Action: "A", Operation: "op-1"},
+				{Type: JournalEnd, Action: "A", Error: "err"},
+				{Type: JournalStart, Action: "A", Time: t0.Add(time.Second)},
+				{Type: JournalOperation, Action: "A", Operation: "op-2"},
+			},
+			want: []InFlightAction{
+				{Action: "A", Start: t0.Add(time.Second), Operations: []string{"op-2"}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(InFlight(tc.entries), tc.want); diff != "" {
+				t.Errorf("InFlight(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFileJournal(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal")
+	j := NewFileJournal(path)
+
+	entries, err := j.Entries(ctx)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Entries() = %v, %v; want [], nil", entries, err)
+	}
+
+	want := []JournalEntry{
+		{Type: JournalStart, Time: time.Unix(1000, 0).UTC(), Action: "A", ActionType: ActionTypeCreate, Resource: "r"},
+		{Type: JournalOperation, Time: time.Unix(1001, 0).UTC(), Action: "A", ActionType: ActionTypeCreate, Resource: "r", Operation: "op-1"},
+	}
+	for i := range want {
+		if err := j.Append(ctx, &want[i]); err != nil {
+			t.Fatalf("Append() = %v, want nil", err)
+		}
+	}
+	// A new FileJournal for the same path (e.g. after a restart) sees the
+	// same entries.
+	entries, err = NewFileJournal(path).Entries(ctx)
+	if err != nil {
+		t.Fatalf("Entries() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(entries, want); diff != "" {
+		t.Errorf("Entries(): -got,+want: %s", diff)
+	}
+
+	// Simulate a crash in the middle of writing an entry.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"type":"End","act`)
+	f.Close()
+
+	entries, err = j.Entries(ctx)
+	if err != nil {
+		t.Fatalf("Entries() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(entries, want); diff != "" {
+		t.Errorf("Entries() after crash: -got,+want: %s", diff)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// LogVerbosity are the klog verbosity levels used by the Executor to log the
+// execution of Actions.
+type LogVerbosity struct {
+	// Start of an Action.
+	Start int
+	// End of an Action that completed successfully.
+	End int
+	// Error returned by an Action.
+	Error int
+}
+
+// DefaultLogVerbosity is used by the Executors unless LogVerbosityOption is
+// set.
+var DefaultLogVerbosity = LogVerbosity{Start: 4, End: 4, Error: 2}
+
+// LoggerOption sets the logger used by the Executor. By default, the logger
+// is taken from the context given to Run() (see klog.FromContext).
+//
+// Each Action is logged with the key/value pairs "action", "op" and "key"
+// (the resource, if any). The logger is passed to Action.Run() in the context
+// and can be retrieved with klog.FromContext().
+func LoggerOption(logger klog.Logger) Option {
+	return func(c *ExecutorConfig) { c.Logger = &logger }
+}
+
+// LogVerbosityOption sets the verbosity of the log messages for the execution
+// of Actions.
+func LogVerbosityOption(v LogVerbosity) Option {
+	return func(c *ExecutorConfig) { c.LogVerbosity = v }
+}
+
+// actionLogger returns the logger for the Action with the key/values for the
+// Action and a context containing the logger.
+func (c *ExecutorConfig) actionLogger(ctx context.Context, a Action) (klog.Logger, context.Context) {
+	logger := klog.FromContext(ctx)
+	if c.Logger != nil {
+		logger = *c.Logger
+	}
+	logger = logger.WithValues(actionKeysAndValues(a)...)
+	return logger, klog.NewContext(ctx, logger)
+}
+
+// actionKeysAndValues for structured logging of the Action.
+func actionKeysAndValues(a Action) []any {
+	md := a.Metadata()
+	kv := []any{"action", md.Name, "op", md.Type}
+	if md.Resource != nil {
+		kv = append(kv, "key", md.Resource)
+	}
+	return kv
+}
+
+// logActionStart logs the start of an Action.
+func (c *ExecutorConfig) logActionStart(logger klog.Logger) {
+	logger.V(c.LogVerbosity.Start).Info("Action start")
+}
+
+// logActionEnd logs the result of an Action.
+func (c *ExecutorConfig) logActionEnd(logger klog.Logger, te *TraceEntry, err error) {
+	duration := te.End.Sub(te.Start).Round(time.Millisecond)
+	if err != nil {
+		logger.V(c.LogVerbosity.Error).Info("Action error", "err", err, "duration", duration, "errorStrategy", c.ErrorStrategy)
+		return
+	}
+	logger.V(c.LogVerbosity.End).Info("Action end", "duration", duration)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/klog/v2"
+)
+
+// testLogger records the messages logged.
+type testLogger struct {
+	lock  sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) logger(verbosity int) klog.Logger {
+	return funcr.New(func(prefix, args string) {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.lines = append(l.lines, args)
+	}, funcr.Options{Verbosity: verbosity})
+}
+
+// count the lines that contain all of the substrings.
+func (l *testLogger) count(substrs ...string) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	var n int
+	for _, line := range l.lines {
+		match := true
+		for _, s := range substrs {
+			match = match && strings.Contains(line, s)
+		}
+		if match {
+			n++
+		}
+	}
+	return n
+}
+
+func TestExecutorLogging(t *testing.T) {
+	type counts struct{ Start, End, Error int }
+	for _, tc := range []struct {
+		name      string
+		verbosity int
+		opts      []Option
+		want      counts
+	}{
+		{
+			name:      "default verbosity",
+			verbosity: 4,
+			want:      counts{Start: 3, End: 2, Error: 1},
+		},
+		{
+			name:      "only errors",
+			verbosity: 2,
+			want:      counts{Error: 1},
+		},
+		{
+			name:      "LogVerbosityOption",
+			verbosity: 0,
+			opts:      []Option{LogVerbosityOption(LogVerbosity{Start: 1, End: 0, Error: 0})},
+			want:      counts{End: 2, Error: 1},
+		},
+	} {
+		for _, newEx := range []struct {
+			name string
+			f    func([]Action, ...Option) (Executor, error)
+		}{
+			{"serial", func(a []Action, o ...Option) (Executor, error) { return NewSerialExecutor(nil, a, o...) }},
+			{"parallel", func(a []Action, o ...Option) (Executor, error) { return NewParallelExecutor(nil, a, o...) }},
+		} {
+			t.Run(tc.name+"/"+newEx.name, func(t *testing.T) {
+				var tl testLogger
+				opts := append([]Option{
+					LoggerOption(tl.logger(tc.verbosity)),
+					ErrorStrategyOption(ContinueOnError),
+				}, tc.opts...)
+				ex, err := newEx.f(actionsFromGraphStr("A -> B; !C"), opts...)
+				if err != nil {
+					t.Fatalf("New() = %v, want nil", err)
+				}
+				ex.Run(context.Background())
+
+				got := counts{
+					Start: tl.count(`"msg"="Action start"`, `"op"="Custom"`),
+					End:   tl.count(`"msg"="Action end"`, `"duration"=`),
+					Error: tl.count(`"msg"="Action error"`, `"action"="C(`, `"err"="injected"`),
+				}
+				if diff := cmp.Diff(got, tc.want); diff != "" {
+					t.Errorf("log lines: -got,+want: %s\n%s", diff, strings.Join(tl.lines, "\n"))
+				}
+			})
+		}
+	}
+}
+
+func TestRetriableActionLogging(t *testing.T) {
+	var tl testLogger
+	fa := &fakeAction{errorRunThreshold: 3}
+	frp := &fakeRetryProvider{shouldRetry: true, backOff: time.Millisecond}
+	a := NewRetriableAction(fa, frp.IsRetriable)
+
+	ctx := klog.NewContext(context.Background(), tl.logger(2))
+	if _, err := a.Run(ctx, nil); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	for _, attempt := range []string{`"attempt"=1`, `"attempt"=2`} {
+		if got := tl.count(`"msg"="Action will be retried"`, attempt); got != 1 {
+			t.Errorf("retry log lines with %s = %d, want 1\n%s", attempt, got, strings.Join(tl.lines, "\n"))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+
+// Attribute keys for the OpenTelemetry spans.
+const (
+	AttrAction   = attribute.Key("rgraph.action")
+	AttrOp       = attribute.Key("rgraph.op")
+	AttrResource = attribute.Key("rgraph.resource")
+	AttrProject  = attribute.Key("rgraph.project")
+	AttrActions  = attribute.Key("rgraph.actions")
+)
+
+// TracerProviderOption sets the OpenTelemetry TracerProvider used to emit
+// spans for the execution. The default is the global TracerProvider
+// (otel.GetTracerProvider()).
+//
+// The Executor emits a span for Run() with a child span for each Action.
+func TracerProviderOption(tp trace.TracerProvider) Option {
+	return func(c *ExecutorConfig) { c.TracerProvider = tp }
+}
+
+// TraceParentOption sets the parent of the spans emitted by the Executor if
+// the context given to Run() does not contain a span. Use this to link the
+// execution to the trace of the plan, e.g.
+// TraceParentOption(planResult.SpanContext).
+func TraceParentOption(sc trace.SpanContext) Option {
+	return func(c *ExecutorConfig) { c.TraceParent = sc }
+}
+
+func (c *ExecutorConfig) tracer() trace.Tracer {
+	tp := c.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(instrumentationName)
+}
+
+// startRunSpan starts the span for the execution of the Executor.
+func (c *ExecutorConfig) startRunSpan(ctx context.Context, name string, pending int) (context.Context, trace.Span) {
+	if c.TraceParent.IsValid() && !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, c.TraceParent)
+	}
+	return c.tracer().Start(ctx, name, trace.WithAttributes(AttrActions.Int(pending)))
+}
+
+// startActionSpan starts the span for executing the Action.
+func (c *ExecutorConfig) startActionSpan(ctx context.Context, a Action) (context.Context, trace.Span) {
+	md := a.Metadata()
+	attrs := []attribute.KeyValue{
+		AttrAction.String(md.Name),
+		AttrOp.String(string(md.Type)),
+	}
+	if md.Resource != nil {
+		attrs = append(attrs, AttrResource.String(md.Resource.String()), AttrProject.String(md.Resource.ProjectID))
+	}
+	return c.tracer().Start(ctx, "rgraph.Action", trace.WithAttributes(attrs...))
+}
+
+// endSpan ends the span, recording err.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
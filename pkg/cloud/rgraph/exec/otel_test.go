@@ -0,0 +1,93 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/tracerec"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExecutorTracing(t *testing.T) {
+	for _, newEx := range []struct {
+		name    string
+		runSpan string
+		f       func([]Action, ...Option) (Executor, error)
+	}{
+		{"serial", "rgraph.SerialExecutor", func(a []Action, o ...Option) (Executor, error) { return NewSerialExecutor(nil, a, o...) }},
+		{"parallel", "rgraph.ParallelExecutor", func(a []Action, o ...Option) (Executor, error) { return NewParallelExecutor(nil, a, o...) }},
+	} {
+		t.Run(newEx.name, func(t *testing.T) {
+			parent := trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{1},
+				TraceFlags: trace.FlagsSampled,
+			})
+			tp := tracerec.New()
+			ex, err := newEx.f(actionsFromGraphStr("A -> B; !C"),
+				TracerProviderOption(tp),
+				TraceParentOption(parent),
+				ErrorStrategyOption(ContinueOnError),
+			)
+			if err != nil {
+				t.Fatalf("New() = %v, want nil", err)
+			}
+			ex.Run(context.Background())
+
+			runSpans := tp.Find(newEx.runSpan)
+			if len(runSpans) != 1 {
+				t.Fatalf("len(Find(%q)) = %d, want 1", newEx.runSpan, len(runSpans))
+			}
+			run := runSpans[0]
+			if !run.Parent().Equal(parent) {
+				t.Errorf("run span Parent() = %v, want %v", run.Parent(), parent)
+			}
+			if run.Status() != codes.Error || !run.Ended() {
+				t.Errorf("run span Status() = %v, Ended() = %t; want Error, true", run.Status(), run.Ended())
+			}
+
+			actionSpans := tp.Find("rgraph.Action")
+			if len(actionSpans) != 3 {
+				t.Fatalf("len(Find(rgraph.Action)) = %d, want 3", len(actionSpans))
+			}
+			var errs int
+			for _, s := range actionSpans {
+				if !s.Parent().Equal(run.SpanContext()) {
+					t.Errorf("action span Parent() = %v, want %v", s.Parent(), run.SpanContext())
+				}
+				if s.SpanContext().TraceID() != parent.TraceID() {
+					t.Errorf("action span TraceID() = %v, want %v", s.SpanContext().TraceID(), parent.TraceID())
+				}
+				if v, ok := s.Attr(AttrOp); !ok || v.AsString() != string(ActionTypeCustom) {
+					t.Errorf("action span Attr(%s) = %v, %t; want %s", AttrOp, v, ok, ActionTypeCustom)
+				}
+				if s.Status() == codes.Error {
+					errs++
+					if v, _ := s.Attr(AttrAction); v.AsString()[0] != 'C' {
+						t.Errorf("action span with error has Attr(%s) = %v, want C", AttrAction, v)
+					}
+				}
+			}
+			if errs != 1 {
+				t.Errorf("got %d action spans with errors, want 1", errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// RateLimiterOption paces the execution of Actions using rl. The Executor
+// calls rl.Accept() before dispatching each Action that operates on a
+// resource and rl.Observe() with the result of the Action. The RateLimitKey
+// is the same as the one of the API call made by the Action in the
+// generated code, so limits registered with e.g.
+// CompositeRateLimiter.Register("BackendServices", "Insert", ...) apply to
+// the Actions:
+//
+//   - Service is the name of the service for the resource and its scope
+//     (e.g. "BackendServices" or "RegionBackendServices").
+//   - Operation is "Insert" for ActionTypeCreate, "Delete" for
+//     ActionTypeDelete and "Update" (or "Patch" if the service has no
+//     Update) for ActionTypeUpdate. Other ActionTypes are used as is.
+//   - Version is the API version used for the resource (see
+//     ActionMetadata.Version).
+//
+// In addition, runnable Actions with the same priority are interleaved by
+// resource type instead of dispatching all of the Actions for one type of
+// resource first. This avoids stalling on the quota for a single resource
+// type while Actions for other types could make progress.
+//
+// Note: the API calls made by the Actions are still rate limited by the
+// cloud.Cloud. Use a RateLimiter for the Executor that accounts for this
+// (e.g. a separate limit per ActionType).
+func RateLimiterOption(rl cloud.RateLimiter) Option {
+	return func(c *ExecutorConfig) { c.RateLimiter = rl }
+}
+
+// pacer tracks the dispatch of Actions by resource type.
+type pacer struct {
+	rl cloud.RateLimiter
+
+	lock sync.Mutex
+	// seq is incremented for each dispatched Action.
+	seq int
+	// last is the seq of the last dispatch for the resource type.
+	last map[string]int
+}
+
+func newPacer(rl cloud.RateLimiter) *pacer {
+	return &pacer{rl: rl, last: map[string]int{}}
+}
+
+// resourceType of the Action for the purposes of pacing. Actions that are
+// not specific to a resource return "".
+func resourceType(a Action) string {
+	if r := a.Metadata().Resource; r != nil {
+		return r.Resource
+	}
+	return ""
+}
+
+func rateLimitKey(a Action) *cloud.RateLimitKey {
+	md := a.Metadata()
+	if md.Resource == nil {
+		return nil
+	}
+	si := lookupService(md.Resource)
+	return &cloud.RateLimitKey{
+		ProjectID: md.Resource.ProjectID,
+		Operation: operation(si, md.Type),
+		Version:   md.Version,
+		Service:   serviceName(si, md.Resource),
+	}
+}
+
+// lookupService returns the service of the resource id or nil if the resource
+// is not known.
+func lookupService(id *cloud.ResourceID) *meta.ServiceInfo {
+	group := id.APIGroup
+	if group == "" {
+		group = meta.APIGroupCompute
+	}
+	for _, si := range meta.AllServices {
+		if si.APIGroup != group || si.Resource != id.Resource {
+			continue
+		}
+		if id.Key == nil {
+			return si
+		}
+		switch id.Key.Type() {
+		case meta.Zonal:
+			if si.KeyIsZonal() {
+				return si
+			}
+		case meta.Regional:
+			if si.KeyIsRegional() {
+				return si
+			}
+		default:
+			if si.KeyIsGlobal() {
+				return si
+			}
+		}
+	}
+	return nil
+}
+
+// serviceName returns the name of the service for the generated code, e.g.
+// "BackendServices". If the service is not known, the resource name is
+// capitalized.
+func serviceName(si *meta.ServiceInfo, id *cloud.ResourceID) string {
+	if si != nil {
+		return si.Service
+	}
+	if id.Resource == "" {
+		return ""
+	}
+	return strings.ToUpper(id.Resource[:1]) + id.Resource[1:]
+}
+
+// operation returns the name of the method called for an Action of type t.
+func operation(si *meta.ServiceInfo, t ActionType) string {
+	switch t {
+	case ActionTypeCreate:
+		return "Insert"
+	case ActionTypeDelete:
+		return "Delete"
+	case ActionTypeUpdate:
+		if si != nil && !hasMethod(si, "Update") && hasMethod(si, "Patch") {
+			return "Patch"
+		}
+		return "Update"
+	}
+	return string(t)
+}
+
+// methodCache holds the names of the additional methods of the services as
+// these are found by reflection.
+var methodCache sync.Map // *meta.ServiceInfo => map[string]bool
+
+func hasMethod(si *meta.ServiceInfo, name string) bool {
+	if m, ok := methodCache.Load(si); ok {
+		return m.(map[string]bool)[name]
+	}
+	m := map[string]bool{}
+	for _, method := range si.Methods() {
+		m[method.Name()] = true
+	}
+	methodCache.Store(si, m)
+	return m[name]
+}
+
+// accept blocks until Action a can be dispatched. Returns an error if the
+// ctx was canceled while waiting.
+func (p *pacer) accept(ctx context.Context, a Action) error {
+	p.lock.Lock()
+	p.seq++
+	p.last[resourceType(a)] = p.seq
+	p.lock.Unlock()
+
+	if p.rl == nil {
+		return nil
+	}
+	if key := rateLimitKey(a); key != nil {
+		if err := p.rl.Accept(ctx, key); err != nil {
+			return fmt.Errorf("rate limit for Action %s: %w", a, err)
+		}
+	}
+	return nil
+}
+
+// observe the result of running Action a.
+func (p *pacer) observe(ctx context.Context, a Action, err error) {
+	if p.rl == nil {
+		return
+	}
+	if key := rateLimitKey(a); key != nil {
+		p.rl.Observe(ctx, err, key)
+	}
+}
+
+// less returns true if Action i should be dispatched before Action j when they
+// have the same priority: the resource type that was dispatched least recently
+// goes first.
+func (p *pacer) less(i, j Action) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.last[resourceType(i)] < p.last[resourceType(j)]
+}
+
+// interleave reorders the Actions (sorted by priority) so that consecutive
+// Actions with the same priority round-robin across resource types. The order
+// of Actions for the same resource type is preserved.
+func (p *pacer) interleave(actions []Action, priority func(Action) int) []Action {
+	var ret []Action
+	for start := 0; start < len(actions); {
+		end := start
+		for end < len(actions) && priority(actions[end]) == priority(actions[start]) {
+			end++
+		}
+		ret = append(ret, p.roundRobin(actions[start:end])...)
+		start = end
+	}
+	return ret
+}
+
+func (p *pacer) roundRobin(actions []Action) []Action {
+	byType := map[string][]Action{}
+	var types []string
+	for _, a := range actions {
+		t := resourceType(a)
+		if _, ok := byType[t]; !ok {
+			types = append(types, t)
+		}
+		byType[t] = append(byType[t], a)
+	}
+	// Start with the resource types that were dispatched least recently.
+	p.lock.Lock()
+	sort.SliceStable(types, func(i, j int) bool { return p.last[types[i]] < p.last[types[j]] })
+	p.lock.Unlock()
+
+	var ret []Action
+	for len(ret) < len(actions) {
+		for _, t := range types {
+			if len(byType[t]) > 0 {
+				ret = append(ret, byType[t][0])
+				byType[t] = byType[t][1:]
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testRateLimiter records the calls to Accept and Observe.
+type testRateLimiter struct {
+	lock      sync.Mutex
+	acceptErr error
+	accepted  []cloud.RateLimitKey
+	observed  []error
+}
+
+func (rl *testRateLimiter) Accept(_ context.Context, key *cloud.RateLimitKey) error {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	if rl.acceptErr != nil {
+		return rl.acceptErr
+	}
+	rl.accepted = append(rl.accepted, *key)
+	return nil
+}
+
+func (rl *testRateLimiter) Observe(_ context.Context, err error, _ *cloud.RateLimitKey) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.observed = append(rl.observed, err)
+}
+
+// pacingActions returns independent Actions named "<resource><n>" for the
+// given resource types.
+func pacingActions(names ...string) []Action {
+	var ret []Action
+	for _, name := range names {
+		ret = append(ret, &testAction{
+			name:   name,
+			events: EventList{StringEvent(name)},
+			resource: &cloud.ResourceID{
+				ProjectID: "proj",
+				Resource:  name[:len(name)-1],
+				Key:       meta.GlobalKey(name),
+			},
+			version: meta.VersionBeta,
+		})
+	}
+	return ret
+}
+
+func TestSerialExecutorPacing(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		actions   []Action
+		opts      []Option
+		acceptErr error
+		wantOrder []string
+		wantErr   bool
+	}{
+		{
+			name:      "no rate limiter",
+			actions:   pacingActions("a1", "a2", "a3", "b1", "b2"),
+			wantOrder: []string{"a1", "a2", "a3", "b1", "b2"},
+		},
+		{
+			name:      "interleave resource types",
+			actions:   pacingActions("a1", "a2", "a3", "b1", "b2", "c1"),
+			opts:      []Option{RateLimiterOption(&testRateLimiter{})},
+			wantOrder: []string{"a1", "b1", "c1", "a2", "b2", "a3"},
+		},
+		{
+			name:    "priority before interleave",
+			actions: pacingActions("a1", "a2", "b1"),
+			opts: []Option{
+				RateLimiterOption(&testRateLimiter{}),
+				PriorityOption(func(a Action) int {
+					if a.(*testAction).resource.Resource == "a" {
+						return 1
+					}
+					return 0
+				}),
+			},
+			wantOrder: []string{"a1", "a2", "b1"},
+		},
+		{
+			name:      "Accept error",
+			actions:   pacingActions("a1", "b1"),
+			opts:      []Option{RateLimiterOption(&testRateLimiter{acceptErr: context.Canceled})},
+			wantOrder: nil,
+			wantErr:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ex, err := NewSerialExecutor(nil, tc.actions, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(context.Background())
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			var order []string
+			for _, a := range result.Completed {
+				order = append(order, a.(*testAction).name)
+			}
+			if diff := cmp.Diff(order, tc.wantOrder); diff != "" {
+				t.Errorf("Completed: -got,+want: %s", diff)
+			}
+			if tc.wantErr && len(result.Pending) != len(tc.actions) {
+				t.Errorf("len(Pending) = %d, want %d", len(result.Pending), len(tc.actions))
+			}
+		})
+	}
+}
+
+func TestParallelExecutorPacing(t *testing.T) {
+	rl := &testRateLimiter{}
+	actions := pacingActions("a1", "a2", "b1")
+	actions[2].(*testAction).err = errors.New("injected")
+
+	ex, err := NewParallelExecutor(nil, actions, RateLimiterOption(rl))
+	if err != nil {
+		t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+	}
+	ex.Run(context.Background())
+
+	if len(rl.accepted) != 3 {
+		t.Errorf("len(accepted) = %d, want 3", len(rl.accepted))
+	}
+	for _, key := range rl.accepted {
+		if key.ProjectID != "proj" || key.Operation != string(ActionTypeCustom) || key.Version != meta.VersionBeta {
+			t.Errorf("key = %+v, want ProjectID=proj, Operation=%s, Version=%s", key, ActionTypeCustom, meta.VersionBeta)
+		}
+	}
+	var errs int
+	for _, err := range rl.observed {
+		if err != nil {
+			errs++
+		}
+	}
+	if len(rl.observed) != 3 || errs != 1 {
+		t.Errorf("observed = %v, want 3 results with 1 error", rl.observed)
+	}
+}
+
+func TestPacingRateLimitKey(t *testing.T) {
+	action := func(name string, actionType ActionType, id *cloud.ResourceID) *testAction {
+		return &testAction{
+			name:       name,
+			events:     EventList{StringEvent(name)},
+			resource:   id,
+			version:    meta.VersionGA,
+			actionType: actionType,
+		}
+	}
+	actions := []Action{
+		action("create-bs", ActionTypeCreate, &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: meta.GlobalKey("bs")}),
+		action("create-rbs", ActionTypeCreate, &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: meta.RegionalKey("bs", "us-central1")}),
+		action("delete-fw", ActionTypeDelete, &cloud.ResourceID{ProjectID: "proj", Resource: "firewalls", Key: meta.GlobalKey("fw")}),
+		action("update-hc", ActionTypeUpdate, &cloud.ResourceID{ProjectID: "proj", Resource: "healthChecks", Key: meta.GlobalKey("hc")}),
+		action("update-route", ActionTypeUpdate, &cloud.ResourceID{ProjectID: "proj", APIGroup: meta.APIGroupNetworkServices, Resource: "tcpRoutes", Key: meta.GlobalKey("route")}),
+	}
+
+	// The limiters are registered with the names used by the generated
+	// code (see gen.go).
+	rl := cloud.NewCompositeRateLimiter(&cloud.NopRateLimiter{})
+	limiters := map[string]*testRateLimiter{}
+	for _, k := range []struct{ service, op string }{
+		{"BackendServices", "Insert"},
+		{"RegionBackendServices", "Insert"},
+		{"Firewalls", "Delete"},
+		{"HealthChecks", "Update"},
+		{"TcpRoutes", "Patch"},
+		{"BackendServices", "Delete"},
+	} {
+		limiters[k.service+"/"+k.op] = &testRateLimiter{}
+		rl.Register(k.service, k.op, limiters[k.service+"/"+k.op])
+	}
+
+	ex, err := NewSerialExecutor(nil, actions, RateLimiterOption(rl))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(context.Background()); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	for name, want := range map[string]int{
+		"BackendServices/Insert":       1,
+		"RegionBackendServices/Insert": 1,
+		"Firewalls/Delete":             1,
+		"HealthChecks/Update":          1,
+		"TcpRoutes/Patch":              1,
+		"BackendServices/Delete":       0,
+	} {
+		if got := len(limiters[name].accepted); got != want {
+			t.Errorf("limiter %s: %d Actions accepted, want %d", name, got, want)
+		}
+		if got := len(limiters[name].observed); got != want {
+			t.Errorf("limiter %s: %d Actions observed, want %d", name, got, want)
+		}
+	}
+
+	// A limiter that rejects the call stops the Action.
+	rl.Register("BackendServices", "Insert", &testRateLimiter{acceptErr: context.Canceled})
+	ex, err = NewSerialExecutor(nil, []Action{action("create-bs", ActionTypeCreate, &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: meta.GlobalKey("bs")})}, RateLimiterOption(rl))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background())
+	if err == nil || len(result.Completed) != 0 {
+		t.Errorf("Run() = %+v, %v; want error and no completed Actions", result, err)
+	}
+}
+
+func TestPacerInterleave(t *testing.T) {
+	p := newPacer(nil)
+	priority := func(a Action) int {
+		if a.(*testAction).name == "c1" {
+			return 1
+		}
+		return 0
+	}
+	actions := pacingActions("c1", "a1", "a2", "a3", "b1", "b2")
+
+	var order []string
+	for _, a := range p.interleave(actions, priority) {
+		order = append(order, a.(*testAction).name)
+	}
+	want := []string{"c1", "a1", "b1", "a2", "b2", "a3"}
+	if diff := cmp.Diff(order, want); diff != "" {
+		t.Errorf("interleave(): -got,+want: %s", diff)
+	}
+
+	// Resource types that were dispatched more recently go last.
+	p.accept(context.Background(), actions[1])
+	order = nil
+	for _, a := range p.interleave(actions[1:], priority) {
+		order = append(order, a.(*testAction).name)
+	}
+	want = []string{"b1", "a1", "b2", "a2", "a3"}
+	if diff := cmp.Diff(order, want); diff != "" {
+		t.Errorf("interleave() after accept: -got,+want: %s", diff)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// ErrPreconditionFailed is returned (wrapped) when the precondition of an
+// Action does not hold immediately before it is run, e.g. the resource was
+// changed concurrently since it was planned.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// Preconditioner is implemented by Actions that verify the state in the Cloud
+// before they are run. The Executor calls CheckPreconditions immediately
+// before Run(); if it returns an error the Action is not run and fails with
+// the error. Preconditions are not checked in dry run mode.
+type Preconditioner interface {
+	// CheckPreconditions returns an error wrapping ErrPreconditionFailed if
+	// the Action must not be run (see PreconditionFailed). Other errors
+	// (e.g. the check could not be made) also prevent the Action from
+	// running.
+	CheckPreconditions(ctx context.Context, c cloud.Cloud) error
+}
+
+// PreconditionFailed returns an error wrapping ErrPreconditionFailed.
+func PreconditionFailed(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrPreconditionFailed, fmt.Sprintf(format, args...))
+}
+
+// PreconditionFunc is a check made before an Action is run. See
+// Preconditioner.
+type PreconditionFunc func(ctx context.Context, c cloud.Cloud) error
+
+// NewPreconditionedAction returns an Action that checks the preconditions
+// (in addition to the preconditions of a, if any) before a is run.
+func NewPreconditionedAction(a Action, checks ...PreconditionFunc) Action {
+	return &preconditionedAction{Action: a, checks: checks}
+}
+
+type preconditionedAction struct {
+	Action
+	checks []PreconditionFunc
+}
+
+// CheckPreconditions implements Preconditioner.
+func (pa *preconditionedAction) CheckPreconditions(ctx context.Context, c cloud.Cloud) error {
+	if err := checkPreconditions(ctx, c, pa.Action); err != nil {
+		return err
+	}
+	for _, check := range pa.checks {
+		if err := check(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compensation implements Compensator if the wrapped Action does.
+func (pa *preconditionedAction) Compensation() (Action, error) {
+	return compensation(pa.Action)
+}
+
+// checkPreconditions of a if it is a Preconditioner.
+func checkPreconditions(ctx context.Context, c cloud.Cloud, a Action) error {
+	p, ok := a.(Preconditioner)
+	if !ok {
+		return nil
+	}
+	return p.CheckPreconditions(ctx, c)
+}
+
+// runChecked runs a after checking the preconditions.
+func runChecked(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
+	if err := checkPreconditions(ctx, c, a); err != nil {
+		return nil, fmt.Errorf("%s: %w", a, err)
+	}
+	return a.Run(ctx, c)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+func TestPreconditions(t *testing.T) {
+	pass := func(context.Context, cloud.Cloud) error { return nil }
+	fail := func(context.Context, cloud.Cloud) error { return PreconditionFailed("injected") }
+	checkErr := func(context.Context, cloud.Cloud) error { return errors.New("check error") }
+
+	for _, tc := range []struct {
+		name   string
+		wrap   func(Action) Action
+		dryRun bool
+
+		wantRun                bool
+		wantErr                bool
+		wantPreconditionFailed bool
+	}{
+		{
+			name:    "no preconditions",
+			wrap:    func(a Action) Action { return a },
+			wantRun: true,
+		},
+		{
+			name:    "pass",
+			wrap:    func(a Action) Action { return NewPreconditionedAction(a, pass, pass) },
+			wantRun: true,
+		},
+		{
+			name:                   "fail",
+			wrap:                   func(a Action) Action { return NewPreconditionedAction(a, pass, fail) },
+			wantErr:                true,
+			wantPreconditionFailed: true,
+		},
+		{
+			name:    "check error",
+			wrap:    func(a Action) Action { return NewPreconditionedAction(a, checkErr) },
+			wantErr: true,
+		},
+		{
+			name: "nested wrappers",
+			wrap: func(a Action) Action {
+				return NewPrioritizedAction(NewPreconditionedAction(NewPreconditionedAction(a, fail), pass), 1)
+			},
+			wantErr:                true,
+			wantPreconditionFailed: true,
+		},
+		{
+			name:   "not checked in dry run",
+			wrap:   func(a Action) Action { return NewPreconditionedAction(a, fail) },
+			dryRun: true,
+		},
+	} {
+		for _, executor := range []string{"serial", "parallel"} {
+			if tc.dryRun && executor == "parallel" {
+				// The parallel Executor does not support dry run.
+				continue
+			}
+			t.Run(tc.name+"/"+executor, func(t *testing.T) {
+				ran := false
+				a := &testAction{
+					name:    "A",
+					events:  EventList{StringEvent("A")},
+					runHook: func(context.Context) error { ran = true; return nil },
+				}
+				b := &testAction{name: "B", ActionBase: ActionBase{Want: EventList{StringEvent("A")}}}
+				acts := []Action{tc.wrap(a), b}
+
+				var (
+					ex  Executor
+					err error
+				)
+				if executor == "serial" {
+					ex, err = NewSerialExecutor(nil, acts, DryRunOption(tc.dryRun))
+				} else {
+					ex, err = NewParallelExecutor(nil, acts)
+				}
+				if err != nil {
+					t.Fatalf("NewExecutor() = %v, want nil", err)
+				}
+				result, err := ex.Run(context.Background())
+				if gotErr := err != nil; gotErr != tc.wantErr {
+					t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+				}
+				if ran != tc.wantRun {
+					t.Errorf("ran = %t, want %t", ran, tc.wantRun)
+				}
+				if !tc.wantErr {
+					return
+				}
+				if len(result.Errors) != 1 {
+					t.Fatalf("len(result.Errors) = %d, want 1", len(result.Errors))
+				}
+				if got := errors.Is(result.Errors[0].Err, ErrPreconditionFailed); got != tc.wantPreconditionFailed {
+					t.Errorf("errors.Is(%v, ErrPreconditionFailed) = %t, want %t", result.Errors[0].Err, got, tc.wantPreconditionFailed)
+				}
+				// B depends on A and must not run.
+				if len(result.Pending) != 1 || result.Pending[0] != b {
+					t.Errorf("result.Pending = %v, want [B]", result.Pending)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// Prioritized is implemented by Actions that have a scheduling priority.
+type Prioritized interface {
+	// Priority of the Action. When more than one Action can run, the
+	// Executor starts the Actions with a higher priority first.
+	Priority() int
+}
+
+// ActionPriority returns the priority of the Action. This is the default used
+// by the Executors (see PriorityOption). Actions that do not implement
+// Prioritized have priority 0.
+func ActionPriority(a Action) int {
+	if p, ok := a.(Prioritized); ok {
+		return p.Priority()
+	}
+	return 0
+}
+
+// prioritizedAction is an Action with a priority.
+type prioritizedAction struct {
+	Action
+	priority int
+}
+
+// NewPrioritizedAction returns an Action that has the given scheduling
+// priority. Use this to execute Actions on the critical path first, e.g.
+// Actions for HealthChecks and BackendServices before the proxies that
+// reference them. See PriorityOption for the limits of the ordering.
+func NewPrioritizedAction(a Action, priority int) Action {
+	return &prioritizedAction{Action: a, priority: priority}
+}
+
+// Priority implements Prioritized.
+func (pa *prioritizedAction) Priority() int { return pa.priority }
+
+// Compensation implements Compensator if the wrapped Action does.
+func (pa *prioritizedAction) Compensation() (Action, error) {
+	return compensation(pa.Action)
+}
+
+// CheckPreconditions implements Preconditioner if the wrapped Action does.
+func (pa *prioritizedAction) CheckPreconditions(ctx context.Context, c cloud.Cloud) error {
+	return checkPreconditions(ctx, c, pa.Action)
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
 )
 
 // retriableAction is an action with retry mechanism
@@ -37,16 +38,24 @@ func NewRetriableAction(a Action, canRetry func(error) (bool, time.Duration)) Ac
 }
 
 // Run executes Action. On error `canRetry` function is used to check time
-// period after which the action should be retried. If canRetry returns false or
-// context is canceled action returns with error.
+// period after which the action should be retried. If canRetry returns false,
+// the RetryBudget given to the Executor is exhausted or context is canceled
+// action returns with error.
 func (ra *retriableAction) Run(ctx context.Context, c cloud.Cloud) (EventList, error) {
-	for {
-		events, err := ra.Action.Run(ctx, c)
+	logger := klog.FromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		attemptLogger := logger.WithValues("attempt", attempt)
+		events, err := ra.Action.Run(klog.NewContext(ctx, attemptLogger), c)
 
 		if err == nil {
 			return events, nil
 		}
 		if canRetry, backOffTime := ra.canRetry(err); canRetry {
+			if b := retryBudgetFromContext(ctx); b != nil && !b.take(ra, err) {
+				attemptLogger.V(2).Info("Action will not be retried, retry budget exhausted", "err", err)
+				return events, fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, err)
+			}
+			attemptLogger.V(2).Info("Action will be retried", "err", err, "backoff", backOffTime)
 			timer := time.NewTimer(backOffTime)
 			select {
 			case <-timer.C:
@@ -65,3 +74,14 @@ func (ra *retriableAction) Run(ctx context.Context, c cloud.Cloud) (EventList, e
 func (ra *retriableAction) String() string {
 	return ra.Action.String() + " with retry"
 }
+
+// Compensation implements Compensator if the wrapped Action does.
+func (ra *retriableAction) Compensation() (Action, error) {
+	return compensation(ra.Action)
+}
+
+// CheckPreconditions implements Preconditioner if the wrapped Action does. The
+// preconditions are checked once, before the first attempt.
+func (ra *retriableAction) CheckPreconditions(ctx context.Context, c cloud.Cloud) error {
+	return checkPreconditions(ctx, c, ra.Action)
+}
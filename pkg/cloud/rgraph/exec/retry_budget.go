@@ -0,0 +1,151 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrRetryBudgetExhausted is returned (wrapped) by a retriable Action when the
+// shared RetryBudget has been used up.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudgetOption sets a RetryBudget that is shared by all of the retriable
+// Actions (see NewRetriableAction) run by the Executor. When the budget is
+// exhausted, the Executor stops starting new Actions and returns a
+// *RetryBudgetError, regardless of the ErrorStrategy.
+//
+// The same RetryBudget can be given to more than one Executor, e.g. to keep
+// the budget when resuming an execution with Result.Remaining().
+func RetryBudgetOption(b *RetryBudget) Option {
+	return func(c *ExecutorConfig) { c.RetryBudget = b }
+}
+
+// NewRetryBudget returns a RetryBudget that allows at most max retries in
+// total.
+func NewRetryBudget(max int) *RetryBudget {
+	return &RetryBudget{max: max}
+}
+
+// RetryBudget limits the total number of retries across the Actions of a
+// plan. This keeps a pathological failure (e.g. an outage in the API) from
+// multiplying the number of calls by the number of Actions.
+type RetryBudget struct {
+	lock    sync.Mutex
+	max     int
+	used    int
+	records []*RetryRecord
+}
+
+// RetryRecord is the retry history of a single Action.
+type RetryRecord struct {
+	Action Action
+	// Retries is the number of retries that were allowed by the budget.
+	Retries int
+	// LastErr is the last error returned by the Action.
+	LastErr error
+}
+
+// Used returns the number of retries taken from the budget.
+func (b *RetryBudget) Used() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.used
+}
+
+// Exhausted is true if there are no more retries left.
+func (b *RetryBudget) Exhausted() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.used >= b.max
+}
+
+// take a retry for Action a that failed with err. Returns false if the budget
+// has been exhausted.
+func (b *RetryBudget) take(a Action, err error) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var rec *RetryRecord
+	for _, r := range b.records {
+		if r.Action == a {
+			rec = r
+			break
+		}
+	}
+	if rec == nil {
+		rec = &RetryRecord{Action: a}
+		b.records = append(b.records, rec)
+	}
+	rec.LastErr = err
+
+	if b.used >= b.max {
+		return false
+	}
+	b.used++
+	rec.Retries++
+	return true
+}
+
+// err returns the consolidated error report for the budget.
+func (b *RetryBudget) err() *RetryBudgetError {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	ret := &RetryBudgetError{Budget: b.max}
+	for _, r := range b.records {
+		ret.Records = append(ret.Records, *r)
+	}
+	return ret
+}
+
+// RetryBudgetError is returned by the Executor when the RetryBudget has been
+// exhausted. It lists all of the Actions that needed a retry.
+type RetryBudgetError struct {
+	// Budget is the maximum number of retries.
+	Budget int
+	// Records of the Actions that were retried, in the order of their first
+	// retry.
+	Records []RetryRecord
+}
+
+func (e *RetryBudgetError) Error() string {
+	var parts []string
+	for _, r := range e.Records {
+		parts = append(parts, fmt.Sprintf("%s: %d retries, last error: %v", r.Action, r.Retries, r.LastErr))
+	}
+	return fmt.Sprintf("%v (budget %d, %d Actions retried): [%s]", ErrRetryBudgetExhausted, e.Budget, len(e.Records), strings.Join(parts, "; "))
+}
+
+// Unwrap returns ErrRetryBudgetExhausted.
+func (e *RetryBudgetError) Unwrap() error { return ErrRetryBudgetExhausted }
+
+type retryBudgetKey struct{}
+
+func withRetryBudget(ctx context.Context, b *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, b)
+}
+
+// retryBudgetFromContext returns nil if there is no RetryBudget in ctx.
+func retryBudgetFromContext(ctx context.Context) *RetryBudget {
+	b, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return b
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func alwaysRetry(error) (bool, time.Duration) { return true, 0 }
+
+func TestRetryBudget(t *testing.T) {
+	type newExecutorFunc func([]Action, ...Option) (Executor, error)
+	serial := func(acts []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(nil, acts, opts...) }
+	parallel := func(acts []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(nil, acts, opts...) }
+
+	for _, tc := range []struct {
+		name        string
+		newExecutor newExecutorFunc
+		budget      int
+		// errorRuns is the number of runs that fail for each Action. -1
+		// means the Action always fails.
+		errorRuns []int
+		opts      []Option
+
+		wantErr     bool
+		wantUsed    int
+		wantPending int
+	}{
+		{
+			name:        "serial within budget",
+			newExecutor: serial,
+			budget:      5,
+			errorRuns:   []int{2, 1, 0},
+			wantUsed:    3,
+		},
+		{
+			name:        "parallel within budget",
+			newExecutor: parallel,
+			budget:      5,
+			errorRuns:   []int{2, 1, 0},
+			wantUsed:    3,
+		},
+		{
+			name:        "serial exhausted",
+			newExecutor: serial,
+			budget:      3,
+			errorRuns:   []int{-1, -1, -1},
+			opts:        []Option{ErrorStrategyOption(ContinueOnError)},
+			wantErr:     true,
+			wantUsed:    3,
+			wantPending: 2,
+		},
+		{
+			name:        "parallel exhausted",
+			newExecutor: parallel,
+			budget:      3,
+			errorRuns:   []int{-1, -1, -1},
+			wantErr:     true,
+			wantUsed:    3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var acts []Action
+			for _, n := range tc.errorRuns {
+				// fakeAction fails while runCtr < errorRunThreshold.
+				threshold := n + 1
+				if n < 0 {
+					threshold = 1 << 30
+				}
+				acts = append(acts, NewRetriableAction(&fakeAction{errorRunThreshold: threshold}, alwaysRetry))
+			}
+			budget := NewRetryBudget(tc.budget)
+			ex, err := tc.newExecutor(acts, append(tc.opts, RetryBudgetOption(budget))...)
+			if err != nil {
+				t.Fatalf("newExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(context.Background())
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if got := budget.Used(); got != tc.wantUsed {
+				t.Errorf("budget.Used() = %d, want %d", got, tc.wantUsed)
+			}
+			if !tc.wantErr {
+				return
+			}
+			var budgetErr *RetryBudgetError
+			if !errors.As(err, &budgetErr) {
+				t.Fatalf("Run() = %v, want RetryBudgetError", err)
+			}
+			if !errors.Is(err, ErrRetryBudgetExhausted) {
+				t.Errorf("Run() = %v, want ErrRetryBudgetExhausted", err)
+			}
+			if budgetErr.Budget != tc.budget || len(budgetErr.Records) == 0 {
+				t.Errorf("RetryBudgetError = %+v, want Budget = %d and non-empty Records", budgetErr, tc.budget)
+			}
+			if len(result.Pending) < tc.wantPending {
+				t.Errorf("len(Pending) = %d, want %d", len(result.Pending), tc.wantPending)
+			}
+		})
+	}
+}
+
+func TestRetryBudgetShared(t *testing.T) {
+	budget := NewRetryBudget(2)
+
+	ex, err := NewSerialExecutor(nil, []Action{
+		NewRetriableAction(&fakeAction{errorRunThreshold: 3}, alwaysRetry),
+	}, RetryBudgetOption(budget))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(context.Background()); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if !budget.Exhausted() {
+		t.Fatalf("budget.Exhausted() = false, want true (used %d)", budget.Used())
+	}
+
+	fa := &fakeAction{errorRunThreshold: 2}
+	ex, err = NewSerialExecutor(nil, []Action{NewRetriableAction(fa, alwaysRetry)}, RetryBudgetOption(budget))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(context.Background()); !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("Run() = %v, want ErrRetryBudgetExhausted", err)
+	}
+	if fa.runCtr != 1 {
+		t.Errorf("fa.runCtr = %d, want 1", fa.runCtr)
+	}
+}
@@ -96,8 +96,18 @@ func (a *genericCreateAction[GA, Alpha, Beta]) String() string {
 
 func (a *genericCreateAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("GenericCreateAction(%s)", a.id),
-		Type:    exec.ActionTypeCreate,
-		Summary: fmt.Sprintf("Create %s", a.id),
+		Name:     fmt.Sprintf("GenericCreateAction(%s)", a.id),
+		Type:     exec.ActionTypeCreate,
+		Summary:  fmt.Sprintf("Create %s", a.id),
+		Resource: a.id,
+		Version:  a.resource.Version(),
 	}
 }
+
+// Compensation implements exec.Compensator by deleting the created resource.
+func (a *genericCreateAction[GA, Alpha, Beta]) Compensation() (exec.Action, error) {
+	return &genericDeleteAction[GA, Alpha, Beta]{
+		ops: a.ops,
+		id:  a.id,
+	}, nil
+}
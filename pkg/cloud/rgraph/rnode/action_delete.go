@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 )
 
@@ -30,11 +32,14 @@ func NewGenericDeleteAction[GA any, Alpha any, Beta any](
 	ops GenericOps[GA, Alpha, Beta],
 	got Node,
 ) *genericDeleteAction[GA, Alpha, Beta] {
+	// resource may be nil if the Node does not have a resource.
+	resource, _ := got.Resource().(api.Resource[GA, Alpha, Beta])
 	return &genericDeleteAction[GA, Alpha, Beta]{
 		ActionBase: exec.ActionBase{Want: want},
 		ops:        ops,
 		id:         got.ID(),
 		outRefs:    got.OutRefs(),
+		resource:   resource,
 	}
 }
 
@@ -61,6 +66,9 @@ type genericDeleteAction[GA any, Alpha any, Beta any] struct {
 	ops     GenericOps[GA, Alpha, Beta]
 	id      *cloud.ResourceID
 	outRefs []ResourceRef
+	// resource is the value before deletion. This is used to recreate the
+	// resource in Compensation().
+	resource api.Resource[GA, Alpha, Beta]
 
 	start, end time.Time
 }
@@ -96,8 +104,25 @@ func (a *genericDeleteAction[GA, Alpha, Beta]) String() string {
 
 func (a *genericDeleteAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("GenericDeleteAction(%s)", a.id),
-		Type:    exec.ActionTypeDelete,
-		Summary: fmt.Sprintf("Delete %s", a.id),
+		Name:     fmt.Sprintf("GenericDeleteAction(%s)", a.id),
+		Type:     exec.ActionTypeDelete,
+		Summary:  fmt.Sprintf("Delete %s", a.id),
+		Resource: a.id,
+		// DeleteFuncs always use the GA API.
+		Version: meta.VersionGA,
 	}
 }
+
+// Compensation implements exec.Compensator by creating the resource again.
+// The OutputOnly fields of the deleted resource (e.g. Id, SelfLink) are
+// cleared as they cannot be sent in the insert.
+func (a *genericDeleteAction[GA, Alpha, Beta]) Compensation() (exec.Action, error) {
+	if a.resource == nil {
+		return nil, fmt.Errorf("%s: value of the deleted resource is unknown", a)
+	}
+	r, err := api.ClearOutputOnly(a.resource)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a, err)
+	}
+	return newGenericCreateAction(nil, a.ops, a.id, r), nil
+}
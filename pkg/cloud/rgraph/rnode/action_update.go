@@ -19,6 +19,7 @@ package rnode
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -33,14 +34,16 @@ func UpdateActions[GA any, Alpha any, Beta any](
 	resource api.Resource[GA, Alpha, Beta],
 	fingerprint string,
 ) ([]exec.Action, error) {
-	preEvents, err := updatePreconditions(got, want)
+	preEvents, err := UpdatePreconditions(got, want)
 	if err != nil {
 		return nil, err
 	}
-	postEvents := postUpdateActionEvents(got, want)
-	return []exec.Action{
-		newGenericUpdateAction(preEvents, ops, want.ID(), resource, postEvents, fingerprint),
-	}, nil
+	postEvents := PostUpdateActionEvents(got, want)
+	act := newGenericUpdateAction(preEvents, ops, want.ID(), resource, postEvents, fingerprint)
+	// gotResource may be nil if the Node does not have a resource.
+	act.gotResource, _ = got.Resource().(api.Resource[GA, Alpha, Beta])
+	act.gotFingerprint = resourceFingerprint(act.gotResource)
+	return []exec.Action{act}, nil
 }
 
 func newGenericUpdateAction[GA any, Alpha any, Beta any](
@@ -68,6 +71,15 @@ type genericUpdateAction[GA any, Alpha any, Beta any] struct {
 	resource    api.Resource[GA, Alpha, Beta]
 	postEvents  exec.EventList
 	fingerprint string
+	// gotResource is the value before the update. This is used to revert
+	// the update in Compensation().
+	gotResource api.Resource[GA, Alpha, Beta]
+	// fetchFingerprint will get the current fingerprint of the resource
+	// before the update instead of using fingerprint.
+	fetchFingerprint bool
+	// gotFingerprint is the fingerprint of gotResource. This is "" if the
+	// resource does not have a fingerprint.
+	gotFingerprint string
 
 	start, end time.Time
 }
@@ -77,7 +89,15 @@ func (a *genericUpdateAction[GA, Alpha, Beta]) Run(
 	c cloud.Cloud,
 ) (exec.EventList, error) {
 	a.start = time.Now()
-	err := a.ops.UpdateFuncs(c).Do(ctx, a.fingerprint, a.id, a.resource)
+	fingerprint := a.fingerprint
+	if a.fetchFingerprint {
+		var err error
+		if fingerprint, err = currentFingerprint(ctx, c, a.ops, a.resource.Version(), a.id); err != nil {
+			a.end = time.Now()
+			return nil, err
+		}
+	}
+	err := a.ops.UpdateFuncs(c).Do(ctx, fingerprint, a.id, a.resource)
 	a.end = time.Now()
 
 	// Emit DropReference events for removed references.
@@ -95,13 +115,111 @@ func (a *genericUpdateAction[GA, Alpha, Beta]) String() string {
 
 func (a *genericUpdateAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("GenericUpdateAction(%s)", a.id),
-		Type:    exec.ActionTypeUpdate,
-		Summary: fmt.Sprintf("Update %s", a.id),
+		Name:     fmt.Sprintf("GenericUpdateAction(%s)", a.id),
+		Type:     exec.ActionTypeUpdate,
+		Summary:  fmt.Sprintf("Update %s", a.id),
+		Resource: a.id,
+		Version:  a.resource.Version(),
+	}
+}
+
+// Compensation implements exec.Compensator by updating the resource back to
+// the value before the update.
+func (a *genericUpdateAction[GA, Alpha, Beta]) Compensation() (exec.Action, error) {
+	if a.gotResource == nil {
+		return nil, fmt.Errorf("%s: value of the resource before the update is unknown", a)
+	}
+	act := newGenericUpdateAction(nil, a.ops, a.id, a.gotResource, nil, "")
+	// The fingerprint will have changed due to the update.
+	act.fetchFingerprint = true
+	return act, nil
+}
+
+// CheckPreconditions implements exec.Preconditioner. The fingerprint of the
+// resource in the Cloud must match the fingerprint of the resource when it was
+// planned. Otherwise, the resource has been changed concurrently and the
+// update would overwrite the change.
+func (a *genericUpdateAction[GA, Alpha, Beta]) CheckPreconditions(ctx context.Context, c cloud.Cloud) error {
+	if a.gotFingerprint == "" {
+		return nil
+	}
+	fingerprint, err := currentFingerprint(ctx, c, a.ops, a.resource.Version(), a.id)
+	if err != nil {
+		return fmt.Errorf("CheckPreconditions: %w", err)
+	}
+	if fingerprint != "" && fingerprint != a.gotFingerprint {
+		return exec.PreconditionFailed("%v was changed after planning (fingerprint %q, planned %q)", a.id, fingerprint, a.gotFingerprint)
+	}
+	return nil
+}
+
+// resourceFingerprint returns the .Fingerprint of r or "" if r is nil or does
+// not have a fingerprint.
+func resourceFingerprint[GA any, Alpha any, Beta any](r api.Resource[GA, Alpha, Beta]) string {
+	if r == nil {
+		return ""
+	}
+	var (
+		raw any
+		err error
+	)
+	switch r.Version() {
+	case meta.VersionGA:
+		raw, err = r.ToGA()
+	case meta.VersionAlpha:
+		raw, err = r.ToAlpha()
+	case meta.VersionBeta:
+		raw, err = r.ToBeta()
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
 	}
+	fv, err := fingerprintField(reflect.ValueOf(raw))
+	if err != nil {
+		return ""
+	}
+	return fv.String()
 }
 
-func updatePreconditions(got, want Node) (exec.EventList, error) {
+// currentFingerprint gets the .Fingerprint of the resource from the Cloud.
+func currentFingerprint[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	c cloud.Cloud,
+	ops GenericOps[GA, Alpha, Beta],
+	ver meta.Version,
+	id *cloud.ResourceID,
+) (string, error) {
+	if ops.UpdateFuncs(c).Options&UpdateFuncsNoFingerprint != 0 {
+		return "", nil
+	}
+	var (
+		raw any
+		err error
+	)
+	getFuncs := ops.GetFuncs(c)
+	switch ver {
+	case meta.VersionGA:
+		raw, err = getFuncs.GA.Do(ctx, id.Key, cloud.ForceProjectID(id.ProjectID))
+	case meta.VersionAlpha:
+		raw, err = getFuncs.Alpha.Do(ctx, id.Key, cloud.ForceProjectID(id.ProjectID))
+	case meta.VersionBeta:
+		raw, err = getFuncs.Beta.Do(ctx, id.Key, cloud.ForceProjectID(id.ProjectID))
+	default:
+		return "", fmt.Errorf("currentFingerprint: unsupported version %q", ver)
+	}
+	if err != nil {
+		return "", err
+	}
+	fv, err := fingerprintField(reflect.ValueOf(raw))
+	if err != nil {
+		return "", err
+	}
+	return fv.String(), nil
+}
+
+func UpdatePreconditions(got, want Node) (exec.EventList, error) {
 	// Update can only occur if the resource Exists TODO: is there a case where
 	// the ambient signal for existance from Update op collides with a
 	// reference to it?
@@ -118,7 +236,8 @@ func updatePreconditions(got, want Node) (exec.EventList, error) {
 	return events, nil
 }
 
-func postUpdateActionEvents(got, want Node) exec.EventList {
+// PostUpdateActionEvents are signalled by a successful update of want.
+func PostUpdateActionEvents(got, want Node) exec.EventList {
 	wantOutRefs := want.OutRefs()
 	gotOutRefs := got.OutRefs()
 
@@ -140,3 +259,14 @@ func postUpdateActionEvents(got, want Node) exec.EventList {
 	events = append(events, exec.NewExistsEvent(want.ID()))
 	return events
 }
+
+// AdoptActions returns the Actions for a Node planned with OpAdopt. If the
+// existing resource differs from want, update() is used to compute the Actions
+// to update the resource in place. Otherwise, adoption does not change the
+// resource and only signals its existence.
+func AdoptActions(want Node, update func() ([]exec.Action, error)) ([]exec.Action, error) {
+	if details := want.Plan().Details(); details != nil && details.Diff != nil && details.Diff.HasDiff() {
+		return update()
+	}
+	return []exec.Action{exec.NewExistsAction(want.ID())}, nil
+}
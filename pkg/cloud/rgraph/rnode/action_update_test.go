@@ -122,10 +122,10 @@ func TestPostUpdateActions(t *testing.T) {
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 
-			gotEvents, err := updatePreconditions(tc.oldNode, tc.newNode)
+			gotEvents, err := UpdatePreconditions(tc.oldNode, tc.newNode)
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
-				t.Errorf("updatePreconditions(_, _) = %v, want %v", gotErr, tc.wantErr)
+				t.Errorf("UpdatePreconditions(_, _) = %v, want %v", gotErr, tc.wantErr)
 			}
 			if tc.wantErr {
 				return
@@ -189,9 +189,9 @@ func TestUpdatePreconditions(t *testing.T) {
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 
-			gotEvents := postUpdateActionEvents(tc.oldNode, tc.newNode)
+			gotEvents := PostUpdateActionEvents(tc.oldNode, tc.newNode)
 			if len(gotEvents) != len(tc.wantEvents) {
-				t.Fatalf("postUpdateActionEvents(got, want) = %d, want %d", len(gotEvents), len(tc.wantEvents))
+				t.Fatalf("PostUpdateActionEvents(got, want) = %d, want %d", len(gotEvents), len(tc.wantEvents))
 			}
 			for i, gotEvent := range gotEvents {
 				wantEvent := tc.wantEvents[i]
@@ -26,6 +26,10 @@ import (
 	"google.golang.org/api/compute/v1"
 )
 
+func init() {
+	api.RegisterSchema("compute/addresses", NewMutableAddress("project", meta.GlobalKey("key")))
+}
+
 func ID(project string, key *meta.Key) *cloud.ResourceID {
 	return &cloud.ResourceID{
 		Resource:  "addresses",
@@ -19,6 +19,7 @@ package address
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	alpha "google.golang.org/api/compute/v0.alpha"
@@ -32,6 +33,7 @@ type addressNode struct {
 }
 
 var _ rnode.Node = (*addressNode)(nil)
+var _ rnode.RefRewriter = (*addressNode)(nil)
 
 func (n *addressNode) Resource() rnode.UntypedResource { return n.resource }
 
@@ -81,6 +83,11 @@ func (n *addressNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 	case rnode.OpUpdate:
 		return nil, fmt.Errorf("%s is not supported for Address", op)
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) {
+			return nil, fmt.Errorf("%s with changes is not supported for Address", op)
+		})
 	}
 
 	return nil, fmt.Errorf("AddressNode: invalid plan op %s", op)
@@ -89,5 +96,14 @@ func (n *addressNode) Actions(got rnode.Node) ([]exec.Action, error) {
 func (n *addressNode) Builder() rnode.Builder {
 	b := &builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
+
+func (n *addressNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
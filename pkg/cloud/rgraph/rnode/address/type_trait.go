@@ -31,8 +31,6 @@ type typeTrait struct {
 
 func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
 	dt := api.NewFieldTraits()
-	// Built-ins
-	dt.OutputOnly(api.Path{}.Pointer().Field("Fingerprint"))
 	// [Output Only]
 	dt.OutputOnly(api.Path{}.Pointer().Field("Kind"))
 	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
@@ -14,44 +14,31 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package all imports all of the Node types in this repository so that they
+// are in the rnode registry (see rnode.Register).
 package all
 
 import (
-	"fmt"
-
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
-	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+
+	// Register the Node types.
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/firewall"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/sslcertificate"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpsproxy"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/tcproute"
+	_ "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
 )
 
+// NewBuilderByID returns an empty Builder for the id. Node types from other
+// packages can be added with rnode.Register().
 func NewBuilderByID(id *cloud.ResourceID) (rnode.Builder, error) {
-	switch id.Resource {
-	case "addresses":
-		return address.NewBuilder(id), nil
-	case "backendServices":
-		return backendservice.NewBuilder(id), nil
-	case "fakes":
-		return fake.NewBuilder(id), nil
-	case "forwardingRules":
-		return forwardingrule.NewBuilder(id), nil
-	case "healthChecks":
-		return healthcheck.NewBuilder(id), nil
-	case "networkEndpointGroups":
-		return networkendpointgroup.NewBuilder(id), nil
-	case "targetHttpProxies":
-		return targethttpproxy.NewBuilder(id), nil
-	case "urlMaps":
-		return urlmap.NewBuilder(id), nil
-	case "tcpRoute":
-		return tcproute.NewBuilder(id), nil
-	}
-	return nil, fmt.Errorf("NewBuilderByID: invalid Resource %q", id.Resource)
+	return rnode.NewBuilderByID(id)
 }
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package all
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+)
+
+func TestNewBuilderByID(t *testing.T) {
+	for _, resource := range []string{
+		"addresses",
+		"backendServices",
+		"fakes",
+		"firewalls",
+		"forwardingRules",
+		"healthChecks",
+		"networkEndpointGroups",
+		"sslCertificates",
+		"targetHttpProxies",
+		"targetHttpsProxies",
+		"tcpRoutes",
+		"urlMaps",
+	} {
+		id := &cloud.ResourceID{Resource: resource, ProjectID: "proj", Key: meta.GlobalKey("x")}
+		b, err := NewBuilderByID(id)
+		if err != nil {
+			t.Errorf("NewBuilderByID(%v) = %v, want nil", id, err)
+			continue
+		}
+		if !b.ID().Equal(id) {
+			t.Errorf("NewBuilderByID(%v).ID() = %v", id, b.ID())
+		}
+	}
+
+	if _, err := NewBuilderByID(&cloud.ResourceID{Resource: "invalid"}); err == nil {
+		t.Error("NewBuilderByID(invalid) = nil, want error")
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	if err := api.ValidateAll(); err != nil {
+		t.Fatalf("api.ValidateAll() = %v, want nil", err)
+	}
+	if len(api.RegisteredSchemas()) == 0 {
+		t.Error("api.RegisteredSchemas() is empty")
+	}
+}
+
+func TestNewBuilderWithResource(t *testing.T) {
+	b := ResourceBuilder{Project: "proj", Name: "hc"}
+	r, err := b.HealthCheck().Resource().Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	nb, err := rnode.NewBuilderWithResource(r)
+	if err != nil {
+		t.Fatalf("NewBuilderWithResource() = %v, want nil", err)
+	}
+	if !nb.ID().Equal(r.ResourceID()) || nb.Resource() != r {
+		t.Errorf("NewBuilderWithResource() = %+v, want Builder with resource %v", nb, r.ResourceID())
+	}
+}
+
+func TestRegisterCustomType(t *testing.T) {
+	const resource = "customTestResources"
+	rnode.Register(rnode.NodeType{
+		Resource:   resource,
+		NewBuilder: func(id *cloud.ResourceID) rnode.Builder { return fake.NewBuilder(id) },
+	})
+
+	found := false
+	for _, r := range rnode.RegisteredTypes() {
+		found = found || r == resource
+	}
+	if !found {
+		t.Errorf("RegisteredTypes() = %v, does not contain %q", rnode.RegisteredTypes(), resource)
+	}
+	id := &cloud.ResourceID{Resource: resource, ProjectID: "proj", Key: meta.GlobalKey("x")}
+	if _, err := NewBuilderByID(id); err != nil {
+		t.Errorf("NewBuilderByID(%v) = %v, want nil", id, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() twice did not panic")
+		}
+	}()
+	rnode.Register(rnode.NodeType{
+		Resource:   resource,
+		NewBuilder: func(id *cloud.ResourceID) rnode.Builder { return fake.NewBuilder(id) },
+	})
+}
@@ -18,6 +18,7 @@ package backendservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -218,6 +219,47 @@ func TestActionUpdate(t *testing.T) {
 	}
 }
 
+func TestActionUpdatePreconditions(t *testing.T) {
+	for _, tc := range []struct {
+		desc            string
+		cloudFP         string
+		noResource      bool
+		wantErr         bool
+		wantPrecondFail bool
+	}{
+		{desc: "fingerprint unchanged", cloudFP: fingerprintStr},
+		{desc: "fingerprint changed", cloudFP: "changed", wantErr: true, wantPrecondFail: true},
+		{desc: "resource deleted", noResource: true, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotNode, err := createBackendServiceNode("bs-name", func(m MutableBackendService) error { return nil })
+			if err != nil {
+				t.Fatalf("createBackendServiceNode(bs-name, _) = %v, want nil", err)
+			}
+			actions, err := rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, gotNode, gotNode, gotNode.resource, fingerprintStr)
+			if err != nil {
+				t.Fatalf("rnode.UpdateActions[]() = %v, want nil", err)
+			}
+			p, ok := actions[0].(exec.Preconditioner)
+			if !ok {
+				t.Fatalf("%T is not an exec.Preconditioner", actions[0])
+			}
+
+			mockCloud := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+			if !tc.noResource {
+				mockCloud.BackendServices().Insert(context.Background(), gotNode.ID().Key, &compute.BackendService{Fingerprint: tc.cloudFP})
+			}
+			err = p.CheckPreconditions(context.Background(), mockCloud)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("CheckPreconditions() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if got := errors.Is(err, exec.ErrPreconditionFailed); got != tc.wantPrecondFail {
+				t.Errorf("errors.Is(%v, ErrPreconditionFailed) = %t, want %t", err, got, tc.wantPrecondFail)
+			}
+		})
+	}
+}
+
 func TestBackendServiceDiff(t *testing.T) {
 	bsName := "bs-name"
 	for _, tc := range []struct {
@@ -1012,6 +1054,12 @@ func TestOutRefs(t *testing.T) {
 		ProjectID: proj,
 		Key:       meta.GlobalKey("esp-name"),
 	}
+	sbID := &cloud.ResourceID{
+		Resource:  "serviceBindings",
+		APIGroup:  meta.APIGroupNetworkServices,
+		ProjectID: proj,
+		Key:       meta.GlobalKey("sb-name"),
+	}
 	for _, tc := range []struct {
 		desc        string
 		resource    rnode.UntypedResource
@@ -1125,6 +1173,53 @@ func TestOutRefs(t *testing.T) {
 			}),
 			wantErr: true,
 		},
+		{
+			desc: "with serviceBindings",
+			resource: createBackendServiceResource(t, bsID, func(m MutableBackendService) error {
+				return m.Access(func(x *compute.BackendService) {
+					x.ServiceBindings = []string{
+						"projects/" + proj + "/locations/global/serviceBindings/sb-name",
+						"https://networkservices.googleapis.com/v1/projects/" + proj + "/locations/global/serviceBindings/sb-name",
+						sbID.SelfLink(meta.VersionGA),
+					}
+				})
+			}),
+			wantOutRefs: []rnode.ResourceRef{
+				{
+					From: bsID,
+					Path: api.Path{}.Field("ServiceBindings").Index(0),
+					To:   sbID,
+				},
+				{
+					From: bsID,
+					Path: api.Path{}.Field("ServiceBindings").Index(1),
+					To:   sbID,
+				},
+				{
+					From: bsID,
+					Path: api.Path{}.Field("ServiceBindings").Index(2),
+					To:   sbID,
+				},
+			},
+		},
+		{
+			desc: "with serviceBindings wrong format",
+			resource: createBackendServiceResource(t, bsID, func(m MutableBackendService) error {
+				return m.Access(func(x *compute.BackendService) {
+					x.ServiceBindings = []string{"projects/proj1/locations/global/meshes/mesh-name"}
+				})
+			}),
+			wantErr: true,
+		},
+		{
+			desc: "with serviceBindings of another resource",
+			resource: createBackendServiceResource(t, bsID, func(m MutableBackendService) error {
+				return m.Access(func(x *compute.BackendService) {
+					x.ServiceBindings = []string{hcID.SelfLink(meta.VersionGA)}
+				})
+			}),
+			wantErr: true,
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			bsBuilder := NewBuilder(bsID)
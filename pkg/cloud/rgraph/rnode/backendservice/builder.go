@@ -19,15 +19,21 @@ package backendservice
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
 )
 
+func init() {
+	rnode.Register(rnode.NewType("backendServices", NewBuilder, NewBuilderWithResource))
+}
+
 func NewBuilder(id *cloud.ResourceID) rnode.Builder {
 	b := &builder{}
 	b.Defaults(id)
@@ -116,7 +122,7 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 	if obj.EdgeSecurityPolicy != "" {
 		id, err := cloud.ParseResourceURL(obj.EdgeSecurityPolicy)
 		if err != nil {
-			return nil, fmt.Errorf("BackendServiceNode SecurityPolicy: %w", err)
+			return nil, fmt.Errorf("BackendServiceNode EdgeSecurityPolicy: %w", err)
 		}
 		ret = append(ret, rnode.ResourceRef{
 			From: b.ID(),
@@ -125,9 +131,52 @@ func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
 		})
 	}
 
+	// ServiceBindings[]
+	for idx, sb := range obj.ServiceBindings {
+		id, err := parseServiceBindingURL(sb)
+		if err != nil {
+			return nil, fmt.Errorf("BackendServiceNode ServiceBindings: %w", err)
+		}
+		ret = append(ret, rnode.ResourceRef{
+			From: b.ID(),
+			Path: api.Path{}.Field("ServiceBindings").Index(idx),
+			To:   id,
+		})
+	}
+
 	return ret, nil
 }
 
+// parseServiceBindingURL parses a ServiceBinding reference. ServiceBindings
+// are referenced by their networkservices resource name
+// ("projects/<project>/locations/global/serviceBindings/<name>"), optionally
+// prefixed with the service URL, which is not handled by
+// cloud.ParseResourceURL.
+func parseServiceBindingURL(url string) (*cloud.ResourceID, error) {
+	name := url
+	if i := strings.Index(name, "/projects/"); i >= 0 {
+		name = name[i+1:]
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) == 6 && parts[0] == "projects" && parts[2] == "locations" && parts[3] == "global" && parts[4] == "serviceBindings" {
+		return &cloud.ResourceID{
+			ProjectID: parts[1],
+			APIGroup:  meta.APIGroupNetworkServices,
+			Resource:  "serviceBindings",
+			Key:       meta.GlobalKey(parts[5]),
+		}, nil
+	}
+	id, err := cloud.ParseResourceURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if id.Resource != "serviceBindings" {
+		return nil, fmt.Errorf("%q is not a ServiceBinding", url)
+	}
+	id.APIGroup = meta.APIGroupNetworkServices
+	return id, nil
+}
+
 func (b *builder) Build() (rnode.Node, error) {
 	if b.State() == rnode.NodeExists && b.resource == nil {
 		return nil, fmt.Errorf("BackendService %s resource is nil with state %s", b.ID(), b.State())
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
@@ -35,6 +36,7 @@ type backendServiceNode struct {
 }
 
 var _ rnode.Node = (*backendServiceNode)(nil)
+var _ rnode.RefRewriter = (*backendServiceNode)(nil)
 
 func (n *backendServiceNode) Resource() rnode.UntypedResource { return n.resource }
 
@@ -137,23 +139,39 @@ func (n *backendServiceNode) Actions(got rnode.Node) ([]exec.Action, error) {
 		return rnode.RecreateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, got, n, n.resource)
 
 	case rnode.OpUpdate:
-		gotNode := got.(*backendServiceNode)
-		f, err := fingerprint(gotNode)
-		if err != nil {
-			return nil, fmt.Errorf("Cannot get fingerprint from BackendService: %w", err)
-		}
-		return rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, got, n, n.resource, f)
+		return n.updateActions(got)
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) { return n.updateActions(got) })
 	}
 
 	return nil, fmt.Errorf("BackendServiceNode: invalid plan op %s", op)
 }
 
+func (n *backendServiceNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	gotNode := got.(*backendServiceNode)
+	f, err := fingerprint(gotNode)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot get fingerprint from BackendService: %w", err)
+	}
+	return rnode.UpdateActions[compute.BackendService, alpha.BackendService, beta.BackendService](&ops{}, got, n, n.resource, f)
+}
+
 func (n *backendServiceNode) Builder() rnode.Builder {
 	b := &builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
 
+func (n *backendServiceNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
+
 /*
 name
 string
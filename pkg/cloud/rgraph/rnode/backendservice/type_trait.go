@@ -44,8 +44,7 @@ func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
 	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
 
 	dt.OutputOnly(api.Path{}.Pointer().Field("Iap").Pointer().Field("Oauth2ClientSecretSha256"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("CdnPolicy").Field("SignedUrlKeyNames"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("CdnPolicy").Pointer().Field("CacheKeyPolicy").Pointer().Field("SignedUrlKeyNames"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("CdnPolicy").Pointer().Field("SignedUrlKeyNames"))
 
 	dt.NonZeroValue(api.Path{}.Pointer().Field("LoadBalancingScheme"))
 	dt.NonZeroValue(api.Path{}.Pointer().Field("Protocol"))
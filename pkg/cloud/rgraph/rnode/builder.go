@@ -38,6 +38,12 @@ type Builder interface {
 	// SetOwnership of this resource.
 	SetOwnership(os OwnershipStatus)
 
+	// DeletionProtected is true if the resource must not be deleted or
+	// recreated by the planner.
+	DeletionProtected() bool
+	// SetDeletionProtected for this resource.
+	SetDeletionProtected(p bool)
+
 	// Resource (cloud type) for this Node.
 	Resource() UntypedResource
 	// SetResource to a new value.
@@ -72,6 +78,7 @@ type BuilderBase struct {
 	state     NodeState
 	ownership OwnershipStatus
 	version   meta.Version
+	protected bool
 
 	curInRefs []ResourceRef
 }
@@ -82,6 +89,8 @@ func (b *BuilderBase) SetState(state NodeState)        { b.state = state }
 func (b *BuilderBase) Ownership() OwnershipStatus      { return b.ownership }
 func (b *BuilderBase) SetOwnership(os OwnershipStatus) { b.ownership = os }
 func (b *BuilderBase) Version() meta.Version           { return b.version }
+func (b *BuilderBase) DeletionProtected() bool         { return b.protected }
+func (b *BuilderBase) SetDeletionProtected(p bool)     { b.protected = p }
 
 func (b *BuilderBase) AddInRef(ref ResourceRef) { b.curInRefs = append(b.curInRefs, ref) }
 func (b *BuilderBase) inRefs() []ResourceRef    { return b.curInRefs }
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+func newCreateAction(want exec.EventList, h Handler, id *cloud.ResourceID, value any) *createAction {
+	return &createAction{
+		ActionBase: exec.ActionBase{Want: want},
+		handler:    h,
+		id:         id,
+		value:      value,
+	}
+}
+
+type createAction struct {
+	exec.ActionBase
+	handler Handler
+	id      *cloud.ResourceID
+	value   any
+}
+
+func (a *createAction) Run(ctx context.Context, _ cloud.Cloud) (exec.EventList, error) {
+	err := a.handler.Create(ctx, a.id, a.value)
+	return exec.EventList{exec.NewExistsEvent(a.id)}, err
+}
+
+func (a *createAction) DryRun() exec.EventList {
+	return exec.EventList{exec.NewExistsEvent(a.id)}
+}
+
+func (a *createAction) String() string {
+	return fmt.Sprintf("CustomCreateAction(%v)", a.id)
+}
+
+func (a *createAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:     fmt.Sprintf("CustomCreateAction(%s)", a.id),
+		Type:     exec.ActionTypeCreate,
+		Summary:  fmt.Sprintf("Create %s", a.id),
+		Resource: a.id,
+	}
+}
+
+// Compensation implements exec.Compensator by deleting the created resource.
+func (a *createAction) Compensation() (exec.Action, error) {
+	return &deleteAction{handler: a.handler, id: a.id}, nil
+}
+
+func newDeleteAction(want exec.EventList, h Handler, got rnode.Node) *deleteAction {
+	act := &deleteAction{
+		ActionBase: exec.ActionBase{Want: want},
+		handler:    h,
+		id:         got.ID(),
+		outRefs:    got.OutRefs(),
+	}
+	// resource may be nil if the Node does not have a resource.
+	if r, ok := got.Resource().(*Resource); ok {
+		act.value = r.Value()
+		act.hasValue = true
+	}
+	return act
+}
+
+type deleteAction struct {
+	exec.ActionBase
+	handler Handler
+	id      *cloud.ResourceID
+	outRefs []rnode.ResourceRef
+	// value before deletion. This is used to recreate the resource in
+	// Compensation().
+	value    any
+	hasValue bool
+}
+
+func (a *deleteAction) Run(ctx context.Context, _ cloud.Cloud) (exec.EventList, error) {
+	err := a.handler.Delete(ctx, a.id)
+
+	// Event: Node no longer exists.
+	events := exec.EventList{exec.NewNotExistsEvent(a.id)}
+	for _, ref := range a.outRefs {
+		events = append(events, exec.NewDropRefEvent(ref.From, ref.To))
+	}
+	return events, err
+}
+
+func (a *deleteAction) DryRun() exec.EventList {
+	return exec.EventList{exec.NewNotExistsEvent(a.id)}
+}
+
+func (a *deleteAction) String() string {
+	return fmt.Sprintf("CustomDeleteAction(%v)", a.id)
+}
+
+func (a *deleteAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:     fmt.Sprintf("CustomDeleteAction(%s)", a.id),
+		Type:     exec.ActionTypeDelete,
+		Summary:  fmt.Sprintf("Delete %s", a.id),
+		Resource: a.id,
+	}
+}
+
+// Compensation implements exec.Compensator by creating the resource again.
+func (a *deleteAction) Compensation() (exec.Action, error) {
+	if !a.hasValue {
+		return nil, fmt.Errorf("%s: value of the deleted resource is unknown", a)
+	}
+	return newCreateAction(nil, a.handler, a.id, a.value), nil
+}
+
+func newUpdateAction(want, postEvents exec.EventList, h Handler, id *cloud.ResourceID, got, value any) *updateAction {
+	return &updateAction{
+		ActionBase: exec.ActionBase{Want: want},
+		handler:    h,
+		id:         id,
+		got:        got,
+		value:      value,
+		postEvents: postEvents,
+	}
+}
+
+type updateAction struct {
+	exec.ActionBase
+	handler Handler
+	id      *cloud.ResourceID
+	// got is the value before the update.
+	got        any
+	value      any
+	postEvents exec.EventList
+}
+
+func (a *updateAction) Run(ctx context.Context, _ cloud.Cloud) (exec.EventList, error) {
+	err := a.handler.Update(ctx, a.id, a.got, a.value)
+	return a.postEvents, err
+}
+
+func (a *updateAction) DryRun() exec.EventList {
+	return a.postEvents
+}
+
+func (a *updateAction) String() string {
+	return fmt.Sprintf("CustomUpdateAction(%v)", a.id)
+}
+
+func (a *updateAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:     fmt.Sprintf("CustomUpdateAction(%s)", a.id),
+		Type:     exec.ActionTypeUpdate,
+		Summary:  fmt.Sprintf("Update %s", a.id),
+		Resource: a.id,
+	}
+}
+
+// Compensation implements exec.Compensator by updating the resource back to
+// the value before the update.
+func (a *updateAction) Compensation() (exec.Action, error) {
+	return newUpdateAction(nil, nil, a.handler, a.id, a.value, a.got), nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// NewBuilder returns an empty Builder for a custom resource handled by h.
+func NewBuilder(id *cloud.ResourceID, h Handler) rnode.Builder {
+	b := &builder{handler: h}
+	b.Defaults(id)
+	return b
+}
+
+// NewBuilderWithResource returns a Builder containing r.
+func NewBuilderWithResource(r *Resource, h Handler) rnode.Builder {
+	b := &builder{handler: h, resource: r}
+	b.Init(r.ResourceID(), rnode.NodeExists, rnode.OwnershipUnknown, r)
+	return b
+}
+
+type builder struct {
+	rnode.BuilderBase
+	handler  Handler
+	resource *Resource
+}
+
+// builder implements node.Builder.
+var _ rnode.Builder = (*builder)(nil)
+
+func (b *builder) Resource() rnode.UntypedResource {
+	// Avoid returning a typed nil in the interface.
+	if b.resource == nil {
+		return nil
+	}
+	return b.resource
+}
+
+func (b *builder) SetResource(u rnode.UntypedResource) error {
+	r, ok := u.(*Resource)
+	if !ok {
+		return fmt.Errorf("custom %s: invalid type for SetResource: %T", b.ID(), u)
+	}
+	b.resource = r
+	return nil
+}
+
+func (b *builder) SyncFromCloud(ctx context.Context, _ cloud.Cloud) error {
+	v, err := b.handler.Get(ctx, b.ID())
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		b.SetState(rnode.NodeDoesNotExist)
+		return nil // Not found is not an error condition.
+
+	case err != nil:
+		b.SetState(rnode.NodeStateError)
+		return fmt.Errorf("custom %s: Get: %w", b.ID(), err)
+
+	default:
+		b.SetState(rnode.NodeExists)
+		b.resource = NewResource(b.ID(), v)
+		return nil
+	}
+}
+
+func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
+	if b.resource == nil {
+		return nil, nil
+	}
+	ids, err := b.handler.OutRefs(b.ID(), b.resource.Value())
+	if err != nil {
+		return nil, fmt.Errorf("custom %s: OutRefs: %w", b.ID(), err)
+	}
+	var ret []rnode.ResourceRef
+	for _, id := range ids {
+		ret = append(ret, rnode.ResourceRef{From: b.ID(), To: id})
+	}
+	return ret, nil
+}
+
+func (b *builder) Build() (rnode.Node, error) {
+	if b.State() == rnode.NodeExists && b.resource == nil {
+		return nil, fmt.Errorf("custom %s: resource is nil with state %s", b.ID(), b.State())
+	}
+
+	ret := &customNode{handler: b.handler, resource: b.resource}
+	if err := ret.InitFromBuilder(b); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package custom implements Nodes for resources that are not managed by the
+// GCE APIs (e.g. a DNS record in an external provider). The fetch, diff and
+// mutation operations are supplied by a user Handler.
+//
+// Custom Nodes participate in the graph like any other Node: OutRefs from a
+// custom Node to a GCE resource (or vice versa for the ordering of deletes)
+// gate the Actions in the same way as the references between GCE resources.
+package custom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// ErrNotFound is returned by Handler.Get when the resource does not exist.
+var ErrNotFound = errors.New("custom resource not found")
+
+// Handler implements the operations for a custom resource type. Values are
+// opaque to the graph library; they are only interpreted by the Handler.
+type Handler interface {
+	// Get the current value of the resource. Return ErrNotFound (or an error
+	// wrapping ErrNotFound) if the resource does not exist.
+	Get(ctx context.Context, id *cloud.ResourceID) (any, error)
+	// OutRefs returns the resources referenced by the value. These can be
+	// GCE resources or other custom resources.
+	OutRefs(id *cloud.ResourceID, value any) ([]*cloud.ResourceID, error)
+	// Diff the got and want values. The returned Operation must be one of
+	// OpNothing, OpUpdate or OpRecreate. A nil return value is equivalent to
+	// OpNothing.
+	Diff(got, want any) (*rnode.PlanDetails, error)
+	// Create the resource.
+	Create(ctx context.Context, id *cloud.ResourceID, value any) error
+	// Update the resource from got to want.
+	Update(ctx context.Context, id *cloud.ResourceID, got, want any) error
+	// Delete the resource.
+	Delete(ctx context.Context, id *cloud.ResourceID) error
+}
+
+// Resource is the value of a custom Node.
+type Resource struct {
+	id    *cloud.ResourceID
+	value any
+}
+
+// Resource implements rnode.UntypedResource.
+var _ rnode.UntypedResource = (*Resource)(nil)
+
+// NewResource returns a Resource with the given value. The value should not be
+// modified after it is added to the Resource.
+func NewResource(id *cloud.ResourceID, value any) *Resource {
+	return &Resource{id: id, value: value}
+}
+
+// ResourceID implements rnode.UntypedResource.
+func (r *Resource) ResourceID() *cloud.ResourceID { return r.id }
+
+// Version implements rnode.UntypedResource. Custom resources are not
+// versioned; this is always meta.VersionGA.
+func (r *Resource) Version() meta.Version { return meta.VersionGA }
+
+// Value of the resource.
+func (r *Resource) Value() any { return r.value }
+
+// NewType returns a NodeType for the custom resource type (this must be the
+// same as ResourceID.Resource) that is handled by h. The type should be
+// registered with rnode.Register() so that references to it can be followed
+// when the graph is fetched.
+func NewType(resource string, h Handler) rnode.NodeType {
+	return rnode.NodeType{
+		Resource:   resource,
+		NewBuilder: func(id *cloud.ResourceID) rnode.Builder { return NewBuilder(id, h) },
+		NewBuilderWithResource: func(u rnode.UntypedResource) (rnode.Builder, error) {
+			r, ok := u.(*Resource)
+			if !ok {
+				return nil, fmt.Errorf("%s: invalid type for resource: %T", resource, u)
+			}
+			return NewBuilderWithResource(r, h), nil
+		},
+	}
+}
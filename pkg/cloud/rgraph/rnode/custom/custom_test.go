@@ -0,0 +1,272 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	project     = "proj"
+	dnsResource = "dnsRecords"
+)
+
+// dnsRecord is an example resource in an external system.
+type dnsRecord struct {
+	Address *cloud.ResourceID
+	TTL     int
+}
+
+// dnsHandler stores the records in memory. It checks that the referenced
+// address exists in the mock when the record is created.
+type dnsHandler struct {
+	mock *cloud.MockGCE
+
+	lock    sync.Mutex
+	records map[string]dnsRecord
+	ops     []string
+}
+
+func (h *dnsHandler) Get(ctx context.Context, id *cloud.ResourceID) (any, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	r, ok := h.records[id.String()]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", id, ErrNotFound)
+	}
+	return r, nil
+}
+
+func (h *dnsHandler) OutRefs(id *cloud.ResourceID, value any) ([]*cloud.ResourceID, error) {
+	r, ok := value.(dnsRecord)
+	if !ok {
+		return nil, fmt.Errorf("invalid type %T", value)
+	}
+	return []*cloud.ResourceID{r.Address}, nil
+}
+
+func (h *dnsHandler) Diff(got, want any) (*rnode.PlanDetails, error) {
+	g, w := got.(dnsRecord), want.(dnsRecord)
+	if g == w {
+		return nil, nil
+	}
+	return &rnode.PlanDetails{
+		Operation: rnode.OpUpdate,
+		Why:       "record changed",
+		Diff: &api.DiffResult{Items: []api.DiffItem{
+			{State: api.DiffItemDifferent, Path: api.Path{}.Field("TTL")},
+		}},
+	}, nil
+}
+
+func (h *dnsHandler) Create(ctx context.Context, id *cloud.ResourceID, value any) error {
+	r := value.(dnsRecord)
+	if _, err := h.mock.GlobalAddresses().Get(ctx, r.Address.Key); err != nil {
+		return fmt.Errorf("address %s must exist before %s: %w", r.Address, id, err)
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.records[id.String()] = r
+	h.ops = append(h.ops, "create")
+	return nil
+}
+
+func (h *dnsHandler) Update(ctx context.Context, id *cloud.ResourceID, got, want any) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.records[id.String()] = want.(dnsRecord)
+	h.ops = append(h.ops, "update")
+	return nil
+}
+
+func (h *dnsHandler) Delete(ctx context.Context, id *cloud.ResourceID) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.records, id.String())
+	h.ops = append(h.ops, "delete")
+	return nil
+}
+
+func dnsID() *cloud.ResourceID {
+	return &cloud.ResourceID{
+		ProjectID: "zone-1",
+		APIGroup:  "dns.example.com",
+		Resource:  dnsResource,
+		Key:       meta.GlobalKey("www.example.com"),
+	}
+}
+
+func TestCustomNode(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	h := &dnsHandler{mock: mock, records: map[string]dnsRecord{}}
+	addrID := address.ID(project, meta.GlobalKey("addr"))
+
+	mock.MockGlobalAddresses.DeleteHook = func(ctx context.Context, key *meta.Key, m *cloud.MockGlobalAddresses, options ...cloud.Option) (bool, error) {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if len(h.records) > 0 {
+			return true, fmt.Errorf("address deleted before the DNS record")
+		}
+		return false, nil
+	}
+
+	buildGraph := func(state rnode.NodeState, ttl int) *rgraph.Graph {
+		gr := rgraph.NewBuilder()
+
+		ma := address.NewMutableAddress(project, addrID.Key)
+		ra, _ := ma.Freeze()
+		ab := address.NewBuilderWithResource(ra)
+		ab.SetOwnership(rnode.OwnershipManaged)
+		ab.SetState(state)
+		gr.Add(ab)
+
+		db := NewBuilderWithResource(NewResource(dnsID(), dnsRecord{Address: addrID, TTL: ttl}), h)
+		db.SetOwnership(rnode.OwnershipManaged)
+		db.SetState(state)
+		gr.Add(db)
+
+		return gr.MustBuild()
+	}
+
+	for _, step := range []struct {
+		name    string
+		state   rnode.NodeState
+		ttl     int
+		wantOps map[string]rnode.Operation
+		// wantHandlerOps are the calls to the Handler after the step.
+		wantHandlerOps []string
+		wantRecords    int
+	}{
+		{
+			name:  "create",
+			state: rnode.NodeExists,
+			ttl:   300,
+			wantOps: map[string]rnode.Operation{
+				"addresses": rnode.OpCreate,
+				dnsResource: rnode.OpCreate,
+			},
+			wantHandlerOps: []string{"create"},
+			wantRecords:    1,
+		},
+		{
+			name:  "no changes",
+			state: rnode.NodeExists,
+			ttl:   300,
+			wantOps: map[string]rnode.Operation{
+				"addresses": rnode.OpNothing,
+				dnsResource: rnode.OpNothing,
+			},
+			wantHandlerOps: []string{"create"},
+			wantRecords:    1,
+		},
+		{
+			name:  "update",
+			state: rnode.NodeExists,
+			ttl:   600,
+			wantOps: map[string]rnode.Operation{
+				"addresses": rnode.OpNothing,
+				dnsResource: rnode.OpUpdate,
+			},
+			wantHandlerOps: []string{"create", "update"},
+			wantRecords:    1,
+		},
+		{
+			name:  "delete",
+			state: rnode.NodeDoesNotExist,
+			wantOps: map[string]rnode.Operation{
+				"addresses": rnode.OpDelete,
+				dnsResource: rnode.OpDelete,
+			},
+			wantHandlerOps: []string{"create", "update", "delete"},
+		},
+	} {
+		// Steps depend on the state from the previous step.
+		res, err := plan.Do(ctx, mock, buildGraph(step.state, step.ttl))
+		if err != nil {
+			t.Fatalf("%s: plan.Do() = %v, want nil", step.name, err)
+		}
+		gotOps := map[string]rnode.Operation{}
+		for _, n := range res.Want.All() {
+			gotOps[n.ID().Resource] = n.Plan().Op()
+		}
+		if diff := cmp.Diff(gotOps, step.wantOps); diff != "" {
+			t.Fatalf("%s: ops: -got,+want: %s", step.name, diff)
+		}
+
+		ex, err := exec.NewParallelExecutor(mock, res.Actions)
+		if err != nil {
+			t.Fatalf("%s: NewParallelExecutor() = %v, want nil", step.name, err)
+		}
+		if result, err := ex.Run(ctx); err != nil {
+			t.Fatalf("%s: Run() = %v, want nil (result: %+v)", step.name, err, result)
+		}
+
+		if diff := cmp.Diff(h.ops, step.wantHandlerOps); diff != "" {
+			t.Errorf("%s: handler ops: -got,+want: %s", step.name, diff)
+		}
+		if len(h.records) != step.wantRecords {
+			t.Errorf("%s: len(records) = %d, want %d", step.name, len(h.records), step.wantRecords)
+		}
+		if step.wantRecords > 0 && h.records[dnsID().String()].TTL != step.ttl {
+			t.Errorf("%s: record = %+v, want TTL %d", step.name, h.records[dnsID().String()], step.ttl)
+		}
+	}
+	if _, err := mock.GlobalAddresses().Get(ctx, addrID.Key); err == nil {
+		t.Errorf("address %s exists after delete", addrID)
+	}
+}
+
+func TestNewType(t *testing.T) {
+	h := &dnsHandler{records: map[string]dnsRecord{}}
+	nt := NewType(dnsResource, h)
+	b := nt.NewBuilder(dnsID())
+	if !b.ID().Equal(dnsID()) {
+		t.Errorf("ID() = %v, want %v", b.ID(), dnsID())
+	}
+	if err := b.SyncFromCloud(context.Background(), nil); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if b.State() != rnode.NodeDoesNotExist {
+		t.Errorf("State() = %s, want %s", b.State(), rnode.NodeDoesNotExist)
+	}
+
+	h.records[dnsID().String()] = dnsRecord{TTL: 10}
+	nb, err := nt.NewBuilderWithResource(NewResource(dnsID(), dnsRecord{}))
+	if err != nil {
+		t.Fatalf("NewBuilderWithResource() = %v, want nil", err)
+	}
+	if err := nb.SyncFromCloud(context.Background(), nil); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if got := nb.Resource().(*Resource).Value(); got != (dnsRecord{TTL: 10}) {
+		t.Errorf("Value() = %+v, want TTL 10", got)
+	}
+}
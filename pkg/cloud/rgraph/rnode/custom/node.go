@@ -0,0 +1,132 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+type customNode struct {
+	rnode.NodeBase
+	handler  Handler
+	resource *Resource
+}
+
+var _ rnode.Node = (*customNode)(nil)
+
+func (n *customNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *customNode) value() any {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource.Value()
+}
+
+func (n *customNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	gotRes, ok := gotNode.Resource().(*Resource)
+	if !ok {
+		return nil, fmt.Errorf("custom %s: invalid type to Diff: %T", n.ID(), gotNode.Resource())
+	}
+
+	details, err := n.handler.Diff(gotRes.Value(), n.value())
+	if err != nil {
+		return nil, fmt.Errorf("custom %s: Diff: %w", n.ID(), err)
+	}
+	if details == nil {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpNothing,
+			Why:       "No diff between got and want",
+		}, nil
+	}
+
+	switch details.Operation {
+	case rnode.OpNothing, rnode.OpUpdate, rnode.OpRecreate:
+		return details, nil
+	}
+	return nil, fmt.Errorf("custom %s: Diff returned invalid operation %s", n.ID(), details.Operation)
+}
+
+func (n *customNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return n.createActions()
+
+	case rnode.OpDelete:
+		return []exec.Action{newDeleteAction(rnode.DeletePreconditions(got, n), n.handler, got)}, nil
+
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+
+	case rnode.OpRecreate:
+		createActions, err := n.createActions()
+		if err != nil {
+			return nil, err
+		}
+		create := createActions[0].(*createAction)
+		// Condition: resource must have been deleted.
+		create.Want = append(create.Want, exec.NewNotExistsEvent(n.ID()))
+		del := newDeleteAction(rnode.DeletePreconditions(got, n), n.handler, got)
+		return []exec.Action{del, create}, nil
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) { return n.updateActions(got) })
+	}
+
+	return nil, fmt.Errorf("custom %s: invalid plan op %s", n.ID(), op)
+}
+
+func (n *customNode) createActions() ([]exec.Action, error) {
+	events, err := rnode.CreatePreconditions(n)
+	if err != nil {
+		return nil, err
+	}
+	return []exec.Action{newCreateAction(events, n.handler, n.ID(), n.value())}, nil
+}
+
+func (n *customNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	preEvents, err := rnode.UpdatePreconditions(got, n)
+	if err != nil {
+		return nil, err
+	}
+	var gotValue any
+	if r, ok := got.Resource().(*Resource); ok {
+		gotValue = r.Value()
+	}
+	postEvents := rnode.PostUpdateActionEvents(got, n)
+	return []exec.Action{newUpdateAction(preEvents, postEvents, n.handler, n.ID(), gotValue, n.value())}, nil
+}
+
+func (n *customNode) Builder() rnode.Builder {
+	b := &builder{handler: n.handler}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetDeletionProtected(n.DeletionProtected())
+	return b
+}
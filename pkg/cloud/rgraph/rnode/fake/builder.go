@@ -26,6 +26,13 @@ import (
 	"k8s.io/klog/v2"
 )
 
+func init() {
+	rnode.Register(rnode.NodeType{
+		Resource:   "fakes",
+		NewBuilder: func(id *cloud.ResourceID) rnode.Builder { return NewBuilder(id) },
+	})
+}
+
 // NewBuilder returns a Node builder.
 func NewBuilder(id *cloud.ResourceID) *Builder {
 	b := &Builder{}
@@ -70,6 +70,8 @@ func (n *fakeNode) Actions(got rnode.Node) ([]exec.Action, error) {
 		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
 	case rnode.OpUpdate:
 		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+	case rnode.OpAdopt:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
 	}
 
 	return nil, fmt.Errorf("fakeNode %s: invalid plan op %s", n.ID(), op)
@@ -78,5 +80,6 @@ func (n *fakeNode) Actions(got rnode.Node) ([]exec.Action, error) {
 func (n *fakeNode) Builder() rnode.Builder {
 	b := &Builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), nil)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
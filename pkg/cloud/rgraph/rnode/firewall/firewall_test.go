@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	cloudmock "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/rnodetest"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj    = "proj-1"
+	network = "https://www.googleapis.com/compute/v1/projects/proj-1/global/networks/default"
+)
+
+func TestFirewallSchema(t *testing.T) {
+	key := meta.GlobalKey("key-1")
+	x := NewMutableFirewall(proj, key)
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func newFirewall(x *compute.Firewall) {
+	x.Network = network
+	x.Direction = "INGRESS"
+	x.Priority = 1000
+	x.SourceRanges = []string{"130.211.0.0/22"}
+	x.TargetTags = []string{"backend"}
+	x.Allowed = []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"8080"}}}
+}
+
+func node(t *testing.T, f func(x *compute.Firewall)) rnode.Node {
+	t.Helper()
+	return rnodetest.Node(t, NewMutableFirewall(proj, meta.GlobalKey("fw")), func(x *compute.Firewall) {
+		newFirewall(x)
+		f(x)
+	}, NewBuilderWithResource)
+}
+
+func TestFirewallDiff(t *testing.T) {
+	got := node(t, func(*compute.Firewall) {})
+
+	for _, tc := range []struct {
+		name   string
+		f      func(x *compute.Firewall)
+		wantOp rnode.Operation
+	}{
+		{
+			name:   "no diff",
+			f:      func(*compute.Firewall) {},
+			wantOp: rnode.OpNothing,
+		},
+		{
+			name:   "source ranges",
+			f:      func(x *compute.Firewall) { x.SourceRanges = append(x.SourceRanges, "35.191.0.0/16") },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "ports",
+			f:      func(x *compute.Firewall) { x.Allowed[0].Ports = []string{"8081"} },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "target tags",
+			f:      func(x *compute.Firewall) { x.TargetTags = []string{"other"} },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "priority",
+			f:      func(x *compute.Firewall) { x.Priority = 900 },
+			wantOp: rnode.OpUpdate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := node(t, tc.f)
+			pd, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if pd.Operation != tc.wantOp {
+				t.Errorf("Diff() = %+v, want Operation %v", pd, tc.wantOp)
+			}
+		})
+	}
+}
+
+func TestFirewallUpdate(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	mock.MockFirewalls.UpdateHook = cloudmock.UpdateFirewallHook
+
+	existing := &compute.Firewall{}
+	newFirewall(existing)
+	mock.Firewalls().Insert(ctx, meta.GlobalKey("fw"), existing)
+
+	got := node(t, func(*compute.Firewall) {})
+	want := node(t, func(x *compute.Firewall) {
+		x.SourceRanges = []string{"130.211.0.0/22", "35.191.0.0/16"}
+		x.Allowed = []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"8080", "8443"}}}
+	})
+	pd, err := want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	want.Plan().Set(*pd)
+	actions, err := want.Actions(got)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	if len(actions) != 1 || actions[0].Metadata().Type != exec.ActionTypeUpdate {
+		t.Fatalf("Actions() = %v, want a single update", actions)
+	}
+	if _, err := actions[0].Run(ctx, mock); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	fw, err := mock.Firewalls().Get(ctx, meta.GlobalKey("fw"))
+	if err != nil {
+		t.Fatalf("Firewalls().Get() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(fw.SourceRanges, []string{"130.211.0.0/22", "35.191.0.0/16"}); diff != "" {
+		t.Errorf("SourceRanges: -got,+want: %s", diff)
+	}
+	wantAllowed := []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"8080", "8443"}}}
+	if diff := cmp.Diff(fw.Allowed, wantAllowed); diff != "" {
+		t.Errorf("Allowed: -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(fw.TargetTags, []string{"backend"}); diff != "" {
+		t.Errorf("TargetTags: -got,+want: %s", diff)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package firewall
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type firewallNode struct {
+	rnode.NodeBase
+	resource Firewall
+}
+
+var _ rnode.Node = (*firewallNode)(nil)
+var _ rnode.RefRewriter = (*firewallNode)(nil)
+
+func (n *firewallNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *firewallNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*firewallNode)
+	if !ok {
+		return nil, fmt.Errorf("FirewallNode: invalid type to Diff: %T", gotNode)
+	}
+
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("FirewallNode: Diff %w", err)
+	}
+
+	if diff.HasDiff() {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpUpdate,
+			Why:       "Firewall update",
+			Diff:      diff,
+		}, nil
+	}
+
+	return &rnode.PlanDetails{
+		Operation: rnode.OpNothing,
+		Why:       "No diff between got and want",
+	}, nil
+}
+
+func (n *firewallNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.Firewall, alpha.Firewall, beta.Firewall](&ops{}, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.Firewall, alpha.Firewall, beta.Firewall](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.Firewall, alpha.Firewall, beta.Firewall](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return rnode.UpdateActions[compute.Firewall, alpha.Firewall, beta.Firewall](&ops{}, got, n, n.resource, "")
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) {
+			return rnode.UpdateActions[compute.Firewall, alpha.Firewall, beta.Firewall](&ops{}, got, n, n.resource, "")
+		})
+	}
+
+	return nil, fmt.Errorf("FirewallNode: invalid plan op %s", op)
+}
+
+func (n *firewallNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
+	return b
+}
+
+func (n *firewallNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package firewall
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.Firewall, alpha.Firewall, beta.Firewall] {
+	return &rnode.GetFuncs[compute.Firewall, alpha.Firewall, beta.Firewall]{
+		GA: rnode.GetFuncsByScope[compute.Firewall]{
+			Global: gcp.Firewalls().Get,
+		},
+		Alpha: rnode.GetFuncsByScope[alpha.Firewall]{
+			Global: gcp.AlphaFirewalls().Get,
+		},
+		Beta: rnode.GetFuncsByScope[beta.Firewall]{
+			Global: gcp.BetaFirewalls().Get,
+		},
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.Firewall, alpha.Firewall, beta.Firewall] {
+	return &rnode.CreateFuncs[compute.Firewall, alpha.Firewall, beta.Firewall]{
+		GA: rnode.CreateFuncsByScope[compute.Firewall]{
+			Global: gcp.Firewalls().Insert,
+		},
+		Alpha: rnode.CreateFuncsByScope[alpha.Firewall]{
+			Global: gcp.AlphaFirewalls().Insert,
+		},
+		Beta: rnode.CreateFuncsByScope[beta.Firewall]{
+			Global: gcp.BetaFirewalls().Insert,
+		},
+	}
+}
+
+func (*ops) UpdateFuncs(gcp cloud.Cloud) *rnode.UpdateFuncs[compute.Firewall, alpha.Firewall, beta.Firewall] {
+	return &rnode.UpdateFuncs[compute.Firewall, alpha.Firewall, beta.Firewall]{
+		GA: rnode.UpdateFuncsByScope[compute.Firewall]{
+			Global: gcp.Firewalls().Update,
+		},
+		Alpha: rnode.UpdateFuncsByScope[alpha.Firewall]{
+			Global: gcp.AlphaFirewalls().Update,
+		},
+		Beta: rnode.UpdateFuncsByScope[beta.Firewall]{
+			Global: gcp.BetaFirewalls().Update,
+		},
+		Options: rnode.UpdateFuncsNoFingerprint,
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.Firewall, alpha.Firewall, beta.Firewall] {
+	return &rnode.DeleteFuncs[compute.Firewall, alpha.Firewall, beta.Firewall]{
+		GA: rnode.DeleteFuncsByScope[compute.Firewall]{
+			Global: gcp.Firewalls().Delete,
+		},
+		Alpha: rnode.DeleteFuncsByScope[alpha.Firewall]{
+			Global: gcp.AlphaFirewalls().Delete,
+		},
+		Beta: rnode.DeleteFuncsByScope[beta.Firewall]{
+			Global: gcp.BetaFirewalls().Delete,
+		},
+	}
+}
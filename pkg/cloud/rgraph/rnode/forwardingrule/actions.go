@@ -23,6 +23,9 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
 )
 
@@ -48,11 +51,12 @@ func forwardingRuleSetLabels(
 	return fmt.Errorf("forwardingRuleMethodsByScope: invalid scope %v", key.Type())
 }
 
-func newForwardingRuleCreateAction(id *cloud.ResourceID, res ForwardingRule, want exec.EventList) exec.Action {
+func newForwardingRuleCreateAction(n *forwardingRuleNode, want exec.EventList) exec.Action {
 	return &forwardingRuleCreateAction{
 		ActionBase: exec.ActionBase{Want: want},
-		id:         id,
-		res:        res,
+		id:         n.ID(),
+		res:        n.resource,
+		node:       n,
 	}
 }
 
@@ -60,6 +64,9 @@ type forwardingRuleCreateAction struct {
 	exec.ActionBase
 	id  *cloud.ResourceID
 	res ForwardingRule
+	// node that is created. This is used to delete the resource in
+	// Compensation().
+	node rnode.Node
 }
 
 func (act *forwardingRuleCreateAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
@@ -97,12 +104,19 @@ func (act *forwardingRuleCreateAction) String() string {
 
 func (act *forwardingRuleCreateAction) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("ForwardingRuleCreateAction(%s)", act.id),
-		Type:    exec.ActionTypeCreate,
-		Summary: fmt.Sprintf("Create %s", act.id),
+		Name:     fmt.Sprintf("ForwardingRuleCreateAction(%s)", act.id),
+		Type:     exec.ActionTypeCreate,
+		Summary:  fmt.Sprintf("Create %s", act.id),
+		Resource: act.id,
+		Version:  act.res.Version(),
 	}
 }
 
+// Compensation implements exec.Compensator by deleting the created resource.
+func (act *forwardingRuleCreateAction) Compensation() (exec.Action, error) {
+	return rnode.NewGenericDeleteAction[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule](nil, &ops{}, act.node), nil
+}
+
 type forwardingRuleUpdateAction struct {
 	exec.ActionBase
 
@@ -116,15 +130,30 @@ type forwardingRuleUpdateAction struct {
 	labelFingerprint string
 	// labels if non-nil will call setLabels().
 	labels map[string]string
+	// gotLabels are the labels before the update. This is used to revert
+	// the update in Compensation().
+	gotLabels map[string]string
+	// fetchLabelFingerprint will get the current labelFingerprint of the
+	// resource before the update instead of using labelFingerprint.
+	fetchLabelFingerprint bool
 }
 
 func (act *forwardingRuleUpdateAction) Run(ctx context.Context, cl cloud.Cloud) (exec.EventList, error) {
 	// TODO: project routing.
 	if act.labels != nil {
+		labelFingerprint := act.labelFingerprint
+		if act.fetchLabelFingerprint {
+			res, err := (&ops{}).GetFuncs(cl).Do(ctx, meta.VersionGA, act.id, &typeTrait{})
+			if err != nil {
+				return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): Get: %w", act.id, err)
+			}
+			ga, _ := res.ToGA()
+			labelFingerprint = ga.LabelFingerprint
+		}
 		switch act.id.Key.Type() {
 		case meta.Global:
 			err := cl.GlobalForwardingRules().SetLabels(ctx, act.id.Key, &compute.GlobalSetLabelsRequest{
-				LabelFingerprint: act.labelFingerprint,
+				LabelFingerprint: labelFingerprint,
 				Labels:           act.labels,
 			})
 			if err != nil {
@@ -132,7 +161,7 @@ func (act *forwardingRuleUpdateAction) Run(ctx context.Context, cl cloud.Cloud)
 			}
 		case meta.Regional:
 			err := cl.ForwardingRules().SetLabels(ctx, act.id.Key, &compute.RegionSetLabelsRequest{
-				LabelFingerprint: act.labelFingerprint,
+				LabelFingerprint: labelFingerprint,
 				Labels:           act.labels,
 			})
 			if err != nil {
@@ -153,7 +182,7 @@ func (act *forwardingRuleUpdateAction) Run(ctx context.Context, cl cloud.Cloud)
 				return nil, fmt.Errorf("forwardingRuleUpdateAction Run(%s): SetTarget: %w", act.id, err)
 			}
 		case meta.Regional:
-			err := cl.GlobalForwardingRules().SetTarget(ctx, act.id.Key, &compute.TargetReference{
+			err := cl.ForwardingRules().SetTarget(ctx, act.id.Key, &compute.TargetReference{
 				Target: act.target.SelfLink(meta.VersionGA),
 			})
 			if err != nil {
@@ -186,8 +215,36 @@ func (act *forwardingRuleUpdateAction) String() string {
 
 func (act *forwardingRuleUpdateAction) Metadata() *exec.ActionMetadata {
 	return &exec.ActionMetadata{
-		Name:    fmt.Sprintf("ForwardingRuleUpdateAction(%s)", act.id),
-		Type:    exec.ActionTypeUpdate,
-		Summary: fmt.Sprintf("Update %s", act.id),
+		Name:     fmt.Sprintf("ForwardingRuleUpdateAction(%s)", act.id),
+		Type:     exec.ActionTypeUpdate,
+		Summary:  fmt.Sprintf("Update %s", act.id),
+		Resource: act.id,
+		Version:  meta.VersionGA,
+	}
+}
+
+// Compensation implements exec.Compensator by setting the Target and the
+// Labels back to the values before the update.
+func (act *forwardingRuleUpdateAction) Compensation() (exec.Action, error) {
+	if act.target == nil && act.labels == nil {
+		return nil, nil
+	}
+	ret := &forwardingRuleUpdateAction{id: act.id}
+	if act.target != nil {
+		if act.oldTarget == nil {
+			return nil, fmt.Errorf("%s: Target before the update is unknown", act)
+		}
+		ret.target = act.oldTarget
+		ret.oldTarget = act.target
+	}
+	if act.labels != nil {
+		ret.labels = act.gotLabels
+		if ret.labels == nil {
+			// A nil map would skip SetLabels().
+			ret.labels = map[string]string{}
+		}
+		// The labelFingerprint will have changed due to the update.
+		ret.fetchLabelFingerprint = true
 	}
+	return ret, nil
 }
@@ -29,6 +29,10 @@ import (
 	"google.golang.org/api/compute/v1"
 )
 
+func init() {
+	rnode.Register(rnode.NewType("forwardingRules", NewBuilder, NewBuilderWithResource))
+}
+
 func NewBuilder(id *cloud.ResourceID) rnode.Builder {
 	b := &builder{}
 	b.Defaults(id)
@@ -36,6 +36,7 @@ type forwardingRuleNode struct {
 }
 
 var _ rnode.Node = (*forwardingRuleNode)(nil)
+var _ rnode.RefRewriter = (*forwardingRuleNode)(nil)
 
 func (n *forwardingRuleNode) Resource() rnode.UntypedResource { return n.resource }
 
@@ -127,6 +128,9 @@ func (n *forwardingRuleNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 	case rnode.OpUpdate:
 		return n.updateActions(got)
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) { return n.updateActions(got) })
 	}
 	return nil, nodeErr("invalid plan op %s", op)
 }
@@ -134,16 +138,25 @@ func (n *forwardingRuleNode) Actions(got rnode.Node) ([]exec.Action, error) {
 func (n *forwardingRuleNode) Builder() rnode.Builder {
 	b := &builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
 
+func (n *forwardingRuleNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
+
 func (n *forwardingRuleNode) createActions() ([]exec.Action, error) {
 	want, err := rnode.CreatePreconditions(n)
 	if err != nil {
 		return nil, err
 	}
 	return []exec.Action{
-		newForwardingRuleCreateAction(n.ID(), n.resource, want),
+		newForwardingRuleCreateAction(n, want),
 	}, nil
 }
 
@@ -184,6 +197,7 @@ func (n *forwardingRuleNode) updateActions(ngot rnode.Node) ([]exec.Action, erro
 		wantRes, _ := n.resource.ToGA()
 		act.labelFingerprint = gotRes.LabelFingerprint
 		act.labels = wantRes.Labels
+		act.gotLabels = gotRes.Labels
 	}
 
 	return []exec.Action{
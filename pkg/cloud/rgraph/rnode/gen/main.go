@@ -0,0 +1,561 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generator for the skeleton of rnode packages. Each entry in nodeTypes
+// describes a node type; the generator emits the boilerplate (builder, node,
+// ops, type trait) for the entries that do not have a package yet:
+//
+//	$ go run ./pkg/cloud/rgraph/rnode/gen -dir pkg/cloud/rgraph/rnode
+//
+// The generated code is a starting point and is expected to be edited by hand
+// (e.g. the FieldTraits, references in OutRefs and the planning in Diff).
+// Existing packages are never overwritten.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// nodeType describes an rnode package to generate.
+type nodeType struct {
+	// Package is the name of the Go package, e.g. "healthcheck".
+	Package string
+	// Object is the name of the resource in the compute API (see
+	// meta.ServiceInfo.Object), e.g. "HealthCheck".
+	Object string
+	// NoFingerprint is true if the resource does not have a .Fingerprint
+	// field to use for Update.
+	NoFingerprint bool
+}
+
+// nodeTypes are the node types in the rnode package. Add an entry to this list
+// to create a new node type.
+var nodeTypes = []nodeType{
+	{Package: "address", Object: "Address"},
+	{Package: "backendservice", Object: "BackendService"},
+	{Package: "firewall", Object: "Firewall", NoFingerprint: true},
+	{Package: "forwardingrule", Object: "ForwardingRule"},
+	{Package: "healthcheck", Object: "HealthCheck", NoFingerprint: true},
+	{Package: "networkendpointgroup", Object: "NetworkEndpointGroup"},
+	{Package: "sslcertificate", Object: "SslCertificate"},
+	{Package: "targethttpproxy", Object: "TargetHttpProxy"},
+	{Package: "targethttpsproxy", Object: "TargetHttpsProxy"},
+	{Package: "urlmap", Object: "UrlMap"},
+}
+
+var flags = struct {
+	dir     string
+	pkg     string
+	dryRun  bool
+	verbose bool
+	year    int
+}{}
+
+func init() {
+	flag.StringVar(&flags.dir, "dir", "pkg/cloud/rgraph/rnode", "directory containing the rnode packages")
+	flag.StringVar(&flags.pkg, "package", "", "only generate the given package")
+	flag.BoolVar(&flags.dryRun, "dryrun", false, "print the generated files instead of writing them")
+	flag.BoolVar(&flags.verbose, "v", false, "verbose output")
+	flag.IntVar(&flags.year, "year", 2023, "year in the copyright header of the generated files")
+}
+
+// scopeData is the service to use for a scope (e.g. Global).
+type scopeData struct {
+	Scope   string
+	Service string
+}
+
+// versionData are the services by scope for an API version.
+type versionData struct {
+	Version string
+	Package string
+	Scopes  []scopeData
+}
+
+// funcsData is the data for an rnode.XXXFuncs.
+type funcsData struct {
+	// Kind of the funcs, e.g. "Get" for rnode.GetFuncs.
+	Kind string
+	// Method to call on the service, e.g. "Get".
+	Method   string
+	Versions []versionData
+}
+
+// typeData is the data for the templates.
+type typeData struct {
+	nodeType
+	Year     int
+	Resource string
+	Funcs    []funcsData
+	Update   bool
+}
+
+// NodeType is the name of the Node type, e.g. "healthCheckNode".
+func (d *typeData) NodeType() string {
+	return strings.ToLower(d.Object[:1]) + d.Object[1:] + "Node"
+}
+
+// Generics are the type parameters for the resource.
+func (d *typeData) Generics() string {
+	return fmt.Sprintf("compute.%[1]s, alpha.%[1]s, beta.%[1]s", d.Object)
+}
+
+// methodForKind maps the kind of rnode funcs to the method in the service.
+var methodForKind = []struct{ kind, method string }{
+	{"Get", "Get"},
+	{"Create", "Insert"},
+	{"Update", "Update"},
+	{"Delete", "Delete"},
+}
+
+// newTypeData finds the services for nt in meta.AllServices.
+func newTypeData(nt nodeType) (*typeData, error) {
+	var services []*meta.ServiceInfo
+	for _, si := range meta.AllServices {
+		if si.Object == nt.Object && si.APIGroup == meta.APIGroupCompute {
+			services = append(services, si)
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no compute services found for %q", nt.Object)
+	}
+
+	d := &typeData{
+		nodeType: nt,
+		Year:     flags.year,
+		Resource: services[0].Resource,
+	}
+	for _, mk := range methodForKind {
+		fd := funcsData{Kind: mk.kind, Method: mk.method}
+		for _, v := range []struct {
+			version meta.Version
+			name    string
+			pkg     string
+		}{
+			{meta.VersionGA, "GA", "compute"},
+			{meta.VersionAlpha, "Alpha", "alpha"},
+			{meta.VersionBeta, "Beta", "beta"},
+		} {
+			vd := versionData{Version: v.name, Package: v.pkg}
+			for _, scope := range []struct {
+				name string
+				f    func(*meta.ServiceInfo) bool
+			}{
+				{"Global", (*meta.ServiceInfo).KeyIsGlobal},
+				{"Regional", (*meta.ServiceInfo).KeyIsRegional},
+				{"Zonal", (*meta.ServiceInfo).KeyIsZonal},
+			} {
+				for _, si := range services {
+					if si.Version() != v.version || !scope.f(si) || !hasMethod(si, mk.method) {
+						continue
+					}
+					vd.Scopes = append(vd.Scopes, scopeData{Scope: scope.name, Service: si.WrapType()})
+					break
+				}
+			}
+			if len(vd.Scopes) > 0 {
+				fd.Versions = append(fd.Versions, vd)
+			}
+		}
+		if mk.kind == "Update" {
+			d.Update = len(fd.Versions) > 0
+		}
+		d.Funcs = append(d.Funcs, fd)
+	}
+	return d, nil
+}
+
+func hasMethod(si *meta.ServiceInfo, method string) bool {
+	switch method {
+	case "Get":
+		return si.GenerateGet()
+	case "Insert":
+		return si.GenerateInsert()
+	case "Delete":
+		return si.GenerateDelete()
+	}
+	for _, m := range si.Methods() {
+		if m.Name() == method {
+			return true
+		}
+	}
+	return false
+}
+
+const header = `/*
+Copyright {{.Year}} Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package {{.Package}}
+`
+
+// computeImports are the imports for the compute API types.
+const computeImports = `	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+`
+
+var files = map[string]string{
+	"{{.Package}}.go": header + `
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+` + computeImports + `
+func init() {
+	api.RegisterSchema("compute/{{.Resource}}", NewMutable{{.Object}}("project", meta.GlobalKey("key")))
+}
+
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  "{{.Resource}}",
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+type Mutable{{.Object}} = api.MutableResource[{{.Generics}}]
+
+func NewMutable{{.Object}}(project string, key *meta.Key) Mutable{{.Object}} {
+	id := ID(project, key)
+	return api.NewResource[
+		compute.{{.Object}},
+		alpha.{{.Object}},
+		beta.{{.Object}},
+	](id, &typeTrait{})
+}
+
+type {{.Object}} = api.Resource[{{.Generics}}]
+`,
+
+	"builder.go": header + `
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+` + computeImports + `
+func init() {
+	rnode.Register(rnode.NewType("{{.Resource}}", NewBuilder, NewBuilderWithResource))
+}
+
+func NewBuilder(id *cloud.ResourceID) rnode.Builder {
+	b := &builder{}
+	b.Defaults(id)
+	return b
+}
+
+func NewBuilderWithResource(r {{.Object}}) rnode.Builder {
+	b := &builder{resource: r}
+	b.Init(r.ResourceID(), rnode.NodeUnknown, rnode.OwnershipUnknown, r)
+	return b
+}
+
+type builder struct {
+	rnode.BuilderBase
+	resource {{.Object}}
+}
+
+// builder implements node.Builder.
+var _ rnode.Builder = (*builder)(nil)
+
+func (b *builder) Resource() rnode.UntypedResource { return b.resource }
+
+func (b *builder) SetResource(u rnode.UntypedResource) error {
+	r, ok := u.({{.Object}})
+	if !ok {
+		return fmt.Errorf("SetResource: invalid type: %T, want {{.Object}}", u)
+	}
+	b.resource = r
+	return nil
+}
+
+func (b *builder) SyncFromCloud(ctx context.Context, gcp cloud.Cloud) error {
+	return rnode.GenericGet[{{.Generics}}](
+		ctx, gcp, "{{.Object}}", &ops{}, &typeTrait{}, b)
+}
+
+func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
+	// TODO: return the references to other resources.
+	return nil, nil
+}
+
+func (b *builder) Build() (rnode.Node, error) {
+	if b.State() == rnode.NodeExists && b.resource == nil {
+		return nil, fmt.Errorf("{{.Object}} %s resource is nil with state %s", b.ID(), b.State())
+	}
+
+	ret := &{{.NodeType}}{resource: b.resource}
+	if err := ret.InitFromBuilder(b); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+`,
+
+	"node.go": header + `
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+` + computeImports + `
+type {{.NodeType}} struct {
+	rnode.NodeBase
+	resource {{.Object}}
+}
+
+var _ rnode.Node = (*{{.NodeType}})(nil)
+var _ rnode.RefRewriter = (*{{.NodeType}})(nil)
+
+func (n *{{.NodeType}}) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *{{.NodeType}}) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*{{.NodeType}})
+	if !ok {
+		return nil, fmt.Errorf("{{.Object}}Node: invalid type to Diff: %T", gotNode)
+	}
+
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("{{.Object}}Node: Diff %w", err)
+	}
+
+	if diff.HasDiff() {
+{{- if .Update}}
+		return &rnode.PlanDetails{
+			Operation: rnode.OpUpdate,
+			Why:       "{{.Object}} update",
+			Diff:      diff,
+		}, nil
+{{- else}}
+		return &rnode.PlanDetails{
+			Operation: rnode.OpRecreate,
+			Why:       "{{.Object}} needs to be recreated (no update method exists)",
+			Diff:      diff,
+		}, nil
+{{- end}}
+	}
+
+	return &rnode.PlanDetails{
+		Operation: rnode.OpNothing,
+		Why:       "No diff between got and want",
+	}, nil
+}
+
+func (n *{{.NodeType}}) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[{{.Generics}}](&ops{}, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[{{.Generics}}](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[{{.Generics}}](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+{{- if .Update}}
+		return rnode.UpdateActions[{{.Generics}}](&ops{}, got, n, n.resource, "")
+{{- else}}
+		return nil, fmt.Errorf("%s is not supported for {{.Object}}", op)
+{{- end}}
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) {
+{{- if .Update}}
+			return rnode.UpdateActions[{{.Generics}}](&ops{}, got, n, n.resource, "")
+{{- else}}
+			return nil, fmt.Errorf("%s with changes is not supported for {{.Object}}", op)
+{{- end}}
+		})
+	}
+
+	return nil, fmt.Errorf("{{.Object}}Node: invalid plan op %s", op)
+}
+
+func (n *{{.NodeType}}) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
+	return b
+}
+
+func (n *{{.NodeType}}) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
+`,
+
+	"ops.go": header + `
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+` + computeImports + `
+type ops struct{}
+{{range .Funcs}}
+func (*ops) {{.Kind}}Funcs(gcp cloud.Cloud) *rnode.{{.Kind}}Funcs[{{$.Generics}}] {
+{{- if not .Versions}}
+	return nil // Does not support generic {{.Kind}}.
+{{- else}}
+	return &rnode.{{.Kind}}Funcs[{{$.Generics}}]{
+{{- $funcs := .}}
+{{- range .Versions}}
+		{{.Version}}: rnode.{{$funcs.Kind}}FuncsByScope[{{.Package}}.{{$.Object}}]{
+{{- range .Scopes}}
+			{{.Scope}}: gcp.{{.Service}}().{{$funcs.Method}},
+{{- end}}
+		},
+{{- end}}
+{{- if and (eq .Kind "Update") $.NoFingerprint}}
+		Options: rnode.UpdateFuncsNoFingerprint,
+{{- end}}
+	}
+{{- end}}
+}
+{{end}}`,
+
+	"type_trait.go": header + `
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+` + computeImports + `
+type typeTrait struct {
+	api.BaseTypeTrait[{{.Generics}}]
+}
+
+func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// [Output Only]
+	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Kind"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
+
+	// TODO: add the remaining field traits for {{.Object}}.
+
+	if v == meta.VersionAlpha {
+		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
+	}
+
+	return dt
+}
+`,
+}
+
+// genFiles returns the generated files for nt by file name.
+func genFiles(nt nodeType) (map[string][]byte, error) {
+	d, err := newTypeData(nt)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string][]byte{}
+	for nameTmpl, text := range files {
+		var name, content bytes.Buffer
+		if err := template.Must(template.New("name").Parse(nameTmpl)).Execute(&name, d); err != nil {
+			return nil, err
+		}
+		if err := template.Must(template.New(name.String()).Parse(text)).Execute(&content, d); err != nil {
+			return nil, fmt.Errorf("%s: %w", name.String(), err)
+		}
+		src, err := format.Source(content.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: gofmt: %w\n%s", name.String(), err, content.String())
+		}
+		ret[name.String()] = src
+	}
+	return ret, nil
+}
+
+func main() {
+	flag.Parse()
+
+	for _, nt := range nodeTypes {
+		if flags.pkg != "" && flags.pkg != nt.Package {
+			continue
+		}
+		dir := filepath.Join(flags.dir, nt.Package)
+		if _, err := os.Stat(dir); err == nil && !flags.dryRun {
+			if flags.verbose {
+				log.Printf("%s exists, skipping", dir)
+			}
+			continue
+		}
+
+		out, err := genFiles(nt)
+		if err != nil {
+			log.Fatalf("%s: %v", nt.Package, err)
+		}
+		if flags.dryRun {
+			var names []string
+			for name := range out {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("// %s\n%s\n", filepath.Join(dir, name), out[name])
+			}
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Fatal(err)
+		}
+		for name, src := range out {
+			if err := os.WriteFile(filepath.Join(dir, name), src, 0644); err != nil {
+				log.Fatal(err)
+			}
+		}
+		log.Printf("Generated %s, add the package to the imports in the all package", dir)
+	}
+}
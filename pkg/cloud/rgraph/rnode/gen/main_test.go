@@ -0,0 +1,86 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenFiles(t *testing.T) {
+	for _, nt := range nodeTypes {
+		out, err := genFiles(nt)
+		if err != nil {
+			t.Errorf("genFiles(%+v) = %v, want nil", nt, err)
+			continue
+		}
+		for _, name := range []string{nt.Package + ".go", "builder.go", "node.go", "ops.go", "type_trait.go"} {
+			src, ok := out[name]
+			if !ok {
+				t.Errorf("genFiles(%+v): missing file %q", nt, name)
+				continue
+			}
+			// The output must not depend on when the generator is run.
+			if !strings.Contains(string(src), "Copyright 2023 Google LLC") {
+				t.Errorf("genFiles(%+v): %s does not have the copyright year 2023", nt, name)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		nt       nodeType
+		wantOps  []string
+		wantNode []string
+	}{
+		{
+			nt: nodeType{Package: "address", Object: "Address"},
+			wantOps: []string{
+				"Regional: gcp.Addresses().Insert",
+				"return nil // Does not support generic Update.",
+			},
+			wantNode: []string{"rnode.OpRecreate"},
+		},
+		{
+			nt: nodeType{Package: "healthcheck", Object: "HealthCheck", NoFingerprint: true},
+			wantOps: []string{
+				"Global:   gcp.HealthChecks().Update",
+				"Regional: gcp.BetaRegionHealthChecks().Update",
+				"Options: rnode.UpdateFuncsNoFingerprint",
+			},
+			wantNode: []string{"rnode.UpdateActions"},
+		},
+	} {
+		out, err := genFiles(tc.nt)
+		if err != nil {
+			t.Fatalf("genFiles(%+v) = %v, want nil", tc.nt, err)
+		}
+		for _, s := range tc.wantOps {
+			if !strings.Contains(string(out["ops.go"]), s) {
+				t.Errorf("genFiles(%+v): ops.go does not contain %q", tc.nt, s)
+			}
+		}
+		for _, s := range tc.wantNode {
+			if !strings.Contains(string(out["node.go"]), s) {
+				t.Errorf("genFiles(%+v): node.go does not contain %q", tc.nt, s)
+			}
+		}
+	}
+
+	if _, err := genFiles(nodeType{Package: "invalid", Object: "Invalid"}); err == nil {
+		t.Errorf("genFiles(Invalid) = nil, want error")
+	}
+}
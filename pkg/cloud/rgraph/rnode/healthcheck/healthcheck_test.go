@@ -158,12 +158,20 @@ func TestHealthCheckAlphaFields(t *testing.T) {
 		x.HealthyThreshold = 10
 		x.CheckIntervalSec = 7
 		x.TimeoutSec = 5
-		x.Type = "UDP"
+		x.Type = "TCP"
 		x.UnhealthyThreshold = 4
 	})
 	if err != nil {
 		t.Fatalf("hcMutRes.Access(_) = %v, want nil", err)
 	}
+	// Check that Access will return error when the Type is only valid in
+	// Alpha.
+	err = hcMutRes.Access(func(x *compute.HealthCheck) {
+		x.Type = "UDP"
+	})
+	if err == nil {
+		t.Fatalf("hcMutRes.Access(_) = %v, want err", err)
+	}
 	// Check that Access will return error when OutputOnly fields are set
 	err = hcMutRes.AccessAlpha(func(x *alpha.HealthCheck) {
 		x.SelfLinkWithId = "hc-1"
@@ -174,6 +182,7 @@ func TestHealthCheckAlphaFields(t *testing.T) {
 	// Set Alpha specific fields
 	err = hcMutRes.AccessAlpha(func(x *alpha.HealthCheck) {
 		x.SelfLinkWithId = ""
+		x.Type = "UDP"
 		x.UdpHealthCheck = &alpha.UDPHealthCheck{Port: 60}
 	})
 	if err != nil {
@@ -19,6 +19,7 @@ package healthcheck
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	alpha "google.golang.org/api/compute/v0.alpha"
@@ -32,6 +33,7 @@ type healthCheckNode struct {
 }
 
 var _ rnode.Node = (*healthCheckNode)(nil)
+var _ rnode.RefRewriter = (*healthCheckNode)(nil)
 
 func (n *healthCheckNode) Resource() rnode.UntypedResource { return n.resource }
 
@@ -78,6 +80,11 @@ func (n *healthCheckNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 	case rnode.OpUpdate:
 		return rnode.UpdateActions[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck](&healthCheckOps{}, got, n, n.resource, "")
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) {
+			return rnode.UpdateActions[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck](&healthCheckOps{}, got, n, n.resource, "")
+		})
 	}
 
 	return nil, fmt.Errorf("HealthCheckNode: invalid plan op %s", op)
@@ -86,5 +93,14 @@ func (n *healthCheckNode) Actions(got rnode.Node) ([]exec.Action, error) {
 func (n *healthCheckNode) Builder() rnode.Builder {
 	b := &builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
+
+func (n *healthCheckNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
@@ -29,33 +29,35 @@ type typeTrait struct {
 	api.BaseTypeTrait[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck]
 }
 
+// schema of the field traits for HealthCheck. See api.FieldTraitsFromSchema.
+var schema = api.MustNewSchemaTypeTrait[compute.HealthCheck, alpha.HealthCheck, beta.HealthCheck](`
+	# [Output Only]
+	OutputOnly    CreationTimestamp
+	OutputOnly    Id
+	OutputOnly    Kind
+	OutputOnly    Region
+	OutputOnly    SelfLink
+	OutputOnly    SelfLinkWithId              alpha
+
+	# This field is not supported
+	OutputOnly    GrpcHealthCheck.PortName
+	OutputOnly    Http2HealthCheck.PortName
+	OutputOnly    HttpHealthCheck.PortName
+	OutputOnly    SslHealthCheck.PortName
+	OutputOnly    HttpsHealthCheck.PortName
+	OutputOnly    UdpHealthCheck.PortName     alpha
+
+	# required fields
+	NonZeroValue  HealthyThreshold
+	NonZeroValue  UnhealthyThreshold
+	NonZeroValue  CheckIntervalSec
+	NonZeroValue  TimeoutSec
+	NonZeroValue  Type
+
+	Enum          Type                        GRPC,HTTP,HTTP2,HTTPS,SSL,TCP
+	Enum          Type                        UDP         alpha
+`)
+
 func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
-	dt := api.NewFieldTraits()
-	// [Output Only]
-	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("Kind"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
-
-	// This field is not supported
-	dt.OutputOnly(api.Path{}.Pointer().Field("GrpcHealthCheck").Pointer().Field("PortName"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("Http2HealthCheck").Pointer().Field("PortName"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("HttpHealthCheck").Pointer().Field("PortName"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("SslHealthCheck").Pointer().Field("PortName"))
-	dt.OutputOnly(api.Path{}.Pointer().Field("HttpsHealthCheck").Pointer().Field("PortName"))
-
-	// required fields
-	dt.NonZeroValue(api.Path{}.Pointer().Field("HealthyThreshold"))
-	dt.NonZeroValue(api.Path{}.Pointer().Field("UnhealthyThreshold"))
-	dt.NonZeroValue(api.Path{}.Pointer().Field("CheckIntervalSec"))
-	dt.NonZeroValue(api.Path{}.Pointer().Field("TimeoutSec"))
-	dt.NonZeroValue(api.Path{}.Pointer().Field("Type"))
-
-	if v == meta.VersionAlpha {
-		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
-		dt.OutputOnly(api.Path{}.Pointer().Field("UdpHealthCheck").Pointer().Field("PortName"))
-	}
-
-	return dt
+	return schema.FieldTraits(v)
 }
@@ -27,6 +27,10 @@ import (
 	"google.golang.org/api/compute/v1"
 )
 
+func init() {
+	rnode.Register(rnode.NewType("networkEndpointGroups", NewBuilder, NewBuilderWithResource))
+}
+
 func NewBuilder(id *cloud.ResourceID) rnode.Builder {
 	b := &builder{}
 	b.Defaults(id)
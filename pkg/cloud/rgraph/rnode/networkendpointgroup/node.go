@@ -19,6 +19,7 @@ package networkendpointgroup
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	alpha "google.golang.org/api/compute/v0.alpha"
@@ -32,6 +33,7 @@ type networkEndpointGroupNode struct {
 }
 
 var _ rnode.Node = (*networkEndpointGroupNode)(nil)
+var _ rnode.RefRewriter = (*networkEndpointGroupNode)(nil)
 
 func (n *networkEndpointGroupNode) Resource() rnode.UntypedResource { return n.resource }
 
@@ -84,6 +86,11 @@ func (n *networkEndpointGroupNode) Actions(got rnode.Node) ([]exec.Action, error
 
 	case rnode.OpUpdate:
 		// TODO
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) {
+			return nil, fmt.Errorf("%s with changes is not supported for NetworkEndpointGroup", op)
+		})
 	}
 
 	return nil, fmt.Errorf("NetworkEndpointGroupNode: invalid plan op %s", op)
@@ -92,5 +99,14 @@ func (n *networkEndpointGroupNode) Actions(got rnode.Node) ([]exec.Action, error
 func (n *networkEndpointGroupNode) Builder() rnode.Builder {
 	b := &builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
+
+func (n *networkEndpointGroupNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
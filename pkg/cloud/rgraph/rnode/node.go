@@ -36,6 +36,9 @@ type Node interface {
 	State() NodeState
 	// Ownership of this resource.
 	Ownership() OwnershipStatus
+	// DeletionProtected is true if the resource must not be deleted or
+	// recreated. Planning fails if the plan would do so.
+	DeletionProtected() bool
 	// OutRefs of this resource pointing to other resources.
 	OutRefs() []ResourceRef
 	// InRefs pointing to this resource.
@@ -63,6 +66,7 @@ type NodeBase struct {
 	id        *cloud.ResourceID
 	state     NodeState
 	ownership OwnershipStatus
+	protected bool
 	outRefs   []ResourceRef
 	inRefs    []ResourceRef
 	plan      Plan
@@ -71,6 +75,7 @@ type NodeBase struct {
 func (n *NodeBase) ID() *cloud.ResourceID      { return n.id }
 func (n *NodeBase) State() NodeState           { return n.state }
 func (n *NodeBase) Ownership() OwnershipStatus { return n.ownership }
+func (n *NodeBase) DeletionProtected() bool    { return n.protected }
 func (n *NodeBase) OutRefs() []ResourceRef     { return n.outRefs }
 func (n *NodeBase) InRefs() []ResourceRef      { return n.inRefs }
 func (n *NodeBase) Plan() *Plan                { return &n.plan }
@@ -81,6 +86,7 @@ func (n *NodeBase) InitFromBuilder(b Builder) error {
 	n.id = b.ID()
 	n.state = b.State()
 	n.ownership = b.Ownership()
+	n.protected = b.DeletionProtected()
 	outRefs, err := b.OutRefs()
 	if err != nil {
 		return err
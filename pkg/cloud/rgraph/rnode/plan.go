@@ -51,6 +51,10 @@ var (
 	OpUpdate Operation = "Update"
 	// OpDelete will delete the resource.
 	OpDelete Operation = "Delete"
+	// OpAdopt will take ownership of an existing resource that was not
+	// previously managed. If the resource differs from the wanted state, it
+	// will be updated in place. Adoption never recreates the resource.
+	OpAdopt Operation = "Adopt"
 )
 
 // PlanDetails is a human-readable reasons describing the Sync operation that
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// NodeType describes a type of Node for the registry. Packages implementing a
+// Node type call Register() from init() so that the type can be found by the
+// resource name (e.g. when following references to other resources).
+type NodeType struct {
+	// Resource is the resource type in cloud.ResourceID.Resource (e.g.
+	// "addresses").
+	Resource string
+	// NewBuilder returns an empty Builder for the resource id.
+	NewBuilder func(id *cloud.ResourceID) Builder
+	// NewBuilderWithResource returns a Builder containing the resource. This
+	// is optional; the default calls NewBuilder() and SetResource().
+	NewBuilderWithResource func(r UntypedResource) (Builder, error)
+}
+
+// NewType returns a NodeType for the typed constructors that are defined by
+// the Node packages.
+func NewType[R UntypedResource](resource string, newBuilder func(*cloud.ResourceID) Builder, newBuilderWithResource func(R) Builder) NodeType {
+	return NodeType{
+		Resource:   resource,
+		NewBuilder: newBuilder,
+		NewBuilderWithResource: func(u UntypedResource) (Builder, error) {
+			r, ok := u.(R)
+			if !ok {
+				return nil, fmt.Errorf("%s: invalid type for resource: %T", resource, u)
+			}
+			return newBuilderWithResource(r), nil
+		},
+	}
+}
+
+var registry = struct {
+	lock  sync.RWMutex
+	types map[string]NodeType
+}{types: map[string]NodeType{}}
+
+// Register the NodeType. This panics if the type is invalid or if the
+// Resource has already been registered.
+func Register(t NodeType) {
+	if t.Resource == "" || t.NewBuilder == nil {
+		panic(fmt.Sprintf("rnode.Register: invalid NodeType %+v", t))
+	}
+	if t.NewBuilderWithResource == nil {
+		newBuilder := t.NewBuilder
+		t.NewBuilderWithResource = func(r UntypedResource) (Builder, error) {
+			b := newBuilder(r.ResourceID())
+			if err := b.SetResource(r); err != nil {
+				return nil, err
+			}
+			return b, nil
+		}
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	if _, ok := registry.types[t.Resource]; ok {
+		panic(fmt.Sprintf("rnode.Register: %q registered twice", t.Resource))
+	}
+	registry.types[t.Resource] = t
+}
+
+// LookupType returns the registered NodeType for the resource type.
+func LookupType(resource string) (NodeType, bool) {
+	registry.lock.RLock()
+	defer registry.lock.RUnlock()
+	t, ok := registry.types[resource]
+	return t, ok
+}
+
+// RegisteredTypes returns the sorted list of resource types in the registry.
+func RegisteredTypes() []string {
+	registry.lock.RLock()
+	defer registry.lock.RUnlock()
+	var ret []string
+	for r := range registry.types {
+		ret = append(ret, r)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// NewBuilderByID returns an empty Builder for the id using the registered
+// NodeType.
+func NewBuilderByID(id *cloud.ResourceID) (Builder, error) {
+	t, ok := LookupType(id.Resource)
+	if !ok {
+		return nil, fmt.Errorf("NewBuilderByID: invalid Resource %q", id.Resource)
+	}
+	return t.NewBuilder(id), nil
+}
+
+// NewBuilderWithResource returns a Builder containing r using the registered
+// NodeType.
+func NewBuilderWithResource(r UntypedResource) (Builder, error) {
+	id := r.ResourceID()
+	t, ok := LookupType(id.Resource)
+	if !ok {
+		return nil, fmt.Errorf("NewBuilderWithResource: invalid Resource %q", id.Resource)
+	}
+	return t.NewBuilderWithResource(r)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+)
+
+// RefRewriter is implemented by Nodes that can be copied with their resource
+// references rewritten. This is used to replace a resource with a copy under a
+// different name (see OpRecreate).
+type RefRewriter interface {
+	// RewriteRefs returns a Builder for a copy of the Node with the given id.
+	// References to the keys in renames are replaced with the corresponding
+	// ID.
+	RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (Builder, error)
+}
+
+// RewriteRefs returns a copy of the resource with the given id. All string
+// fields that are resource references to the keys in renames are rewritten to
+// refer to the new ID. The format of the reference (e.g. full URL vs relative
+// name) is preserved.
+func RewriteRefs[GA any, Alpha any, Beta any](
+	r api.Resource[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	renames map[cloud.ResourceMapKey]*cloud.ResourceID,
+) (api.Resource[GA, Alpha, Beta], error) {
+	rewrite := func(_ api.Path, s string) string {
+		ref, err := cloud.ParseResourceURL(s)
+		if err != nil || ref.Key == nil {
+			return s
+		}
+		newID, ok := renames[ref.MapKey()]
+		if !ok {
+			return s
+		}
+		return strings.Replace(s, ref.RelativeResourceName(), newID.RelativeResourceName(), 1)
+	}
+	ret, err := api.CopyWithID(r, id, rewrite)
+	if err != nil {
+		return nil, fmt.Errorf("RewriteRefs(%v): %w", id, err)
+	}
+	return ret, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package sslcertificate
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] {
+	return &rnode.GetFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]{
+		GA: rnode.GetFuncsByScope[compute.SslCertificate]{
+			Global:   gcp.SslCertificates().Get,
+			Regional: gcp.RegionSslCertificates().Get,
+		},
+		Alpha: rnode.GetFuncsByScope[alpha.SslCertificate]{
+			Global:   gcp.AlphaSslCertificates().Get,
+			Regional: gcp.AlphaRegionSslCertificates().Get,
+		},
+		Beta: rnode.GetFuncsByScope[beta.SslCertificate]{
+			Global:   gcp.BetaSslCertificates().Get,
+			Regional: gcp.BetaRegionSslCertificates().Get,
+		},
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] {
+	return &rnode.CreateFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]{
+		GA: rnode.CreateFuncsByScope[compute.SslCertificate]{
+			Global:   gcp.SslCertificates().Insert,
+			Regional: gcp.RegionSslCertificates().Insert,
+		},
+		Alpha: rnode.CreateFuncsByScope[alpha.SslCertificate]{
+			Global:   gcp.AlphaSslCertificates().Insert,
+			Regional: gcp.AlphaRegionSslCertificates().Insert,
+		},
+		Beta: rnode.CreateFuncsByScope[beta.SslCertificate]{
+			Global:   gcp.BetaSslCertificates().Insert,
+			Regional: gcp.BetaRegionSslCertificates().Insert,
+		},
+	}
+}
+
+func (*ops) UpdateFuncs(gcp cloud.Cloud) *rnode.UpdateFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] {
+	return nil // Does not support generic Update.
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] {
+	return &rnode.DeleteFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]{
+		GA: rnode.DeleteFuncsByScope[compute.SslCertificate]{
+			Global:   gcp.SslCertificates().Delete,
+			Regional: gcp.RegionSslCertificates().Delete,
+		},
+		Alpha: rnode.DeleteFuncsByScope[alpha.SslCertificate]{
+			Global:   gcp.AlphaSslCertificates().Delete,
+			Regional: gcp.AlphaRegionSslCertificates().Delete,
+		},
+		Beta: rnode.DeleteFuncsByScope[beta.SslCertificate]{
+			Global:   gcp.BetaSslCertificates().Delete,
+			Regional: gcp.BetaRegionSslCertificates().Delete,
+		},
+	}
+}
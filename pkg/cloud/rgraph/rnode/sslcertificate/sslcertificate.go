@@ -0,0 +1,54 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package sslcertificate
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+func init() {
+	api.RegisterSchema("compute/sslCertificates", NewMutableSslCertificate("project", meta.GlobalKey("key")))
+}
+
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  "sslCertificates",
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+type MutableSslCertificate = api.MutableResource[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]
+
+func NewMutableSslCertificate(project string, key *meta.Key) MutableSslCertificate {
+	id := ID(project, key)
+	return api.NewResource[
+		compute.SslCertificate,
+		alpha.SslCertificate,
+		beta.SslCertificate,
+	](id, &typeTrait{})
+}
+
+type SslCertificate = api.Resource[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]
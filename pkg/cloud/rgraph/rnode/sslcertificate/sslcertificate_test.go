@@ -0,0 +1,75 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sslcertificate
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/rnodetest"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestSslCertificateSchema(t *testing.T) {
+	const proj = "proj-1"
+	key := meta.GlobalKey("key-1")
+	x := NewMutableSslCertificate(proj, key)
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func TestSslCertificateDiffIgnoresPrivateKey(t *testing.T) {
+	const proj = "proj-1"
+	key := meta.GlobalKey("key-1")
+
+	node := func(f func(x *compute.SslCertificate)) rnode.Node {
+		t.Helper()
+		return rnodetest.Node(t, NewMutableSslCertificate(proj, key), f, NewBuilderWithResource)
+	}
+	// The API does not return the private key.
+	got := node(func(x *compute.SslCertificate) {
+		x.Certificate = "cert"
+		x.SelfManaged = &compute.SslCertificateSelfManagedSslCertificate{Certificate: "cert"}
+	})
+	want := node(func(x *compute.SslCertificate) {
+		x.Certificate = "cert"
+		x.PrivateKey = "key"
+		x.SelfManaged = &compute.SslCertificateSelfManagedSslCertificate{Certificate: "cert", PrivateKey: "key"}
+	})
+
+	pd, err := want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	if pd.Operation != rnode.OpNothing {
+		t.Errorf("Diff() = %+v, want Operation %v", pd, rnode.OpNothing)
+	}
+
+	want = node(func(x *compute.SslCertificate) {
+		x.Certificate = "cert2"
+		x.PrivateKey = "key"
+	})
+	pd, err = want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	if pd.Operation != rnode.OpRecreate {
+		t.Errorf("Diff() = %+v, want Operation %v", pd, rnode.OpRecreate)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package sslcertificate
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+// writeOnlyFields are not returned by the API. These are ignored by Diff.
+var writeOnlyFields = []api.Path{
+	api.Path{}.Pointer().Field("PrivateKey"),
+	api.Path{}.Pointer().Field("SelfManaged").Pointer().Field("PrivateKey"),
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]
+}
+
+func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// [Output Only]
+	dt.OutputOnly(api.Path{}.Pointer().Field("CreationTimestamp"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Id"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Kind"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("ExpireTime"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("SubjectAlternativeNames"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Managed").Pointer().Field("DomainStatus"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Managed").Pointer().Field("Status"))
+
+	dt.Enum(api.Path{}.Pointer().Field("Type"), "MANAGED", "SELF_MANAGED", "TYPE_UNSPECIFIED")
+
+	if v == meta.VersionAlpha {
+		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
+	}
+
+	return dt
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package targethttpsproxy
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type targetHttpsProxyNode struct {
+	rnode.NodeBase
+	resource TargetHttpsProxy
+}
+
+var _ rnode.Node = (*targetHttpsProxyNode)(nil)
+var _ rnode.RefRewriter = (*targetHttpsProxyNode)(nil)
+
+func (n *targetHttpsProxyNode) Resource() rnode.UntypedResource { return n.resource }
+
+func (n *targetHttpsProxyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*targetHttpsProxyNode)
+	if !ok {
+		return nil, fmt.Errorf("TargetHttpsProxyNode: invalid type to Diff: %T", gotNode)
+	}
+
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("TargetHttpsProxyNode: Diff %w", err)
+	}
+
+	if diff.HasDiff() {
+		return &rnode.PlanDetails{
+			Operation: rnode.OpRecreate,
+			Why:       "TargetHttpsProxy needs to be recreated (no update method exists)",
+			Diff:      diff,
+		}, nil
+	}
+
+	return &rnode.PlanDetails{
+		Operation: rnode.OpNothing,
+		Why:       "No diff between got and want",
+	}, nil
+}
+
+func (n *targetHttpsProxyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy](&ops{}, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return []exec.Action{exec.NewExistsAction(n.ID())}, nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return nil, fmt.Errorf("%s is not supported for TargetHttpsProxy", op)
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) {
+			return nil, fmt.Errorf("%s with changes is not supported for TargetHttpsProxy", op)
+		})
+	}
+
+	return nil, fmt.Errorf("TargetHttpsProxyNode: invalid plan op %s", op)
+}
+
+func (n *targetHttpsProxyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
+	return b
+}
+
+func (n *targetHttpsProxyNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
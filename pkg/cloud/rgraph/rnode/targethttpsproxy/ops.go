@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generated by "go run ./pkg/cloud/rgraph/rnode/gen".
+
+package targethttpsproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] {
+	return &rnode.GetFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]{
+		GA: rnode.GetFuncsByScope[compute.TargetHttpsProxy]{
+			Global:   gcp.TargetHttpsProxies().Get,
+			Regional: gcp.RegionTargetHttpsProxies().Get,
+		},
+		Alpha: rnode.GetFuncsByScope[alpha.TargetHttpsProxy]{
+			Global:   gcp.AlphaTargetHttpsProxies().Get,
+			Regional: gcp.AlphaRegionTargetHttpsProxies().Get,
+		},
+		Beta: rnode.GetFuncsByScope[beta.TargetHttpsProxy]{
+			Global:   gcp.BetaTargetHttpsProxies().Get,
+			Regional: gcp.BetaRegionTargetHttpsProxies().Get,
+		},
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] {
+	return &rnode.CreateFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]{
+		GA: rnode.CreateFuncsByScope[compute.TargetHttpsProxy]{
+			Global:   gcp.TargetHttpsProxies().Insert,
+			Regional: gcp.RegionTargetHttpsProxies().Insert,
+		},
+		Alpha: rnode.CreateFuncsByScope[alpha.TargetHttpsProxy]{
+			Global:   gcp.AlphaTargetHttpsProxies().Insert,
+			Regional: gcp.AlphaRegionTargetHttpsProxies().Insert,
+		},
+		Beta: rnode.CreateFuncsByScope[beta.TargetHttpsProxy]{
+			Global:   gcp.BetaTargetHttpsProxies().Insert,
+			Regional: gcp.BetaRegionTargetHttpsProxies().Insert,
+		},
+	}
+}
+
+func (*ops) UpdateFuncs(gcp cloud.Cloud) *rnode.UpdateFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] {
+	return nil // Does not support generic Update.
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] {
+	return &rnode.DeleteFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]{
+		GA: rnode.DeleteFuncsByScope[compute.TargetHttpsProxy]{
+			Global:   gcp.TargetHttpsProxies().Delete,
+			Regional: gcp.RegionTargetHttpsProxies().Delete,
+		},
+		Alpha: rnode.DeleteFuncsByScope[alpha.TargetHttpsProxy]{
+			Global:   gcp.AlphaTargetHttpsProxies().Delete,
+			Regional: gcp.AlphaRegionTargetHttpsProxies().Delete,
+		},
+		Beta: rnode.DeleteFuncsByScope[beta.TargetHttpsProxy]{
+			Global:   gcp.BetaTargetHttpsProxies().Delete,
+			Regional: gcp.BetaRegionTargetHttpsProxies().Delete,
+		},
+	}
+}
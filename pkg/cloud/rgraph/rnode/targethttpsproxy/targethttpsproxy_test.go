@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethttpsproxy
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/rnodetest"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj      = "proj-1"
+	urlMap    = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um"
+	cert      = "https://www.googleapis.com/compute/v1/projects/proj-1/global/sslCertificates/cert"
+	otherCert = "https://www.googleapis.com/compute/v1/projects/proj-1/global/sslCertificates/cert2"
+)
+
+func TestTargetHttpsProxySchema(t *testing.T) {
+	key := meta.GlobalKey("key-1")
+	x := NewMutableTargetHttpsProxy(proj, key)
+	if err := x.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+}
+
+func node(t *testing.T, f func(x *compute.TargetHttpsProxy)) rnode.Node {
+	t.Helper()
+	return rnodetest.Node(t, NewMutableTargetHttpsProxy(proj, meta.GlobalKey("tp")), func(x *compute.TargetHttpsProxy) {
+		x.UrlMap = urlMap
+		x.QuicOverride = "NONE"
+		x.SslCertificates = []string{cert}
+		f(x)
+	}, NewBuilderWithResource)
+}
+
+func TestTargetHttpsProxyDiff(t *testing.T) {
+	got := node(t, func(*compute.TargetHttpsProxy) {})
+
+	for _, tc := range []struct {
+		name   string
+		f      func(x *compute.TargetHttpsProxy)
+		wantOp rnode.Operation
+	}{
+		{
+			name:   "no diff",
+			f:      func(*compute.TargetHttpsProxy) {},
+			wantOp: rnode.OpNothing,
+		},
+		{
+			name:   "certificates",
+			f:      func(x *compute.TargetHttpsProxy) { x.SslCertificates = []string{cert, otherCert} },
+			wantOp: rnode.OpRecreate,
+		},
+		{
+			name:   "quic override",
+			f:      func(x *compute.TargetHttpsProxy) { x.QuicOverride = "ENABLE" },
+			wantOp: rnode.OpRecreate,
+		},
+		{
+			name:   "url map",
+			f:      func(x *compute.TargetHttpsProxy) { x.UrlMap = urlMap + "2" },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := node(t, tc.f)
+			pd, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if pd.Operation != tc.wantOp {
+				t.Errorf("Diff() = %+v, want Operation %v", pd, tc.wantOp)
+			}
+		})
+	}
+}
+
+func TestTargetHttpsProxyActions(t *testing.T) {
+	got := node(t, func(*compute.TargetHttpsProxy) {})
+
+	for _, tc := range []struct {
+		op      rnode.Operation
+		want    []exec.ActionType
+		wantErr bool
+	}{
+		{op: rnode.OpNothing, want: []exec.ActionType{exec.ActionTypeMeta}},
+		{op: rnode.OpRecreate, want: []exec.ActionType{exec.ActionTypeDelete, exec.ActionTypeCreate}},
+		// There is no API to update a TargetHttpsProxy in place.
+		{op: rnode.OpUpdate, wantErr: true},
+	} {
+		t.Run(string(tc.op), func(t *testing.T) {
+			want := node(t, func(x *compute.TargetHttpsProxy) { x.QuicOverride = "ENABLE" })
+			want.Plan().Set(rnode.PlanDetails{Operation: tc.op, Why: "test plan"})
+			actions, err := want.Actions(got)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Actions() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			var gotTypes []exec.ActionType
+			for _, a := range actions {
+				gotTypes = append(gotTypes, a.Metadata().Type)
+			}
+			if len(gotTypes) != len(tc.want) {
+				t.Fatalf("Actions() = %v, want types %v", gotTypes, tc.want)
+			}
+			for i := range gotTypes {
+				if gotTypes[i] != tc.want[i] {
+					t.Errorf("Actions() = %v, want types %v", gotTypes, tc.want)
+				}
+			}
+		})
+	}
+}
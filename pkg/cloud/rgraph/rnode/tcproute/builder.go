@@ -31,6 +31,10 @@ const (
 	resourceName = "TcpRoute"
 )
 
+func init() {
+	rnode.Register(rnode.NewType("tcpRoutes", NewBuilder, NewBuilderWithResource))
+}
+
 // NewBuilder creates builder for tcp route.
 func NewBuilder(id *cloud.ResourceID) rnode.Builder {
 	b := &builder{}
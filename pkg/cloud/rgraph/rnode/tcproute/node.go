@@ -19,6 +19,7 @@ package tcproute
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
@@ -32,6 +33,7 @@ type tcpRouteNode struct {
 }
 
 var _ rnode.Node = (*tcpRouteNode)(nil)
+var _ rnode.RefRewriter = (*tcpRouteNode)(nil)
 
 func (n *tcpRouteNode) Resource() rnode.UntypedResource { return n.resource }
 
@@ -77,6 +79,9 @@ func (n *tcpRouteNode) runOp(got rnode.Node, op rnode.Operation) ([]exec.Action,
 	case rnode.OpUpdate:
 		// TCP route does not support fingerprint
 		return rnode.UpdateActions[networkservices.TcpRoute, api.PlaceholderType, beta.TcpRoute](&tcpRouteOps{}, got, n, n.resource, "")
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) { return n.runOp(got, rnode.OpUpdate) })
 	}
 
 	return nil, fmt.Errorf("TcpRouteNode: invalid plan op %s", op)
@@ -94,5 +99,14 @@ func (n *tcpRouteNode) Actions(got rnode.Node) ([]exec.Action, error) {
 func (n *tcpRouteNode) Builder() rnode.Builder {
 	b := &builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
+
+func (n *tcpRouteNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
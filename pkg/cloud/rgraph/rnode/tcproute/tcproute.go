@@ -25,6 +25,10 @@ import (
 	beta "google.golang.org/api/networkservices/v1beta1"
 )
 
+func init() {
+	api.RegisterSchema("networkservices/tcpRoutes", NewMutableTcpRoute("project", meta.GlobalKey("key")))
+}
+
 func ID(project string, key *meta.Key) *cloud.ResourceID {
 	return &cloud.ResourceID{
 		Resource:  "tcpRoutes",
@@ -19,6 +19,7 @@ package urlmap
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 	alpha "google.golang.org/api/compute/v0.alpha"
@@ -32,6 +33,7 @@ type urlMapNode struct {
 }
 
 var _ rnode.Node = (*urlMapNode)(nil)
+var _ rnode.RefRewriter = (*urlMapNode)(nil)
 
 func (n *urlMapNode) Resource() rnode.UntypedResource { return n.resource }
 
@@ -79,6 +81,11 @@ func (n *urlMapNode) Actions(got rnode.Node) ([]exec.Action, error) {
 
 	case rnode.OpUpdate:
 		// TODO
+
+	case rnode.OpAdopt:
+		return rnode.AdoptActions(n, func() ([]exec.Action, error) {
+			return nil, fmt.Errorf("%s with changes is not supported for UrlMap", op)
+		})
 	}
 
 	return nil, fmt.Errorf("UrlMapNode: invalid plan op %s", op)
@@ -87,5 +94,14 @@ func (n *urlMapNode) Actions(got rnode.Node) ([]exec.Action, error) {
 func (n *urlMapNode) Builder() rnode.Builder {
 	b := &builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), n.resource)
+	b.SetDeletionProtected(n.DeletionProtected())
 	return b
 }
+
+func (n *urlMapNode) RewriteRefs(id *cloud.ResourceID, renames map[cloud.ResourceMapKey]*cloud.ResourceID) (rnode.Builder, error) {
+	r, err := rnode.RewriteRefs(n.resource, id, renames)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilderWithResource(r), nil
+}
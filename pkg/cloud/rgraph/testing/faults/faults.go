@@ -0,0 +1,251 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faults injects scripted failures into a cloud.MockGCE. This is used
+// to deterministically test the handling of errors (e.g. retries and rollback)
+// when planning and executing rgraphs.
+//
+//	mock, inj := faults.NewMockGCE("proj",
+//		// Fail the 2nd HealthCheck insert.
+//		faults.Fault{Resource: "healthChecks", Op: "Insert", Nth: 2, Err: faults.HTTPError(500)},
+//		// Return 409 (conflict) on all deletes.
+//		faults.Fault{Op: "Delete", Err: faults.HTTPError(409)},
+//	)
+//
+// This package should only be used for testing.
+package faults
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+)
+
+// Fault is a scripted failure. Empty fields match all calls.
+type Fault struct {
+	// Resource to match, e.g. "healthChecks" (see cloud.ResourceID.Resource).
+	Resource string
+	// Op is the method to match, e.g. "Get", "Insert", "Delete", "Update",
+	// "SetTarget".
+	Op string
+	// Name of the resource to match.
+	Name string
+	// Nth matching call will fail, starting at 1. If Nth is 0, all matching
+	// calls fail.
+	Nth int
+	// Count is the number of consecutive matching calls that fail, starting
+	// with the Nth call. Count 0 is the same as 1. This is ignored if Nth is 0.
+	Count int
+	// Err to return from the call.
+	Err error
+}
+
+func (f *Fault) match(c *Call) bool {
+	return (f.Resource == "" || f.Resource == c.Resource) &&
+		(f.Op == "" || f.Op == c.Op) &&
+		(f.Name == "" || f.Name == c.Key.Name)
+}
+
+// HTTPError returns a googleapi.Error with the HTTP status code, e.g.
+// http.StatusConflict.
+func HTTPError(code int) error {
+	return &googleapi.Error{Code: code, Message: http.StatusText(code)}
+}
+
+// Call to the mock that was seen by the Injector.
+type Call struct {
+	// Service is the name of the mock, e.g. "AlphaHealthChecks".
+	Service string
+	// Resource is the resource type, e.g. "healthChecks".
+	Resource string
+	// Op is the method called.
+	Op  string
+	Key meta.Key
+	// Err is the injected error, nil if the call was not failed.
+	Err error
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s.%s(%s) = %v", c.Service, c.Op, c.Key, c.Err)
+}
+
+// Injector fails the calls to the mock matching the Faults.
+type Injector struct {
+	lock   sync.Mutex
+	faults []*faultState
+	calls  []Call
+}
+
+type faultState struct {
+	Fault
+	// seen is the number of matching calls.
+	seen int
+}
+
+// NewMockGCE returns a new MockGCE for project with the Injector installed.
+func NewMockGCE(project string, faults ...Fault) (*cloud.MockGCE, *Injector) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	return mock, Install(mock, faults...)
+}
+
+// Install the Injector into the mock by wrapping the xxxHooks of the
+// services that have a *meta.Key as a parameter (e.g. Get, Insert, Delete,
+// Update but not List). Existing hooks are called if the call is not failed,
+// so Install should be called after the hooks have been set up.
+func Install(mock *cloud.MockGCE, faults ...Fault) *Injector {
+	inj := &Injector{}
+	for _, f := range faults {
+		inj.Add(f)
+	}
+
+	mv := reflect.ValueOf(mock).Elem()
+	for _, si := range meta.AllServices {
+		sv := mv.FieldByName(si.MockField())
+		if !sv.IsValid() || sv.Kind() != reflect.Pointer || sv.IsNil() {
+			continue
+		}
+		sv = sv.Elem()
+		for i := 0; i < sv.NumField(); i++ {
+			name := sv.Type().Field(i).Name
+			if !strings.HasSuffix(name, "Hook") {
+				continue
+			}
+			inj.wrapHook(si, strings.TrimSuffix(name, "Hook"), sv.Field(i))
+		}
+	}
+	return inj
+}
+
+var (
+	keyType   = reflect.TypeOf(&meta.Key{})
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	boolType  = reflect.TypeOf(true)
+)
+
+// wrapHook replaces hook with a func that checks for faults before calling the
+// original hook.
+func (inj *Injector) wrapHook(si *meta.ServiceInfo, op string, hook reflect.Value) {
+	ht := hook.Type()
+	if ht.Kind() != reflect.Func || ht.NumIn() < 2 || ht.In(1) != keyType {
+		return
+	}
+	// Hooks either return error or (bool, ..., error), where the bool
+	// signals that the call has been intercepted.
+	numOut := ht.NumOut()
+	if numOut == 0 || ht.Out(numOut-1) != errorType {
+		return
+	}
+	intercept := ht.Out(0) == boolType
+
+	orig := reflect.ValueOf(hook.Interface())
+	wrapped := reflect.MakeFunc(ht, func(args []reflect.Value) []reflect.Value {
+		key := args[1].Interface().(*meta.Key)
+		if err := inj.check(si, op, key); err != nil {
+			ret := make([]reflect.Value, numOut)
+			for i := 0; i < numOut; i++ {
+				ret[i] = reflect.Zero(ht.Out(i))
+			}
+			if intercept {
+				ret[0] = reflect.ValueOf(true)
+			}
+			ret[numOut-1] = reflect.ValueOf(&err).Elem()
+			return ret
+		}
+		if !orig.IsNil() {
+			if ht.IsVariadic() {
+				return orig.CallSlice(args)
+			}
+			return orig.Call(args)
+		}
+		// No original hook, continue with the default behavior of
+		// the mock.
+		ret := make([]reflect.Value, numOut)
+		for i := 0; i < numOut; i++ {
+			ret[i] = reflect.Zero(ht.Out(i))
+		}
+		return ret
+	})
+	hook.Set(wrapped)
+}
+
+// check the call against the faults, returning the error to inject.
+func (inj *Injector) check(si *meta.ServiceInfo, op string, key *meta.Key) error {
+	inj.lock.Lock()
+	defer inj.lock.Unlock()
+
+	c := Call{
+		Service:  si.WrapType(),
+		Resource: si.Resource,
+		Op:       op,
+		Key:      *key,
+	}
+	for _, f := range inj.faults {
+		if !f.match(&c) {
+			continue
+		}
+		f.seen++
+		count := f.Count
+		if count == 0 {
+			count = 1
+		}
+		if f.Nth == 0 || (f.seen >= f.Nth && f.seen < f.Nth+count) {
+			c.Err = f.Err
+			break
+		}
+	}
+	inj.calls = append(inj.calls, c)
+	return c.Err
+}
+
+// Add a Fault to the Injector. Faults are checked in the order they are
+// added; the first Fault that triggers is used.
+func (inj *Injector) Add(f Fault) {
+	inj.lock.Lock()
+	defer inj.lock.Unlock()
+	inj.faults = append(inj.faults, &faultState{Fault: f})
+}
+
+// Reset removes all of the Faults and the recorded Calls.
+func (inj *Injector) Reset() {
+	inj.lock.Lock()
+	defer inj.lock.Unlock()
+	inj.faults = nil
+	inj.calls = nil
+}
+
+// Calls returns the calls seen by the Injector in order.
+func (inj *Injector) Calls() []Call {
+	inj.lock.Lock()
+	defer inj.lock.Unlock()
+	return append([]Call{}, inj.calls...)
+}
+
+// Failed returns the calls that were failed by the Injector.
+func (inj *Injector) Failed() []Call {
+	var ret []Call
+	for _, c := range inj.Calls() {
+		if c.Err != nil {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/cerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestInjector(t *testing.T) {
+	errInjected := errors.New("injected")
+
+	type call struct {
+		op   string
+		name string
+	}
+	for _, tc := range []struct {
+		name   string
+		faults []Fault
+		calls  []call
+		// wantFail is the index of the calls that fail.
+		wantFail []int
+	}{
+		{
+			name:     "no faults",
+			calls:    []call{{"Insert", "a"}, {"Get", "a"}, {"Delete", "a"}},
+			wantFail: nil,
+		},
+		{
+			name:     "fail 2nd insert",
+			faults:   []Fault{{Resource: "healthChecks", Op: "Insert", Nth: 2, Err: errInjected}},
+			calls:    []call{{"Insert", "a"}, {"Get", "a"}, {"Insert", "b"}, {"Insert", "c"}},
+			wantFail: []int{2},
+		},
+		{
+			name:     "fail consecutive calls",
+			faults:   []Fault{{Op: "Get", Nth: 2, Count: 2, Err: errInjected}},
+			calls:    []call{{"Insert", "a"}, {"Get", "a"}, {"Get", "a"}, {"Get", "a"}, {"Get", "a"}},
+			wantFail: []int{2, 3},
+		},
+		{
+			name:     "fail all calls by name",
+			faults:   []Fault{{Name: "b", Err: errInjected}},
+			calls:    []call{{"Insert", "a"}, {"Insert", "b"}, {"Get", "b"}, {"Get", "a"}},
+			wantFail: []int{1, 2},
+		},
+		{
+			name:     "other resource",
+			faults:   []Fault{{Resource: "backendServices", Err: errInjected}},
+			calls:    []call{{"Insert", "a"}, {"Get", "a"}},
+			wantFail: nil,
+		},
+		{
+			name: "first fault wins",
+			faults: []Fault{
+				{Op: "Delete", Nth: 1, Err: errInjected},
+				{Op: "Delete", Err: HTTPError(http.StatusConflict)},
+			},
+			calls:    []call{{"Insert", "a"}, {"Delete", "a"}, {"Delete", "a"}},
+			wantFail: []int{1, 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock, inj := NewMockGCE("proj", tc.faults...)
+
+			var gotFail []int
+			for i, c := range tc.calls {
+				key := meta.GlobalKey(c.name)
+				var err error
+				switch c.op {
+				case "Insert":
+					err = mock.HealthChecks().Insert(ctx, key, &compute.HealthCheck{})
+				case "Get":
+					_, err = mock.HealthChecks().Get(ctx, key)
+				case "Delete":
+					err = mock.HealthChecks().Delete(ctx, key)
+				}
+				if err != nil {
+					gotFail = append(gotFail, i)
+				}
+			}
+			if diff := cmp.Diff(gotFail, tc.wantFail); diff != "" {
+				t.Errorf("failed calls: -got,+want: %s", diff)
+			}
+			if got, want := len(inj.Calls()), len(tc.calls); got != want {
+				t.Errorf("len(inj.Calls()) = %d, want %d", got, want)
+			}
+			if got, want := len(inj.Failed()), len(tc.wantFail); got != want {
+				t.Errorf("len(inj.Failed()) = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestInjectorExistingHook(t *testing.T) {
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	var hookCalls int
+	mock.MockHealthChecks.GetHook = func(context.Context, *meta.Key, *cloud.MockHealthChecks, ...cloud.Option) (bool, *compute.HealthCheck, error) {
+		hookCalls++
+		return true, &compute.HealthCheck{Name: "from-hook"}, nil
+	}
+	Install(mock, Fault{Op: "Get", Nth: 1, Err: HTTPError(http.StatusConflict)})
+
+	_, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("a"))
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) || gerr.Code != http.StatusConflict {
+		t.Errorf("Get() = %v, want HTTP %d", err, http.StatusConflict)
+	}
+	hc, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("a"))
+	if err != nil || hc.Name != "from-hook" {
+		t.Errorf("Get() = %+v, %v; want from-hook, nil", hc, err)
+	}
+	if hookCalls != 1 {
+		t.Errorf("hookCalls = %d, want 1", hookCalls)
+	}
+}
+
+func TestInjectorRollback(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+
+	mock, inj := NewMockGCE(project, Fault{Resource: "healthChecks", Op: "Insert", Nth: 2, Err: HTTPError(http.StatusInternalServerError)})
+
+	gr := rgraph.NewBuilder()
+	for _, name := range []string{"hc-1", "hc-2"} {
+		m := b.N(name).HealthCheck().Resource()
+		r, _ := m.Freeze()
+		nb := healthcheck.NewBuilderWithResource(r)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		gr.Add(nb)
+	}
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	res, err := plan.Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("plan.Do() = %v, want nil", err)
+	}
+	ex, err := exec.NewTransactionalExecutor(mock, res.Actions)
+	if err != nil {
+		t.Fatalf("NewTransactionalExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(ctx)
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if len(result.Compensated) != 1 {
+		t.Errorf("len(Compensated) = %d, want 1", len(result.Compensated))
+	}
+	// The HealthCheck that was created must be rolled back.
+	for _, name := range []string{"hc-1", "hc-2"} {
+		if _, err := mock.HealthChecks().Get(ctx, meta.GlobalKey(name)); !cerrors.IsGoogleAPINotFound(err) {
+			t.Errorf("HealthChecks().Get(%q) = %v, want NotFound", name, err)
+		}
+	}
+	if got := len(inj.Failed()); got != 1 {
+		t.Errorf("inj.Failed() = %v, want 1 failed call", inj.Failed())
+	}
+}
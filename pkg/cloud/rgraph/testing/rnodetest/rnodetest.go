@@ -0,0 +1,59 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rnodetest has helpers for testing the rnode resource types.
+//
+// This package should only be used for testing.
+package rnodetest
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Node builds a Node from the resource r after it is modified with f (using
+// Access). newBuilder is the NewBuilderWithResource() func of the type. The
+// Node is managed and exists. The test fails if the Node cannot be built.
+//
+//	n := rnodetest.Node(t, firewall.NewMutableFirewall(proj, key), func(x *compute.Firewall) {
+//		x.Network = "default"
+//	}, firewall.NewBuilderWithResource)
+func Node[GA any, Alpha any, Beta any](
+	t *testing.T,
+	r api.MutableResource[GA, Alpha, Beta],
+	f func(x *GA),
+	newBuilder func(api.Resource[GA, Alpha, Beta]) rnode.Builder,
+) rnode.Node {
+	t.Helper()
+
+	if err := r.Access(f); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	res, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	nb := newBuilder(res)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	n, err := nb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	return n
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracerec is an in-memory OpenTelemetry TracerProvider that records
+// the spans for inspection in tests.
+//
+// This package should only be used for testing.
+package tracerec
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// Provider is a TracerProvider that records all spans.
+type Provider struct {
+	embedded.TracerProvider
+
+	lock   sync.Mutex
+	spans  []*Span
+	nextID uint64
+}
+
+var _ trace.TracerProvider = (*Provider)(nil)
+
+// New returns a new Provider.
+func New() *Provider { return &Provider{} }
+
+// Tracer implements trace.TracerProvider.
+func (p *Provider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &tracer{p: p}
+}
+
+// Spans returns the spans that were started, in order.
+func (p *Provider) Spans() []*Span {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]*Span{}, p.spans...)
+}
+
+// Find the spans with the given name.
+func (p *Provider) Find(name string) []*Span {
+	var ret []*Span
+	for _, s := range p.Spans() {
+		if s.Name() == name {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func (p *Provider) newID() uint64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.nextID++
+	return p.nextID
+}
+
+type tracer struct {
+	embedded.Tracer
+	p *Provider
+}
+
+// Start implements trace.Tracer.
+func (t *tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+	if cfg.NewRoot() {
+		parent = trace.SpanContext{}
+	}
+
+	var traceID trace.TraceID
+	if parent.IsValid() {
+		traceID = parent.TraceID()
+	} else {
+		binary.BigEndian.PutUint64(traceID[8:], t.p.newID())
+	}
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], t.p.newID())
+
+	s := &Span{
+		p:      t.p,
+		name:   name,
+		parent: parent,
+		sc: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}),
+		attrs: cfg.Attributes(),
+	}
+	t.p.lock.Lock()
+	t.p.spans = append(t.p.spans, s)
+	t.p.lock.Unlock()
+
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// Span is a recorded span.
+type Span struct {
+	embedded.Span
+
+	p      *Provider
+	sc     trace.SpanContext
+	parent trace.SpanContext
+
+	lock       sync.Mutex
+	name       string
+	attrs      []attribute.KeyValue
+	events     []string
+	errs       []error
+	statusCode codes.Code
+	ended      bool
+}
+
+var _ trace.Span = (*Span)(nil)
+
+// Name of the span.
+func (s *Span) Name() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.name
+}
+
+// Parent is the SpanContext of the parent span. This is invalid for a root
+// span.
+func (s *Span) Parent() trace.SpanContext { return s.parent }
+
+// Attr returns the value of the attribute with key.
+func (s *Span) Attr(key attribute.Key) (attribute.Value, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// Return the last value set for the key.
+	for i := len(s.attrs) - 1; i >= 0; i-- {
+		if s.attrs[i].Key == key {
+			return s.attrs[i].Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// Events are the names of the events added to the span.
+func (s *Span) Events() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string{}, s.events...)
+}
+
+// Errors recorded in the span.
+func (s *Span) Errors() []error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]error{}, s.errs...)
+}
+
+// Status code of the span.
+func (s *Span) Status() codes.Code {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.statusCode
+}
+
+// Ended is true if End() was called.
+func (s *Span) Ended() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.ended
+}
+
+// End implements trace.Span.
+func (s *Span) End(...trace.SpanEndOption) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.ended = true
+}
+
+// AddEvent implements trace.Span.
+func (s *Span) AddEvent(name string, _ ...trace.EventOption) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = append(s.events, name)
+}
+
+// IsRecording implements trace.Span.
+func (s *Span) IsRecording() bool { return !s.Ended() }
+
+// RecordError implements trace.Span.
+func (s *Span) RecordError(err error, _ ...trace.EventOption) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+// SpanContext implements trace.Span.
+func (s *Span) SpanContext() trace.SpanContext { return s.sc }
+
+// SetStatus implements trace.Span.
+func (s *Span) SetStatus(code codes.Code, _ string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.statusCode = code
+}
+
+// SetName implements trace.Span.
+func (s *Span) SetName(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.name = name
+}
+
+// SetAttributes implements trace.Span.
+func (s *Span) SetAttributes(kv ...attribute.KeyValue) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.attrs = append(s.attrs, kv...)
+}
+
+// TracerProvider implements trace.Span.
+func (s *Span) TracerProvider() trace.TracerProvider { return s.p }
@@ -0,0 +1,245 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift detects changes to Cloud resources that were made outside of
+// the rgraph workflow, i.e. the resources no longer match the intended
+// ("want") graph. Drift is only reported; no changes are made to the
+// resources.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/trclosure"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"k8s.io/klog/v2"
+)
+
+// Kind of drift.
+type Kind string
+
+var (
+	// Changed means the resource exists but differs from want.
+	Changed Kind = "Changed"
+	// Missing means the resource exists in want but not in the Cloud.
+	Missing Kind = "Missing"
+	// Unexpected means the resource should not exist but exists in the
+	// Cloud.
+	Unexpected Kind = "Unexpected"
+)
+
+// Drift of a resource from the intended state.
+type Drift struct {
+	ID   *cloud.ResourceID
+	Kind Kind
+	// Changes are the fields that differ for Kind == Changed. A is the
+	// value in the Cloud, B is the wanted value.
+	Changes []api.DiffItem
+	// Audit is the information about who made the change. This is nil if
+	// not available (see AuditLookup).
+	Audit *Audit
+	// DetectedAt is the time of the snapshot that detected the drift.
+	DetectedAt time.Time
+}
+
+func (d *Drift) String() string {
+	if d.Kind != Changed {
+		return fmt.Sprintf("%v: %s", d.ID, d.Kind)
+	}
+	var paths []string
+	for _, item := range d.Changes {
+		paths = append(paths, item.Path.String())
+	}
+	return fmt.Sprintf("%v: %s %v", d.ID, d.Kind, paths)
+}
+
+// Audit metadata for a change to a resource.
+type Audit struct {
+	// Actor that made the change (e.g. the principal email).
+	Actor string
+	// Time of the change.
+	Time time.Time
+}
+
+// Option for drift detection.
+type Option func(*config)
+
+// AuditLookup sets a function used to get the Audit metadata for the resources
+// that have drifted (e.g. from the Cloud Audit Logs). f may return nil if the
+// information is not available.
+func AuditLookup(f func(ctx context.Context, id *cloud.ResourceID) (*Audit, error)) Option {
+	return func(c *config) { c.audit = f }
+}
+
+// FetchOptions are passed to the fetch of the resources from the Cloud.
+func FetchOptions(opts ...trclosure.Option) Option {
+	return func(c *config) { c.fetchOpts = append(c.fetchOpts, opts...) }
+}
+
+// Interval between snapshots for the Watcher. The default is 5 minutes. The
+// default is also used if d is zero or negative.
+func Interval(d time.Duration) Option {
+	return func(c *config) { c.interval = d }
+}
+
+// OnDrift is called by the Watcher with the drift found by each snapshot. f
+// is not called if there is no drift.
+func OnDrift(f func([]Drift)) Option {
+	return func(c *config) { c.onDrift = f }
+}
+
+// OnError is called by the Watcher if a snapshot fails.
+func OnError(f func(error)) Option {
+	return func(c *config) { c.onError = f }
+}
+
+type config struct {
+	audit     func(ctx context.Context, id *cloud.ResourceID) (*Audit, error)
+	fetchOpts []trclosure.Option
+	interval  time.Duration
+	onDrift   func([]Drift)
+	onError   func(error)
+}
+
+const defaultInterval = 5 * time.Minute
+
+func makeConfig(opts ...Option) config {
+	c := config{
+		interval: defaultInterval,
+		onDrift:  func([]Drift) {},
+		onError:  func(err error) { klog.Errorf("drift: %v", err) },
+	}
+	for _, o := range opts {
+		o(&c)
+	}
+	return c
+}
+
+// Detect takes a snapshot of the resources in want and returns the managed
+// resources that have drifted. The result is sorted by ID.
+func Detect(ctx context.Context, c cloud.Cloud, want *rgraph.Graph, opts ...Option) ([]Drift, error) {
+	config := makeConfig(opts...)
+	return detect(ctx, c, want, &config)
+}
+
+func detect(ctx context.Context, c cloud.Cloud, want *rgraph.Graph, config *config) ([]Drift, error) {
+	now := time.Now()
+
+	gotBuilder := want.NewBuilderWithEmptyNodes()
+	fetchOpts := append([]trclosure.Option{}, config.fetchOpts...)
+	fetchOpts = append(fetchOpts, trclosure.OnGetFunc(func(n rnode.Builder) error {
+		n.SetOwnership(rnode.OwnershipManaged)
+		return nil
+	}))
+	if err := trclosure.Do(ctx, c, gotBuilder, fetchOpts...); err != nil {
+		return nil, fmt.Errorf("drift: %w", err)
+	}
+	got, err := gotBuilder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("drift: %w", err)
+	}
+
+	var ret []Drift
+	for _, wantNode := range want.All() {
+		if wantNode.Ownership() != rnode.OwnershipManaged {
+			continue
+		}
+		gotNode := got.Get(wantNode.ID())
+		if gotNode == nil {
+			return nil, fmt.Errorf("drift: node %v not in the snapshot", wantNode.ID())
+		}
+		d := Drift{ID: wantNode.ID(), DetectedAt: now}
+		switch {
+		case gotNode.State() == rnode.NodeExists && wantNode.State() == rnode.NodeExists:
+			details, err := wantNode.Diff(gotNode)
+			if err != nil {
+				return nil, fmt.Errorf("drift: %w", err)
+			}
+			if details.Diff == nil || !details.Diff.HasDiff() {
+				continue
+			}
+			d.Kind = Changed
+			d.Changes = details.Diff.Items
+		case gotNode.State() != rnode.NodeExists && wantNode.State() == rnode.NodeExists:
+			d.Kind = Missing
+		case gotNode.State() == rnode.NodeExists && wantNode.State() == rnode.NodeDoesNotExist:
+			d.Kind = Unexpected
+		default:
+			continue
+		}
+		if config.audit != nil {
+			if d.Audit, err = config.audit(ctx, d.ID); err != nil {
+				return nil, fmt.Errorf("drift: audit lookup for %v: %w", d.ID, err)
+			}
+		}
+		ret = append(ret, d)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID.String() < ret[j].ID.String() })
+
+	return ret, nil
+}
+
+// NewWatcher returns a Watcher for the resources in want. want is not
+// modified.
+func NewWatcher(c cloud.Cloud, want *rgraph.Graph, opts ...Option) *Watcher {
+	config := makeConfig(opts...)
+	if config.interval <= 0 {
+		config.interval = defaultInterval
+	}
+	return &Watcher{
+		config: config,
+		cloud:  c,
+		want:   want,
+	}
+}
+
+// Watcher periodically checks the resources for drift.
+type Watcher struct {
+	config config
+	cloud  cloud.Cloud
+	want   *rgraph.Graph
+}
+
+// Run the Watcher until ctx is done. A snapshot is taken immediately and then
+// after every Interval. Returns ctx.Err().
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.interval)
+	defer ticker.Stop()
+
+	for {
+		drift, err := detect(ctx, w.cloud, w.want, &w.config)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			w.config.onError(err)
+		case len(drift) > 0:
+			w.config.onDrift(drift)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
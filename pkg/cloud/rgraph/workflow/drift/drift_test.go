@@ -0,0 +1,186 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const project = "proj"
+
+func wantGraph(t *testing.T, state rnode.NodeState, interval int64) *rgraph.Graph {
+	t.Helper()
+	b := all.ResourceBuilder{Project: project}
+	m := b.N("hc").HealthCheck().Resource()
+	m.Access(func(x *compute.HealthCheck) { x.CheckIntervalSec = interval })
+	r, _ := m.Freeze()
+	nb := healthcheck.NewBuilderWithResource(r)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(state)
+	gr := rgraph.NewBuilder()
+	gr.Add(nb)
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	return want
+}
+
+func TestDetect(t *testing.T) {
+	b := all.ResourceBuilder{Project: project}
+	hcID := b.N("hc").HealthCheck().ID()
+
+	for _, tc := range []struct {
+		name      string
+		existing  *compute.HealthCheck
+		state     rnode.NodeState
+		wantKind  Kind
+		wantPaths []string
+	}{
+		{
+			name:     "no drift",
+			existing: &compute.HealthCheck{CheckIntervalSec: 5},
+			state:    rnode.NodeExists,
+		},
+		{
+			name:      "changed",
+			existing:  &compute.HealthCheck{CheckIntervalSec: 10},
+			state:     rnode.NodeExists,
+			wantKind:  Changed,
+			wantPaths: []string{"*.CheckIntervalSec"},
+		},
+		{
+			name:     "missing",
+			state:    rnode.NodeExists,
+			wantKind: Missing,
+		},
+		{
+			name:     "unexpected",
+			existing: &compute.HealthCheck{CheckIntervalSec: 5},
+			state:    rnode.NodeDoesNotExist,
+			wantKind: Unexpected,
+		},
+		{
+			name:  "does not exist",
+			state: rnode.NodeDoesNotExist,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			if tc.existing != nil {
+				mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), tc.existing)
+			}
+			want := wantGraph(t, tc.state, 5)
+
+			audit := &Audit{Actor: "user@example.com"}
+			drift, err := Detect(ctx, mock, want, AuditLookup(func(context.Context, *cloud.ResourceID) (*Audit, error) {
+				return audit, nil
+			}))
+			if err != nil {
+				t.Fatalf("Detect() = %v, want nil", err)
+			}
+			if tc.wantKind == "" {
+				if len(drift) != 0 {
+					t.Fatalf("Detect() = %v, want no drift", drift)
+				}
+				return
+			}
+			if len(drift) != 1 {
+				t.Fatalf("Detect() = %v, want 1 drift", drift)
+			}
+			d := drift[0]
+			if !d.ID.Equal(hcID) || d.Kind != tc.wantKind || d.Audit != audit {
+				t.Errorf("Detect() = %+v, want ID=%v, Kind=%s, Audit=%v", d, hcID, tc.wantKind, audit)
+			}
+			var paths []string
+			for _, item := range d.Changes {
+				paths = append(paths, item.Path.String())
+			}
+			if diff := cmp.Diff(paths, tc.wantPaths); diff != "" {
+				t.Errorf("Changes: -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &compute.HealthCheck{CheckIntervalSec: 5})
+	want := wantGraph(t, rnode.NodeExists, 5)
+
+	var snapshots atomic.Int32
+	var got []Drift
+	w := NewWatcher(mock, want,
+		Interval(time.Millisecond),
+		OnDrift(func(d []Drift) {
+			got = d
+			cancel()
+		}),
+		OnError(func(err error) { t.Errorf("snapshot error: %v", err) }),
+	)
+	// The change is made out of band after the first snapshot.
+	mock.MockHealthChecks.GetHook = func(context.Context, *meta.Key, *cloud.MockHealthChecks, ...cloud.Option) (bool, *compute.HealthCheck, error) {
+		if snapshots.Add(1) > 1 {
+			return true, &compute.HealthCheck{Name: "hc", CheckIntervalSec: 7}, nil
+		}
+		return false, nil, nil
+	}
+
+	if err := w.Run(ctx); err != context.Canceled {
+		t.Fatalf("Run() = %v, want %v", err, context.Canceled)
+	}
+	if len(got) != 1 || got[0].Kind != Changed {
+		t.Errorf("OnDrift() = %v, want 1 Changed drift", got)
+	}
+	if n := snapshots.Load(); n < 2 {
+		t.Errorf("snapshots = %d, want >= 2", n)
+	}
+}
+
+func TestWatcherNonPositiveInterval(t *testing.T) {
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	want := wantGraph(t, rnode.NodeExists, 5)
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		ctx, cancel := context.WithCancel(context.Background())
+		// Stop before waiting for the next snapshot.
+		w := NewWatcher(mock, want, Interval(d), OnError(func(error) { cancel() }), OnDrift(func([]Drift) { cancel() }))
+		if w.config.interval != defaultInterval {
+			t.Errorf("NewWatcher(Interval(%v)): interval = %v, want %v", d, w.config.interval, defaultInterval)
+		}
+		if err := w.Run(ctx); err != context.Canceled {
+			t.Errorf("Run() = %v, want %v", err, context.Canceled)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,407 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lb composes the resource graph for common load balancer topologies
+// from a small Spec, e.g. a global external HTTP load balancer:
+//
+//	Address <- ForwardingRule -> TargetHttpProxy -> UrlMap -> BackendService -> HealthCheck
+//	                                                                |
+//	                                                                +-> NEG backends (external)
+//
+// and a Firewall that allows the health checkers to reach the backends. The
+// returned graph is the "want" graph to be used with plan.Do().
+package lb
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/address"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/firewall"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/sslcertificate"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpsproxy"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// Protocol of the load balancer.
+type Protocol string
+
+var (
+	HTTP  Protocol = "HTTP"
+	HTTPS Protocol = "HTTPS"
+)
+
+// healthCheckRanges are the source ranges of the Google Cloud health checkers.
+var healthCheckRanges = []string{"130.211.0.0/22", "35.191.0.0/16"}
+
+// Spec of the load balancer.
+type Spec struct {
+	// Project of the resources.
+	Project string
+	// Name of the load balancer. The resources are named
+	// "<Name>-<suffix>" (see IDs).
+	Name string
+	// Protocol of the load balancer. Default is HTTP.
+	Protocol Protocol
+	// Region of the load balancer. The load balancer is global if this is
+	// empty.
+	Region string
+	// LoadBalancingScheme of the load balancer. The default is
+	// "EXTERNAL_MANAGED".
+	LoadBalancingScheme string
+	// Port of the forwarding rule. The default is 80 for HTTP and 443 for
+	// HTTPS.
+	Port int
+	// Network for the Firewall and for regional load balancers (URL). The
+	// Firewall is only created if this is set.
+	Network string
+	// TargetTags of the backend instances. The Firewall only applies to
+	// instances with these tags. This must be set if Network is set.
+	TargetTags []string
+	// Backends of the load balancer.
+	Backends []Backend
+	// HealthCheck for the backends.
+	HealthCheck HealthCheck
+	// TLS configuration. This must be set if Protocol is HTTPS.
+	TLS *TLS
+}
+
+// Backend is a NetworkEndpointGroup serving the traffic. The
+// NetworkEndpointGroups are not managed by the load balancer.
+type Backend struct {
+	// Group is the NetworkEndpointGroup.
+	Group *cloud.ResourceID
+	// MaxRatePerEndpoint for the backend. The default is 100.
+	MaxRatePerEndpoint float64
+}
+
+// HealthCheck for the backends.
+type HealthCheck struct {
+	// Port to check. The default is to use the serving port of the backends.
+	// This must be set if Network is set as the Firewall only allows
+	// traffic to this port.
+	Port int64
+	// RequestPath to check. The default is "/".
+	RequestPath string
+}
+
+// TLS configuration.
+type TLS struct {
+	// Certificates to use for the load balancer. The SslCertificates are not
+	// managed by the load balancer.
+	Certificates []*cloud.ResourceID
+}
+
+// IDs of the resources that are created for the load balancer.
+type IDs struct {
+	Address        *cloud.ResourceID
+	ForwardingRule *cloud.ResourceID
+	// TargetProxy is either a TargetHttpProxy or a TargetHttpsProxy
+	// depending on the Protocol.
+	TargetProxy    *cloud.ResourceID
+	UrlMap         *cloud.ResourceID
+	BackendService *cloud.ResourceID
+	HealthCheck    *cloud.ResourceID
+	// Firewall is nil if Spec.Network is not set.
+	Firewall *cloud.ResourceID
+}
+
+// IDs of the resources for the Spec.
+func (s *Spec) IDs() *IDs {
+	key := func(suffix string) *meta.Key {
+		name := s.Name + "-" + suffix
+		if s.Region != "" {
+			return meta.RegionalKey(name, s.Region)
+		}
+		return meta.GlobalKey(name)
+	}
+	ret := &IDs{
+		Address:        address.ID(s.Project, key("ip")),
+		ForwardingRule: forwardingrule.ID(s.Project, key("fr")),
+		TargetProxy:    targethttpproxy.ID(s.Project, key("tp")),
+		UrlMap:         urlmap.ID(s.Project, key("um")),
+		BackendService: backendservice.ID(s.Project, key("bs")),
+		HealthCheck:    healthcheck.ID(s.Project, key("hc")),
+	}
+	if s.protocol() == HTTPS {
+		ret.TargetProxy = targethttpsproxy.ID(s.Project, key("tp"))
+	}
+	if s.Network != "" {
+		// Firewalls are always global.
+		ret.Firewall = firewall.ID(s.Project, meta.GlobalKey(s.Name+"-fw"))
+	}
+	return ret
+}
+
+func (s *Spec) protocol() Protocol {
+	if s.Protocol == "" {
+		return HTTP
+	}
+	return s.Protocol
+}
+
+func (s *Spec) scheme() string {
+	if s.LoadBalancingScheme == "" {
+		return "EXTERNAL_MANAGED"
+	}
+	return s.LoadBalancingScheme
+}
+
+func (s *Spec) port() int {
+	switch {
+	case s.Port != 0:
+		return s.Port
+	case s.protocol() == HTTPS:
+		return 443
+	default:
+		return 80
+	}
+}
+
+func (s *Spec) validate() error {
+	if s.Project == "" || s.Name == "" {
+		return fmt.Errorf("lb: Project and Name must be set")
+	}
+	switch s.protocol() {
+	case HTTP:
+		if s.TLS != nil {
+			return fmt.Errorf("lb: TLS set for protocol %s", HTTP)
+		}
+	case HTTPS:
+		if s.TLS == nil || len(s.TLS.Certificates) == 0 {
+			return fmt.Errorf("lb: TLS.Certificates must be set for protocol %s", HTTPS)
+		}
+	default:
+		return fmt.Errorf("lb: invalid Protocol %q", s.Protocol)
+	}
+	if len(s.Backends) == 0 {
+		return fmt.Errorf("lb: no Backends")
+	}
+	for _, b := range s.Backends {
+		if b.Group == nil {
+			return fmt.Errorf("lb: Backend with nil Group")
+		}
+	}
+	if s.Network != "" {
+		if s.HealthCheck.Port == 0 {
+			return fmt.Errorf("lb: HealthCheck.Port must be set with Network")
+		}
+		if len(s.TargetTags) == 0 {
+			return fmt.Errorf("lb: TargetTags must be set with Network")
+		}
+	}
+	return nil
+}
+
+// Build the graph for the load balancer. The resources in IDs() are managed;
+// the Backends and Certificates are added as externally owned Nodes.
+func Build(s *Spec) (*rgraph.Builder, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	ids := s.IDs()
+	link := func(id *cloud.ResourceID) string { return id.SelfLink(meta.VersionGA) }
+	gr := rgraph.NewBuilder()
+
+	add := func(nb rnode.Builder, ownership rnode.OwnershipStatus) {
+		nb.SetOwnership(ownership)
+		nb.SetState(rnode.NodeExists)
+		gr.Add(nb)
+	}
+
+	// Address
+	addr := address.NewMutableAddress(s.Project, ids.Address.Key)
+	addrRes, err := addr.Freeze()
+	if err != nil {
+		return nil, fmt.Errorf("lb: %w", err)
+	}
+	add(address.NewBuilderWithResource(addrRes), rnode.OwnershipManaged)
+
+	// ForwardingRule
+	fr := forwardingrule.NewMutableForwardingRule(s.Project, ids.ForwardingRule.Key)
+	err = fr.Access(func(x *compute.ForwardingRule) {
+		x.IPAddress = link(ids.Address)
+		x.IPProtocol = "TCP"
+		x.PortRange = strconv.Itoa(s.port())
+		x.LoadBalancingScheme = s.scheme()
+		x.Target = link(ids.TargetProxy)
+		if s.Region != "" {
+			x.Network = s.Network
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lb: ForwardingRule: %w", err)
+	}
+	frRes, err := fr.Freeze()
+	if err != nil {
+		return nil, fmt.Errorf("lb: %w", err)
+	}
+	add(forwardingrule.NewBuilderWithResource(frRes), rnode.OwnershipManaged)
+
+	// TargetHttp(s)Proxy
+	switch s.protocol() {
+	case HTTP:
+		tp := targethttpproxy.NewMutableTargetHttpProxy(s.Project, ids.TargetProxy.Key)
+		if err := tp.Access(func(x *compute.TargetHttpProxy) { x.UrlMap = link(ids.UrlMap) }); err != nil {
+			return nil, fmt.Errorf("lb: TargetHttpProxy: %w", err)
+		}
+		tpRes, err := tp.Freeze()
+		if err != nil {
+			return nil, fmt.Errorf("lb: %w", err)
+		}
+		add(targethttpproxy.NewBuilderWithResource(tpRes), rnode.OwnershipManaged)
+	case HTTPS:
+		tp := targethttpsproxy.NewMutableTargetHttpsProxy(s.Project, ids.TargetProxy.Key)
+		err := tp.Access(func(x *compute.TargetHttpsProxy) {
+			x.UrlMap = link(ids.UrlMap)
+			x.QuicOverride = "NONE"
+			for _, cert := range s.TLS.Certificates {
+				x.SslCertificates = append(x.SslCertificates, link(cert))
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("lb: TargetHttpsProxy: %w", err)
+		}
+		tpRes, err := tp.Freeze()
+		if err != nil {
+			return nil, fmt.Errorf("lb: %w", err)
+		}
+		add(targethttpsproxy.NewBuilderWithResource(tpRes), rnode.OwnershipManaged)
+
+		for _, cert := range s.TLS.Certificates {
+			certRes, err := sslcertificate.NewMutableSslCertificate(cert.ProjectID, cert.Key).Freeze()
+			if err != nil {
+				return nil, fmt.Errorf("lb: %w", err)
+			}
+			add(sslcertificate.NewBuilderWithResource(certRes), rnode.OwnershipExternal)
+		}
+	}
+
+	// UrlMap
+	um := urlmap.NewMutableUrlMap(s.Project, ids.UrlMap.Key)
+	if err := um.Access(func(x *compute.UrlMap) { x.DefaultService = link(ids.BackendService) }); err != nil {
+		return nil, fmt.Errorf("lb: UrlMap: %w", err)
+	}
+	umRes, err := um.Freeze()
+	if err != nil {
+		return nil, fmt.Errorf("lb: %w", err)
+	}
+	add(urlmap.NewBuilderWithResource(umRes), rnode.OwnershipManaged)
+
+	// BackendService
+	bs := backendservice.NewMutableBackendService(s.Project, ids.BackendService.Key)
+	err = bs.Access(func(x *compute.BackendService) {
+		x.Protocol = "HTTP"
+		x.LoadBalancingScheme = s.scheme()
+		// API defaults, these are NonZeroValue fields.
+		x.CompressionMode = "DISABLED"
+		x.SessionAffinity = "NONE"
+		x.TimeoutSec = 30
+		x.HealthChecks = []string{link(ids.HealthCheck)}
+		for _, b := range s.Backends {
+			rate := b.MaxRatePerEndpoint
+			if rate == 0 {
+				rate = 100
+			}
+			x.Backends = append(x.Backends, &compute.Backend{
+				Group:              link(b.Group),
+				BalancingMode:      "RATE",
+				MaxRatePerEndpoint: rate,
+			})
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lb: BackendService: %w", err)
+	}
+	bsRes, err := bs.Freeze()
+	if err != nil {
+		return nil, fmt.Errorf("lb: %w", err)
+	}
+	add(backendservice.NewBuilderWithResource(bsRes), rnode.OwnershipManaged)
+
+	for _, b := range s.Backends {
+		negRes, err := networkendpointgroup.NewMutableNetworkEndpointGroup(b.Group.ProjectID, b.Group.Key).Freeze()
+		if err != nil {
+			return nil, fmt.Errorf("lb: %w", err)
+		}
+		add(networkendpointgroup.NewBuilderWithResource(negRes), rnode.OwnershipExternal)
+	}
+
+	// HealthCheck
+	hc := healthcheck.NewMutableHealthCheck(s.Project, ids.HealthCheck.Key)
+	err = hc.Access(func(x *compute.HealthCheck) {
+		x.Type = "HTTP"
+		// API defaults, these are NonZeroValue fields.
+		x.CheckIntervalSec = 5
+		x.TimeoutSec = 5
+		x.HealthyThreshold = 2
+		x.UnhealthyThreshold = 2
+		x.HttpHealthCheck = &compute.HTTPHealthCheck{RequestPath: s.HealthCheck.RequestPath}
+		if x.HttpHealthCheck.RequestPath == "" {
+			x.HttpHealthCheck.RequestPath = "/"
+		}
+		if s.HealthCheck.Port != 0 {
+			x.HttpHealthCheck.Port = s.HealthCheck.Port
+		} else {
+			x.HttpHealthCheck.PortSpecification = "USE_SERVING_PORT"
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lb: HealthCheck: %w", err)
+	}
+	hcRes, err := hc.Freeze()
+	if err != nil {
+		return nil, fmt.Errorf("lb: %w", err)
+	}
+	add(healthcheck.NewBuilderWithResource(hcRes), rnode.OwnershipManaged)
+
+	// Firewall
+	if ids.Firewall != nil {
+		fw := firewall.NewMutableFirewall(s.Project, ids.Firewall.Key)
+		err := fw.Access(func(x *compute.Firewall) {
+			x.Network = s.Network
+			x.Direction = "INGRESS"
+			x.Priority = 1000
+			x.SourceRanges = slices.Clone(healthCheckRanges)
+			x.TargetTags = slices.Clone(s.TargetTags)
+			x.Allowed = []*compute.FirewallAllowed{{
+				IPProtocol: "tcp",
+				Ports:      []string{strconv.FormatInt(s.HealthCheck.Port, 10)},
+			}}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("lb: Firewall: %w", err)
+		}
+		fwRes, err := fw.Freeze()
+		if err != nil {
+			return nil, fmt.Errorf("lb: %w", err)
+		}
+		add(firewall.NewBuilderWithResource(fwRes), rnode.OwnershipManaged)
+	}
+
+	return gr, nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lb
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/sslcertificate"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestBuild(t *testing.T) {
+	const project = "proj"
+	negID := networkendpointgroup.ID(project, meta.ZonalKey("neg", "us-central1-b"))
+	certID := sslcertificate.ID(project, meta.GlobalKey("cert"))
+	network := "https://www.googleapis.com/compute/v1/projects/proj/global/networks/default"
+
+	for _, tc := range []struct {
+		name string
+		spec Spec
+		// wantOps are the planned operations by resource type.
+		wantOps map[string]rnode.Operation
+		wantErr bool
+	}{
+		{
+			name: "global http",
+			spec: Spec{
+				Project:  project,
+				Name:     "lb",
+				Backends: []Backend{{Group: negID}},
+			},
+			wantOps: map[string]rnode.Operation{
+				"addresses":             rnode.OpCreate,
+				"backendServices":       rnode.OpCreate,
+				"forwardingRules":       rnode.OpCreate,
+				"healthChecks":          rnode.OpCreate,
+				"networkEndpointGroups": rnode.OpNothing,
+				"targetHttpProxies":     rnode.OpCreate,
+				"urlMaps":               rnode.OpCreate,
+			},
+		},
+		{
+			name: "global https with firewall",
+			spec: Spec{
+				Project:     project,
+				Name:        "lb",
+				Protocol:    HTTPS,
+				Network:     network,
+				TargetTags:  []string{"backend"},
+				Backends:    []Backend{{Group: negID}},
+				HealthCheck: HealthCheck{Port: 8080, RequestPath: "/healthz"},
+				TLS:         &TLS{Certificates: []*cloud.ResourceID{certID}},
+			},
+			wantOps: map[string]rnode.Operation{
+				"addresses":             rnode.OpCreate,
+				"backendServices":       rnode.OpCreate,
+				"firewalls":             rnode.OpCreate,
+				"forwardingRules":       rnode.OpCreate,
+				"healthChecks":          rnode.OpCreate,
+				"networkEndpointGroups": rnode.OpNothing,
+				"sslCertificates":       rnode.OpNothing,
+				"targetHttpsProxies":    rnode.OpCreate,
+				"urlMaps":               rnode.OpCreate,
+			},
+		},
+		{
+			name: "regional http",
+			spec: Spec{
+				Project:     project,
+				Name:        "lb",
+				Region:      "us-central1",
+				Network:     network,
+				TargetTags:  []string{"backend"},
+				Backends:    []Backend{{Group: negID, MaxRatePerEndpoint: 10}},
+				HealthCheck: HealthCheck{Port: 8080},
+			},
+			wantOps: map[string]rnode.Operation{
+				"addresses":             rnode.OpCreate,
+				"backendServices":       rnode.OpCreate,
+				"firewalls":             rnode.OpCreate,
+				"forwardingRules":       rnode.OpCreate,
+				"healthChecks":          rnode.OpCreate,
+				"networkEndpointGroups": rnode.OpNothing,
+				"targetHttpProxies":     rnode.OpCreate,
+				"urlMaps":               rnode.OpCreate,
+			},
+		},
+		{
+			name:    "no backends",
+			spec:    Spec{Project: project, Name: "lb"},
+			wantErr: true,
+		},
+		{
+			name: "https without TLS",
+			spec: Spec{
+				Project:  project,
+				Name:     "lb",
+				Protocol: HTTPS,
+				Backends: []Backend{{Group: negID}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "firewall without HealthCheck.Port",
+			spec: Spec{
+				Project:    project,
+				Name:       "lb",
+				Network:    network,
+				TargetTags: []string{"backend"},
+				Backends:   []Backend{{Group: negID}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "firewall without TargetTags",
+			spec: Spec{
+				Project:     project,
+				Name:        "lb",
+				Network:     network,
+				Backends:    []Backend{{Group: negID}},
+				HealthCheck: HealthCheck{Port: 8080},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid protocol",
+			spec: Spec{
+				Project:  project,
+				Name:     "lb",
+				Protocol: "TCP",
+				Backends: []Backend{{Group: negID}},
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.NetworkEndpointGroups().Insert(ctx, negID.Key, &compute.NetworkEndpointGroup{})
+			mock.SslCertificates().Insert(ctx, certID.Key, &compute.SslCertificate{})
+
+			gr, err := Build(&tc.spec)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Build() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("gr.Build() = %v, want nil", err)
+			}
+			res, err := plan.Do(ctx, mock, want)
+			if err != nil {
+				t.Fatalf("plan.Do() = %v, want nil", err)
+			}
+			gotOps := map[string]rnode.Operation{}
+			for _, n := range res.Want.All() {
+				gotOps[n.ID().Resource] = n.Plan().Op()
+			}
+			if diff := cmp.Diff(gotOps, tc.wantOps); diff != "" {
+				t.Errorf("ops: -got,+want: %s", diff)
+			}
+
+			ex, err := exec.NewSerialExecutor(mock, res.Actions)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			if _, err := ex.Run(ctx); err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+
+			// Planning again should result in no changes.
+			res, err = plan.Do(ctx, mock, want)
+			if err != nil {
+				t.Fatalf("plan.Do() = %v, want nil", err)
+			}
+			var changed []string
+			for _, n := range res.Want.All() {
+				if op := n.Plan().Op(); op != rnode.OpNothing {
+					changed = append(changed, n.ID().String()+": "+n.Plan().String())
+				}
+			}
+			sort.Strings(changed)
+			if len(changed) > 0 {
+				t.Errorf("plan after sync has changes: %v", changed)
+			}
+		})
+	}
+}
+
+func TestBuildFirewall(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	negID := networkendpointgroup.ID(project, meta.ZonalKey("neg", "us-central1-b"))
+	mock.NetworkEndpointGroups().Insert(ctx, negID.Key, &compute.NetworkEndpointGroup{})
+
+	spec := &Spec{
+		Project:     project,
+		Name:        "lb",
+		Network:     "https://www.googleapis.com/compute/v1/projects/proj/global/networks/default",
+		TargetTags:  []string{"backend"},
+		Backends:    []Backend{{Group: negID}},
+		HealthCheck: HealthCheck{Port: 8080},
+	}
+	gr, err := Build(spec)
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("gr.Build() = %v, want nil", err)
+	}
+	res, err := plan.Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("plan.Do() = %v, want nil", err)
+	}
+	ex, err := exec.NewSerialExecutor(mock, res.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	fw, err := mock.Firewalls().Get(ctx, spec.IDs().Firewall.Key)
+	if err != nil {
+		t.Fatalf("Firewalls().Get() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(fw.TargetTags, []string{"backend"}); diff != "" {
+		t.Errorf("TargetTags: -got,+want: %s", diff)
+	}
+	wantAllowed := []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"8080"}}}
+	if diff := cmp.Diff(fw.Allowed, wantAllowed); diff != "" {
+		t.Errorf("Allowed: -got,+want: %s", diff)
+	}
+}
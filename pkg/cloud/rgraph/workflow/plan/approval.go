@@ -0,0 +1,101 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+)
+
+// ErrRejected is returned (wrapped) by Do if the plan was rejected by an
+// ApprovalFunc.
+var ErrRejected = errors.New("plan rejected")
+
+// ApprovalFunc is called with the Result of planning before it is returned by
+// Do. It returns the Actions to execute, which may be a subset of
+// r.Actions (e.g. to skip Actions that are not allowed). Return an error
+// wrapping ErrRejected (see Reject) to reject the plan.
+//
+// Note: Actions that depend on Actions that were removed will not be able to
+// run and will remain in exec.Result.Pending.
+type ApprovalFunc func(ctx context.Context, r *Result) ([]exec.Action, error)
+
+// Approval adds an ApprovalFunc to the planning. ApprovalFuncs are called in
+// the order they are given; each one receives the Actions returned by the
+// previous one.
+func Approval(f ApprovalFunc) Option {
+	return func(c *config) { c.approvals = append(c.approvals, f) }
+}
+
+// Reject returns an error for an ApprovalFunc to reject the plan.
+func Reject(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrRejected, fmt.Sprintf(format, args...))
+}
+
+// NeverDelete is an ApprovalFunc that rejects plans that delete (or
+// recreate) resources.
+func NeverDelete(_ context.Context, r *Result) ([]exec.Action, error) {
+	for _, a := range r.Actions {
+		if md := a.Metadata(); md.Type == exec.ActionTypeDelete {
+			return nil, Reject("%s deletes %v", md.Name, md.Resource)
+		}
+	}
+	return r.Actions, nil
+}
+
+// SkipActions returns an ApprovalFunc that removes the Actions where skip
+// returns true from the plan.
+func SkipActions(skip func(exec.Action) bool) ApprovalFunc {
+	return func(_ context.Context, r *Result) ([]exec.Action, error) {
+		var ret []exec.Action
+		for _, a := range r.Actions {
+			if !skip(a) {
+				ret = append(ret, a)
+			}
+		}
+		return ret, nil
+	}
+}
+
+// approve calls the ApprovalFuncs, updating res.Actions and res.Skipped.
+func (pl *planner) approve(ctx context.Context, res *Result) error {
+	if len(pl.config.approvals) == 0 {
+		return nil
+	}
+	planned := res.Actions
+	for _, f := range pl.config.approvals {
+		acts, err := f(ctx, res)
+		if err != nil {
+			return fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		res.Actions = acts
+	}
+
+	kept := map[exec.Action]bool{}
+	for _, a := range res.Actions {
+		kept[a] = true
+	}
+	for _, a := range planned {
+		if !kept[a] {
+			res.Skipped = append(res.Skipped, a)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestApproval(t *testing.T) {
+	const project = "proj"
+	b := all.ResourceBuilder{Project: project}
+
+	// actionTypes returns the sorted types of the Actions.
+	actionTypes := func(acts []exec.Action) []exec.ActionType {
+		var ret []exec.ActionType
+		for _, a := range acts {
+			ret = append(ret, a.Metadata().Type)
+		}
+		sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+		return ret
+	}
+
+	for _, tc := range []struct {
+		name         string
+		opts         []Option
+		wantRejected bool
+		wantActions  []exec.ActionType
+		wantSkipped  []exec.ActionType
+	}{
+		{
+			name:        "no approval",
+			wantActions: []exec.ActionType{exec.ActionTypeDelete, exec.ActionTypeUpdate},
+		},
+		{
+			name:         "NeverDelete",
+			opts:         []Option{Approval(NeverDelete)},
+			wantRejected: true,
+		},
+		{
+			name: "SkipActions",
+			opts: []Option{Approval(SkipActions(func(a exec.Action) bool {
+				return a.Metadata().Type == exec.ActionTypeDelete
+			}))},
+			wantActions: []exec.ActionType{exec.ActionTypeUpdate},
+			wantSkipped: []exec.ActionType{exec.ActionTypeDelete},
+		},
+		{
+			name: "chained approvals",
+			opts: []Option{
+				Approval(SkipActions(func(a exec.Action) bool { return a.Metadata().Type == exec.ActionTypeDelete })),
+				Approval(NeverDelete),
+			},
+			wantActions: []exec.ActionType{exec.ActionTypeUpdate},
+			wantSkipped: []exec.ActionType{exec.ActionTypeDelete},
+		},
+		{
+			name: "custom reject",
+			opts: []Option{Approval(func(context.Context, *Result) ([]exec.Action, error) {
+				return nil, Reject("not in a maintenance window")
+			})},
+			wantRejected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc1"), &compute.HealthCheck{CheckIntervalSec: 5})
+			mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc2"), &compute.HealthCheck{})
+
+			gr := rgraph.NewBuilder()
+			hc1 := b.N("hc1").HealthCheck().Resource()
+			hc1.Access(func(x *compute.HealthCheck) { x.CheckIntervalSec = 10 })
+			hc1r, _ := hc1.Freeze()
+			nb := healthcheck.NewBuilderWithResource(hc1r)
+			nb.SetOwnership(rnode.OwnershipManaged)
+			nb.SetState(rnode.NodeExists)
+			gr.Add(nb)
+
+			hc2r, _ := b.N("hc2").HealthCheck().Resource().Freeze()
+			nb = healthcheck.NewBuilderWithResource(hc2r)
+			nb.SetOwnership(rnode.OwnershipManaged)
+			nb.SetState(rnode.NodeDoesNotExist)
+			gr.Add(nb)
+
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+			res, err := Do(ctx, mock, want, tc.opts...)
+			if tc.wantRejected {
+				if !errors.Is(err, ErrRejected) {
+					t.Fatalf("Do() = %v, want ErrRejected", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+
+			var acts []exec.Action
+			for _, a := range res.Actions {
+				// Ignore the EventActions.
+				if a.Metadata().Type != exec.ActionTypeMeta {
+					acts = append(acts, a)
+				}
+			}
+			if diff := cmp.Diff(actionTypes(acts), tc.wantActions); diff != "" {
+				t.Errorf("Actions: -got,+want: %s", diff)
+			}
+			if diff := cmp.Diff(actionTypes(res.Skipped), tc.wantSkipped); diff != "" {
+				t.Errorf("Skipped: -got,+want: %s", diff)
+			}
+		})
+	}
+}
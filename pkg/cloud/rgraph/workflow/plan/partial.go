@@ -0,0 +1,100 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Selector selects the Nodes for a partial apply of the plan.
+type Selector func(id *cloud.ResourceID) bool
+
+// ByResource selects Nodes with one of the resource types (e.g.
+// "backendServices").
+func ByResource(resources ...string) Selector {
+	m := map[string]bool{}
+	for _, r := range resources {
+		m[r] = true
+	}
+	return func(id *cloud.ResourceID) bool { return m[id.Resource] }
+}
+
+// ByIDPattern selects Nodes where the regular expression matches
+// ResourceID.String() (e.g. "backendServices:my-project/lb-.*").
+func ByIDPattern(pattern string) (Selector, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("ByIDPattern: %w", err)
+	}
+	return func(id *cloud.ResourceID) bool { return re.MatchString(id.String()) }, nil
+}
+
+// AnyOf selects Nodes that are selected by any of the Selectors.
+func AnyOf(sels ...Selector) Selector {
+	return func(id *cloud.ResourceID) bool {
+		for _, s := range sels {
+			if s(id) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Partial returns an ApprovalFunc that limits the plan to the Actions for the
+// Nodes selected by sel. This can be used for staged rollouts or to apply an
+// urgent fix without applying the rest of the plan.
+//
+// The Actions for the Nodes that are not selected are replaced by Actions that
+// signal the current state of the resource (i.e. the state in the "got"
+// graph). This means that a selected Action will only run if its
+// preconditions are met by the current state in the Cloud. For example,
+// creating a resource that references an unselected resource that does not
+// exist yet will remain in exec.Result.Pending.
+func Partial(sel Selector) ApprovalFunc {
+	return func(_ context.Context, r *Result) ([]exec.Action, error) {
+		var ret []exec.Action
+		var skipped []*cloud.ResourceID
+		seen := map[cloud.ResourceMapKey]bool{}
+		for _, a := range r.Actions {
+			// Actions without a Resource only signal events.
+			id := a.Metadata().Resource
+			if id == nil || sel(id) {
+				ret = append(ret, a)
+				continue
+			}
+			if !seen[id.MapKey()] {
+				seen[id.MapKey()] = true
+				skipped = append(skipped, id)
+			}
+		}
+		for _, id := range skipped {
+			if gotNode := r.Got.Get(id); gotNode != nil && gotNode.State() == rnode.NodeExists {
+				ret = append(ret, exec.NewExistsAction(id))
+			} else {
+				ret = append(ret, exec.NewDoesNotExistAction(id))
+			}
+		}
+		return ret, nil
+	}
+}
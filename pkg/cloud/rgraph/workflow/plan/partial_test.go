@@ -0,0 +1,159 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	cloudmock "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/all"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/backendservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheck"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestPartial(t *testing.T) {
+	const project = "proj"
+	b := all.ResourceBuilder{Project: project}
+
+	mustPattern := func(p string) Selector {
+		s, err := ByIDPattern(p)
+		if err != nil {
+			t.Fatalf("ByIDPattern(%q) = %v, want nil", p, err)
+		}
+		return s
+	}
+
+	for _, tc := range []struct {
+		name string
+		sel  Selector
+		// wantInterval of hc1 after execution.
+		wantInterval int64
+		wantHC2      bool
+		wantBS       bool
+		wantPending  int
+	}{
+		{
+			name:         "all",
+			sel:          func(*cloud.ResourceID) bool { return true },
+			wantInterval: 10,
+			wantHC2:      true,
+			wantBS:       true,
+		},
+		{
+			name:         "health checks",
+			sel:          ByResource("healthChecks"),
+			wantInterval: 10,
+			wantHC2:      true,
+		},
+		{
+			name:         "by pattern",
+			sel:          mustPattern("healthChecks:proj/hc1$"),
+			wantInterval: 10,
+		},
+		{
+			name:         "AnyOf",
+			sel:          AnyOf(mustPattern("/hc1$"), mustPattern("/hc2$")),
+			wantInterval: 10,
+			wantHC2:      true,
+		},
+		{
+			// The BackendService references hc2 which will not be created.
+			name:         "dependency not selected",
+			sel:          ByResource("backendServices"),
+			wantInterval: 5,
+			wantPending:  1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.MockHealthChecks.UpdateHook = cloudmock.UpdateHealthCheckHook
+			mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc1"), &compute.HealthCheck{CheckIntervalSec: 5})
+
+			gr := rgraph.NewBuilder()
+			hc1 := b.N("hc1").HealthCheck().Resource()
+			hc1.Access(func(x *compute.HealthCheck) { x.CheckIntervalSec = 10 })
+			hc1r, _ := hc1.Freeze()
+			gr.Add(healthcheck.NewBuilderWithResource(hc1r))
+
+			hc2r, _ := b.N("hc2").HealthCheck().Resource().Freeze()
+			gr.Add(healthcheck.NewBuilderWithResource(hc2r))
+
+			bs := b.N("bs").BackendService().Resource()
+			bs.Access(func(x *compute.BackendService) {
+				x.HealthChecks = []string{b.N("hc2").HealthCheck().SelfLink()}
+			})
+			bsr, _ := bs.Freeze()
+			gr.Add(backendservice.NewBuilderWithResource(bsr))
+
+			for _, nb := range gr.All() {
+				nb.SetOwnership(rnode.OwnershipManaged)
+				nb.SetState(rnode.NodeExists)
+			}
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+			res, err := Do(ctx, mock, want, Approval(Partial(tc.sel)))
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+
+			ex, err := exec.NewParallelExecutor(mock, res.Actions)
+			if err != nil {
+				t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(ctx)
+			if gotErr := err != nil; gotErr != (tc.wantPending > 0) {
+				t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantPending > 0)
+			}
+			if len(result.Pending) != tc.wantPending {
+				t.Errorf("len(Pending) = %d, want %d (%v)", len(result.Pending), tc.wantPending, result.Pending)
+			}
+
+			hc, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("hc1"))
+			if err != nil {
+				t.Fatalf("HealthChecks().Get(hc1) = %v, want nil", err)
+			}
+			if diff := cmp.Diff(hc.CheckIntervalSec, tc.wantInterval); diff != "" {
+				t.Errorf("hc1.CheckIntervalSec: -got,+want: %s", diff)
+			}
+			_, err = mock.HealthChecks().Get(ctx, meta.GlobalKey("hc2"))
+			if gotHC2 := err == nil; gotHC2 != tc.wantHC2 {
+				t.Errorf("hc2 exists = %t, want %t", gotHC2, tc.wantHC2)
+			}
+			_, err = mock.BackendServices().Get(ctx, meta.GlobalKey("bs"))
+			if gotBS := err == nil; gotBS != tc.wantBS {
+				t.Errorf("bs exists = %t, want %t", gotBS, tc.wantBS)
+			}
+		})
+	}
+}
+
+func TestByIDPatternInvalid(t *testing.T) {
+	if _, err := ByIDPattern("("); err == nil {
+		t.Errorf("ByIDPattern(%q) = nil, want error", "(")
+	}
+}
@@ -19,39 +19,212 @@ package plan
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/actions"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/localplan"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/quota"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/traversal"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/trclosure"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Result struct {
 	Got     *rgraph.Graph
 	Want    *rgraph.Graph
 	Actions []exec.Action
+	// Skipped are the planned Actions that were removed by an ApprovalFunc.
+	Skipped []exec.Action
+	// Swaps are the resources that will be replaced by a copy with a
+	// different name (see CreateThenSwap).
+	Swaps []Swap
+	// SpanContext of the span for the plan. Pass this to
+	// exec.TraceParentOption to link the execution to the same trace.
+	SpanContext trace.SpanContext
+}
+
+// Swap of a resource Old with a replacement New.
+type Swap struct {
+	Old *cloud.ResourceID
+	New *cloud.ResourceID
+}
+
+// DeletionProtectedError is returned by Do if the plan would delete or
+// recreate resources that are DeletionProtected.
+type DeletionProtectedError struct {
+	Nodes []ProtectedNode
+}
+
+// ProtectedNode is a DeletionProtected resource and the operation that was
+// planned for it.
+type ProtectedNode struct {
+	ID  *cloud.ResourceID
+	Op  rnode.Operation
+	Why string
+}
+
+func (e *DeletionProtectedError) Error() string {
+	var s []string
+	for _, n := range e.Nodes {
+		s = append(s, fmt.Sprintf("%v (%s: %s)", n.ID, n.Op, n.Why))
+	}
+	return fmt.Sprintf("%s: plan deletes resources with deletion protection: [%s]", errPrefix, strings.Join(s, ", "))
+}
+
+// Option for Do.
+type Option func(*config)
+
+// FetchOptions are passed to the fetch of the current ("got") graph from the
+// Cloud. Use this to set the parallelism and rate limiting of the fetch, e.g.
+// FetchOptions(trclosure.WorkerCount(10)).
+func FetchOptions(opts ...trclosure.Option) Option {
+	return func(c *config) { c.fetchOpts = append(c.fetchOpts, opts...) }
+}
+
+// QuotaCheck enables a pre-flight check of the plan against the project
+// quota. Planning will fail with a *quota.ExceededError if the resources to be
+// created exceed the remaining quota.
+func QuotaCheck(opts ...quota.Option) Option {
+	return func(c *config) {
+		c.quotaCheck = true
+		c.quotaOpts = append(c.quotaOpts, opts...)
+	}
+}
+
+// Adopt takes ownership of existing resources that are not currently managed
+// (e.g. resources created manually) instead of recreating them. The Nodes must
+// be in the want graph and the resources must already exist. If the existing
+// resource differs from want, it will be updated in place; planning fails if
+// the changes would require the resource to be recreated.
+//
+// The caller must record the adopted resources (the Nodes planned with
+// OpAdopt) so that IsManaged returns true for them in subsequent plans.
+func Adopt(ids ...*cloud.ResourceID) Option {
+	return func(c *config) { c.adopt = append(c.adopt, ids...) }
+}
+
+// IsManaged sets the function used to determine the ownership of the
+// existing resources fetched from the Cloud. Resources where f returns false
+// are OwnershipExternal: the plan will not delete them and planning fails if
+// a managed Node in want would change an unmanaged resource, unless the
+// resource is adopted (see Adopt).
+//
+// The default treats all existing resources as managed.
+func IsManaged(f func(id *cloud.ResourceID) bool) Option {
+	return func(c *config) { c.isManaged = f }
+}
+
+// CreateThenSwap changes the strategy used to recreate resources that are
+// referenced by other resources. By default, the resource is deleted and then
+// created again, which causes downtime for the resources that reference it.
+// With CreateThenSwap, a replacement is created with the ID returned by rename,
+// the references are updated to point to the replacement and the old resource
+// is deleted last.
+//
+// The replaced resources are returned in Result.Swaps; the caller must use the
+// new names for subsequent plans. The strategy is only used if the resource
+// and all of the resources referencing it implement rnode.RefRewriter.
+func CreateThenSwap(rename func(id *cloud.ResourceID) *cloud.ResourceID) Option {
+	return func(c *config) { c.rename = rename }
+}
+
+// ResolveOnDemand marks Nodes that are only fetched from the Cloud if they are
+// needed by the plan. This reduces the number of API calls when planning large
+// graphs that are mostly unchanged.
+//
+// The Nodes are assumed to be unchanged from "want" and are fetched only if a
+// Node that references them needs to be changed (e.g. created or updated).
+// Nodes that do not exist in "want" or are being adopted are always fetched.
+func ResolveOnDemand(ids ...*cloud.ResourceID) Option {
+	return func(c *config) { c.resolveOnDemand = append(c.resolveOnDemand, ids...) }
+}
+
+// Transactional validates that the plan can be executed with
+// exec.NewTransactionalExecutor. Planning fails if any of the planned Actions
+// cannot be compensated.
+func Transactional() Option {
+	return func(c *config) { c.transactional = true }
+}
+
+// TracerProvider sets the OpenTelemetry TracerProvider used to emit spans for
+// the plan. The default is the global TracerProvider (otel.GetTracerProvider()).
+//
+// A span is emitted for the plan with child spans for the fetch of the
+// resources and the diff of each Node.
+func TracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+type config struct {
+	adopt           []*cloud.ResourceID
+	approvals       []ApprovalFunc
+	fetchOpts       []trclosure.Option
+	isManaged       func(id *cloud.ResourceID) bool
+	quotaCheck      bool
+	quotaOpts       []quota.Option
+	rename          func(id *cloud.ResourceID) *cloud.ResourceID
+	resolveOnDemand []*cloud.ResourceID
+	tracerProvider  trace.TracerProvider
+	transactional   bool
 }
 
 // Do will plan updates to cloud resources wanted in graph. Returns the set of
 // Actions needed to sync to "want".
-func Do(ctx context.Context, c cloud.Cloud, want *rgraph.Graph) (*Result, error) {
+func Do(ctx context.Context, c cloud.Cloud, want *rgraph.Graph, opts ...Option) (*Result, error) {
 	w := planner{
-		cloud: c,
-		want:  want,
+		cloud:      c,
+		want:       want,
+		tombstones: map[cloud.ResourceMapKey]bool{},
 	}
-	return w.plan(ctx)
+	for _, o := range opts {
+		o(&w.config)
+	}
+
+	tp := w.config.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	ctx, span := tp.Tracer(instrumentationName).Start(ctx, "rgraph.Plan",
+		trace.WithAttributes(attrNodes.Int(len(want.All()))))
+	defer span.End()
+
+	res, err := w.plan(ctx)
+	if err == nil {
+		err = w.approve(ctx, res)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(exec.AttrActions.Int(len(res.Actions)))
+	res.SpanContext = span.SpanContext()
+	return res, nil
 }
 
-const errPrefix = "Plan"
+const (
+	errPrefix           = "Plan"
+	instrumentationName = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	attrNodes           = attribute.Key("rgraph.nodes")
+)
 
 type planner struct {
+	config config
+
 	cloud cloud.Cloud
 	got   *rgraph.Graph
 	want  *rgraph.Graph
+
+	// tombstones are the Nodes added to want for resources to be deleted.
+	tombstones map[cloud.ResourceMapKey]bool
 }
 
 func (pl *planner) plan(ctx context.Context) (*Result, error) {
@@ -60,21 +233,140 @@ func (pl *planner) plan(ctx context.Context) (*Result, error) {
 	// are not in the "want" graph.
 	gotBuilder := pl.want.NewBuilderWithEmptyNodes()
 
-	// Fetch the current resource graph from Cloud.
+	// Nodes that are resolved on demand start out as a copy of "want" and are
+	// not fetched.
+	lazy, err := pl.initLazyNodes(gotBuilder)
+	if err != nil {
+		return nil, err
+	}
+	skip := func(n rnode.Builder) bool { return lazy[n.ID().MapKey()] != nil }
+
+	for {
+		if err := pl.fetch(ctx, gotBuilder, skip); err != nil {
+			return nil, err
+		}
+		if err := pl.localPlan(ctx, gotBuilder); err != nil {
+			return nil, err
+		}
+
+		// Fetch the lazy Nodes that are needed by the plan and plan again.
+		resolve := pl.lazyNodesToResolve(lazy)
+		if len(resolve) == 0 {
+			break
+		}
+		fetched := map[cloud.ResourceMapKey]bool{}
+		for _, n := range gotBuilder.All() {
+			fetched[n.ID().MapKey()] = true
+		}
+		for _, id := range resolve {
+			delete(lazy, id.MapKey())
+			delete(fetched, id.MapKey())
+			gotBuilder.Add(pl.want.Get(id).Builder())
+		}
+		skip = func(n rnode.Builder) bool { return fetched[n.ID().MapKey()] }
+	}
+
+	if err := pl.planAdoptions(); err != nil {
+		return nil, err
+	}
+
+	if pl.config.rename != nil {
+		want, swaps, err := pl.swapRecreates()
+		if err != nil {
+			return nil, err
+		}
+		if len(swaps) > 0 {
+			// Plan again using the graph with the replacement resources.
+			next := planner{
+				config:     pl.config,
+				cloud:      pl.cloud,
+				want:       want,
+				tombstones: map[cloud.ResourceMapKey]bool{},
+			}
+			next.config.rename = nil
+			res, err := next.plan(ctx)
+			if err != nil {
+				return nil, err
+			}
+			res.Swaps = swaps
+			return res, nil
+		}
+	}
+
+	if err := pl.propagateRecreates(); err != nil {
+		return nil, err
+	}
+
+	if err := pl.checkOwnership(); err != nil {
+		return nil, err
+	}
+
+	if err := pl.checkDeletionProtection(); err != nil {
+		return nil, err
+	}
+
+	if err := pl.sanityCheck(); err != nil {
+		return nil, err
+	}
+
+	if pl.config.quotaCheck {
+		if err := quota.Check(ctx, pl.cloud, pl.want, pl.config.quotaOpts...); err != nil {
+			return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+	}
+
+	acts, err := actions.Do(pl.got, pl.want)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	if pl.config.transactional {
+		if err := exec.CheckCompensations(acts); err != nil {
+			return nil, fmt.Errorf("%s: plan cannot be run transactionally: %w", errPrefix, err)
+		}
+	}
+	return &Result{
+		Got:     pl.got,
+		Want:    pl.want,
+		Actions: acts,
+	}, nil
+}
+
+// fetch the current state of the resources in gotBuilder from the Cloud,
+// skipping the Nodes where skip() returns true.
+func (pl *planner) fetch(ctx context.Context, gotBuilder *rgraph.Builder, skip func(rnode.Builder) bool) error {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(instrumentationName)
+	ctx, span := tracer.Start(ctx, "rgraph.Fetch")
+	defer span.End()
+
 	// TODO: resource_prefix, ownership due to prefix etc.
-	err := trclosure.Do(ctx, pl.cloud, gotBuilder,
+	fetchOpts := append([]trclosure.Option{}, pl.config.fetchOpts...)
+	fetchOpts = append(fetchOpts,
 		trclosure.OnGetFunc(func(n rnode.Builder) error {
-			n.SetOwnership(rnode.OwnershipManaged)
+			if pl.config.isManaged == nil || pl.config.isManaged(n.ID()) {
+				n.SetOwnership(rnode.OwnershipManaged)
+			} else {
+				n.SetOwnership(rnode.OwnershipExternal)
+			}
 			return nil
 		}),
+		trclosure.SkipFetchFunc(skip),
 	)
-	if err != nil {
-		return nil, err
+	if err := trclosure.Do(ctx, pl.cloud, gotBuilder, fetchOpts...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
+	span.SetAttributes(attrNodes.Int(len(gotBuilder.All())))
+	return nil
+}
 
+// localPlan builds the "got" graph and computes the local plan for each
+// resource in "want".
+func (pl *planner) localPlan(ctx context.Context, gotBuilder *rgraph.Builder) error {
+	var err error
 	pl.got, err = gotBuilder.Build()
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		return fmt.Errorf("%s: %w", errPrefix, err)
 	}
 
 	// Figure out what to do with Nodes in "got" that aren't in "want". These
@@ -91,39 +383,208 @@ func (pl *planner) plan(ctx context.Context) (*Result, error) {
 			wantNodeBuilder.SetState(rnode.NodeDoesNotExist)
 			wantNode, err := wantNodeBuilder.Build()
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = pl.want.AddTombstone(wantNode)
 			if err != nil {
-				return nil, err
+				return err
 			}
+			pl.tombstones[wantNode.ID().MapKey()] = true
 		default:
-			return nil, fmt.Errorf("%s: node %s has invalid ownership %s", errPrefix, gotNode.ID(), gotNode.Ownership())
+			return fmt.Errorf("%s: node %s has invalid ownership %s", errPrefix, gotNode.ID(), gotNode.Ownership())
 		}
 	}
 
 	// Compute the local plan for each resource.
-	if err := localplan.PlanWantGraph(pl.got, pl.want); err != nil {
-		return nil, err
+	return localplan.PlanWantGraphContext(ctx, pl.got, pl.want)
+}
+
+// initLazyNodes sets the Nodes to be resolved on demand in gotBuilder to a
+// copy of the "want" Node. The copy will be planned with OpNothing. Returns
+// the set of lazy Nodes.
+func (pl *planner) initLazyNodes(gotBuilder *rgraph.Builder) (map[cloud.ResourceMapKey]*cloud.ResourceID, error) {
+	adopt := map[cloud.ResourceMapKey]bool{}
+	for _, id := range pl.config.adopt {
+		adopt[id.MapKey()] = true
 	}
 
-	if err := pl.propagateRecreates(); err != nil {
-		return nil, err
+	lazy := map[cloud.ResourceMapKey]*cloud.ResourceID{}
+	for _, id := range pl.config.resolveOnDemand {
+		wantNode := pl.want.Get(id)
+		switch {
+		case wantNode == nil:
+			return nil, fmt.Errorf("%s: cannot resolve %v on demand: node is not in the want graph", errPrefix, id)
+		case wantNode.State() != rnode.NodeExists || adopt[id.MapKey()]:
+			// The current state of the resource is always needed to plan
+			// these Nodes.
+			continue
+		}
+		nb := wantNode.Builder()
+		if err := nb.SetResource(wantNode.Resource()); err != nil {
+			return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		}
+		gotBuilder.Add(nb)
+		lazy[id.MapKey()] = id
 	}
+	return lazy, nil
+}
 
-	if err := pl.sanityCheck(); err != nil {
-		return nil, err
+// lazyNodesToResolve returns the lazy Nodes that need to be fetched from the
+// Cloud. This is the case if a Node referencing the lazy Node (in "got" or
+// "want") is going to be changed by the plan.
+func (pl *planner) lazyNodesToResolve(lazy map[cloud.ResourceMapKey]*cloud.ResourceID) []*cloud.ResourceID {
+	var ret []*cloud.ResourceID
+	for _, id := range lazy {
+		var inRefs []rnode.ResourceRef
+		for _, g := range []*rgraph.Graph{pl.got, pl.want} {
+			if n := g.Get(id); n != nil {
+				inRefs = append(inRefs, n.InRefs()...)
+			}
+		}
+		for _, ref := range inRefs {
+			if n := pl.want.Get(ref.From); n != nil && n.Plan().Op() != rnode.OpNothing {
+				ret = append(ret, id)
+				break
+			}
+		}
 	}
+	return ret
+}
 
-	acts, err := actions.Do(pl.got, pl.want)
+// planAdoptions replaces the local plan for the Nodes to be adopted with
+// OpAdopt.
+func (pl *planner) planAdoptions() error {
+	for _, id := range pl.config.adopt {
+		wantNode := pl.want.Get(id)
+		if wantNode == nil {
+			return fmt.Errorf("%s: cannot adopt %v: node is not in the want graph", errPrefix, id)
+		}
+		if wantNode.Ownership() != rnode.OwnershipManaged || wantNode.State() != rnode.NodeExists {
+			return fmt.Errorf("%s: cannot adopt %v: node must be managed and exist in want (ownership=%s, state=%s)", errPrefix, id, wantNode.Ownership(), wantNode.State())
+		}
+		if gotNode := pl.got.Get(id); gotNode == nil || gotNode.State() != rnode.NodeExists {
+			return fmt.Errorf("%s: cannot adopt %v: resource does not exist", errPrefix, id)
+		}
+
+		details := wantNode.Plan().Details()
+		switch details.Operation {
+		case rnode.OpNothing, rnode.OpUpdate:
+			wantNode.Plan().Set(rnode.PlanDetails{
+				Operation: rnode.OpAdopt,
+				Why:       fmt.Sprintf("Adopt existing resource (%s)", details.Why),
+				Diff:      details.Diff,
+			})
+		case rnode.OpRecreate:
+			return fmt.Errorf("%s: cannot adopt %v: resource must be recreated (%s)", errPrefix, id, details.Why)
+		default:
+			return fmt.Errorf("%s: cannot adopt %v: invalid op %s", errPrefix, id, details.Operation)
+		}
+	}
+	return nil
+}
+
+// swapRecreates returns a new want graph where the referenced Nodes planned for
+// recreate are replaced with a copy with a new ID. References to the Nodes are
+// rewritten to the copy and the original Nodes are planned for deletion.
+// Returns a nil graph if there is nothing to swap.
+func (pl *planner) swapRecreates() (*rgraph.Graph, []Swap, error) {
+	renames := map[cloud.ResourceMapKey]*cloud.ResourceID{}
+	var swaps []Swap
+	for _, n := range pl.want.All() {
+		if n.Plan().Op() != rnode.OpRecreate || len(n.InRefs()) == 0 || !pl.canSwap(n) {
+			continue
+		}
+		newID := pl.config.rename(n.ID())
+		switch {
+		case newID == nil || newID.Key == nil:
+			return nil, nil, fmt.Errorf("%s: invalid rename of %v", errPrefix, n.ID())
+		case newID.Resource != n.ID().Resource || newID.Key.Type() != n.ID().Key.Type():
+			return nil, nil, fmt.Errorf("%s: rename of %v to %v changes the resource type or scope", errPrefix, n.ID(), newID)
+		case pl.want.Get(newID) != nil:
+			return nil, nil, fmt.Errorf("%s: rename of %v to %v already exists in the graph", errPrefix, n.ID(), newID)
+		}
+		renames[n.ID().MapKey()] = newID
+		swaps = append(swaps, Swap{Old: n.ID(), New: newID})
+	}
+	if len(swaps) == 0 {
+		return nil, nil, nil
+	}
+	sort.Slice(swaps, func(i, j int) bool { return swaps[i].Old.String() < swaps[j].Old.String() })
+
+	// Nodes that contain references to the swapped Nodes.
+	referrers := map[cloud.ResourceMapKey]bool{}
+	for _, s := range swaps {
+		for _, ref := range pl.want.Get(s.Old).InRefs() {
+			referrers[ref.From.MapKey()] = true
+		}
+	}
+
+	builder := rgraph.NewBuilder()
+	for _, n := range pl.want.All() {
+		key := n.ID().MapKey()
+		if pl.tombstones[key] {
+			// Tombstones will be recomputed when planning the new graph.
+			continue
+		}
+		if newID, ok := renames[key]; ok {
+			nb, err := n.(rnode.RefRewriter).RewriteRefs(newID, renames)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+			}
+			nb.SetState(rnode.NodeExists)
+			nb.SetOwnership(rnode.OwnershipManaged)
+			nb.SetDeletionProtected(n.DeletionProtected())
+			builder.Add(nb)
+
+			// The old resource is deleted.
+			tombstone := n.Builder()
+			tombstone.SetState(rnode.NodeDoesNotExist)
+			builder.Add(tombstone)
+			continue
+		}
+
+		nb := n.Builder()
+		if r := n.Resource(); r != nil {
+			if err := nb.SetResource(r); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+			}
+		}
+		if referrers[key] {
+			var err error
+			nb, err = n.(rnode.RefRewriter).RewriteRefs(n.ID(), renames)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
+			}
+			nb.SetState(n.State())
+			nb.SetOwnership(n.Ownership())
+			nb.SetDeletionProtected(n.DeletionProtected())
+		}
+		builder.Add(nb)
+	}
+
+	want, err := builder.Build()
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", errPrefix, err)
+		return nil, nil, fmt.Errorf("%s: %w", errPrefix, err)
 	}
-	return &Result{
-		Got:     pl.got,
-		Want:    pl.want,
-		Actions: acts,
-	}, nil
+	return want, swaps, nil
+}
+
+// canSwap returns true if n and all of the Nodes referencing it can be
+// rewritten to use a replacement for n.
+func (pl *planner) canSwap(n rnode.Node) bool {
+	if _, ok := n.(rnode.RefRewriter); !ok {
+		return false
+	}
+	for _, ref := range n.InRefs() {
+		inNode := pl.want.Get(ref.From)
+		if inNode == nil || inNode.Ownership() != rnode.OwnershipManaged || inNode.State() != rnode.NodeExists {
+			return false
+		}
+		if _, ok := inNode.(rnode.RefRewriter); !ok {
+			return false
+		}
+	}
+	return true
 }
 
 // propagateRecreates through inbound references. If a resource needs to be
@@ -158,6 +619,9 @@ func (pl *planner) propagateRecreates() error {
 			switch inRefNode.Plan().Op() {
 			case rnode.OpCreate, rnode.OpRecreate, rnode.OpDelete:
 				// Resource is already being created or destroy.
+			case rnode.OpAdopt:
+				// Adoption never recreates the resource.
+				return fmt.Errorf("%s: cannot adopt %v: dependency %v is being recreated", errPrefix, inRefNode.ID(), n.ID())
 			case rnode.OpNothing, rnode.OpUpdate:
 				inRefNode.Plan().Set(rnode.PlanDetails{
 					Operation: rnode.OpRecreate,
@@ -171,6 +635,44 @@ func (pl *planner) propagateRecreates() error {
 	return nil
 }
 
+// checkOwnership returns an error if the plan changes an existing resource that
+// is not managed and is not being adopted.
+func (pl *planner) checkOwnership() error {
+	for _, n := range pl.want.All() {
+		gotNode := pl.got.Get(n.ID())
+		if gotNode == nil || gotNode.State() != rnode.NodeExists || gotNode.Ownership() == rnode.OwnershipManaged {
+			continue
+		}
+		switch op := n.Plan().Op(); op {
+		case rnode.OpNothing, rnode.OpAdopt:
+		default:
+			return fmt.Errorf("%s: %v is not managed (ownership=%s), but is planned for %s (%s); use Adopt() to take ownership",
+				errPrefix, n.ID(), gotNode.Ownership(), op, n.Plan().Details().Why)
+		}
+	}
+	return nil
+}
+
+// checkDeletionProtection returns a *DeletionProtectedError if the plan
+// deletes or recreates any DeletionProtected Nodes.
+func (pl *planner) checkDeletionProtection() error {
+	var e DeletionProtectedError
+	for _, n := range pl.want.All() {
+		if !n.DeletionProtected() {
+			continue
+		}
+		switch op := n.Plan().Op(); op {
+		case rnode.OpDelete, rnode.OpRecreate:
+			e.Nodes = append(e.Nodes, ProtectedNode{ID: n.ID(), Op: op, Why: n.Plan().Details().Why})
+		}
+	}
+	if len(e.Nodes) > 0 {
+		sort.Slice(e.Nodes, func(i, j int) bool { return e.Nodes[i].ID.String() < e.Nodes[j].ID.String() })
+		return &e
+	}
+	return nil
+}
+
 func (pl *planner) sanityCheck() error {
 	for _, n := range pl.want.All() {
 		switch n.Plan().Op() {
@@ -18,10 +18,14 @@ package plan
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	cloudmock "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/graphviz"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
@@ -34,6 +38,9 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targethttpproxy"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/urlmap"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/tracerec"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/api/compute/v1"
 )
 
@@ -150,3 +157,922 @@ func TestLB(t *testing.T) {
 	t.Logf("got: %s", graphviz.Do(res.Got))
 	t.Logf("want: %s", graphviz.Do(res.Want))
 }
+
+func TestAdopt(t *testing.T) {
+	const project = "proj"
+	b := all.ResourceBuilder{Project: project}
+	hcID := b.N("hc").HealthCheck().ID()
+
+	for _, tc := range []struct {
+		name       string
+		existing   *compute.HealthCheck
+		want       func(*compute.HealthCheck)
+		wantErr    bool
+		wantDiff   bool
+		wantAction string
+	}{
+		{
+			name:       "resource matches",
+			existing:   &compute.HealthCheck{CheckIntervalSec: 5},
+			want:       func(x *compute.HealthCheck) { x.CheckIntervalSec = 5 },
+			wantAction: "EventAction([Exists(" + hcID.String() + ")])",
+		},
+		{
+			name:       "resource is updated",
+			existing:   &compute.HealthCheck{CheckIntervalSec: 5},
+			want:       func(x *compute.HealthCheck) { x.CheckIntervalSec = 10 },
+			wantDiff:   true,
+			wantAction: "GenericUpdateAction(" + hcID.String() + ")",
+		},
+		{
+			name:    "resource does not exist",
+			want:    func(x *compute.HealthCheck) { x.CheckIntervalSec = 5 },
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			if tc.existing != nil {
+				mock.HealthChecks().Insert(context.Background(), meta.GlobalKey("hc"), tc.existing)
+				// Populate the output-only fields.
+				got, _ := mock.HealthChecks().Get(context.Background(), meta.GlobalKey("hc"))
+				tc.existing = got
+			}
+
+			m := b.N("hc").HealthCheck().Resource()
+			m.Access(func(x *compute.HealthCheck) {
+				if tc.existing != nil {
+					x.SelfLink = tc.existing.SelfLink
+				}
+				tc.want(x)
+			})
+			r, _ := m.Freeze()
+			nb := healthcheck.NewBuilderWithResource(r)
+			nb.SetOwnership(rnode.OwnershipManaged)
+			nb.SetState(rnode.NodeExists)
+			gr := rgraph.NewBuilder()
+			gr.Add(nb)
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+
+			res, err := Do(context.Background(), mock, want, Adopt(hcID))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			plan := res.Want.Get(hcID).Plan()
+			if plan.Op() != rnode.OpAdopt {
+				t.Fatalf("Op() = %s, want %s (%s)", plan.Op(), rnode.OpAdopt, plan)
+			}
+			if gotDiff := plan.Details().Diff != nil && plan.Details().Diff.HasDiff(); gotDiff != tc.wantDiff {
+				t.Errorf("HasDiff() = %t, want %t (%+v)", gotDiff, tc.wantDiff, plan.Details().Diff)
+			}
+			if len(res.Actions) != 1 || res.Actions[0].String() != tc.wantAction {
+				t.Errorf("Actions = %v, want [%s]", res.Actions, tc.wantAction)
+			}
+		})
+	}
+}
+
+func TestAdoptWithRecreatedDependency(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+	frID := b.N("fr").ForwardingRule().ID()
+	tpID := b.N("tp").TargetHttpProxy().ID()
+	umID := b.N("um").UrlMap().ID()
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	mock.UrlMaps().Insert(ctx, meta.GlobalKey("um"), &compute.UrlMap{})
+	mock.TargetHttpProxies().Insert(ctx, meta.GlobalKey("tp"), &compute.TargetHttpProxy{
+		Description: "old",
+		UrlMap:      umID.SelfLink(meta.VersionGA),
+	})
+	mock.GlobalForwardingRules().Insert(ctx, meta.GlobalKey("fr"), &compute.ForwardingRule{
+		Target: tpID.SelfLink(meta.VersionGA),
+	})
+
+	umm := b.N("um").UrlMap().Resource()
+	umr, _ := umm.Freeze()
+	// Changing the Description of the TargetHttpProxy requires it to be
+	// recreated.
+	tpm := b.N("tp").TargetHttpProxy().Resource()
+	tpm.Access(func(x *compute.TargetHttpProxy) {
+		x.Description = "new"
+		x.UrlMap = umID.SelfLink(meta.VersionGA)
+	})
+	tpr, _ := tpm.Freeze()
+	frm := b.N("fr").ForwardingRule().Resource()
+	frm.Access(func(x *compute.ForwardingRule) { x.Target = tpID.SelfLink(meta.VersionGA) })
+	frr, _ := frm.Freeze()
+
+	gr := rgraph.NewBuilder()
+	for _, nb := range []rnode.Builder{
+		urlmap.NewBuilderWithResource(umr),
+		targethttpproxy.NewBuilderWithResource(tpr),
+		forwardingrule.NewBuilderWithResource(frr),
+	} {
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		gr.Add(nb)
+	}
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	_, err = Do(ctx, mock, want, Adopt(frID))
+	if err == nil {
+		t.Fatalf("Do() = nil, want error")
+	}
+	for _, id := range []*cloud.ResourceID{frID, tpID} {
+		if !strings.Contains(err.Error(), id.String()) {
+			t.Errorf("Do() = %v, want error containing %v", err, id)
+		}
+	}
+}
+
+func TestUnmanagedResource(t *testing.T) {
+	const project = "proj"
+	b := all.ResourceBuilder{Project: project}
+	hcID := b.N("hc").HealthCheck().ID()
+
+	for _, tc := range []struct {
+		name      string
+		isManaged bool
+		opts      []Option
+		wantErr   bool
+		wantOp    rnode.Operation
+		// wantInterval is the CheckIntervalSec of the resource after the
+		// plan is executed.
+		wantInterval int64
+	}{
+		{
+			name:         "managed",
+			isManaged:    true,
+			wantOp:       rnode.OpUpdate,
+			wantInterval: 10,
+		},
+		{
+			name:         "unmanaged",
+			wantErr:      true,
+			wantInterval: 5,
+		},
+		{
+			name:         "unmanaged and adopted",
+			opts:         []Option{Adopt(hcID)},
+			wantOp:       rnode.OpAdopt,
+			wantInterval: 10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.MockHealthChecks.UpdateHook = cloudmock.UpdateHealthCheckHook
+			mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &compute.HealthCheck{CheckIntervalSec: 5})
+
+			m := b.N("hc").HealthCheck().Resource()
+			m.Access(func(x *compute.HealthCheck) { x.CheckIntervalSec = 10 })
+			r, _ := m.Freeze()
+			nb := healthcheck.NewBuilderWithResource(r)
+			nb.SetOwnership(rnode.OwnershipManaged)
+			nb.SetState(rnode.NodeExists)
+			gr := rgraph.NewBuilder()
+			gr.Add(nb)
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+
+			opts := append([]Option{IsManaged(func(*cloud.ResourceID) bool { return tc.isManaged })}, tc.opts...)
+			res, err := Do(ctx, mock, want, opts...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Do() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if err == nil {
+				if op := res.Want.Get(hcID).Plan().Op(); op != tc.wantOp {
+					t.Errorf("Op() = %s, want %s", op, tc.wantOp)
+				}
+				ex, err := exec.NewSerialExecutor(mock, res.Actions)
+				if err != nil {
+					t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+				}
+				if _, err := ex.Run(ctx); err != nil {
+					t.Fatalf("Run() = %v, want nil", err)
+				}
+			}
+
+			hc, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("hc"))
+			if err != nil {
+				t.Fatalf("HealthChecks().Get() = %v, want nil", err)
+			}
+			if hc.CheckIntervalSec != tc.wantInterval {
+				t.Errorf("CheckIntervalSec = %d, want %d", hc.CheckIntervalSec, tc.wantInterval)
+			}
+		})
+	}
+}
+
+func TestCreateThenSwap(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+	frID := b.N("fr").ForwardingRule().ID()
+	tpID := b.N("tp").TargetHttpProxy().ID()
+	umID := b.N("um").UrlMap().ID()
+	rename := func(id *cloud.ResourceID) *cloud.ResourceID {
+		return b.N(id.Key.Name + "-2").TargetHttpProxy().ID()
+	}
+	tp2ID := rename(tpID)
+
+	for _, tc := range []struct {
+		name      string
+		opts      []Option
+		wantOps   map[string]rnode.Operation
+		wantSwaps []Swap
+	}{
+		{
+			name: "recreate",
+			wantOps: map[string]rnode.Operation{
+				frID.String(): rnode.OpRecreate,
+				tpID.String(): rnode.OpRecreate,
+				umID.String(): rnode.OpNothing,
+			},
+		},
+		{
+			name: "create then swap",
+			opts: []Option{CreateThenSwap(rename)},
+			wantOps: map[string]rnode.Operation{
+				frID.String():  rnode.OpUpdate,
+				tpID.String():  rnode.OpDelete,
+				tp2ID.String(): rnode.OpCreate,
+				umID.String():  rnode.OpNothing,
+			},
+			wantSwaps: []Swap{{Old: tpID, New: tp2ID}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.UrlMaps().Insert(ctx, meta.GlobalKey("um"), &compute.UrlMap{})
+			mock.TargetHttpProxies().Insert(ctx, meta.GlobalKey("tp"), &compute.TargetHttpProxy{
+				Description: "old",
+				UrlMap:      umID.SelfLink(meta.VersionGA),
+			})
+			mock.GlobalForwardingRules().Insert(ctx, meta.GlobalKey("fr"), &compute.ForwardingRule{
+				Target: tpID.SelfLink(meta.VersionGA),
+			})
+
+			umm := b.N("um").UrlMap().Resource()
+			umr, _ := umm.Freeze()
+			tpm := b.N("tp").TargetHttpProxy().Resource()
+			tpm.Access(func(x *compute.TargetHttpProxy) {
+				x.Description = "new"
+				x.UrlMap = umID.SelfLink(meta.VersionGA)
+			})
+			tpr, _ := tpm.Freeze()
+			frm := b.N("fr").ForwardingRule().Resource()
+			frm.Access(func(x *compute.ForwardingRule) { x.Target = tpID.SelfLink(meta.VersionGA) })
+			frr, _ := frm.Freeze()
+
+			gr := rgraph.NewBuilder()
+			for _, nb := range []rnode.Builder{
+				urlmap.NewBuilderWithResource(umr),
+				targethttpproxy.NewBuilderWithResource(tpr),
+				forwardingrule.NewBuilderWithResource(frr),
+			} {
+				nb.SetOwnership(rnode.OwnershipManaged)
+				nb.SetState(rnode.NodeExists)
+				gr.Add(nb)
+			}
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+
+			res, err := Do(ctx, mock, want, tc.opts...)
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			gotOps := map[string]rnode.Operation{}
+			for _, n := range res.Want.All() {
+				gotOps[n.ID().String()] = n.Plan().Op()
+			}
+			if diff := cmp.Diff(gotOps, tc.wantOps); diff != "" {
+				t.Errorf("ops: -got,+want: %s", diff)
+			}
+			if diff := cmp.Diff(res.Swaps, tc.wantSwaps); diff != "" {
+				t.Errorf("Swaps: -got,+want: %s", diff)
+			}
+
+			ex, err := exec.NewSerialExecutor(mock, res.Actions)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			if _, err := ex.Run(ctx); err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			if len(tc.wantSwaps) == 0 {
+				return
+			}
+			// The ForwardingRule should point to the replacement.
+			fr, err := res.Want.Get(frID).Resource().(forwardingrule.ForwardingRule).ToGA()
+			if err != nil {
+				t.Fatalf("ToGA() = %v, want nil", err)
+			}
+			if wantTarget := tp2ID.SelfLink(meta.VersionGA); fr.Target != wantTarget {
+				t.Errorf("Target = %q, want %q", fr.Target, wantTarget)
+			}
+			if _, err := mock.TargetHttpProxies().Get(ctx, meta.GlobalKey("tp-2")); err != nil {
+				t.Errorf("TargetHttpProxies().Get(tp-2) = %v, want nil", err)
+			}
+			if _, err := mock.TargetHttpProxies().Get(ctx, meta.GlobalKey("tp")); err == nil {
+				t.Errorf("TargetHttpProxies().Get(tp) = nil, want err")
+			}
+		})
+	}
+}
+
+func TestTransactionalApply(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+	hcID := b.N("hc").HealthCheck().ID()
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &compute.HealthCheck{CheckIntervalSec: 5})
+	mock.MockBackendServices.InsertHook = func(context.Context, *meta.Key, *compute.BackendService, *cloud.MockBackendServices, ...cloud.Option) (bool, error) {
+		return true, errors.New("injected")
+	}
+
+	hcm := b.N("hc").HealthCheck().Resource()
+	hcm.Access(func(x *compute.HealthCheck) { x.CheckIntervalSec = 10 })
+	hcr, _ := hcm.Freeze()
+	bsm := b.N("bs").BackendService().Resource()
+	bsm.Access(func(x *compute.BackendService) { x.HealthChecks = []string{hcID.SelfLink(meta.VersionGA)} })
+	bsr, _ := bsm.Freeze()
+
+	gr := rgraph.NewBuilder()
+	for _, nb := range []rnode.Builder{
+		healthcheck.NewBuilderWithResource(hcr),
+		backendservice.NewBuilderWithResource(bsr),
+	} {
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		gr.Add(nb)
+	}
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	res, err := Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+
+	ex, err := exec.NewTransactionalExecutor(mock, res.Actions)
+	if err != nil {
+		t.Fatalf("NewTransactionalExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(ctx)
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if len(result.Compensated) != 1 || len(result.CompensationErrors) != 0 {
+		t.Errorf("Compensated = %v, CompensationErrors = %v; want 1 compensation, no errors", result.Compensated, result.CompensationErrors)
+	}
+	hc, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("hc"))
+	if err != nil {
+		t.Fatalf("HealthChecks().Get() = %v, want nil", err)
+	}
+	if hc.CheckIntervalSec != 5 {
+		t.Errorf("CheckIntervalSec = %d, want 5 (update was not reverted)", hc.CheckIntervalSec)
+	}
+}
+
+func TestTransactionalApplyForwardingRule(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+	frID := b.N("fr").ForwardingRule().ID()
+	fr2ID := b.N("fr2").ForwardingRule().ID()
+	tpID := b.N("tp").TargetHttpProxy().ID()
+	tp2ID := b.N("tp2").TargetHttpProxy().ID()
+	umID := b.N("um").UrlMap().ID()
+	bsID := b.N("bs").BackendService().ID()
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	mock.MockGlobalForwardingRules.SetTargetHook = cloudmock.SetTargetGlobalForwardingRuleHook
+	mock.MockGlobalForwardingRules.SetLabelsHook = func(ctx context.Context, key *meta.Key, req *compute.GlobalSetLabelsRequest, m *cloud.MockGlobalForwardingRules, _ ...cloud.Option) error {
+		fr, err := m.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		fr.Labels = req.Labels
+		return nil
+	}
+	mock.MockBackendServices.InsertHook = func(context.Context, *meta.Key, *compute.BackendService, *cloud.MockBackendServices, ...cloud.Option) (bool, error) {
+		return true, errors.New("injected")
+	}
+	mock.UrlMaps().Insert(ctx, meta.GlobalKey("um"), &compute.UrlMap{})
+	for _, name := range []string{"tp", "tp2"} {
+		mock.TargetHttpProxies().Insert(ctx, meta.GlobalKey(name), &compute.TargetHttpProxy{UrlMap: umID.SelfLink(meta.VersionGA)})
+	}
+	mock.GlobalForwardingRules().Insert(ctx, meta.GlobalKey("fr"), &compute.ForwardingRule{
+		Target: tpID.SelfLink(meta.VersionGA),
+		Labels: map[string]string{"a": "1"},
+	})
+
+	gr := rgraph.NewBuilder()
+	umr, _ := b.N("um").UrlMap().Resource().Freeze()
+	gr.Add(urlmap.NewBuilderWithResource(umr))
+	for _, name := range []string{"tp", "tp2"} {
+		tpm := b.N(name).TargetHttpProxy().Resource()
+		tpm.Access(func(x *compute.TargetHttpProxy) { x.UrlMap = umID.SelfLink(meta.VersionGA) })
+		tpr, _ := tpm.Freeze()
+		gr.Add(targethttpproxy.NewBuilderWithResource(tpr))
+	}
+	// fr is updated, fr2 is created.
+	for _, name := range []string{"fr", "fr2"} {
+		frm := b.N(name).ForwardingRule().Resource()
+		frm.Access(func(x *compute.ForwardingRule) {
+			x.Target = tp2ID.SelfLink(meta.VersionGA)
+			x.Labels = map[string]string{"a": "2"}
+		})
+		frr, _ := frm.Freeze()
+		gr.Add(forwardingrule.NewBuilderWithResource(frr))
+	}
+	// Creating bs fails.
+	bsr, _ := b.N("bs").BackendService().Resource().Freeze()
+	gr.Add(backendservice.NewBuilderWithResource(bsr))
+	for _, nb := range gr.All() {
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+	}
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	res, err := Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	for id, op := range map[*cloud.ResourceID]rnode.Operation{frID: rnode.OpUpdate, fr2ID: rnode.OpCreate} {
+		if got := res.Want.Get(id).Plan().Op(); got != op {
+			t.Fatalf("Op(%v) = %s, want %s", id, got, op)
+		}
+	}
+
+	ex, err := exec.NewTransactionalExecutor(mock, res.Actions,
+		// Run the failing Action after the ForwardingRule Actions.
+		exec.PriorityOption(func(a exec.Action) int {
+			if a.Metadata().Resource.Equal(bsID) {
+				return -1
+			}
+			return 0
+		}))
+	if err != nil {
+		t.Fatalf("NewTransactionalExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(ctx)
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if len(result.CompensationErrors) != 0 {
+		t.Errorf("CompensationErrors = %v, want none", result.CompensationErrors)
+	}
+	var gotCompensated []string
+	for _, a := range result.Compensated {
+		gotCompensated = append(gotCompensated, a.String())
+	}
+	sort.Strings(gotCompensated)
+	wantCompensated := []string{
+		"ForwardingRuleUpdateAction(" + frID.String() + ")",
+		"GenericDeleteAction(" + fr2ID.String() + ")",
+	}
+	if diff := cmp.Diff(gotCompensated, wantCompensated); diff != "" {
+		t.Errorf("Compensated: -got,+want: %s", diff)
+	}
+	fr, err := mock.GlobalForwardingRules().Get(ctx, meta.GlobalKey("fr"))
+	if err != nil {
+		t.Fatalf("GlobalForwardingRules().Get(fr) = %v, want nil", err)
+	}
+	if wantTarget := tpID.SelfLink(meta.VersionGA); fr.Target != wantTarget {
+		t.Errorf("Target = %q, want %q (update was not reverted)", fr.Target, wantTarget)
+	}
+	if diff := cmp.Diff(fr.Labels, map[string]string{"a": "1"}); diff != "" {
+		t.Errorf("Labels: -got,+want: %s (update was not reverted)", diff)
+	}
+	if _, err := mock.GlobalForwardingRules().Get(ctx, meta.GlobalKey("fr2")); err == nil {
+		t.Errorf("GlobalForwardingRules().Get(fr2) = nil, want err (create was not reverted)")
+	}
+}
+
+func TestTransactionalApplyDelete(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+	hcOldID := b.N("hc-old").HealthCheck().ID()
+	hcNewID := b.N("hc-new").HealthCheck().ID()
+	addrID := b.N("addr").Address().ID()
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	mock.MockBackendServices.UpdateHook = cloudmock.UpdateBackendServiceHook
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc-old"), &compute.HealthCheck{
+		Id:                123,
+		CreationTimestamp: "2023-01-01T00:00:00Z",
+		CheckIntervalSec:  5,
+	})
+	mock.BackendServices().Insert(ctx, meta.GlobalKey("bs"), &compute.BackendService{
+		HealthChecks: []string{hcOldID.SelfLink(meta.VersionGA)},
+	})
+	// Like the API, reject inserts with OutputOnly fields.
+	mock.MockHealthChecks.Validator = func(hc *compute.HealthCheck) error {
+		if hc.Id != 0 || hc.CreationTimestamp != "" || hc.SelfLink != "" {
+			return errors.New("OutputOnly field set")
+		}
+		return nil
+	}
+	mock.MockGlobalAddresses.InsertHook = func(context.Context, *meta.Key, *compute.Address, *cloud.MockGlobalAddresses, ...cloud.Option) (bool, error) {
+		return true, errors.New("injected")
+	}
+
+	gr := rgraph.NewBuilder()
+	hcm := b.N("hc-new").HealthCheck().Resource()
+	hcm.Access(func(x *compute.HealthCheck) { x.CheckIntervalSec = 10 })
+	hcr, _ := hcm.Freeze()
+	gr.Add(healthcheck.NewBuilderWithResource(hcr))
+	// bs is updated to use hc-new; hc-old is deleted.
+	bsm := b.N("bs").BackendService().Resource()
+	bsm.Access(func(x *compute.BackendService) { x.HealthChecks = []string{hcNewID.SelfLink(meta.VersionGA)} })
+	bsr, _ := bsm.Freeze()
+	gr.Add(backendservice.NewBuilderWithResource(bsr))
+	// Creating addr fails.
+	addrr, _ := b.N("addr").Address().Resource().Freeze()
+	gr.Add(address.NewBuilderWithResource(addrr))
+	for _, nb := range gr.All() {
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+	}
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	res, err := Do(ctx, mock, want, Transactional())
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if got := res.Want.Get(hcOldID).Plan().Op(); got != rnode.OpDelete {
+		t.Fatalf("Op(%v) = %s, want %s", hcOldID, got, rnode.OpDelete)
+	}
+
+	ex, err := exec.NewTransactionalExecutor(mock, res.Actions,
+		// Run the failing Action after the delete.
+		exec.PriorityOption(func(a exec.Action) int {
+			if a.Metadata().Resource.Equal(addrID) {
+				return -1
+			}
+			return 0
+		}))
+	if err != nil {
+		t.Fatalf("NewTransactionalExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(ctx)
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if len(result.CompensationErrors) != 0 {
+		t.Fatalf("CompensationErrors = %v, want none", result.CompensationErrors)
+	}
+	var compensatedDelete bool
+	for _, a := range result.Compensated {
+		if a.Metadata().Type == exec.ActionTypeCreate && a.Metadata().Resource.Equal(hcOldID) {
+			compensatedDelete = true
+		}
+	}
+	if !compensatedDelete {
+		t.Errorf("Compensated = %v, want the delete of %v to be compensated", result.Compensated, hcOldID)
+	}
+
+	hc, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("hc-old"))
+	if err != nil {
+		t.Fatalf("HealthChecks().Get(hc-old) = %v, want nil (delete was not reverted)", err)
+	}
+	if hc.CheckIntervalSec != 5 {
+		t.Errorf("CheckIntervalSec = %d, want 5", hc.CheckIntervalSec)
+	}
+	if _, err := mock.HealthChecks().Get(ctx, meta.GlobalKey("hc-new")); err == nil {
+		t.Errorf("HealthChecks().Get(hc-new) = nil, want err (create was not reverted)")
+	}
+	bs, err := mock.BackendServices().Get(ctx, meta.GlobalKey("bs"))
+	if err != nil {
+		t.Fatalf("BackendServices().Get() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(bs.HealthChecks, []string{hcOldID.SelfLink(meta.VersionGA)}); diff != "" {
+		t.Errorf("HealthChecks: -got,+want: %s (update was not reverted)", diff)
+	}
+}
+
+func TestResolveOnDemand(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+	frID := b.N("fr").ForwardingRule().ID()
+	tpID := b.N("tp").TargetHttpProxy().ID()
+	umID := b.N("um").UrlMap().ID()
+
+	for _, tc := range []struct {
+		name          string
+		description   string
+		opts          []Option
+		wantOps       map[string]rnode.Operation
+		wantUrlMapGet int
+	}{
+		{
+			name:        "no changes",
+			description: "old",
+			wantOps: map[string]rnode.Operation{
+				frID.String(): rnode.OpNothing,
+				tpID.String(): rnode.OpNothing,
+				umID.String(): rnode.OpNothing,
+			},
+			wantUrlMapGet: 1,
+		},
+		{
+			name:        "lazy node not needed",
+			description: "old",
+			opts:        []Option{ResolveOnDemand(umID)},
+			wantOps: map[string]rnode.Operation{
+				frID.String(): rnode.OpNothing,
+				tpID.String(): rnode.OpNothing,
+				umID.String(): rnode.OpNothing,
+			},
+		},
+		{
+			name:        "lazy node needed by referrer",
+			description: "new",
+			opts:        []Option{ResolveOnDemand(umID)},
+			wantOps: map[string]rnode.Operation{
+				frID.String(): rnode.OpRecreate,
+				tpID.String(): rnode.OpRecreate,
+				umID.String(): rnode.OpNothing,
+			},
+			wantUrlMapGet: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.UrlMaps().Insert(ctx, meta.GlobalKey("um"), &compute.UrlMap{})
+			mock.TargetHttpProxies().Insert(ctx, meta.GlobalKey("tp"), &compute.TargetHttpProxy{
+				Description: "old",
+				UrlMap:      umID.SelfLink(meta.VersionGA),
+			})
+			mock.GlobalForwardingRules().Insert(ctx, meta.GlobalKey("fr"), &compute.ForwardingRule{
+				Target: tpID.SelfLink(meta.VersionGA),
+			})
+			var urlMapGet int
+			mock.MockUrlMaps.GetHook = func(context.Context, *meta.Key, *cloud.MockUrlMaps, ...cloud.Option) (bool, *compute.UrlMap, error) {
+				urlMapGet++
+				return false, nil, nil
+			}
+
+			umm := b.N("um").UrlMap().Resource()
+			umr, _ := umm.Freeze()
+			tpm := b.N("tp").TargetHttpProxy().Resource()
+			tpm.Access(func(x *compute.TargetHttpProxy) {
+				x.Description = tc.description
+				x.UrlMap = umID.SelfLink(meta.VersionGA)
+			})
+			tpr, _ := tpm.Freeze()
+			frm := b.N("fr").ForwardingRule().Resource()
+			frm.Access(func(x *compute.ForwardingRule) { x.Target = tpID.SelfLink(meta.VersionGA) })
+			frr, _ := frm.Freeze()
+
+			gr := rgraph.NewBuilder()
+			for _, nb := range []rnode.Builder{
+				urlmap.NewBuilderWithResource(umr),
+				targethttpproxy.NewBuilderWithResource(tpr),
+				forwardingrule.NewBuilderWithResource(frr),
+			} {
+				nb.SetOwnership(rnode.OwnershipManaged)
+				nb.SetState(rnode.NodeExists)
+				gr.Add(nb)
+			}
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+
+			res, err := Do(ctx, mock, want, tc.opts...)
+			if err != nil {
+				t.Fatalf("Do() = %v, want nil", err)
+			}
+			gotOps := map[string]rnode.Operation{}
+			for _, n := range res.Want.All() {
+				gotOps[n.ID().String()] = n.Plan().Op()
+			}
+			if diff := cmp.Diff(gotOps, tc.wantOps); diff != "" {
+				t.Errorf("ops: -got,+want: %s", diff)
+			}
+			if urlMapGet != tc.wantUrlMapGet {
+				t.Errorf("UrlMaps().Get() called %d times, want %d", urlMapGet, tc.wantUrlMapGet)
+			}
+		})
+	}
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	if _, err := Do(ctx, mock, rgraph.NewBuilder().MustBuild(), ResolveOnDemand(umID)); err == nil {
+		t.Errorf("Do(ResolveOnDemand(%v)) = nil, want error for a node not in the graph", umID)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+	mock.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &compute.HealthCheck{CheckIntervalSec: 5})
+
+	hcm := b.N("hc").HealthCheck().Resource()
+	hcm.Access(func(x *compute.HealthCheck) { x.CheckIntervalSec = 10 })
+	hcr, _ := hcm.Freeze()
+	nb := healthcheck.NewBuilderWithResource(hcr)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	gr := rgraph.NewBuilder()
+	gr.Add(nb)
+	want, err := gr.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+
+	tp := tracerec.New()
+	res, err := Do(ctx, mock, want, TracerProvider(tp))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if !res.SpanContext.IsValid() {
+		t.Fatalf("res.SpanContext is not valid")
+	}
+	ex, err := exec.NewSerialExecutor(mock, res.Actions,
+		exec.TracerProviderOption(tp),
+		exec.TraceParentOption(res.SpanContext),
+	)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(ctx); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	spanCount := map[string]int{}
+	for _, s := range tp.Spans() {
+		spanCount[s.Name()]++
+		if s.SpanContext().TraceID() != res.SpanContext.TraceID() {
+			t.Errorf("span %q has TraceID %v, want %v", s.Name(), s.SpanContext().TraceID(), res.SpanContext.TraceID())
+		}
+		if !s.Ended() {
+			t.Errorf("span %q was not ended", s.Name())
+		}
+	}
+	wantCount := map[string]int{
+		"rgraph.Plan":           1,
+		"rgraph.Fetch":          1,
+		"rgraph.Diff":           1,
+		"rgraph.SerialExecutor": 1,
+		"rgraph.Action":         1,
+	}
+	if diff := cmp.Diff(spanCount, wantCount); diff != "" {
+		t.Errorf("spans: -got,+want: %s", diff)
+	}
+
+	diffSpan := tp.Find("rgraph.Diff")[0]
+	for _, a := range []struct {
+		key  attribute.Key
+		want string
+	}{
+		{exec.AttrResource, b.N("hc").HealthCheck().ID().String()},
+		{exec.AttrOp, string(rnode.OpUpdate)},
+	} {
+		if v, _ := diffSpan.Attr(a.key); v.AsString() != a.want {
+			t.Errorf("Diff span Attr(%s) = %q, want %q", a.key, v.AsString(), a.want)
+		}
+	}
+}
+
+func TestDeletionProtection(t *testing.T) {
+	const project = "proj"
+	ctx := context.Background()
+	b := all.ResourceBuilder{Project: project}
+	frID := b.N("fr").ForwardingRule().ID()
+	tpID := b.N("tp").TargetHttpProxy().ID()
+	umID := b.N("um").UrlMap().ID()
+	rename := func(id *cloud.ResourceID) *cloud.ResourceID {
+		return b.N(id.Key.Name + "-2").TargetHttpProxy().ID()
+	}
+
+	for _, tc := range []struct {
+		name      string
+		protected []*cloud.ResourceID
+		deleteFR  bool
+		opts      []Option
+		// wantErr are the protected Nodes in the error.
+		wantErr []ProtectedNode
+	}{
+		{
+			name:      "unchanged resource",
+			protected: []*cloud.ResourceID{umID},
+		},
+		{
+			name:      "recreate",
+			protected: []*cloud.ResourceID{tpID},
+			wantErr:   []ProtectedNode{{ID: tpID, Op: rnode.OpRecreate}},
+		},
+		{
+			name:      "propagated recreate",
+			protected: []*cloud.ResourceID{frID, umID},
+			wantErr:   []ProtectedNode{{ID: frID, Op: rnode.OpRecreate}},
+		},
+		{
+			name:      "delete",
+			protected: []*cloud.ResourceID{frID},
+			deleteFR:  true,
+			wantErr:   []ProtectedNode{{ID: frID, Op: rnode.OpDelete}},
+		},
+		{
+			name:      "create then swap",
+			protected: []*cloud.ResourceID{tpID},
+			opts:      []Option{CreateThenSwap(rename)},
+			wantErr:   []ProtectedNode{{ID: tpID, Op: rnode.OpDelete}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.UrlMaps().Insert(ctx, meta.GlobalKey("um"), &compute.UrlMap{})
+			mock.TargetHttpProxies().Insert(ctx, meta.GlobalKey("tp"), &compute.TargetHttpProxy{
+				Description: "old",
+				UrlMap:      umID.SelfLink(meta.VersionGA),
+			})
+			mock.GlobalForwardingRules().Insert(ctx, meta.GlobalKey("fr"), &compute.ForwardingRule{
+				Target: tpID.SelfLink(meta.VersionGA),
+			})
+
+			umm := b.N("um").UrlMap().Resource()
+			umr, _ := umm.Freeze()
+			tpm := b.N("tp").TargetHttpProxy().Resource()
+			tpm.Access(func(x *compute.TargetHttpProxy) {
+				x.Description = "new"
+				x.UrlMap = umID.SelfLink(meta.VersionGA)
+			})
+			tpr, _ := tpm.Freeze()
+			frm := b.N("fr").ForwardingRule().Resource()
+			frm.Access(func(x *compute.ForwardingRule) { x.Target = tpID.SelfLink(meta.VersionGA) })
+			frr, _ := frm.Freeze()
+
+			protected := map[cloud.ResourceMapKey]bool{}
+			for _, id := range tc.protected {
+				protected[id.MapKey()] = true
+			}
+			gr := rgraph.NewBuilder()
+			for _, nb := range []rnode.Builder{
+				urlmap.NewBuilderWithResource(umr),
+				targethttpproxy.NewBuilderWithResource(tpr),
+				forwardingrule.NewBuilderWithResource(frr),
+			} {
+				nb.SetOwnership(rnode.OwnershipManaged)
+				nb.SetState(rnode.NodeExists)
+				if tc.deleteFR && nb.ID().Equal(frID) {
+					nb.SetState(rnode.NodeDoesNotExist)
+				}
+				nb.SetDeletionProtected(protected[nb.ID().MapKey()])
+				gr.Add(nb)
+			}
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("Build() = %v, want nil", err)
+			}
+
+			_, err = Do(ctx, mock, want, tc.opts...)
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Do() = %v, want nil", err)
+				}
+				return
+			}
+			var dpErr *DeletionProtectedError
+			if !errors.As(err, &dpErr) {
+				t.Fatalf("Do() = %v, want *DeletionProtectedError", err)
+			}
+			for i := range dpErr.Nodes {
+				dpErr.Nodes[i].Why = ""
+			}
+			if diff := cmp.Diff(dpErr.Nodes, tc.wantErr); diff != "" {
+				t.Errorf("DeletionProtectedError.Nodes: -got,+want: %s", diff)
+			}
+		})
+	}
+}
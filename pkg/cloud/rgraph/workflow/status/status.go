@@ -0,0 +1,191 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status reports the per-Node status after the execution of a plan.
+// The status is in a form that can be copied into the status of a Kubernetes
+// resource by controllers.
+package status
+
+import (
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+)
+
+// State of a Node.
+type State string
+
+var (
+	// Synced means the resource matches the intended state.
+	Synced State = "Synced"
+	// Pending means the changes for the resource have not been applied
+	// (yet).
+	Pending State = "Pending"
+	// Error means an Action for the resource failed.
+	Error State = "Error"
+)
+
+// Reasons for the State. These are CamelCase to be used in the Reason of a
+// Kubernetes condition.
+const (
+	ReasonUpToDate    = "UpToDate"
+	ReasonApplied     = "Applied"
+	ReasonNotExecuted = "NotExecuted"
+	ReasonRolledBack  = "RolledBack"
+	ReasonActionError = "ActionError"
+)
+
+// NodeStatus is the status of a single Node.
+type NodeStatus struct {
+	// ID of the resource.
+	ID *cloud.ResourceID `json:"-"`
+	// Resource is the string form of the ID.
+	Resource string `json:"resource"`
+	// SelfLink of the resource.
+	SelfLink string `json:"selfLink"`
+	// State of the resource.
+	State State `json:"state"`
+	// Reason is a CamelCase reason for the State.
+	Reason string `json:"reason"`
+	// Message is a human readable message (e.g. the error).
+	Message string `json:"message,omitempty"`
+	// Operation is the last operation that was planned for the resource.
+	Operation rnode.Operation `json:"operation"`
+}
+
+// Report is the status of all of the Nodes in a plan.
+type Report struct {
+	// Nodes sorted by Resource.
+	Nodes []NodeStatus `json:"nodes"`
+}
+
+// Get the status for the id. Returns nil if the id is not in the Report.
+func (r *Report) Get(id *cloud.ResourceID) *NodeStatus {
+	for i := range r.Nodes {
+		if r.Nodes[i].ID.Equal(id) {
+			return &r.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// Synced returns true if all of the Nodes are Synced.
+func (r *Report) Synced() bool {
+	for _, n := range r.Nodes {
+		if n.State != Synced {
+			return false
+		}
+	}
+	return true
+}
+
+// Compute the status of the Nodes in the plan after the execution. execResult
+// may be nil if the plan was not executed. Actions are associated with a Node
+// using ActionMetadata.Resource.
+func Compute(planResult *plan.Result, execResult *exec.Result) *Report {
+	type actionState struct {
+		errs        []error
+		pending     bool
+		compensated bool
+	}
+	states := map[cloud.ResourceMapKey]*actionState{}
+	get := func(a exec.Action) *actionState {
+		r := a.Metadata().Resource
+		if r == nil {
+			return nil
+		}
+		s, ok := states[r.MapKey()]
+		if !ok {
+			s = &actionState{}
+			states[r.MapKey()] = s
+		}
+		return s
+	}
+
+	if execResult == nil {
+		for _, a := range planResult.Actions {
+			if s := get(a); s != nil {
+				s.pending = true
+			}
+		}
+	} else {
+		for _, a := range execResult.Pending {
+			if s := get(a); s != nil {
+				s.pending = true
+			}
+		}
+		for _, ae := range execResult.Errors {
+			if s := get(ae.Action); s != nil {
+				s.errs = append(s.errs, ae.Err)
+			}
+		}
+		for _, a := range execResult.Compensated {
+			if s := get(a); s != nil {
+				s.compensated = true
+			}
+		}
+		for _, ae := range execResult.CompensationErrors {
+			if s := get(ae.Action); s != nil {
+				s.errs = append(s.errs, ae.Err)
+			}
+		}
+	}
+
+	ret := &Report{}
+	for _, n := range planResult.Want.All() {
+		ns := NodeStatus{
+			ID:        n.ID(),
+			Resource:  n.ID().String(),
+			SelfLink:  n.ID().SelfLink(version(n)),
+			Operation: n.Plan().Op(),
+		}
+		s := states[n.ID().MapKey()]
+		switch {
+		case s != nil && len(s.errs) > 0:
+			ns.State = Error
+			ns.Reason = ReasonActionError
+			ns.Message = s.errs[0].Error()
+		case s != nil && s.compensated:
+			ns.State = Pending
+			ns.Reason = ReasonRolledBack
+			ns.Message = "Changes were rolled back due to errors in other Actions"
+		case s != nil && s.pending:
+			ns.State = Pending
+			ns.Reason = ReasonNotExecuted
+		case n.Plan().Op() == rnode.OpNothing:
+			ns.State = Synced
+			ns.Reason = ReasonUpToDate
+		default:
+			ns.State = Synced
+			ns.Reason = ReasonApplied
+		}
+		ret.Nodes = append(ret.Nodes, ns)
+	}
+	sort.Slice(ret.Nodes, func(i, j int) bool { return ret.Nodes[i].Resource < ret.Nodes[j].Resource })
+
+	return ret
+}
+
+func version(n rnode.Node) meta.Version {
+	if r := n.Resource(); r != nil {
+		return r.Version()
+	}
+	return meta.VersionGA
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/networkendpointgroup"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/lb"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestCompute(t *testing.T) {
+	const project = "proj"
+	negID := networkendpointgroup.ID(project, meta.ZonalKey("neg", "us-central1-b"))
+	spec := &lb.Spec{
+		Project:  project,
+		Name:     "lb",
+		Backends: []lb.Backend{{Group: negID}},
+	}
+	ids := spec.IDs()
+
+	type result struct {
+		State  State
+		Reason string
+	}
+	for _, tc := range []struct {
+		name       string
+		execute    bool
+		failBS     bool
+		wantSynced bool
+		want       map[string]result
+	}{
+		{
+			name: "not executed",
+			want: map[string]result{
+				ids.Address.String():        {Pending, ReasonNotExecuted},
+				ids.BackendService.String(): {Pending, ReasonNotExecuted},
+				ids.ForwardingRule.String(): {Pending, ReasonNotExecuted},
+				ids.HealthCheck.String():    {Pending, ReasonNotExecuted},
+				negID.String():              {Synced, ReasonUpToDate},
+				ids.TargetProxy.String():    {Pending, ReasonNotExecuted},
+				ids.UrlMap.String():         {Pending, ReasonNotExecuted},
+			},
+		},
+		{
+			name:       "executed",
+			execute:    true,
+			wantSynced: true,
+			want: map[string]result{
+				ids.Address.String():        {Synced, ReasonApplied},
+				ids.BackendService.String(): {Synced, ReasonApplied},
+				ids.ForwardingRule.String(): {Synced, ReasonApplied},
+				ids.HealthCheck.String():    {Synced, ReasonApplied},
+				negID.String():              {Synced, ReasonUpToDate},
+				ids.TargetProxy.String():    {Synced, ReasonApplied},
+				ids.UrlMap.String():         {Synced, ReasonApplied},
+			},
+		},
+		{
+			name:    "error",
+			execute: true,
+			failBS:  true,
+			want: map[string]result{
+				ids.Address.String():        {Synced, ReasonApplied},
+				ids.BackendService.String(): {Error, ReasonActionError},
+				ids.ForwardingRule.String(): {Pending, ReasonNotExecuted},
+				ids.HealthCheck.String():    {Synced, ReasonApplied},
+				negID.String():              {Synced, ReasonUpToDate},
+				ids.TargetProxy.String():    {Pending, ReasonNotExecuted},
+				ids.UrlMap.String():         {Pending, ReasonNotExecuted},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: project})
+			mock.NetworkEndpointGroups().Insert(ctx, negID.Key, &compute.NetworkEndpointGroup{})
+			if tc.failBS {
+				mock.MockBackendServices.InsertHook = func(context.Context, *meta.Key, *compute.BackendService, *cloud.MockBackendServices, ...cloud.Option) (bool, error) {
+					return true, errors.New("injected")
+				}
+			}
+
+			gr, err := lb.Build(spec)
+			if err != nil {
+				t.Fatalf("lb.Build() = %v, want nil", err)
+			}
+			want, err := gr.Build()
+			if err != nil {
+				t.Fatalf("gr.Build() = %v, want nil", err)
+			}
+			planResult, err := plan.Do(ctx, mock, want)
+			if err != nil {
+				t.Fatalf("plan.Do() = %v, want nil", err)
+			}
+			var execResult *exec.Result
+			if tc.execute {
+				ex, err := exec.NewParallelExecutor(mock, planResult.Actions)
+				if err != nil {
+					t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+				}
+				execResult, _ = ex.Run(ctx)
+			}
+
+			report := Compute(planResult, execResult)
+			got := map[string]result{}
+			for _, n := range report.Nodes {
+				got[n.Resource] = result{n.State, n.Reason}
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("Compute(): -got,+want: %s", diff)
+			}
+			if report.Synced() != tc.wantSynced {
+				t.Errorf("Synced() = %t, want %t", report.Synced(), tc.wantSynced)
+			}
+			bs := report.Get(ids.BackendService)
+			if bs == nil {
+				t.Fatalf("Get(%v) = nil", ids.BackendService)
+			}
+			if wantLink := ids.BackendService.SelfLink(meta.VersionGA); bs.SelfLink != wantLink {
+				t.Errorf("SelfLink = %q, want %q", bs.SelfLink, wantLink)
+			}
+			if tc.failBS && bs.Message == "" {
+				t.Errorf("Message is empty, want error")
+			}
+		})
+	}
+}
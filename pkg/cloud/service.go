@@ -20,15 +20,22 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	ga "google.golang.org/api/compute/v1"
+	networksecurityga "google.golang.org/api/networksecurity/v1"
+	networksecuritybeta "google.golang.org/api/networksecurity/v1beta1"
 	networkservicesga "google.golang.org/api/networkservices/v1"
 	networkservicesbeta "google.golang.org/api/networkservices/v1beta1"
 	"google.golang.org/api/option"
 	"k8s.io/klog/v2"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
 // Service is the top-level adapter for all of the different compute API
@@ -39,45 +46,178 @@ type Service struct {
 	Beta                *beta.Service
 	NetworkServicesGA   *networkservicesga.ProjectsLocationsService
 	NetworkServicesBeta *networkservicesbeta.ProjectsLocationsService
+	NetworkSecurityGA   *networksecurityga.ProjectsLocationsService
+	NetworkSecurityBeta *networksecuritybeta.ProjectsLocationsService
 	ProjectRouter       ProjectRouter
 	RateLimiter         RateLimiter
+	// RetryPolicy for the calls to the API. If nil, calls are not retried.
+	RetryPolicy *RetryPolicy
+	// CallTimeouts are the default timeouts for the calls to the API. If
+	// nil, only the deadline of the context passed to the call applies.
+	CallTimeouts *CallTimeouts
+	// TracerProvider is used to emit OpenTelemetry spans for the calls to
+	// the API. The default is the global TracerProvider
+	// (otel.GetTracerProvider()).
+	TracerProvider trace.TracerProvider
+	// Metrics receives measurements for the calls to the API. If nil, no
+	// metrics are recorded.
+	Metrics Metrics
+	// Observers are called before and after each call to the API (see
+	// ServiceObserver).
+	Observers []ServiceObserver
+	// Interceptor is called before and after each request to the API made
+	// with the HTTP client given to NewService. If nil, no Interceptor is
+	// called.
+	Interceptor Interceptor
+	// UserProject is sent in the X-Goog-User-Project header of the requests
+	// made with the HTTP client given to NewService, so the API usage is
+	// billed and counted against the quota of this project (e.g. the host
+	// project of a shared VPC). If empty, no header is sent. See also the
+	// UserProject Option.
+	UserProject string
+	// RegionalEndpoints routes the requests for regional and zonal
+	// resources to regional endpoints. If nil, all requests are sent to the
+	// endpoints of the API clients.
+	RegionalEndpoints *RegionalEndpoints
+	// OperationProgressHook is called after each poll of a long running
+	// operation, e.g. to log the operations that take a long time. If nil,
+	// no hook is called.
+	OperationProgressHook func(ctx context.Context, p *OperationProgress)
+	// OperationPollPolicy configures the delays between the polls of long
+	// running operations. If nil, operations are polled again as soon as
+	// the RateLimiter allows.
+	OperationPollPolicy *OperationPollPolicy
+	// ReadCache caches the results of the read calls made with the HTTP
+	// client given to NewService. If nil, results are not cached.
+	ReadCache *ReadCache
+}
+
+// OperationProgress is the state of a long running operation after it was
+// polled by WaitForCompletion.
+type OperationProgress struct {
+	// URL of the operation (see OperationObserver).
+	URL string
+	// Status of the operation, e.g. "PENDING", "RUNNING" or "DONE".
+	Status string
+	// Percent is the progress of the operation reported by the API, between
+	// 0 and 100. It is not necessarily monotonic and some operations do not
+	// report a progress.
+	Percent int
+	// Elapsed time since the start of the wait.
+	Elapsed time.Duration
+	// PollCount is the number of times the operation was polled.
+	PollCount int
+}
+
+// ServiceOption configures the Service returned by NewService.
+type ServiceOption func(*serviceConfig)
+
+type serviceConfig struct {
+	endpoints      Endpoints
+	universeDomain string
+}
+
+// Endpoints override the base paths of the APIs, e.g. to target an
+// emulator, a test proxy or a private endpoint. The default endpoint is used
+// for the empty fields.
+type Endpoints struct {
+	// Compute is the base path of the GA compute API, e.g.
+	// "https://compute.googleapis.com/compute/v1/".
+	Compute string
+	// ComputeAlpha is the base path of the alpha compute API, e.g.
+	// "https://compute.googleapis.com/compute/alpha/".
+	ComputeAlpha string
+	// ComputeBeta is the base path of the beta compute API, e.g.
+	// "https://compute.googleapis.com/compute/beta/".
+	ComputeBeta string
+	// NetworkServices is the base path of the GA and beta networkservices
+	// APIs, e.g. "https://networkservices.googleapis.com/". The version is
+	// part of the request paths.
+	NetworkServices string
+	// NetworkSecurity is the base path of the GA and beta networksecurity
+	// APIs, e.g. "https://networksecurity.googleapis.com/".
+	NetworkSecurity string
+}
+
+// WithEndpoints overrides the endpoints of the APIs.
+func WithEndpoints(e Endpoints) ServiceOption {
+	return func(c *serviceConfig) { c.endpoints = e }
+}
+
+// WithUniverseDomain sets the universe domain of the APIs, e.g. for Trusted
+// Partner Cloud environments. The default is the domain set by
+// SetUniverseDomain ("googleapis.com"). Endpoints set with WithEndpoints take
+// precedence.
+func WithUniverseDomain(universe string) ServiceOption {
+	return func(c *serviceConfig) { c.universeDomain = universe }
+}
+
+// clientOptions returns the options for the API clients with the given
+// endpoint ("" for the default).
+func clientOptions(client *http.Client, config *serviceConfig, endpoint string) []option.ClientOption {
+	ret := []option.ClientOption{option.WithHTTPClient(client)}
+	if config.universeDomain != defaultUniverseDomain {
+		ret = append(ret, option.WithUniverseDomain(config.universeDomain))
+	}
+	if endpoint != "" {
+		ret = append(ret, option.WithEndpoint(endpoint))
+	}
+	return ret
 }
 
 // NewService returns a new Service instance initialized with from an HTTP
 // client to the API endpoints.
-func NewService(ctx context.Context, client *http.Client, pr ProjectRouter, rl RateLimiter) (*Service, error) {
-	alpha, err := alpha.NewService(ctx, option.WithHTTPClient(client))
+func NewService(ctx context.Context, client *http.Client, pr ProjectRouter, rl RateLimiter, opts ...ServiceOption) (*Service, error) {
+	config := serviceConfig{universeDomain: universeDomain}
+	for _, o := range opts {
+		o(&config)
+	}
+	svc := &Service{
+		ProjectRouter: pr,
+		RateLimiter:   rl,
+	}
+	client = interceptClient(svc, client)
+	ep := config.endpoints
+
+	alpha, err := alpha.NewService(ctx, clientOptions(client, &config, ep.ComputeAlpha)...)
 	if err != nil {
 		return nil, err
 	}
-	beta, err := beta.NewService(ctx, option.WithHTTPClient(client))
+	beta, err := beta.NewService(ctx, clientOptions(client, &config, ep.ComputeBeta)...)
 	if err != nil {
 		return nil, err
 	}
-	ga, err := ga.NewService(ctx, option.WithHTTPClient(client))
+	ga, err := ga.NewService(ctx, clientOptions(client, &config, ep.Compute)...)
 	if err != nil {
 		return nil, err
 	}
 
-	nsGA, err := networkservicesga.NewService(ctx, option.WithHTTPClient(client))
+	nsGA, err := networkservicesga.NewService(ctx, clientOptions(client, &config, ep.NetworkServices)...)
 	if err != nil {
 		return nil, err
 	}
-	nsBeta, err := networkservicesbeta.NewService(ctx, option.WithHTTPClient(client))
+	nsBeta, err := networkservicesbeta.NewService(ctx, clientOptions(client, &config, ep.NetworkServices)...)
 	if err != nil {
 		return nil, err
 	}
 
-	svc := &Service{
-		GA:                  ga,
-		Alpha:               alpha,
-		Beta:                beta,
-		NetworkServicesGA:   nsGA.Projects.Locations,
-		NetworkServicesBeta: nsBeta.Projects.Locations,
-		ProjectRouter:       pr,
-		RateLimiter:         rl,
+	nsecGA, err := networksecurityga.NewService(ctx, clientOptions(client, &config, ep.NetworkSecurity)...)
+	if err != nil {
+		return nil, err
+	}
+	nsecBeta, err := networksecuritybeta.NewService(ctx, clientOptions(client, &config, ep.NetworkSecurity)...)
+	if err != nil {
+		return nil, err
 	}
 
+	svc.GA = ga
+	svc.Alpha = alpha
+	svc.Beta = beta
+	svc.NetworkServicesGA = nsGA.Projects.Locations
+	svc.NetworkServicesBeta = nsBeta.Projects.Locations
+	svc.NetworkSecurityGA = nsecGA.Projects.Locations
+	svc.NetworkSecurityBeta = nsecBeta.Projects.Locations
+
 	return svc, nil
 }
 
@@ -134,6 +274,29 @@ func (s *Service) wrapOperation(anyOp any) (operation, error) {
 			projectID: result.projectID,
 			key:       result.key,
 		}, nil
+	case *networksecurityga.Operation:
+		result, err := parseNetworkServiceOpURL(o.Name)
+		if err != nil {
+			return nil, fmt.Errorf("wrapOperation: %w", err)
+		}
+		return &networkServicesOperation{
+			s:         s,
+			apiGroup:  meta.APIGroupNetworkSecurity,
+			projectID: result.projectID,
+			key:       result.key,
+		}, nil
+	case *networksecuritybeta.Operation:
+		result, err := parseNetworkServiceOpURL(o.Name)
+		if err != nil {
+			return nil, fmt.Errorf("wrapOperation: %w", err)
+		}
+		// Reuse the GA operation stream for Beta.
+		return &networkServicesOperation{
+			s:         s,
+			apiGroup:  meta.APIGroupNetworkSecurity,
+			projectID: result.projectID,
+			key:       result.key,
+		}, nil
 	default:
 		return nil, fmt.Errorf("invalid type %T", anyOp)
 	}
@@ -148,17 +311,152 @@ func (s *Service) WaitForCompletion(ctx context.Context, genericOp interface{})
 		klog.Errorf("wrapOperation(%+v) error: %v", genericOp, err)
 		return err
 	}
+	url := operationURL(genericOp)
+	operationObserverStarted(ctx, url)
+
+	start := time.Now()
+	ctx, span := s.tracer().Start(ctx, "cloud.WaitForCompletion", trace.WithAttributes(AttrOpURL.String(url)))
+	defer span.End()
+	err = s.pollOperation(ctx, op, url)
+	recordSpanError(ctx, err)
+	s.observeOperationWait(ctx, start, err)
+	return err
+}
 
-	return s.pollOperation(ctx, op)
+// waitForCompletion waits for the completion of op unless the call was made
+// with the DetachOperation option, in which case the URL of the operation is
+// returned to the caller.
+func (s *Service) waitForCompletion(ctx context.Context, op any, opts allOptions) error {
+	if opts.detachedURL == nil {
+		return s.WaitForCompletion(ctx, op)
+	}
+	url := operationURL(op)
+	if url == "" {
+		return fmt.Errorf("invalid operation %T", op)
+	}
+	operationObserverStarted(ctx, url)
+	*opts.detachedURL = url
+	klog.V(4).Infof("waitForCompletion(%v, %v): detached", ctx, url)
+	return nil
+}
+
+// ResumeOperation waits for the completion of the long running operation
+// identified by url, e.g. an operation that was started by a call with the
+// DetachOperation option. url is the SelfLink of a compute Operation, the
+// name of a Network Services Operation or the full resource name of a
+// Network Security Operation (see OperationObserver).
+func (s *Service) ResumeOperation(ctx context.Context, url string) error {
+	op, err := operationFromURL(url)
+	if err != nil {
+		return err
+	}
+	return s.WaitForCompletion(ctx, op)
+}
+
+// operationFromURL returns an Operation of the API version of url.
+func operationFromURL(url string) (any, error) {
+	if m := networkSecurityOpRegex.FindStringSubmatch(url); m != nil {
+		if _, err := parseNetworkServiceOpURL(m[1]); err != nil {
+			return nil, err
+		}
+		return &networksecurityga.Operation{Name: m[1]}, nil
+	}
+	if strings.HasPrefix(url, "projects/") && strings.Contains(url, "/locations/") {
+		if _, err := parseNetworkServiceOpURL(url); err != nil {
+			return nil, err
+		}
+		return &networkservicesga.Operation{Name: url}, nil
+	}
+	r, err := ParseResourceURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if r.Resource != "operations" || r.Key == nil {
+		return nil, fmt.Errorf("%q is not an operation URL", url)
+	}
+	var ver string
+	if m := apiGroupRegex.FindStringSubmatch(url); m != nil {
+		ver = m[3]
+	}
+	switch ver {
+	case "alpha":
+		return &alpha.Operation{SelfLink: url}, nil
+	case "beta":
+		return &beta.Operation{SelfLink: url}, nil
+	default:
+		return &ga.Operation{SelfLink: url}, nil
+	}
+}
+
+// WaitForOperations waits for the completion of a set of long running
+// operations (see WaitForCompletion) and returns the result of each of the
+// operations, in the same order as ops. This is used after making a lot of
+// mutations without waiting, e.g. with the API clients of the Service:
+//
+//	errs := svc.WaitForOperations(ctx, op1, op2, op3)
+//
+// The operations are polled concurrently and the polls share the RateLimiter
+// of the Service, which bounds the rate of the polls.
+func (s *Service) WaitForOperations(ctx context.Context, ops ...any) []error {
+	res := Batch(ctx, len(ops), ops, func(ctx context.Context, op any) (struct{}, error) {
+		return struct{}{}, s.WaitForCompletion(ctx, op)
+	})
+	ret := make([]error, len(res))
+	for i, r := range res {
+		ret[i] = r.Err
+	}
+	return ret
+}
+
+// operationURL returns the URL identifying the operation.
+func operationURL(anyOp any) string {
+	switch o := anyOp.(type) {
+	case *ga.Operation:
+		return o.SelfLink
+	case *alpha.Operation:
+		return o.SelfLink
+	case *beta.Operation:
+		return o.SelfLink
+	case *networkservicesga.Operation:
+		return o.Name
+	case *networkservicesbeta.Operation:
+		return o.Name
+	case *networksecurityga.Operation:
+		return networkSecurityOpURL(o.Name)
+	case *networksecuritybeta.Operation:
+		return networkSecurityOpURL(o.Name)
+	}
+	return ""
+}
+
+// networkSecurityOpRegex matches the full resource name of a Network
+// Security Operation. The operations of the networksecurity and
+// networkservices APIs have the same names, so the networksecurity ones are
+// identified by their full resource name.
+var networkSecurityOpRegex = regexp.MustCompile(`^//networksecurity\.[^/]+/(projects/.*)$`)
+
+// networkSecurityOpURL returns the full resource name of the Network
+// Security Operation name.
+func networkSecurityOpURL(name string) string {
+	return fmt.Sprintf("//%s.%s/%s", meta.APIGroupNetworkSecurity, universeDomain, name)
 }
 
 // pollOperation calls operations.isDone until the function comes back true or context is Done.
 // If an error occurs retrieving the operation, the loop will continue until the context is done.
 // This is to prevent a transient error from bubbling up to controller-level logic.
-func (s *Service) pollOperation(ctx context.Context, op operation) error {
+// url identifies op for the OperationProgressHook.
+func (s *Service) pollOperation(ctx context.Context, op operation, url string) error {
 	start := time.Now()
 	var pollCount int
 	for {
+		if d := s.OperationPollPolicy.delay(pollCount); d > 0 {
+			t := time.NewTimer(d)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+			case <-t.C:
+			}
+		}
 		// Check if context has been cancelled. Note that ctx.Done() must be checked before
 		// returning ctx.Err().
 		select {
@@ -172,7 +470,18 @@ func (s *Service) pollOperation(ctx context.Context, op operation) error {
 		pollCount++
 		klog.V(5).Infof("op.isDone(%v) waiting; op = %v, poll count = %d (%v elapsed)", ctx, op, pollCount, time.Since(start))
 		s.RateLimiter.Accept(ctx, op.rateLimitKey())
-		switch done, err := op.isDone(ctx); {
+		done, err := op.isDone(ctx)
+		if err == nil && s.OperationProgressHook != nil {
+			status, percent := op.progress()
+			s.OperationProgressHook(ctx, &OperationProgress{
+				URL:       url,
+				Status:    status,
+				Percent:   percent,
+				Elapsed:   time.Since(start),
+				PollCount: pollCount,
+			})
+		}
+		switch {
 		case err != nil:
 			klog.V(5).Infof("op.isDone(%v) error; op = %v, poll count = %d, err = %v, retrying (%v elapsed)", ctx, op, pollCount, err, time.Since(start))
 			s.RateLimiter.Observe(ctx, err, op.rateLimitKey())
@@ -19,12 +19,20 @@ package cloud
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	networksecurity "google.golang.org/api/networksecurity/v1"
+	networksecuritybeta "google.golang.org/api/networksecurity/v1beta1"
 	"google.golang.org/api/networkservices/v1"
 	networkservicesbeta "google.golang.org/api/networkservices/v1beta1"
 )
@@ -74,7 +82,7 @@ func TestPollOperation(t *testing.T) {
 			if test.cancel {
 				cfn()
 			}
-			if gotErr := s.pollOperation(ctx, test.op); gotErr != test.wantErr {
+			if gotErr := s.pollOperation(ctx, test.op, ""); gotErr != test.wantErr {
 				t.Errorf("pollOperation: got %v, want %v", gotErr, test.wantErr)
 			}
 			if test.op.attemptsRemaining != test.wantRemainingAttempts {
@@ -106,6 +114,36 @@ func (f *fakeOperation) rateLimitKey() *RateLimitKey {
 	return nil
 }
 
+func (f *fakeOperation) progress() (string, int) {
+	if f.attemptsRemaining <= 0 {
+		return operationStatusDone, 100
+	}
+	return "RUNNING", 50
+}
+
+func TestOperationProgressHook(t *testing.T) {
+	t.Parallel()
+
+	var got []OperationProgress
+	s := Service{
+		RateLimiter: &NopRateLimiter{},
+		OperationProgressHook: func(ctx context.Context, p *OperationProgress) {
+			p.Elapsed = 0
+			got = append(got, *p)
+		},
+	}
+	if err := s.pollOperation(context.Background(), &fakeOperation{attemptsRemaining: 2}, "op-url"); err != nil {
+		t.Fatalf("pollOperation() = %v", err)
+	}
+	want := []OperationProgress{
+		{URL: "op-url", Status: "RUNNING", Percent: 50, PollCount: 1},
+		{URL: "op-url", Status: operationStatusDone, Percent: 100, PollCount: 2},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OperationProgressHook: diff -got,+want: %s", diff)
+	}
+}
+
 func TestWrapOperation(t *testing.T) {
 	t.Parallel()
 
@@ -165,6 +203,18 @@ func TestWrapOperation(t *testing.T) {
 			},
 			want: "nsga",
 		},
+		{
+			in: &networksecurity.Operation{
+				Name: "projects/my-project/locations/global/operations/operation-1234",
+			},
+			want: "nsecga",
+		},
+		{
+			in: &networksecuritybeta.Operation{
+				Name: "projects/my-project/locations/global/operations/operation-1234",
+			},
+			want: "nsecga",
+		},
 		{
 			in:      struct{}{},
 			wantErr: true,
@@ -189,6 +239,9 @@ func TestWrapOperation(t *testing.T) {
 				gotType = "beta"
 			case *networkServicesOperation:
 				gotType = "nsga"
+				if op.(*networkServicesOperation).apiGroup == meta.APIGroupNetworkSecurity {
+					gotType = "nsecga"
+				}
 			default:
 				gotType = "invalid"
 			}
@@ -198,3 +251,244 @@ func TestWrapOperation(t *testing.T) {
 		})
 	}
 }
+
+func TestWaitForOperations(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/proj/global/operations/op-ok/wait":
+			fmt.Fprint(w, `{"status": "DONE"}`)
+		case "/projects/proj/global/operations/op-failed/wait":
+			fmt.Fprint(w, `{"status": "DONE", "httpErrorStatusCode": 400, "error": {"errors": [{"code": "BAD", "message": "failed"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+
+	opURL := func(name string) *ga.Operation {
+		return &ga.Operation{SelfLink: "https://www.googleapis.com/compute/v1/projects/proj/global/operations/" + name}
+	}
+	errs := s.WaitForOperations(context.Background(), opURL("op-ok"), opURL("op-failed"), "invalid")
+	if len(errs) != 3 {
+		t.Fatalf("len(WaitForOperations()) = %d, want 3", len(errs))
+	}
+	if errs[0] != nil {
+		t.Errorf("errs[0] = %v, want nil", errs[0])
+	}
+	var gerr *googleapi.Error
+	if !errors.As(errs[1], &gerr) || gerr.Code != http.StatusBadRequest {
+		t.Errorf("errs[1] = %v, want code %d", errs[1], http.StatusBadRequest)
+	}
+	if errs[2] == nil {
+		t.Errorf("errs[2] = nil, want error")
+	}
+}
+
+func TestWithEndpoints(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	s, err := NewService(ctx, srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{}, WithEndpoints(Endpoints{
+		Compute:         srv.URL + "/compute/",
+		ComputeBeta:     srv.URL + "/beta/",
+		NetworkServices: srv.URL + "/ns/",
+		NetworkSecurity: srv.URL + "/nsec/",
+	}))
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	if s.Alpha.BasePath != "https://compute.googleapis.com/compute/alpha/" {
+		t.Errorf("Alpha.BasePath = %q, want the default", s.Alpha.BasePath)
+	}
+
+	g := NewGCE(s)
+	key := meta.GlobalKey("x")
+	if _, err := g.GlobalAddresses().Get(ctx, key); err != nil {
+		t.Errorf("GlobalAddresses().Get() = %v", err)
+	}
+	if _, err := g.BetaGlobalAddresses().Get(ctx, key); err != nil {
+		t.Errorf("BetaGlobalAddresses().Get() = %v", err)
+	}
+	if _, err := g.TcpRoutes().Get(ctx, key); err != nil {
+		t.Errorf("TcpRoutes().Get() = %v", err)
+	}
+	if _, err := g.BetaServerTlsPolicies().Get(ctx, key); err != nil {
+		t.Errorf("BetaServerTlsPolicies().Get() = %v", err)
+	}
+	want := []string{
+		"/compute/projects/proj/global/addresses/x",
+		"/beta/projects/proj/global/addresses/x",
+		"/ns/v1/projects/proj/locations/global/tcpRoutes/x",
+		"/nsec/v1beta1/projects/proj/locations/global/serverTlsPolicies/x",
+	}
+	if diff := cmp.Diff(paths, want); diff != "" {
+		t.Errorf("paths: diff -got,+want: %s", diff)
+	}
+}
+
+func TestWithUniverseDomain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, err := NewService(ctx, http.DefaultClient, &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{}, WithUniverseDomain("apis-tpc.goog"))
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"GA", s.GA.BasePath, "https://compute.apis-tpc.goog/compute/v1/"},
+		{"Alpha", s.Alpha.BasePath, "https://compute.apis-tpc.goog/compute/alpha/"},
+		{"Beta", s.Beta.BasePath, "https://compute.apis-tpc.goog/compute/beta/"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s.BasePath = %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestDetachOperation(t *testing.T) {
+	t.Parallel()
+
+	const opURL = "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/projects/proj/global/addresses":
+			fmt.Fprintf(w, `{"status": "RUNNING", "selfLink": %q}`, opURL)
+		case "/projects/proj/global/operations/op-1/wait":
+			fmt.Fprint(w, `{"status": "DONE"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	ctx := context.Background()
+
+	var url string
+	if err := NewGCE(s).GlobalAddresses().Insert(ctx, meta.GlobalKey("addr"), &ga.Address{}, DetachOperation(&url)); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if url != opURL {
+		t.Errorf("url = %q, want %q", url, opURL)
+	}
+	if diff := cmp.Diff(paths, []string{"POST /projects/proj/global/addresses"}); diff != "" {
+		t.Errorf("paths after Insert(): diff -got,+want: %s", diff)
+	}
+
+	if err := s.ResumeOperation(ctx, url); err != nil {
+		t.Fatalf("ResumeOperation() = %v", err)
+	}
+	if diff := cmp.Diff(paths[1:], []string{"POST /projects/proj/global/operations/op-1/wait"}); diff != "" {
+		t.Errorf("paths after ResumeOperation(): diff -got,+want: %s", diff)
+	}
+}
+
+func TestNetworkSecurityOperation(t *testing.T) {
+	t.Parallel()
+
+	const opName = "projects/proj/locations/global/operations/op-1"
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/projects/proj/locations/global/serverTlsPolicies":
+			fmt.Fprintf(w, `{"name": %q}`, opName)
+		case "/v1/" + opName:
+			fmt.Fprintf(w, `{"name": %q, "done": true}`, opName)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	s, err := NewService(ctx, srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{}, WithEndpoints(Endpoints{
+		NetworkSecurity: srv.URL + "/",
+	}))
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+
+	var url string
+	if err := NewGCE(s).ServerTlsPolicies().Insert(ctx, meta.GlobalKey("policy"), &networksecurity.ServerTlsPolicy{}, DetachOperation(&url)); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if want := "//networksecurity.googleapis.com/" + opName; url != want {
+		t.Errorf("url = %q, want %q", url, want)
+	}
+	if err := s.ResumeOperation(ctx, url); err != nil {
+		t.Fatalf("ResumeOperation() = %v", err)
+	}
+	want := []string{
+		"POST /v1/projects/proj/locations/global/serverTlsPolicies",
+		"GET /v1/" + opName,
+	}
+	if diff := cmp.Diff(paths, want); diff != "" {
+		t.Errorf("paths: diff -got,+want: %s", diff)
+	}
+}
+
+func TestOperationFromURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://www.googleapis.com/compute/v1/projects/p/zones/z/operations/op", want: "*compute.Operation"},
+		{url: "https://www.googleapis.com/compute/alpha/projects/p/regions/r/operations/op", want: "*compute.Operation (alpha)"},
+		{url: "https://www.googleapis.com/compute/beta/projects/p/global/operations/op", want: "*compute.Operation (beta)"},
+		{url: "projects/p/global/operations/op", want: "*compute.Operation"},
+		{url: "projects/p/locations/global/operations/op", want: "*networkservices.Operation"},
+		{url: "//networksecurity.googleapis.com/projects/p/locations/global/operations/op", want: "*networksecurity.Operation"},
+		{url: "//networksecurity.googleapis.com/projects/p/locations/us-central1/operations/op", wantErr: true},
+		{url: "https://www.googleapis.com/compute/v1/projects/p/global/addresses/a", wantErr: true},
+		{url: "projects/p/locations/us-central1/operations/op", wantErr: true},
+		{url: "invalid", wantErr: true},
+	} {
+		op, err := operationFromURL(tc.url)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("operationFromURL(%q) = %v, want error %t", tc.url, err, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		got := fmt.Sprintf("%T", op)
+		switch op.(type) {
+		case *alpha.Operation:
+			got += " (alpha)"
+		case *beta.Operation:
+			got += " (beta)"
+		}
+		if got != tc.want {
+			t.Errorf("operationFromURL(%q) = %s, want %s", tc.url, got, tc.want)
+		}
+	}
+}
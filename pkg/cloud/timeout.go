@@ -0,0 +1,102 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"time"
+)
+
+// CallTimeouts configures the default timeout for calls to the API by service
+// and operation (see CallContextKey). Set Service.CallTimeouts to apply the
+// timeouts to all calls made by the generated code.
+//
+// The timeout covers the entire call, including waiting for the RateLimiter,
+// retries and waiting for the completion of long running operations. A
+// deadline already set on the context passed to the call takes precedence if
+// it is earlier.
+//
+// # Example
+//
+//	ct := NewCallTimeouts(5 * time.Minute)
+//	ct.Register("", "Get", 30*time.Second)            // All Gets.
+//	ct.Register("BackendServices", "", 10*time.Minute) // All BackendServices calls except Get.
+//	ct.Register("BackendServices", "Get", time.Minute)
+//
+// Timeouts are matched in the following order: (service, operation),
+// (service, ""), ("", operation), default.
+type CallTimeouts struct {
+	// map[service]map[operation]timeout
+	timeouts map[string]map[string]time.Duration
+}
+
+// NewCallTimeouts creates CallTimeouts with the given default timeout. A
+// timeout <= 0 means no timeout.
+func NewCallTimeouts(defaultTimeout time.Duration) *CallTimeouts {
+	return &CallTimeouts{
+		timeouts: map[string]map[string]time.Duration{
+			"": {"": defaultTimeout},
+		},
+	}
+}
+
+// Register the timeout for service, operation. Service or operation can be
+// omitted by providing an empty string, in which case the timeout is used for
+// all services or operations without a more specific timeout. It replaces the
+// previous timeout for the same service, operation.
+func (c *CallTimeouts) Register(service, operation string, timeout time.Duration) {
+	if _, ok := c.timeouts[service]; !ok {
+		c.timeouts[service] = map[string]time.Duration{}
+	}
+	c.timeouts[service][operation] = timeout
+}
+
+// Timeout returns the timeout for the call identified by key. A timeout <= 0
+// means no timeout.
+func (c *CallTimeouts) Timeout(key *CallContextKey) time.Duration {
+	if key != nil {
+		for _, k := range [][2]string{
+			{key.Service, key.Operation},
+			{key.Service, ""},
+			{"", key.Operation},
+		} {
+			if t, ok := c.timeouts[k[0]][k[1]]; ok {
+				return t
+			}
+		}
+	}
+	return c.timeouts[""][""]
+}
+
+// callContext returns the context for the call identified by ck with the
+// timeout from the Service CallTimeouts. The context carries ck (see
+// callKeyFromContext) and the user project from opts (see
+// userProjectFromContext).
+func (s *Service) callContext(ctx context.Context, ck *CallContextKey, opts allOptions) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(ctx, callKeyContextKey, ck)
+	if opts.userProject != "" {
+		ctx = context.WithValue(ctx, userProjectContextKey, opts.userProject)
+	}
+	if s.CallTimeouts == nil {
+		return ctx, func() {}
+	}
+	timeout := s.CallTimeouts.Timeout(ck)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
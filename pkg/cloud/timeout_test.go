@@ -0,0 +1,128 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestCallTimeouts(t *testing.T) {
+	t.Parallel()
+
+	ct := NewCallTimeouts(time.Minute)
+	ct.Register("", "Get", 2*time.Minute)
+	ct.Register("BackendServices", "", 3*time.Minute)
+	ct.Register("BackendServices", "Get", 4*time.Minute)
+	ct.Register("Firewalls", "Insert", 0)
+
+	for _, tc := range []struct {
+		key  *CallContextKey
+		want time.Duration
+	}{
+		{key: nil, want: time.Minute},
+		{key: &CallContextKey{Service: "Addresses", Operation: "Insert"}, want: time.Minute},
+		{key: &CallContextKey{Service: "Addresses", Operation: "Get"}, want: 2 * time.Minute},
+		{key: &CallContextKey{Service: "BackendServices", Operation: "Insert"}, want: 3 * time.Minute},
+		{key: &CallContextKey{Service: "BackendServices", Operation: "Get"}, want: 4 * time.Minute},
+		{key: &CallContextKey{Service: "Firewalls", Operation: "Insert"}, want: 0},
+		{key: &CallContextKey{Service: "Firewalls", Operation: "Get"}, want: 2 * time.Minute},
+	} {
+		if got := ct.Timeout(tc.key); got != tc.want {
+			t.Errorf("Timeout(%+v) = %v, want %v", tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestServiceCallContext(t *testing.T) {
+	t.Parallel()
+
+	ck := &CallContextKey{Service: "Addresses", Operation: "Get"}
+
+	// No CallTimeouts.
+	s := &Service{}
+	ctx, cancel := s.callContext(context.Background(), ck, allOptions{})
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("callContext() has a deadline, want none")
+	}
+
+	s.CallTimeouts = NewCallTimeouts(0)
+	s.CallTimeouts.Register("Addresses", "Get", time.Minute)
+
+	start := time.Now()
+	ctx, cancel = s.callContext(context.Background(), ck, allOptions{})
+	defer cancel()
+	if d, ok := ctx.Deadline(); !ok || d.Before(start.Add(time.Minute)) {
+		t.Errorf("callContext() deadline = %v, %t; want >= %v", d, ok, start.Add(time.Minute))
+	}
+
+	// An earlier deadline on the context is kept.
+	pctx, pcancel := context.WithTimeout(context.Background(), time.Second)
+	defer pcancel()
+	want, _ := pctx.Deadline()
+	ctx, cancel = s.callContext(pctx, ck, allOptions{})
+	defer cancel()
+	if d, _ := ctx.Deadline(); !d.Equal(want) {
+		t.Errorf("callContext() deadline = %v, want %v", d, want)
+	}
+
+	// No timeout for the default.
+	ctx, cancel = s.callContext(context.Background(), &CallContextKey{Service: "Firewalls"}, allOptions{})
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("callContext() has a deadline, want none")
+	}
+}
+
+func TestGeneratedCallsTimeout(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+	defer close(done)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the client gives up.
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewService(context.Background(), srv.Client(), &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	s.GA.BasePath = srv.URL + "/"
+	s.CallTimeouts = NewCallTimeouts(0)
+	s.CallTimeouts.Register("GlobalAddresses", "Get", 50*time.Millisecond)
+
+	start := time.Now()
+	_, err = NewGCE(s).GlobalAddresses().Get(context.Background(), meta.GlobalKey("addr"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Get() took %v, want it to time out after 50ms", elapsed)
+	}
+}
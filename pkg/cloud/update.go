@@ -0,0 +1,96 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+	"k8s.io/klog/v2"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// DefaultUpdateAttempts is the number of attempts made by UpdateWithRetry.
+const DefaultUpdateAttempts = 5
+
+// ErrSkipUpdate can be returned by the mutate function of UpdateWithRetry
+// when the resource does not need to be updated.
+var ErrSkipUpdate = errors.New("skip update")
+
+// Updater is a service that can Get and Update resources of type T, e.g.
+// BackendServices.
+type Updater[T any] interface {
+	Get(ctx context.Context, key *meta.Key, options ...Option) (*T, error)
+	Update(context.Context, *meta.Key, *T, ...Option) error
+}
+
+// UpdateWithRetry does a read-modify-write of the resource identified by key:
+// it Gets the resource, calls mutate to change it and Updates the resource.
+// The Update is sent with the fingerprint of the resource that was read, so
+// it fails if the resource was changed concurrently; in this case the whole
+// read-modify-write is retried, up to DefaultUpdateAttempts times:
+//
+//	err := UpdateWithRetry(ctx, gce.BackendServices(), key, func(bs *ga.BackendService) error {
+//		bs.TimeoutSec = 60
+//		return nil
+//	})
+//
+// If mutate returns an error, the resource is not updated and the error is
+// returned, except for ErrSkipUpdate, which makes UpdateWithRetry return nil.
+func UpdateWithRetry[T any](ctx context.Context, svc Updater[T], key *meta.Key, mutate func(*T) error, options ...Option) error {
+	var err error
+	for attempt := 1; attempt <= DefaultUpdateAttempts; attempt++ {
+		var obj *T
+		obj, err = svc.Get(ctx, key, options...)
+		if err != nil {
+			return err
+		}
+		if err = mutate(obj); err != nil {
+			if errors.Is(err, ErrSkipUpdate) {
+				return nil
+			}
+			return err
+		}
+		err = svc.Update(ctx, key, obj, options...)
+		if !isConditionNotMet(err) {
+			return err
+		}
+		klog.V(4).Infof("UpdateWithRetry(%v, %v): attempt %d failed, retrying: %v", ctx, key, attempt, err)
+	}
+	return err
+}
+
+// isConditionNotMet returns true if err is the error returned by the API when
+// the fingerprint of a resource does not match.
+func isConditionNotMet(err error) bool {
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return false
+	}
+	if gerr.Code == http.StatusPreconditionFailed {
+		return true
+	}
+	for _, e := range gerr.Errors {
+		if e.Reason == "conditionNotMet" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestUpdateWithRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := meta.GlobalKey("bs")
+	mutate := func(bs *ga.BackendService) error {
+		bs.TimeoutSec = 31
+		return nil
+	}
+	for _, tc := range []struct {
+		name        string
+		failures    int
+		updateErr   error
+		mutate      func(*ga.BackendService) error
+		wantErr     bool
+		wantUpdates int
+		wantTimeout int64
+	}{
+		{name: "ok", mutate: mutate, wantUpdates: 1, wantTimeout: 31},
+		{name: "retried", failures: 2, updateErr: &googleapi.Error{Code: http.StatusPreconditionFailed}, mutate: mutate, wantUpdates: 3, wantTimeout: 31},
+		{name: "conditionNotMet", failures: 1, updateErr: &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "conditionNotMet"}}}, mutate: mutate, wantUpdates: 2, wantTimeout: 31},
+		{name: "too many failures", failures: 10, updateErr: &googleapi.Error{Code: http.StatusPreconditionFailed}, mutate: mutate, wantErr: true, wantUpdates: DefaultUpdateAttempts, wantTimeout: 30},
+		{name: "other error", failures: 1, updateErr: &googleapi.Error{Code: http.StatusBadRequest}, mutate: mutate, wantErr: true, wantUpdates: 1, wantTimeout: 30},
+		{name: "mutate error", mutate: func(*ga.BackendService) error { return errors.New("fail") }, wantErr: true, wantTimeout: 30},
+		{name: "skip", mutate: func(*ga.BackendService) error { return ErrSkipUpdate }, wantTimeout: 30},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := NewMockGCE(&SingleProjectRouter{"proj"})
+			if err := mock.BackendServices().Insert(ctx, key, &ga.BackendService{TimeoutSec: 30}); err != nil {
+				t.Fatalf("Insert() = %v", err)
+			}
+			updates := 0
+			stored := int64(30)
+			mock.MockBackendServices.UpdateHook = func(_ context.Context, _ *meta.Key, obj *ga.BackendService, _ *MockBackendServices, _ ...Option) error {
+				updates++
+				if updates <= tc.failures {
+					return tc.updateErr
+				}
+				stored = obj.TimeoutSec
+				return nil
+			}
+
+			err := UpdateWithRetry(ctx, mock.BackendServices(), key, tc.mutate)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("UpdateWithRetry() = %v, want error %t", err, tc.wantErr)
+			}
+			if updates != tc.wantUpdates {
+				t.Errorf("updates = %d, want %d", updates, tc.wantUpdates)
+			}
+			if stored != tc.wantTimeout {
+				t.Errorf("TimeoutSec = %d, want %d", stored, tc.wantTimeout)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package cloud
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -25,10 +26,14 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
+const defaultUniverseDomain = "googleapis.com"
+
 var (
+	universeDomain        = defaultUniverseDomain
 	domainPrefix          = "https://www.googleapis.com"
 	computePrefix         = "https://www.googleapis.com/compute"
 	networkServicesPrefix = "https://www.googleapis.com/networkservices"
+	networkSecurityPrefix = "https://www.googleapis.com/networksecurity"
 )
 
 // SetAPIDomain sets the root of the URL for the API. The default domain is
@@ -37,9 +42,29 @@ func SetAPIDomain(domain string) {
 	domainPrefix = domain
 	computePrefix = domain + "/compute"
 	networkServicesPrefix = domain + "/networkservices"
+	networkSecurityPrefix = domain + "/networksecurity"
+}
+
+// SetUniverseDomain sets the universe domain of the APIs, e.g. for Trusted
+// Partner Cloud environments. The default universe domain is
+// "googleapis.com". The self links are generated with the
+// "https://compute.<universe>/compute", "https://networkservices.<universe>"
+// and "https://networksecurity.<universe>" prefixes and NewService targets
+// the universe unless overridden with WithUniverseDomain or WithEndpoints.
+func SetUniverseDomain(universe string) {
+	universeDomain = universe
+	domainPrefix = "https://www." + universe
+	computePrefix = "https://compute." + universe + "/compute"
+	networkServicesPrefix = "https://networkservices." + universe
+	networkSecurityPrefix = "https://networksecurity." + universe
 }
 
 // ResourceID identifies a GCE resource as parsed from compute resource URL.
+//
+// Resources that do not belong to a project have an empty ProjectID. They
+// are either under the parent of their Key (e.g. "organizations/123") or, if
+// the Key has no parent, identified by their location only (e.g.
+// hierarchical firewall policies: locations/global/firewallPolicies/<id>).
 type ResourceID struct {
 	ProjectID string
 	// APIGroup identifies the API Group of the resource.
@@ -66,6 +91,74 @@ func (r *ResourceID) Equal(other *ResourceID) bool {
 	}
 }
 
+// Normalize returns a copy of r in canonical form. The APIGroup defaults to
+// compute, as in SelfLink.
+func (r *ResourceID) Normalize() *ResourceID {
+	if r == nil {
+		return nil
+	}
+	ret := *r
+	if ret.APIGroup == "" {
+		ret.APIGroup = meta.APIGroupCompute
+	}
+	if r.Key != nil {
+		key := *r.Key
+		ret.Key = &key
+	}
+	return &ret
+}
+
+// Equivalent returns true if r and other identify the same resource once
+// normalized (see Normalize), e.g. when one of them was parsed from a partial
+// URL without an API group.
+func (r *ResourceID) Equivalent(other *ResourceID) bool {
+	return r.Normalize().Equal(other.Normalize())
+}
+
+// FullResourceName returns the version independent name of the resource,
+// e.g. "//compute.googleapis.com/projects/p/global/networks/n". This is the
+// canonical form of the resource URLs, see NormalizeResourceURL.
+func (r *ResourceID) FullResourceName() string {
+	n := r.Normalize()
+	return fmt.Sprintf("//%s.%s/%s", n.APIGroup, universeDomain, RelativeResourceName(n.ProjectID, n.Resource, n.Key))
+}
+
+// NormalizeResourceURL returns the canonical form of a resource URL in any
+// of the formats accepted by ParseResourceURL. The version and the domain
+// of the URL are dropped, so that e.g.
+//
+//	https://www.googleapis.com/compute/v1/projects/p/global/networks/n
+//	https://compute.googleapis.com/compute/beta/projects/p/global/networks/n
+//	projects/p/global/networks/n
+//
+// are all normalized to "//compute.googleapis.com/projects/p/global/networks/n"
+// (see ResourceID.FullResourceName).
+func NormalizeResourceURL(url string) (string, error) {
+	id, err := ParseResourceURL(url)
+	if err != nil {
+		return "", err
+	}
+	return id.FullResourceName(), nil
+}
+
+// EqualResourceURLs returns true if the URLs a and b reference the same
+// resource, regardless of the format of the URLs (see NormalizeResourceURL).
+// URLs that cannot be parsed are only equal to the same string.
+func EqualResourceURLs(a, b string) bool {
+	if a == b {
+		return true
+	}
+	idA, err := ParseResourceURL(a)
+	if err != nil {
+		return false
+	}
+	idB, err := ParseResourceURL(b)
+	if err != nil {
+		return false
+	}
+	return idA.Equivalent(idB)
+}
+
 // ResourceMapKey is a flat ResourceID that can be used as a key in maps.
 type ResourceMapKey struct {
 	ProjectID string
@@ -74,6 +167,7 @@ type ResourceMapKey struct {
 	Name      string
 	Zone      string
 	Region    string
+	Parent    string
 }
 
 func (rk ResourceMapKey) ToID() *ResourceID {
@@ -81,7 +175,7 @@ func (rk ResourceMapKey) ToID() *ResourceID {
 		ProjectID: rk.ProjectID,
 		APIGroup:  rk.APIGroup,
 		Resource:  rk.Resource,
-		Key:       &meta.Key{Name: rk.Name, Zone: rk.Zone, Region: rk.Region},
+		Key:       &meta.Key{Name: rk.Name, Zone: rk.Zone, Region: rk.Region, Parent: rk.Parent},
 	}
 }
 
@@ -94,6 +188,7 @@ func (r *ResourceID) MapKey() ResourceMapKey {
 		Name:      r.Key.Name,
 		Zone:      r.Key.Zone,
 		Region:    r.Key.Region,
+		Parent:    r.Key.Parent,
 	}
 }
 
@@ -122,6 +217,9 @@ func (r *ResourceID) SelfLink(ver meta.Version) string {
 
 func (r *ResourceID) String() string {
 	prefix := fmt.Sprintf("%s:%s", r.Resource, r.ProjectID)
+	if r.Key != nil && r.Key.Parent != "" {
+		prefix = fmt.Sprintf("%s:%s", r.Resource, r.Key.Parent)
+	}
 	if r.APIGroup != "" {
 		prefix = fmt.Sprintf("%s/%s", r.APIGroup, prefix)
 	}
@@ -136,10 +234,18 @@ func (r *ResourceID) String() string {
 
 // apiGroupRegex is used to extract the API Group out of a Resource URL.
 // This regex expects API Group to be followed ine one of 2 patterns:
-// <ver>/projects/ path or legacy one <api_group>.googleapis.com/<ver>/projects/.
-// Unfortunately it cannot predict what comes before the API
-// group since that is configurable via SetAPIDomain.
-var apiGroupRegex = regexp.MustCompile(`([a-z]*)(\.googleapis\.com)?\/(alpha|beta|v1|v1alpha1|v1beta1)/projects`)
+// <ver>/projects/ path or legacy one <api_group>.<universe>/<ver>/projects/
+// (e.g. <api_group>.googleapis.com). Unfortunately it cannot predict what
+// comes before the API group since that is configurable via SetAPIDomain.
+var apiGroupRegex = regexp.MustCompile(`([a-z]*)(\.[a-z0-9-]+(?:\.[a-z0-9-]+)+)?\/(alpha|beta|v1|v1alpha1|v1beta1)/(?:projects|organizations|folders|locations)/`)
+
+// fullResourceNameRegex is used to extract the API Group out of a full
+// resource name, e.g. //compute.googleapis.com/projects/...
+var fullResourceNameRegex = regexp.MustCompile(`^//([a-z]+)\.[a-z0-9.-]+/(?:projects|organizations|folders|locations)/`)
+
+// ErrInvalidResourceURL is wrapped by the errors returned by
+// ParseResourceURL for malformed URLs.
+var ErrInvalidResourceURL = errors.New("invalid resource URL")
 
 // ParseResourceURL parses resource URLs of the following formats:
 //
@@ -150,17 +256,43 @@ var apiGroupRegex = regexp.MustCompile(`([a-z]*)(\.googleapis\.com)?\/(alpha|bet
 //	projects/<proj>/global/<res>/<name>
 //	projects/<proj>/regions/<region>/<res>/<name>
 //	projects/<proj>/zones/<zone>/<res>/<name>
+//	projects/<proj>/locations/global/<res>/<name>
+//	projects/<proj>/locations/<region>/<res>/<name>
+//	organizations/<org>/locations/<location>/<res>/<name>
+//	folders/<folder>/locations/<location>/<res>/<name>
+//	locations/<location>/<res>/<name>
 //	[https://www.googleapis.com/<apigroup>/<ver>]/projects/<proj>/global/<res>/<name>
 //	[https://www.googleapis.com/<apigroup>/<ver>]/projects/<proj>/regions/<region>/<res>/<name>
 //	[https://www.googleapis.com/<apigroup>/<ver>]/projects/<proj>/zones/<zone>/<res>/<name>
 //	[https://<apigroup>.googleapis.com/<ver>]/projects/<proj>/global/<res>/<name>
 //	[https://<apigroup>.googleapis.com/<ver>]/projects/<proj>/regions/<region>/<res>/<name>
 //	[https://<apigroup>.googleapis.com/<ver>]/projects/<proj>/zones/<zone>/<res>/<name>
+//	[//<apigroup>.googleapis.com]/projects/<proj>/...
+//
+// The googleapis.com domain may be any universe domain (see
+// SetUniverseDomain), e.g. https://compute.<universe>/compute/<ver>. The
+// version (v1, beta, alpha) of the URL is ignored and the scheme and the
+// domain are case insensitive. Query parameters and a trailing "/" are
+// ignored. The last form is the full resource name used e.g. by Cloud Asset
+// Inventory.
+//
+// The Key of resources under an organization or a folder has the Parent
+// set. For locations, "global" gives a global Key, zones (e.g.
+// "us-central1-a") a zonal Key and regions a regional Key.
+//
+// For a selfLinkWithId (e.g. .../global/backendServices/1234567890), the
+// Name of the Key is the numeric ID of the resource.
+//
+// Errors for malformed URLs wrap ErrInvalidResourceURL.
 //
 // Note that ParseResourceURL can't round trip partial paths that do not
 // include an API Group.
 func ParseResourceURL(url string) (*ResourceID, error) {
+	url = lowerDomain(url)
 	matches := apiGroupRegex.FindStringSubmatch(url)
+	if matches == nil {
+		matches = fullResourceNameRegex.FindStringSubmatch(url)
+	}
 	apiGroup, err := apiGroupFromMatches(matches)
 	if err != nil {
 		return nil, fmt.Errorf("ParseResourceURL(%q) returned error: %v", url, err)
@@ -168,36 +300,79 @@ func ParseResourceURL(url string) (*ResourceID, error) {
 	return parseURL(url, apiGroup)
 }
 
+// lowerDomain returns url with the scheme and the domain in lower case.
+func lowerDomain(url string) string {
+	start := strings.Index(url, "//")
+	if start < 0 || (start > 0 && !strings.HasSuffix(url[:start], ":")) {
+		return url
+	}
+	end := len(url)
+	if i := strings.Index(url[start+2:], "/"); i >= 0 {
+		end = start + 2 + i
+	}
+	return strings.ToLower(url[:end]) + url[end:]
+}
+
 func apiGroupFromMatches(matches []string) (meta.APIGroup, error) {
-	if len(matches) < 2 {
+	if len(matches) < 2 || matches[1] == "" {
 		return meta.APIGroup(""), nil
 	}
-
-	switch matches[1] {
-	case "compute":
-		return meta.APIGroupCompute, nil
-	case "networkservices":
-		return meta.APIGroupNetworkServices, nil
+	if group, ok := meta.LookupAPIGroup(meta.APIGroup(matches[1])); ok {
+		return group.Group, nil
 	}
 	return meta.APIGroup(""), fmt.Errorf("matches does not contain a supported API Group: %v", matches)
 }
 
 func parseURL(url string, apiGroup meta.APIGroup) (*ResourceID, error) {
-	errNotValid := fmt.Errorf("%q is not a valid resource URL", url)
-	// Trim prefix off URL leaving "projects/..."
-	projectsIndex := strings.Index(url, "/projects/")
-	if projectsIndex >= 0 {
-		url = url[projectsIndex+1:]
+	errNotValid := func(reason string) error {
+		return fmt.Errorf("%w %q: %s", ErrInvalidResourceURL, url, reason)
+	}
+
+	path := url
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.TrimSuffix(path, "/")
+	// Trim prefix off URL leaving "projects/..." (or the parent or location
+	// of resources that are not in a project).
+	prefixes := []string{"projects/", "organizations/", "folders/", "locations/"}
+	var trimmed bool
+	for _, p := range prefixes {
+		trimmed = trimmed || strings.HasPrefix(path, p)
+	}
+	if !trimmed {
+		prefixIndex := -1
+		for _, p := range prefixes {
+			if i := strings.Index(path, "/"+p); i >= 0 && (prefixIndex < 0 || i < prefixIndex) {
+				prefixIndex = i
+			}
+		}
+		if prefixIndex >= 0 {
+			path = path[prefixIndex+1:]
+		}
 	}
 
-	parts := strings.Split(url, "/")
+	parts := strings.Split(path, "/")
 	if len(parts) < 2 || len(parts) > 6 {
-		return nil, errNotValid
+		return nil, errNotValid(fmt.Sprintf("got %d path segments, want 2 to 6", len(parts)))
+	}
+	for _, p := range parts {
+		if p == "" {
+			return nil, errNotValid("empty path segment")
+		}
 	}
 
 	ret := &ResourceID{APIGroup: apiGroup}
 	scopedName := parts
-	if parts[0] == "projects" {
+	var parent string
+	switch parts[0] {
+	case "organizations", "folders":
+		parent = parts[0] + "/" + parts[1]
+		scopedName = parts[2:]
+		if len(scopedName) == 0 || scopedName[0] != "locations" {
+			return nil, errNotValid("want " + parts[0] + "/<id>/locations/<location>/<res>/<name>")
+		}
+	case "projects":
 		ret.Resource = "projects"
 		ret.ProjectID = parts[1]
 		scopedName = parts[2:]
@@ -210,7 +385,7 @@ func parseURL(url string, apiGroup meta.APIGroup) (*ResourceID, error) {
 	switch scopedName[0] {
 	case "global":
 		if len(scopedName) != 3 {
-			return nil, errNotValid
+			return nil, errNotValid("want global/<res>/<name>")
 		}
 		ret.Resource = scopedName[1]
 		ret.Key = meta.GlobalKey(scopedName[2])
@@ -226,7 +401,7 @@ func parseURL(url string, apiGroup meta.APIGroup) (*ResourceID, error) {
 			ret.Key = meta.RegionalKey(scopedName[3], scopedName[1])
 			return ret, nil
 		default:
-			return nil, errNotValid
+			return nil, errNotValid("want regions/<region>[/<res>/<name>]")
 		}
 	case "zones":
 		switch len(scopedName) {
@@ -239,10 +414,53 @@ func parseURL(url string, apiGroup meta.APIGroup) (*ResourceID, error) {
 			ret.Key = meta.ZonalKey(scopedName[3], scopedName[1])
 			return ret, nil
 		default:
-			return nil, errNotValid
+			return nil, errNotValid("want zones/<zone>[/<res>/<name>]")
 		}
+	case "locations":
+		if len(scopedName) != 4 {
+			return nil, errNotValid("want locations/<location>/<res>/<name>")
+		}
+		ret.Resource = scopedName[2]
+		location, name := scopedName[1], scopedName[3]
+		switch {
+		case location == "global":
+			ret.Key = meta.GlobalKey(name)
+		case isZone(location):
+			ret.Key = meta.ZonalKey(name, location)
+		default:
+			ret.Key = meta.RegionalKey(name, location)
+		}
+		ret.Key.Parent = parent
+		return ret, nil
 	}
-	return nil, errNotValid
+	return nil, errNotValid(fmt.Sprintf("unknown scope %q", scopedName[0]))
+}
+
+// locationResourceName returns the name of the resource for key in a
+// location based API (e.g. networkservices) from format, which is the name
+// of the resource in projectID. If key has a Parent, it replaces the
+// project.
+func locationResourceName(format, projectID string, key *meta.Key) string {
+	name := fmt.Sprintf(format, projectID, key.Name)
+	if key.Parent != "" {
+		name = key.Parent + strings.TrimPrefix(name, "projects/"+projectID)
+	}
+	return name
+}
+
+// locationParent returns the parent used to create the resource for key in
+// a location based API.
+func locationParent(projectID string, key *meta.Key) string {
+	if key.Parent != "" {
+		return key.Parent + "/locations/global"
+	}
+	return fmt.Sprintf("projects/%s/locations/global", projectID)
+}
+
+// isZone returns true if location is the name of a zone (e.g.
+// "us-central1-a") rather than a region (e.g. "us-central1").
+func isZone(location string) bool {
+	return strings.Count(location, "-") >= 2
 }
 
 func copyViaJSON(dest, src interface{}) error {
@@ -264,6 +482,9 @@ func ResourcePath(resource string, key *meta.Key) string {
 		return "invalid-resource"
 	}
 
+	if key.Parent != "" {
+		return locationPath(resource, key)
+	}
 	switch key.Type() {
 	case meta.Zonal:
 		return fmt.Sprintf("zones/%s/%s/%s", key.Zone, resource, key.Name)
@@ -279,14 +500,32 @@ func ResourcePath(resource string, key *meta.Key) string {
 // Example: projects/my-project/regions/us-central1/subnetworks/my-subnet
 // Deprecated: Use SelfLinkWithGroup instead
 func RelativeResourceName(project, resource string, key *meta.Key) string {
-	switch resource {
-	case "projects":
+	switch {
+	case resource == "projects":
 		return fmt.Sprintf("projects/%s", project)
+	case key != nil && key.Parent != "":
+		return fmt.Sprintf("%s/%s", key.Parent, locationPath(resource, key))
+	case project == "" && key != nil:
+		return locationPath(resource, key)
 	default:
 		return fmt.Sprintf("projects/%s/%s", project, ResourcePath(resource, key))
 	}
 }
 
+// locationPath returns the path of a resource that is not in a project,
+// starting from the location.
+// Example: locations/global/firewallPolicies/123
+func locationPath(resource string, key *meta.Key) string {
+	location := "global"
+	switch key.Type() {
+	case meta.Zonal:
+		location = key.Zone
+	case meta.Regional:
+		location = key.Region
+	}
+	return fmt.Sprintf("locations/%s/%s/%s", location, resource, key.Name)
+}
+
 // SelfLink returns a URL representing the resource and assumes Compute API Group.
 // Deprecated: Use SelfLinkWithGroup instead
 func SelfLink(ver meta.Version, project, resource string, key *meta.Key) string {
@@ -302,6 +541,8 @@ func SelfLinkWithGroup(apiGroup meta.APIGroup, ver meta.Version, project, resour
 		prefix = computePrefix
 	case meta.APIGroupNetworkServices:
 		prefix = networkServicesPrefix
+	case meta.APIGroupNetworkSecurity:
+		prefix = networkSecurityPrefix
 	default:
 		prefix = domainPrefix + "/invalid-apigroup"
 	}
@@ -310,7 +551,7 @@ func SelfLinkWithGroup(apiGroup meta.APIGroup, ver meta.Version, project, resour
 	case meta.VersionAlpha:
 		prefix = prefix + "/alpha"
 	case meta.VersionBeta:
-		if apiGroup == meta.APIGroupNetworkServices {
+		if apiGroup == meta.APIGroupNetworkServices || apiGroup == meta.APIGroupNetworkSecurity {
 			prefix = prefix + "/v1beta1"
 		} else {
 			prefix = prefix + "/beta"
@@ -17,7 +17,9 @@ limitations under the License.
 package cloud
 
 import (
+	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -185,6 +187,14 @@ func TestParseResourceURL(t *testing.T) {
 			"http://localhost:3990/compute/v1/projects/some-gce-project/zones/dev-central1-std/instances/instance-1",
 			&ResourceID{"some-gce-project", meta.APIGroupCompute, "instances", meta.ZonalKey("instance-1", "dev-central1-std")},
 		},
+		{
+			"https://compute.apis-tpc.goog/compute/v1/projects/some-gce-project/zones/tpc-central1-a/instances/instance-1",
+			&ResourceID{"some-gce-project", meta.APIGroupCompute, "instances", meta.ZonalKey("instance-1", "tpc-central1-a")},
+		},
+		{
+			"https://networkservices.apis-tpc.goog/v1/projects/some-gce-project/global/tcpRoutes/route-1",
+			&ResourceID{"some-gce-project", meta.APIGroupNetworkServices, "tcpRoutes", meta.GlobalKey("route-1")},
+		},
 		{
 			"projects/some-gce-project",
 			&ResourceID{"some-gce-project", "", "projects", nil},
@@ -225,6 +235,51 @@ func TestParseResourceURL(t *testing.T) {
 			"https://compute.googleapis.com/compute/v1/projects/some-gce-project/regions/us-central1/backendServices/bs1",
 			&ResourceID{"some-gce-project", meta.APIGroupCompute, "backendServices", meta.RegionalKey("bs1", "us-central1")},
 		},
+		{
+			// selfLinkWithId.
+			"https://www.googleapis.com/compute/beta/projects/some-gce-project/global/backendServices/1234567890123",
+			&ResourceID{"some-gce-project", meta.APIGroupCompute, "backendServices", meta.GlobalKey("1234567890123")},
+		},
+		{
+			"https://www.googleapis.com/compute/v1/projects/some-gce-project/global/networks/my-network/",
+			&ResourceID{"some-gce-project", meta.APIGroupCompute, "networks", meta.GlobalKey("my-network")},
+		},
+		{
+			"https://www.googleapis.com/compute/v1/projects/some-gce-project/global/networks/my-network?alt=json",
+			&ResourceID{"some-gce-project", meta.APIGroupCompute, "networks", meta.GlobalKey("my-network")},
+		},
+		{
+			"//compute.googleapis.com/projects/some-gce-project/zones/us-central1-c/instances/instance-1",
+			&ResourceID{"some-gce-project", meta.APIGroupCompute, "instances", meta.ZonalKey("instance-1", "us-central1-c")},
+		},
+		{
+			"//networkservices.googleapis.com/projects/some-gce-project/locations/global/tcpRoutes/route-1",
+			&ResourceID{"some-gce-project", meta.APIGroupNetworkServices, "tcpRoutes", meta.GlobalKey("route-1")},
+		},
+		{
+			"https://networkservices.googleapis.com/v1beta1/projects/some-gce-project/locations/us-central1/meshes/mesh-1",
+			&ResourceID{"some-gce-project", meta.APIGroupNetworkServices, "meshes", meta.RegionalKey("mesh-1", "us-central1")},
+		},
+		{
+			"/compute/v1/projects/some-gce-project/regions/us-central1/addresses/my-address",
+			&ResourceID{"some-gce-project", meta.APIGroupCompute, "addresses", meta.RegionalKey("my-address", "us-central1")},
+		},
+		{
+			"projects/some-gce-project/locations/us-central1-a/firewallEndpoints/fe",
+			&ResourceID{"some-gce-project", "", "firewallEndpoints", meta.ZonalKey("fe", "us-central1-a")},
+		},
+		{
+			"https://www.googleapis.com/compute/v1/locations/global/firewallPolicies/1234567890",
+			&ResourceID{"", meta.APIGroupCompute, "firewallPolicies", meta.GlobalKey("1234567890")},
+		},
+		{
+			"https://networkservices.googleapis.com/v1/organizations/123/locations/global/meshes/mesh-1",
+			&ResourceID{"", meta.APIGroupNetworkServices, "meshes", meta.GlobalKey("mesh-1").InParent("organizations/123")},
+		},
+		{
+			"folders/456/locations/us-central1/meshes/mesh-1",
+			&ResourceID{"", "", "meshes", meta.RegionalKey("mesh-1", "us-central1").InParent("folders/456")},
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 			r, err := ParseResourceURL(tc.in)
@@ -255,12 +310,24 @@ func TestParseResourceURL(t *testing.T) {
 		"projects/some-gce-project/regions/us-central1/res",
 		"projects/some-gce-project/zones/us-central1-c/res",
 		"projects/some-gce-project/zones/us-central1-c/res/name/extra",
+		"projects//global/networks/my-network",
+		"projects/some-gce-project/global/networks/",
+		"projects/some-gce-project/locations/global/tcpRoutes",
+		"projects/some-gce-project/unknown/foo/bar",
+		"organizations/123",
+		"organizations/123/global/meshes/mesh-1",
+		"folders/456/locations/global/meshes",
 	} {
 		r, err := ParseResourceURL(tc)
-		if err == nil {
-			t.Errorf("ParseResourceURL(%q) = %+v, %v, want _, error", tc, r, err)
+		if !errors.Is(err, ErrInvalidResourceURL) {
+			t.Errorf("ParseResourceURL(%q) = %+v, %v, want _, ErrInvalidResourceURL", tc, r, err)
 		}
 	}
+
+	// Unsupported API Group.
+	if r, err := ParseResourceURL("https://www.googleapis.com/storage/v1/projects/some-gce-project"); err == nil {
+		t.Errorf("ParseResourceURL(storage) = %+v, nil, want error", r)
+	}
 }
 
 type A struct {
@@ -332,6 +399,21 @@ func TestResourceIdSelfLink(t *testing.T) {
 			meta.VersionAlpha,
 			"https://www.googleapis.com/compute/alpha/projects/proj1/global/res1/key1",
 		},
+		{
+			&ResourceID{"", meta.APIGroupCompute, "firewallPolicies", meta.GlobalKey("123")},
+			meta.VersionGA,
+			"https://www.googleapis.com/compute/v1/locations/global/firewallPolicies/123",
+		},
+		{
+			&ResourceID{"", meta.APIGroupNetworkServices, "res1", meta.RegionalKey("key1", "us-central1").InParent("organizations/123")},
+			meta.VersionGA,
+			"https://www.googleapis.com/networkservices/v1/organizations/123/locations/us-central1/res1/key1",
+		},
+		{
+			&ResourceID{"", meta.APIGroupCompute, "res1", meta.ZonalKey("key1", "us-central1-a").InParent("folders/456")},
+			meta.VersionGA,
+			"https://www.googleapis.com/compute/v1/folders/456/locations/us-central1-a/res1/key1",
+		},
 	} {
 		if link := tc.resourceID.SelfLink(tc.ver); link != tc.want {
 			t.Errorf("ResourceID{%+v}.SelfLink(%v) = %v, want %q", tc.resourceID, tc.ver, link, tc.want)
@@ -339,6 +421,158 @@ func TestResourceIdSelfLink(t *testing.T) {
 	}
 }
 
+func TestResourceIDParentRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []*ResourceID{
+		{"", meta.APIGroupCompute, "firewallPolicies", meta.GlobalKey("123")},
+		{"", meta.APIGroupNetworkServices, "meshes", meta.GlobalKey("m").InParent("organizations/123")},
+		{"", meta.APIGroupCompute, "res", meta.ZonalKey("r", "us-central1-a").InParent("folders/456")},
+	} {
+		link := id.SelfLink(meta.VersionGA)
+		got, err := ParseResourceURL(link)
+		if err != nil {
+			t.Errorf("ParseResourceURL(%q) = _, %v, want nil", link, err)
+			continue
+		}
+		if !got.Equal(id) {
+			t.Errorf("ParseResourceURL(%q) = %v, want %v", link, got, id)
+		}
+		if got.MapKey().ToID().Key.Parent != id.Key.Parent {
+			t.Errorf("MapKey().ToID() of %v lost the parent", id)
+		}
+	}
+	if got, want := (&ResourceID{"", "", "meshes", meta.GlobalKey("m").InParent("organizations/123")}).String(), "meshes:organizations/123/m"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeResourceURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		urls []string
+		want string
+	}{
+		{
+			urls: []string{
+				"https://www.googleapis.com/compute/v1/projects/proj1/global/networks/net1",
+				"https://www.googleapis.com/compute/beta/projects/proj1/global/networks/net1",
+				"https://compute.googleapis.com/compute/alpha/projects/proj1/global/networks/net1",
+				"HTTPS://Compute.GoogleAPIs.com/compute/v1/projects/proj1/global/networks/net1",
+				"https://www.googleapis.com/compute/v1/projects/proj1/global/networks/net1/?alt=json",
+				"//compute.googleapis.com/projects/proj1/global/networks/net1",
+				"projects/proj1/global/networks/net1",
+			},
+			want: "//compute.googleapis.com/projects/proj1/global/networks/net1",
+		},
+		{
+			urls: []string{
+				"https://www.googleapis.com/compute/v1/projects/proj1/zones/us-central1-b/instances/vm1",
+				"https://compute.googleapis.com/compute/v1/projects/proj1/zones/us-central1-b/instances/vm1",
+			},
+			want: "//compute.googleapis.com/projects/proj1/zones/us-central1-b/instances/vm1",
+		},
+		{
+			urls: []string{
+				"https://networkservices.googleapis.com/v1/projects/proj1/global/tcpRoutes/route1",
+				"https://www.googleapis.com/networkservices/v1beta1/projects/proj1/global/tcpRoutes/route1",
+			},
+			want: "//networkservices.googleapis.com/projects/proj1/global/tcpRoutes/route1",
+		},
+		{
+			urls: []string{
+				"https://www.googleapis.com/compute/v1/projects/proj1",
+				"projects/proj1",
+			},
+			want: "//compute.googleapis.com/projects/proj1",
+		},
+	} {
+		for _, url := range tc.urls {
+			got, err := NormalizeResourceURL(url)
+			if err != nil || got != tc.want {
+				t.Errorf("NormalizeResourceURL(%q) = %q, %v; want %q, nil", url, got, err, tc.want)
+			}
+		}
+	}
+
+	if _, err := NormalizeResourceURL("projects/proj1/global/networks"); err == nil {
+		t.Errorf("NormalizeResourceURL() = _, nil; want error")
+	}
+}
+
+func TestEqualResourceURLs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{
+			a:    "https://www.googleapis.com/compute/v1/projects/proj1/regions/us-central1/addresses/a",
+			b:    "https://compute.googleapis.com/compute/beta/projects/proj1/regions/us-central1/addresses/a",
+			want: true,
+		},
+		{
+			a:    "https://www.googleapis.com/compute/v1/projects/proj1/regions/us-central1/addresses/a",
+			b:    "projects/proj1/regions/us-central1/addresses/a",
+			want: true,
+		},
+		{
+			a:    "https://www.googleapis.com/compute/v1/projects/proj1/regions/us-central1/addresses/a",
+			b:    "https://www.googleapis.com/compute/v1/projects/proj2/regions/us-central1/addresses/a",
+			want: false,
+		},
+		{
+			a:    "https://www.googleapis.com/compute/v1/projects/proj1/regions/us-central1/addresses/a",
+			b:    "https://www.googleapis.com/compute/v1/projects/proj1/regions/us-east1/addresses/a",
+			want: false,
+		},
+		{
+			a:    "https://www.googleapis.com/compute/v1/projects/proj1/global/tcpRoutes/r",
+			b:    "https://www.googleapis.com/networkservices/v1/projects/proj1/global/tcpRoutes/r",
+			want: false,
+		},
+		{
+			a:    "not-a-url",
+			b:    "not-a-url",
+			want: true,
+		},
+		{
+			a:    "not-a-url",
+			b:    "projects/proj1/global/networks/net1",
+			want: false,
+		},
+	} {
+		if got := EqualResourceURLs(tc.a, tc.b); got != tc.want {
+			t.Errorf("EqualResourceURLs(%q, %q) = %t, want %t", tc.a, tc.b, got, tc.want)
+		}
+		if got := EqualResourceURLs(tc.b, tc.a); got != tc.want {
+			t.Errorf("EqualResourceURLs(%q, %q) = %t, want %t", tc.b, tc.a, got, tc.want)
+		}
+	}
+}
+
+func TestResourceIDEquivalent(t *testing.T) {
+	t.Parallel()
+
+	a := &ResourceID{"proj1", "", "networks", meta.GlobalKey("net1")}
+	b := &ResourceID{"proj1", meta.APIGroupCompute, "networks", meta.GlobalKey("net1")}
+	if a.Equal(b) {
+		t.Errorf("%v.Equal(%v) = true, want false", a, b)
+	}
+	if !a.Equivalent(b) {
+		t.Errorf("%v.Equivalent(%v) = false, want true", a, b)
+	}
+	if n := a.Normalize(); n.APIGroup != meta.APIGroupCompute || n.Key == a.Key || a.APIGroup != "" {
+		t.Errorf("%v.Normalize() = %v, want a copy with the compute API group", a, n)
+	}
+	var nilID *ResourceID
+	if !nilID.Equivalent(nil) || nilID.Equivalent(a) {
+		t.Errorf("Equivalent() with nil ResourceIDs is wrong")
+	}
+}
+
 func TestSelfLink(t *testing.T) {
 	t.Parallel()
 
@@ -417,6 +651,14 @@ func TestSelfLinkWithGroup(t *testing.T) {
 			meta.RegionalKey("key1", "us-central1"),
 			"https://www.googleapis.com/compute/alpha/projects/proj1/regions/us-central1/addresses/key1",
 		},
+		{
+			meta.APIGroupNetworkSecurity,
+			meta.VersionBeta,
+			"proj1",
+			"serverTlsPolicies",
+			meta.GlobalKey("key1"),
+			"https://www.googleapis.com/networksecurity/v1beta1/projects/proj1/global/serverTlsPolicies/key1",
+		},
 		{
 			meta.APIGroupCompute,
 			meta.VersionBeta,
@@ -551,6 +793,46 @@ func TestSelfLinkWithSetAPIDomain(t *testing.T) {
 	}
 }
 
+// This test is not run in parallel since it modifies global vars.
+func TestSetUniverseDomain(t *testing.T) {
+	defer func() {
+		SetUniverseDomain(defaultUniverseDomain)
+		SetAPIDomain("https://www.googleapis.com")
+	}()
+	SetUniverseDomain("apis-tpc.goog")
+
+	for _, tc := range []struct {
+		apiGroup meta.APIGroup
+		ver      meta.Version
+		want     string
+	}{
+		{meta.APIGroupCompute, meta.VersionGA, "https://compute.apis-tpc.goog/compute/v1/projects/proj1/global/tcpRoutes/key1"},
+		{meta.APIGroupCompute, meta.VersionBeta, "https://compute.apis-tpc.goog/compute/beta/projects/proj1/global/tcpRoutes/key1"},
+		{meta.APIGroupNetworkServices, meta.VersionGA, "https://networkservices.apis-tpc.goog/v1/projects/proj1/global/tcpRoutes/key1"},
+	} {
+		link := SelfLinkWithGroup(tc.apiGroup, tc.ver, "proj1", "tcpRoutes", meta.GlobalKey("key1"))
+		if link != tc.want {
+			t.Errorf("SelfLinkWithGroup(%v, %v, ...) = %q, want %q", tc.apiGroup, tc.ver, link, tc.want)
+		}
+		id, err := ParseResourceURL(link)
+		if err != nil {
+			t.Fatalf("ParseResourceURL(%q) = %v", link, err)
+		}
+		want := &ResourceID{"proj1", tc.apiGroup, "tcpRoutes", meta.GlobalKey("key1")}
+		if !id.Equal(want) {
+			t.Errorf("ParseResourceURL(%q) = %+v, want %+v", link, id, want)
+		}
+	}
+
+	s, err := NewService(context.Background(), http.DefaultClient, &SingleProjectRouter{ID: "proj"}, &NopRateLimiter{})
+	if err != nil {
+		t.Fatalf("NewService() = %v", err)
+	}
+	if want := "https://compute.apis-tpc.goog/compute/v1/"; s.GA.BasePath != want {
+		t.Errorf("GA.BasePath = %q, want %q", s.GA.BasePath, want)
+	}
+}
+
 func TestAggregatedListKey(t *testing.T) {
 	for _, tc := range []struct {
 		key          *meta.Key
@@ -0,0 +1,215 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultWatchInterval is the interval between Lists of a Watcher if
+// Watcher.Interval is not set or is not positive.
+const DefaultWatchInterval = time.Minute
+
+// WatchEventType is the type of a WatchEvent.
+type WatchEventType string
+
+const (
+	// WatchAdded is sent when a resource is listed for the first time.
+	WatchAdded WatchEventType = "Added"
+	// WatchUpdated is sent when a resource has changed since the previous
+	// List.
+	WatchUpdated WatchEventType = "Updated"
+	// WatchDeleted is sent when a resource is no longer listed.
+	WatchDeleted WatchEventType = "Deleted"
+)
+
+// WatchEvent is a change of a resource seen by a Watcher.
+type WatchEvent[T any] struct {
+	Type WatchEventType
+	// Key of the resource (see Watcher.Key).
+	Key string
+	// Object is the resource as it was listed. For WatchDeleted, this is
+	// the last version of the resource that was seen.
+	Object *T
+	// Old is the previous version of the resource for WatchUpdated and nil
+	// otherwise.
+	Old *T
+}
+
+// Watcher periodically lists resources and sends a WatchEvent to Handler for
+// each resource that was added, updated or deleted since the previous List.
+// This gives an informer-like view of GCE resources:
+//
+//	w := NewWatcher(func(ctx context.Context) ([]*ga.Address, error) {
+//		return gce.Addresses().List(ctx, "us-central1", filter.Regexp("name", "k8s-.*"))
+//	}, func(a *ga.Address) string { return a.SelfLink })
+//	w.Interval = 30 * time.Second
+//	w.Handler = func(ctx context.Context, ev WatchEvent[ga.Address]) {
+//		klog.Infof("%s %s", ev.Type, ev.Key)
+//	}
+//	go w.Run(ctx)
+//
+// The first List sends WatchAdded for all of the resources. If a List fails,
+// no events are sent and the resources are compared with the last successful
+// List the next time. Changes that are reverted between two Lists are not
+// seen.
+type Watcher[T any] struct {
+	// List returns the resources to watch.
+	List func(ctx context.Context) ([]*T, error)
+	// Key returns a unique key for a resource, e.g. its SelfLink.
+	Key func(*T) string
+	// Equal reports whether a resource is unchanged. If nil,
+	// reflect.DeepEqual is used.
+	Equal func(a, b *T) bool
+	// Interval between Lists. If zero or negative, DefaultWatchInterval is
+	// used.
+	Interval time.Duration
+	// Handler is called for each event, in the order of the keys. Handler
+	// is called from the goroutine running Run or Poll.
+	Handler func(ctx context.Context, ev WatchEvent[T])
+	// OnError is called when List returns an error. If nil, the error is
+	// logged.
+	OnError func(ctx context.Context, err error)
+
+	lock    sync.Mutex
+	synced  bool
+	objects map[string]*T
+}
+
+// NewWatcher returns a Watcher of the resources returned by list, identified
+// by key.
+func NewWatcher[T any](list func(ctx context.Context) ([]*T, error), key func(*T) string) *Watcher[T] {
+	return &Watcher[T]{
+		List: list,
+		Key:  key,
+	}
+}
+
+// Run calls Poll every Interval until ctx is done. The first Poll is done
+// immediately. Run returns the error of ctx.
+func (w *Watcher[T]) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(ctx); err != nil {
+			if w.OnError != nil {
+				w.OnError(ctx, err)
+			} else {
+				klog.Errorf("Watcher: %v", err)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Poll lists the resources once, sends the events to Handler and returns
+// them.
+func (w *Watcher[T]) Poll(ctx context.Context) ([]WatchEvent[T], error) {
+	objs, err := w.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Watcher.Poll: %w", err)
+	}
+
+	w.lock.Lock()
+	events := w.diff(objs)
+	w.lock.Unlock()
+
+	if w.Handler != nil {
+		for _, ev := range events {
+			w.Handler(ctx, ev)
+		}
+	}
+	return events, nil
+}
+
+// diff updates the snapshot with objs and returns the events. w.lock must be
+// held.
+func (w *Watcher[T]) diff(objs []*T) []WatchEvent[T] {
+	equal := w.Equal
+	if equal == nil {
+		equal = func(a, b *T) bool { return reflect.DeepEqual(a, b) }
+	}
+
+	var events []WatchEvent[T]
+	current := make(map[string]*T, len(objs))
+	for _, obj := range objs {
+		key := w.Key(obj)
+		current[key] = obj
+		old, ok := w.objects[key]
+		switch {
+		case !ok:
+			events = append(events, WatchEvent[T]{Type: WatchAdded, Key: key, Object: obj})
+		case !equal(old, obj):
+			events = append(events, WatchEvent[T]{Type: WatchUpdated, Key: key, Object: obj, Old: old})
+		}
+	}
+	for key, old := range w.objects {
+		if _, ok := current[key]; !ok {
+			events = append(events, WatchEvent[T]{Type: WatchDeleted, Key: key, Object: old})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Key < events[j].Key })
+
+	w.objects = current
+	w.synced = true
+	return events
+}
+
+// HasSynced returns true once the resources have been listed successfully.
+func (w *Watcher[T]) HasSynced() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.synced
+}
+
+// Get returns the resource with the given key as of the last successful
+// List.
+func (w *Watcher[T]) Get(key string) (*T, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	obj, ok := w.objects[key]
+	return obj, ok
+}
+
+// Keys returns the sorted keys of the resources as of the last successful
+// List.
+func (w *Watcher[T]) Keys() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	var keys []string
+	for key := range w.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
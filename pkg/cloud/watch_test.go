@@ -0,0 +1,182 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func eventStrings(events []WatchEvent[ga.Address]) []string {
+	var ret []string
+	for _, ev := range events {
+		ret = append(ret, string(ev.Type)+" "+ev.Object.Name)
+	}
+	return ret
+}
+
+func TestWatcherPoll(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"proj"})
+	listErr := errors.New("list failed")
+	var failList bool
+	w := NewWatcher(func(ctx context.Context) ([]*ga.Address, error) {
+		if failList {
+			return nil, listErr
+		}
+		return mock.GlobalAddresses().List(ctx, filter.Regexp("name", "k8s-.*"))
+	}, func(a *ga.Address) string { return a.Name })
+	var handled []WatchEvent[ga.Address]
+	w.Handler = func(_ context.Context, ev WatchEvent[ga.Address]) {
+		handled = append(handled, ev)
+	}
+
+	for _, name := range []string{"k8s-a", "k8s-b", "other"} {
+		mock.GlobalAddresses().Insert(ctx, meta.GlobalKey(name), &ga.Address{Description: "v1"})
+	}
+	if w.HasSynced() {
+		t.Errorf("HasSynced() = true before the first Poll, want false")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		change  func()
+		wantErr bool
+		want    []string
+	}{
+		{
+			name: "initial list",
+			want: []string{"Added k8s-a", "Added k8s-b"},
+		},
+		{
+			name: "no change",
+		},
+		{
+			name: "add, update and delete",
+			change: func() {
+				mock.GlobalAddresses().Insert(ctx, meta.GlobalKey("k8s-c"), &ga.Address{})
+				mock.GlobalAddresses().Delete(ctx, meta.GlobalKey("k8s-a"))
+				mock.GlobalAddresses().Delete(ctx, meta.GlobalKey("k8s-b"))
+				mock.GlobalAddresses().Insert(ctx, meta.GlobalKey("k8s-b"), &ga.Address{Description: "v2"})
+			},
+			want: []string{"Deleted k8s-a", "Updated k8s-b", "Added k8s-c"},
+		},
+		{
+			name:    "list error",
+			change:  func() { failList = true },
+			wantErr: true,
+		},
+		{
+			name: "changes during the list error",
+			change: func() {
+				failList = false
+				mock.GlobalAddresses().Delete(ctx, meta.GlobalKey("k8s-c"))
+			},
+			want: []string{"Deleted k8s-c"},
+		},
+	} {
+		if tc.change != nil {
+			tc.change()
+		}
+		handled = nil
+		events, err := w.Poll(ctx)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: Poll() = %v, want error %t", tc.name, err, tc.wantErr)
+		}
+		if diff := cmp.Diff(eventStrings(events), tc.want); diff != "" {
+			t.Errorf("%s: Poll() events: diff -got,+want: %s", tc.name, diff)
+		}
+		if diff := cmp.Diff(eventStrings(handled), tc.want); diff != "" {
+			t.Errorf("%s: Handler events: diff -got,+want: %s", tc.name, diff)
+		}
+	}
+
+	if !w.HasSynced() {
+		t.Errorf("HasSynced() = false, want true")
+	}
+	if diff := cmp.Diff(w.Keys(), []string{"k8s-b"}); diff != "" {
+		t.Errorf("Keys(): diff -got,+want: %s", diff)
+	}
+	if a, ok := w.Get("k8s-b"); !ok || a.Description != "v2" {
+		t.Errorf("Get(k8s-b) = %+v, %t; want Description v2", a, ok)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		lock  sync.Mutex
+		lists int
+	)
+	w := NewWatcher(func(context.Context) ([]*ga.Address, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		lists++
+		if lists == 3 {
+			cancel()
+		}
+		return nil, errors.New("fail")
+	}, func(a *ga.Address) string { return a.Name })
+	w.Interval = time.Millisecond
+	var errs int
+	w.OnError = func(context.Context, error) { errs++ }
+
+	if err := w.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want %v", err, context.Canceled)
+	}
+	if lists < 3 || errs != lists {
+		t.Errorf("lists, errs = %d, %d; want >= 3 and the same number of errors", lists, errs)
+	}
+}
+
+func TestWatcherRunNegativeInterval(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lists int
+	w := NewWatcher(func(context.Context) ([]*ga.Address, error) {
+		lists++
+		// Stop before waiting for the next tick.
+		cancel()
+		return nil, nil
+	}, func(a *ga.Address) string { return a.Name })
+	w.Interval = -time.Second
+
+	if err := w.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want %v", err, context.Canceled)
+	}
+	if lists != 1 {
+		t.Errorf("lists = %d, want 1", lists)
+	}
+}